@@ -7,7 +7,11 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
 )
 
 // Client for the Avalanche Platform Info API Endpoint
@@ -50,6 +54,15 @@ func (c *Client) LockProfile() (bool, error) {
 	return res.Success, err
 }
 
+// GetRuntimeSamples ...
+func (c *Client) GetRuntimeSamples(startTime time.Time) ([]profiler.Sample, error) {
+	res := &GetRuntimeSamplesReply{}
+	err := c.requester.SendRequest("getRuntimeSamples", &GetRuntimeSamplesArgs{
+		StartTime: startTime,
+	}, res)
+	return res.Samples, err
+}
+
 // Alias ...
 func (c *Client) Alias(endpoint, alias string) (bool, error) {
 	res := &api.SuccessResponse{}
@@ -70,6 +83,15 @@ func (c *Client) AliasChain(chain, alias string) (bool, error) {
 	return res.Success, err
 }
 
+// RemoveChainAlias ...
+func (c *Client) RemoveChainAlias(alias string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("removeChainAlias", &RemoveChainAliasArgs{
+		Alias: alias,
+	}, res)
+	return res.Success, err
+}
+
 // GetChainAliases ...
 func (c *Client) GetChainAliases(chain string) ([]string, error) {
 	res := &GetChainAliasesReply{}
@@ -79,6 +101,107 @@ func (c *Client) GetChainAliases(chain string) ([]string, error) {
 	return res.Aliases, err
 }
 
+// ExportChain ...
+func (c *Client) ExportChain(chain, fileName string) (ChainBundle, error) {
+	res := &ExportChainReply{}
+	err := c.requester.SendRequest("exportChain", &ExportChainArgs{
+		Chain:    chain,
+		FileName: fileName,
+	}, res)
+	return res.Bundle, err
+}
+
+// ImportChain ...
+func (c *Client) ImportChain(fileName string) (ChainBundle, error) {
+	res := &ImportChainReply{}
+	err := c.requester.SendRequest("importChain", &ImportChainArgs{
+		FileName: fileName,
+	}, res)
+	return res.Bundle, err
+}
+
+// GetOutstandingPolls ...
+func (c *Client) GetOutstandingPolls(chain string) ([]Poll, error) {
+	res := &GetOutstandingPollsReply{}
+	err := c.requester.SendRequest("getOutstandingPolls", &GetOutstandingPollsArgs{
+		Chain: chain,
+	}, res)
+	return res.Polls, err
+}
+
+// GetBlockedContainers ...
+func (c *Client) GetBlockedContainers(chain string) ([]BlockedContainer, error) {
+	res := &GetBlockedContainersReply{}
+	err := c.requester.SendRequest("getBlockedContainers", &GetBlockedContainersArgs{
+		Chain: chain,
+	}, res)
+	return res.Containers, err
+}
+
+// GetEvidence ...
+func (c *Client) GetEvidence(chain string, nodeID string) ([]Evidence, error) {
+	res := &GetEvidenceReply{}
+	err := c.requester.SendRequest("getEvidence", &GetEvidenceArgs{
+		Chain:  chain,
+		NodeID: nodeID,
+	}, res)
+	return res.Evidence, err
+}
+
+// UpdateConsensusParameters ...
+func (c *Client) UpdateConsensusParameters(chain string, k, alpha, concurrentRepolls uint32) (*UpdateConsensusParametersReply, error) {
+	res := &UpdateConsensusParametersReply{}
+	err := c.requester.SendRequest("updateConsensusParameters", &UpdateConsensusParametersArgs{
+		Chain:             chain,
+		K:                 cjson.Uint32(k),
+		Alpha:             cjson.Uint32(alpha),
+		ConcurrentRepolls: cjson.Uint32(concurrentRepolls),
+	}, res)
+	return res, err
+}
+
+// BanIP ...
+func (c *Client) BanIP(ip string, duration time.Duration) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("banIP", &BanIPArgs{
+		IP:       ip,
+		Duration: duration.String(),
+	}, res)
+	return res.Success, err
+}
+
+// UnbanIP ...
+func (c *Client) UnbanIP(ip string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("unbanIP", &UnbanIPArgs{
+		IP: ip,
+	}, res)
+	return res.Success, err
+}
+
+// GetBannedIPs ...
+func (c *Client) GetBannedIPs() ([]BannedIP, error) {
+	res := &GetBannedIPsReply{}
+	err := c.requester.SendRequest("getBannedIPs", struct{}{}, res)
+	return res.BannedIPs, err
+}
+
+// GetWhitelistedSubnets ...
+func (c *Client) GetWhitelistedSubnets() ([]ids.ID, error) {
+	res := &WhitelistedSubnetsReply{}
+	err := c.requester.SendRequest("getWhitelistedSubnets", struct{}{}, res)
+	return res.Subnets, err
+}
+
+// SetWhitelistedSubnets ...
+func (c *Client) SetWhitelistedSubnets(subnets []ids.ID) ([]ids.ID, error) {
+	res := &WhitelistedSubnetsReply{}
+	err := c.requester.SendRequest("setWhitelistedSubnets", &SetWhitelistedSubnetsArgs{
+		Subnets: subnets,
+	}, res)
+	return res.Subnets, err
+}
+
 // Stacktrace ...
 func (c *Client) Stacktrace() (bool, error) {
 	res := &api.SuccessResponse{}
@@ -6,10 +6,13 @@ package admin
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
@@ -61,6 +64,33 @@ func (mc *mockClient) SendRequest(method string, params interface{}, reply inter
 	case *GetChainAliasesReply:
 		response := mc.response.(*GetChainAliasesReply)
 		*p = *response
+	case *GetRuntimeSamplesReply:
+		response := mc.response.(*GetRuntimeSamplesReply)
+		*p = *response
+	case *GetOutstandingPollsReply:
+		response := mc.response.(*GetOutstandingPollsReply)
+		*p = *response
+	case *GetBlockedContainersReply:
+		response := mc.response.(*GetBlockedContainersReply)
+		*p = *response
+	case *GetEvidenceReply:
+		response := mc.response.(*GetEvidenceReply)
+		*p = *response
+	case *UpdateConsensusParametersReply:
+		response := mc.response.(*UpdateConsensusParametersReply)
+		*p = *response
+	case *GetBannedIPsReply:
+		response := mc.response.(*GetBannedIPsReply)
+		*p = *response
+	case *ExportChainReply:
+		response := mc.response.(*ExportChainReply)
+		*p = *response
+	case *ImportChainReply:
+		response := mc.response.(*ImportChainReply)
+		*p = *response
+	case *WhitelistedSubnetsReply:
+		response := mc.response.(*WhitelistedSubnetsReply)
+		*p = *response
 	default:
 		panic("illegal type")
 	}
@@ -203,6 +233,250 @@ func TestGetChainAliases(t *testing.T) {
 	})
 }
 
+func TestGetRuntimeSamples(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []profiler.Sample{
+			{NumGoroutines: 1},
+			{NumGoroutines: 2},
+		}
+		mockClient := Client{requester: NewMockClient(&GetRuntimeSamplesReply{
+			Samples: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.GetRuntimeSamples(time.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&GetRuntimeSamplesReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetRuntimeSamples(time.Time{})
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetOutstandingPolls(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []Poll{
+			{RequestID: 1, Pending: []string{"NodeID-111111111111111111116DBWJs"}},
+			{RequestID: 2},
+		}
+		mockClient := Client{requester: NewMockClient(&GetOutstandingPollsReply{
+			Polls: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.GetOutstandingPolls("X")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&GetOutstandingPollsReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetOutstandingPolls("X")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetBlockedContainers(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []BlockedContainer{
+			{ContainerID: ids.GenerateTestID(), MissingContainers: []ids.ID{ids.GenerateTestID()}},
+			{ContainerID: ids.GenerateTestID(), MissingTxs: []ids.ID{ids.GenerateTestID()}},
+		}
+		mockClient := Client{requester: NewMockClient(&GetBlockedContainersReply{
+			Containers: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.GetBlockedContainers("X")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&GetBlockedContainersReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetBlockedContainers("X")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetEvidence(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []Evidence{
+			{
+				NodeID:       "NodeID-111111111111111111116DBWJs",
+				Kind:         "unrequestedContainer",
+				RequestID:    1,
+				ContainerIDs: []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()},
+			},
+		}
+		mockClient := Client{requester: NewMockClient(&GetEvidenceReply{
+			Evidence: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.GetEvidence("X", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&GetEvidenceReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetEvidence("X", "")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestUpdateConsensusParameters(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := &UpdateConsensusParametersReply{
+			K:                 20,
+			Alpha:             15,
+			ConcurrentRepolls: 4,
+		}
+		mockClient := Client{requester: NewMockClient(expectedReply, nil)}
+
+		reply, err := mockClient.UpdateConsensusParameters("X", 20, 15, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&UpdateConsensusParametersReply{}, errors.New("some error"))}
+
+		_, err := mockClient.UpdateConsensusParameters("X", 20, 15, 0)
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestBanIP(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := Client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.BanIP("1.2.3.4", time.Hour)
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestUnbanIP(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := Client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.UnbanIP("1.2.3.4")
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestGetBannedIPs(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []BannedIP{
+			{
+				IP:    "1.2.3.4",
+				Until: time.Unix(1607626800, 0),
+			},
+		}
+		mockClient := Client{requester: NewMockClient(&GetBannedIPsReply{
+			BannedIPs: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.GetBannedIPs()
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&GetBannedIPsReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetBannedIPs()
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestExportChain(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedBundle := ChainBundle{
+			ChainID:  ids.GenerateTestID(),
+			SubnetID: ids.GenerateTestID(),
+			Aliases:  []string{"mychain"},
+		}
+		mockClient := Client{requester: NewMockClient(&ExportChainReply{
+			Bundle: expectedBundle,
+		}, nil)}
+
+		bundle, err := mockClient.ExportChain("mychain", "mychain.bundle")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBundle, bundle)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&ExportChainReply{}, errors.New("some error"))}
+
+		_, err := mockClient.ExportChain("mychain", "mychain.bundle")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestImportChain(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedBundle := ChainBundle{
+			ChainID:  ids.GenerateTestID(),
+			SubnetID: ids.GenerateTestID(),
+		}
+		mockClient := Client{requester: NewMockClient(&ImportChainReply{
+			Bundle: expectedBundle,
+		}, nil)}
+
+		bundle, err := mockClient.ImportChain("mychain.bundle")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBundle, bundle)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&ImportChainReply{}, errors.New("some error"))}
+
+		_, err := mockClient.ImportChain("mychain.bundle")
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
 func TestStacktrace(t *testing.T) {
 	tests := GetSuccessResponseTests()
 
@@ -221,3 +495,47 @@ func TestStacktrace(t *testing.T) {
 		}
 	}
 }
+
+func TestGetWhitelistedSubnets(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedSubnets := []ids.ID{ids.GenerateTestID()}
+		mockClient := Client{requester: NewMockClient(&WhitelistedSubnetsReply{
+			Subnets: expectedSubnets,
+		}, nil)}
+
+		subnets, err := mockClient.GetWhitelistedSubnets()
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSubnets, subnets)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&WhitelistedSubnetsReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetWhitelistedSubnets()
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestSetWhitelistedSubnets(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedSubnets := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+		mockClient := Client{requester: NewMockClient(&WhitelistedSubnetsReply{
+			Subnets: expectedSubnets,
+		}, nil)}
+
+		subnets, err := mockClient.SetWhitelistedSubnets(expectedSubnets[1:])
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSubnets, subnets)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&WhitelistedSubnetsReply{}, errors.New("some error"))}
+
+		_, err := mockClient.SetWhitelistedSubnets(nil)
+
+		assert.EqualError(t, err, "some error")
+	})
+}
@@ -5,7 +5,13 @@ package admin
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -13,7 +19,9 @@ import (
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
@@ -28,27 +36,50 @@ const (
 	stacktraceFile = "stacktrace.txt"
 )
 
-var errAliasTooLong = errors.New("alias length is too long")
+var (
+	errAliasTooLong    = errors.New("alias length is too long")
+	errSamplerDisabled = errors.New("runtime stats sampler is disabled")
+	errInvalidIP       = errors.New("invalid IP")
+	errNonPositiveBan  = errors.New("ban duration must be positive")
+	errInvalidBundle   = errors.New("bundle must be named by a file name without a directory")
+)
 
 // Admin is the API service for node admin management
 type Admin struct {
-	log          logging.Logger
-	profiler     profiler.Profiler
-	chainManager chains.Manager
-	httpServer   *server.Server
+	log           logging.Logger
+	profiler      profiler.Profiler
+	sampler       profiler.Sampler
+	chainManager  chains.Manager
+	httpServer    *server.Server
+	connThrottler throttling.InboundConnThrottler
+	// Directory that chain bundles are exported to and imported from
+	exportDir string
 }
 
-// NewService returns a new admin API service
-func NewService(log logging.Logger, chainManager chains.Manager, httpServer *server.Server, profileDir string) (*common.HTTPHandler, error) {
+// NewService returns a new admin API service.
+// [sampler] may be nil if runtime stats sampling is disabled.
+// Chain bundles are only exported to and imported from [exportDir].
+func NewService(
+	log logging.Logger,
+	chainManager chains.Manager,
+	httpServer *server.Server,
+	profileDir string,
+	sampler profiler.Sampler,
+	connThrottler throttling.InboundConnThrottler,
+	exportDir string,
+) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	if err := newServer.RegisterService(&Admin{
-		log:          log,
-		chainManager: chainManager,
-		httpServer:   httpServer,
-		profiler:     profiler.New(profileDir),
+		log:           log,
+		chainManager:  chainManager,
+		httpServer:    httpServer,
+		profiler:      profiler.New(profileDir),
+		sampler:       sampler,
+		connThrottler: connThrottler,
+		exportDir:     exportDir,
 	}, "admin"); err != nil {
 		return nil, err
 	}
@@ -86,6 +117,29 @@ func (service *Admin) LockProfile(_ *http.Request, _ *struct{}, reply *api.Succe
 	return service.profiler.LockProfile()
 }
 
+// GetRuntimeSamplesArgs are the arguments for calling GetRuntimeSamples
+type GetRuntimeSamplesArgs struct {
+	// Only samples taken at or after [StartTime] are returned
+	StartTime time.Time `json:"startTime"`
+}
+
+// GetRuntimeSamplesReply are the recorded runtime stats samples
+type GetRuntimeSamplesReply struct {
+	Samples []profiler.Sample `json:"samples"`
+}
+
+// GetRuntimeSamples returns the periodically recorded runtime stats of this
+// node, ordered from oldest to newest
+func (service *Admin) GetRuntimeSamples(_ *http.Request, args *GetRuntimeSamplesArgs, reply *GetRuntimeSamplesReply) error {
+	service.log.Info("Admin: GetRuntimeSamples called with StartTime: %s", args.StartTime)
+
+	if service.sampler == nil {
+		return errSamplerDisabled
+	}
+	reply.Samples = service.sampler.Samples(args.StartTime)
+	return nil
+}
+
 // AliasArgs are the arguments for calling Alias
 type AliasArgs struct {
 	Endpoint string `json:"endpoint"`
@@ -110,7 +164,8 @@ type AliasChainArgs struct {
 	Alias string `json:"alias"`
 }
 
-// AliasChain attempts to alias a chain to a new name
+// AliasChain attempts to alias a chain to a new name. The alias is given to the
+// chain again when the node restarts.
 func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *api.SuccessResponse) error {
 	service.log.Info("Admin: AliasChain called with Chain: %s, Alias: %s", args.Chain, args.Alias)
 
@@ -122,7 +177,7 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *a
 		return err
 	}
 
-	if err := service.chainManager.Alias(chainID, args.Alias); err != nil {
+	if err := service.chainManager.PersistAlias(chainID, args.Alias, "admin API"); err != nil {
 		return err
 	}
 
@@ -130,6 +185,25 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *a
 	return service.httpServer.AddAliasesWithReadLock("bc/"+chainID.String(), "bc/"+args.Alias)
 }
 
+// RemoveChainAliasArgs are the arguments for calling RemoveChainAlias
+type RemoveChainAliasArgs struct {
+	Alias string `json:"alias"`
+}
+
+// RemoveChainAlias removes an alias given with AliasChain, so that it isn't
+// given to the chain again when the node restarts. The chain's API remains
+// reachable under the alias until the node restarts.
+func (service *Admin) RemoveChainAlias(_ *http.Request, args *RemoveChainAliasArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: RemoveChainAlias called with Alias: %s", args.Alias)
+
+	if err := service.chainManager.RemovePersistedAlias(args.Alias); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return nil
+}
+
 // GetChainAliasesArgs are the arguments for calling GetChainAliases
 type GetChainAliasesArgs struct {
 	Chain string `json:"chain"`
@@ -153,6 +227,454 @@ func (service *Admin) GetChainAliases(r *http.Request, args *GetChainAliasesArgs
 	return nil
 }
 
+// ChainBundle describes the chain stored in a bundle
+type ChainBundle struct {
+	ChainID  ids.ID   `json:"chainID"`
+	SubnetID ids.ID   `json:"subnetID"`
+	VMID     ids.ID   `json:"vmID"`
+	FxIDs    []ids.ID `json:"fxIDs"`
+	Aliases  []string `json:"aliases"`
+}
+
+func newChainBundle(header *chains.BundleHeader) ChainBundle {
+	return ChainBundle{
+		ChainID:  header.ChainID,
+		SubnetID: header.SubnetID,
+		VMID:     header.VMID,
+		FxIDs:    header.FxIDs,
+		Aliases:  header.Aliases,
+	}
+}
+
+// bundlePath returns the path of the bundle file named [fileName] in [dir].
+// [fileName] can't name a file outside of [dir].
+func bundlePath(dir, fileName string) (string, error) {
+	if fileName == "" || fileName == "." || fileName == ".." || filepath.Base(fileName) != fileName {
+		return "", errInvalidBundle
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// ExportChainArgs are the arguments for calling ExportChain
+type ExportChainArgs struct {
+	Chain string `json:"chain"`
+	// Name of the file, in the node's export directory, to write the bundle
+	// to. Must not exist yet.
+	FileName string `json:"fileName"`
+}
+
+// ExportChainReply describes the exported chain
+type ExportChainReply struct {
+	Bundle ChainBundle `json:"bundle"`
+}
+
+// ExportChain writes the chain's genesis, database, aliases and config to a
+// bundle in the node's export directory that can be imported by another node.
+// The chain doesn't process messages while it's being exported.
+func (service *Admin) ExportChain(_ *http.Request, args *ExportChainArgs, reply *ExportChainReply) error {
+	service.log.Info("Admin: ExportChain called with Chain: %s, FileName: %s", args.Chain, args.FileName)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	path, err := bundlePath(service.exportDir, args.FileName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(service.exportDir, perms.ReadWriteExecute); err != nil {
+		return fmt.Errorf("couldn't create export directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perms.ReadWrite)
+	if err != nil {
+		return fmt.Errorf("couldn't create bundle file: %w", err)
+	}
+	header, err := service.chainManager.ExportChain(chainID, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a partial bundle behind
+		_ = os.Remove(path)
+		return err
+	}
+
+	reply.Bundle = newChainBundle(header)
+	return nil
+}
+
+// ImportChainArgs are the arguments for calling ImportChain
+type ImportChainArgs struct {
+	// Name of the bundle file, in the node's export directory, to import
+	FileName string `json:"fileName"`
+}
+
+// ImportChainReply describes the imported chain
+type ImportChainReply struct {
+	Bundle ChainBundle `json:"bundle"`
+}
+
+// ImportChain writes the database of the chain in the bundle, read from the
+// node's export directory, to this node's database. The chain must not be running on this node. It's run, with the
+// bundled aliases and config, once its subnet is whitelisted and the Platform
+// Chain creates it.
+func (service *Admin) ImportChain(_ *http.Request, args *ImportChainArgs, reply *ImportChainReply) error {
+	service.log.Info("Admin: ImportChain called with FileName: %s", args.FileName)
+
+	path, err := bundlePath(service.exportDir, args.FileName)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("couldn't open bundle file: %w", err)
+	}
+	defer file.Close()
+
+	header, err := service.chainManager.ImportChain(file)
+	if err != nil {
+		return err
+	}
+
+	reply.Bundle = newChainBundle(header)
+	return nil
+}
+
+// GetOutstandingPollsArgs are the arguments for calling GetOutstandingPolls
+type GetOutstandingPollsArgs struct {
+	Chain string `json:"chain"`
+}
+
+// Poll describes a poll of the network that hasn't finished yet
+type Poll struct {
+	RequestID cjson.Uint32 `json:"requestID"`
+	// ID of the block or vertex the network was queried about
+	ContainerID ids.ID `json:"containerID"`
+	// Validators that were queried
+	Validators []string `json:"validators"`
+	// Validators that haven't responded yet
+	Pending  []string     `json:"pending"`
+	NumChits cjson.Uint32 `json:"numChits"`
+	Start    time.Time    `json:"start"`
+	Age      string       `json:"age"`
+}
+
+// GetOutstandingPollsReply are the outstanding polls of the given chain
+type GetOutstandingPollsReply struct {
+	Polls []Poll `json:"polls"`
+}
+
+// GetOutstandingPolls returns the polls of the network that the chain is
+// waiting on, ordered from oldest to newest
+func (service *Admin) GetOutstandingPolls(_ *http.Request, args *GetOutstandingPollsArgs, reply *GetOutstandingPollsReply) error {
+	service.log.Info("Admin: GetOutstandingPolls called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	polls, err := service.chainManager.OutstandingPolls(chainID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(polls, func(i, j int) bool { return polls[i].Start.Before(polls[j].Start) })
+
+	now := time.Now()
+	reply.Polls = make([]Poll, len(polls))
+	for i, poll := range polls {
+		reply.Polls[i] = Poll{
+			RequestID:   cjson.Uint32(poll.RequestID),
+			ContainerID: poll.ContainerID,
+			Validators:  formatNodeIDs(poll.Validators),
+			Pending:     formatNodeIDs(poll.Pending),
+			NumChits:    cjson.Uint32(poll.NumChits),
+			Start:       poll.Start,
+			Age:         now.Sub(poll.Start).String(),
+		}
+	}
+	return nil
+}
+
+// GetBlockedContainersArgs are the arguments for calling GetBlockedContainers
+type GetBlockedContainersArgs struct {
+	Chain string `json:"chain"`
+}
+
+// BlockedContainer describes a container that was received but can't be
+// issued to consensus until its dependencies are
+type BlockedContainer struct {
+	ContainerID ids.ID `json:"containerID"`
+	// Containers the container is waiting on
+	MissingContainers []ids.ID `json:"missingContainers"`
+	// Transactions the container is waiting on
+	MissingTxs []ids.ID  `json:"missingTxs"`
+	Since      time.Time `json:"since"`
+	Age        string    `json:"age"`
+}
+
+// GetBlockedContainersReply are the blocked containers of the given chain
+type GetBlockedContainersReply struct {
+	Containers []BlockedContainer `json:"containers"`
+}
+
+// GetBlockedContainers returns the containers that the chain received but
+// can't issue to consensus until their missing dependencies are, ordered from
+// oldest to newest
+func (service *Admin) GetBlockedContainers(_ *http.Request, args *GetBlockedContainersArgs, reply *GetBlockedContainersReply) error {
+	service.log.Info("Admin: GetBlockedContainers called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	blocked, err := service.chainManager.BlockedContainers(chainID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(blocked, func(i, j int) bool { return blocked[i].Since.Before(blocked[j].Since) })
+
+	now := time.Now()
+	reply.Containers = make([]BlockedContainer, len(blocked))
+	for i, container := range blocked {
+		reply.Containers[i] = BlockedContainer{
+			ContainerID:       container.ContainerID,
+			MissingContainers: container.MissingContainers,
+			MissingTxs:        container.MissingTxs,
+			Since:             container.Since,
+			Age:               now.Sub(container.Since).String(),
+		}
+	}
+	return nil
+}
+
+// GetEvidenceArgs are the arguments for calling GetEvidence
+type GetEvidenceArgs struct {
+	Chain string `json:"chain"`
+	// If non-empty, only the evidence against this node is returned
+	NodeID string `json:"nodeID"`
+}
+
+// Evidence records that a peer misbehaved
+type Evidence struct {
+	NodeID string `json:"nodeID"`
+	Kind   string `json:"kind"`
+	// ID of the request the peer responded to. 0 if the message wasn't a
+	// response to a request.
+	RequestID cjson.Uint32 `json:"requestID"`
+	// Containers that show the misbehavior
+	ContainerIDs []ids.ID  `json:"containerIDs"`
+	Description  string    `json:"description"`
+	Time         time.Time `json:"time"`
+}
+
+// GetEvidenceReply is the evidence recorded by the given chain
+type GetEvidenceReply struct {
+	Evidence []Evidence `json:"evidence"`
+}
+
+// GetEvidence returns the evidence of peers misbehaving that the chain
+// recorded, ordered from oldest to newest
+func (service *Admin) GetEvidence(_ *http.Request, args *GetEvidenceArgs, reply *GetEvidenceReply) error {
+	service.log.Info("Admin: GetEvidence called with Chain: %s, NodeID: %s", args.Chain, args.NodeID)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	var nodeID ids.ShortID
+	if args.NodeID != "" {
+		nodeID, err = ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+	}
+	evidence, err := service.chainManager.Evidence(chainID)
+	if err != nil {
+		return err
+	}
+
+	reply.Evidence = make([]Evidence, 0, len(evidence))
+	for _, e := range evidence {
+		if args.NodeID != "" && e.NodeID != nodeID {
+			continue
+		}
+		reply.Evidence = append(reply.Evidence, Evidence{
+			NodeID:       e.NodeID.PrefixedString(constants.NodeIDPrefix),
+			Kind:         string(e.Kind),
+			RequestID:    cjson.Uint32(e.RequestID),
+			ContainerIDs: e.ContainerIDs,
+			Description:  e.Description,
+			Time:         e.Time,
+		})
+	}
+	return nil
+}
+
+// UpdateConsensusParametersArgs are the arguments for calling
+// UpdateConsensusParameters. Zero parameters keep their current values.
+type UpdateConsensusParametersArgs struct {
+	Chain             string       `json:"chain"`
+	K                 cjson.Uint32 `json:"k"`
+	Alpha             cjson.Uint32 `json:"alpha"`
+	ConcurrentRepolls cjson.Uint32 `json:"concurrentRepolls"`
+}
+
+// UpdateConsensusParametersReply are the parameters the chain uses after the
+// update
+type UpdateConsensusParametersReply struct {
+	K                 cjson.Uint32 `json:"k"`
+	Alpha             cjson.Uint32 `json:"alpha"`
+	ConcurrentRepolls cjson.Uint32 `json:"concurrentRepolls"`
+}
+
+// UpdateConsensusParameters changes the sampling parameters of a running
+// chain. The new parameters aren't persisted, so they only apply until the
+// node restarts.
+func (service *Admin) UpdateConsensusParameters(_ *http.Request, args *UpdateConsensusParametersArgs, reply *UpdateConsensusParametersReply) error {
+	service.log.Info("Admin: UpdateConsensusParameters called with Chain: %s, K: %d, Alpha: %d, ConcurrentRepolls: %d",
+		args.Chain, args.K, args.Alpha, args.ConcurrentRepolls)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	params, err := service.chainManager.UpdateSamplingParameters(chainID, common.SamplingParameters{
+		K:                 int(args.K),
+		Alpha:             int(args.Alpha),
+		ConcurrentRepolls: int(args.ConcurrentRepolls),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't update the consensus parameters: %w", err)
+	}
+	reply.K = cjson.Uint32(params.K)
+	reply.Alpha = cjson.Uint32(params.Alpha)
+	reply.ConcurrentRepolls = cjson.Uint32(params.ConcurrentRepolls)
+	return nil
+}
+
+// BanIPArgs are the arguments for calling BanIP
+type BanIPArgs struct {
+	IP string `json:"ip"`
+	// How long the IP is banned, e.g. "1h30m"
+	Duration string `json:"duration"`
+}
+
+// BanIP closes the connections of the IP and doesn't accept any connection
+// from it for the given duration. The ban isn't persisted, so it only applies
+// until the node restarts.
+func (service *Admin) BanIP(_ *http.Request, args *BanIPArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: BanIP called with IP: %s, Duration: %s", args.IP, args.Duration)
+
+	ip := net.ParseIP(args.IP)
+	if ip == nil {
+		return fmt.Errorf("%w: %q", errInvalidIP, args.IP)
+	}
+	duration, err := time.ParseDuration(args.Duration)
+	if err != nil {
+		return fmt.Errorf("couldn't parse duration: %w", err)
+	}
+	if duration <= 0 {
+		return errNonPositiveBan
+	}
+
+	service.connThrottler.Ban(ip.String(), duration)
+	reply.Success = true
+	return nil
+}
+
+// UnbanIPArgs are the arguments for calling UnbanIP
+type UnbanIPArgs struct {
+	IP string `json:"ip"`
+}
+
+// UnbanIP accepts connections from the IP again. Success is false if the IP
+// wasn't banned.
+func (service *Admin) UnbanIP(_ *http.Request, args *UnbanIPArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: UnbanIP called with IP: %s", args.IP)
+
+	ip := net.ParseIP(args.IP)
+	if ip == nil {
+		return fmt.Errorf("%w: %q", errInvalidIP, args.IP)
+	}
+	reply.Success = service.connThrottler.Unban(ip.String())
+	return nil
+}
+
+// BannedIP is an IP that no connection is accepted from
+type BannedIP struct {
+	IP    string    `json:"ip"`
+	Until time.Time `json:"until"`
+}
+
+// GetBannedIPsReply are the IPs that are banned
+type GetBannedIPsReply struct {
+	BannedIPs []BannedIP `json:"bannedIPs"`
+}
+
+// GetBannedIPs returns the IPs that are banned, ordered by when their bans
+// expire
+func (service *Admin) GetBannedIPs(_ *http.Request, _ *struct{}, reply *GetBannedIPsReply) error {
+	service.log.Info("Admin: GetBannedIPs called")
+
+	banned := service.connThrottler.BannedIPs()
+	reply.BannedIPs = make([]BannedIP, 0, len(banned))
+	for ip, until := range banned {
+		reply.BannedIPs = append(reply.BannedIPs, BannedIP{
+			IP:    ip,
+			Until: until,
+		})
+	}
+	sort.Slice(reply.BannedIPs, func(i, j int) bool { return reply.BannedIPs[i].Until.Before(reply.BannedIPs[j].Until) })
+	return nil
+}
+
+// WhitelistedSubnetsReply are the subnets whose chains the node runs
+type WhitelistedSubnetsReply struct {
+	Subnets []ids.ID `json:"subnets"`
+}
+
+// GetWhitelistedSubnets returns the subnets whose chains the node runs,
+// including the primary network
+func (service *Admin) GetWhitelistedSubnets(_ *http.Request, _ *struct{}, reply *WhitelistedSubnetsReply) error {
+	service.log.Info("Admin: GetWhitelistedSubnets called")
+
+	reply.Subnets = service.chainManager.WhitelistedSubnets().List()
+	ids.SortIDs(reply.Subnets)
+	return nil
+}
+
+// SetWhitelistedSubnetsArgs are the arguments for calling SetWhitelistedSubnets
+type SetWhitelistedSubnetsArgs struct {
+	Subnets []ids.ID `json:"subnets"`
+}
+
+// SetWhitelistedSubnets replaces the subnets whose chains the node runs, like
+// restarting the node with --whitelisted-subnets would. The chains of the
+// subnets added to the whitelist are created, and the chains of the subnets
+// removed from it are shut down. A chain that was shut down can only run again
+// after the node restarts. The new whitelist isn't persisted, so it only
+// applies until the node restarts.
+func (service *Admin) SetWhitelistedSubnets(_ *http.Request, args *SetWhitelistedSubnetsArgs, reply *WhitelistedSubnetsReply) error {
+	service.log.Info("Admin: SetWhitelistedSubnets called with Subnets: %s", args.Subnets)
+
+	subnetIDs := ids.NewSet(len(args.Subnets))
+	subnetIDs.Add(args.Subnets...)
+	if err := service.chainManager.SetWhitelistedSubnets(subnetIDs); err != nil {
+		return fmt.Errorf("couldn't update the whitelisted subnets: %w", err)
+	}
+	reply.Subnets = service.chainManager.WhitelistedSubnets().List()
+	ids.SortIDs(reply.Subnets)
+	return nil
+}
+
+func formatNodeIDs(nodeIDs []ids.ShortID) []string {
+	nodeIDStrs := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		nodeIDStrs[i] = nodeID.PrefixedString(constants.NodeIDPrefix)
+	}
+	return nodeIDStrs
+}
+
 // Stacktrace returns the current global stacktrace
 func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
 	service.log.Info("Admin: Stacktrace called")
@@ -0,0 +1,31 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundlePath(t *testing.T) {
+	dir := filepath.Join("node", "exports")
+
+	path, err := bundlePath(dir, "mychain.bundle")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "mychain.bundle"), path)
+
+	for _, fileName := range []string{
+		"",
+		".",
+		"..",
+		"../mychain.bundle",
+		"bundles/mychain.bundle",
+		"/tmp/mychain.bundle",
+	} {
+		_, err := bundlePath(dir, fileName)
+		assert.ErrorIs(t, err, errInvalidBundle, fileName)
+	}
+}
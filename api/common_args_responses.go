@@ -64,9 +64,12 @@ type JSONSpendHeader struct {
 }
 
 // GetTxArgs ...
+// If the chain supports snapshots and [Snapshot] is non-empty, the tx is read
+// from that snapshot.
 type GetTxArgs struct {
 	TxID     ids.ID              `json:"txID"`
 	Encoding formatting.Encoding `json:"encoding"`
+	Snapshot ids.ID              `json:"snapshot"`
 }
 
 // FormattedTx defines a JSON formatted struct containing a Tx in CB58 format
@@ -94,12 +97,18 @@ type Index struct {
 // If [StartIndex] is omitted, gets all UTXOs.
 // If GetUTXOs is called multiple times, with our without [StartIndex], it is not guaranteed
 // that returned UTXOs are unique. That is, the same UTXO may appear in the response of multiple calls.
+// If the chain supports snapshots and [Snapshot] is non-empty, the native UTXOs are read from that
+// snapshot.
+// If the chain keeps a UTXO history and [Epoch] is non-nil, the native UTXOs are the ones that
+// existed at the end of [Epoch].
 type GetUTXOsArgs struct {
 	Addresses   []string            `json:"addresses"`
 	SourceChain string              `json:"sourceChain"`
 	Limit       json.Uint32         `json:"limit"`
 	StartIndex  Index               `json:"startIndex"`
 	Encoding    formatting.Encoding `json:"encoding"`
+	Snapshot    ids.ID              `json:"snapshot"`
+	Epoch       *json.Uint32        `json:"epoch"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
@@ -0,0 +1,195 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errNotSigner             = errors.New("certificate's private key can't sign")
+	errUnsupportedPublicKey  = errors.New("unsupported certificate public key algorithm")
+	errWrongBeaconNodeID     = errors.New("beacon's node ID doesn't match its certificate")
+	errUnexpectedBeaconReply = errors.New("unexpected beacon endpoint response")
+)
+
+// ChainStatus describes the progress of a chain
+type ChainStatus struct {
+	Bootstrapped bool `json:"bootstrapped"`
+	// IDs of the last accepted containers
+	Frontier []ids.ID `json:"frontier"`
+	// Height of the highest last accepted container
+	Height uint64 `json:"height"`
+}
+
+// BeaconMessage describes the liveness of a node
+type BeaconMessage struct {
+	NodeID    ids.ShortID `json:"nodeID"`
+	Version   string      `json:"version"`
+	Timestamp time.Time   `json:"timestamp"`
+	// True if every health check is passing
+	Healthy bool `json:"healthy"`
+	// Chain alias --> status of that chain
+	Chains map[string]ChainStatus `json:"chains"`
+}
+
+// SignedBeacon is a beacon message signed by the staking key of the node that
+// sent it
+type SignedBeacon struct {
+	// JSON encoding of a BeaconMessage
+	Message json.RawMessage `json:"message"`
+	// Signature of the SHA-256 hash of [Message]
+	Signature []byte `json:"signature"`
+	// DER encoding of the staking certificate of the node
+	Certificate []byte `json:"certificate"`
+}
+
+// SignBeacon signs [msg] with the private key of [cert]
+func SignBeacon(msg *BeaconMessage, cert tls.Certificate) (*SignedBeacon, error) {
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errNotSigner
+	}
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.Sign(rand.Reader, hashing.ComputeHash256(msgBytes), crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedBeacon{
+		Message:     msgBytes,
+		Signature:   sig,
+		Certificate: cert.Leaf.Raw,
+	}, nil
+}
+
+// VerifyBeacon returns the message of [beacon] if it was signed by the node
+// the message claims to be from
+func VerifyBeacon(beacon *SignedBeacon) (*BeaconMessage, error) {
+	cert, err := x509.ParseCertificate(beacon.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA:
+		algorithm = x509.SHA256WithRSA
+	case x509.ECDSA:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return nil, errUnsupportedPublicKey
+	}
+	if err := cert.CheckSignature(algorithm, beacon.Message, beacon.Signature); err != nil {
+		return nil, err
+	}
+
+	msg := &BeaconMessage{}
+	if err := json.Unmarshal(beacon.Message, msg); err != nil {
+		return nil, err
+	}
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(beacon.Certificate))
+	if err != nil {
+		return nil, err
+	}
+	if nodeID != msg.NodeID {
+		return nil, errWrongBeaconNodeID
+	}
+	return msg, nil
+}
+
+// Beacon periodically sends a signed beacon message to an HTTP endpoint
+type Beacon interface {
+	Dispatch()
+	Shutdown()
+}
+
+type beacon struct {
+	log    logging.Logger
+	url    string
+	freq   time.Duration
+	cert   tls.Certificate
+	client http.Client
+	// Returns the message to send
+	message func() *BeaconMessage
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// NewBeacon returns a beacon that POSTs the message returned by [message],
+// signed with the private key of [cert], to [url] every [freq].
+func NewBeacon(
+	log logging.Logger,
+	url string,
+	freq time.Duration,
+	cert tls.Certificate,
+	message func() *BeaconMessage,
+) Beacon {
+	return &beacon{
+		log:     log,
+		url:     url,
+		freq:    freq,
+		cert:    cert,
+		client:  http.Client{Timeout: freq},
+		message: message,
+		closer:  make(chan struct{}),
+	}
+}
+
+func (b *beacon) Dispatch() {
+	t := time.NewTicker(b.freq)
+	defer t.Stop()
+
+	for {
+		if err := b.send(); err != nil {
+			b.log.Warn("failed to send health beacon to %s: %s", b.url, err)
+		}
+
+		select {
+		case <-b.closer:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (b *beacon) Shutdown() {
+	close(b.closer)
+}
+
+func (b *beacon) send() error {
+	signed, err := SignBeacon(b.message(), b.cert)
+	if err != nil {
+		return fmt.Errorf("couldn't sign beacon: %w", err)
+	}
+	body, err := json.Marshal(signed)
+	if err != nil {
+		return err
+	}
+	resp, err := b.client.Post(b.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// The body isn't needed, so the error closing it is ignored
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", errUnexpectedBeaconReply, resp.Status)
+	}
+	return nil
+}
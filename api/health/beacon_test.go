@@ -0,0 +1,95 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestSignAndVerifyBeacon(t *testing.T) {
+	assert := assert.New(t)
+
+	cert, err := staking.NewTLSCert()
+	assert.NoError(err)
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Leaf.Raw))
+	assert.NoError(err)
+
+	msg := &BeaconMessage{
+		NodeID:    nodeID,
+		Version:   "avalanche/1.0.0",
+		Timestamp: time.Unix(1000, 0).UTC(),
+		Healthy:   true,
+		Chains: map[string]ChainStatus{
+			"X": {Bootstrapped: true, Frontier: []ids.ID{{1}}, Height: 5},
+		},
+	}
+	signed, err := SignBeacon(msg, *cert)
+	assert.NoError(err)
+
+	verified, err := VerifyBeacon(signed)
+	assert.NoError(err)
+	assert.Equal(msg, verified)
+
+	// A tampered message isn't accepted
+	tampered := *signed
+	tampered.Message = []byte(`{"nodeID":"` + nodeID.String() + `","healthy":false}`)
+	_, err = VerifyBeacon(&tampered)
+	assert.Error(err)
+
+	// A node can't send beacons on behalf of another node
+	msg.NodeID = ids.GenerateTestShortID()
+	signed, err = SignBeacon(msg, *cert)
+	assert.NoError(err)
+	_, err = VerifyBeacon(signed)
+	assert.Equal(errWrongBeaconNodeID, err)
+}
+
+func TestBeaconSend(t *testing.T) {
+	assert := assert.New(t)
+
+	cert, err := staking.NewTLSCert()
+	assert.NoError(err)
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Leaf.Raw))
+	assert.NoError(err)
+
+	received := make(chan *BeaconMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signed := &SignedBeacon{}
+		if err := json.NewDecoder(r.Body).Decode(signed); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		msg, err := VerifyBeacon(signed)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received <- msg
+	}))
+	defer server.Close()
+
+	b := NewBeacon(logging.NoLog{}, server.URL, time.Second, *cert, func() *BeaconMessage {
+		return &BeaconMessage{NodeID: nodeID}
+	})
+	assert.NoError(b.(*beacon).send())
+	msg := <-received
+	assert.Equal(nodeID, msg.NodeID)
+
+	// A beacon the endpoint rejects is reported
+	b = NewBeacon(logging.NoLog{}, server.URL, time.Second, *cert, func() *BeaconMessage {
+		return &BeaconMessage{NodeID: ids.GenerateTestShortID()}
+	})
+	assert.Error(b.(*beacon).send())
+}
@@ -30,8 +30,14 @@ type Service interface {
 	Handler() (*common.HTTPHandler, error)
 }
 
-func NewService(checkFreq time.Duration, log logging.Logger, namespace string, registry prometheus.Registerer) (Service, error) {
-	service, err := healthlib.NewService(checkFreq, log, namespace, registry)
+func NewService(
+	checkFreq time.Duration,
+	log logging.Logger,
+	namespace string,
+	registry prometheus.Registerer,
+	onChange func(name string, healthy bool),
+) (Service, error) {
+	service, err := healthlib.NewService(checkFreq, log, namespace, registry, onChange)
 	if err != nil {
 		return nil, err
 	}
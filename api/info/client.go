@@ -6,7 +6,9 @@ package info
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
@@ -59,6 +61,13 @@ func (c *Client) Peers() ([]network.PeerID, error) {
 	return res.Peers, err
 }
 
+// PeerTopology ...
+func (c *Client) PeerTopology() (network.Topology, error) {
+	res := &PeerTopologyReply{}
+	err := c.requester.SendRequest("peerTopology", struct{}{}, res)
+	return res.Topology, err
+}
+
 // IsBootstrapped ...
 func (c *Client) IsBootstrapped(chain string) (bool, error) {
 	res := &IsBootstrappedResponse{}
@@ -68,6 +77,26 @@ func (c *Client) IsBootstrapped(chain string) (bool, error) {
 	return res.IsBootstrapped, err
 }
 
+// GetTxFinality ...
+func (c *Client) GetTxFinality(chain string, txID ids.ID) (*GetTxFinalityReply, error) {
+	res := &GetTxFinalityReply{}
+	err := c.requester.SendRequest("getTxFinality", &GetTxFinalityArgs{
+		Chain: chain,
+		TxID:  txID,
+	}, res)
+	return res, err
+}
+
+// GetBlockIDAtHeight ...
+func (c *Client) GetBlockIDAtHeight(chain string, height uint64) (ids.ID, error) {
+	res := &GetBlockIDAtHeightReply{}
+	err := c.requester.SendRequest("getBlockIDAtHeight", &GetBlockIDAtHeightArgs{
+		Chain:  chain,
+		Height: json.Uint64(height),
+	}, res)
+	return res.BlockID, err
+}
+
 // GetTxFee ...
 func (c *Client) GetTxFee() (*GetTxFeeResponse, error) {
 	res := &GetTxFeeResponse{}
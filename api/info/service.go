@@ -178,6 +178,20 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 	return nil
 }
 
+// PeerTopologyReply are the results from calling PeerTopology
+type PeerTopologyReply struct {
+	network.Topology
+}
+
+// PeerTopology returns the number of connected peers in each country and
+// autonomous system
+func (service *Info) PeerTopology(_ *http.Request, _ *struct{}, reply *PeerTopologyReply) error {
+	service.log.Info("Info: PeerTopology called")
+
+	reply.Topology = service.networking.Topology()
+	return nil
+}
+
 // IsBootstrappedArgs are the arguments for calling IsBootstrapped
 type IsBootstrappedArgs struct {
 	// Alias of the chain
@@ -206,6 +220,100 @@ func (service *Info) IsBootstrapped(_ *http.Request, args *IsBootstrappedArgs, r
 	return nil
 }
 
+// GetTxFinalityArgs are the arguments for calling GetTxFinality
+type GetTxFinalityArgs struct {
+	// Alias of the chain
+	// Can also be the string representation of the chain's ID
+	Chain string `json:"chain"`
+	// ID of the processing transaction
+	TxID ids.ID `json:"txID"`
+}
+
+// GetTxFinalityReply estimates how close a processing transaction is to being
+// accepted
+type GetTxFinalityReply struct {
+	// Number of consecutive successful polls the tx is currently in
+	Confidence json.Uint32 `json:"confidence"`
+	// Number of consecutive successful polls needed to accept the tx
+	Beta json.Uint32 `json:"beta"`
+	// Number of processing txs that conflict with the tx
+	NumConflicts json.Uint32 `json:"numConflicts"`
+	// Number of polls finished since the tx was issued
+	NumPolls json.Uint32 `json:"numPolls"`
+	// Number of those polls that the tx received at least alpha votes in
+	NumSuccessfulPolls json.Uint32 `json:"numSuccessfulPolls"`
+	// Estimated portion of stake that prefers the tx
+	VoteFraction json.Float64 `json:"voteFraction"`
+	// Estimated probability that the tx is accepted by the next
+	// [beta - confidence] polls
+	Probability json.Float64 `json:"probability"`
+	// Estimated time until the tx is accepted. Empty if there isn't enough
+	// information to make an estimate.
+	ETA string `json:"eta"`
+}
+
+// GetTxFinality returns an estimate of how close the processing transaction
+// is to being accepted, based on the polls recorded since it was issued. This
+// is a heuristic; the transaction is final only once it's accepted.
+func (service *Info) GetTxFinality(_ *http.Request, args *GetTxFinalityArgs, reply *GetTxFinalityReply) error {
+	service.log.Info("Info: GetTxFinality called with chain: %s, txID: %s", args.Chain, args.TxID)
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	finality, err := service.chainManager.TxFinality(chainID, args.TxID)
+	if err != nil {
+		return fmt.Errorf("couldn't estimate finality of %s: %w", args.TxID, err)
+	}
+
+	reply.Confidence = json.Uint32(finality.Confidence)
+	reply.Beta = json.Uint32(finality.Beta)
+	reply.NumConflicts = json.Uint32(finality.NumConflicts)
+	reply.NumPolls = json.Uint32(finality.NumPolls)
+	reply.NumSuccessfulPolls = json.Uint32(finality.NumSuccessfulPolls)
+	reply.VoteFraction = json.Float64(finality.VoteFraction)
+	reply.Probability = json.Float64(finality.Probability)
+	if finality.ETA > 0 {
+		reply.ETA = finality.ETA.String()
+	}
+	return nil
+}
+
+// GetBlockIDAtHeightArgs are the arguments for calling GetBlockIDAtHeight
+type GetBlockIDAtHeightArgs struct {
+	// Alias of the chain
+	// Can also be the string representation of the chain's ID
+	Chain string `json:"chain"`
+	// Height of the accepted block
+	Height json.Uint64 `json:"height"`
+}
+
+// GetBlockIDAtHeightReply are the results from calling GetBlockIDAtHeight
+type GetBlockIDAtHeightReply struct {
+	BlockID ids.ID `json:"blockID"`
+}
+
+// GetBlockIDAtHeight returns the ID of the block accepted at the given height
+// by a linear chain. The chain must index its accepted blocks by height.
+func (service *Info) GetBlockIDAtHeight(_ *http.Request, args *GetBlockIDAtHeightArgs, reply *GetBlockIDAtHeightReply) error {
+	service.log.Info("Info: GetBlockIDAtHeight called with chain: %s, height: %d", args.Chain, args.Height)
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	reply.BlockID, err = service.chainManager.GetBlockIDAtHeight(chainID, uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("couldn't get the block at height %d: %w", args.Height, err)
+	}
+	return nil
+}
+
 // GetTxFeeResponse ...
 type GetTxFeeResponse struct {
 	CreationTxFee json.Uint64 `json:"creationTxFee"`
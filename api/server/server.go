@@ -100,7 +100,7 @@ func (s *Server) Initialize(
 
 // Dispatch starts the API server
 func (s *Server) Dispatch() error {
-	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
+	listenAddress := net.JoinHostPort(s.listenHost, fmt.Sprintf("%d", s.listenPort))
 	listener, err := net.Listen("tcp", listenAddress)
 	if err != nil {
 		return err
@@ -110,7 +110,7 @@ func (s *Server) Dispatch() error {
 	if err != nil {
 		s.log.Info("HTTP API server listening on %q", listenAddress)
 	} else {
-		s.log.Info("HTTP API server listening on \"%s:%d\"", s.listenHost, ipDesc.Port)
+		s.log.Info("HTTP API server listening on %q", net.JoinHostPort(s.listenHost, fmt.Sprintf("%d", ipDesc.Port)))
 	}
 
 	s.srv = &http.Server{Handler: s.handler}
@@ -119,7 +119,7 @@ func (s *Server) Dispatch() error {
 
 // DispatchTLS starts the API server with the provided TLS certificate
 func (s *Server) DispatchTLS(certFile, keyFile string) error {
-	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
+	listenAddress := net.JoinHostPort(s.listenHost, fmt.Sprintf("%d", s.listenPort))
 	listener, err := net.Listen("tcp", listenAddress)
 	if err != nil {
 		return err
@@ -129,7 +129,7 @@ func (s *Server) DispatchTLS(certFile, keyFile string) error {
 	if err != nil {
 		s.log.Info("HTTPS API server listening on %q", listenAddress)
 	} else {
-		s.log.Info("HTTPS API server listening on \"%s:%d\"", s.listenHost, ipDesc.Port)
+		s.log.Info("HTTPS API server listening on %q", net.JoinHostPort(s.listenHost, fmt.Sprintf("%d", ipDesc.Port)))
 	}
 
 	return http.ServeTLS(listener, s.handler, certFile, keyFile)
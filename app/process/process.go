@@ -223,6 +223,7 @@ func (a *App) Start() int {
 		a.config.DynamicUpdateDuration,
 		a.log,
 		&a.config.StakingIP,
+		a.config.DynamicDNSUpdater,
 	)
 	defer externalIPUpdater.Stop()
 
@@ -0,0 +1,85 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var _ ids.AliasDB = &aliasDB{}
+
+// persistedAlias is what's stored for each persisted alias of a chain
+type persistedAlias struct {
+	ChainID    ids.ID `serialize:"true"`
+	Registrant string `serialize:"true"`
+}
+
+// aliasDB stores the persisted aliases of chains in [db]. The key of an alias
+// is the alias itself.
+type aliasDB struct {
+	db database.Database
+}
+
+func (a *aliasDB) GetAliases() ([]ids.AliasRecord, error) {
+	it := a.db.NewIterator()
+	defer it.Release()
+
+	records := []ids.AliasRecord(nil)
+	for it.Next() {
+		alias := persistedAlias{}
+		if _, err := bundleCodec.Unmarshal(it.Value(), &alias); err != nil {
+			return nil, err
+		}
+		records = append(records, ids.AliasRecord{
+			ID:         alias.ChainID,
+			Alias:      string(it.Key()),
+			Registrant: alias.Registrant,
+		})
+	}
+	return records, it.Error()
+}
+
+func (a *aliasDB) PutAlias(record ids.AliasRecord) error {
+	aliasBytes, err := bundleCodec.Marshal(bundleCodecVersion, &persistedAlias{
+		ChainID:    record.ID,
+		Registrant: record.Registrant,
+	})
+	if err != nil {
+		return err
+	}
+	return a.db.Put([]byte(record.Alias), aliasBytes)
+}
+
+func (a *aliasDB) DeleteAlias(alias string) error {
+	return a.db.Delete([]byte(alias))
+}
+
+// LoadAliases gives chains the aliases persisted with PersistAlias, and
+// exposes the chains' APIs under those aliases
+func (m *manager) LoadAliases() error {
+	db := &aliasDB{db: m.persistedAliases}
+	collisions, err := m.Load(db)
+	if err != nil {
+		return err
+	}
+	for _, collision := range collisions {
+		m.Log.Warn("couldn't give chain its persisted alias: %s", collision)
+	}
+
+	records, err := db.GetAliases()
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if chainID, err := m.Lookup(record.Alias); err != nil || chainID != record.ID {
+			// This alias collided with an alias of another chain
+			continue
+		}
+		if err := m.Server.AddAliases("bc/"+record.ID.String(), "bc/"+record.Alias); err != nil {
+			m.Log.Warn("couldn't add API alias %s of chain %s: %s", record.Alias, record.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestAliasDB(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	chainID := ids.GenerateTestID()
+
+	aliaser := ids.Aliaser{}
+	aliaser.Initialize()
+	_, err := aliaser.Load(&aliasDB{db: db})
+	assert.NoError(err)
+	assert.NoError(aliaser.PersistAlias(chainID, "mychain", "admin API"))
+
+	reloaded := ids.Aliaser{}
+	reloaded.Initialize()
+	collisions, err := reloaded.Load(&aliasDB{db: db})
+	assert.NoError(err)
+	assert.Empty(collisions)
+
+	loadedID, err := reloaded.Lookup("mychain")
+	assert.NoError(err)
+	assert.Equal(chainID, loadedID)
+	registrant, ok := reloaded.Registrant("mychain")
+	assert.True(ok)
+	assert.Equal("admin API", registrant)
+
+	assert.NoError(reloaded.RemoveAliases(chainID))
+	records, err := (&aliasDB{db: db}).GetAliases()
+	assert.NoError(err)
+	assert.Empty(records)
+}
@@ -0,0 +1,336 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	bundleCodecVersion = 0
+
+	// Marks that a key/value pair follows in a bundle
+	bundleRecordMarker byte = 1
+	// Marks the end of the key/value pairs of a bundle
+	bundleEndMarker byte = 0
+
+	// Max size of the header, a key or a value in a bundle
+	maxBundleFieldSize = 64 * units.MiB
+	// Keys read from a bundle are written to the database once a batch gets
+	// this large
+	bundleBatchSize = 4 * units.MiB
+)
+
+var (
+	bundleCodec codec.Manager
+
+	errBundleFieldTooLarge = errors.New("bundle field is too large")
+	errBundleChecksum      = errors.New("bundle checksum doesn't match its content")
+	errInvalidBundleMarker = errors.New("invalid bundle marker")
+	errUnexpectedBundleKey = errors.New("bundle contains a key that isn't under one of the chain's prefixes")
+)
+
+func init() {
+	bundleCodec = codec.NewManager(math.MaxInt32)
+	if err := bundleCodec.RegisterCodec(bundleCodecVersion, linearcodec.NewDefault()); err != nil {
+		panic(err)
+	}
+}
+
+// BundleHeader describes the chain stored in a bundle.
+//
+// A bundle is the header followed by every key/value pair that the chain
+// stored in the database under one of its prefixes, and a checksum of the
+// bundle's content.
+type BundleHeader struct {
+	// Version of the database the chain's data was read from
+	DatabaseVersion string   `serialize:"true"`
+	ChainID         ids.ID   `serialize:"true"`
+	SubnetID        ids.ID   `serialize:"true"`
+	VMID            ids.ID   `serialize:"true"`
+	FxIDs           []ids.ID `serialize:"true"`
+	GenesisData     []byte   `serialize:"true"`
+	// Aliases of the chain, other than its ID
+	Aliases []string `serialize:"true"`
+	Config  []byte   `serialize:"true"`
+	Upgrade []byte   `serialize:"true"`
+	// Paths, as described by prefixdb.NewTracked, of the databases that the
+	// chain stored keys in, relative to the chain's database
+	PrefixPaths [][][]byte `serialize:"true"`
+}
+
+// prefixes returns the prefixes that the keys of the chain are stored under in
+// the database. They are derived from [ChainID], so that a bundle can't write
+// keys outside of the chain's databases.
+func (h *BundleHeader) prefixes() [][]byte {
+	prefixes := make([][]byte, len(h.PrefixPaths))
+	for i, path := range h.PrefixPaths {
+		prefixes[i] = prefixdb.MakePrefix(append([][]byte{h.ChainID[:]}, path...)...)
+	}
+	return prefixes
+}
+
+// prefixTracker records the paths of the databases that a chain stores its
+// keys in
+type prefixTracker struct {
+	lock sync.Mutex
+	// Prefix of the path's database under an empty root --> path
+	paths map[string][][]byte
+}
+
+func newPrefixTracker() *prefixTracker {
+	return &prefixTracker{paths: make(map[string][][]byte)}
+}
+
+func (p *prefixTracker) add(path [][]byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.paths[string(prefixdb.MakePrefix(path...))] = path
+}
+
+// list returns the tracked paths in a deterministic order
+func (p *prefixTracker) list() [][][]byte {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	keys := make([]string, 0, len(p.paths))
+	for key := range p.paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	paths := make([][][]byte, len(keys))
+	for i, key := range keys {
+		paths[i] = p.paths[key]
+	}
+	return paths
+}
+
+// writeBundle writes [header] followed by every key/value pair of [db] under
+// one of the header's prefixes to [w]. [lock] is held until the bundle is
+// written, so that the bundle is a consistent snapshot of [db] as long as the
+// chain only writes to [db] while holding [lock].
+func writeBundle(w io.Writer, header *BundleHeader, db database.Iteratee, lock sync.Locker) error {
+	headerBytes, err := bundleCodec.Marshal(bundleCodecVersion, header)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal bundle header: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	checksum := sha256.New()
+	cw := io.MultiWriter(bw, checksum)
+	if err := writeBundleField(cw, headerBytes); err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, prefix := range header.prefixes() {
+		if err := writeBundleRecords(cw, db, prefix); err != nil {
+			return err
+		}
+	}
+
+	if _, err := cw.Write([]byte{bundleEndMarker}); err != nil {
+		return err
+	}
+	if _, err := bw.Write(checksum.Sum(nil)); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// writeBundleRecords writes the key/value pairs of [db] under [prefix] to [w]
+func writeBundleRecords(w io.Writer, db database.Iteratee, prefix []byte) error {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		if _, err := w.Write([]byte{bundleRecordMarker}); err != nil {
+			return err
+		}
+		if err := writeBundleField(w, it.Key()); err != nil {
+			return err
+		}
+		if err := writeBundleField(w, it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// readBundle writes the key/value pairs of the bundle read from [r] to [db] and
+// returns the bundle's header. [verify] is called with the header before
+// anything is written to [db], and the bundle isn't read if it returns an
+// error. Keys that aren't under one of the prefixes of the bundle's chain are
+// rejected. If the bundle turns out to be invalid after keys were written, the
+// keys under the chain's prefixes are removed from [db].
+func readBundle(r io.Reader, db database.Database, verify func(*BundleHeader) error) (*BundleHeader, error) {
+	br := bufio.NewReader(r)
+	checksum := sha256.New()
+	cr := io.TeeReader(br, checksum)
+
+	headerBytes, err := readBundleField(cr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read bundle header: %w", err)
+	}
+	header := &BundleHeader{}
+	if _, err := bundleCodec.Unmarshal(headerBytes, header); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal bundle header: %w", err)
+	}
+	if err := verify(header); err != nil {
+		return nil, err
+	}
+
+	prefixes := header.prefixes()
+	if err := readBundleRecords(cr, br, checksum.Sum, prefixes, db); err != nil {
+		if cleanupErr := deletePrefixes(db, prefixes); cleanupErr != nil {
+			return nil, fmt.Errorf("%w. Also couldn't remove the keys written: %s", err, cleanupErr)
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
+// readBundleRecords writes the key/value pairs read from [cr] to [db] and then
+// checks the checksum read from [r] against [sum]
+func readBundleRecords(cr, r io.Reader, sum func([]byte) []byte, prefixes [][]byte, db database.Database) error {
+	batch := db.NewBatch()
+	marker := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(cr, marker); err != nil {
+			return err
+		}
+		if marker[0] == bundleEndMarker {
+			break
+		}
+		if marker[0] != bundleRecordMarker {
+			return errInvalidBundleMarker
+		}
+
+		key, err := readBundleField(cr)
+		if err != nil {
+			return err
+		}
+		if !hasAnyPrefix(key, prefixes) {
+			return errUnexpectedBundleKey
+		}
+		value, err := readBundleField(cr)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		if batch.Size() < bundleBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	expectedChecksum := sum(nil)
+	bundleChecksum := make([]byte, len(expectedChecksum))
+	if _, err := io.ReadFull(r, bundleChecksum); err != nil {
+		return fmt.Errorf("couldn't read bundle checksum: %w", err)
+	}
+	if !bytes.Equal(expectedChecksum, bundleChecksum) {
+		return errBundleChecksum
+	}
+	return nil
+}
+
+// deletePrefixes removes all the keys of [db] under one of [prefixes]
+func deletePrefixes(db database.Database, prefixes [][]byte) error {
+	for _, prefix := range prefixes {
+		batch := db.NewBatch()
+		it := db.NewIteratorWithPrefix(prefix)
+		for it.Next() {
+			if err := batch.Delete(it.Key()); err != nil {
+				it.Release()
+				return err
+			}
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasPrefixedKeys returns true if [db] has a key under one of [prefixes]
+func hasPrefixedKeys(db database.Iteratee, prefixes [][]byte) (bool, error) {
+	for _, prefix := range prefixes {
+		it := db.NewIteratorWithPrefix(prefix)
+		hasNext := it.Next()
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return false, err
+		}
+		if hasNext {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func hasAnyPrefix(key []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeBundleField(w io.Writer, field []byte) error {
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(field)))
+	if _, err := w.Write(size); err != nil {
+		return err
+	}
+	_, err := w.Write(field)
+	return err
+}
+
+func readBundleField(r io.Reader) ([]byte, error) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, err
+	}
+	fieldSize := binary.BigEndian.Uint32(size)
+	if fieldSize > maxBundleFieldSize {
+		return nil, errBundleFieldTooLarge
+	}
+	field := make([]byte, fieldSize)
+	_, err := io.ReadFull(r, field)
+	return field, err
+}
@@ -0,0 +1,221 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// newBundleTestDB returns a database with the keys of one chain and of
+// another chain, and the header of a bundle of the first chain
+func newBundleTestDB(t *testing.T) (database.Database, *BundleHeader) {
+	db := memdb.New()
+
+	chainID := ids.GenerateTestID()
+	prefixes := newPrefixTracker()
+	chainDB := prefixdb.NewTracked(chainID[:], db, prefixes.add)
+	vmDB := prefixdb.New([]byte("vm"), chainDB)
+	otherChainID := ids.GenerateTestID()
+	otherChainDB := prefixdb.New(otherChainID[:], db)
+
+	assert.NoError(t, chainDB.Put([]byte{1}, []byte{2}))
+	assert.NoError(t, vmDB.Put([]byte{3}, []byte{4}))
+	assert.NoError(t, otherChainDB.Put([]byte{5}, []byte{6}))
+
+	return db, &BundleHeader{
+		DatabaseVersion: "v1.4.5",
+		ChainID:         chainID,
+		SubnetID:        ids.GenerateTestID(),
+		VMID:            ids.GenerateTestID(),
+		FxIDs:           []ids.ID{ids.GenerateTestID()},
+		GenesisData:     []byte("genesis"),
+		Aliases:         []string{"mychain"},
+		Config:          []byte("config"),
+		Upgrade:         []byte("upgrade"),
+		PrefixPaths:     prefixes.list(),
+	}
+}
+
+func countKeys(t *testing.T, db database.Iteratee) int {
+	it := db.NewIterator()
+	defer it.Release()
+
+	numKeys := 0
+	for it.Next() {
+		numKeys++
+	}
+	assert.NoError(t, it.Error())
+	return numKeys
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	assert.Len(header.PrefixPaths, 2)
+
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, header, db, &sync.Mutex{}))
+
+	importDB := memdb.New()
+	importedHeader, err := readBundle(bundle, importDB, func(*BundleHeader) error { return nil })
+	assert.NoError(err)
+	assert.Equal(header, importedHeader)
+
+	// Only the keys of the bundled chain should have been imported
+	assert.Equal(2, countKeys(t, importDB))
+	hasData, err := hasPrefixedKeys(importDB, header.prefixes())
+	assert.NoError(err)
+	assert.True(hasData)
+
+	chainID := header.ChainID
+	chainDB := prefixdb.New(chainID[:], importDB)
+	value, err := chainDB.Get([]byte{1})
+	assert.NoError(err)
+	assert.Equal([]byte{2}, value)
+	value, err = prefixdb.New([]byte("vm"), chainDB).Get([]byte{3})
+	assert.NoError(err)
+	assert.Equal([]byte{4}, value)
+}
+
+func TestBundleVerifyFails(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, header, db, &sync.Mutex{}))
+
+	errVerify := errors.New("verify failed")
+	importDB := memdb.New()
+	_, err := readBundle(bundle, importDB, func(*BundleHeader) error { return errVerify })
+	assert.Equal(errVerify, err)
+	assert.Zero(countKeys(t, importDB))
+}
+
+func TestBundleCorrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, header, db, &sync.Mutex{}))
+
+	// Flip a bit of the last value in the bundle
+	bundleBytes := bundle.Bytes()
+	bundleBytes[len(bundleBytes)-34] ^= 1
+
+	importDB := memdb.New()
+	_, err := readBundle(bytes.NewReader(bundleBytes), importDB, func(*BundleHeader) error { return nil })
+	assert.Equal(errBundleChecksum, err)
+	// The keys written before the corruption was found should be removed
+	assert.Zero(countKeys(t, importDB))
+}
+
+func TestBundleTruncated(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, header, db, &sync.Mutex{}))
+
+	bundleBytes := bundle.Bytes()
+	importDB := memdb.New()
+	_, err := readBundle(bytes.NewReader(bundleBytes[:len(bundleBytes)-10]), importDB, func(*BundleHeader) error { return nil })
+	assert.Error(err)
+	assert.Zero(countKeys(t, importDB))
+}
+
+func TestBundleUnexpectedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	writtenHeader := *header
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, &writtenHeader, db, &sync.Mutex{}))
+
+	// Claim that only the first prefix is the chain's
+	bundleBytes := bundle.Bytes()
+	header.PrefixPaths = header.PrefixPaths[:1]
+	headerBytes, err := bundleCodec.Marshal(bundleCodecVersion, header)
+	assert.NoError(err)
+	writtenHeaderBytes, err := bundleCodec.Marshal(bundleCodecVersion, &writtenHeader)
+	assert.NoError(err)
+
+	tampered := &bytes.Buffer{}
+	assert.NoError(writeBundleField(tampered, headerBytes))
+	tampered.Write(bundleBytes[4+len(writtenHeaderBytes):])
+
+	importDB := memdb.New()
+	_, err = readBundle(tampered, importDB, func(*BundleHeader) error { return nil })
+	assert.Equal(errUnexpectedBundleKey, err)
+	assert.Zero(countKeys(t, importDB))
+}
+
+func TestBundleOtherChain(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, header, db, &sync.Mutex{}))
+	bundleBytes := bundle.Bytes()
+	headerBytes, err := bundleCodec.Marshal(bundleCodecVersion, header)
+	assert.NoError(err)
+
+	// Claim that the keys belong to another chain. Its prefixes are derived
+	// from its ID, so the keys aren't under them.
+	header.ChainID = ids.GenerateTestID()
+	tamperedHeaderBytes, err := bundleCodec.Marshal(bundleCodecVersion, header)
+	assert.NoError(err)
+	tampered := &bytes.Buffer{}
+	assert.NoError(writeBundleField(tampered, tamperedHeaderBytes))
+	tampered.Write(bundleBytes[4+len(headerBytes):])
+
+	importDB := memdb.New()
+	_, err = readBundle(tampered, importDB, func(*BundleHeader) error { return nil })
+	assert.Equal(errUnexpectedBundleKey, err)
+	assert.Zero(countKeys(t, importDB))
+}
+
+// countingLocker counts the number of times it's locked
+type countingLocker struct {
+	sync.Mutex
+	locks int
+}
+
+func (l *countingLocker) Lock() {
+	l.Mutex.Lock()
+	l.locks++
+}
+
+func TestBundleLocksOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	db, header := newBundleTestDB(t)
+	chainDB := prefixdb.New(header.ChainID[:], db)
+	value := make([]byte, bundleBatchSize/2)
+	for i := byte(0); i < 4; i++ {
+		assert.NoError(chainDB.Put([]byte{10 + i}, value))
+	}
+
+	lock := &countingLocker{}
+	bundle := &bytes.Buffer{}
+	assert.NoError(writeBundle(bundle, header, db, lock))
+	// The lock is held while the whole database is read, so that the bundle
+	// is consistent
+	assert.Equal(1, lock.locks)
+
+	importDB := memdb.New()
+	_, err := readBundle(bundle, importDB, func(*BundleHeader) error { return nil })
+	assert.NoError(err)
+	assert.Equal(6, countKeys(t, importDB))
+}
@@ -0,0 +1,459 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Errors of a chain are counted over this window
+	errorBudgetWindow = time.Hour
+	// Number of messages most recently logged by a chain that are included in
+	// an incident snapshot
+	incidentLogSize = 512
+	// Max length of a message included in an incident snapshot
+	incidentLogEntrySize = 4 * units.KiB
+	// Max size of the goroutine dump included in an incident snapshot
+	incidentGoroutinesSize = 16 * units.MiB
+	// Max number of incident snapshots of a chain that are kept. The oldest
+	// snapshots are removed first.
+	maxIncidents = 8
+	// Max time to wait for the context lock of a chain when summarizing its
+	// consensus state
+	incidentLockTimeout = 5 * time.Second
+)
+
+var (
+	errChainDegraded       = errors.New("chain exceeded its error budget")
+	errIncidentLockTimeout = errors.New("timed out waiting for the context lock")
+
+	_ logging.Logger = &budgetedLog{}
+)
+
+// incidentSummary is the summary of the state of a chain in an incident
+// snapshot
+type incidentSummary struct {
+	ChainID        ids.ID      `json:"chainID"`
+	Alias          string      `json:"alias"`
+	Reason         string      `json:"reason"`
+	Time           time.Time   `json:"time"`
+	ErrorsInWindow int         `json:"errorsInWindow"`
+	ErrorBudget    int         `json:"errorBudget"`
+	Bootstrapped   bool        `json:"bootstrapped"`
+	Health         interface{} `json:"health,omitempty"`
+	HealthError    string      `json:"healthError,omitempty"`
+}
+
+// errorBudget marks a chain as degraded, and captures an incident snapshot,
+// when the chain hits more than [budget] errors within [errorBudgetWindow].
+// Only errors of the chain's VM or consensus engine count. That is, the
+// messages logged as errors by the chain. Errors returned by the consensus
+// engine, which shut the chain down, and panics exceed the budget immediately.
+//
+// An incident snapshot is a directory in [dir] that contains a goroutine dump,
+// the messages the chain logged recently and a summary of the chain's state.
+// Only the [maxIncidents] most recent snapshots of a chain are kept.
+type errorBudget struct {
+	log        logging.Logger
+	dir        string
+	chainID    ids.ID
+	chainAlias string
+	budget     int
+	clock      timer.Clock
+
+	lock sync.Mutex
+	// Times that the chain hit an error within [errorBudgetWindow], oldest
+	// first
+	errors []time.Time
+	// Ring buffer of the messages most recently logged
+	recentLogs []string
+	nextLog    int
+	degraded   bool
+	// True if the chain failed in a way it can't recover from
+	fatal bool
+	// Directory of the last incident snapshot. Empty if none was captured.
+	lastIncident string
+
+	// Set once the chain is created. May be nil.
+	ctx    *snow.Context
+	engine common.Engine
+}
+
+// newErrorBudget returns the error budget of chain [chainID]. Incidents are
+// reported to [log].
+func newErrorBudget(log logging.Logger, dir string, chainID ids.ID, chainAlias string, budget int) *errorBudget {
+	return &errorBudget{
+		log:        log,
+		dir:        dir,
+		chainID:    chainID,
+		chainAlias: chainAlias,
+		budget:     budget,
+		recentLogs: make([]string, 0, incidentLogSize),
+	}
+}
+
+// setChain sets the chain whose consensus state is summarized in incident
+// snapshots
+func (b *errorBudget) setChain(ctx *snow.Context, engine common.Engine) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.ctx = ctx
+	b.engine = engine
+}
+
+// wrap [log] so that the messages it logs are included in incident snapshots,
+// the errors it logs count towards this budget, and the fatal errors and panics
+// it reports exceed this budget
+func (b *errorBudget) wrap(log logging.Logger) logging.Logger {
+	return &budgetedLog{
+		Logger: log,
+		budget: b,
+	}
+}
+
+// record that [msg] was logged at [level]
+func (b *errorBudget) record(level logging.Level, msg string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(msg) > incidentLogEntrySize {
+		msg = msg[:incidentLogEntrySize]
+	}
+	entry := fmt.Sprintf("%s[%s] %s", level, b.clock.Time().Format(time.RFC3339Nano), msg)
+	if len(b.recentLogs) < incidentLogSize {
+		b.recentLogs = append(b.recentLogs, entry)
+	} else {
+		b.recentLogs[b.nextLog] = entry
+	}
+	b.nextLog = (b.nextLog + 1) % incidentLogSize
+}
+
+// fail counts the error [msg], logged by the chain, towards the budget
+func (b *errorBudget) fail(msg string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.clock.Time()
+	b.errors = append(b.errors, now)
+	b.expire(now)
+	if b.degraded || len(b.errors) <= b.budget {
+		return
+	}
+
+	b.degraded = true
+	reason := fmt.Sprintf("hit %d errors within %s. Last error: %s", len(b.errors), errorBudgetWindow, msg)
+	// The chain may log errors while holding the context lock, so the snapshot
+	// is captured asynchronously
+	go b.capture(reason)
+}
+
+// failFatal exceeds the budget because the chain failed in a way it can't
+// recover from
+func (b *errorBudget) failFatal(reason string) {
+	b.lock.Lock()
+	alreadyDegraded := b.degraded
+	b.degraded = true
+	b.fatal = true
+	b.lock.Unlock()
+
+	if !alreadyDegraded {
+		// The chain may fail while holding the context lock, so the snapshot is
+		// captured asynchronously
+		go b.capture(reason)
+	}
+}
+
+// recoverPanic captures an incident snapshot if the caller is panicking, then
+// continues panicking. Must be deferred.
+func (b *errorBudget) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	b.lock.Lock()
+	b.degraded = true
+	b.fatal = true
+	b.lock.Unlock()
+
+	// The node is about to exit, so the snapshot is captured synchronously
+	b.capture(fmt.Sprintf("panicked: %v", r))
+	panic(r)
+}
+
+// capture an incident snapshot and report it
+func (b *errorBudget) capture(reason string) {
+	b.log.Error("chain %s is degraded because it %s. Capturing an incident snapshot", b.chainAlias, reason)
+
+	dir, err := b.snapshot(reason)
+	if err != nil {
+		b.log.Error("couldn't capture an incident snapshot of chain %s: %s", b.chainAlias, err)
+		return
+	}
+
+	b.lock.Lock()
+	b.lastIncident = dir
+	b.lock.Unlock()
+
+	b.log.Info("wrote incident snapshot of chain %s to %s", b.chainAlias, dir)
+
+	if err := b.removeOldIncidents(); err != nil {
+		b.log.Warn("couldn't remove old incident snapshots of chain %s: %s", b.chainAlias, err)
+	}
+}
+
+// snapshot writes an incident snapshot and returns its directory
+func (b *errorBudget) snapshot(reason string) (string, error) {
+	now := b.clock.Time()
+
+	b.lock.Lock()
+	summary := incidentSummary{
+		ChainID:        b.chainID,
+		Alias:          b.chainAlias,
+		Reason:         reason,
+		Time:           now,
+		ErrorsInWindow: len(b.errors),
+		ErrorBudget:    b.budget,
+	}
+	logs := make([]string, 0, len(b.recentLogs))
+	if len(b.recentLogs) == incidentLogSize {
+		logs = append(logs, b.recentLogs[b.nextLog:]...)
+		logs = append(logs, b.recentLogs[:b.nextLog]...)
+	} else {
+		logs = append(logs, b.recentLogs...)
+	}
+	ctx := b.ctx
+	engine := b.engine
+	b.lock.Unlock()
+
+	if ctx != nil && engine != nil {
+		summary.Bootstrapped = ctx.IsBootstrapped()
+		health, err := consensusHealth(ctx, engine)
+		summary.Health = health
+		if err != nil {
+			summary.HealthError = err.Error()
+		}
+	}
+
+	dir := filepath.Join(b.dir, fmt.Sprintf("%s-%d", b.chainAlias, now.UnixNano()))
+	if err := os.MkdirAll(dir, perms.ReadWriteExecute); err != nil {
+		return "", err
+	}
+
+	summaryBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := perms.WriteFile(filepath.Join(dir, "summary.json"), summaryBytes, perms.ReadWrite); err != nil {
+		return "", err
+	}
+	if err := perms.WriteFile(filepath.Join(dir, "logs.txt"), []byte(strings.Join(logs, "\n")), perms.ReadWrite); err != nil {
+		return "", err
+	}
+
+	goroutines, err := perms.Create(filepath.Join(dir, "goroutines.txt"), perms.ReadWrite)
+	if err != nil {
+		return "", err
+	}
+	// A node may run many goroutines, so the dump is truncated
+	if err := pprof.Lookup("goroutine").WriteTo(&limitedWriter{w: goroutines, n: incidentGoroutinesSize}, 2); err != nil {
+		_ = goroutines.Close()
+		return "", err
+	}
+	return dir, goroutines.Close()
+}
+
+// removeOldIncidents removes all but the [maxIncidents] most recent incident
+// snapshots of this chain
+func (b *errorBudget) removeOldIncidents() error {
+	files, err := ioutil.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+
+	prefix := b.chainAlias + "-"
+	type incident struct {
+		name string
+		time int64
+	}
+	incidents := []incident(nil)
+	for _, file := range files {
+		name := file.Name()
+		if !file.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		// Skip the directories of other chains whose alias starts with this
+		// chain's alias
+		timestamp, err := strconv.ParseInt(strings.TrimPrefix(name, prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		incidents = append(incidents, incident{
+			name: name,
+			time: timestamp,
+		})
+	}
+	if len(incidents) <= maxIncidents {
+		return nil
+	}
+
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].time < incidents[j].time })
+	errs := wrappers.Errs{}
+	for _, incident := range incidents[:len(incidents)-maxIncidents] {
+		errs.Add(os.RemoveAll(filepath.Join(b.dir, incident.name)))
+	}
+	return errs.Err
+}
+
+// healthCheck fails while the chain is degraded
+func (b *errorBudget) healthCheck() (interface{}, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.expire(b.clock.Time())
+	if b.degraded && !b.fatal && len(b.errors) <= b.budget {
+		b.degraded = false
+		b.log.Info("chain %s is no longer degraded", b.chainAlias)
+	}
+
+	details := map[string]interface{}{
+		"errorsInWindow": len(b.errors),
+		"errorBudget":    b.budget,
+	}
+	if b.lastIncident != "" {
+		details["lastIncident"] = b.lastIncident
+	}
+	if b.degraded {
+		return details, errChainDegraded
+	}
+	return details, nil
+}
+
+// expire the errors before the window ending at [now]
+// Assumes [b.lock] is held
+func (b *errorBudget) expire(now time.Time) {
+	start := now.Add(-errorBudgetWindow)
+	i := 0
+	for i < len(b.errors) && !b.errors[i].After(start) {
+		i++
+	}
+	b.errors = b.errors[i:]
+}
+
+// consensusHealth returns the result of the health check of [engine]. Gives up
+// if the context lock can't be grabbed within [incidentLockTimeout].
+func consensusHealth(ctx *snow.Context, engine common.Engine) (interface{}, error) {
+	type result struct {
+		details interface{}
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		details, err := engine.HealthCheck()
+		done <- result{
+			details: details,
+			err:     err,
+		}
+	}()
+
+	select {
+	case result := <-done:
+		return result.details, result.err
+	case <-time.After(incidentLockTimeout):
+		return nil, errIncidentLockTimeout
+	}
+}
+
+// limitedWriter writes at most [n] bytes to [w]. Bytes past the limit are
+// dropped.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	if len(p) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.w.Write(p)
+	l.n -= n
+	if err != nil {
+		return n, err
+	}
+	return written, nil
+}
+
+// budgetedLog records the messages it logs in an errorBudget. The errors it
+// logs count towards the budget. The fatal errors it logs, which are only
+// logged when the consensus engine or VM fails and the chain shuts down, exceed
+// the budget.
+type budgetedLog struct {
+	logging.Logger
+	budget *errorBudget
+}
+
+func (l *budgetedLog) Fatal(format string, args ...interface{}) {
+	l.Logger.Fatal(format, args...)
+	msg := fmt.Sprintf(format, args...)
+	l.budget.record(logging.Fatal, msg)
+	l.budget.failFatal(fmt.Sprintf("failed: %s", msg))
+}
+
+func (l *budgetedLog) Error(format string, args ...interface{}) {
+	l.Logger.Error(format, args...)
+	msg := fmt.Sprintf(format, args...)
+	l.budget.record(logging.Error, msg)
+	l.budget.fail(msg)
+}
+
+func (l *budgetedLog) Warn(format string, args ...interface{}) {
+	l.Logger.Warn(format, args...)
+	l.budget.record(logging.Warn, fmt.Sprintf(format, args...))
+}
+
+func (l *budgetedLog) Info(format string, args ...interface{}) {
+	l.Logger.Info(format, args...)
+	l.budget.record(logging.Info, fmt.Sprintf(format, args...))
+}
+
+func (l *budgetedLog) RecoverAndPanic(f func()) {
+	l.Logger.RecoverAndPanic(func() {
+		defer l.budget.recoverPanic()
+		f()
+	})
+}
+
+func (l *budgetedLog) RecoverAndExit(f, exit func()) {
+	l.Logger.RecoverAndExit(func() {
+		defer l.budget.recoverPanic()
+		f()
+	}, exit)
+}
@@ -0,0 +1,138 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+func TestErrorBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	chainID := ids.GenerateTestID()
+	budget := newErrorBudget(logging.NoLog{}, dir, chainID, "X", 2)
+	now := time.Unix(1600000000, 0)
+	budget.clock.Set(now)
+	log := budget.wrap(logging.NoLog{})
+
+	// Messages logged below the error level don't count towards the budget
+	log.Info("starting")
+	log.Warn("warning")
+	log.Error("first error")
+	log.Error("second error")
+	_, err := budget.healthCheck()
+	assert.NoError(err)
+
+	// Errors that are older than the window don't count
+	budget.clock.Set(now.Add(errorBudgetWindow))
+	log.Error("third error")
+	_, err = budget.healthCheck()
+	assert.NoError(err)
+
+	log.Error("fourth error")
+	log.Error("fifth error")
+	details, err := budget.healthCheck()
+	assert.Equal(errChainDegraded, err)
+	assert.Equal(3, details.(map[string]interface{})["errorsInWindow"])
+
+	incident := waitForIncident(budget)
+	assert.Equal(dir, filepath.Dir(incident))
+
+	summaryBytes, err := ioutil.ReadFile(filepath.Join(incident, "summary.json"))
+	assert.NoError(err)
+	summary := incidentSummary{}
+	assert.NoError(json.Unmarshal(summaryBytes, &summary))
+	assert.Equal(chainID, summary.ChainID)
+	assert.Equal(3, summary.ErrorsInWindow)
+	assert.Contains(summary.Reason, "fifth error")
+
+	logs, err := ioutil.ReadFile(filepath.Join(incident, "logs.txt"))
+	assert.NoError(err)
+	lines := strings.Split(string(logs), "\n")
+	assert.Len(lines, 7)
+	assert.Contains(lines[0], "starting")
+	assert.Contains(lines[6], "fifth error")
+
+	goroutines, err := ioutil.ReadFile(filepath.Join(incident, "goroutines.txt"))
+	assert.NoError(err)
+	assert.Contains(string(goroutines), "goroutine")
+
+	// The chain recovers once the failures leave the window
+	budget.clock.Set(now.Add(3 * errorBudgetWindow))
+	details, err = budget.healthCheck()
+	assert.NoError(err)
+	assert.Equal(incident, details.(map[string]interface{})["lastIncident"])
+}
+
+func TestErrorBudgetFatal(t *testing.T) {
+	assert := assert.New(t)
+
+	budget := newErrorBudget(logging.NoLog{}, t.TempDir(), ids.GenerateTestID(), "X", 2)
+	log := budget.wrap(logging.NoLog{})
+
+	log.Fatal("chain shutting down")
+	_, err := budget.healthCheck()
+	assert.Equal(errChainDegraded, err)
+
+	// A chain that failed fatally doesn't recover
+	waitForIncident(budget)
+	budget.clock.Set(time.Now().Add(3 * errorBudgetWindow))
+	_, err = budget.healthCheck()
+	assert.Equal(errChainDegraded, err)
+}
+
+func TestRemoveOldIncidents(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	budget := newErrorBudget(logging.NoLog{}, dir, ids.GenerateTestID(), "X", 0)
+	for i := 0; i < maxIncidents+2; i++ {
+		assert.NoError(os.Mkdir(filepath.Join(dir, fmt.Sprintf("X-%d", 10+i)), perms.ReadWriteExecute))
+	}
+	// Snapshots of other chains are kept
+	assert.NoError(os.Mkdir(filepath.Join(dir, "X-Y-1"), perms.ReadWriteExecute))
+	assert.NoError(os.Mkdir(filepath.Join(dir, "Y-1"), perms.ReadWriteExecute))
+
+	assert.NoError(budget.removeOldIncidents())
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(err)
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name()
+	}
+	assert.Len(names, maxIncidents+2)
+	assert.NotContains(names, "X-10")
+	assert.NotContains(names, "X-11")
+	assert.Contains(names, "X-12")
+	assert.Contains(names, "X-Y-1")
+	assert.Contains(names, "Y-1")
+}
+
+// waitForIncident waits until [budget] has captured a snapshot, which is
+// captured asynchronously, and returns its directory
+func waitForIncident(budget *errorBudget) string {
+	incident := ""
+	for i := 0; i < 100 && incident == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+		budget.lock.Lock()
+		incident = budget.lastIncident
+		budget.lock.Unlock()
+	}
+	return incident
+}
@@ -6,6 +6,9 @@ package chains
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -13,11 +16,14 @@ import (
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/migration"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -30,6 +36,9 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/workers"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/metervm"
 
@@ -46,11 +55,37 @@ import (
 
 const (
 	defaultChannelSize = 1024
+
+	// Max number of recently rejected containers of a chain that are dropped
+	// when they're received again
+	rejectedCacheSize = 16384
+	rejectedCacheName = "rejectedCache"
+
+	heightIndexName = "heightIndex"
 )
 
 var (
 	BootstrappedKey         = []byte{0x00}
 	_               Manager = &manager{}
+
+	importedChainsPrefix = []byte("imported_chains")
+	aliasesPrefix        = []byte("aliases")
+	rejectedCachePrefix  = []byte("rejected")
+	heightIndexPrefix    = []byte("height")
+
+	errUnknownChain    = errors.New("unknown chain ID")
+	errNoPollReporting = errors.New("chain's engine doesn't report its polls")
+	errNoFrontier      = errors.New("chain's engine doesn't report its frontier")
+	errNoBlocked       = errors.New("chain's engine doesn't report its blocked containers")
+	errNoEvidence      = errors.New("chain's engine doesn't record evidence of misbehavior")
+	errNoTxFinality    = errors.New("chain's engine doesn't estimate transaction finality")
+	errNoHeightIndex   = errors.New("chain's engine doesn't index blocks by height")
+	errNoParamUpdates  = errors.New("chain's engine doesn't support updating its parameters")
+	errTxNotProcessing = errors.New("transaction isn't processing")
+	errChainRunning    = errors.New("chain is running")
+	errChainImporting  = errors.New("chain is already being imported")
+	errChainDataExists = errors.New("chain already has data in the database")
+	errUnlimitedVM     = errors.New("resource limits are only supported for VMs that run as plugins")
 )
 
 // Manager manages the chains running on this node.
@@ -86,12 +121,79 @@ type Manager interface {
 	// Add an alias to a chain
 	Alias(ids.ID, string) error
 
+	// Add an alias to a chain on behalf of a component, which is reported if
+	// the alias collides with another alias
+	RegisterAlias(chainID ids.ID, alias, registrant string) error
+
+	// Add an alias to a chain that is given again after a restart
+	PersistAlias(chainID ids.ID, alias, registrant string) error
+
+	// Remove an alias added with PersistAlias, so that it isn't given after a
+	// restart
+	RemovePersistedAlias(alias string) error
+
+	// Give chains the aliases added with PersistAlias before the node
+	// restarted. Should be called after the chains' static aliases are added.
+	LoadAliases() error
+
 	// Returns the ID of the subnet that is validating the provided chain
 	SubnetID(chainID ids.ID) (ids.ID, error)
 
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the polls of the network that the chain with the given ID is
+	// waiting on
+	OutstandingPolls(ids.ID) ([]common.PollInfo, error)
+
+	// Returns the containers that the chain with the given ID received but
+	// can't issue to consensus until their missing dependencies are
+	BlockedContainers(ids.ID) ([]common.BlockedInfo, error)
+
+	// Returns the evidence of peers misbehaving that the chain with the given
+	// ID recorded, ordered from oldest to newest
+	Evidence(ids.ID) ([]common.Evidence, error)
+
+	// Returns the last accepted containers of the chain with the given ID and
+	// the height of the highest of them
+	Frontier(ids.ID) ([]ids.ID, uint64, error)
+
+	// Returns an estimate of how close the processing transaction is to being
+	// accepted by the chain with the given ID
+	TxFinality(chainID ids.ID, txID ids.ID) (common.TxFinality, error)
+
+	// Returns the ID of the block accepted at the given height by the linear
+	// chain with the given ID
+	GetBlockIDAtHeight(chainID ids.ID, height uint64) (ids.ID, error)
+
+	// Replaces the sampling parameters of the running chain with the given ID.
+	// Zero parameters keep their current values. Returns the parameters the
+	// chain uses after the update.
+	UpdateSamplingParameters(chainID ids.ID, update common.SamplingParameters) (common.SamplingParameters, error)
+
+	// Writes the chain with the given ID, including its database, aliases and
+	// config, to the writer as a bundle. The chain doesn't process messages
+	// until the bundle is written, so that the bundle is consistent.
+	ExportChain(chainID ids.ID, w io.Writer) (*BundleHeader, error)
+
+	// Reads a bundle written by ExportChain into this node's database. The
+	// bundled chain must not be running nor have data in the database. Its
+	// aliases and config are applied when the chain is created.
+	ImportChain(r io.Reader) (*BundleHeader, error)
+
+	// Returns the subnets whose chains this node runs
+	WhitelistedSubnets() ids.Set
+
+	// Replaces the subnets whose chains this node runs. The chains of the
+	// subnets removed from the whitelist are shut down, and can only run
+	// again after the node restarts. The chains of the subnets added to the
+	// whitelist are created. The primary network is always whitelisted.
+	SetWhitelistedSubnets(ids.Set) error
+
+	// Sets the tracker told when subnets are added to or removed from the
+	// whitelist
+	SetSubnetTracker(SubnetTracker)
+
 	Shutdown()
 }
 
@@ -107,20 +209,42 @@ type ChainParameters struct {
 }
 
 type chain struct {
-	Name    string
-	Engine  common.Engine
-	Handler *router.Handler
-	Ctx     *snow.Context
-	VM      interface{}
-	Beacons validators.Set
+	Name     string
+	Engine   common.Engine
+	Handler  *router.Handler
+	Ctx      *snow.Context
+	VM       interface{}
+	Beacons  validators.Set
+	Prefixes *prefixTracker
+	// Migrates the VM's database. Nil if the VM's database isn't versioned.
+	Migrator *migration.Migrator
+}
+
+// exportableChain is what's needed to export a running chain
+type exportableChain struct {
+	params   ChainParameters
+	prefixes *prefixTracker
+}
+
+// importedChain is what's applied to a chain imported from a bundle when it's
+// created
+type importedChain struct {
+	Aliases []string `serialize:"true"`
+	Config  []byte   `serialize:"true"`
+	Upgrade []byte   `serialize:"true"`
 }
 
 // ChainConfig is configuration settings for the current execution.
 // [Config] is the user-provided config blob for the chain.
 // [Upgrade] is a chain-specific blob for coordinating upgrades.
+// [ResourceLimits] bound the resources of the chain's VM, if it runs as a
+// plugin.
+// [Budget] bounds the load the chain's message handler puts on the node.
 type ChainConfig struct {
-	Config  []byte
-	Upgrade []byte
+	Config         []byte
+	Upgrade        []byte
+	ResourceLimits vms.ResourceLimits
+	Budget         router.Budget
 }
 
 // ManagerConfig ...
@@ -132,9 +256,25 @@ type ManagerConfig struct {
 	DecisionEvents            *triggers.EventDispatcher
 	ConsensusEvents           *triggers.EventDispatcher
 	DBManager                 dbManager.Manager
-	Router                    router.Router    // Routes incoming messages to the appropriate chain
-	Net                       network.Network  // Sends consensus messages to other validators
-	ConsensusParams           avcon.Parameters // The consensus parameters (alpha, beta, etc.) for new chains
+	Router                    router.Router       // Routes incoming messages to the appropriate chain
+	Net                       network.Network     // Sends consensus messages to other validators
+	NetworkClock              *timer.NetworkClock // Estimates the network time from the times reported by peers
+	CryptoPool                *workers.Pool       // Runs the cryptographic operations of the chains
+	ConsensusParams           avcon.Parameters    // The consensus parameters (alpha, beta, etc.) for new chains
+	InputConflictGraphChains  []string            // IDs or aliases of DAG based chains that track conflicts per input
+	ReplayLogDir              string              // If non-empty, DAG based chains record their conflict graphs to replay logs in this directory
+	RepollLatencyBias         float64             // If positive, repolls favor validators that respond quickly
+	LightVerification         bool                // If true, DAG based chains observe the votes of validators while this node isn't one
+	VirtuousRepoll            bool                // If true, DAG based chains repoll immediately when the virtuous frontier changes
+	MaxOrphans                int                 // Max number of abandoned vertices DAG based chains keep to issue again
+	OrphanExpiry              time.Duration       // Time DAG based chains keep an abandoned vertex
+	VertexLimits              vertex.Limits       // Limits on the vertices DAG based chains build
+	StallThreshold            time.Duration       // If positive, DAG based chains try to recover after not deciding a vertex for this long
+	StateSync                 bool                // If true, new DAG based chains sync their state from a beacon
+	TxVerifiers               int                 // If greater than 1, DAG based chains verify the txs of a vertex concurrently
+	FrontierSyncFrequency     time.Duration       // If positive, how often DAG based chains fetch the vertices they're missing from a validator's accepted frontier
+	SkipBenched               bool                // If true, DAG based chains replace the benched validators sampled for a poll
+	HeightIndex               bool                // If true, linear chains index their accepted blocks by height
 	EpochFirstTransition      time.Time
 	EpochDuration             time.Duration
 	Validators                validators.Manager // Validators validating on this chain
@@ -147,6 +287,8 @@ type ManagerConfig struct {
 	XChainID                  ids.ID
 	CriticalChains            ids.Set          // Chains that can't exit gracefully
 	WhitelistedSubnets        ids.Set          // Subnets to validate
+	WhitelistedChains         ids.Set          // If non-empty, the only chains of non-primary subnets to run
+	BlacklistedChains         ids.Set          // Chains of non-primary subnets not to run
 	TimeoutManager            *timeout.Manager // Manages request timeouts when sending messages to other validators
 	HealthService             health.Service
 	RetryBootstrap            bool                   // Should Bootstrap be retried
@@ -169,6 +311,20 @@ type ManagerConfig struct {
 	// This node will only consider the first [MultiputMaxContainersReceived]
 	// containers in a multiput it receives.
 	BootstrapMultiputMaxContainersReceived int
+	// Max number of GetAncestors requests a DAG sends concurrently while
+	// bootstrapping
+	BootstrapMaxOutstandingGetAncestors int
+	// If positive, a chain that hasn't accepted a container for this long
+	// while beacons report higher accepted containers is bootstrapped again
+	BootstrapStaleChainThreshold time.Duration
+	// If non-empty, containers fetched during bootstrapping are stored in
+	// memory-mapped files in this directory rather than in the database
+	BootstrapContainerDir string
+	// If positive, a chain whose VM or consensus engine logs more than this
+	// many errors within an hour, or fails fatally, is marked degraded and an
+	// incident snapshot of it is written to [ChainIncidentDir]
+	ChainErrorBudget int
+	ChainIncidentDir string
 }
 
 type manager struct {
@@ -191,15 +347,51 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]*router.Handler
+	// Key: Chain's ID
+	// Value: What's needed to export the chain
+	exportable map[ids.ID]exportableChain
+	// Key: Chain's ID
+	// Value: The migrator of the chain's VM database, if it's versioned
+	migrators map[ids.ID]*migration.Migrator
+	// Chains that are being created
+	creating ids.Set
+	// Key: ID of a chain being imported
+	// Value: Closed once the import is done
+	importing map[ids.ID]chan struct{}
+
+	// Key: ID of a chain imported from a bundle
+	// Value: The chain's importedChain
+	importedChains database.Database
+	// Key: Alias given with PersistAlias
+	// Value: The alias' persistedAlias
+	persistedAliases database.Database
+
+	// Serializes updates of the whitelist
+	updateWhitelistLock sync.Mutex
+	whitelistLock       sync.RWMutex
+	// Subnets whose chains are run. Starts as [WhitelistedSubnets].
+	whitelistedSubnets ids.Set
+	// Told when subnets are added to or removed from the whitelist. May be
+	// nil.
+	subnetTracker SubnetTracker
+	// Chains stopped because their subnet was removed from the whitelist.
+	// [chainsLock] must be held while accessing it.
+	stopped ids.Set
 }
 
 // New returns a new Manager
 func New(config *ManagerConfig) Manager {
 	m := &manager{
-		ManagerConfig: *config,
-		subnets:       make(map[ids.ID]Subnet),
-		chains:        make(map[ids.ID]*router.Handler),
+		ManagerConfig:    *config,
+		subnets:          make(map[ids.ID]Subnet),
+		chains:           make(map[ids.ID]*router.Handler),
+		exportable:       make(map[ids.ID]exportableChain),
+		migrators:        make(map[ids.ID]*migration.Migrator),
+		importing:        make(map[ids.ID]chan struct{}),
+		importedChains:   prefixdb.New(importedChainsPrefix, config.DBManager.Current().Database),
+		persistedAliases: prefixdb.New(aliasesPrefix, config.DBManager.Current().Database),
 	}
+	m.whitelistedSubnets.Union(config.WhitelistedSubnets)
 	m.Initialize()
 	return m
 }
@@ -216,10 +408,37 @@ func (m *manager) CreateChain(chain ChainParameters) {
 	}
 }
 
+// bootstrapContainerPath returns the path of the file that stores the
+// containers of chain [chainID]'s [name] bootstrapping queue, creating
+// [m.BootstrapContainerDir] if needed.
+func (m *manager) bootstrapContainerPath(chainID ids.ID, name string) (string, error) {
+	if err := os.MkdirAll(m.BootstrapContainerDir, perms.ReadWriteExecute); err != nil {
+		return "", fmt.Errorf("couldn't create bootstrap container directory: %w", err)
+	}
+	return filepath.Join(m.BootstrapContainerDir, fmt.Sprintf("%s_%s", chainID, name)), nil
+}
+
+// tracksChain returns true if this node should run the chain described by
+// [chainParams]. The chains of the primary network are always run. Otherwise,
+// the chain's subnet must be whitelisted and the chain must be allowed by the
+// chain whitelist and blacklist.
+func (m *manager) tracksChain(chainParams ChainParameters) bool {
+	switch {
+	case chainParams.SubnetID == constants.PrimaryNetworkID:
+		return true
+	case !m.isWhitelisted(chainParams.SubnetID):
+		return false
+	case m.BlacklistedChains.Contains(chainParams.ID):
+		return false
+	default:
+		return m.WhitelistedChains.Len() == 0 || m.WhitelistedChains.Contains(chainParams.ID)
+	}
+}
+
 // Create a chain, this is only called from the P-chain thread, except for
 // creating the P-chain.
 func (m *manager) ForceCreateChain(chainParams ChainParameters) {
-	if chainParams.SubnetID != constants.PrimaryNetworkID && !m.WhitelistedSubnets.Contains(chainParams.SubnetID) {
+	if !m.tracksChain(chainParams) {
 		m.Log.Debug("Skipped creating non-whitelisted chain:\n"+
 			"    ID: %s\n"+
 			"    VMID:%s",
@@ -228,6 +447,13 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 		)
 		return
 	}
+	if m.isStopped(chainParams.ID) {
+		m.Log.Warn("not creating chain %s as it was stopped when its subnet was removed from the whitelist. "+
+			"It can only be created again after the node restarts.",
+			chainParams.ID,
+		)
+		return
+	}
 	// Assert that there isn't already a chain with an alias in [chain].Aliases
 	// (Recall that the string representation of a chain's ID is also an alias
 	//  for a chain)
@@ -243,6 +469,25 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 		chainParams.VMAlias,
 	)
 
+	// Don't run the chain until it's done being imported
+	m.chainsLock.Lock()
+	for {
+		imported, importing := m.importing[chainParams.ID]
+		if !importing {
+			break
+		}
+		m.chainsLock.Unlock()
+		<-imported
+		m.chainsLock.Lock()
+	}
+	m.creating.Add(chainParams.ID)
+	m.chainsLock.Unlock()
+	defer func() {
+		m.chainsLock.Lock()
+		m.creating.Remove(chainParams.ID)
+		m.chainsLock.Unlock()
+	}()
+
 	sb, exists := m.subnets[chainParams.SubnetID]
 	if !exists {
 		var onBootstrapped func()
@@ -287,10 +532,18 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	m.exportable[chainParams.ID] = exportableChain{
+		params:   chainParams,
+		prefixes: chain.Prefixes,
+	}
+	if chain.Migrator != nil {
+		m.migrators[chainParams.ID] = chain.Migrator
+	}
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
-	m.Log.AssertNoError(m.Alias(chainParams.ID, chainParams.ID.String()))
+	m.Log.AssertNoError(m.RegisterAlias(chainParams.ID, chainParams.ID.String(), "chain manager"))
+	m.aliasImportedChain(chainParams.ID)
 
 	// Notify those that registered to be notified when a new chain is created
 	m.notifyRegistrants(chain.Name, chain.Ctx, chain.Engine)
@@ -307,6 +560,52 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	// Allows messages to be routed to the new chain
 	m.ManagerConfig.Router.AddChain(chain.Handler)
+
+	if chain.Migrator != nil {
+		go chain.Ctx.Log.RecoverAndPanic(func() { m.migrate(chain) })
+	}
+}
+
+// newMigrator returns the migrator of [db], the database of the VM of chain
+// [ctx]. Returns nil if the VM's database isn't versioned.
+func (m *manager) newMigrator(ctx *snow.Context, db database.Database, vm migration.Migratable) (*migration.Migrator, error) {
+	if vm == nil {
+		return nil, nil
+	}
+	migrator, err := migration.New(db, &ctx.Lock, ctx.Log, migration.DefaultStepSize, vm.Migrations())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create the migrator of the vm's database: %w", err)
+	}
+	return migrator, nil
+}
+
+// migrate applies the pending migrations of the database of [chain]'s VM.
+// Returns once the database is at the latest schema version or the chain is
+// stopped.
+func (m *manager) migrate(chain *chain) {
+	err := chain.Migrator.Dispatch()
+	switch {
+	case err == nil:
+	case errors.Is(err, migration.ErrShutdown):
+		chain.Ctx.Log.Info("stopped migrating the vm's database at schema version %d", chain.Migrator.Version())
+	default:
+		chain.Ctx.Log.Error("failed to migrate the vm's database: %s", err)
+	}
+
+	m.chainsLock.Lock()
+	if m.migrators[chain.Ctx.ChainID] == chain.Migrator {
+		delete(m.migrators, chain.Ctx.ChainID)
+	}
+	m.chainsLock.Unlock()
+}
+
+// stopMigrator stops the migration of the database of chain [chainID]'s VM.
+// Assumes [m.chainsLock] is held.
+func (m *manager) stopMigrator(chainID ids.ID) {
+	if migrator, ok := m.migrators[chainID]; ok {
+		migrator.Shutdown()
+		delete(m.migrators, chainID)
+	}
 }
 
 // Create a chain
@@ -327,6 +626,13 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, fmt.Errorf("error while creating chain's log %w", err)
 	}
 
+	// Count the errors of the chain towards its error budget
+	var budget *errorBudget
+	if m.ChainErrorBudget > 0 {
+		budget = newErrorBudget(m.Log, m.ChainIncidentDir, chainParams.ID, primaryAlias, m.ChainErrorBudget)
+		chainLog = budget.wrap(chainLog)
+	}
+
 	ctx := &snow.Context{
 		NetworkID:            m.NetworkID,
 		SubnetID:             chainParams.SubnetID,
@@ -345,6 +651,8 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		Metrics:              m.ConsensusParams.Metrics,
 		EpochFirstTransition: m.EpochFirstTransition,
 		EpochDuration:        m.EpochDuration,
+		NetworkClock:         m.NetworkClock,
+		CryptoExecutor:       m.CryptoPool.Executor(chainParams.ID),
 	}
 
 	// Get a factory for the vm we want to use on our chain
@@ -354,7 +662,14 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	}
 
 	// Create the chain
-	vm, err := vmFactory.New(ctx)
+	var vm interface{}
+	if limits := m.getChainConfig(chainParams.ID).ResourceLimits; limits.IsZero() {
+		vm, err = vmFactory.New(ctx)
+	} else if limitedFactory, ok := vmFactory.(vms.LimitedFactory); ok {
+		vm, err = limitedFactory.NewLimited(ctx, limits)
+	} else {
+		err = errUnlimitedVM
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error while creating vm: %w", err)
 	}
@@ -448,6 +763,13 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, err
 	}
 
+	if budget != nil {
+		budget.setChain(ctx, chain.Engine)
+		if err := m.HealthService.RegisterCheck(fmt.Sprintf("%s-error-budget", chain.Name), budget.healthCheck); err != nil {
+			return nil, fmt.Errorf("couldn't add error budget health check for chain %s: %w", chain.Name, err)
+		}
+	}
+
 	return chain, nil
 }
 
@@ -463,6 +785,30 @@ func (m *manager) unblockChains() {
 	}
 }
 
+// conflictFactory returns the factory of the conflict graph that the DAG based
+// chain [chainID] should use
+func (m *manager) conflictFactory(chainID ids.ID) snowstorm.Factory {
+	for _, chain := range m.InputConflictGraphChains {
+		if id, err := m.Lookup(chain); err == nil && id == chainID {
+			return snowstorm.InputFactory{}
+		}
+		if id, err := ids.FromString(chain); err == nil && id == chainID {
+			return snowstorm.InputFactory{}
+		}
+	}
+	return snowstorm.DirectedFactory{}
+}
+
+// openReplayLog opens the replay log of the conflict graph of chain [chainID]
+// in [m.ReplayLogDir]. The log is appended to if it already exists.
+func (m *manager) openReplayLog(chainID ids.ID) (io.Writer, error) {
+	if err := os.MkdirAll(m.ReplayLogDir, perms.ReadWriteExecute); err != nil {
+		return nil, fmt.Errorf("couldn't create replay log directory: %w", err)
+	}
+	path := filepath.Join(m.ReplayLogDir, fmt.Sprintf("%s.replay", chainID))
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perms.ReadWrite)
+}
+
 // Create a DAG-based blockchain that uses Avalanche
 func (m *manager) createAvalancheChain(
 	ctx *snow.Context,
@@ -478,6 +824,8 @@ func (m *manager) createAvalancheChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
+	// Grab the migrations before the VM is wrapped
+	migratable, _ := vm.(migration.Migratable)
 	if m.MeterVMEnabled {
 		vm = metervm.NewVertexVM(vm)
 	}
@@ -485,21 +833,63 @@ func (m *manager) createAvalancheChain(
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
+	prefixes := newPrefixTracker()
+	prefixDBManager := meterDBManager.NewTrackedPrefixDBManager(ctx.ChainID[:], prefixes.add)
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
+	// The migrator must be created before the VM writes to its database
+	migrator, err := m.newMigrator(ctx, vmDBManager.Current().Database, migratable)
+	if err != nil {
+		return nil, err
+	}
+
 	db := prefixDBManager.Current()
 	vertexDB := prefixdb.New([]byte("vertex"), db.Database)
 	vertexBootstrappingDB := prefixdb.New([]byte("vertex_bs"), db.Database)
 	txBootstrappingDB := prefixdb.New([]byte("tx_bs"), db.Database)
 
-	vtxBlocker, err := queue.NewWithMissing(vertexBootstrappingDB, consensusParams.Namespace+"_vtx", ctx.Metrics)
-	if err != nil {
-		return nil, err
+	var (
+		vtxBlocker *queue.JobsWithMissing
+		txBlocker  *queue.Jobs
+	)
+	if m.BootstrapContainerDir == "" {
+		vtxBlocker, err = queue.NewWithMissing(vertexBootstrappingDB, consensusParams.Namespace+"_vtx", ctx.Metrics)
+		if err != nil {
+			return nil, err
+		}
+		txBlocker, err = queue.New(txBootstrappingDB, consensusParams.Namespace+"_tx", ctx.Metrics)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		vtxPath, err := m.bootstrapContainerPath(ctx.ChainID, "vtx")
+		if err != nil {
+			return nil, err
+		}
+		vtxBlocker, err = queue.NewFileBackedWithMissing(vertexBootstrappingDB, vtxPath, consensusParams.Namespace+"_vtx", ctx.Metrics)
+		if err != nil {
+			return nil, err
+		}
+		txPath, err := m.bootstrapContainerPath(ctx.ChainID, "tx")
+		if err != nil {
+			return nil, err
+		}
+		txBlocker, err = queue.NewFileBacked(txBootstrappingDB, txPath, consensusParams.Namespace+"_tx", ctx.Metrics)
+		if err != nil {
+			return nil, err
+		}
 	}
-	txBlocker, err := queue.New(txBootstrappingDB, consensusParams.Namespace+"_tx", ctx.Metrics)
-	if err != nil {
-		return nil, err
+
+	conflictFactory := m.conflictFactory(ctx.ChainID)
+	if m.ReplayLogDir != "" {
+		replayLog, err := m.openReplayLog(ctx.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		conflictFactory = snowstorm.RecorderFactory{
+			Factory: conflictFactory,
+			W:       replayLog,
+		}
 	}
 
 	// The channel through which a VM may send messages to the consensus engine
@@ -515,6 +905,7 @@ func (m *manager) createAvalancheChain(
 	// persistence of vertices
 	vtxManager := &state.Serializer{}
 	vtxManager.Initialize(ctx, vm, vertexDB)
+	vtxManager.SetLimits(m.VertexLimits)
 
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
@@ -555,15 +946,33 @@ func (m *manager) createAvalancheChain(
 				MaxTimeGetAncestors:           m.BootstrapMaxTimeGetAncestors,
 				MultiputMaxContainersSent:     m.BootstrapMultiputMaxContainersSent,
 				MultiputMaxContainersReceived: m.BootstrapMultiputMaxContainersReceived,
+				MaxOutstandingGetAncestors:    m.BootstrapMaxOutstandingGetAncestors,
+				Latencies:                     m.TimeoutManager,
+				RepollLatencyBias:             m.RepollLatencyBias,
+				Benchlist:                     m.TimeoutManager,
 			},
 			VtxBlocked: vtxBlocker,
 			TxBlocked:  txBlocker,
 			Manager:    vtxManager,
+			Checkpoint: true,
+			StateSync:  m.StateSync,
 
 			VM: vm,
 		},
-		Params:    consensusParams,
-		Consensus: &avcon.Topological{},
+		Params: consensusParams,
+		Consensus: &avcon.Topological{
+			ConflictFactory: conflictFactory,
+		},
+		LightVerification:     m.LightVerification,
+		VirtuousRepoll:        m.VirtuousRepoll,
+		MaxOrphans:            m.MaxOrphans,
+		OrphanExpiry:          m.OrphanExpiry,
+		VertexLimits:          m.VertexLimits,
+		StallThreshold:        m.StallThreshold,
+		TxVerifiers:           m.TxVerifiers,
+		FrontierSyncFrequency: m.FrontierSyncFrequency,
+		SkipBenched:           m.SkipBenched,
+		StaleThreshold:        m.BootstrapStaleChainThreshold,
 	}); err != nil {
 		return nil, fmt.Errorf("error initializing avalanche engine: %w", err)
 	}
@@ -575,8 +984,12 @@ func (m *manager) createAvalancheChain(
 	}
 	// Grab the context lock before calling the chain's health check
 	checkFn := func() (interface{}, error) {
+		if m.isStopped(ctx.ChainID) {
+			return "stopped as its subnet isn't whitelisted", nil
+		}
 		ctx.Lock.Lock()
 		defer ctx.Lock.Unlock()
+
 		return engine.HealthCheck()
 	}
 	if err := m.HealthService.RegisterCheck(chainAlias, checkFn); err != nil {
@@ -590,14 +1003,26 @@ func (m *manager) createAvalancheChain(
 		fmt.Sprintf("%s_handler", consensusParams.Namespace),
 		consensusParams.Metrics,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
+	}
+
+	rejected, err := m.newRejectedCache(ctx, db.Database)
+	if err != nil {
+		return nil, err
+	}
+	handler.SetRejectedCache(rejected)
+	handler.SetBudget(chainConfig.Budget)
 
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
-		VM:      vm,
-		Ctx:     ctx,
-	}, err
+		Name:     chainAlias,
+		Engine:   engine,
+		Handler:  handler,
+		VM:       vm,
+		Ctx:      ctx,
+		Prefixes: prefixes,
+		Migrator: migrator,
+	}, nil
 }
 
 // Create a linear chain using the Snowman consensus engine
@@ -615,6 +1040,8 @@ func (m *manager) createSnowmanChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
+	// Grab the migrations before the VM is wrapped
+	migratable, _ := vm.(migration.Migratable)
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
 	}
@@ -622,15 +1049,34 @@ func (m *manager) createSnowmanChain(
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
+	prefixes := newPrefixTracker()
+	prefixDBManager := meterDBManager.NewTrackedPrefixDBManager(ctx.ChainID[:], prefixes.add)
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
+	// The migrator must be created before the VM writes to its database
+	migrator, err := m.newMigrator(ctx, vmDBManager.Current().Database, migratable)
+	if err != nil {
+		return nil, err
+	}
+
 	db := prefixDBManager.Current()
 	bootstrappingDB := prefixdb.New([]byte("bs"), db.Database)
 
-	blocked, err := queue.NewWithMissing(bootstrappingDB, consensusParams.Namespace+"_block", ctx.Metrics)
-	if err != nil {
-		return nil, err
+	var blocked *queue.JobsWithMissing
+	if m.BootstrapContainerDir == "" {
+		blocked, err = queue.NewWithMissing(bootstrappingDB, consensusParams.Namespace+"_block", ctx.Metrics)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		blockPath, err := m.bootstrapContainerPath(ctx.ChainID, "block")
+		if err != nil {
+			return nil, err
+		}
+		blocked, err = queue.NewFileBackedWithMissing(bootstrappingDB, blockPath, consensusParams.Namespace+"_block", ctx.Metrics)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// The channel through which a VM may send messages to the consensus engine
@@ -643,6 +1089,14 @@ func (m *manager) createSnowmanChain(
 		return nil, err
 	}
 
+	var heightIndex *block.HeightIndex
+	if _, ok := vm.(block.HeightIndexedChainVM); m.HeightIndex && !ok {
+		heightIndex, err = m.newHeightIndex(ctx, db.Database, vm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
 	err = sender.Initialize(
@@ -689,13 +1143,17 @@ func (m *manager) createSnowmanChain(
 				MaxTimeGetAncestors:           m.BootstrapMaxTimeGetAncestors,
 				MultiputMaxContainersSent:     m.BootstrapMultiputMaxContainersSent,
 				MultiputMaxContainersReceived: m.BootstrapMultiputMaxContainersReceived,
+				Latencies:                     m.TimeoutManager,
+				RepollLatencyBias:             m.RepollLatencyBias,
 			},
 			Blocked:      blocked,
 			VM:           vm,
 			Bootstrapped: m.unblockChains,
 		},
-		Params:    consensusParams,
-		Consensus: &smcon.Topological{},
+		Params:         consensusParams,
+		Consensus:      &smcon.Topological{},
+		StaleThreshold: m.BootstrapStaleChainThreshold,
+		HeightIndex:    heightIndex,
 	}); err != nil {
 		return nil, fmt.Errorf("error initializing snowman engine: %w", err)
 	}
@@ -711,6 +1169,13 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("couldn't initialize message handler: %s", err)
 	}
 
+	rejected, err := m.newRejectedCache(ctx, db.Database)
+	if err != nil {
+		return nil, err
+	}
+	handler.SetRejectedCache(rejected)
+	handler.SetBudget(chainConfig.Budget)
+
 	// Register health checks
 	chainAlias, err := m.PrimaryAlias(ctx.ChainID)
 	if err != nil {
@@ -718,8 +1183,12 @@ func (m *manager) createSnowmanChain(
 	}
 
 	checkFn := func() (interface{}, error) {
+		if m.isStopped(ctx.ChainID) {
+			return "stopped as its subnet isn't whitelisted", nil
+		}
 		ctx.Lock.Lock()
 		defer ctx.Lock.Unlock()
+
 		return engine.HealthCheck()
 	}
 	if err := m.HealthService.RegisterCheck(chainAlias, checkFn); err != nil {
@@ -727,21 +1196,58 @@ func (m *manager) createSnowmanChain(
 	}
 
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
-		VM:      vm,
-		Ctx:     ctx,
+		Name:     chainAlias,
+		Engine:   engine,
+		Handler:  handler,
+		VM:       vm,
+		Ctx:      ctx,
+		Prefixes: prefixes,
+		Migrator: migrator,
 	}, nil
 }
 
+// newRejectedCache returns the recently rejected containers of the chain
+// [ctx], which are stored in [db]
+func (m *manager) newRejectedCache(ctx *snow.Context, db database.Database) (*router.RejectedCache, error) {
+	rejected, err := router.NewRejectedCache(prefixdb.New(rejectedCachePrefix, db), rejectedCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load rejected containers: %w", err)
+	}
+	if err := m.ConsensusEvents.RegisterChain(ctx.ChainID, rejectedCacheName, rejected, false); err != nil {
+		return nil, err
+	}
+	return rejected, nil
+}
+
+// newHeightIndex returns the index of the blocks accepted by [vm] by height,
+// which is stored in [db]. Blocks that were accepted while the chain wasn't
+// indexed are indexed before it's returned.
+func (m *manager) newHeightIndex(ctx *snow.Context, db database.Database, vm block.ChainVM) (*block.HeightIndex, error) {
+	heightIndex := block.NewHeightIndex(prefixdb.New(heightIndexPrefix, db), vm)
+	lastAcceptedID, err := vm.LastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	numIndexed, err := heightIndex.Backfill(lastAcceptedID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't index accepted blocks by height: %w", err)
+	}
+	if numIndexed > 0 {
+		ctx.Log.Info("indexed %d accepted blocks by height", numIndexed)
+	}
+	if err := m.ConsensusEvents.RegisterChain(ctx.ChainID, heightIndexName, heightIndex, true); err != nil {
+		return nil, err
+	}
+	return heightIndex, nil
+}
+
 func (m *manager) SubnetID(chainID ids.ID) (ids.ID, error) {
 	m.chainsLock.Lock()
 	defer m.chainsLock.Unlock()
 
 	chain, exists := m.chains[chainID]
 	if !exists {
-		return ids.ID{}, errors.New("unknown chain ID")
+		return ids.ID{}, errUnknownChain
 	}
 	return chain.Context().SubnetID, nil
 }
@@ -757,9 +1263,321 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Engine().IsBootstrapped()
 }
 
+func (m *manager) OutstandingPolls(id ids.ID) ([]common.PollInfo, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errUnknownChain
+	}
+
+	reporter, ok := chain.Engine().(common.PollReporter)
+	if !ok {
+		return nil, errNoPollReporting
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return reporter.OutstandingPolls(), nil
+}
+
+func (m *manager) BlockedContainers(id ids.ID) ([]common.BlockedInfo, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errUnknownChain
+	}
+
+	reporter, ok := chain.Engine().(common.BlockedReporter)
+	if !ok {
+		return nil, errNoBlocked
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return reporter.BlockedContainers(), nil
+}
+
+func (m *manager) Evidence(id ids.ID) ([]common.Evidence, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errUnknownChain
+	}
+
+	reporter, ok := chain.Engine().(common.EvidenceReporter)
+	if !ok {
+		return nil, errNoEvidence
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return reporter.Evidence(), nil
+}
+
+func (m *manager) Frontier(id ids.ID) ([]ids.ID, uint64, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, 0, errUnknownChain
+	}
+
+	reporter, ok := chain.Engine().(common.FrontierReporter)
+	if !ok {
+		return nil, 0, errNoFrontier
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return reporter.Frontier()
+}
+
+func (m *manager) TxFinality(chainID ids.ID, txID ids.ID) (common.TxFinality, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return common.TxFinality{}, errUnknownChain
+	}
+
+	reporter, ok := chain.Engine().(common.TxFinalityReporter)
+	if !ok {
+		return common.TxFinality{}, errNoTxFinality
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	finality, processing := reporter.TxFinality(txID)
+	if !processing {
+		return common.TxFinality{}, errTxNotProcessing
+	}
+	return finality, nil
+}
+
+func (m *manager) GetBlockIDAtHeight(chainID ids.ID, height uint64) (ids.ID, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return ids.ID{}, errUnknownChain
+	}
+
+	indexer, ok := chain.Engine().(block.HeightIndexer)
+	if !ok {
+		return ids.ID{}, errNoHeightIndex
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return indexer.GetBlockIDAtHeight(height)
+}
+
+func (m *manager) UpdateSamplingParameters(chainID ids.ID, update common.SamplingParameters) (common.SamplingParameters, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return common.SamplingParameters{}, errUnknownChain
+	}
+
+	updater, ok := chain.Engine().(common.ParameterUpdater)
+	if !ok {
+		return common.SamplingParameters{}, errNoParamUpdates
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	params := updater.SamplingParameters()
+	if update.K != 0 {
+		params.K = update.K
+	}
+	if update.Alpha != 0 {
+		params.Alpha = update.Alpha
+	}
+	if update.ConcurrentRepolls != 0 {
+		params.ConcurrentRepolls = update.ConcurrentRepolls
+	}
+	if err := updater.UpdateSamplingParameters(params); err != nil {
+		return updater.SamplingParameters(), err
+	}
+	return params, nil
+}
+
+func (m *manager) ExportChain(chainID ids.ID, w io.Writer) (*BundleHeader, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	exportable := m.exportable[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errUnknownChain
+	}
+
+	vmID, err := m.VMManager.Lookup(exportable.params.VMAlias)
+	if err != nil {
+		return nil, fmt.Errorf("error while looking up VM: %w", err)
+	}
+	fxIDs := make([]ids.ID, len(exportable.params.FxAliases))
+	for i, fxAlias := range exportable.params.FxAliases {
+		fxIDs[i], err = m.VMManager.Lookup(fxAlias)
+		if err != nil {
+			return nil, fmt.Errorf("error while looking up Fx: %w", err)
+		}
+	}
+	var aliases []string
+	for _, alias := range m.Aliases(chainID) {
+		if alias != chainID.String() {
+			aliases = append(aliases, alias)
+		}
+	}
+	chainConfig := m.getChainConfig(chainID)
+	db := m.DBManager.Current()
+
+	header := &BundleHeader{
+		DatabaseVersion: db.Version.String(),
+		ChainID:         chainID,
+		SubnetID:        exportable.params.SubnetID,
+		VMID:            vmID,
+		FxIDs:           fxIDs,
+		GenesisData:     exportable.params.GenesisData,
+		Aliases:         aliases,
+		Config:          chainConfig.Config,
+		Upgrade:         chainConfig.Upgrade,
+		PrefixPaths:     exportable.prefixes.list(),
+	}
+
+	// The chain's lock is held until the bundle is written, so that the chain
+	// doesn't write to its database as it's read. The chain doesn't process
+	// messages in the meantime.
+	if err := writeBundle(w, header, db.Database, &chain.Context().Lock); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+func (m *manager) ImportChain(r io.Reader) (*BundleHeader, error) {
+	db := m.DBManager.Current()
+
+	var chainID ids.ID
+	header, err := readBundle(r, db.Database, func(header *BundleHeader) error {
+		if header.DatabaseVersion != db.Version.String() {
+			return fmt.Errorf("bundle was exported from database version %s but the current database version is %s",
+				header.DatabaseVersion, db.Version)
+		}
+
+		m.chainsLock.Lock()
+		defer m.chainsLock.Unlock()
+
+		if _, running := m.chains[header.ChainID]; running || m.creating.Contains(header.ChainID) {
+			return errChainRunning
+		}
+		if _, importing := m.importing[header.ChainID]; importing {
+			return errChainImporting
+		}
+		hasData, err := hasPrefixedKeys(db.Database, header.prefixes())
+		if err != nil {
+			return err
+		}
+		if hasData {
+			return errChainDataExists
+		}
+
+		chainID = header.ChainID
+		m.importing[chainID] = make(chan struct{})
+		return nil
+	})
+	if chainID != ids.Empty {
+		defer func() {
+			m.chainsLock.Lock()
+			close(m.importing[chainID])
+			delete(m.importing, chainID)
+			m.chainsLock.Unlock()
+		}()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	importedBytes, err := bundleCodec.Marshal(bundleCodecVersion, &importedChain{
+		Aliases: header.Aliases,
+		Config:  header.Config,
+		Upgrade: header.Upgrade,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := m.importedChains.Put(header.ChainID[:], importedBytes); err != nil {
+		return nil, err
+	}
+
+	m.Log.Info("imported chain %s of subnet %s", header.ChainID, header.SubnetID)
+	return header, nil
+}
+
+// getImportedChain returns what should be applied to chain [chainID] because it
+// was imported from a bundle, or nil if it wasn't
+func (m *manager) getImportedChain(chainID ids.ID) (*importedChain, error) {
+	importedBytes, err := m.importedChains.Get(chainID[:])
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	imported := &importedChain{}
+	_, err = bundleCodec.Unmarshal(importedBytes, imported)
+	return imported, err
+}
+
+// aliasImportedChain gives chain [chainID], if it was imported from a bundle,
+// the aliases it had on the node it was exported from
+func (m *manager) aliasImportedChain(chainID ids.ID) {
+	imported, err := m.getImportedChain(chainID)
+	if err != nil {
+		m.Log.Error("couldn't get the imported aliases of chain %s: %s", chainID, err)
+		return
+	}
+	if imported == nil {
+		return
+	}
+	for _, alias := range imported.Aliases {
+		if err := m.RegisterAlias(chainID, alias, "chain import"); err != nil {
+			m.Log.Warn("couldn't give chain %s its imported alias %s: %s", chainID, alias, err)
+			continue
+		}
+		if err := m.Server.AddAliases("bc/"+chainID.String(), "bc/"+alias); err != nil {
+			m.Log.Warn("couldn't add API alias %s of chain %s: %s", alias, chainID, err)
+		}
+	}
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.Log.Info("shutting down chain manager")
+
+	// Stop the migrations before the VMs are shutdown
+	m.chainsLock.Lock()
+	for chainID := range m.migrators {
+		m.stopMigrator(chainID)
+	}
+	m.chainsLock.Unlock()
+
 	m.ManagerConfig.Router.Shutdown()
 }
 
@@ -788,6 +1606,7 @@ func (m *manager) isChainWithAlias(aliases ...string) (string, bool) {
 
 // getChainConfig returns value of a entry by looking at ID key and alias key
 // it first searches ID key, then falls back to it's corresponding primary alias
+// and then to the config the chain was imported with
 func (m *manager) getChainConfig(id ids.ID) ChainConfig {
 	if val, ok := m.ManagerConfig.ChainConfigs[id.String()]; ok {
 		return val
@@ -799,5 +1618,16 @@ func (m *manager) getChainConfig(id ids.ID) ChainConfig {
 		}
 	}
 
+	// Fall back to the config the chain had on the node it was exported from
+	imported, err := m.getImportedChain(id)
+	if err != nil {
+		m.Log.Error("couldn't get the imported config of chain %s: %s", id, err)
+	}
+	if imported != nil {
+		return ChainConfig{
+			Config:  imported.Config,
+			Upgrade: imported.Upgrade,
+		}
+	}
 	return ChainConfig{}
 }
@@ -1,7 +1,10 @@
 package chains
 
 import (
+	"io"
+
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 )
 
@@ -15,10 +18,39 @@ func (mm MockManager) ForceCreateChain(ChainParameters) {}
 func (mm MockManager) AddRegistrant(Registrant)         {}
 func (mm MockManager) Aliases(ids.ID) []string          { return nil }
 func (mm MockManager) Alias(ids.ID, string) error       { return nil }
+func (mm MockManager) LoadAliases() error               { return nil }
 func (mm MockManager) Shutdown()                        {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)  { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool       { return false }
 
+func (mm MockManager) OutstandingPolls(ids.ID) ([]common.PollInfo, error) { return nil, nil }
+
+func (mm MockManager) BlockedContainers(ids.ID) ([]common.BlockedInfo, error) { return nil, nil }
+
+func (mm MockManager) Evidence(ids.ID) ([]common.Evidence, error) { return nil, nil }
+
+func (mm MockManager) Frontier(ids.ID) ([]ids.ID, uint64, error) { return nil, 0, nil }
+
+func (mm MockManager) TxFinality(ids.ID, ids.ID) (common.TxFinality, error) {
+	return common.TxFinality{}, nil
+}
+
+func (mm MockManager) GetBlockIDAtHeight(ids.ID, uint64) (ids.ID, error) { return ids.ID{}, nil }
+
+func (mm MockManager) UpdateSamplingParameters(ids.ID, common.SamplingParameters) (common.SamplingParameters, error) {
+	return common.SamplingParameters{}, nil
+}
+
+func (mm MockManager) RegisterAlias(ids.ID, string, string) error { return nil }
+
+func (mm MockManager) PersistAlias(ids.ID, string, string) error { return nil }
+
+func (mm MockManager) RemovePersistedAlias(string) error { return nil }
+
+func (mm MockManager) ExportChain(ids.ID, io.Writer) (*BundleHeader, error) { return nil, nil }
+
+func (mm MockManager) ImportChain(io.Reader) (*BundleHeader, error) { return nil, nil }
+
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
 	if err == nil {
@@ -34,3 +66,9 @@ func (mm MockManager) LookupVM(s string) (ids.ID, error) {
 	}
 	return ids.ID{}, nil
 }
+
+func (mm MockManager) WhitelistedSubnets() ids.Set { return nil }
+
+func (mm MockManager) SetWhitelistedSubnets(ids.Set) error { return nil }
+
+func (mm MockManager) SetSubnetTracker(SubnetTracker) {}
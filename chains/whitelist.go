@@ -0,0 +1,136 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var errNoSubnetTracker = errors.New("the P-chain isn't running yet")
+
+// SubnetTracker is told when subnets are added to or removed from the
+// whitelist. It's implemented by the P-chain, which knows the chains and the
+// validators of each subnet.
+type SubnetTracker interface {
+	// TrackSubnet starts tracking the validators of subnet [subnetID], and
+	// creates its chains
+	TrackSubnet(subnetID ids.ID) error
+	// UntrackSubnet stops tracking the validators of subnet [subnetID]. Its
+	// chains are stopped by the chain manager.
+	UntrackSubnet(subnetID ids.ID) error
+}
+
+func (m *manager) SetSubnetTracker(tracker SubnetTracker) {
+	m.whitelistLock.Lock()
+	defer m.whitelistLock.Unlock()
+
+	m.subnetTracker = tracker
+}
+
+func (m *manager) WhitelistedSubnets() ids.Set {
+	m.whitelistLock.RLock()
+	defer m.whitelistLock.RUnlock()
+
+	subnetIDs := ids.NewSet(m.whitelistedSubnets.Len())
+	subnetIDs.Union(m.whitelistedSubnets)
+	return subnetIDs
+}
+
+func (m *manager) SetWhitelistedSubnets(subnetIDs ids.Set) error {
+	// Updates are applied one at a time, so that a subnet isn't tracked and
+	// untracked concurrently
+	m.updateWhitelistLock.Lock()
+	defer m.updateWhitelistLock.Unlock()
+
+	whitelisted := ids.NewSet(subnetIDs.Len() + 1)
+	whitelisted.Union(subnetIDs)
+	whitelisted.Add(constants.PrimaryNetworkID)
+
+	m.whitelistLock.Lock()
+	tracker := m.subnetTracker
+	if tracker == nil {
+		m.whitelistLock.Unlock()
+		return errNoSubnetTracker
+	}
+	var added, removed []ids.ID
+	for subnetID := range whitelisted {
+		if !m.whitelistedSubnets.Contains(subnetID) {
+			added = append(added, subnetID)
+		}
+	}
+	for subnetID := range m.whitelistedSubnets {
+		if !whitelisted.Contains(subnetID) {
+			removed = append(removed, subnetID)
+		}
+	}
+	m.whitelistedSubnets = whitelisted
+	m.whitelistLock.Unlock()
+
+	errs := wrappers.Errs{}
+	for _, subnetID := range removed {
+		m.Log.Info("removing subnet %s from the whitelist", subnetID)
+		m.stopSubnet(subnetID)
+		if err := tracker.UntrackSubnet(subnetID); err != nil {
+			errs.Add(fmt.Errorf("couldn't stop tracking subnet %s: %w", subnetID, err))
+		}
+	}
+	// The chains of added subnets are created once the whitelist includes
+	// them, as chains of subnets that aren't whitelisted are skipped
+	for _, subnetID := range added {
+		m.Log.Info("adding subnet %s to the whitelist", subnetID)
+		if err := tracker.TrackSubnet(subnetID); err != nil {
+			errs.Add(fmt.Errorf("couldn't start tracking subnet %s: %w", subnetID, err))
+		}
+	}
+	return errs.Err
+}
+
+// isWhitelisted returns true if the chains of subnet [subnetID] should be run
+func (m *manager) isWhitelisted(subnetID ids.ID) bool {
+	m.whitelistLock.RLock()
+	defer m.whitelistLock.RUnlock()
+
+	return m.whitelistedSubnets.Contains(subnetID)
+}
+
+// stopSubnet gracefully shuts down the running chains of subnet [subnetID].
+// Stopped chains aren't started again until the node restarts, as their API
+// endpoints and aliases are still registered.
+func (m *manager) stopSubnet(subnetID ids.ID) {
+	m.chainsLock.Lock()
+	stopping := make(map[ids.ID]*router.Handler)
+	for chainID, chain := range m.exportable {
+		if chain.params.SubnetID != subnetID {
+			continue
+		}
+		stopping[chainID] = m.chains[chainID]
+		delete(m.chains, chainID)
+		delete(m.exportable, chainID)
+		m.stopMigrator(chainID)
+		m.stopped.Add(chainID)
+	}
+	m.chainsLock.Unlock()
+
+	for chainID, handler := range stopping {
+		m.Log.Info("stopping chain %s of subnet %s", chainID, subnetID)
+		m.ManagerConfig.Router.RemoveChain(chainID)
+		// Reject the API calls to the stopped chain
+		handler.Context().Unbootstrapped()
+	}
+}
+
+// isStopped returns true if chain [chainID] was stopped because its subnet was
+// removed from the whitelist
+func (m *manager) isStopped(chainID ids.ID) bool {
+	m.chainsLock.Lock()
+	defer m.chainsLock.Unlock()
+
+	return m.stopped.Contains(chainID)
+}
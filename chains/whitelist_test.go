@@ -0,0 +1,61 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type testSubnetTracker struct {
+	tracked, untracked ids.Set
+}
+
+func (t *testSubnetTracker) TrackSubnet(subnetID ids.ID) error {
+	t.tracked.Add(subnetID)
+	return nil
+}
+
+func (t *testSubnetTracker) UntrackSubnet(subnetID ids.ID) error {
+	t.untracked.Add(subnetID)
+	return nil
+}
+
+func TestSetWhitelistedSubnets(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID0 := ids.GenerateTestID()
+	subnetID1 := ids.GenerateTestID()
+	m := &manager{
+		ManagerConfig: ManagerConfig{Log: logging.NoLog{}},
+		chains:        make(map[ids.ID]*router.Handler),
+		exportable:    make(map[ids.ID]exportableChain),
+	}
+	m.whitelistedSubnets.Add(constants.PrimaryNetworkID, subnetID0)
+
+	// The whitelist can't change until the P-chain tracks subnets
+	assert.ErrorIs(m.SetWhitelistedSubnets(ids.Set{}), errNoSubnetTracker)
+	assert.True(m.isWhitelisted(subnetID0))
+
+	tracker := &testSubnetTracker{}
+	m.SetSubnetTracker(tracker)
+
+	newWhitelist := ids.Set{}
+	newWhitelist.Add(subnetID1)
+	assert.NoError(m.SetWhitelistedSubnets(newWhitelist))
+
+	expected := ids.Set{}
+	expected.Add(constants.PrimaryNetworkID, subnetID1)
+	assert.Equal(expected, m.WhitelistedSubnets())
+	assert.True(m.tracksChain(ChainParameters{SubnetID: subnetID1}))
+	assert.False(m.tracksChain(ChainParameters{SubnetID: subnetID0}))
+	assert.Equal(ids.Set{subnetID1: struct{}{}}, tracker.tracked)
+	assert.Equal(ids.Set{subnetID0: struct{}{}}, tracker.untracked)
+}
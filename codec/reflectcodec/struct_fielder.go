@@ -15,6 +15,10 @@ const (
 	// SliceLenTagName that specifies the length of a slice.
 	SliceLenTagName = "len"
 
+	// CopyTagName that specifies that the byte slices of a field are copies of
+	// the unmarshaled bytes rather than references to them.
+	CopyTagName = "copy"
+
 	// TagValue is the value the tag must have to be serialized.
 	TagValue = "true"
 )
@@ -24,13 +28,15 @@ var _ StructFielder = &structFielder{}
 type FieldDesc struct {
 	Index       int
 	MaxSliceLen uint32
+	Copy        bool
 }
 
 // StructFielder handles discovery of serializable fields in a struct.
 type StructFielder interface {
 	// Returns the fields that have been marked as serializable in [t], which is
 	// a struct type. Additionally, returns the custom maximum length slice that
-	// may be serialized into the field, if any.
+	// may be serialized into the field, if any, and whether the field copies
+	// the unmarshaled bytes.
 	// Returns an error if a field has tag "[tagName]: [TagValue]" but the field
 	// is un-exported.
 	// GetSerializedField(Foo) --> [1,5,8] means Foo.Field(1), Foo.Field(5),
@@ -88,6 +94,7 @@ func (s *structFielder) GetSerializedFields(t reflect.Type) ([]FieldDesc, error)
 		serializedFields = append(serializedFields, FieldDesc{
 			Index:       i,
 			MaxSliceLen: maxSliceLen,
+			Copy:        field.Tag.Get(CopyTagName) == TagValue,
 		})
 	}
 	s.serializedFieldIndices[t] = serializedFields // cache result
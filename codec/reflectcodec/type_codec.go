@@ -56,6 +56,13 @@ type TypeCodec interface {
 // 5) To unmarshal an interface,  you must call codec.RegisterType([instance of the type that fulfills the interface]).
 // 6) Serialized fields must be exported
 // 7) nil slices are marshaled as empty slices
+// 8) Unmarshaled byte slices reference the unmarshaled bytes, unless the field
+//    is tagged with `copy:"true"`. So, the unmarshaled bytes must not be
+//    modified while the value is in use, and the value must not be modified if
+//    the unmarshaled bytes are in use. Appending to an unmarshaled byte slice
+//    never modifies the unmarshaled bytes. The byte slices of a copied field,
+//    including those nested in slices of it, are copies of the unmarshaled
+//    bytes, so they don't keep all of the unmarshaled bytes alive.
 type genericCodec struct {
 	typer       TypeCodec
 	maxSliceLen uint32
@@ -204,7 +211,7 @@ func (c *genericCodec) Unmarshal(bytes []byte, dest interface{}) error {
 	if destPtr.Kind() != reflect.Ptr {
 		return errNeedPointer
 	}
-	if err := c.unmarshal(&p, destPtr.Elem(), c.maxSliceLen); err != nil {
+	if err := c.unmarshal(&p, destPtr.Elem(), c.maxSliceLen, false /*=copyBytes*/); err != nil {
 		return err
 	}
 	if p.Offset != len(bytes) {
@@ -213,9 +220,11 @@ func (c *genericCodec) Unmarshal(bytes []byte, dest interface{}) error {
 	return nil
 }
 
-// Unmarshal from p.Bytes into [value]. [value] must be addressable.
+// Unmarshal from p.Bytes into [value]. [value] must be addressable. If
+// [copyBytes], byte slices in [value] are copies of p.Bytes rather than
+// references to it.
 // c.lock should be held for the duration of this function
-func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSliceLen uint32) error {
+func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSliceLen uint32, copyBytes bool) error {
 	switch value.Kind() {
 	case reflect.Uint8:
 		value.SetUint(uint64(p.UnpackByte()))
@@ -291,14 +300,26 @@ func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSli
 		// If this is a slice of bytes, manually unpack the bytes rather
 		// than calling unmarshal on each byte. This improves performance.
 		if elemKind := value.Type().Elem().Kind(); elemKind == reflect.Uint8 {
-			value.SetBytes(p.UnpackFixedBytes(numElts))
-			return p.Err
+			unpackedBytes := p.UnpackFixedBytes(numElts)
+			if p.Errored() {
+				return p.Err
+			}
+			if copyBytes {
+				copiedBytes := make([]byte, numElts)
+				copy(copiedBytes, unpackedBytes)
+				value.SetBytes(copiedBytes)
+			} else {
+				// Cap the slice so that appending to it can't overwrite the
+				// rest of p.Bytes
+				value.SetBytes(unpackedBytes[:numElts:numElts])
+			}
+			return nil
 		}
 		// set [value] to be a slice of the appropriate type/capacity (right now it is nil)
 		value.Set(reflect.MakeSlice(value.Type(), numElts, numElts))
 		// Unmarshal each element into the appropriate index of the slice
 		for i := 0; i < numElts; i++ {
-			if err := c.unmarshal(p, value.Index(i), c.maxSliceLen); err != nil {
+			if err := c.unmarshal(p, value.Index(i), c.maxSliceLen, copyBytes); err != nil {
 				return fmt.Errorf("couldn't unmarshal slice element: %w", err)
 			}
 		}
@@ -316,7 +337,7 @@ func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSli
 			return nil
 		}
 		for i := 0; i < numElts; i++ {
-			if err := c.unmarshal(p, value.Index(i), c.maxSliceLen); err != nil {
+			if err := c.unmarshal(p, value.Index(i), c.maxSliceLen, copyBytes); err != nil {
 				return fmt.Errorf("couldn't unmarshal array element: %w", err)
 			}
 		}
@@ -333,7 +354,7 @@ func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSli
 			return err
 		}
 		// Unmarshal into the struct
-		if err := c.unmarshal(p, intfImplementor, c.maxSliceLen); err != nil {
+		if err := c.unmarshal(p, intfImplementor, c.maxSliceLen, false /*=copyBytes*/); err != nil {
 			return fmt.Errorf("couldn't unmarshal interface: %w", err)
 		}
 		// And assign the filled struct to the value
@@ -347,7 +368,7 @@ func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSli
 		}
 		// Go through the fields and umarshal into them
 		for _, fieldDesc := range serializedFieldIndices {
-			if err := c.unmarshal(p, value.Field(fieldDesc.Index), fieldDesc.MaxSliceLen); err != nil {
+			if err := c.unmarshal(p, value.Field(fieldDesc.Index), fieldDesc.MaxSliceLen, fieldDesc.Copy); err != nil {
 				return fmt.Errorf("couldn't unmarshal struct: %w", err)
 			}
 		}
@@ -358,7 +379,7 @@ func (c *genericCodec) unmarshal(p *wrappers.Packer, value reflect.Value, maxSli
 		// Create a new pointer to a new value of the underlying type
 		v := reflect.New(t)
 		// Fill the value
-		if err := c.unmarshal(p, v.Elem(), c.maxSliceLen); err != nil {
+		if err := c.unmarshal(p, v.Elem(), c.maxSliceLen, copyBytes); err != nil {
 			return fmt.Errorf("couldn't unmarshal pointer: %w", err)
 		}
 		// Assign to the top-level struct's member
@@ -37,6 +37,8 @@ var Tests = []func(c GeneralCodec, t testing.TB){
 	TestRestrictedSlice,
 	TestExtraSpace,
 	TestSliceLengthOverflow,
+	TestCopiedSlice,
+	TestAliasedSlice,
 }
 
 // The below structs and interfaces exist
@@ -993,3 +995,82 @@ func TestSliceLengthOverflow(codec GeneralCodec, t testing.TB) {
 		t.Fatalf("Should have errored due to large of a slice")
 	}
 }
+
+// Ensure unmarshaled byte slices of copied fields don't reference the
+// unmarshaled bytes
+func TestCopiedSlice(codec GeneralCodec, t testing.TB) {
+	var _ GeneralCodec = codec
+
+	type inner struct {
+		Bytes  []byte   `serialize:"true" copy:"true"`
+		Slices [][]byte `serialize:"true" copy:"true"`
+	}
+
+	manager := NewDefaultManager()
+	if err := manager.RegisterCodec(0, codec); err != nil {
+		t.Fatal(err)
+	}
+
+	bytes, err := manager.Marshal(0, inner{
+		Bytes:  []byte{1, 2},
+		Slices: [][]byte{{3}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := inner{}
+	if _, err := manager.Unmarshal(bytes, &s); err != nil {
+		t.Fatal(err)
+	}
+	for i := range bytes {
+		bytes[i] = 0
+	}
+	if !reflect.DeepEqual(s, inner{Bytes: []byte{1, 2}, Slices: [][]byte{{3}}}) {
+		t.Fatalf("unmarshaled value changed when the unmarshaled bytes were modified")
+	}
+}
+
+// Ensure unmarshaled byte slices reference the unmarshaled bytes by default,
+// and can't be used to modify the rest of the unmarshaled bytes
+func TestAliasedSlice(codec GeneralCodec, t testing.TB) {
+	var _ GeneralCodec = codec
+
+	type inner struct {
+		Bytes  []byte   `serialize:"true"`
+		Slices [][]byte `serialize:"true"`
+		Last   uint8    `serialize:"true"`
+	}
+
+	manager := NewDefaultManager()
+	if err := manager.RegisterCodec(0, codec); err != nil {
+		t.Fatal(err)
+	}
+
+	bytes, err := manager.Marshal(0, inner{
+		Bytes:  []byte{1, 2},
+		Slices: [][]byte{{3}},
+		Last:   4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := inner{}
+	if _, err := manager.Unmarshal(bytes, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	// Appending to an aliased slice must not overwrite the bytes after it
+	_ = append(s.Slices[0], 5)
+	if bytes[len(bytes)-1] != 4 {
+		t.Fatalf("appending to an aliased slice modified the unmarshaled bytes")
+	}
+
+	for i := range bytes {
+		bytes[i] = 0
+	}
+	if !reflect.DeepEqual(s, inner{Bytes: []byte{0, 0}, Slices: [][]byte{{0}}, Last: 4}) {
+		t.Fatalf("aliased slices don't reference the unmarshaled bytes")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -19,14 +20,17 @@ import (
 
 	"github.com/ava-labs/avalanchego/app/process"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/geoip"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
@@ -36,13 +40,15 @@ import (
 	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/ulimit"
+	"github.com/ava-labs/avalanchego/vms"
 )
 
 const (
-	avalanchegoLatest     = "avalanchego-latest"
-	avalanchegoPreupgrade = "avalanchego-preupgrade"
-	chainConfigFileName   = "config"
-	chainUpgradeFileName  = "upgrade"
+	avalanchegoLatest      = "avalanchego-latest"
+	avalanchegoPreupgrade  = "avalanchego-preupgrade"
+	chainConfigFileName    = "config"
+	chainUpgradeFileName   = "upgrade"
+	chainResourcesFileName = "resources"
 )
 
 var (
@@ -122,14 +128,65 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.ConsensusParams.BetaRogue = v.GetInt(SnowRogueCommitThresholdKey)
 	nodeConfig.ConsensusParams.Parents = v.GetInt(SnowAvalancheNumParentsKey)
 	nodeConfig.ConsensusParams.BatchSize = v.GetInt(SnowAvalancheBatchSizeKey)
+	nodeConfig.ConsensusParams.MaxBatchSize = v.GetInt(SnowAvalancheMaxBatchSizeKey)
 	nodeConfig.ConsensusParams.ConcurrentRepolls = v.GetInt(SnowConcurrentRepollsKey)
 	nodeConfig.ConsensusParams.OptimalProcessing = v.GetInt(SnowOptimalProcessingKey)
 	nodeConfig.ConsensusParams.MaxOutstandingItems = v.GetInt(SnowMaxProcessingKey)
 	nodeConfig.ConsensusParams.MaxItemProcessingTime = v.GetDuration(SnowMaxTimeProcessingKey)
+	nodeConfig.ConsensusParams.MaxItemStalledTime = v.GetDuration(SnowMaxTimeStalledKey)
+	nodeConfig.ConsensusParams.MaxProcessing = v.GetInt(SnowProcessingLimitKey)
+	if err := nodeConfig.ConsensusParams.Valid(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid consensus parameters: %w", err)
+	}
 	nodeConfig.ConsensusGossipFrequency = v.GetDuration(ConsensusGossipFrequencyKey)
 	nodeConfig.ConsensusShutdownTimeout = v.GetDuration(ConsensusShutdownTimeoutKey)
 	nodeConfig.ConsensusGossipAcceptedFrontierSize = uint(v.GetUint32(ConsensusGossipAcceptedFrontierSizeKey))
 	nodeConfig.ConsensusGossipOnAcceptSize = uint(v.GetUint32(ConsensusGossipOnAcceptSizeKey))
+	nodeConfig.RepollLatencyBias = v.GetFloat64(SnowRepollLatencyBiasKey)
+	if nodeConfig.RepollLatencyBias < 0 || nodeConfig.RepollLatencyBias > 1 {
+		return node.Config{}, fmt.Errorf("%s must be in [0,1]", SnowRepollLatencyBiasKey)
+	}
+	nodeConfig.LightVerification = v.GetBool(SnowLightVerificationKey)
+	nodeConfig.VirtuousRepoll = v.GetBool(SnowVirtuousRepollKey)
+	nodeConfig.MaxOrphans = v.GetInt(SnowMaxOrphansKey)
+	if nodeConfig.MaxOrphans < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", SnowMaxOrphansKey)
+	}
+	nodeConfig.OrphanExpiry = v.GetDuration(SnowOrphanExpiryKey)
+	if nodeConfig.OrphanExpiry < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", SnowOrphanExpiryKey)
+	}
+	nodeConfig.VertexLimits = vertex.Limits{
+		MaxTxs:          v.GetInt(SnowVertexMaxTxsKey),
+		MaxRestrictions: v.GetInt(SnowVertexMaxRestrictionsKey),
+		MaxBytes:        v.GetInt(SnowVertexMaxBytesKey),
+	}
+	if err := nodeConfig.VertexLimits.Valid(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid vertex limits: %w", err)
+	}
+	nodeConfig.StallThreshold = v.GetDuration(SnowStallThresholdKey)
+	if nodeConfig.StallThreshold < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", SnowStallThresholdKey)
+	}
+	nodeConfig.StateSync = v.GetBool(SnowStateSyncKey)
+	nodeConfig.TxVerifiers = v.GetInt(SnowTxVerifiersKey)
+	if nodeConfig.TxVerifiers < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", SnowTxVerifiersKey)
+	}
+	nodeConfig.FrontierSyncFrequency = v.GetDuration(SnowFrontierSyncFrequencyKey)
+	if nodeConfig.FrontierSyncFrequency < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", SnowFrontierSyncFrequencyKey)
+	}
+	nodeConfig.SkipBenched = v.GetBool(SnowSkipBenchedKey)
+	nodeConfig.HeightIndex = v.GetBool(SnowHeightIndexKey)
+	for _, chain := range strings.Split(v.GetString(SnowInputConflictGraphChainsKey), ",") {
+		if chain = strings.TrimSpace(chain); chain != "" {
+			nodeConfig.InputConflictGraphChains = append(nodeConfig.InputConflictGraphChains, chain)
+		}
+	}
+	if replayLogDir := v.GetString(SnowReplayLogDirKey); replayLogDir != "" {
+		nodeConfig.ReplayLogDir = os.ExpandEnv(replayLogDir)
+	}
 
 	// Logging:
 	loggingConfig, err := logging.DefaultConfig()
@@ -174,9 +231,18 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		constants.NetworkName(nodeConfig.NetworkID),
 	)
 
+	// Proxy that outbound connections go through, if any
+	proxyURL, err := dialer.ParseProxyURL(v.GetString(OutboundConnectionProxyKey))
+	if err != nil {
+		return node.Config{}, fmt.Errorf("couldn't parse %s: %w", OutboundConnectionProxyKey, err)
+	}
+
 	// IP configuration
 	// Resolves our public IP, or does nothing
-	nodeConfig.DynamicPublicIPResolver = dynamicip.NewResolver(v.GetString(DynamicPublicIPResolverKey))
+	nodeConfig.DynamicPublicIPResolver, err = dynamicip.ParseResolver(v.GetString(DynamicPublicIPResolverKey), proxyURL)
+	if err != nil {
+		return node.Config{}, fmt.Errorf("couldn't parse %s: %w", DynamicPublicIPResolverKey, err)
+	}
 
 	var ip net.IP
 	publicIP := v.GetString(PublicIPKey)
@@ -200,7 +266,7 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	default:
 		// User specified a public IP to use; don't use NAT
 		nodeConfig.Nat = nat.NewNoRouter()
-		ip = net.ParseIP(publicIP)
+		ip = utils.ParseIP(publicIP)
 	}
 
 	if ip == nil {
@@ -212,6 +278,19 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.StakingIP = utils.NewDynamicIPDesc(ip, stakingPort)
 
 	nodeConfig.DynamicUpdateDuration = v.GetDuration(DynamicUpdateDurationKey)
+	if dnsConfigFile := v.GetString(DynamicDNSConfigFileKey); dnsConfigFile != "" {
+		if !nodeConfig.DynamicPublicIPResolver.IsResolver() {
+			return node.Config{}, fmt.Errorf("%s requires %s", DynamicDNSConfigFileKey, DynamicPublicIPResolverKey)
+		}
+		dnsConfig, err := dynamicip.LoadDNSConfig(os.ExpandEnv(dnsConfigFile))
+		if err != nil {
+			return node.Config{}, err
+		}
+		nodeConfig.DynamicDNSUpdater, err = dynamicip.NewDNSUpdater(dnsConfig, proxyURL)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't create dynamic DNS updater: %w", err)
+		}
+	}
 	nodeConfig.ConnMeterResetDuration = v.GetDuration(ConnMeterResetDurationKey)
 	nodeConfig.ConnMeterMaxConns = v.GetInt(ConnMeterMaxConnsKey)
 
@@ -275,6 +354,20 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		}
 	}
 
+	nodeConfig.WhitelistedChains, err = parseChainIDs(v.GetString(WhitelistedChainsKey))
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.BlacklistedChains, err = parseChainIDs(v.GetString(BlacklistedChainsKey))
+	if err != nil {
+		return node.Config{}, err
+	}
+	for chainID := range nodeConfig.WhitelistedChains {
+		if nodeConfig.BlacklistedChains.Contains(chainID) {
+			return node.Config{}, fmt.Errorf("chain %s can't be both whitelisted and blacklisted", chainID)
+		}
+	}
+
 	// HTTP:
 	nodeConfig.HTTPHost = v.GetString(HTTPHostKey)
 	nodeConfig.HTTPPort = uint16(v.GetUint(HTTPPortKey))
@@ -319,6 +412,7 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.RouterHealthConfig.MaxOutstandingDuration = v.GetDuration(NetworkHealthMaxOutstandingDurationKey)
 	nodeConfig.RouterHealthConfig.MaxRunTimeRequests = v.GetDuration(NetworkMaximumTimeoutKey)
 	nodeConfig.RouterHealthConfig.MaxDropRateHalflife = healthCheckAveragerHalflife
+	nodeConfig.RouterMaxPendingMsgs = int(v.GetUint(RouterMaxPendingMsgsKey))
 	switch {
 	case nodeConfig.RouterHealthConfig.MaxDropRate < 0 || nodeConfig.RouterHealthConfig.MaxDropRate > 1:
 		return node.Config{}, fmt.Errorf("%s must be in [0,1]", RouterHealthMaxDropRateKey)
@@ -355,6 +449,20 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 
 	// Health
 	nodeConfig.HealthCheckFreq = v.GetDuration(HealthCheckFreqKey)
+	nodeConfig.HealthBeaconURL = v.GetString(HealthBeaconURLKey)
+	nodeConfig.HealthBeaconFreq = v.GetDuration(HealthBeaconFreqKey)
+	if nodeConfig.HealthBeaconURL != "" {
+		beaconURL, err := url.Parse(nodeConfig.HealthBeaconURL)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", HealthBeaconURLKey, err)
+		}
+		if beaconURL.Scheme != "https" {
+			return node.Config{}, fmt.Errorf("%s must be an https URL", HealthBeaconURLKey)
+		}
+		if nodeConfig.HealthBeaconFreq <= 0 {
+			return node.Config{}, fmt.Errorf("%s must be positive", HealthBeaconFreqKey)
+		}
+	}
 	// Network Health Check
 	nodeConfig.NetworkConfig.HealthConfig = network.HealthConfig{
 		MaxTimeSinceMsgSent:          v.GetDuration(NetworkHealthMaxTimeSinceMsgSentKey),
@@ -406,10 +514,66 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.PeerListGossipFreq = v.GetDuration(NetworkPeerListGossipFreqKey)
 	nodeConfig.PeerListGossipSize = v.GetUint32(NetworkPeerListGossipSizeKey)
 
+	// Peer location resolution
+	nodeConfig.NetworkConfig.GeoIPResolver, err = geoip.NewResolver(os.ExpandEnv(v.GetString(NetworkGeoIPDBFileKey)))
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Compression
+	nodeConfig.NetworkConfig.CompressionConfig = network.CompressionConfig{
+		Enabled: v.GetBool(NetworkCompressionEnabledKey),
+		MinSize: v.GetInt(NetworkCompressionMinSizeKey),
+	}
+	if nodeConfig.NetworkConfig.CompressionConfig.MinSize < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", NetworkCompressionMinSizeKey)
+	}
+
+	// Peer scoring
+	nodeConfig.NetworkConfig.ScoringConfig = network.ScoringConfig{
+		Enabled:      v.GetBool(NetworkPeerScoringEnabledKey),
+		BanThreshold: v.GetFloat64(NetworkPeerBanThresholdKey),
+		BanDuration:  v.GetDuration(NetworkPeerBanDurationKey),
+		HalfLife:     v.GetDuration(NetworkPeerScoreHalfLifeKey),
+	}
+	switch {
+	case nodeConfig.NetworkConfig.ScoringConfig.BanThreshold <= 0:
+		return node.Config{}, fmt.Errorf("%s must be positive", NetworkPeerBanThresholdKey)
+	case nodeConfig.NetworkConfig.ScoringConfig.BanDuration < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", NetworkPeerBanDurationKey)
+	case nodeConfig.NetworkConfig.ScoringConfig.HalfLife <= 0:
+		return node.Config{}, fmt.Errorf("%s must be positive", NetworkPeerScoreHalfLifeKey)
+	}
+
+	// Peer metrics
+	nodeConfig.NetworkConfig.PeerMetricsEnabled = v.GetBool(NetworkPeerMetricsEnabledKey)
+
+	// Peer persistence
+	nodeConfig.NetworkConfig.MaxReconnectPeers = v.GetInt(MaxReconnectPeersKey)
+	if nodeConfig.NetworkConfig.MaxReconnectPeers < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", MaxReconnectPeersKey)
+	}
+
+	// Inbound connection throttling
+	nodeConfig.NetworkConfig.InboundConnThrottlerConfig = throttling.InboundConnThrottlerConfig{
+		MaxConnsPerIP:         v.GetInt(InboundConnectionMaxConnsPerIPKey),
+		HandshakesPerSecPerIP: v.GetFloat64(InboundConnectionHandshakesPerSecKey),
+		BytesPerSecPerIP:      v.GetInt(InboundConnectionBytesPerSecKey),
+	}
+	switch {
+	case nodeConfig.NetworkConfig.InboundConnThrottlerConfig.MaxConnsPerIP < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", InboundConnectionMaxConnsPerIPKey)
+	case nodeConfig.NetworkConfig.InboundConnThrottlerConfig.HandshakesPerSecPerIP < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", InboundConnectionHandshakesPerSecKey)
+	case nodeConfig.NetworkConfig.InboundConnThrottlerConfig.BytesPerSecPerIP < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", InboundConnectionBytesPerSecKey)
+	}
+
 	// Outbound connection throttling
 	nodeConfig.NetworkConfig.DialerConfig = dialer.NewConfig(
 		v.GetUint32(OutboundConnectionThrottlingRps),
 		v.GetDuration(OutboundConnectionTimeout),
+		proxyURL,
 	)
 
 	// Benchlist
@@ -467,27 +631,34 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		if nodeConfig.StakeMintingPeriod < nodeConfig.MaxStakeDuration {
 			return node.Config{}, errors.New("stake minting period can't be less than max stake duration")
 		}
-
-		nodeConfig.EpochFirstTransition = time.Unix(v.GetInt64(SnowEpochFirstTransition), 0)
-		nodeConfig.EpochDuration = v.GetDuration(SnowEpochDuration)
 	} else {
 		nodeConfig.Params = *genesis.GetParams(networkID)
 	}
 
 	// Load genesis data
-	nodeConfig.GenesisBytes, nodeConfig.AvaxAssetID, err = genesis.Genesis(
+	genesisConfig, err := genesis.LoadConfig(
 		networkID,
 		os.ExpandEnv(v.GetString(GenesisConfigFileKey)),
 	)
 	if err != nil {
 		return node.Config{}, fmt.Errorf("unable to load genesis file: %w", err)
 	}
+	nodeConfig.GenesisBytes, nodeConfig.AvaxAssetID, err = genesis.FromConfig(genesisConfig)
+	if err != nil {
+		return node.Config{}, fmt.Errorf("unable to build genesis: %w", err)
+	}
+	nodeConfig.EpochFirstTransition = time.Unix(int64(genesisConfig.EpochFirstTransition), 0)
+	nodeConfig.EpochDuration = time.Duration(genesisConfig.EpochDuration) * time.Second
 
 	// Assertions
 	nodeConfig.EnableAssertions = v.GetBool(AssertionsEnabledKey)
 
 	// Crypto
 	nodeConfig.EnableCrypto = v.GetBool(SignatureVerificationEnabledKey)
+	nodeConfig.CryptoWorkers = v.GetInt(CryptoWorkersKey)
+	if nodeConfig.CryptoWorkers <= 0 {
+		return node.Config{}, fmt.Errorf("%s must be positive", CryptoWorkersKey)
+	}
 
 	// Indexer
 	nodeConfig.IndexAllowIncomplete = v.GetBool(IndexAllowIncompleteKey)
@@ -499,6 +670,17 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.BootstrapMaxTimeGetAncestors = v.GetDuration(BootstrapMaxTimeGetAncestorsKey)
 	nodeConfig.BootstrapMultiputMaxContainersSent = int(v.GetUint(BootstrapMultiputMaxContainersSentKey))
 	nodeConfig.BootstrapMultiputMaxContainersReceived = int(v.GetUint(BootstrapMultiputMaxContainersReceivedKey))
+	nodeConfig.BootstrapMaxOutstandingGetAncestors = int(v.GetUint(BootstrapMaxOutstandingGetAncestorsKey))
+	if nodeConfig.BootstrapMaxOutstandingGetAncestors == 0 {
+		return node.Config{}, fmt.Errorf("%s must be positive", BootstrapMaxOutstandingGetAncestorsKey)
+	}
+	nodeConfig.BootstrapStaleChainThreshold = v.GetDuration(BootstrapStaleChainThresholdKey)
+	if nodeConfig.BootstrapStaleChainThreshold < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", BootstrapStaleChainThresholdKey)
+	}
+	if v.GetBool(BootstrapContainerFilesEnabledKey) && nodeConfig.DBName != memdb.Name {
+		nodeConfig.BootstrapContainerDir = filepath.Join(nodeConfig.DBPath, "bootstrap")
+	}
 
 	// Peer alias
 	nodeConfig.PeerAliasTimeout = v.GetDuration(PeerAliasTimeoutKey)
@@ -509,12 +691,30 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 	nodeConfig.ChainConfigs = chainConfigs
+	nodeConfig.PluginCgroupDir = os.ExpandEnv(v.GetString(PluginCgroupDirKey))
+	nodeConfig.ChainErrorBudget = v.GetInt(ChainErrorBudgetKey)
+	if nodeConfig.ChainErrorBudget < 0 {
+		return node.Config{}, fmt.Errorf("%s must be non-negative", ChainErrorBudgetKey)
+	}
+	nodeConfig.ChainIncidentDir = os.ExpandEnv(v.GetString(ChainIncidentDirKey))
+	nodeConfig.ChainExportDir = os.ExpandEnv(v.GetString(ChainExportDirKey))
 
 	// Profile config
 	nodeConfig.ProfilerConfig.Dir = os.ExpandEnv(v.GetString(ProfileDirKey))
 	nodeConfig.ProfilerConfig.Enabled = v.GetBool(ProfileContinuousEnabledKey)
 	nodeConfig.ProfilerConfig.Freq = v.GetDuration(ProfileContinuousFreqKey)
 	nodeConfig.ProfilerConfig.MaxNumFiles = v.GetInt(ProfileContinuousMaxFilesKey)
+	nodeConfig.ProfilerConfig.SamplerEnabled = v.GetBool(ProfileSamplerEnabledKey)
+	if nodeConfig.ProfilerConfig.SamplerEnabled {
+		nodeConfig.ProfilerConfig.SamplerFreq = v.GetDuration(ProfileSamplerFreqKey)
+		nodeConfig.ProfilerConfig.SamplerMaxNumSamples = v.GetInt(ProfileSamplerMaxSamplesKey)
+		switch {
+		case nodeConfig.ProfilerConfig.SamplerFreq <= 0:
+			return node.Config{}, fmt.Errorf("%s must be positive", ProfileSamplerFreqKey)
+		case nodeConfig.ProfilerConfig.SamplerMaxNumSamples <= 0:
+			return node.Config{}, fmt.Errorf("%s must be positive", ProfileSamplerMaxSamplesKey)
+		}
+	}
 
 	// VM Aliases
 	vmAliases, err := readVMAliases(v)
@@ -600,6 +800,22 @@ func getChainConfigs(v *viper.Viper) (map[string]chains.ChainConfig, error) {
 	return chainConfigs, nil
 }
 
+// parseChainIDs parses a comma separated list of chain IDs
+func parseChainIDs(chainIDsStr string) (ids.Set, error) {
+	chainIDs := ids.Set{}
+	for _, chain := range strings.Split(chainIDsStr, ",") {
+		if chain == "" {
+			continue
+		}
+		chainID, err := ids.FromString(chain)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse chainID %s: %w", chain, err)
+		}
+		chainIDs.Add(chainID)
+	}
+	return chainIDs, nil
+}
+
 // Initialize config.BootstrapPeers.
 func initBootstrapPeers(v *viper.Viper, config *node.Config) error {
 	bootstrapIPs, bootstrapIDs := genesis.SampleBeacons(config.NetworkID, 5)
@@ -659,9 +875,35 @@ func readChainConfigDirs(chainDirs []string) (map[string]chains.ChainConfig, err
 			return chainConfigMap, err
 		}
 
+		// chainconfigdir/chainId/resources.*
+		resourcesData, err := readSingleFile(chainDir, chainResourcesFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+		resourceLimits := vms.ResourceLimits{}
+		budget := router.Budget{}
+		if len(resourcesData) > 0 {
+			// The limits of the VM's process and the budget of the chain's
+			// handler are read from the same file
+			if err := json.Unmarshal(resourcesData, &resourceLimits); err != nil {
+				return chainConfigMap, fmt.Errorf("couldn't parse resource limits of %s: %w", dirInfo.Name(), err)
+			}
+			if err := resourceLimits.Verify(); err != nil {
+				return chainConfigMap, fmt.Errorf("invalid resource limits of %s: %w", dirInfo.Name(), err)
+			}
+			if err := json.Unmarshal(resourcesData, &budget); err != nil {
+				return chainConfigMap, fmt.Errorf("couldn't parse resource limits of %s: %w", dirInfo.Name(), err)
+			}
+			if err := budget.Verify(); err != nil {
+				return chainConfigMap, fmt.Errorf("invalid resource limits of %s: %w", dirInfo.Name(), err)
+			}
+		}
+
 		chainConfigMap[dirInfo.Name()] = chains.ChainConfig{
-			Config:  configData,
-			Upgrade: upgradeData,
+			Config:         configData,
+			Upgrade:        upgradeData,
+			ResourceLimits: resourceLimits,
+			Budget:         budget,
 		}
 	}
 
@@ -19,6 +19,8 @@ import (
 
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/vms"
 )
 
 func TestSetChainConfigs(t *testing.T) {
@@ -135,6 +137,62 @@ func TestSetChainConfigs(t *testing.T) {
 	}
 }
 
+func TestSetChainConfigsResourceLimits(t *testing.T) {
+	tests := map[string]struct {
+		resources      string
+		errMessage     string
+		expected       vms.ResourceLimits
+		expectedBudget router.Budget
+	}{
+		"cpu and memory": {
+			resources: `{"cpus": 1.5, "memoryBytes": 1073741824}`,
+			expected:  vms.ResourceLimits{CPUs: 1.5, MemoryBytes: 1 << 30},
+		},
+		"memory only": {
+			resources: `{"memoryBytes": 1024}`,
+			expected:  vms.ResourceLimits{MemoryBytes: 1024},
+		},
+		"budget only": {
+			resources:      `{"requestsPerSecond": 100, "handlingCPUs": 0.5}`,
+			expectedBudget: router.Budget{RequestsPerSecond: 100, HandlingCPUs: 0.5},
+		},
+		"negative handling cpus": {
+			resources:  `{"handlingCPUs": -1}`,
+			errMessage: "invalid resource limits",
+		},
+		"negative cpus": {
+			resources:  `{"cpus": -1}`,
+			errMessage: "invalid resource limits",
+		},
+		"malformed": {
+			resources:  `{"cpus": "one"}`,
+			errMessage: "couldn't parse resource limits",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			root := t.TempDir()
+			configFile := setupConfigJSON(t, root, fmt.Sprintf(`{%q: %q}`, ChainConfigDirKey, root))
+			setupFile(t, path.Join(root, "C"), chainResourcesFileName+".json", test.resources)
+
+			v := setupViper(configFile)
+			chainConfigs, err := getChainConfigs(v)
+			if len(test.errMessage) > 0 {
+				assert.Error(err)
+				if err != nil {
+					assert.Contains(err.Error(), test.errMessage)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, chainConfigs["C"].ResourceLimits)
+			assert.Equal(test.expectedBudget, chainConfigs["C"].Budget)
+		})
+	}
+}
+
 func TestSetChainConfigsDirNotExist(t *testing.T) {
 	tests := map[string]struct {
 		structure  string
@@ -364,3 +422,19 @@ func setupViper(configFilePath string) *viper.Viper {
 	}
 	return v
 }
+
+func TestParseChainIDs(t *testing.T) {
+	chainID := ids.GenerateTestID()
+
+	chainIDs, err := parseChainIDs(fmt.Sprintf("%s,,%s", chainID, chainID))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, chainIDs.Len())
+	assert.True(t, chainIDs.Contains(chainID))
+
+	chainIDs, err = parseChainIDs("")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, chainIDs.Len())
+
+	_, err = parseChainIDs("not a chainID")
+	assert.Error(t, err)
+}
@@ -21,15 +21,21 @@ const (
 	StakeMintingPeriodKey                     = "stake-minting-period"
 	AssertionsEnabledKey                      = "assertions-enabled"
 	SignatureVerificationEnabledKey           = "signature-verification-enabled"
+	CryptoWorkersKey                          = "crypto-workers"
 	DBTypeKey                                 = "db-type"
 	DBPathKey                                 = "db-dir"
 	PublicIPKey                               = "public-ip"
 	DynamicUpdateDurationKey                  = "dynamic-update-duration"
 	DynamicPublicIPResolverKey                = "dynamic-public-ip"
+	DynamicDNSConfigFileKey                   = "dynamic-dns-config-file"
 	ConnMeterResetDurationKey                 = "conn-meter-reset-duration"
 	ConnMeterMaxConnsKey                      = "conn-meter-max-conns"
 	OutboundConnectionThrottlingRps           = "outbound-connection-throttling-rps"
+	InboundConnectionMaxConnsPerIPKey         = "inbound-connection-throttling-max-conns-per-ip"
+	InboundConnectionHandshakesPerSecKey      = "inbound-connection-throttling-handshakes-per-sec-per-ip"
+	InboundConnectionBytesPerSecKey           = "inbound-connection-throttling-bytes-per-sec-per-ip"
 	OutboundConnectionTimeout                 = "outbound-connection-timeout"
+	OutboundConnectionProxyKey                = "outbound-connection-proxy"
 	HTTPHostKey                               = "http-host"
 	HTTPPortKey                               = "http-port"
 	HTTPSEnabledKey                           = "http-tls-enabled"
@@ -62,6 +68,15 @@ const (
 	NetworkPeerListSizeKey                    = "network-peer-list-size"
 	NetworkPeerListGossipSizeKey              = "network-peer-list-gossip-size"
 	NetworkPeerListGossipFreqKey              = "network-peer-list-gossip-frequency"
+	NetworkGeoIPDBFileKey                     = "network-geoip-db-file"
+	NetworkCompressionEnabledKey              = "network-compression-enabled"
+	NetworkCompressionMinSizeKey              = "network-compression-min-size"
+	NetworkPeerScoringEnabledKey              = "network-peer-scoring-enabled"
+	NetworkPeerBanThresholdKey                = "network-peer-ban-threshold"
+	NetworkPeerBanDurationKey                 = "network-peer-ban-duration"
+	NetworkPeerScoreHalfLifeKey               = "network-peer-score-half-life"
+	NetworkPeerMetricsEnabledKey              = "network-peer-metrics-enabled"
+	MaxReconnectPeersKey                      = "max-reconnect-peers"
 	SendQueueSizeKey                          = "send-queue-size"
 	BenchlistFailThresholdKey                 = "benchlist-fail-threshold"
 	BenchlistPeerSummaryEnabledKey            = "benchlist-peer-summary-enabled"
@@ -78,13 +93,32 @@ const (
 	SnowRogueCommitThresholdKey               = "snow-rogue-commit-threshold"
 	SnowAvalancheNumParentsKey                = "snow-avalanche-num-parents"
 	SnowAvalancheBatchSizeKey                 = "snow-avalanche-batch-size"
+	SnowAvalancheMaxBatchSizeKey              = "snow-avalanche-max-batch-size"
 	SnowConcurrentRepollsKey                  = "snow-concurrent-repolls"
 	SnowOptimalProcessingKey                  = "snow-optimal-processing"
 	SnowMaxProcessingKey                      = "snow-max-processing"
 	SnowMaxTimeProcessingKey                  = "snow-max-time-processing"
-	SnowEpochFirstTransition                  = "snow-epoch-first-transition"
-	SnowEpochDuration                         = "snow-epoch-duration"
+	SnowMaxTimeStalledKey                     = "snow-max-time-stalled"
+	SnowProcessingLimitKey                    = "snow-processing-limit"
+	SnowInputConflictGraphChainsKey           = "snow-input-conflict-graph-chains"
+	SnowReplayLogDirKey                       = "snow-replay-log-dir"
+	SnowRepollLatencyBiasKey                  = "snow-repoll-latency-bias"
+	SnowLightVerificationKey                  = "snow-light-verification"
+	SnowVirtuousRepollKey                     = "snow-virtuous-repoll"
+	SnowMaxOrphansKey                         = "snow-max-orphans"
+	SnowOrphanExpiryKey                       = "snow-orphan-expiry"
+	SnowVertexMaxTxsKey                       = "snow-vertex-max-txs"
+	SnowVertexMaxRestrictionsKey              = "snow-vertex-max-restrictions"
+	SnowVertexMaxBytesKey                     = "snow-vertex-max-bytes"
+	SnowStallThresholdKey                     = "snow-stall-threshold"
+	SnowStateSyncKey                          = "snow-state-sync"
+	SnowTxVerifiersKey                        = "snow-tx-verifiers"
+	SnowFrontierSyncFrequencyKey              = "snow-frontier-sync-frequency"
+	SnowSkipBenchedKey                        = "snow-skip-benched"
+	SnowHeightIndexKey                        = "snow-height-index"
 	WhitelistedSubnetsKey                     = "whitelisted-subnets"
+	WhitelistedChainsKey                      = "whitelisted-chains"
+	BlacklistedChainsKey                      = "blacklisted-chains"
 	AdminAPIEnabledKey                        = "api-admin-enabled"
 	InfoAPIEnabledKey                         = "api-info-enabled"
 	KeystoreAPIEnabledKey                     = "api-keystore-enabled"
@@ -104,8 +138,11 @@ const (
 	IndexAllowIncompleteKey                   = "index-allow-incomplete"
 	RouterHealthMaxDropRateKey                = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey     = "router-health-max-outstanding-requests"
+	RouterMaxPendingMsgsKey                   = "router-max-pending-msgs"
 	HealthCheckFreqKey                        = "health-check-frequency"
 	HealthCheckAveragerHalflifeKey            = "health-check-averager-halflife"
+	HealthBeaconURLKey                        = "health-beacon-url"
+	HealthBeaconFreqKey                       = "health-beacon-frequency"
 	RetryBootstrapKey                         = "bootstrap-retry-enabled"
 	RetryBootstrapMaxAttemptsKey              = "bootstrap-retry-max-attempts"
 	PeerAliasTimeoutKey                       = "peer-alias-timeout"
@@ -114,11 +151,21 @@ const (
 	BootstrapMaxTimeGetAncestorsKey           = "boostrap-max-time-get-ancestors"
 	BootstrapMultiputMaxContainersSentKey     = "bootstrap-multiput-max-containers-sent"
 	BootstrapMultiputMaxContainersReceivedKey = "bootstrap-multiput-max-containers-received"
+	BootstrapMaxOutstandingGetAncestorsKey    = "bootstrap-max-outstanding-get-ancestors"
+	BootstrapStaleChainThresholdKey           = "bootstrap-stale-chain-threshold"
+	BootstrapContainerFilesEnabledKey         = "bootstrap-container-files-enabled"
 	ChainConfigDirKey                         = "chain-config-dir"
+	PluginCgroupDirKey                        = "plugin-cgroup-dir"
+	ChainErrorBudgetKey                       = "chain-error-budget"
+	ChainIncidentDirKey                       = "chain-incident-dir"
+	ChainExportDirKey                         = "chain-export-dir"
 	ProfileDirKey                             = "profile-dir"
 	ProfileContinuousEnabledKey               = "profile-continuous-enabled"
 	ProfileContinuousFreqKey                  = "profile-continuous-freq"
 	ProfileContinuousMaxFilesKey              = "profile-continuous-max-files"
+	ProfileSamplerEnabledKey                  = "profile-sampler-enabled"
+	ProfileSamplerFreqKey                     = "profile-sampler-freq"
+	ProfileSamplerMaxSamplesKey               = "profile-sampler-max-samples"
 	InboundThrottlerAtLargeAllocSizeKey       = "throttler-inbound-at-large-alloc-size"
 	InboundThrottlerVdrAllocSizeKey           = "throttler-inbound-validator-alloc-size"
 	InboundThrottlerNodeMaxAtLargeBytesKey    = "throttler-inbound-node-max-at-large-bytes"
@@ -120,3 +120,11 @@ func Size(db Iteratee) (int, error) {
 	}
 	return size, iterator.Error()
 }
+
+// IsEmpty returns true iff [db] doesn't contain any keys
+func IsEmpty(db Iteratee) (bool, error) {
+	iterator := db.NewIterator()
+	defer iterator.Release()
+
+	return !iterator.Next(), iterator.Error()
+}
@@ -54,6 +54,12 @@ type Manager interface {
 	// databases has the nested prefix [prefix] applied to it.
 	NewNestedPrefixDBManager(prefix []byte) Manager
 
+	// NewTrackedPrefixDBManager returns a new database manager with each of
+	// its databases prefixed with [prefix]. [onPrefix] is called with the path
+	// of the current database, and of every database created from it, as
+	// described by prefixdb.NewTracked.
+	NewTrackedPrefixDBManager(prefix []byte, onPrefix func(path [][]byte)) Manager
+
 	// NewMeterDBManager returns a new database manager with each of its
 	// databases wrapped with a meterdb instance to support metrics on database
 	// performance.
@@ -271,6 +277,28 @@ func (m *manager) NewNestedPrefixDBManager(prefix []byte) Manager {
 	return m
 }
 
+// NewTrackedPrefixDBManager creates a new manager with each database instance
+// prefixed by [prefix] and reports the paths of the databases created from the
+// current database to [onPrefix]
+func (m *manager) NewTrackedPrefixDBManager(prefix []byte, onPrefix func(path [][]byte)) Manager {
+	newManager := &manager{
+		databases: make([]*VersionedDatabase, len(m.databases)),
+	}
+	for i, vdb := range m.databases {
+		var db database.Database
+		if i == 0 {
+			db = prefixdb.NewTracked(prefix, vdb.Database, onPrefix)
+		} else {
+			db = prefixdb.New(prefix, vdb.Database)
+		}
+		newManager.databases[i] = &VersionedDatabase{
+			Database: db,
+			Version:  vdb.Version,
+		}
+	}
+	return newManager
+}
+
 // NewMeterDBManager wraps the current database instance with a meterdb instance.
 // Note: calling this more than once with the same [namespace] will cause a conflict error for the [registerer]
 func (m *manager) NewMeterDBManager(namespace string, registerer prometheus.Registerer) (Manager, error) {
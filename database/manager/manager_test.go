@@ -253,6 +253,55 @@ func TestNestedPrefixDBManager(t *testing.T) {
 	assert.Equal(t, v1, val)
 }
 
+func TestTrackedPrefixDBManager(t *testing.T) {
+	db := memdb.New()
+
+	prefix0 := []byte{0}
+	db0 := prefixdb.New(prefix0, db)
+
+	prefix1 := []byte{1}
+	db1 := prefixdb.New(prefix1, db0)
+
+	k0 := []byte{'s', 'c', 'h', 'n', 'i'}
+	v0 := []byte{'t', 'z', 'e', 'l'}
+	k1 := []byte{'c', 'u', 'r', 'r', 'y'}
+	v1 := []byte{'w', 'u', 'r', 's', 't'}
+
+	assert.NoError(t, db0.Put(k0, v0))
+	assert.NoError(t, db1.Put(k1, v1))
+	assert.NoError(t, db0.Close())
+	assert.NoError(t, db1.Close())
+
+	m := &manager{databases: []*VersionedDatabase{
+		{
+			Database: db,
+			Version:  version.DefaultVersion1_0_0,
+		},
+	}}
+
+	var prefixes [][]byte
+	m0 := m.NewTrackedPrefixDBManager(prefix0, func(path [][]byte) {
+		prefixes = append(prefixes, prefixdb.MakePrefix(append([][]byte{prefix0}, path...)...))
+	})
+	m1 := m0.NewPrefixDBManager(prefix1)
+	assert.Len(t, prefixes, 2)
+
+	val, err := m0.Current().Database.Get(k0)
+	assert.NoError(t, err)
+	assert.Equal(t, v0, val)
+
+	val, err = m1.Current().Database.Get(k1)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, val)
+
+	for i, prefix := range prefixes {
+		it := db.NewIteratorWithPrefix(prefix)
+		assert.True(t, it.Next())
+		assert.Equal(t, [][]byte{v0, v1}[i], it.Value())
+		it.Release()
+	}
+}
+
 func TestMeterDBManager(t *testing.T) {
 	registry := prometheus.NewRegistry()
 
@@ -173,6 +173,22 @@ func (_m *Manager) NewPrefixDBManager(prefix []byte) manager.Manager {
 	return r0
 }
 
+// NewTrackedPrefixDBManager provides a mock function with given fields: prefix, onPrefix
+func (_m *Manager) NewTrackedPrefixDBManager(prefix []byte, onPrefix func([][]byte)) manager.Manager {
+	ret := _m.Called(prefix, onPrefix)
+
+	var r0 manager.Manager
+	if rf, ok := ret.Get(0).(func([]byte, func([][]byte)) manager.Manager); ok {
+		r0 = rf(prefix, onPrefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(manager.Manager)
+		}
+	}
+
+	return r0
+}
+
 // Previous provides a mock function with given fields:
 func (_m *Manager) Previous() (*manager.VersionedDatabase, bool) {
 	ret := _m.Called()
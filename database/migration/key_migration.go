@@ -0,0 +1,74 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	errNoKeyRange = errors.New("either a partition or a prefix must be provided")
+
+	_ Migration = &KeyMigration{}
+)
+
+// KeyMigration is a migration that rewrites, one at a time, every key in a
+// range of the database
+type KeyMigration struct {
+	// If non-empty, the keys are read from the prefixdb partition of the
+	// database with this prefix
+	Partition []byte
+	// Only keys with this prefix are migrated
+	Prefix []byte
+	// Migrate is called with every key in the range, in order. It writes the
+	// new form of [key] and [value] to [db], which is the partition the key was
+	// read from. Any key written to [db] that is in the migrated range must
+	// sort before [key], otherwise it will be migrated again.
+	Migrate func(db database.KeyValueWriter, key, value []byte) error
+}
+
+// Step implements the Migration interface
+func (m *KeyMigration) Step(db database.Database, cursor []byte, limit int) ([]byte, error) {
+	if len(m.Partition) == 0 && len(m.Prefix) == 0 {
+		// Without a key range, the migrator's own keys would be migrated
+		return nil, errNoKeyRange
+	}
+	if len(m.Partition) > 0 {
+		db = prefixdb.New(m.Partition, db)
+	}
+
+	// The smallest key greater than [cursor]
+	var start []byte
+	if len(cursor) > 0 {
+		start = make([]byte, len(cursor)+1)
+		copy(start, cursor)
+	}
+
+	it := db.NewIteratorWithStartAndPrefix(start, m.Prefix)
+	defer it.Release()
+
+	var (
+		lastKey  []byte
+		migrated int
+	)
+	for migrated < limit && it.Next() {
+		lastKey = utils.CopyBytes(it.Key())
+		if err := m.Migrate(db, lastKey, utils.CopyBytes(it.Value())); err != nil {
+			return nil, err
+		}
+		migrated++
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	// If fewer than [limit] keys were migrated, the range is exhausted
+	if migrated < limit {
+		return nil, nil
+	}
+	return lastKey, nil
+}
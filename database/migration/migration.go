@@ -0,0 +1,232 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// DefaultStepSize is the default max number of keys migrated by a single
+	// step of a migration
+	DefaultStepSize = 1024
+)
+
+var (
+	// ErrShutdown is returned by Dispatch if the migrator was shutdown before
+	// the database reached the latest schema version
+	ErrShutdown = errors.New("migrator was shutdown")
+
+	schemaPrefix = []byte("schema")
+	versionKey   = []byte("version")
+	cursorKey    = []byte("cursor")
+
+	errUnknownVersion = errors.New("database schema version is newer than the supported version")
+)
+
+// Migratable is implemented by VMs whose database layout is versioned. The
+// chain manager creates a migrator of the VM's database before initializing
+// the VM, and applies the migrations in the background once the chain runs.
+// Until then, the VM must handle data in both the old and the new layouts.
+type Migratable interface {
+	// Migrations returns the migrations of the VM's database. Migration [i]
+	// upgrades the database from schema version [i] to version [i+1].
+	Migrations() []Migration
+}
+
+// Migration upgrades the layout of a database from one schema version to the
+// next. A migration is made of steps, each of which migrates a bounded number
+// of keys, so that a migration can run in the background and be resumed after
+// a restart.
+type Migration interface {
+	// Step migrates at most [limit] keys, starting after [cursor], by writing
+	// to [db]. An empty [cursor] means the migration is starting. Returns the
+	// cursor to resume from or nil if the migration is complete. All the writes
+	// made to [db] are committed atomically with the returned cursor.
+	Step(db database.Database, cursor []byte, limit int) ([]byte, error)
+}
+
+// Migrator tracks the schema version of a database and applies the migrations
+// needed to bring the database to the latest schema version.
+//
+// Migration [i] upgrades the schema from version [i] to version [i+1], so the
+// latest schema version is the number of migrations. The version and the
+// progress of the ongoing migration are persisted in the database, under keys
+// partitioned from the rest of the database.
+type Migrator struct {
+	log        logging.Logger
+	migrations []Migration
+	stepSize   int
+
+	// Held while a step is being applied. Should be held by anyone writing to
+	// the database.
+	lock sync.Locker
+
+	db       *versiondb.Database
+	schemaDB database.Database
+
+	// Protects [version]
+	versionLock sync.RWMutex
+	version     uint64
+	cursor      []byte
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// New returns a migrator of [db]. If [db] has never had its schema version
+// recorded, it is assumed to be at version 0 unless it is empty, in which case
+// it is marked as being at the latest version. Therefore, the migrator must be
+// created before anything else is written to a new database.
+func New(
+	db database.Database,
+	lock sync.Locker,
+	log logging.Logger,
+	stepSize int,
+	migrations []Migration,
+) (*Migrator, error) {
+	vdb := versiondb.New(db)
+	m := &Migrator{
+		log:        log,
+		migrations: migrations,
+		stepSize:   stepSize,
+		lock:       lock,
+		db:         vdb,
+		schemaDB:   prefixdb.New(schemaPrefix, vdb),
+		closer:     make(chan struct{}),
+	}
+
+	version, err := database.GetUInt64(m.schemaDB, versionKey)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		// If [db] isn't initialized, there is nothing to migrate
+		isEmpty, err := database.IsEmpty(db)
+		if err != nil {
+			return nil, err
+		}
+		if !isEmpty {
+			version = 0
+		} else {
+			version = uint64(len(migrations))
+		}
+		if err := database.PutUInt64(m.schemaDB, versionKey, version); err != nil {
+			return nil, err
+		}
+		if err := m.db.Commit(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	if version > uint64(len(migrations)) {
+		return nil, fmt.Errorf("%w: %d > %d", errUnknownVersion, version, len(migrations))
+	}
+	m.version = version
+
+	cursor, err := m.schemaDB.Get(cursorKey)
+	switch err {
+	case nil:
+		m.cursor = cursor
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+	return m, nil
+}
+
+// Version returns the schema version the database is currently at. Data
+// written by the ongoing migration, if any, may be in either layout.
+func (m *Migrator) Version() uint64 {
+	m.versionLock.RLock()
+	defer m.versionLock.RUnlock()
+
+	return m.version
+}
+
+// Done returns true if the database is at the latest schema version
+func (m *Migrator) Done() bool {
+	return m.Version() == uint64(len(m.migrations))
+}
+
+// Dispatch applies the pending migrations. Returns once the database is at the
+// latest schema version, a migration fails, or the migrator is shutdown.
+func (m *Migrator) Dispatch() error {
+	for !m.Done() {
+		select {
+		case <-m.closer:
+			return ErrShutdown
+		default:
+		}
+
+		if err := m.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown stops Dispatch after the current step has been applied. Once
+// Shutdown returns, no step starts, even if Dispatch was waiting on the lock.
+func (m *Migrator) Shutdown() {
+	close(m.closer)
+}
+
+// step applies the next step of the ongoing migration and persists the
+// progress
+func (m *Migrator) step() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// The migrator may have been shutdown while waiting for the lock
+	select {
+	case <-m.closer:
+		return ErrShutdown
+	default:
+	}
+	defer m.db.Abort()
+
+	version := m.Version()
+	if len(m.cursor) == 0 {
+		m.log.Info("starting database migration from schema version %d to %d", version, version+1)
+	}
+
+	cursor, err := m.migrations[version].Step(m.db, m.cursor, m.stepSize)
+	if err != nil {
+		return fmt.Errorf("migration to schema version %d failed: %w", version+1, err)
+	}
+
+	nextVersion := version
+	if cursor == nil {
+		nextVersion++
+		if err := database.PutUInt64(m.schemaDB, versionKey, nextVersion); err != nil {
+			return err
+		}
+		if err := m.schemaDB.Delete(cursorKey); err != nil {
+			return err
+		}
+	} else if err := m.schemaDB.Put(cursorKey, cursor); err != nil {
+		return err
+	}
+	if err := m.db.Commit(); err != nil {
+		return err
+	}
+
+	m.cursor = cursor
+	if nextVersion != version {
+		m.versionLock.Lock()
+		m.version = nextVersion
+		m.versionLock.Unlock()
+
+		m.log.Info("finished database migration to schema version %d", nextVersion)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var partition = []byte("partition")
+
+// shortenPrefix replaces the "long-prefix-" prefix of keys with "s-"
+var shortenPrefix = &KeyMigration{
+	Partition: partition,
+	Prefix:    []byte("long-prefix-"),
+	Migrate: func(db database.KeyValueWriter, key, value []byte) error {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+		newKey := append([]byte("s-"), key[len("long-prefix-"):]...)
+		return db.Put(newKey, value)
+	},
+}
+
+func TestMigratorNewDatabase(t *testing.T) {
+	db := memdb.New()
+	m, err := New(db, &sync.Mutex{}, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+
+	// An empty database is already at the latest version
+	assert.EqualValues(t, 1, m.Version())
+	assert.True(t, m.Done())
+	assert.NoError(t, m.Dispatch())
+}
+
+func TestMigratorMigrates(t *testing.T) {
+	db := memdb.New()
+	partitionDB := prefixdb.New(partition, db)
+	for i := 0; i < 5; i++ {
+		key := []byte(fmt.Sprintf("long-prefix-%d", i))
+		assert.NoError(t, partitionDB.Put(key, []byte{byte(i)}))
+	}
+	assert.NoError(t, partitionDB.Put([]byte("other"), []byte{5}))
+
+	m, err := New(db, &sync.Mutex{}, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, m.Version())
+	assert.False(t, m.Done())
+
+	// Apply a single step, which should persist the progress
+	assert.NoError(t, m.step())
+	assert.EqualValues(t, 0, m.Version())
+
+	// Progress is resumed after a restart
+	m, err = New(db, &sync.Mutex{}, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("long-prefix-1"), m.cursor)
+
+	assert.NoError(t, m.Dispatch())
+	assert.EqualValues(t, 1, m.Version())
+	assert.True(t, m.Done())
+
+	for i := 0; i < 5; i++ {
+		has, err := partitionDB.Has([]byte(fmt.Sprintf("long-prefix-%d", i)))
+		assert.NoError(t, err)
+		assert.False(t, has)
+
+		value, err := partitionDB.Get([]byte(fmt.Sprintf("s-%d", i)))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{byte(i)}, value)
+	}
+	value, err := partitionDB.Get([]byte("other"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{5}, value)
+
+	// The version is persisted
+	m, err = New(db, &sync.Mutex{}, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+	assert.True(t, m.Done())
+}
+
+func TestMigratorUnknownVersion(t *testing.T) {
+	db := memdb.New()
+	_, err := New(db, &sync.Mutex{}, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+
+	_, err = New(db, &sync.Mutex{}, logging.NoLog{}, 2, nil)
+	assert.Error(t, err)
+}
+
+func TestMigratorShutdown(t *testing.T) {
+	db := memdb.New()
+	assert.NoError(t, prefixdb.New(partition, db).Put([]byte("long-prefix-0"), nil))
+
+	m, err := New(db, &sync.Mutex{}, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+
+	m.Shutdown()
+	assert.Error(t, m.Dispatch())
+	assert.False(t, m.Done())
+}
+
+func TestMigratorShutdownWhileWaitingForLock(t *testing.T) {
+	db := memdb.New()
+	assert.NoError(t, prefixdb.New(partition, db).Put([]byte("long-prefix-0"), nil))
+
+	lock := &sync.Mutex{}
+	m, err := New(db, lock, logging.NoLog{}, 2, []Migration{shortenPrefix})
+	assert.NoError(t, err)
+
+	lock.Lock()
+	done := make(chan error)
+	go func() { done <- m.Dispatch() }()
+	m.Shutdown()
+	lock.Unlock()
+
+	assert.ErrorIs(t, <-done, ErrShutdown)
+	assert.False(t, m.Done())
+	has, err := prefixdb.New(partition, db).Has([]byte("long-prefix-0"))
+	assert.NoError(t, err)
+	assert.True(t, has)
+}
+
+func TestKeyMigrationNoRange(t *testing.T) {
+	m := &KeyMigration{}
+	_, err := m.Step(memdb.New(), nil, 1)
+	assert.Error(t, err)
+}
@@ -29,6 +29,12 @@ type Database struct {
 	dbPrefix []byte
 	// The underlying storage
 	db database.Database
+	// If non-nil, called with the path of each database compressed from this
+	// one by New
+	onPrefix func(path [][]byte)
+	// Prefixes passed to New, in order, to create this database from the
+	// tracked database it descends from
+	path [][]byte
 	// Holds unused []byte
 	bufferPool sync.Pool
 }
@@ -39,11 +45,48 @@ func New(prefix []byte, db database.Database) *Database {
 		simplePrefix := make([]byte, len(prefixDB.dbPrefix)+len(prefix))
 		copy(simplePrefix, prefixDB.dbPrefix)
 		copy(simplePrefix[len(prefixDB.dbPrefix):], prefix)
-		return NewNested(simplePrefix, prefixDB.db)
+		newDB := NewNested(simplePrefix, prefixDB.db)
+		newDB.onPrefix = prefixDB.onPrefix
+		if newDB.onPrefix != nil {
+			newDB.path = make([][]byte, len(prefixDB.path)+1)
+			copy(newDB.path, prefixDB.path)
+			newDB.path[len(prefixDB.path)] = append([]byte(nil), prefix...)
+			newDB.onPrefix(newDB.path)
+		}
+		return newDB
 	}
 	return NewNested(prefix, db)
 }
 
+// NewTracked returns a new prefixed database that calls [onPrefix] with the
+// path of every database later created from the returned database, directly
+// or not, by New. The path of a database is the list of prefixes passed to New
+// to create it from the returned database. [onPrefix] is also called with the
+// empty path of the returned database. If [db] isn't a prefixed database, the
+// keys of the database at a path are stored in [db] under
+// MakePrefix(prefix, path...), so this allows finding all the keys written
+// through the returned database.
+func NewTracked(prefix []byte, db database.Database, onPrefix func(path [][]byte)) *Database {
+	newDB := New(prefix, db)
+	newDB.onPrefix = onPrefix
+	onPrefix([][]byte{})
+	return newDB
+}
+
+// MakePrefix returns the prefix that the keys of a database are stored under
+// in [db] if the database is created by calling New with each of [prefixes] in
+// turn, starting with [db]. [db] must not be a prefixed database.
+func MakePrefix(prefixes ...[]byte) []byte {
+	var dbPrefix []byte
+	for _, prefix := range prefixes {
+		simplePrefix := make([]byte, len(dbPrefix)+len(prefix))
+		copy(simplePrefix, dbPrefix)
+		copy(simplePrefix[len(dbPrefix):], prefix)
+		dbPrefix = hashing.ComputeHash256(simplePrefix)
+	}
+	return dbPrefix
+}
+
 // NewNested returns a new prefixed database without attempting to compress
 // prefixes.
 func NewNested(prefix []byte, db database.Database) *Database {
@@ -22,6 +22,45 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestTrackedPrefixes(t *testing.T) {
+	db := memdb.New()
+
+	var prefixes [][]byte
+	root := NewTracked([]byte("root"), db, func(path [][]byte) {
+		prefixes = append(prefixes, MakePrefix(append([][]byte{[]byte("root")}, path...)...))
+	})
+	child := New([]byte("child"), root)
+	grandchild := New([]byte("grandchild"), child)
+	nested := NewNested([]byte("nested"), root)
+	untracked := New([]byte("untracked"), db)
+
+	if len(prefixes) != 3 {
+		t.Fatalf("expected 3 tracked prefixes but got %d", len(prefixes))
+	}
+
+	for i, prefixDB := range []database.Database{root, child, grandchild, nested, untracked} {
+		if err := prefixDB.Put([]byte{byte(i)}, []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	numKeys := 0
+	for _, prefix := range prefixes {
+		it := db.NewIteratorWithPrefix(prefix)
+		for it.Next() {
+			numKeys++
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// All keys other than the untracked one are stored under a tracked prefix
+	if numKeys != 4 {
+		t.Fatalf("expected 4 keys under the tracked prefixes but found %d", numKeys)
+	}
+}
+
 func BenchmarkInterface(b *testing.B) {
 	for _, size := range database.BenchmarkSizes {
 		keys, values := database.SetupBenchmark(b, size, size)
@@ -0,0 +1,380 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshotdb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	errReadOnly = errors.New("snapshot is read only")
+
+	_ database.Database = &Database{}
+	_ database.Database = &Snapshot{}
+	_ database.Batch    = &batch{}
+)
+
+// Database implements the Database interface by living on top of another
+// database. Before a key is overwritten in the underlying database, its
+// previous value is preserved in every open snapshot. This allows a snapshot to
+// serve the state of the database at the time the snapshot was taken, no matter
+// how many writes happened since.
+//
+// All writes to the underlying database must be made through this database.
+type Database struct {
+	lock      sync.RWMutex
+	db        database.Database
+	snapshots map[*Snapshot]struct{}
+}
+
+// New returns a new snapshot database
+func New(db database.Database) *Database {
+	return &Database{
+		db:        db,
+		snapshots: make(map[*Snapshot]struct{}),
+	}
+}
+
+// Has implements the database.Database interface
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, database.ErrClosed
+	}
+	return db.db.Has(key)
+}
+
+// Get implements the database.Database interface
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	return db.db.Get(key)
+}
+
+// Put implements the database.Database interface
+func (db *Database) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	if err := db.preserve(key); err != nil {
+		return err
+	}
+	return db.db.Put(key, value)
+}
+
+// Delete implements the database.Database interface
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	if err := db.preserve(key); err != nil {
+		return err
+	}
+	return db.db.Delete(key)
+}
+
+// NewBatch implements the database.Database interface
+func (db *Database) NewBatch() database.Batch {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return &nodb.Batch{}
+	}
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+// NewIterator implements the database.Database interface
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the database.Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the database.Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the database.Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return db.db.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Stat implements the database.Database interface
+func (db *Database) Stat(stat string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return "", database.ErrClosed
+	}
+	return db.db.Stat(stat)
+}
+
+// Compact implements the database.Database interface
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+// Close implements the database.Database interface. Any open snapshots are
+// closed as well.
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	for snapshot := range db.snapshots {
+		_ = snapshot.overlay.Close()
+	}
+	db.snapshots = nil
+	db.db = nil
+	return nil
+}
+
+// NewSnapshot returns a read only view of the current state of this database.
+// The snapshot must be closed once it is no longer needed, as every write to
+// this database grows the snapshot until then.
+func (db *Database) NewSnapshot() (*Snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snapshot := &Snapshot{
+		db:        db,
+		preserved: make(map[string]struct{}),
+		overlay:   versiondb.New(db.db),
+	}
+	db.snapshots[snapshot] = struct{}{}
+	return snapshot, nil
+}
+
+// preserve the current value of [key] in every open snapshot that doesn't
+// already hold an older value of [key].
+// Assumes [db.lock] is held.
+func (db *Database) preserve(key []byte) error {
+	var (
+		fetched bool
+		value   []byte
+		exists  bool
+	)
+	for snapshot := range db.snapshots {
+		if _, preserved := snapshot.preserved[string(key)]; preserved {
+			continue
+		}
+		if !fetched {
+			var err error
+			value, err = db.db.Get(key)
+			switch err {
+			case nil:
+				exists = true
+			case database.ErrNotFound:
+			default:
+				return err
+			}
+			fetched = true
+		}
+
+		snapshot.preserved[string(key)] = struct{}{}
+		var err error
+		if exists {
+			err = snapshot.overlay.Put(utils.CopyBytes(key), value)
+		} else {
+			err = snapshot.overlay.Delete(utils.CopyBytes(key))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshot is a read only view of a Database at the time the snapshot was
+// taken. Writes to a snapshot always fail.
+type Snapshot struct {
+	db *Database
+	// Keys that have been written to [db] since this snapshot was taken
+	preserved map[string]struct{}
+	// Holds the values of [preserved] at the time this snapshot was taken, on
+	// top of the underlying database
+	overlay *versiondb.Database
+}
+
+// Has implements the database.Database interface
+func (s *Snapshot) Has(key []byte) (bool, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.overlay.Has(key)
+}
+
+// Get implements the database.Database interface
+func (s *Snapshot) Get(key []byte) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.overlay.Get(key)
+}
+
+// Put implements the database.Database interface
+func (s *Snapshot) Put(key, value []byte) error { return errReadOnly }
+
+// Delete implements the database.Database interface
+func (s *Snapshot) Delete(key []byte) error { return errReadOnly }
+
+// NewBatch implements the database.Database interface
+func (s *Snapshot) NewBatch() database.Batch { return &nodb.Batch{} }
+
+// NewIterator implements the database.Database interface
+func (s *Snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the database.Database interface
+func (s *Snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the database.Database interface
+func (s *Snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the database.Database interface.
+// The underlying database must not be written to while the returned iterator
+// is in use.
+func (s *Snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.overlay.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Stat implements the database.Database interface
+func (s *Snapshot) Stat(stat string) (string, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.overlay.Stat(stat)
+}
+
+// Compact implements the database.Database interface
+func (s *Snapshot) Compact(start, limit []byte) error { return errReadOnly }
+
+// Close releases this snapshot
+func (s *Snapshot) Close() error {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	if _, open := s.db.snapshots[s]; !open {
+		return database.ErrClosed
+	}
+	delete(s.db.snapshots, s)
+	return s.overlay.Close()
+}
+
+type batch struct {
+	database.Batch
+	db *Database
+
+	// Keys written to in this batch
+	keys [][]byte
+}
+
+// Put implements the Batch interface
+func (b *batch) Put(key, value []byte) error {
+	b.keys = append(b.keys, utils.CopyBytes(key))
+	return b.Batch.Put(key, value)
+}
+
+// Delete implements the Batch interface
+func (b *batch) Delete(key []byte) error {
+	b.keys = append(b.keys, utils.CopyBytes(key))
+	return b.Batch.Delete(key)
+}
+
+// Write implements the Batch interface
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if b.db.db == nil {
+		return database.ErrClosed
+	}
+	if err := b.preserve(); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+// Reset implements the Batch interface
+func (b *batch) Reset() {
+	b.keys = b.keys[:0]
+	b.Batch.Reset()
+}
+
+// Inner returns the inner batch. Because the returned batch may be written
+// without going through this batch, the values being overwritten by this batch
+// are preserved before it is returned.
+func (b *batch) Inner() database.Batch {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if b.db.db != nil {
+		// Preserving can only fail if the underlying database fails to read,
+		// in which case writing the inner batch will fail as well.
+		_ = b.preserve()
+	}
+	return b.Batch.Inner()
+}
+
+// Assumes [b.db.lock] is held
+func (b *batch) preserve() error {
+	for _, key := range b.keys {
+		if err := b.db.preserve(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
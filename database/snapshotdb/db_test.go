@@ -0,0 +1,100 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshotdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		test(t, New(memdb.New()))
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	db := New(memdb.New())
+
+	assert.NoError(t, db.Put([]byte("a"), []byte("1")))
+	assert.NoError(t, db.Put([]byte("b"), []byte("2")))
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Put([]byte("a"), []byte("3")))
+	assert.NoError(t, db.Put([]byte("a"), []byte("4")))
+	assert.NoError(t, db.Delete([]byte("b")))
+	assert.NoError(t, db.Put([]byte("c"), []byte("5")))
+
+	value, err := snapshot.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	value, err = snapshot.Get([]byte("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("2"), value)
+
+	has, err := snapshot.Has([]byte("c"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	it := snapshot.NewIterator()
+	keys := [][]byte(nil)
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	assert.NoError(t, it.Error())
+	it.Release()
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, keys)
+
+	value, err = db.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("4"), value)
+
+	assert.Error(t, snapshot.Put([]byte("a"), []byte("5")))
+	assert.NoError(t, snapshot.Close())
+	assert.Error(t, snapshot.Close())
+
+	_, err = snapshot.Get([]byte("a"))
+	assert.Equal(t, database.ErrClosed, err)
+}
+
+func TestSnapshotBatch(t *testing.T) {
+	db := New(memdb.New())
+
+	assert.NoError(t, db.Put([]byte("a"), []byte("1")))
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(t, err)
+
+	batch := db.NewBatch()
+	assert.NoError(t, batch.Put([]byte("a"), []byte("2")))
+	assert.NoError(t, batch.Put([]byte("b"), []byte("3")))
+	assert.NoError(t, batch.Write())
+
+	value, err := snapshot.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	_, err = snapshot.Get([]byte("b"))
+	assert.Equal(t, database.ErrNotFound, err)
+
+	// Writing the inner batch directly must also be hidden from the snapshot
+	batch = db.NewBatch()
+	assert.NoError(t, batch.Delete([]byte("a")))
+	assert.NoError(t, batch.Inner().Write())
+
+	value, err = snapshot.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	has, err := db.Has([]byte("a"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
@@ -81,6 +81,11 @@ type Config struct {
 	InitialStakedFunds         []ids.ShortID `json:"initialStakedFunds"`
 	InitialStakers             []Staker      `json:"initialStakers"`
 
+	// Unix time, in seconds, of the transition from epoch 0 to epoch 1
+	EpochFirstTransition uint64 `json:"epochFirstTransition"`
+	// Duration of each epoch, in seconds
+	EpochDuration uint64 `json:"epochDuration"`
+
 	CChainGenesis string `json:"cChainGenesis"`
 
 	Message string `json:"message"`
@@ -96,6 +101,8 @@ func (c Config) Unparse() (UnparsedConfig, error) {
 		InitialStakeDurationOffset: c.InitialStakeDurationOffset,
 		InitialStakedFunds:         make([]string, len(c.InitialStakedFunds)),
 		InitialStakers:             make([]UnparsedStaker, len(c.InitialStakers)),
+		EpochFirstTransition:       c.EpochFirstTransition,
+		EpochDuration:              c.EpochDuration,
 		CChainGenesis:              c.CChainGenesis,
 		Message:                    c.Message,
 	}
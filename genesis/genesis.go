@@ -153,6 +153,16 @@ func validateConfig(networkID uint32, config *Config) error {
 		return errors.New("C-Chain genesis cannot be empty")
 	}
 
+	if config.EpochFirstTransition > math.MaxInt64 {
+		return fmt.Errorf("epoch first transition %d is too large", config.EpochFirstTransition)
+	}
+	switch {
+	case config.EpochDuration == 0:
+		return errors.New("epoch duration must be > 0")
+	case config.EpochDuration > uint64(math.MaxInt64/time.Second):
+		return fmt.Errorf("epoch duration %d is too large", config.EpochDuration)
+	}
+
 	return nil
 }
 
@@ -176,11 +186,22 @@ func validateConfig(networkID uint32, config *Config) error {
 //    (ie the genesis state of the network)
 // 2) The asset ID of AVAX
 func Genesis(networkID uint32, filepath string) ([]byte, ids.ID, error) {
+	config, err := LoadConfig(networkID, filepath)
+	if err != nil {
+		return nil, ids.ID{}, err
+	}
+	return FromConfig(config)
+}
+
+// LoadConfig returns the validated genesis config of the network. If
+// [filepath] is empty or the given network ID is Mainnet, Testnet, or Local,
+// returns the predefined config. Otherwise, loads the config at [filepath].
+func LoadConfig(networkID uint32, filepath string) (*Config, error) {
 	config := GetConfig(networkID)
 	if len(filepath) > 0 {
 		switch networkID {
 		case constants.MainnetID, constants.TestnetID, constants.LocalID:
-			return nil, ids.ID{}, fmt.Errorf(
+			return nil, fmt.Errorf(
 				"cannot override genesis config for standard network %s (%d)",
 				constants.NetworkName(networkID),
 				networkID,
@@ -189,17 +210,16 @@ func Genesis(networkID uint32, filepath string) ([]byte, ids.ID, error) {
 
 		customConfig, err := GetConfigFile(filepath)
 		if err != nil {
-			return nil, ids.ID{}, fmt.Errorf("unable to load provided genesis config at %s: %w", filepath, err)
+			return nil, fmt.Errorf("unable to load provided genesis config at %s: %w", filepath, err)
 		}
 
 		config = customConfig
 	}
 
 	if err := validateConfig(networkID, config); err != nil {
-		return nil, ids.ID{}, fmt.Errorf("genesis config validation failed: %w", err)
+		return nil, fmt.Errorf("genesis config validation failed: %w", err)
 	}
-
-	return FromConfig(config)
+	return config, nil
 }
 
 // FromConfig returns:
@@ -87,6 +87,8 @@ var (
 		"startTime": 1599696000,
 		"initialStakeDuration": 31536000,
 		"initialStakeDurationOffset": 54000,
+		"epochFirstTransition": 1607626800,
+		"epochDuration": 21600,
 		"initialStakedFunds": [
 			"X-fuji1wycv8n7d2fg9aq6unp23pnj4q0arv03ysya8jw"
 		],
@@ -198,17 +200,15 @@ var (
 
 	// FujiParams are the params used for the fuji testnet
 	FujiParams = Params{
-		TxFee:                units.MilliAvax,
-		CreationTxFee:        10 * units.MilliAvax,
-		UptimeRequirement:    .6, // 60%
-		MinValidatorStake:    1 * units.Avax,
-		MaxValidatorStake:    3 * units.MegaAvax,
-		MinDelegatorStake:    1 * units.Avax,
-		MinDelegationFee:     20000, // 2%
-		MinStakeDuration:     24 * time.Hour,
-		MaxStakeDuration:     365 * 24 * time.Hour,
-		StakeMintingPeriod:   365 * 24 * time.Hour,
-		EpochFirstTransition: time.Unix(1607626800, 0),
-		EpochDuration:        6 * time.Hour,
+		TxFee:              units.MilliAvax,
+		CreationTxFee:      10 * units.MilliAvax,
+		UptimeRequirement:  .6, // 60%
+		MinValidatorStake:  1 * units.Avax,
+		MaxValidatorStake:  3 * units.MegaAvax,
+		MinDelegatorStake:  1 * units.Avax,
+		MinDelegationFee:   20000, // 2%
+		MinStakeDuration:   24 * time.Hour,
+		MaxStakeDuration:   365 * 24 * time.Hour,
+		StakeMintingPeriod: 365 * 24 * time.Hour,
 	}
 )
@@ -57,6 +57,8 @@ var (
 		"startTime": 1599696000,
 		"initialStakeDuration": 31536000,
 		"initialStakeDurationOffset": 5400,
+		"epochFirstTransition": 1607626800,
+		"epochDuration": 21600,
 		"initialStakedFunds": [
 			"X-local1g65uqn6t77p656w64023nh8nd9updzmxyymev2"
 		],
@@ -93,17 +95,15 @@ var (
 
 	// LocalParams are the params used for local networks
 	LocalParams = Params{
-		TxFee:                units.MilliAvax,
-		CreationTxFee:        10 * units.MilliAvax,
-		UptimeRequirement:    .6, // 60%
-		MinValidatorStake:    1 * units.Avax,
-		MaxValidatorStake:    3 * units.MegaAvax,
-		MinDelegatorStake:    1 * units.Avax,
-		MinDelegationFee:     20000, // 2%
-		MinStakeDuration:     24 * time.Hour,
-		MaxStakeDuration:     365 * 24 * time.Hour,
-		StakeMintingPeriod:   365 * 24 * time.Hour,
-		EpochFirstTransition: time.Unix(1607626800, 0),
-		EpochDuration:        5 * time.Minute,
+		TxFee:              units.MilliAvax,
+		CreationTxFee:      10 * units.MilliAvax,
+		UptimeRequirement:  .6, // 60%
+		MinValidatorStake:  1 * units.Avax,
+		MaxValidatorStake:  3 * units.MegaAvax,
+		MinDelegatorStake:  1 * units.Avax,
+		MinDelegationFee:   20000, // 2%
+		MinStakeDuration:   24 * time.Hour,
+		MaxStakeDuration:   365 * 24 * time.Hour,
+		StakeMintingPeriod: 365 * 24 * time.Hour,
 	}
 )
@@ -174232,6 +174232,8 @@ var (
 		"startTime": 1599696000,
 		"initialStakeDuration": 7776000,
 		"initialStakeDurationOffset": 5400,
+		"epochFirstTransition": 1607626800,
+		"epochDuration": 21600,
 		"initialStakedFunds": [
 			"X-avax192x4xsdygcmcc2hutxfepkuuzme65vaa86c03a",
 			"X-avax1xfx3agw94k3yacupawqqyvsf5wmwyf5674ped9",
@@ -175771,17 +175773,15 @@ var (
 
 	// MainnetParams are the params used for mainnet
 	MainnetParams = Params{
-		TxFee:                units.MilliAvax,
-		CreationTxFee:        10 * units.MilliAvax,
-		UptimeRequirement:    .6, // 60%
-		MinValidatorStake:    2 * units.KiloAvax,
-		MaxValidatorStake:    3 * units.MegaAvax,
-		MinDelegatorStake:    25 * units.Avax,
-		MinDelegationFee:     20000, // 2%
-		MinStakeDuration:     2 * 7 * 24 * time.Hour,
-		MaxStakeDuration:     365 * 24 * time.Hour,
-		StakeMintingPeriod:   365 * 24 * time.Hour,
-		EpochFirstTransition: time.Unix(1607626800, 0),
-		EpochDuration:        6 * time.Hour,
+		TxFee:              units.MilliAvax,
+		CreationTxFee:      10 * units.MilliAvax,
+		UptimeRequirement:  .6, // 60%
+		MinValidatorStake:  2 * units.KiloAvax,
+		MaxValidatorStake:  3 * units.MegaAvax,
+		MinDelegatorStake:  25 * units.Avax,
+		MinDelegationFee:   20000, // 2%
+		MinStakeDuration:   2 * 7 * 24 * time.Hour,
+		MaxStakeDuration:   365 * 24 * time.Hour,
+		StakeMintingPeriod: 365 * 24 * time.Hour,
 	}
 )
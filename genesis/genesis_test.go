@@ -5,6 +5,7 @@ package genesis
 
 import (
 	"fmt"
+	"math"
 	"path/filepath"
 	"testing"
 
@@ -141,6 +142,33 @@ func TestValidateConfig(t *testing.T) {
 			}(),
 			err: "C-Chain genesis cannot be empty",
 		},
+		"no epoch duration": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				thisConfig.EpochDuration = 0
+				return &thisConfig
+			}(),
+			err: "epoch duration must be > 0",
+		},
+		"epoch duration too large": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				thisConfig.EpochDuration = math.MaxUint64
+				return &thisConfig
+			}(),
+			err: "epoch duration 18446744073709551615 is too large",
+		},
+		"epoch first transition too large": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				thisConfig.EpochFirstTransition = math.MaxUint64
+				return &thisConfig
+			}(),
+			err: "epoch first transition 18446744073709551615 is too large",
+		},
 		"empty message": {
 			networkID: 12345,
 			config: func() *Config {
@@ -210,6 +238,8 @@ var (
 		"startTime": 1599696000,
 		"initialStakeDuration": 31536000,
 		"initialStakeDurationOffset": 5400,
+		"epochFirstTransition": 1607626800,
+		"epochDuration": 21600,
 		"initialStakedFunds": [
 			"X-local1g65uqn6t77p656w64023nh8nd9updzmxyymev2"
 		],
@@ -97,6 +97,9 @@ type UnparsedConfig struct {
 	InitialStakedFunds         []string         `json:"initialStakedFunds"`
 	InitialStakers             []UnparsedStaker `json:"initialStakers"`
 
+	EpochFirstTransition uint64 `json:"epochFirstTransition"`
+	EpochDuration        uint64 `json:"epochDuration"`
+
 	CChainGenesis string `json:"cChainGenesis"`
 
 	Message string `json:"message"`
@@ -112,6 +115,8 @@ func (uc UnparsedConfig) Parse() (Config, error) {
 		InitialStakeDurationOffset: uc.InitialStakeDurationOffset,
 		InitialStakedFunds:         make([]ids.ShortID, len(uc.InitialStakedFunds)),
 		InitialStakers:             make([]Staker, len(uc.InitialStakers)),
+		EpochFirstTransition:       uc.EpochFirstTransition,
+		EpochDuration:              uc.EpochDuration,
 		CChainGenesis:              uc.CChainGenesis,
 		Message:                    uc.Message,
 	}
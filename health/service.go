@@ -23,8 +23,15 @@ type Service interface {
 }
 
 // NewService returns a new [Service] where the health checks
-// run every [checkFreq]
-func NewService(checkFreq time.Duration, log logging.Logger, namespace string, registry prometheus.Registerer) (Service, error) {
+// run every [checkFreq]. If non-nil, [onChange] is called whenever a health
+// check starts passing or starts failing.
+func NewService(
+	checkFreq time.Duration,
+	log logging.Logger,
+	namespace string,
+	registry prometheus.Registerer,
+	onChange func(name string, healthy bool),
+) (Service, error) {
 	healthChecker := health.New()
 	metrics, err := newMetrics(log, namespace, registry)
 	if err != nil {
@@ -32,9 +39,10 @@ func NewService(checkFreq time.Duration, log logging.Logger, namespace string, r
 	}
 	// Add the check listener to report when a check changes status.
 	healthChecker.WithCheckListener(&checkListener{
-		log:     log,
-		checks:  make(map[string]bool),
-		metrics: metrics,
+		log:      log,
+		checks:   make(map[string]bool),
+		metrics:  metrics,
+		onChange: onChange,
 	})
 	return &service{
 		Health:    healthChecker,
@@ -89,6 +97,8 @@ type checkListener struct {
 	// checks maps name -> is healthy
 	checks  map[string]bool
 	metrics *metrics
+	// Called when a check starts passing or starts failing. May be nil.
+	onChange func(name string, healthy bool)
 }
 
 func (c *checkListener) OnCheckStarted(name string) {
@@ -130,4 +140,7 @@ func (c *checkListener) OnCheckCompleted(name string, result health.Result) {
 		c.log.Warn("%q became unhealthy with: %s", name, string(resultJSON))
 		c.metrics.unHealthy()
 	}
+	if c.onChange != nil {
+		c.onChange(name, isHealthy)
+	}
 }
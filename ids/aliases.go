@@ -4,23 +4,109 @@
 package ids
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 )
 
+var (
+	errNoAliasDB         = errors.New("aliaser doesn't have a database to persist aliases to")
+	errAliasNotPersisted = errors.New("alias wasn't persisted")
+)
+
+// AliasRecord is an alias of an ID and the component that registered it
+type AliasRecord struct {
+	ID         ID
+	Alias      string
+	Registrant string
+}
+
+// AliasDB persists aliases given to IDs
+type AliasDB interface {
+	// GetAliases returns all of the persisted aliases
+	GetAliases() ([]AliasRecord, error)
+
+	// PutAlias persists [record]
+	PutAlias(record AliasRecord) error
+
+	// DeleteAlias removes [alias] if it is persisted
+	DeleteAlias(alias string) error
+}
+
+// AliasCollisionError is returned when an alias is given to an ID while it is
+// already an alias of another ID
+type AliasCollisionError struct {
+	// The alias that was being registered
+	Requested AliasRecord
+	// The alias that was registered before
+	Existing AliasRecord
+}
+
+func (e *AliasCollisionError) Error() string {
+	return fmt.Sprintf("%s can't be an alias of %s for %s because it is already an alias of %s for %s",
+		e.Requested.Alias,
+		e.Requested.ID,
+		registrantName(e.Requested.Registrant),
+		e.Existing.ID,
+		registrantName(e.Existing.Registrant),
+	)
+}
+
+func registrantName(registrant string) string {
+	if registrant == "" {
+		return "an unknown registrant"
+	}
+	return registrant
+}
+
 // Aliaser allows one to give an ID aliases and lookup the aliases given to an
 // ID. An ID can have arbitrarily many aliases; two IDs may not have the same
-// alias.
+// alias. The aliases of an ID that aren't persisted come before its persisted
+// aliases, so persisting an alias never changes the primary alias of an ID.
 type Aliaser struct {
 	lock    sync.RWMutex
 	dealias map[string]ID
 	aliases map[ID][]string
+	// alias --> the component that registered it
+	registrants map[string]string
+	// Persists aliases given with PersistAlias. May be nil.
+	db AliasDB
+	// Aliases that are persisted in [db]
+	persisted map[string]bool
 }
 
 // Initialize the aliaser to have no aliases
 func (a *Aliaser) Initialize() {
 	a.dealias = make(map[string]ID)
 	a.aliases = make(map[ID][]string)
+	a.registrants = make(map[string]string)
+	a.persisted = make(map[string]bool)
+}
+
+// Load the aliases persisted in [db] and persist aliases given with
+// PersistAlias to [db]. Persisted aliases that collide with aliases of other
+// IDs aren't given and are returned.
+func (a *Aliaser) Load(db AliasDB) ([]*AliasCollisionError, error) {
+	records, err := db.GetAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.db = db
+	collisions := []*AliasCollisionError(nil)
+	for _, record := range records {
+		if id, exists := a.dealias[record.Alias]; !exists || id != record.ID {
+			if err := a.alias(record, true); err != nil {
+				collisions = append(collisions, err)
+				continue
+			}
+		}
+		a.persisted[record.Alias] = true
+	}
+	return collisions, nil
 }
 
 // Lookup returns the ID associated with alias
@@ -42,34 +128,101 @@ func (a *Aliaser) Aliases(id ID) []string {
 	return a.aliases[id]
 }
 
-// PrimaryAlias returns the first alias of [id]
+// PrimaryAlias returns the first alias of [id]. Persisted aliases are never
+// the primary alias, as they may be given before the ID's other aliases.
 func (a *Aliaser) PrimaryAlias(id ID) (string, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
-	aliases, exists := a.aliases[id]
-	if !exists || len(aliases) == 0 {
+	aliases := a.aliases[id]
+	if len(aliases) == 0 || a.persisted[aliases[0]] {
 		return "", fmt.Errorf("there is no alias for ID %s", id)
 	}
 	return aliases[0], nil
 }
 
+// Registrant returns the component that registered [alias]. Returns false if
+// [alias] isn't an alias.
+func (a *Aliaser) Registrant(alias string) (string, bool) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if _, exists := a.dealias[alias]; !exists {
+		return "", false
+	}
+	return a.registrants[alias], true
+}
+
 // Alias gives [id] the alias [alias]
 func (a *Aliaser) Alias(id ID, alias string) error {
+	return a.RegisterAlias(id, alias, "")
+}
+
+// RegisterAlias gives [id] the alias [alias] on behalf of [registrant]. If
+// [alias] is already an alias of another ID, an *AliasCollisionError is
+// returned.
+func (a *Aliaser) RegisterAlias(id ID, alias, registrant string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.alias(AliasRecord{
+		ID:         id,
+		Alias:      alias,
+		Registrant: registrant,
+	}, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PersistAlias gives [id] the alias [alias] on behalf of [registrant] and
+// persists the alias, so it is given again when the aliaser is loaded.
+func (a *Aliaser) PersistAlias(id ID, alias, registrant string) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
-	if _, exists := a.dealias[alias]; exists {
-		return fmt.Errorf("%s is already used as an alias for an ID", alias)
+	if a.db == nil {
+		return errNoAliasDB
 	}
 
-	a.dealias[alias] = id
-	a.aliases[id] = append(a.aliases[id], alias)
+	record := AliasRecord{
+		ID:         id,
+		Alias:      alias,
+		Registrant: registrant,
+	}
+	if err := a.alias(record, true); err != nil {
+		return err
+	}
+	if err := a.db.PutAlias(record); err != nil {
+		a.removeAlias(id, alias)
+		return err
+	}
+	a.persisted[alias] = true
+	return nil
+}
+
+// RemovePersistedAlias removes [alias], which must have been persisted, and
+// deletes it from the database so it isn't given again when the aliaser is
+// loaded
+func (a *Aliaser) RemovePersistedAlias(alias string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.persisted[alias] {
+		return fmt.Errorf("%w: %s", errAliasNotPersisted, alias)
+	}
+	if err := a.db.DeleteAlias(alias); err != nil {
+		return err
+	}
+	delete(a.persisted, alias)
+	if id, exists := a.dealias[alias]; exists {
+		a.removeAlias(id, alias)
+	}
 	return nil
 }
 
 // RemoveAliases of the provided ID
-func (a *Aliaser) RemoveAliases(id ID) {
+func (a *Aliaser) RemoveAliases(id ID) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
@@ -77,5 +230,70 @@ func (a *Aliaser) RemoveAliases(id ID) {
 	delete(a.aliases, id)
 	for _, alias := range aliases {
 		delete(a.dealias, alias)
+		delete(a.registrants, alias)
+	}
+
+	for _, alias := range aliases {
+		if !a.persisted[alias] {
+			continue
+		}
+		delete(a.persisted, alias)
+		if err := a.db.DeleteAlias(alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alias gives [record.ID] the alias [record.Alias]. If the alias isn't
+// [persisted], it's placed before the persisted aliases of the ID.
+// Assumes [a.lock] is held
+func (a *Aliaser) alias(record AliasRecord, persisted bool) *AliasCollisionError {
+	if id, exists := a.dealias[record.Alias]; exists {
+		return &AliasCollisionError{
+			Requested: record,
+			Existing: AliasRecord{
+				ID:         id,
+				Alias:      record.Alias,
+				Registrant: a.registrants[record.Alias],
+			},
+		}
+	}
+
+	oldAliases := a.aliases[record.ID]
+	i := len(oldAliases)
+	if !persisted {
+		for i > 0 && a.persisted[oldAliases[i-1]] {
+			i--
+		}
+	}
+	aliases := make([]string, 0, len(oldAliases)+1)
+	aliases = append(aliases, oldAliases[:i]...)
+	aliases = append(aliases, record.Alias)
+	aliases = append(aliases, oldAliases[i:]...)
+
+	a.dealias[record.Alias] = record.ID
+	a.aliases[record.ID] = aliases
+	a.registrants[record.Alias] = record.Registrant
+	return nil
+}
+
+// removeAlias removes [alias] from the aliases of [id]
+// Assumes [a.lock] is held
+func (a *Aliaser) removeAlias(id ID, alias string) {
+	delete(a.dealias, alias)
+	delete(a.registrants, alias)
+
+	aliases := a.aliases[id]
+	for i, existing := range aliases {
+		if existing == alias {
+			aliases = append(aliases[:i], aliases[i+1:]...)
+			break
+		}
+	}
+	if len(aliases) == 0 {
+		delete(a.aliases, id)
+	} else {
+		a.aliases[id] = aliases
 	}
 }
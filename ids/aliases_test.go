@@ -4,6 +4,7 @@
 package ids
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -136,7 +137,9 @@ func TestAliaserRemoveAlias(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	aliaser.RemoveAliases(id1)
+	if err := aliaser.RemoveAliases(id1); err != nil {
+		t.Fatal(err)
+	}
 
 	_, err := aliaser.PrimaryAlias(id1)
 	if err == nil {
@@ -158,3 +161,189 @@ func TestAliaserRemoveAlias(t *testing.T) {
 		t.Fatalf("Unexpected error: %s when re-assigning removed ID in aliaser", err)
 	}
 }
+
+type testAliasDB map[string]AliasRecord
+
+func (db testAliasDB) GetAliases() ([]AliasRecord, error) {
+	records := []AliasRecord(nil)
+	for _, record := range db {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (db testAliasDB) PutAlias(record AliasRecord) error {
+	db[record.Alias] = record
+	return nil
+}
+
+func (db testAliasDB) DeleteAlias(alias string) error {
+	delete(db, alias)
+	return nil
+}
+
+func TestAliaserCollision(t *testing.T) {
+	id1 := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	id2 := ID{'J', 'a', 'm', 'e', 's', ' ', 'G', 'o', 'r', 'd', 'o', 'n'}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.RegisterAlias(id1, "Batman", "Gotham"); err != nil {
+		t.Fatal(err)
+	}
+	if registrant, ok := aliaser.Registrant("Batman"); !ok || registrant != "Gotham" {
+		t.Fatalf("Registrant should have been Gotham but was %q", registrant)
+	}
+	if _, ok := aliaser.Registrant("Robin"); ok {
+		t.Fatalf("Registrant should have failed for a missing alias")
+	}
+
+	err := aliaser.RegisterAlias(id2, "Batman", "GCPD")
+	collision, ok := err.(*AliasCollisionError)
+	if !ok {
+		t.Fatalf("Expected an alias collision error but got %v", err)
+	}
+	expected := AliasCollisionError{
+		Requested: AliasRecord{
+			ID:         id2,
+			Alias:      "Batman",
+			Registrant: "GCPD",
+		},
+		Existing: AliasRecord{
+			ID:         id1,
+			Alias:      "Batman",
+			Registrant: "Gotham",
+		},
+	}
+	if !reflect.DeepEqual(expected, *collision) {
+		t.Fatalf("Got %#v, expected %#v", *collision, expected)
+	}
+}
+
+func TestAliaserPersistAlias(t *testing.T) {
+	id1 := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	id2 := ID{'J', 'a', 'm', 'e', 's', ' ', 'G', 'o', 'r', 'd', 'o', 'n'}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.PersistAlias(id1, "Batman", "Gotham"); err != errNoAliasDB {
+		t.Fatalf("Expected %s but got %v", errNoAliasDB, err)
+	}
+	if _, err := aliaser.Lookup("Batman"); err == nil {
+		t.Fatalf("Alias shouldn't have been given without a database")
+	}
+
+	db := testAliasDB{}
+	if _, err := aliaser.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.PersistAlias(id1, "Batman", "Gotham"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.PersistAlias(id2, "Commissioner", "Gotham"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(id1, "Dark Knight"); err != nil {
+		t.Fatal(err)
+	}
+	if len(db) != 2 {
+		t.Fatalf("Expected 2 persisted aliases but got %d", len(db))
+	}
+
+	// Persisted aliases that collide with existing aliases aren't given
+	reloaded := Aliaser{}
+	reloaded.Initialize()
+	if err := reloaded.Alias(id2, "Batman"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.Alias(id2, "Commissioner"); err != nil {
+		t.Fatal(err)
+	}
+	collisions, err := reloaded.Load(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collisions) != 1 || collisions[0].Requested.Alias != "Batman" {
+		t.Fatalf("Expected Batman to collide but got %v", collisions)
+	}
+	if aliases := reloaded.Aliases(id2); !reflect.DeepEqual([]string{"Batman", "Commissioner"}, aliases) {
+		t.Fatalf("Unexpected aliases %#v", aliases)
+	}
+
+	if err := reloaded.RemoveAliases(id2); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := db["Commissioner"]; exists {
+		t.Fatalf("Removed alias should have been deleted from the database")
+	}
+	if _, exists := db["Batman"]; !exists {
+		t.Fatalf("Alias persisted for another ID shouldn't have been deleted from the database")
+	}
+}
+
+func TestAliaserPersistedAliasesAfterOthers(t *testing.T) {
+	id := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	db := testAliasDB{}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if _, err := aliaser.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.PersistAlias(id, "Batman", "Gotham"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Persisted aliases are loaded before the ID gets its other aliases
+	reloaded := Aliaser{}
+	reloaded.Initialize()
+	if _, err := reloaded.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.PrimaryAlias(id); err == nil {
+		t.Fatalf("A persisted alias shouldn't be the primary alias")
+	}
+	if err := reloaded.Alias(id, "Bruce"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.Alias(id, "Dark Knight"); err != nil {
+		t.Fatal(err)
+	}
+	if aliases := reloaded.Aliases(id); !reflect.DeepEqual([]string{"Bruce", "Dark Knight", "Batman"}, aliases) {
+		t.Fatalf("Unexpected aliases %#v", aliases)
+	}
+	if alias, err := reloaded.PrimaryAlias(id); err != nil || alias != "Bruce" {
+		t.Fatalf("Expected the primary alias to be Bruce but got %s, %v", alias, err)
+	}
+}
+
+func TestAliaserRemovePersistedAlias(t *testing.T) {
+	id := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	db := testAliasDB{}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if _, err := aliaser.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(id, "Bruce"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.PersistAlias(id, "Batman", "Gotham"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Aliases that weren't persisted can't be removed
+	if err := aliaser.RemovePersistedAlias("Bruce"); !errors.Is(err, errAliasNotPersisted) {
+		t.Fatalf("Expected %s but got %v", errAliasNotPersisted, err)
+	}
+
+	if err := aliaser.RemovePersistedAlias("Batman"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := aliaser.Lookup("Batman"); err == nil {
+		t.Fatalf("Removed alias shouldn't be an alias anymore")
+	}
+	if _, exists := db["Batman"]; exists {
+		t.Fatalf("Removed alias should have been deleted from the database")
+	}
+	if aliases := aliaser.Aliases(id); !reflect.DeepEqual([]string{"Bruce"}, aliases) {
+		t.Fatalf("Unexpected aliases %#v", aliases)
+	}
+}
@@ -44,7 +44,13 @@ func (c *Client) GetIndex(args *GetIndexArgs) (GetIndexResponse, error) {
 }
 
 func (c *Client) IsAccepted(args *GetIndexArgs) (bool, error) {
-	var response bool
+	var response IsAcceptedResponse
 	err := c.SendRequest("isAccepted", args, &response)
+	return response.IsAccepted, err
+}
+
+func (c *Client) GetContainerByID(args *GetIndexArgs) (FormattedContainer, error) {
+	var response FormattedContainer
+	err := c.SendRequest("getContainerByID", args, &response)
 	return response, err
 }
@@ -60,11 +60,24 @@ func TestIndexClient(t *testing.T) {
 	// Test IsAccepted
 	client.EndpointRequester = &mockClient{
 		f: func(reply interface{}) error {
-			*(reply.(*bool)) = true
+			*(reply.(*IsAcceptedResponse)) = IsAcceptedResponse{IsAccepted: true}
 			return nil
 		},
 	}
 	isAccepted, err := client.IsAccepted(&GetIndexArgs{ContainerID: ids.Empty, Encoding: formatting.Hex})
 	assert.NoError(err)
 	assert.True(isAccepted)
+
+	// Test GetContainerByID
+	id = ids.GenerateTestID()
+	client.EndpointRequester = &mockClient{
+		f: func(reply interface{}) error {
+			*(reply.(*FormattedContainer)) = FormattedContainer{ID: id, Index: 10}
+			return nil
+		},
+	}
+	container, err = client.GetContainerByID(&GetIndexArgs{ContainerID: id, Encoding: formatting.Hex})
+	assert.NoError(err)
+	assert.EqualValues(id, container.ID)
+	assert.EqualValues(10, container.Index)
 }
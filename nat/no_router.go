@@ -15,7 +15,13 @@ var (
 	_                       Router = &noRouter{}
 )
 
-const googleDNSServer = "8.8.8.8:80"
+// The outbound IP is the local address of a connection to these servers. The
+// IPv6 server is only used if the IPv4 one can't be reached, so that IPv6-only
+// hosts find their address.
+const (
+	googleDNSServer     = "8.8.8.8:80"
+	googleDNSServerIPv6 = "[2001:4860:4860::8888]:80"
+)
 
 type noRouter struct {
 	ip    net.IP
@@ -41,7 +47,11 @@ func (r noRouter) ExternalIP() (net.IP, error) {
 func getOutboundIP() (net.IP, error) {
 	conn, err := net.Dial("udp", googleDNSServer)
 	if err != nil {
-		return nil, err
+		var ipv6Err error
+		conn, ipv6Err = net.Dial("udp", googleDNSServerIPv6)
+		if ipv6Err != nil {
+			return nil, err
+		}
 	}
 
 	addr := conn.LocalAddr()
@@ -37,6 +37,40 @@ func (m Builder) Version(networkID, nodeID uint32, myTime uint64, ip utils.IPDes
 	})
 }
 
+// EpochSchedule message
+func (m Builder) EpochSchedule(epochFirstTransition, epochDuration uint64) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, EpochSchedule, map[Field]interface{}{
+		EpochFirstTransition: epochFirstTransition,
+		EpochDuration:        epochDuration,
+	})
+}
+
+// Compression message
+func (m Builder) Compression(types []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, Compression, map[Field]interface{}{
+		CompressionIDs: types,
+	})
+}
+
+// Capabilities message
+func (m Builder) Capabilities(capabilities Capability) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, Capabilities, map[Field]interface{}{
+		CapabilitiesBitmap: uint64(capabilities),
+	})
+}
+
+// Compressed message
+func (m Builder) Compressed(compressionType CompressionType, msgBytes []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, Compressed, map[Field]interface{}{
+		CompressionID:   byte(compressionType),
+		CompressedBytes: msgBytes,
+	})
+}
+
 // GetPeerList message
 func (m Builder) GetPeerList() (Msg, error) {
 	buf := m.getByteSlice()
@@ -137,6 +171,27 @@ func (m Builder) MultiPut(chainID ids.ID, requestID uint32, containers [][]byte)
 	})
 }
 
+// GetStateSummary message
+func (m Builder) GetStateSummary(chainID ids.ID, requestID uint32, deadline uint64) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, GetStateSummary, map[Field]interface{}{
+		ChainID:   chainID[:],
+		RequestID: requestID,
+		Deadline:  deadline,
+	})
+}
+
+// StateSummary message
+func (m Builder) StateSummary(chainID ids.ID, requestID uint32, frontier [][]byte, summary []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, StateSummary, map[Field]interface{}{
+		ChainID:             chainID[:],
+		RequestID:           requestID,
+		MultiContainerBytes: frontier,
+		ContainerBytes:      summary,
+	})
+}
+
 // Get message
 func (m Builder) Get(chainID ids.ID, requestID uint32, deadline uint64, containerID ids.ID) (Msg, error) {
 	buf := m.getByteSlice()
@@ -39,6 +39,75 @@ func TestBuildGetPeerList(t *testing.T) {
 	assert.Equal(t, GetPeerList, parsedMsg.Op())
 }
 
+func TestBuildEpochSchedule(t *testing.T) {
+	firstTransition := uint64(1607626800)
+	duration := uint64(21600)
+
+	msg, err := TestBuilder.EpochSchedule(firstTransition, duration)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, EpochSchedule, msg.Op())
+	assert.Equal(t, firstTransition, msg.Get(EpochFirstTransition))
+	assert.Equal(t, duration, msg.Get(EpochDuration))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, EpochSchedule, parsedMsg.Op())
+	assert.Equal(t, firstTransition, parsedMsg.Get(EpochFirstTransition))
+	assert.Equal(t, duration, parsedMsg.Get(EpochDuration))
+}
+
+func TestBuildCompression(t *testing.T) {
+	types := []byte{byte(SnappyCompression)}
+
+	msg, err := TestBuilder.Compression(types)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Compression, msg.Op())
+	assert.Equal(t, types, msg.Get(CompressionIDs))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Compression, parsedMsg.Op())
+	assert.Equal(t, types, parsedMsg.Get(CompressionIDs))
+}
+
+func TestBuildCapabilities(t *testing.T) {
+	capabilities := StateSyncCapability | 1<<63
+
+	msg, err := TestBuilder.Capabilities(capabilities)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Capabilities, msg.Op())
+	assert.Equal(t, uint64(capabilities), msg.Get(CapabilitiesBitmap))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Capabilities, parsedMsg.Op())
+	assert.Equal(t, uint64(capabilities), parsedMsg.Get(CapabilitiesBitmap))
+}
+
+func TestBuildCompressed(t *testing.T) {
+	compressedBytes := []byte{1, 2, 3}
+
+	msg, err := TestBuilder.Compressed(SnappyCompression, compressedBytes)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Compressed, msg.Op())
+	assert.Equal(t, byte(SnappyCompression), msg.Get(CompressionID))
+	assert.Equal(t, compressedBytes, msg.Get(CompressedBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Compressed, parsedMsg.Op())
+	assert.Equal(t, byte(SnappyCompression), parsedMsg.Get(CompressionID))
+	assert.Equal(t, compressedBytes, parsedMsg.Get(CompressedBytes))
+}
+
 func TestBuildGetAcceptedFrontier(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
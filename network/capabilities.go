@@ -0,0 +1,65 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"strings"
+)
+
+// Capability is an optional feature of the P2P protocol, or a set of them.
+// Peers report the capabilities they support in a Capabilities message sent
+// after Version, and are only sent the messages of the features they support.
+// New features add a capability, so they can be rolled out without changing
+// the version peers must run to be compatible.
+type Capability uint64
+
+const (
+	// CompressionCapability means that the peer can receive Compressed
+	// messages. Compressed messages are only sent to peers that also report
+	// the compression types they support in a Compression message.
+	CompressionCapability Capability = 1 << iota
+	// StateSyncCapability means that the peer handles GetStateSummary and
+	// StateSummary messages
+	StateSyncCapability
+)
+
+var (
+	// supportedCapabilities are the capabilities this node reports to its
+	// peers
+	supportedCapabilities = CompressionCapability | StateSyncCapability
+
+	// legacyCapabilities are assumed to be supported by peers that don't
+	// send a Capabilities message. They predate Capabilities messages, so
+	// they were only gated on the version of peers. Capabilities added later
+	// must not be added here.
+	legacyCapabilities = CompressionCapability | StateSyncCapability
+
+	capabilityNames = []struct {
+		capability Capability
+		name       string
+	}{
+		{CompressionCapability, "compression"},
+		{StateSyncCapability, "stateSync"},
+	}
+)
+
+// Contains returns true if [c] contains every capability in [capabilities]
+func (c Capability) Contains(capabilities Capability) bool {
+	return c&capabilities == capabilities
+}
+
+// List returns the names of the known capabilities in [c]
+func (c Capability) List() []string {
+	names := []string{}
+	for _, capability := range capabilityNames {
+		if c.Contains(capability.capability) {
+			names = append(names, capability.name)
+		}
+	}
+	return names
+}
+
+func (c Capability) String() string {
+	return "[" + strings.Join(c.List(), ", ") + "]"
+}
@@ -0,0 +1,47 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestCapability(t *testing.T) {
+	capabilities := CompressionCapability | StateSyncCapability
+	assert.True(t, capabilities.Contains(CompressionCapability))
+	assert.True(t, capabilities.Contains(CompressionCapability|StateSyncCapability))
+	assert.False(t, CompressionCapability.Contains(capabilities))
+	assert.Equal(t, []string{"compression", "stateSync"}, capabilities.List())
+	assert.Equal(t, "[compression, stateSync]", capabilities.String())
+
+	// Unknown capabilities aren't listed
+	assert.Equal(t, []string{"stateSync"}, (StateSyncCapability | 1<<63).List())
+	assert.Empty(t, Capability(0).List())
+}
+
+func TestPeerCapabilities(t *testing.T) {
+	n := &network{
+		log: logging.NoLog{},
+		b:   TestBuilder,
+	}
+	assert.NoError(t, n.initialize(prometheus.NewRegistry()))
+	p := &peer{net: n}
+
+	// Peers that didn't report their capabilities support the legacy ones
+	assert.True(t, p.supports(CompressionCapability))
+	assert.True(t, p.supports(StateSyncCapability))
+	assert.Nil(t, p.capabilityList())
+
+	msg, err := n.b.Capabilities(StateSyncCapability | 1<<63)
+	assert.NoError(t, err)
+	p.handleCapabilities(msg)
+	assert.False(t, p.supports(CompressionCapability))
+	assert.True(t, p.supports(StateSyncCapability))
+	assert.Equal(t, []string{"stateSync"}, p.capabilityList())
+}
@@ -12,22 +12,28 @@ type Field uint32
 
 // Fields that may be packed. These values are not sent over the wire.
 const (
-	VersionStr          Field = iota // Used in handshake
-	NetworkID                        // Used in handshake
-	NodeID                           // Used in handshake
-	MyTime                           // Used in handshake
-	IP                               // Used in handshake
-	Peers                            // Used in handshake
-	ChainID                          // Used for dispatching
-	RequestID                        // Used for all messages
-	Deadline                         // Used for request messages
-	ContainerID                      // Used for querying
-	ContainerBytes                   // Used for gossiping
-	ContainerIDs                     // Used for querying
-	MultiContainerBytes              // Used in MultiPut
-	SigBytes                         // Used in handshake / peer gossiping
-	VersionTime                      // Used in handshake / peer gossiping
-	SignedPeers                      // Used in peer gossiping
+	VersionStr           Field = iota // Used in handshake
+	NetworkID                         // Used in handshake
+	NodeID                            // Used in handshake
+	MyTime                            // Used in handshake
+	IP                                // Used in handshake
+	Peers                             // Used in handshake
+	ChainID                           // Used for dispatching
+	RequestID                         // Used for all messages
+	Deadline                          // Used for request messages
+	ContainerID                       // Used for querying
+	ContainerBytes                    // Used for gossiping
+	ContainerIDs                      // Used for querying
+	MultiContainerBytes               // Used in MultiPut
+	SigBytes                          // Used in handshake / peer gossiping
+	VersionTime                       // Used in handshake / peer gossiping
+	SignedPeers                       // Used in peer gossiping
+	EpochFirstTransition              // Used in handshake
+	EpochDuration                     // Used in handshake
+	CompressionIDs                    // Used in handshake
+	CompressionID                     // Used for compression
+	CompressedBytes                   // Used for compression
+	CapabilitiesBitmap                // Used in handshake
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -65,6 +71,18 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackLong
 	case SignedPeers:
 		return wrappers.TryPackIPCertList
+	case EpochFirstTransition:
+		return wrappers.TryPackLong
+	case EpochDuration:
+		return wrappers.TryPackLong
+	case CompressionIDs:
+		return wrappers.TryPackBytes
+	case CompressionID:
+		return wrappers.TryPackByte
+	case CompressedBytes:
+		return wrappers.TryPackBytes
+	case CapabilitiesBitmap:
+		return wrappers.TryPackLong
 	default:
 		return nil
 	}
@@ -105,6 +123,18 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackLong
 	case SignedPeers:
 		return wrappers.TryUnpackIPCertList
+	case EpochFirstTransition:
+		return wrappers.TryUnpackLong
+	case EpochDuration:
+		return wrappers.TryUnpackLong
+	case CompressionIDs:
+		return wrappers.TryUnpackBytes
+	case CompressionID:
+		return wrappers.TryUnpackByte
+	case CompressedBytes:
+		return wrappers.TryUnpackBytes
+	case CapabilitiesBitmap:
+		return wrappers.TryUnpackLong
 	default:
 		return nil
 	}
@@ -144,6 +174,18 @@ func (f Field) String() string {
 		return "VersionTime"
 	case SignedPeers:
 		return "SignedPeers"
+	case EpochFirstTransition:
+		return "EpochFirstTransition"
+	case EpochDuration:
+		return "EpochDuration"
+	case CompressionIDs:
+		return "CompressionIDs"
+	case CompressionID:
+		return "CompressionID"
+	case CompressedBytes:
+		return "CompressedBytes"
+	case CapabilitiesBitmap:
+		return "CapabilitiesBitmap"
 	default:
 		return "Unknown Field"
 	}
@@ -188,6 +230,18 @@ func (op Op) String() string {
 		return "pull_query"
 	case Chits:
 		return "chits"
+	case EpochSchedule:
+		return "epoch_schedule"
+	case GetStateSummary:
+		return "get_state_summary"
+	case StateSummary:
+		return "state_summary"
+	case Compression:
+		return "compression"
+	case Compressed:
+		return "compressed"
+	case Capabilities:
+		return "capabilities"
 	default:
 		return "Unknown Op"
 	}
@@ -218,6 +272,17 @@ const (
 	// Handshake / peer gossiping
 	Version
 	PeerList
+	// Handshake:
+	EpochSchedule
+	// State sync:
+	GetStateSummary
+	StateSummary
+	// Handshake:
+	Compression
+	// Compression:
+	Compressed
+	// Handshake:
+	Capabilities
 )
 
 // Defines the messages that can be sent/received with this network
@@ -230,6 +295,8 @@ var (
 		PeerList:    {SignedPeers},
 		Ping:        {},
 		Pong:        {},
+		// Sent after Version. Peers that don't know this message drop it.
+		EpochSchedule: {EpochFirstTransition, EpochDuration},
 		// Bootstrapping:
 		GetAcceptedFrontier: {ChainID, RequestID, Deadline},
 		AcceptedFrontier:    {ChainID, RequestID, ContainerIDs},
@@ -243,5 +310,18 @@ var (
 		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
 		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
 		Chits:     {ChainID, RequestID, ContainerIDs},
+		// State sync. Requests are only sent to peers that support
+		// StateSyncCapability. Peers that don't know these messages drop them,
+		// so requests to them time out.
+		GetStateSummary: {ChainID, RequestID, Deadline},
+		StateSummary:    {ChainID, RequestID, MultiContainerBytes, ContainerBytes},
+		// Sent after Version by peers that compress messages. Peers that don't
+		// know this message drop it, and are never sent compressed messages.
+		Compression: {CompressionIDs},
+		// Wraps a compressed message whose op is compressible.
+		Compressed: {CompressionID, CompressedBytes},
+		// Sent after Version. Peers that don't know this message drop it, and
+		// are assumed to support the legacy capabilities.
+		Capabilities: {CapabilitiesBitmap},
 	}
 )
@@ -0,0 +1,111 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+// CompressionType is an algorithm that messages can be compressed with
+type CompressionType byte
+
+const (
+	// NoCompression means that messages are sent uncompressed
+	NoCompression CompressionType = iota
+	// SnappyCompression compresses messages with snappy
+	SnappyCompression
+)
+
+var (
+	errUnknownCompression     = errors.New("unknown compression type")
+	errDecompressedTooLarge   = errors.New("decompressed message is too large")
+	errNotCompressible        = errors.New("compressed message has an op that can't be compressed")
+	supportedCompressionTypes = []CompressionType{SnappyCompression}
+	supportedCompressionIDs   = compressionIDs(supportedCompressionTypes)
+)
+
+func (t CompressionType) String() string {
+	switch t {
+	case NoCompression:
+		return "none"
+	case SnappyCompression:
+		return "snappy"
+	default:
+		return "unknown"
+	}
+}
+
+// CompressionConfig describes which messages are compressed before they're
+// sent to peers
+type CompressionConfig struct {
+	// If true, this node tells its peers that it can receive compressed
+	// messages, and compresses the messages it sends to peers that can.
+	Enabled bool `json:"enabled"`
+	// Messages smaller than this, in bytes, are sent uncompressed
+	MinSize int `json:"minSize"`
+}
+
+// compressible returns true if messages with [op] may be sent compressed.
+// Only messages that carry containers are worth the cost of compressing them.
+func compressible(op Op) bool {
+	switch op {
+	case Put, PushQuery, MultiPut:
+		return true
+	default:
+		return false
+	}
+}
+
+// selectCompression returns the compression type used to send messages to a
+// peer that can receive messages compressed with [peerTypes]
+func selectCompression(peerTypes []byte) CompressionType {
+	for _, t := range supportedCompressionTypes {
+		for _, peerType := range peerTypes {
+			if CompressionType(peerType) == t {
+				return t
+			}
+		}
+	}
+	return NoCompression
+}
+
+// compress returns [b] compressed with [t]
+func compress(t CompressionType, b []byte) ([]byte, error) {
+	switch t {
+	case SnappyCompression:
+		return snappy.Encode(nil, b), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownCompression, t)
+	}
+}
+
+// decompress returns [b] decompressed with [t]. Returns an error if the
+// decompressed bytes would be larger than [maxSize], so that a peer can't make
+// this node allocate more memory than an uncompressed message would.
+func decompress(t CompressionType, b []byte, maxSize int64) ([]byte, error) {
+	switch t {
+	case SnappyCompression:
+		size, err := snappy.DecodedLen(b)
+		if err != nil {
+			return nil, err
+		}
+		if int64(size) > maxSize {
+			return nil, fmt.Errorf("%w: %d > %d", errDecompressedTooLarge, size, maxSize)
+		}
+		return snappy.Decode(nil, b)
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownCompression, t)
+	}
+}
+
+func compressionIDs(types []CompressionType) []byte {
+	b := make([]byte, len(types))
+	for i, t := range types {
+		b[i] = byte(t)
+	}
+	return b
+}
@@ -0,0 +1,122 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestCompressDecompress(t *testing.T) {
+	b := bytes.Repeat([]byte{1, 2, 3, 4}, 1024)
+
+	compressed, err := compress(SnappyCompression, b)
+	assert.NoError(t, err)
+	assert.Less(t, len(compressed), len(b))
+
+	decompressed, err := decompress(SnappyCompression, compressed, int64(len(b)))
+	assert.NoError(t, err)
+	assert.Equal(t, b, decompressed)
+
+	_, err = decompress(SnappyCompression, compressed, int64(len(b)-1))
+	assert.True(t, errors.Is(err, errDecompressedTooLarge))
+
+	_, err = compress(NoCompression, b)
+	assert.True(t, errors.Is(err, errUnknownCompression))
+	_, err = decompress(CompressionType(0xFF), compressed, int64(len(b)))
+	assert.True(t, errors.Is(err, errUnknownCompression))
+}
+
+func TestSelectCompression(t *testing.T) {
+	assert.Equal(t, SnappyCompression, selectCompression([]byte{0xFF, byte(SnappyCompression)}))
+	assert.Equal(t, NoCompression, selectCompression([]byte{0xFF}))
+	assert.Equal(t, NoCompression, selectCompression(nil))
+}
+
+// newCompressionTestPeer returns a peer that reported it supports
+// [peerTypes], of a network configured with [config]
+func newCompressionTestPeer(t *testing.T, config CompressionConfig, peerTypes []byte) *peer {
+	n := &network{
+		log:               logging.NoLog{},
+		b:                 TestBuilder,
+		maxMessageSize:    int64(DefaultMaxMessageSize),
+		compressionConfig: config,
+	}
+	assert.NoError(t, n.initialize(prometheus.NewRegistry()))
+
+	p := &peer{net: n}
+	msg, err := n.b.Compression(peerTypes)
+	assert.NoError(t, err)
+	p.handleCompression(msg)
+	return p
+}
+
+func TestPeerCompression(t *testing.T) {
+	p := newCompressionTestPeer(t, CompressionConfig{Enabled: true, MinSize: 1024}, supportedCompressionIDs)
+
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+	container := bytes.Repeat([]byte{1, 2, 3, 4}, 1024)
+	msg, err := TestBuilder.Put(chainID, 2, containerID, container)
+	assert.NoError(t, err)
+
+	compressedMsg, ok := p.compress(msg)
+	assert.True(t, ok)
+	assert.Equal(t, Compressed, compressedMsg.Op())
+	assert.Less(t, len(compressedMsg.Bytes()), len(msg.Bytes()))
+
+	parsedMsg, err := TestBuilder.Parse(compressedMsg.Bytes())
+	assert.NoError(t, err)
+	decompressedMsg, err := p.decompress(parsedMsg)
+	assert.NoError(t, err)
+	assert.Equal(t, Put, decompressedMsg.Op())
+	assert.Equal(t, msg.Bytes(), decompressedMsg.Bytes())
+	assert.Equal(t, container, decompressedMsg.Get(ContainerBytes))
+
+	// Messages smaller than the min size are sent as is
+	msg, err = TestBuilder.Put(chainID, 2, containerID, container[:10])
+	assert.NoError(t, err)
+	_, ok = p.compress(msg)
+	assert.False(t, ok)
+
+	// Messages that don't carry containers are sent as is
+	containerIDs := make([]ids.ID, 64)
+	msg, err = TestBuilder.Chits(chainID, 2, containerIDs)
+	assert.NoError(t, err)
+	_, ok = p.compress(msg)
+	assert.False(t, ok)
+
+	// Peers can't send compressed messages that shouldn't be compressed
+	compressedBytes, err := compress(SnappyCompression, msg.Bytes())
+	assert.NoError(t, err)
+	compressedMsg, err = TestBuilder.Compressed(SnappyCompression, compressedBytes)
+	assert.NoError(t, err)
+	_, err = p.decompress(compressedMsg)
+	assert.True(t, errors.Is(err, errNotCompressible))
+}
+
+func TestPeerCompressionNotNegotiated(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+	container := bytes.Repeat([]byte{1, 2, 3, 4}, 1024)
+	msg, err := TestBuilder.Put(chainID, 2, containerID, container)
+	assert.NoError(t, err)
+
+	// The peer doesn't support compression
+	p := newCompressionTestPeer(t, CompressionConfig{Enabled: true}, nil)
+	_, ok := p.compress(msg)
+	assert.False(t, ok)
+
+	// This node doesn't compress messages
+	p = newCompressionTestPeer(t, CompressionConfig{}, supportedCompressionIDs)
+	_, ok = p.compress(msg)
+	assert.False(t, ok)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/url"
 	"time"
 
 	"github.com/ava-labs/avalanchego/network/throttling"
@@ -28,17 +29,23 @@ type dialer struct {
 	network           string
 	throttler         throttling.DialThrottler
 	connectionTimeout time.Duration
+	// Dials directly, or through a proxy
+	dialer contextDialer
 }
 
 type Config struct {
 	throttleRps       uint32
 	connectionTimeout time.Duration
+	proxyURL          *url.URL
 }
 
-func NewConfig(throttleRps uint32, dialTimeout time.Duration) Config {
+// NewConfig returns the config of a dialer. If [proxyURL] isn't nil, the
+// dialer connects through the proxy at [proxyURL]. See ParseProxyURL.
+func NewConfig(throttleRps uint32, dialTimeout time.Duration, proxyURL *url.URL) Config {
 	return Config{
 		throttleRps,
 		dialTimeout,
+		proxyURL,
 	}
 }
 
@@ -47,6 +54,7 @@ func NewConfig(throttleRps uint32, dialTimeout time.Duration) Config {
 // [dialerConfig.connectionTimeout] gives the timeout when dialing an IP.
 // [dialerConfig.throttleRps] gives the max number of outgoing connection attempts/second.
 // If [dialerConfig.throttleRps] == 0, outgoing connections aren't rate-limited.
+// If [dialerConfig.proxyURL] isn't nil, connections go through that proxy.
 func NewDialer(network string, dialerConfig Config, log logging.Logger) Dialer {
 	var throttler throttling.DialThrottler
 	if dialerConfig.throttleRps <= 0 {
@@ -59,11 +67,25 @@ func NewDialer(network string, dialerConfig Config, log logging.Logger) Dialer {
 		dialerConfig.throttleRps,
 		dialerConfig.connectionTimeout,
 	)
+
+	netDialer := &net.Dialer{Timeout: dialerConfig.connectionTimeout}
+	var contextDialer contextDialer = netDialer
+	if dialerConfig.proxyURL != nil {
+		proxyDialer, err := newProxyDialer(dialerConfig.proxyURL, netDialer)
+		if err != nil {
+			log.Error("outgoing connections will fail: %s", err)
+			contextDialer = &errDialer{err: err}
+		} else {
+			log.Info("dialing peers through proxy at %s", dialerConfig.proxyURL.Host)
+			contextDialer = proxyDialer
+		}
+	}
 	return &dialer{
 		log:               log,
 		network:           network,
 		throttler:         throttler,
 		connectionTimeout: dialerConfig.connectionTimeout,
+		dialer:            contextDialer,
 	}
 }
 
@@ -72,10 +94,18 @@ func (d *dialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
 		return nil, err
 	}
 	d.log.Verbo("dialing %s", ip)
-	dialer := net.Dialer{Timeout: d.connectionTimeout}
-	conn, err := dialer.DialContext(ctx, d.network, ip.String())
+	conn, err := d.dialer.DialContext(ctx, d.network, ip.String())
 	if err != nil {
 		return nil, fmt.Errorf("error while dialing %s: %s", ip, err)
 	}
 	return conn, nil
 }
+
+// errDialer fails to connect to any address
+type errDialer struct {
+	err error
+}
+
+func (d *errDialer) DialContext(context.Context, string, string) (net.Conn, error) {
+	return nil, d.err
+}
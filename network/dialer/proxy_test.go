@@ -0,0 +1,232 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dialer
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// listenEcho returns a listener that echoes what's written to the connections it
+// accepts
+func listenEcho(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return l
+}
+
+// serveProxy accepts connections on [l], runs [handshake] on them, and pipes
+// them to the address [handshake] returns
+func serveProxy(l net.Listener, handshake func(conn net.Conn, r *bufio.Reader) (string, error)) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			address, err := handshake(conn, r)
+			if err != nil {
+				return
+			}
+			target, err := net.Dial("tcp", address)
+			if err != nil {
+				return
+			}
+			defer target.Close()
+			go func() { _, _ = io.Copy(target, r) }()
+			_, _ = io.Copy(conn, target)
+		}()
+	}
+}
+
+func socks5Handshake(username, password string) func(net.Conn, *bufio.Reader) (string, error) {
+	return func(conn net.Conn, r *bufio.Reader) (string, error) {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return "", err
+		}
+		methods := make([]byte, header[1])
+		if _, err := io.ReadFull(r, methods); err != nil {
+			return "", err
+		}
+
+		if username == "" {
+			_, _ = conn.Write([]byte{socks5Version, socks5AuthNone})
+		} else {
+			_, _ = conn.Write([]byte{socks5Version, socks5AuthPassword})
+			auth := make([]byte, 2)
+			if _, err := io.ReadFull(r, auth); err != nil {
+				return "", err
+			}
+			user := make([]byte, auth[1])
+			if _, err := io.ReadFull(r, user); err != nil {
+				return "", err
+			}
+			passLen, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			pass := make([]byte, passLen)
+			if _, err := io.ReadFull(r, pass); err != nil {
+				return "", err
+			}
+			if string(user) != username || string(pass) != password {
+				_, _ = conn.Write([]byte{socks5PasswordVersion, 1})
+				return "", errors.New("wrong credentials")
+			}
+			_, _ = conn.Write([]byte{socks5PasswordVersion, 0})
+		}
+
+		request := make([]byte, 4)
+		if _, err := io.ReadFull(r, request); err != nil {
+			return "", err
+		}
+		if request[1] != socks5CmdConnect || request[3] != socks5AddrIPv4 {
+			_, _ = conn.Write([]byte{socks5Version, 7, 0, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+			return "", errors.New("unsupported request")
+		}
+		addr := make([]byte, net.IPv4len+2)
+		if _, err := io.ReadFull(r, addr); err != nil {
+			return "", err
+		}
+		ip := net.IP(addr[:net.IPv4len])
+		port := binary.BigEndian.Uint16(addr[net.IPv4len:])
+		_, _ = conn.Write([]byte{socks5Version, 0, 0, socks5AddrIPv4, 127, 0, 0, 1, 0, 0})
+		return net.JoinHostPort(ip.String(), strconv.Itoa(int(port))), nil
+	}
+}
+
+func httpConnectHandshake(username, password string) func(net.Conn, *bufio.Reader) (string, error) {
+	return func(conn net.Conn, r *bufio.Reader) (string, error) {
+		req, err := http.ReadRequest(r)
+		if err != nil {
+			return "", err
+		}
+		if req.Method != http.MethodConnect {
+			_, _ = conn.Write([]byte("HTTP/1.1 405 Method Not Allowed\r\n\r\n"))
+			return "", errors.New("not a CONNECT")
+		}
+		if username != "" {
+			user, pass, ok := (&http.Request{Header: http.Header{
+				"Authorization": req.Header["Proxy-Authorization"],
+			}}).BasicAuth()
+			if !ok || user != username || pass != password {
+				_, _ = conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+				return "", errors.New("wrong credentials")
+			}
+		}
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		return req.Host, nil
+	}
+}
+
+func testProxyDial(t *testing.T, scheme string, handshake func(string, string) func(net.Conn, *bufio.Reader) (string, error)) {
+	target := listenEcho(t)
+	defer target.Close()
+
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer proxy.Close()
+	go serveProxy(proxy, handshake("user", "password"))
+
+	proxyURL, err := ParseProxyURL(scheme + "://user:password@" + proxy.Addr().String())
+	assert.NoError(t, err)
+	d, err := newProxyDialer(proxyURL, &net.Dialer{Timeout: 5 * time.Second})
+	assert.NoError(t, err)
+
+	conn, err := d.DialContext(context.Background(), "tcp", target.Addr().String())
+	assert.NoError(t, err)
+	_, err = conn.Write([]byte("ping"))
+	assert.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	assert.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+	assert.NoError(t, conn.Close())
+
+	// Wrong credentials are rejected
+	proxyURL.User = url.UserPassword("user", "wrong")
+	d, err = newProxyDialer(proxyURL, &net.Dialer{Timeout: 5 * time.Second})
+	assert.NoError(t, err)
+	_, err = d.DialContext(context.Background(), "tcp", target.Addr().String())
+	assert.True(t, errors.Is(err, errProxyAuthFailed))
+}
+
+func TestSOCKS5Dial(t *testing.T) {
+	testProxyDial(t, "socks5", socks5Handshake)
+}
+
+func TestHTTPConnectDial(t *testing.T) {
+	testProxyDial(t, "http", httpConnectHandshake)
+}
+
+// Test that canceling the context interrupts the proxy handshake
+func TestProxyDialCancel(t *testing.T) {
+	// The proxy accepts connections, but never responds
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer proxy.Close()
+	go func() {
+		for {
+			conn, err := proxy.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	proxyURL, err := ParseProxyURL("socks5://" + proxy.Addr().String())
+	assert.NoError(t, err)
+	d, err := newProxyDialer(proxyURL, &net.Dialer{})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err = d.DialContext(ctx, "tcp", "127.0.0.1:9651")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestParseProxyURL(t *testing.T) {
+	proxyURL, err := ParseProxyURL("")
+	assert.NoError(t, err)
+	assert.Nil(t, proxyURL)
+
+	proxyURL, err = ParseProxyURL("socks5://proxy.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:1080", proxyAddr(proxyURL, defaultSOCKS5Port))
+
+	_, err = ParseProxyURL("https://proxy.example.com")
+	assert.True(t, errors.Is(err, errUnknownProxyScheme))
+	_, err = ParseProxyURL("socks5://")
+	assert.Error(t, err)
+}
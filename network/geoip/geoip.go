@@ -0,0 +1,175 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package geoip
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Unknown is reported as the location of IPs that can't be resolved
+	Unknown = "unknown"
+
+	numColumns = 4
+)
+
+var (
+	errInvalidResolver = errors.New("invalid geoip resolver")
+
+	_ Resolver = &NoResolver{}
+	_ Resolver = &rangeResolver{}
+)
+
+// Record describes where an IP is located and which autonomous system it
+// belongs to.
+type Record struct {
+	Country        string `json:"country"`
+	ASN            uint32 `json:"asn"`
+	ASOrganization string `json:"asOrganization"`
+}
+
+// Resolver maps IPs to their geographic and autonomous system information
+type Resolver interface {
+	// Lookup returns the record that covers [ip]. Returns an error if [ip]
+	// isn't covered by this resolver.
+	Lookup(ip net.IP) (Record, error)
+	// If false, Lookup always returns an error
+	IsResolver() bool
+}
+
+// NoResolver doesn't resolve any IPs
+type NoResolver struct{}
+
+func (r *NoResolver) IsResolver() bool { return false }
+
+func (r *NoResolver) Lookup(net.IP) (Record, error) { return Record{}, errInvalidResolver }
+
+// ipRange is an inclusive range of IPs in their 16 byte representation
+type ipRange struct {
+	start, end net.IP
+	record     Record
+}
+
+// rangeResolver resolves IPs using a sorted set of non-overlapping ranges
+type rangeResolver struct {
+	ranges []ipRange
+}
+
+// NewResolver returns a resolver backed by the database at [path]. If [path]
+// is empty, a resolver that doesn't resolve any IPs is returned.
+//
+// The database is a CSV file where each row is of the form
+// [network,country,asn,asOrganization]. For example, the row
+// [1.0.0.0/24,AU,13335,Cloudflare] maps 1.0.0.0/24 to Australia and AS13335.
+// Empty lines and lines starting with '#' are ignored. The networks in the
+// database must not overlap.
+func NewResolver(path string) (Resolver, error) {
+	if path == "" {
+		return &NoResolver{}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open geoip database %q: %w", path, err)
+	}
+	resolver, err := Parse(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse geoip database %q: %w", path, err)
+	}
+	return resolver, nil
+}
+
+// Parse reads a database in the format described by NewResolver from [r]
+func Parse(r io.Reader) (Resolver, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = numColumns
+	reader.TrimLeadingSpace = true
+
+	resolver := &rangeResolver{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		_, network, err := net.ParseCIDR(strings.TrimSpace(row[0]))
+		if err != nil {
+			return nil, err
+		}
+		asn, err := strconv.ParseUint(strings.TrimSpace(row[2]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asn %q: %w", row[2], err)
+		}
+
+		start := network.IP.To16()
+		end := make(net.IP, net.IPv6len)
+		copy(end, start)
+		// [network.Mask] may be either 4 or 16 bytes long, so it is aligned
+		// with the end of the 16 byte representation of the IP.
+		offset := net.IPv6len - len(network.Mask)
+		for i, b := range network.Mask {
+			end[offset+i] |= ^b
+		}
+
+		resolver.ranges = append(resolver.ranges, ipRange{
+			start: start,
+			end:   end,
+			record: Record{
+				Country:        strings.ToUpper(strings.TrimSpace(row[1])),
+				ASN:            uint32(asn),
+				ASOrganization: strings.TrimSpace(row[3]),
+			},
+		})
+	}
+
+	sort.Slice(resolver.ranges, func(i, j int) bool {
+		return bytes.Compare(resolver.ranges[i].start, resolver.ranges[j].start) < 0
+	})
+	for i := 1; i < len(resolver.ranges); i++ {
+		prev := resolver.ranges[i-1]
+		next := resolver.ranges[i]
+		if bytes.Compare(prev.end, next.start) >= 0 {
+			return nil, fmt.Errorf("networks starting at %s and %s overlap", prev.start, next.start)
+		}
+	}
+	return resolver, nil
+}
+
+func (r *rangeResolver) IsResolver() bool { return true }
+
+func (r *rangeResolver) Lookup(ip net.IP) (Record, error) {
+	ip = ip.To16()
+	if ip == nil {
+		return Record{}, errors.New("invalid ip")
+	}
+
+	// Find the first range that starts after [ip]. The range before it is the
+	// only range that could contain [ip].
+	index := sort.Search(len(r.ranges), func(i int) bool {
+		return bytes.Compare(r.ranges[i].start, ip) > 0
+	})
+	if index == 0 {
+		return Record{}, fmt.Errorf("no record for %s", ip)
+	}
+	ipRange := r.ranges[index-1]
+	if bytes.Compare(ip, ipRange.end) > 0 {
+		return Record{}, fmt.Errorf("no record for %s", ip)
+	}
+	return ipRange.record, nil
+}
@@ -0,0 +1,71 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package geoip
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDB = `
+# network,country,asn,asOrganization
+1.0.0.0/24, au, 13335, Cloudflare
+10.0.0.0/8,US,64512,Private
+2001:db8::/32,DE,64513,Documentation
+`
+
+func TestNoResolver(t *testing.T) {
+	resolver, err := NewResolver("")
+	assert.NoError(t, err)
+	assert.False(t, resolver.IsResolver())
+
+	_, err = resolver.Lookup(net.IPv4(1, 0, 0, 1))
+	assert.Error(t, err)
+}
+
+func TestParseLookup(t *testing.T) {
+	resolver, err := Parse(strings.NewReader(testDB))
+	assert.NoError(t, err)
+	assert.True(t, resolver.IsResolver())
+
+	record, err := resolver.Lookup(net.IPv4(1, 0, 0, 255))
+	assert.NoError(t, err)
+	assert.Equal(t, Record{Country: "AU", ASN: 13335, ASOrganization: "Cloudflare"}, record)
+
+	record, err = resolver.Lookup(net.IPv4(10, 255, 255, 255))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(64512), record.ASN)
+
+	record, err = resolver.Lookup(net.ParseIP("2001:db8::1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "DE", record.Country)
+
+	_, err = resolver.Lookup(net.IPv4(1, 0, 1, 0))
+	assert.Error(t, err)
+
+	_, err = resolver.Lookup(net.IPv4(0, 0, 0, 1))
+	assert.Error(t, err)
+
+	_, err = resolver.Lookup(net.ParseIP("2001:db9::1"))
+	assert.Error(t, err)
+}
+
+func TestParseOverlapping(t *testing.T) {
+	_, err := Parse(strings.NewReader("10.0.0.0/8,US,1,A\n10.1.0.0/16,US,2,B\n"))
+	assert.Error(t, err)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("10.0.0.0/33,US,1,A\n"))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader("10.0.0.0/8,US,-1,A\n"))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader("10.0.0.0/8,US,1\n"))
+	assert.Error(t, err)
+}
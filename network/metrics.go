@@ -5,10 +5,13 @@ package network
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/metric"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
@@ -56,6 +59,11 @@ func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registere
 	return errs.Err
 }
 
+const (
+	peerMetricsNodeIDLabel = "nodeID"
+	peerMetricsOpLabel     = "op"
+)
+
 type metrics struct {
 	numPeers                 prometheus.Gauge
 	timeSinceLastMsgSent     prometheus.Gauge
@@ -65,13 +73,32 @@ type metrics struct {
 	failedToParse            prometheus.Counter
 	connected                prometheus.Counter
 	disconnected             prometheus.Counter
+	peersByCountry           *prometheus.GaugeVec
+	peersByASN               *prometheus.GaugeVec
+
+	// Size of the messages before and after they were compressed
+	compressionInputBytes, compressionOutputBytes prometheus.Counter
+	// Size of the messages before and after they were decompressed
+	decompressionInputBytes, decompressionOutputBytes prometheus.Counter
+	compressionTime, decompressionTime                prometheus.Histogram
+
+	// Backlogs of the send queues of all peers
+	sendQueueMetrics sendQueueMetrics
+
+	// If true, the bytes exchanged with each peer and the latency of its
+	// responses are reported by peer and message type
+	peerMetricsEnabled               bool
+	peerSentBytes, peerReceivedBytes *prometheus.CounterVec
+	peerResponseLatency              *prometheus.SummaryVec
 
 	getVersion, version,
+	epochSchedule, compression, capabilities,
 	getPeerlist, peerList,
 	ping, pong,
 	getAcceptedFrontier, acceptedFrontier,
 	getAccepted, accepted,
 	getAncestors, multiPut,
+	getStateSummary, stateSummary,
 	get, put,
 	pushQuery, pullQuery, chits messageMetrics
 }
@@ -118,6 +145,48 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		Name:      "times_disconnected",
 		Help:      "Times this node disconnected from a peer it had completed a handshake with",
 	})
+	m.peersByCountry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "peers_by_country",
+		Help:      "Number of network peers located in each country",
+	}, []string{"country"})
+	m.peersByASN = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "peers_by_asn",
+		Help:      "Number of network peers located in each autonomous system",
+	}, []string{"asn"})
+	m.compressionInputBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "compression_input_bytes",
+		Help:      "Number of bytes of messages compressed before they were sent",
+	})
+	m.compressionOutputBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "compression_output_bytes",
+		Help:      "Number of bytes that messages were compressed into",
+	})
+	m.decompressionInputBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "decompression_input_bytes",
+		Help:      "Number of bytes of compressed messages received",
+	})
+	m.decompressionOutputBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "decompression_output_bytes",
+		Help:      "Number of bytes that received messages were decompressed into",
+	})
+	m.compressionTime = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: constants.PlatformName,
+		Name:      "compression_time",
+		Help:      "Time spent compressing a message in nanoseconds",
+		Buckets:   metric.NanosecondsBuckets,
+	})
+	m.decompressionTime = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: constants.PlatformName,
+		Name:      "decompression_time",
+		Help:      "Time spent decompressing a message in nanoseconds",
+		Buckets:   metric.NanosecondsBuckets,
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
@@ -129,9 +198,21 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		registerer.Register(m.failedToParse),
 		registerer.Register(m.connected),
 		registerer.Register(m.disconnected),
+		registerer.Register(m.peersByCountry),
+		registerer.Register(m.peersByASN),
+		registerer.Register(m.compressionInputBytes),
+		registerer.Register(m.compressionOutputBytes),
+		registerer.Register(m.decompressionInputBytes),
+		registerer.Register(m.decompressionOutputBytes),
+		registerer.Register(m.compressionTime),
+		registerer.Register(m.decompressionTime),
+		m.sendQueueMetrics.initialize(registerer),
 
 		m.getVersion.initialize(GetVersion, registerer),
 		m.version.initialize(Version, registerer),
+		m.epochSchedule.initialize(EpochSchedule, registerer),
+		m.compression.initialize(Compression, registerer),
+		m.capabilities.initialize(Capabilities, registerer),
 		m.getPeerlist.initialize(GetPeerList, registerer),
 		m.peerList.initialize(PeerList, registerer),
 		m.ping.initialize(Ping, registerer),
@@ -142,21 +223,94 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		m.accepted.initialize(Accepted, registerer),
 		m.getAncestors.initialize(GetAncestors, registerer),
 		m.multiPut.initialize(MultiPut, registerer),
+		m.getStateSummary.initialize(GetStateSummary, registerer),
+		m.stateSummary.initialize(StateSummary, registerer),
 		m.get.initialize(Get, registerer),
 		m.put.initialize(Put, registerer),
 		m.pushQuery.initialize(PushQuery, registerer),
 		m.pullQuery.initialize(PullQuery, registerer),
 		m.chits.initialize(Chits, registerer),
 	)
+
+	if m.peerMetricsEnabled {
+		labels := []string{peerMetricsNodeIDLabel, peerMetricsOpLabel}
+		m.peerSentBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.PlatformName,
+			Name:      "peer_sent_bytes",
+			Help:      "Number of bytes of messages sent to each peer",
+		}, labels)
+		m.peerReceivedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.PlatformName,
+			Name:      "peer_received_bytes",
+			Help:      "Number of bytes of messages received from each peer",
+		}, labels)
+		m.peerResponseLatency = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace: constants.PlatformName,
+			Name:      "peer_response_latency",
+			Help:      "Time it took each peer to respond to requests in milliseconds",
+		}, labels)
+		errs.Add(
+			registerer.Register(m.peerSentBytes),
+			registerer.Register(m.peerReceivedBytes),
+			registerer.Register(m.peerResponseLatency),
+		)
+	}
 	return errs.Err
 }
 
+// observeSent records that a message of type [op] and size [msgLen] was sent
+// to [nodeID]
+func (m *metrics) observeSent(nodeID ids.ShortID, op Op, msgLen int) {
+	if !m.peerMetricsEnabled {
+		return
+	}
+	m.peerSentBytes.WithLabelValues(nodeID.PrefixedString(constants.NodeIDPrefix), op.String()).Add(float64(msgLen))
+}
+
+// observeReceived records that a message of type [op] and size [msgLen] was
+// received from [nodeID]
+func (m *metrics) observeReceived(nodeID ids.ShortID, op Op, msgLen int) {
+	if !m.peerMetricsEnabled {
+		return
+	}
+	m.peerReceivedBytes.WithLabelValues(nodeID.PrefixedString(constants.NodeIDPrefix), op.String()).Add(float64(msgLen))
+}
+
+// observeResponse records that [nodeID] took [latency] to respond to a request
+// of type [op]
+func (m *metrics) observeResponse(nodeID ids.ShortID, op Op, latency time.Duration) {
+	if !m.peerMetricsEnabled {
+		return
+	}
+	m.peerResponseLatency.WithLabelValues(nodeID.PrefixedString(constants.NodeIDPrefix), op.String()).Observe(float64(latency.Milliseconds()))
+}
+
+// removePeer removes the metrics of the messages of types [ops] exchanged
+// with [nodeID]
+func (m *metrics) removePeer(nodeID ids.ShortID, ops []Op) {
+	if !m.peerMetricsEnabled {
+		return
+	}
+	nodeIDStr := nodeID.PrefixedString(constants.NodeIDPrefix)
+	for _, op := range ops {
+		m.peerSentBytes.DeleteLabelValues(nodeIDStr, op.String())
+		m.peerReceivedBytes.DeleteLabelValues(nodeIDStr, op.String())
+		m.peerResponseLatency.DeleteLabelValues(nodeIDStr, op.String())
+	}
+}
+
 func (m *metrics) message(msgType Op) *messageMetrics {
 	switch msgType {
 	case GetVersion:
 		return &m.getVersion
 	case Version:
 		return &m.version
+	case EpochSchedule:
+		return &m.epochSchedule
+	case Compression:
+		return &m.compression
+	case Capabilities:
+		return &m.capabilities
 	case GetPeerList:
 		return &m.getPeerlist
 	case PeerList:
@@ -177,6 +331,10 @@ func (m *metrics) message(msgType Op) *messageMetrics {
 		return &m.getAncestors
 	case MultiPut:
 		return &m.multiPut
+	case GetStateSummary:
+		return &m.getStateSummary
+	case StateSummary:
+		return &m.stateSummary
 	case Get:
 		return &m.get
 	case Put:
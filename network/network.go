@@ -21,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/geoip"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
@@ -61,6 +62,7 @@ const (
 var (
 	errNetworkClosed         = errors.New("network closed")
 	errPeerIsMyself          = errors.New("peer is myself")
+	errPeerIsBanned          = errors.New("peer is banned")
 	errNetworkLayerUnhealthy = errors.New("network layer is unhealthy")
 )
 
@@ -96,6 +98,10 @@ type Network interface {
 	// is empty. Thread safety must be managed internally to the network.
 	Peers(nodeIDs []ids.ShortID) []PeerID
 
+	// Returns the number of connected peers in each country and autonomous
+	// system. Thread safety must be managed internally to the network.
+	Topology() Topology
+
 	// Close this network and all existing connections it has. Thread safety
 	// must be managed internally to the network. Calling close multiple times
 	// will return a nil error.
@@ -231,6 +237,33 @@ type network struct {
 
 	// Rate-limits outgoing messages
 	outboundMsgThrottler throttling.OutboundMsgThrottler
+
+	// Resolves the location of peers
+	geoIPResolver geoip.Resolver
+
+	// Estimates the network time from the times reported by peers
+	networkClock *timer.NetworkClock
+
+	// Epoch schedule of the network, from the genesis. Peers must report the
+	// same schedule during the handshake.
+	epochFirstTransition time.Time
+	epochDuration        time.Duration
+
+	// Describes which messages are compressed before they're sent to peers
+	compressionConfig CompressionConfig
+
+	// Scores the misbehavior of peers, and bans peers that misbehave too much
+	scorer Scorer
+
+	// Limits the inbound connections of each IP, and drops the connections
+	// of banned IPs
+	inboundConnThrottler throttling.InboundConnThrottler
+
+	// Persists the peers we connected to, so that we can reconnect to them
+	// after a restart
+	peerStore PeerStore
+	// Max number of stored peers to reconnect to when the network starts
+	maxReconnectPeers int
 }
 
 type Config struct {
@@ -238,8 +271,18 @@ type Config struct {
 	InboundThrottlerConfig  throttling.MsgThrottlerConfig
 	OutboundThrottlerConfig throttling.MsgThrottlerConfig
 	timer.AdaptiveTimeoutConfig
-	DialerConfig     dialer.Config
-	MetricsNamespace string
+	DialerConfig               dialer.Config
+	GeoIPResolver              geoip.Resolver
+	CompressionConfig          CompressionConfig
+	ScoringConfig              ScoringConfig
+	InboundConnThrottlerConfig throttling.InboundConnThrottlerConfig
+	// Max number of stored peers reconnected to on startup. If 0, peers
+	// aren't stored.
+	MaxReconnectPeers int
+	// If true, report the bytes exchanged with each peer and the latency of
+	// its responses in Prometheus metrics labelled with the peer's node ID
+	PeerMetricsEnabled bool
+	MetricsNamespace   string
 	// [Registerer] is set in node's initMetricsAPI method
 	MetricsRegisterer prometheus.Registerer
 }
@@ -276,6 +319,16 @@ func NewDefaultNetwork(
 	gossipOnAcceptSize uint,
 	inboundMsgThrottler throttling.InboundMsgThrottler,
 	outboundMsgThrottler throttling.OutboundMsgThrottler,
+	geoIPResolver geoip.Resolver,
+	networkClock *timer.NetworkClock,
+	epochFirstTransition time.Time,
+	epochDuration time.Duration,
+	compressionConfig CompressionConfig,
+	scorer Scorer,
+	inboundConnThrottler throttling.InboundConnThrottler,
+	peerStore PeerStore,
+	maxReconnectPeers int,
+	peerMetricsEnabled bool,
 ) Network {
 	return NewNetwork(
 		registerer,
@@ -321,6 +374,16 @@ func NewDefaultNetwork(
 		isFetchOnly,
 		inboundMsgThrottler,
 		outboundMsgThrottler,
+		geoIPResolver,
+		networkClock,
+		epochFirstTransition,
+		epochDuration,
+		compressionConfig,
+		scorer,
+		inboundConnThrottler,
+		peerStore,
+		maxReconnectPeers,
+		peerMetricsEnabled,
 	)
 }
 
@@ -369,6 +432,16 @@ func NewNetwork(
 	isFetchOnly bool,
 	inboundMsgThrottler throttling.InboundMsgThrottler,
 	outboundMsgThrottler throttling.OutboundMsgThrottler,
+	geoIPResolver geoip.Resolver,
+	networkClock *timer.NetworkClock,
+	epochFirstTransition time.Time,
+	epochDuration time.Duration,
+	compressionConfig CompressionConfig,
+	scorer Scorer,
+	inboundConnThrottler throttling.InboundConnThrottler,
+	peerStore PeerStore,
+	maxReconnectPeers int,
+	peerMetricsEnabled bool,
 ) Network {
 	// #nosec G404
 	netw := &network{
@@ -427,6 +500,15 @@ func NewNetwork(
 		},
 		inboundMsgThrottler:  inboundMsgThrottler,
 		outboundMsgThrottler: outboundMsgThrottler,
+		geoIPResolver:        geoIPResolver,
+		networkClock:         networkClock,
+		epochFirstTransition: epochFirstTransition,
+		epochDuration:        epochDuration,
+		compressionConfig:    compressionConfig,
+		scorer:               scorer,
+		inboundConnThrottler: inboundConnThrottler,
+		peerStore:            peerStore,
+		maxReconnectPeers:    maxReconnectPeers,
 	}
 	netw.b = Builder{
 		getByteSlice: func() []byte {
@@ -435,6 +517,7 @@ func NewNetwork(
 	}
 	netw.peers.initialize()
 	netw.sendFailRateCalculator = math.NewSyncAverager(math.NewAverager(0, healthConfig.MaxSendFailRateHalflife, netw.clock.Time()))
+	netw.peerMetricsEnabled = peerMetricsEnabled
 	if err := netw.initialize(registerer); err != nil {
 		log.Warn("initializing network metrics failed with: %s", err)
 	}
@@ -634,6 +717,64 @@ func (n *network) MultiPut(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 	}
 }
 
+// GetStateSummary implements the Sender interface.
+// Assumes [n.stateLock] is not held.
+func (n *network) GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration) bool {
+	now := n.clock.Time()
+
+	msg, err := n.b.GetStateSummary(chainID, requestID, uint64(deadline))
+	if err != nil {
+		n.log.Error("failed to build GetStateSummary message: %s", err)
+		n.sendFailRateCalculator.Observe(1, now)
+		return false
+	}
+
+	peer := n.getPeer(validatorID)
+	lenMsg := len(msg.Bytes())
+	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.supports(StateSyncCapability) || !peer.Send(msg, true) {
+		n.log.Debug("failed to send GetStateSummary(%s, %s, %d)",
+			validatorID,
+			chainID,
+			requestID)
+		n.getStateSummary.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, now)
+		return false
+	}
+	n.getStateSummary.numSent.Inc()
+	n.sendFailRateCalculator.Observe(0, now)
+	n.getStateSummary.sentBytes.Add(float64(lenMsg))
+	return true
+}
+
+// StateSummary implements the Sender interface.
+// Assumes [n.stateLock] is not held.
+func (n *network) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, frontier [][]byte, summary []byte) {
+	now := n.clock.Time()
+
+	msg, err := n.b.StateSummary(chainID, requestID, frontier, summary)
+	if err != nil {
+		n.log.Error("failed to build StateSummary message because of a summary of size %d", len(summary))
+		n.sendFailRateCalculator.Observe(1, now)
+		return
+	}
+
+	peer := n.getPeer(validatorID)
+	lenMsg := len(msg.Bytes())
+	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+		n.log.Debug("failed to send StateSummary(%s, %s, %d, %d)",
+			validatorID,
+			chainID,
+			requestID,
+			len(summary))
+		n.stateSummary.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, now)
+	} else {
+		n.stateSummary.numSent.Inc()
+		n.sendFailRateCalculator.Observe(0, now)
+		n.stateSummary.sentBytes.Add(float64(lenMsg))
+	}
+}
+
 // Get implements the Sender interface.
 // Assumes [n.stateLock] is not held.
 func (n *network) Get(nodeID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool {
@@ -855,6 +996,7 @@ func (n *network) shouldUpgradeIncoming(ipStr string) bool {
 // to this node.
 // Assumes [n.stateLock] is not held.
 func (n *network) Dispatch() error {
+	n.reconnectStoredPeers()
 	go n.gossipPeerList() // Periodically gossip peers
 	go func() {
 		duration := time.Until(n.versionCompatibility.MaskTime())
@@ -920,8 +1062,16 @@ func (n *network) Dispatch() error {
 			}
 		}
 
+		// Reads from [throttledConn] are rate-limited
+		throttledConn, upgrade := n.inboundConnThrottler.Accept(ipStr, conn)
+		if !upgrade {
+			n.log.Debug("not upgrading connection to %s due to per-IP limits", ipStr)
+			_ = conn.Close()
+			continue
+		}
+
 		go func() {
-			if err := n.upgrade(newPeer(n, conn, utils.IPDesc{}), n.serverUpgrader); err != nil {
+			if err := n.upgrade(newPeer(n, throttledConn, utils.IPDesc{}), n.serverUpgrader); err != nil {
 				n.log.Verbo("failed to upgrade connection: %s", err)
 			}
 		}()
@@ -941,15 +1091,7 @@ func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
 		peers := make([]PeerID, 0, n.peers.size())
 		for _, peer := range n.peers.peersList {
 			if peer.finishedHandshake.GetValue() {
-				peers = append(peers, PeerID{
-					IP:           peer.conn.RemoteAddr().String(),
-					PublicIP:     peer.getIP().String(),
-					ID:           peer.nodeID.PrefixedString(constants.NodeIDPrefix),
-					Version:      peer.versionStr.GetValue().(string),
-					LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
-					LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
-					Benched:      n.benchlistManager.GetBenched(peer.nodeID),
-				})
+				peers = append(peers, n.peerID(peer))
 			}
 		}
 		return peers
@@ -958,20 +1100,34 @@ func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
 	peers := make([]PeerID, 0, len(nodeIDs))
 	for _, nodeID := range nodeIDs { // Return info about given peers
 		if peer, ok := n.peers.getByID(nodeID); ok && peer.finishedHandshake.GetValue() {
-			peers = append(peers, PeerID{
-				IP:           peer.conn.RemoteAddr().String(),
-				PublicIP:     peer.getIP().String(),
-				ID:           peer.nodeID.PrefixedString(constants.NodeIDPrefix),
-				Version:      peer.versionStr.GetValue().(string),
-				LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
-				LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
-				Benched:      n.benchlistManager.GetBenched(peer.nodeID),
-			})
+			peers = append(peers, n.peerID(peer))
 		}
 	}
 	return peers
 }
 
+// Returns the description of [peer].
+// Assumes [n.stateLock] is held.
+func (n *network) peerID(peer *peer) PeerID {
+	peerID := PeerID{
+		IP:           peer.conn.RemoteAddr().String(),
+		PublicIP:     peer.getIP().String(),
+		ID:           peer.nodeID.PrefixedString(constants.NodeIDPrefix),
+		Version:      peer.versionStr.GetValue().(string),
+		LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
+		LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+		Benched:      n.benchlistManager.GetBenched(peer.nodeID),
+		Score:        n.scorer.Score(peer.nodeID),
+		Messages:     peer.stats.snapshot(),
+		Capabilities: peer.capabilityList(),
+	}
+	if peer.hasLocation {
+		location := peer.location
+		peerID.Location = &location
+	}
+	return peerID
+}
+
 // Close implements the Network interface
 // Assumes [n.stateLock] is not held.
 func (n *network) Close() error {
@@ -1375,6 +1531,10 @@ func (n *network) tryAddPeer(p *peer) error {
 		return errPeerIsMyself
 	}
 
+	if n.scorer.IsBanned(p.nodeID) {
+		return fmt.Errorf("%w: %s", errPeerIsBanned, p.nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+
 	// If I am already connected to this peer, then I should close this new
 	// connection and add an alias record.
 	if peer, ok := n.peers.getByID(p.nodeID); ok {
@@ -1507,7 +1667,13 @@ func (n *network) connected(p *peer) {
 		n.connectedIPs[str] = struct{}{}
 	}
 
+	n.locate(p)
+	n.storePeer(p)
+
 	n.router.Connected(p.nodeID)
+	// Observed after the router is told about the peer, so that the peer's
+	// weight is known when staking is disabled
+	n.networkClock.Observe(p.nodeID, p.peerTime, p.localTime)
 	n.metrics.connected.Inc()
 }
 
@@ -1539,11 +1705,120 @@ func (n *network) disconnected(p *peer) {
 
 	// Only send Disconnected to router if Connected was sent
 	if p.finishedHandshake.GetValue() {
+		n.storePeer(p)
+		n.unlocate(p)
+		n.networkClock.Remove(p.nodeID)
+		n.removePeer(p.nodeID, p.stats.ops())
 		n.router.Disconnected(p.nodeID)
 	}
 	n.metrics.disconnected.Inc()
 }
 
+// Records that we were connected to [p] at its IP, so that we can reconnect
+// to it after a restart. Only IPs that we dialed, or that the peer signed and
+// connected from, are recorded.
+// Assumes [n.stateLock] is held.
+func (n *network) storePeer(p *peer) {
+	ip := p.getIP()
+	if ip.IsZero() || (!n.allowPrivateIPs && ip.IsPrivate()) {
+		return
+	}
+	err := n.peerStore.Put(StoredPeer{
+		NodeID:   p.nodeID,
+		IP:       ip,
+		Version:  p.versionStr.GetValue().(string),
+		LastSeen: n.clock.Time(),
+	})
+	if err != nil {
+		n.log.Debug("failed to store peer %s%s: %s", constants.NodeIDPrefix, p.nodeID, err)
+	}
+}
+
+// Starts connecting to the most recently seen stored peers, up to
+// [n.maxReconnectPeers] of them. Stored peers that shouldn't be connected to
+// anymore are removed from the store.
+// Assumes [n.stateLock] is not held.
+func (n *network) reconnectStoredPeers() {
+	if n.maxReconnectPeers <= 0 {
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	now := n.clock.Time()
+	ips := make(map[string]struct{})
+	subnets := make(map[string]int)
+	numReconnecting := 0
+	for _, storedPeer := range n.peerStore.Peers() {
+		if numReconnecting >= n.maxReconnectPeers {
+			break
+		}
+		if err := n.validateStoredPeer(storedPeer, now); err != nil {
+			n.log.Debug("removing stored peer %s%s at %s: %s",
+				constants.NodeIDPrefix, storedPeer.NodeID, storedPeer.IP, err)
+			if err := n.peerStore.Remove(storedPeer.NodeID); err != nil {
+				n.log.Debug("failed to remove stored peer %s%s: %s", constants.NodeIDPrefix, storedPeer.NodeID, err)
+			}
+			continue
+		}
+
+		// Peers seen more recently take precedence over peers stored with the
+		// same IP, or in the same subnet
+		ipStr := storedPeer.IP.String()
+		if _, ok := ips[ipStr]; ok {
+			continue
+		}
+		subnet := subnetOf(storedPeer.IP.IP)
+		if subnets[subnet] >= maxReconnectPeersPerSubnet {
+			continue
+		}
+		ips[ipStr] = struct{}{}
+		subnets[subnet]++
+
+		n.log.Verbo("reconnecting to stored peer %s%s at %s", constants.NodeIDPrefix, storedPeer.NodeID, storedPeer.IP)
+		n.track(storedPeer.IP, storedPeer.NodeID)
+		numReconnecting++
+	}
+	if numReconnecting > 0 {
+		n.log.Info("reconnecting to %d stored peers", numReconnecting)
+	}
+}
+
+// Returns an error if [storedPeer] shouldn't be reconnected to. A stored
+// peer could have been written by an older version of this node, or the
+// store could have been tampered with, so the stored IPs are checked as
+// thoroughly as gossiped ones.
+// Assumes [n.stateLock] is held.
+func (n *network) validateStoredPeer(storedPeer StoredPeer, now time.Time) error {
+	ip := storedPeer.IP
+	switch {
+	case storedPeer.NodeID == n.id:
+		return errStoredPeerMyself
+	case ip.IsZero():
+		return errStoredPeerZeroIP
+	case !n.allowPrivateIPs && ip.IsPrivate():
+		return errStoredPeerPrivateIP
+	case ip.Equal(n.ip.IP()):
+		return errStoredPeerMyIP
+	case now.Sub(storedPeer.LastSeen) > maxStoredPeerAge:
+		return errStoredPeerTooOld
+	case storedPeer.LastSeen.Sub(now) > n.maxClockDifference:
+		return errStoredPeerFuture
+	case n.scorer.IsBanned(storedPeer.NodeID):
+		return fmt.Errorf("%w: %s", errPeerIsBanned, storedPeer.NodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	if _, ok := n.myIPs[ip.String()]; ok {
+		return errStoredPeerMyIP
+	}
+
+	peerVersion, err := n.parser.Parse(storedPeer.Version)
+	if err != nil {
+		return err
+	}
+	return n.versionCompatibility.Compatible(peerVersion)
+}
+
 // holds onto the peer object as a result of helper functions
 type PeerElement struct {
 	// the peer, if it wasn't a peer when we cloned the list this value will be
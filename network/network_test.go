@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/geoip"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -29,6 +30,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -267,6 +269,16 @@ func initCerts(t *testing.T) {
 var (
 	defaultInboundMsgThrottler  = throttling.NewNoInboundThrottler()
 	defaultOutboundMsgThrottler = throttling.NewNoOutboundThrottler()
+	defaultGeoIPResolver        = &geoip.NoResolver{}
+	defaultNetworkClock         = timer.NewNetworkClock(validators.NewSet(), 1)
+	defaultEpochFirstTransition = time.Unix(1607626800, 0)
+	defaultEpochDuration        = 6 * time.Hour
+	defaultCompressionConfig    = CompressionConfig{}
+	defaultScorer               = NewNoScorer()
+	defaultInboundConnThrottler = throttling.NewNoInboundConnThrottler()
+	defaultPeerStore            = NewNoPeerStore()
+	defaultMaxReconnectPeers    = 0
+	defaultPeerMetricsEnabled   = false
 )
 
 func TestNewDefaultNetwork(t *testing.T) {
@@ -343,6 +355,16 @@ func TestNewDefaultNetwork(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net)
 
@@ -478,6 +500,16 @@ func TestEstablishConnection(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -511,6 +543,16 @@ func TestEstablishConnection(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -657,6 +699,16 @@ func TestDoubleTrack(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -690,6 +742,16 @@ func TestDoubleTrack(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -837,6 +899,16 @@ func TestDoubleClose(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -870,6 +942,16 @@ func TestDoubleClose(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -1022,6 +1104,16 @@ func TestTrackConnected(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -1055,6 +1147,16 @@ func TestTrackConnected(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -1181,6 +1283,16 @@ func TestTrackConnectedRace(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -1214,6 +1326,16 @@ func TestTrackConnectedRace(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -1471,6 +1593,16 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -1504,6 +1636,16 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -1537,6 +1679,16 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net2)
 
@@ -1570,6 +1722,16 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net3)
 
@@ -1924,6 +2086,16 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -1957,6 +2129,16 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -1990,6 +2172,16 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net2)
 
@@ -2023,6 +2215,16 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net3)
 
@@ -2293,6 +2495,16 @@ func TestPeerSignature(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -2326,6 +2538,16 @@ func TestPeerSignature(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -2359,6 +2581,16 @@ func TestPeerSignature(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net2)
 
@@ -2736,6 +2968,16 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net0)
 
@@ -2769,6 +3011,16 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, net1)
 
@@ -2799,6 +3051,193 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDisconnectOnMismatchedEpochSchedule(t *testing.T) {
+	initCerts(t)
+
+	log := logging.NoLog{}
+	networkID := uint32(0)
+	appVersion := version.NewDefaultApplication("app", 1, 4, 7)
+
+	serverUpgrader0 := NewTLSServerUpgrader(tlsConfig0)
+	clientUpgrader0 := NewTLSClientUpgrader(tlsConfig0)
+
+	serverUpgrader1 := NewTLSServerUpgrader(tlsConfig1)
+	clientUpgrader1 := NewTLSClientUpgrader(tlsConfig1)
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+
+	id0 := certToID(cert0.Leaf)
+	id1 := certToID(cert1.Leaf)
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+		closer:    func(net.Addr, net.Addr) { listener0.Close() },
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	vdrs := validators.NewSet()
+	assert.NoError(t, vdrs.AddWeight(id1, 1))
+	assert.NoError(t, vdrs.AddWeight(id0, 1))
+
+	versionManager := version.NewCompatibility(
+		appVersion,
+		appVersion,
+		time.Now(),
+		appVersion,
+		appVersion,
+		time.Now(),
+		appVersion,
+	)
+
+	net0 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id0,
+		ip0,
+		networkID,
+		versionManager,
+		version.NewDefaultApplicationParser(),
+		listener0,
+		caller0,
+		serverUpgrader0,
+		clientUpgrader0,
+		vdrs,
+		vdrs,
+		&testHandler{},
+		time.Duration(0),
+		0,
+		defaultSendQueueSize,
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert0.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
+	)
+	assert.NotNil(t, net0)
+
+	net1 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id1,
+		ip1,
+		networkID,
+		versionManager,
+		version.NewDefaultApplicationParser(),
+		listener1,
+		caller1,
+		serverUpgrader1,
+		clientUpgrader1,
+		vdrs,
+		vdrs,
+		&testHandler{},
+		time.Duration(0),
+		0,
+		defaultSendQueueSize,
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert1.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		5*time.Minute,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
+	)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	// net1 connects to net0
+	// they start the handshake and exchange epoch schedules
+	// net1 sees that net0's epoch schedule differs and closes the connection
+	net1.Track(ip0.IP(), id0)
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Error("should have closed immediately because the epoch schedules differ")
+	case <-listener0.closed:
+	}
+
+	// Cleanup
+	err := net0.Close()
+	assert.NoError(t, err)
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
 // Helper method for TestValidatorIPs
 func createPeer(peerID ids.ShortID, peerIPDesc utils.IPDesc, peerVersion version.Application) *peer {
 	newPeer := peer{
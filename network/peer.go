@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"crypto/x509"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"math"
 	"net"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/geoip"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -75,8 +77,8 @@ type peer struct {
 	// if the close function has been called.
 	closed utils.AtomicBool
 
-	// queue of messages to be sent to this peer
-	sendQueue [][]byte
+	// queue of messages to be sent to this peer, by priority
+	sendQueue sendQueue
 
 	// Signalled when a message is added to [sendQueue],
 	// and when [p.closed] is set to true.
@@ -114,6 +116,9 @@ type peer struct {
 	// Must only be accessed atomically
 	lastSent, lastReceived int64
 
+	// Messages exchanged with this peer, and the latency of its responses
+	stats peerStats
+
 	tickerCloser chan struct{}
 
 	// ticker processes
@@ -134,11 +139,33 @@ type peer struct {
 	// Should be cleared before use.
 	// Should only be used in peer's reader goroutine.
 	idSet ids.Set
+
+	// Location of this peer's connection. Only populated if the network has a
+	// geoip resolver and the connection's IP is covered by it.
+	// Set when the peer finishes the handshake. [net.stateLock] must be held
+	// when accessing [location] or [hasLocation].
+	location    geoip.Record
+	hasLocation bool
+
+	// Time this peer reported in its version message, and our time when the
+	// version message was handled. Set before the peer finishes the handshake.
+	peerTime, localTime time.Time
+
+	// CompressionType that compressible messages sent to this peer are
+	// compressed with. Set when the peer reports the compression types it
+	// supports. Must only be accessed atomically.
+	compressionType uint32
+
+	// Capabilities this peer reported. Must only be accessed atomically.
+	// [gotCapabilities] is set after [capabilities].
+	capabilities    uint64
+	gotCapabilities utils.AtomicBool
 }
 
 // newPeer returns a properly initialized *peer.
 func newPeer(net *network, conn net.Conn, ip utils.IPDesc) *peer {
 	p := &peer{
+		sendQueue:     newSendQueue(int(net.sendQueueSize), &net.sendQueueMetrics),
 		sendQueueCond: sync.NewCond(&sync.Mutex{}),
 		net:           net,
 		conn:          conn,
@@ -288,9 +315,25 @@ func (p *peer) ReadMessages() {
 			// Couldn't parse the message. Read the next one.
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			// Peers running a newer version may send ops that this node
+			// doesn't know
+			if err != errBadOp {
+				p.registerOffense(InvalidMessage)
+			}
 			continue
 		}
 
+		if msg.Op() == Compressed {
+			msg, err = p.decompress(msg)
+			if err != nil {
+				p.net.log.Verbo("failed to decompress message from %s%s at %s:\n%s\n%s", constants.NodeIDPrefix, p.nodeID, p.getIP(), formatting.DumpBytes{Bytes: msgBytes}, err)
+				onFinishedHandling()
+				p.net.metrics.failedToParse.Inc()
+				p.registerOffense(InvalidMessage)
+				continue
+			}
+		}
+
 		// Handle the message. Note that when we are done handling
 		// this message, we must call [p.net.msgThrottler.Release]
 		// to release the bytes used by this message. See MsgThrottler.
@@ -313,15 +356,14 @@ func (p *peer) WriteMessages() {
 				p.sendQueueCond.L.Unlock()
 				return
 			}
-			if len(p.sendQueue) > 0 {
+			if p.sendQueue.len() > 0 {
 				// There is a message to send
 				break
 			}
 			// Wait until there is a message to send
 			p.sendQueueCond.Wait()
 		}
-		msg := p.sendQueue[0]
-		p.sendQueue = p.sendQueue[1:]
+		msg, _ := p.sendQueue.pop()
 		p.sendQueueCond.L.Unlock()
 
 		msgLen := uint32(len(msg))
@@ -360,6 +402,13 @@ func (p *peer) WriteMessages() {
 // If ![canModifyMsg], [msg] will not be modified by this method.
 // [canModifyMsg] should be false if [msg] is sent in a loop, for example/.
 func (p *peer) Send(msg Msg, canModifyMsg bool) bool {
+	priority := sendPriorityOf(msg)
+	uncompressedMsg := msg
+	if compressedMsg, ok := p.compress(msg); ok {
+		// [compressedMsg] isn't referenced by the caller
+		msg = compressedMsg
+		canModifyMsg = true
+	}
 	msgBytes := msg.Bytes()
 	msgLen := int64(len(msgBytes))
 
@@ -389,11 +438,81 @@ func (p *peer) Send(msg Msg, canModifyMsg bool) bool {
 		copy(toSend, msgBytes)
 	}
 
-	p.sendQueue = append(p.sendQueue, toSend)
+	dropped, queued := p.sendQueue.push(priority, toSend)
+	if dropped != nil {
+		p.net.log.Debug("dropping a %s message to %s%s at %s due to a full send queue", priority, constants.NodeIDPrefix, p.nodeID, p.getIP())
+		p.net.outboundMsgThrottler.Release(uint64(len(dropped)), p.nodeID)
+	}
+	if !queued {
+		return false
+	}
 	p.sendQueueCond.Signal()
+
+	uncompressedLen := len(uncompressedMsg.Bytes())
+	p.stats.sent(uncompressedMsg, uncompressedLen, p.net.clock.Time())
+	p.net.observeSent(p.nodeID, uncompressedMsg.Op(), uncompressedLen)
 	return true
 }
 
+// compress returns [msg] compressed, and true, if [msg] should be sent to this
+// peer compressed. Returns false if [msg] should be sent as is.
+func (p *peer) compress(msg Msg) (Msg, bool) {
+	compressionType := CompressionType(atomic.LoadUint32(&p.compressionType))
+	if compressionType == NoCompression || !compressible(msg.Op()) || !p.supports(CompressionCapability) {
+		return nil, false
+	}
+	msgBytes := msg.Bytes()
+	if len(msgBytes) < p.net.compressionConfig.MinSize {
+		return nil, false
+	}
+
+	startTime := p.net.clock.Time()
+	compressedBytes, err := compress(compressionType, msgBytes)
+	if err != nil {
+		p.net.log.Debug("failed to compress %s message to %s%s at %s: %s", msg.Op(), constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return nil, false
+	}
+	compressedMsg, err := p.net.b.Compressed(compressionType, compressedBytes)
+	if err != nil {
+		p.net.log.Debug("failed to build compressed %s message to %s%s at %s: %s", msg.Op(), constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return nil, false
+	}
+	p.net.compressionTime.Observe(float64(p.net.clock.Time().Sub(startTime)))
+
+	lenCompressedMsg := len(compressedMsg.Bytes())
+	if lenCompressedMsg >= len(msgBytes) {
+		// [msg] doesn't compress well, so it's sent as is
+		return nil, false
+	}
+	p.net.compressionInputBytes.Add(float64(len(msgBytes)))
+	p.net.compressionOutputBytes.Add(float64(lenCompressedMsg))
+	return compressedMsg, true
+}
+
+// decompress returns the message wrapped by the Compressed message [msg]
+func (p *peer) decompress(msg Msg) (Msg, error) {
+	startTime := p.net.clock.Time()
+	msgBytes, err := decompress(
+		CompressionType(msg.Get(CompressionID).(byte)),
+		msg.Get(CompressedBytes).([]byte),
+		p.net.maxMessageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	decompressedMsg, err := p.net.b.Parse(msgBytes)
+	if err != nil {
+		return nil, err
+	}
+	if op := decompressedMsg.Op(); !compressible(op) {
+		return nil, fmt.Errorf("%w: %s", errNotCompressible, op)
+	}
+	p.net.decompressionTime.Observe(float64(p.net.clock.Time().Sub(startTime)))
+	p.net.decompressionInputBytes.Add(float64(len(msg.Bytes())))
+	p.net.decompressionOutputBytes.Add(float64(len(msgBytes)))
+	return decompressedMsg, nil
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) handle(msg Msg, onFinishedHandling func()) {
 	now := p.net.clock.Time()
@@ -401,6 +520,13 @@ func (p *peer) handle(msg Msg, onFinishedHandling func()) {
 	atomic.StoreInt64(&p.net.lastMsgReceivedTime, now.Unix())
 	msgLen := uint64(len(msg.Bytes()))
 
+	if p.net.scorer.IsBanned(p.nodeID) {
+		p.net.log.Debug("dropping %s from banned peer %s%s at %s", msg.Op(), constants.NodeIDPrefix, p.nodeID, p.getIP())
+		onFinishedHandling()
+		p.discardIP()
+		return
+	}
+
 	op := msg.Op()
 	msgMetrics := p.net.message(op)
 	if msgMetrics == nil {
@@ -410,6 +536,10 @@ func (p *peer) handle(msg Msg, onFinishedHandling func()) {
 	}
 	msgMetrics.numReceived.Inc()
 	msgMetrics.receivedBytes.Add(float64(msgLen))
+	p.net.observeReceived(p.nodeID, op, int(msgLen))
+	if requestOp, latency, ok := p.stats.received(msg, int(msgLen), now); ok {
+		p.net.observeResponse(p.nodeID, requestOp, latency)
+	}
 
 	switch op { // Network-related message types
 	case Version:
@@ -420,6 +550,18 @@ func (p *peer) handle(msg Msg, onFinishedHandling func()) {
 		p.handleGetVersion(msg)
 		onFinishedHandling()
 		return
+	case EpochSchedule:
+		p.handleEpochSchedule(msg)
+		onFinishedHandling()
+		return
+	case Compression:
+		p.handleCompression(msg)
+		onFinishedHandling()
+		return
+	case Capabilities:
+		p.handleCapabilities(msg)
+		onFinishedHandling()
+		return
 	case Ping:
 		p.handlePing(msg)
 		onFinishedHandling()
@@ -468,6 +610,10 @@ func (p *peer) handle(msg Msg, onFinishedHandling func()) {
 		p.handlePut(msg, onFinishedHandling)
 	case MultiPut:
 		p.handleMultiPut(msg, onFinishedHandling)
+	case GetStateSummary:
+		p.handleGetStateSummary(msg, onFinishedHandling)
+	case StateSummary:
+		p.handleStateSummary(msg, onFinishedHandling)
 	case PushQuery:
 		p.handlePushQuery(msg, onFinishedHandling)
 	case PullQuery:
@@ -498,10 +644,9 @@ func (p *peer) close() {
 
 	p.sendQueueCond.L.Lock()
 	// Release the bytes of the unsent messages to the outbound message throttler
-	for i := 0; i < len(p.sendQueue); i++ {
-		p.net.outboundMsgThrottler.Release(uint64(len(p.sendQueue[i])), p.nodeID)
+	for _, msg := range p.sendQueue.clear() {
+		p.net.outboundMsgThrottler.Release(uint64(len(msg)), p.nodeID)
 	}
-	p.sendQueue = nil
 	p.sendQueueCond.L.Unlock()
 	// Per [p.sendQueueCond]'s spec, it is signalled when [p.closed] is set to true
 	// so that we exit the WriteMessages goroutine.
@@ -554,12 +699,68 @@ func (p *peer) sendVersion() {
 		p.net.metrics.version.sentBytes.Add(float64(lenMsg))
 		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
 		p.versionSent.SetValue(true)
+		p.sendCapabilities()
+		p.sendEpochSchedule()
+		if p.net.compressionConfig.Enabled {
+			p.sendCompression()
+		}
 	} else {
 		p.net.metrics.version.numFailed.Inc()
 		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
 	}
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) sendEpochSchedule() {
+	msg, err := p.net.b.EpochSchedule(
+		uint64(p.net.epochFirstTransition.Unix()),
+		uint64(p.net.epochDuration/time.Second),
+	)
+	p.net.log.AssertNoError(err)
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.epochSchedule.numSent.Inc()
+		p.net.epochSchedule.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.epochSchedule.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendCompression() {
+	msg, err := p.net.b.Compression(supportedCompressionIDs)
+	p.net.log.AssertNoError(err)
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.compression.numSent.Inc()
+		p.net.compression.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.compression.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendCapabilities() {
+	msg, err := p.net.b.Capabilities(supportedCapabilities)
+	p.net.log.AssertNoError(err)
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.capabilities.numSent.Inc()
+		p.net.capabilities.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.capabilities.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) sendGetPeerList() {
 	msg, err := p.net.b.GetPeerList()
@@ -641,11 +842,78 @@ func (p *peer) handleGetVersion(_ Msg) {
 	}
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) handleEpochSchedule(msg Msg) {
+	peerFirstTransition := msg.Get(EpochFirstTransition).(uint64)
+	peerDuration := msg.Get(EpochDuration).(uint64)
+	myFirstTransition := uint64(p.net.epochFirstTransition.Unix())
+	myDuration := uint64(p.net.epochDuration / time.Second)
+	if peerFirstTransition == myFirstTransition && peerDuration == myDuration {
+		return
+	}
+
+	if p.net.beacons.Contains(p.nodeID) {
+		p.net.log.Warn(
+			"beacon %s%s at %s reports epoch schedule (first transition %d, duration %ds) that doesn't match ours (first transition %d, duration %ds)",
+			constants.NodeIDPrefix, p.nodeID, p.getIP(), peerFirstTransition, peerDuration, myFirstTransition, myDuration,
+		)
+	} else {
+		p.net.log.Debug(
+			"peer %s%s at %s reports epoch schedule (first transition %d, duration %ds) that doesn't match ours (first transition %d, duration %ds)",
+			constants.NodeIDPrefix, p.nodeID, p.getIP(), peerFirstTransition, peerDuration, myFirstTransition, myDuration,
+		)
+	}
+	p.discardIP()
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleCompression(msg Msg) {
+	if !p.net.compressionConfig.Enabled {
+		return
+	}
+	compressionType := selectCompression(msg.Get(CompressionIDs).([]byte))
+	atomic.StoreUint32(&p.compressionType, uint32(compressionType))
+	p.net.log.Verbo("compressing messages to %s%s at %s with %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), compressionType)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleCapabilities(msg Msg) {
+	if p.gotCapabilities.GetValue() {
+		p.net.log.Verbo("dropping duplicated capabilities message from %s%s at %s", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		p.registerOffense(ProtocolViolation)
+		return
+	}
+	// Capabilities this node doesn't know are kept, but never used
+	capabilities := msg.Get(CapabilitiesBitmap).(uint64)
+	atomic.StoreUint64(&p.capabilities, capabilities)
+	p.gotCapabilities.SetValue(true)
+	p.net.log.Verbo("%s%s at %s supports capabilities %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), Capability(capabilities))
+}
+
+// supports returns true if this peer supports [capability]. Peers that didn't
+// report their capabilities are assumed to support the legacy capabilities.
+func (p *peer) supports(capability Capability) bool {
+	if !p.gotCapabilities.GetValue() {
+		return legacyCapabilities.Contains(capability)
+	}
+	return Capability(atomic.LoadUint64(&p.capabilities)).Contains(capability)
+}
+
+// capabilityList returns the names of the capabilities this peer reported, or
+// nil if it didn't report them
+func (p *peer) capabilityList() []string {
+	if !p.gotCapabilities.GetValue() {
+		return nil
+	}
+	return Capability(atomic.LoadUint64(&p.capabilities)).List()
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) handleVersion(msg Msg) {
 	switch {
 	case p.gotVersion.GetValue():
 		p.net.log.Verbo("dropping duplicated version message from %s%s at %s", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		p.registerOffense(ProtocolViolation)
 		return
 	case msg.Get(NodeID).(uint32) == p.net.nodeID:
 		p.net.log.Debug("peer at %s has same node ID as me", p.getIP())
@@ -656,6 +924,7 @@ func (p *peer) handleVersion(msg Msg) {
 			"network ID of %s%s at %s (%d) doesn't match our's (%d)",
 			constants.NodeIDPrefix, p.nodeID, p.getIP(), msg.Get(NetworkID).(uint32), p.net.networkID,
 		)
+		p.registerOffense(FailedHandshake)
 		p.discardIP()
 		return
 	case p.closed.GetValue():
@@ -676,14 +945,18 @@ func (p *peer) handleVersion(msg Msg) {
 				constants.NodeIDPrefix, p.nodeID, p.getIP(), uint64(peerTime), uint64(myTime),
 			)
 		}
+		p.registerOffense(FailedHandshake)
 		p.discardIP()
 		return
 	}
+	p.peerTime = time.Unix(int64(peerTime), 0)
+	p.localTime = time.Unix(int64(myTime), 0)
 
 	peerVersionStr := msg.Get(VersionStr).(string)
 	peerVersion, err := p.net.parser.Parse(peerVersionStr)
 	if err != nil {
 		p.net.log.Debug("version of %s%s at %s could not be parsed: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.registerOffense(InvalidMessage)
 		p.discardIP()
 		p.net.metrics.failedToParse.Inc()
 		return
@@ -705,6 +978,7 @@ func (p *peer) handleVersion(msg Msg) {
 
 	if err := p.net.versionCompatibility.Compatible(peerVersion); err != nil {
 		p.net.log.Verbo("peer %s%s at %s version (%s) not compatible: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), peerVersion, err)
+		p.registerOffense(FailedHandshake)
 		p.discardIP()
 		return
 	}
@@ -724,6 +998,7 @@ func (p *peer) handleVersion(msg Msg) {
 			"peer %s%s at %s attempting to connect with version timestamp (%d) too far in the future",
 			constants.NodeIDPrefix, p.nodeID, p.getIP(), latestPeerIP.time,
 		)
+		p.registerOffense(FailedHandshake)
 		p.discardIP()
 		return
 	}
@@ -733,6 +1008,7 @@ func (p *peer) handleVersion(msg Msg) {
 	err = p.cert.CheckSignature(p.cert.SignatureAlgorithm, signed, sig)
 	if err != nil {
 		p.net.log.Debug("signature verification failed for %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		p.registerOffense(FailedHandshake)
 		p.discardIP()
 		return
 	}
@@ -898,6 +1174,7 @@ func (p *peer) handleAcceptedFrontier(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -907,6 +1184,7 @@ func (p *peer) handleAcceptedFrontier(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		containerIDs[i] = containerID
@@ -941,6 +1219,7 @@ func (p *peer) handleGetAccepted(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -950,6 +1229,7 @@ func (p *peer) handleGetAccepted(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		containerIDs[i] = containerID
@@ -984,6 +1264,7 @@ func (p *peer) handleAccepted(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -993,6 +1274,7 @@ func (p *peer) handleAccepted(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		containerIDs[i] = containerID
@@ -1080,6 +1362,40 @@ func (p *peer) handleMultiPut(msg Msg, onFinishedHandling func()) {
 	)
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) handleGetStateSummary(msg Msg, onFinishedHandling func()) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+
+	p.net.router.GetStateSummary(
+		p.nodeID,
+		chainID,
+		requestID,
+		deadline,
+		onFinishedHandling,
+	)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleStateSummary(msg Msg, onFinishedHandling func()) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	frontier := msg.Get(MultiContainerBytes).([][]byte)
+	summary := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.StateSummary(
+		p.nodeID,
+		chainID,
+		requestID,
+		frontier,
+		summary,
+		onFinishedHandling,
+	)
+}
+
 func (p *peer) handlePushQuery(msg Msg, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
 	p.net.log.AssertNoError(err)
@@ -1137,6 +1453,7 @@ func (p *peer) handleChits(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -1146,6 +1463,7 @@ func (p *peer) handleChits(msg Msg, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.registerOffense(ProtocolViolation)
 			return
 		}
 		containerIDs[i] = containerID
@@ -1171,6 +1489,17 @@ func (p *peer) tryMarkFinishedHandshake() {
 	}
 }
 
+// registerOffense registers that this peer committed [offense], and
+// disconnects from the peer if it's banned because of it.
+// assumes the [stateLock] is not held
+func (p *peer) registerOffense(offense Offense) {
+	if !p.net.scorer.RegisterOffense(p.nodeID, offense) {
+		return
+	}
+	p.net.log.Info("banning %s%s at %s after it committed an offense: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), offense)
+	p.discardIP()
+}
+
 func (p *peer) discardIP() {
 	// By clearing the IP, we will not attempt to reconnect to this peer
 	if ip := p.getIP(); !ip.IsZero() {
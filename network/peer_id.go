@@ -7,15 +7,23 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/geoip"
 )
 
 // PeerID ...
 type PeerID struct {
-	IP           string    `json:"ip"`
-	PublicIP     string    `json:"publicIP"`
-	ID           string    `json:"nodeID"`
-	Version      string    `json:"version"`
-	LastSent     time.Time `json:"lastSent"`
-	LastReceived time.Time `json:"lastReceived"`
-	Benched      []ids.ID  `json:"benched"`
+	IP           string        `json:"ip"`
+	PublicIP     string        `json:"publicIP"`
+	ID           string        `json:"nodeID"`
+	Version      string        `json:"version"`
+	LastSent     time.Time     `json:"lastSent"`
+	LastReceived time.Time     `json:"lastReceived"`
+	Benched      []ids.ID      `json:"benched"`
+	Score        float64       `json:"score"`
+	Location     *geoip.Record `json:"location,omitempty"`
+	// Optional features of the P2P protocol the peer supports. Nil if the
+	// peer didn't report them.
+	Capabilities []string `json:"capabilities"`
+	// Messages exchanged with the peer, by message type
+	Messages map[string]MessageStats `json:"messages"`
 }
@@ -0,0 +1,204 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	// Max number of requests sent to a peer that are waiting for a response
+	// whose latency is measured. Requests sent past this limit aren't
+	// measured.
+	maxPendingPeerRequests = 1024
+
+	// Weight of a new latency observation in the estimated latency of a
+	// peer's responses
+	peerLatencyEWMAWeight = 0.125
+)
+
+// responseOps maps the op of a request to the op of its response
+var responseOps = map[Op]Op{
+	GetAcceptedFrontier: AcceptedFrontier,
+	GetAccepted:         Accepted,
+	GetAncestors:        MultiPut,
+	GetStateSummary:     StateSummary,
+	Get:                 Put,
+	PushQuery:           Chits,
+	PullQuery:           Chits,
+}
+
+// MessageStats describes the messages of a type exchanged with a peer. Sizes
+// are of the messages before they are compressed.
+type MessageStats struct {
+	Sent          uint64 `json:"sent"`
+	SentBytes     uint64 `json:"sentBytes"`
+	Received      uint64 `json:"received"`
+	ReceivedBytes uint64 `json:"receivedBytes"`
+	// Number of responses the peer sent to requests of this type, and the
+	// estimated time it took the peer to respond. Only set for requests.
+	Responses uint64        `json:"responses,omitempty"`
+	Latency   time.Duration `json:"latency,omitempty"`
+}
+
+type requestKey struct {
+	chainID   ids.ID
+	requestID uint32
+}
+
+type pendingRequest struct {
+	op     Op
+	sent   time.Time
+	expiry time.Time
+}
+
+// peerStats counts the messages exchanged with a peer, and measures the time
+// it takes the peer to respond to requests. The zero value is ready to use.
+// peerStats is safe for concurrent access.
+type peerStats struct {
+	lock     sync.Mutex
+	messages map[Op]*MessageStats
+	pending  map[requestKey]pendingRequest
+}
+
+// sent records that [msg], of size [msgLen], was sent to the peer at [now]
+func (s *peerStats) sent(msg Msg, msgLen int, now time.Time) {
+	op := msg.Op()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.get(op)
+	stats.Sent++
+	stats.SentBytes += uint64(msgLen)
+
+	if _, isRequest := responseOps[op]; !isRequest {
+		return
+	}
+	key, ok := keyOf(msg)
+	if !ok {
+		return
+	}
+	deadline, ok := msg.Get(Deadline).(uint64)
+	if !ok {
+		return
+	}
+	if s.pending == nil {
+		s.pending = make(map[requestKey]pendingRequest)
+	}
+	if len(s.pending) >= maxPendingPeerRequests {
+		s.removeExpired(now)
+		if len(s.pending) >= maxPendingPeerRequests {
+			return
+		}
+	}
+	s.pending[key] = pendingRequest{
+		op:     op,
+		sent:   now,
+		expiry: now.Add(time.Duration(deadline)),
+	}
+}
+
+// received records that [msg], of size [msgLen], was received from the peer
+// at [now]. If [msg] responds to a request sent to the peer, returns the op of
+// the request, the time it took the peer to respond and true.
+func (s *peerStats) received(msg Msg, msgLen int, now time.Time) (Op, time.Duration, bool) {
+	op := msg.Op()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.get(op)
+	stats.Received++
+	stats.ReceivedBytes += uint64(msgLen)
+
+	key, ok := keyOf(msg)
+	if !ok {
+		return 0, 0, false
+	}
+	request, ok := s.pending[key]
+	if !ok || responseOps[request.op] != op {
+		return 0, 0, false
+	}
+	delete(s.pending, key)
+
+	latency := now.Sub(request.sent)
+	requestStats := s.get(request.op)
+	if requestStats.Responses == 0 {
+		requestStats.Latency = latency
+	} else {
+		requestStats.Latency += time.Duration(peerLatencyEWMAWeight * float64(latency-requestStats.Latency))
+	}
+	requestStats.Responses++
+	return request.op, latency, true
+}
+
+// snapshot returns the stats of the messages exchanged with the peer, by op
+func (s *peerStats) snapshot() map[string]MessageStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	messages := make(map[string]MessageStats, len(s.messages))
+	for op, stats := range s.messages {
+		messages[op.String()] = *stats
+	}
+	return messages
+}
+
+// ops returns the ops of the messages exchanged with the peer
+func (s *peerStats) ops() []Op {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ops := make([]Op, 0, len(s.messages))
+	for op := range s.messages {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// Assumes [s.lock] is held
+func (s *peerStats) get(op Op) *MessageStats {
+	if s.messages == nil {
+		s.messages = make(map[Op]*MessageStats)
+	}
+	stats, ok := s.messages[op]
+	if !ok {
+		stats = &MessageStats{}
+		s.messages[op] = stats
+	}
+	return stats
+}
+
+// Assumes [s.lock] is held
+func (s *peerStats) removeExpired(now time.Time) {
+	for key, request := range s.pending {
+		if now.After(request.expiry) {
+			delete(s.pending, key)
+		}
+	}
+}
+
+// keyOf returns the chain ID and request ID of [msg], if it has them
+func keyOf(msg Msg) (requestKey, bool) {
+	chainIDBytes, ok := msg.Get(ChainID).([]byte)
+	if !ok {
+		return requestKey{}, false
+	}
+	chainID, err := ids.ToID(chainIDBytes)
+	if err != nil {
+		return requestKey{}, false
+	}
+	requestID, ok := msg.Get(RequestID).(uint32)
+	if !ok {
+		return requestKey{}, false
+	}
+	return requestKey{
+		chainID:   chainID,
+		requestID: requestID,
+	}, true
+}
@@ -0,0 +1,119 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestPeerStats(t *testing.T) {
+	s := peerStats{}
+	now := time.Unix(1607626800, 0)
+	chainID := ids.ID{1}
+
+	get, err := TestBuilder.Get(chainID, 1, uint64(time.Second), ids.ID{2})
+	assert.NoError(t, err)
+	s.sent(get, 100, now)
+
+	pushQuery, err := TestBuilder.PushQuery(chainID, 2, uint64(time.Second), ids.ID{2}, []byte{3})
+	assert.NoError(t, err)
+	s.sent(pushQuery, 200, now)
+
+	// A Chits with the request ID of the Get doesn't respond to it
+	chits, err := TestBuilder.Chits(chainID, 1, nil)
+	assert.NoError(t, err)
+	_, _, ok := s.received(chits, 10, now.Add(time.Millisecond))
+	assert.False(t, ok)
+
+	put, err := TestBuilder.Put(chainID, 1, ids.ID{2}, []byte{3})
+	assert.NoError(t, err)
+	op, latency, ok := s.received(put, 300, now.Add(100*time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, Get, op)
+	assert.Equal(t, 100*time.Millisecond, latency)
+
+	// A request is only responded to once
+	_, _, ok = s.received(put, 300, now.Add(200*time.Millisecond))
+	assert.False(t, ok)
+
+	chits, err = TestBuilder.Chits(chainID, 2, nil)
+	assert.NoError(t, err)
+	op, latency, ok = s.received(chits, 10, now.Add(50*time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, PushQuery, op)
+	assert.Equal(t, 50*time.Millisecond, latency)
+
+	messages := s.snapshot()
+	assert.Equal(t, MessageStats{
+		Sent:      1,
+		SentBytes: 100,
+		Responses: 1,
+		Latency:   100 * time.Millisecond,
+	}, messages[Get.String()])
+	assert.Equal(t, MessageStats{
+		Received:      2,
+		ReceivedBytes: 600,
+	}, messages[Put.String()])
+	assert.Equal(t, MessageStats{
+		Received:      2,
+		ReceivedBytes: 20,
+	}, messages[Chits.String()])
+	assert.Equal(t, uint64(1), messages[PushQuery.String()].Responses)
+	assert.Len(t, s.ops(), 4)
+}
+
+func TestPeerStatsLatencyEstimate(t *testing.T) {
+	s := peerStats{}
+	now := time.Unix(1607626800, 0)
+	chainID := ids.ID{1}
+
+	for i, latency := range []time.Duration{100 * time.Millisecond, 900 * time.Millisecond} {
+		requestID := uint32(i)
+		get, err := TestBuilder.Get(chainID, requestID, uint64(time.Second), ids.ID{2})
+		assert.NoError(t, err)
+		s.sent(get, 100, now)
+		put, err := TestBuilder.Put(chainID, requestID, ids.ID{2}, []byte{3})
+		assert.NoError(t, err)
+		_, _, ok := s.received(put, 100, now.Add(latency))
+		assert.True(t, ok)
+	}
+
+	// 100ms + 0.125 * (900ms - 100ms)
+	assert.Equal(t, 200*time.Millisecond, s.snapshot()[Get.String()].Latency)
+}
+
+func TestPeerStatsMaxPendingRequests(t *testing.T) {
+	s := peerStats{}
+	now := time.Unix(1607626800, 0)
+	chainID := ids.ID{1}
+
+	for requestID := uint32(0); requestID < maxPendingPeerRequests; requestID++ {
+		get, err := TestBuilder.Get(chainID, requestID, uint64(time.Second), ids.ID{2})
+		assert.NoError(t, err)
+		s.sent(get, 100, now)
+	}
+
+	// The pending requests haven't expired, so this one isn't measured
+	get, err := TestBuilder.Get(chainID, maxPendingPeerRequests, uint64(time.Second), ids.ID{2})
+	assert.NoError(t, err)
+	s.sent(get, 100, now)
+	assert.Len(t, s.pending, maxPendingPeerRequests)
+	put, err := TestBuilder.Put(chainID, maxPendingPeerRequests, ids.ID{2}, []byte{3})
+	assert.NoError(t, err)
+	_, _, ok := s.received(put, 100, now)
+	assert.False(t, ok)
+
+	// Once they expire, they're removed to make room for new requests
+	later := now.Add(2 * time.Second)
+	s.sent(get, 100, later)
+	assert.Len(t, s.pending, 1)
+	_, latency, ok := s.received(put, 100, later.Add(time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, time.Millisecond, latency)
+}
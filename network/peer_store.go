@@ -0,0 +1,214 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	peerStoreCodecVersion = 0
+
+	// Max number of peers kept in the store. When a peer is stored past this
+	// limit, the peer seen the longest time ago is removed.
+	maxStoredPeers = 1024
+
+	// A stored peer that wasn't seen for this long isn't reconnected to
+	maxStoredPeerAge = 14 * 24 * time.Hour
+
+	// Max number of stored peers in the same subnet that are reconnected to.
+	// Stops a few hosts with many addresses from taking over the peers we
+	// reconnect to.
+	maxReconnectPeersPerSubnet = 2
+	ipv4SubnetBits             = 24
+	ipv6SubnetBits             = 48
+)
+
+var (
+	errUnknownPeerStoreCodec = errors.New("unknown peer store codec version")
+	errStoredPeerZeroIP      = errors.New("IP is zero")
+	errStoredPeerPrivateIP   = errors.New("IP is private")
+	errStoredPeerMyIP        = errors.New("IP is mine")
+	errStoredPeerMyself      = errors.New("peer is myself")
+	errStoredPeerTooOld      = errors.New("peer wasn't seen recently")
+	errStoredPeerFuture      = errors.New("peer was seen in the future")
+)
+
+// StoredPeer is a peer that this node was connected to
+type StoredPeer struct {
+	NodeID ids.ShortID
+	// IP the peer was connected to at. Either this node dialed the peer at
+	// this IP, or the peer signed this IP and connected from it.
+	IP       utils.IPDesc
+	Version  string
+	LastSeen time.Time
+}
+
+// PeerStore persists the peers this node was connected to, so that they can
+// be reconnected to after a restart without relying only on the beacons.
+// PeerStore is safe for concurrent access.
+type PeerStore interface {
+	// Put stores [peer], replacing the previously stored peer with the same
+	// node ID
+	Put(peer StoredPeer) error
+	// Remove the peer with [nodeID] from the store
+	Remove(nodeID ids.ShortID) error
+	// Peers returns the stored peers, the most recently seen first
+	Peers() []StoredPeer
+}
+
+type peerStore struct {
+	db database.Database
+
+	lock  sync.Mutex
+	peers map[ids.ShortID]StoredPeer
+}
+
+// NewPeerStore returns a PeerStore that persists peers in [db]. Stored
+// peers that can't be parsed are removed from [db].
+func NewPeerStore(db database.Database) (PeerStore, error) {
+	s := &peerStore{
+		db:    db,
+		peers: make(map[ids.ShortID]StoredPeer),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	var corrupted [][]byte
+	for it.Next() {
+		peer, err := parseStoredPeer(it.Key(), it.Value())
+		if err != nil {
+			corrupted = append(corrupted, append([]byte(nil), it.Key()...))
+			continue
+		}
+		s.peers[peer.NodeID] = peer
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("couldn't read stored peers: %w", err)
+	}
+	it.Release()
+
+	for _, key := range corrupted {
+		if err := db.Delete(key); err != nil {
+			return nil, fmt.Errorf("couldn't remove corrupted stored peer: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Put implements the PeerStore interface
+func (s *peerStore) Put(peer StoredPeer) error {
+	p := wrappers.Packer{MaxSize: wrappers.ShortLen + wrappers.IPLen + wrappers.ShortLen + len(peer.Version) + wrappers.LongLen}
+	p.PackShort(peerStoreCodecVersion)
+	p.PackIP(peer.IP)
+	p.PackStr(peer.Version)
+	p.PackLong(uint64(peer.LastSeen.Unix()))
+	if p.Errored() {
+		return p.Err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.db.Put(peer.NodeID[:], p.Bytes); err != nil {
+		return err
+	}
+	s.peers[peer.NodeID] = peer
+
+	if len(s.peers) <= maxStoredPeers {
+		return nil
+	}
+	oldest := peer
+	for _, storedPeer := range s.peers {
+		if storedPeer.LastSeen.Before(oldest.LastSeen) {
+			oldest = storedPeer
+		}
+	}
+	return s.remove(oldest.NodeID)
+}
+
+// Remove implements the PeerStore interface
+func (s *peerStore) Remove(nodeID ids.ShortID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.remove(nodeID)
+}
+
+// Assumes [s.lock] is held
+func (s *peerStore) remove(nodeID ids.ShortID) error {
+	delete(s.peers, nodeID)
+	return s.db.Delete(nodeID[:])
+}
+
+// Peers implements the PeerStore interface
+func (s *peerStore) Peers() []StoredPeer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	peers := make([]StoredPeer, 0, len(s.peers))
+	for _, peer := range s.peers {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].LastSeen.After(peers[j].LastSeen)
+	})
+	return peers
+}
+
+func parseStoredPeer(key, value []byte) (StoredPeer, error) {
+	nodeID, err := ids.ToShortID(key)
+	if err != nil {
+		return StoredPeer{}, err
+	}
+
+	p := wrappers.Packer{Bytes: value}
+	if codecVersion := p.UnpackShort(); codecVersion != peerStoreCodecVersion && !p.Errored() {
+		return StoredPeer{}, fmt.Errorf("%w: %d", errUnknownPeerStoreCodec, codecVersion)
+	}
+	ip := p.UnpackIP()
+	version := p.UnpackStr()
+	lastSeen := p.UnpackLong()
+	if p.Errored() {
+		return StoredPeer{}, p.Err
+	}
+	if p.Offset != len(value) {
+		return StoredPeer{}, fmt.Errorf("%d trailing bytes", len(value)-p.Offset)
+	}
+	return StoredPeer{
+		NodeID:   nodeID,
+		IP:       ip,
+		Version:  version,
+		LastSeen: time.Unix(int64(lastSeen), 0),
+	}, nil
+}
+
+// subnetOf returns the subnet of [ip] in which at most
+// [maxReconnectPeersPerSubnet] peers are reconnected to
+func subnetOf(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(ipv4SubnetBits, 8*net.IPv4len)).String()
+	}
+	return ip.Mask(net.CIDRMask(ipv6SubnetBits, 8*net.IPv6len)).String()
+}
+
+type noPeerStore struct{}
+
+// NewNoPeerStore returns a PeerStore that doesn't store any peer
+func NewNoPeerStore() PeerStore { return &noPeerStore{} }
+
+func (noPeerStore) Put(StoredPeer) error     { return nil }
+func (noPeerStore) Remove(ids.ShortID) error { return nil }
+func (noPeerStore) Peers() []StoredPeer      { return nil }
@@ -0,0 +1,161 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestPeerStore(t *testing.T) {
+	db := memdb.New()
+	s, err := NewPeerStore(db)
+	assert.NoError(t, err)
+	assert.Empty(t, s.Peers())
+
+	now := time.Unix(1607626800, 0)
+	peer0 := StoredPeer{
+		NodeID:   ids.ShortID{1},
+		IP:       utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+		Version:  "app/0.1.0",
+		LastSeen: now.Add(-time.Hour),
+	}
+	peer1 := StoredPeer{
+		NodeID:   ids.ShortID{2},
+		IP:       utils.IPDesc{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+		Version:  "app/0.1.0",
+		LastSeen: now,
+	}
+	assert.NoError(t, s.Put(peer0))
+	assert.NoError(t, s.Put(peer1))
+	assert.NoError(t, db.Put([]byte("corrupted"), []byte{1, 2, 3}))
+
+	// The peers are read back from the database, and the corrupted entry is
+	// removed
+	s, err = NewPeerStore(db)
+	assert.NoError(t, err)
+	peers := s.Peers()
+	assert.Len(t, peers, 2)
+	assert.Equal(t, peer1.NodeID, peers[0].NodeID)
+	assert.True(t, peer1.IP.Equal(peers[0].IP))
+	assert.Equal(t, peer1.Version, peers[0].Version)
+	assert.True(t, peer1.LastSeen.Equal(peers[0].LastSeen))
+	assert.Equal(t, peer0.NodeID, peers[1].NodeID)
+	assert.True(t, peer0.IP.Equal(peers[1].IP))
+	has, err := db.Has([]byte("corrupted"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	// Storing a peer again updates it
+	peer0.LastSeen = now.Add(time.Minute)
+	assert.NoError(t, s.Put(peer0))
+	peers = s.Peers()
+	assert.Len(t, peers, 2)
+	assert.Equal(t, peer0.NodeID, peers[0].NodeID)
+
+	assert.NoError(t, s.Remove(peer0.NodeID))
+	s, err = NewPeerStore(db)
+	assert.NoError(t, err)
+	peers = s.Peers()
+	assert.Len(t, peers, 1)
+	assert.Equal(t, peer1.NodeID, peers[0].NodeID)
+}
+
+func TestPeerStoreEvictsOldest(t *testing.T) {
+	s, err := NewPeerStore(memdb.New())
+	assert.NoError(t, err)
+
+	now := time.Unix(1607626800, 0)
+	for i := 0; i <= maxStoredPeers; i++ {
+		assert.NoError(t, s.Put(StoredPeer{
+			NodeID:   ids.ShortID{byte(i), byte(i >> 8)},
+			IP:       utils.IPDesc{IP: net.IPv4(1, 2, byte(i>>8), byte(i)), Port: 9651},
+			Version:  "app/0.1.0",
+			LastSeen: now.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	peers := s.Peers()
+	assert.Len(t, peers, maxStoredPeers)
+	assert.Equal(t, ids.ShortID{1}, peers[len(peers)-1].NodeID)
+}
+
+func TestSubnetOf(t *testing.T) {
+	assert.Equal(t, subnetOf(net.ParseIP("1.2.3.4")), subnetOf(net.ParseIP("1.2.3.200")))
+	assert.NotEqual(t, subnetOf(net.ParseIP("1.2.3.4")), subnetOf(net.ParseIP("1.2.4.4")))
+	assert.Equal(t, subnetOf(net.ParseIP("2001:db8:1::1")), subnetOf(net.ParseIP("2001:db8:1:ffff::1")))
+	assert.NotEqual(t, subnetOf(net.ParseIP("2001:db8:1::1")), subnetOf(net.ParseIP("2001:db8:2::1")))
+}
+
+func TestValidateStoredPeer(t *testing.T) {
+	appVersion := version.NewDefaultApplication("app", 0, 1, 0)
+	now := time.Unix(1607626800, 0)
+	myIP := utils.NewDynamicIPDesc(net.IPv4(5, 6, 7, 8), 9651)
+	n := &network{
+		id:                 ids.ShortID{9},
+		ip:                 myIP,
+		parser:             version.NewDefaultApplicationParser(),
+		maxClockDifference: time.Minute,
+		myIPs:              map[string]struct{}{"9.9.9.9:9651": {}},
+		scorer:             NewNoScorer(),
+		versionCompatibility: version.NewCompatibility(
+			appVersion,
+			appVersion,
+			now,
+			appVersion,
+			appVersion,
+			now,
+			appVersion,
+		),
+	}
+
+	valid := StoredPeer{
+		NodeID:   ids.ShortID{1},
+		IP:       utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+		Version:  "app/0.1.0",
+		LastSeen: now.Add(-time.Hour),
+	}
+	assert.NoError(t, n.validateStoredPeer(valid, now))
+
+	storedPeer := valid
+	storedPeer.NodeID = n.id
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerMyself))
+
+	storedPeer = valid
+	storedPeer.IP.Port = 0
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerZeroIP))
+
+	storedPeer = valid
+	storedPeer.IP.IP = net.IPv4(192, 168, 0, 1)
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerPrivateIP))
+	n.allowPrivateIPs = true
+	assert.NoError(t, n.validateStoredPeer(storedPeer, now))
+
+	storedPeer = valid
+	storedPeer.IP = myIP.IP()
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerMyIP))
+	storedPeer.IP = utils.IPDesc{IP: net.IPv4(9, 9, 9, 9), Port: 9651}
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerMyIP))
+
+	storedPeer = valid
+	storedPeer.LastSeen = now.Add(-maxStoredPeerAge - time.Second)
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerTooOld))
+	storedPeer.LastSeen = now.Add(time.Hour)
+	assert.True(t, errors.Is(n.validateStoredPeer(storedPeer, now), errStoredPeerFuture))
+
+	storedPeer = valid
+	storedPeer.Version = "app/0.0.9"
+	assert.Error(t, n.validateStoredPeer(storedPeer, now))
+	storedPeer.Version = "not a version"
+	assert.Error(t, n.validateStoredPeer(storedPeer, now))
+}
@@ -111,6 +111,16 @@ func TestPeer_Close(t *testing.T) {
 		defaultGossipOnAcceptSize,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultGeoIPResolver,
+		defaultNetworkClock,
+		defaultEpochFirstTransition,
+		defaultEpochDuration,
+		defaultCompressionConfig,
+		defaultScorer,
+		defaultInboundConnThrottler,
+		defaultPeerStore,
+		defaultMaxReconnectPeers,
+		defaultPeerMetricsEnabled,
 	)
 	assert.NotNil(t, netwrk)
 
@@ -128,7 +138,6 @@ func TestPeer_Close(t *testing.T) {
 
 	// fake a peer, and write a message
 	peer := newPeer(basenetwork, conn, ip1.IP())
-	peer.sendQueue = [][]byte{}
 	testMsg := newTestMsg(GetVersion, newmsgbytes)
 	peer.Send(testMsg, true)
 
@@ -0,0 +1,309 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	// Weight of a peer's request timeout rate in its score. A peer that times
+	// out on every request is scored this much, so a peer that is only slow
+	// is benched, rather than banned, unless the ban threshold is this low.
+	timeoutRateWeight = 50
+
+	// Max number of peers whose scores are tracked before the scores that
+	// decayed away are pruned
+	maxScoredPeers = 4096
+)
+
+// Offense is a kind of misbehavior that lowers the score of a peer
+type Offense byte
+
+const (
+	// InvalidMessage is a message that can't be parsed
+	InvalidMessage Offense = iota
+	// FailedHandshake is a handshake message that is invalid, or that shows
+	// that the peer can't be connected to
+	FailedHandshake
+	// ProtocolViolation is a well formed message that honest peers don't send
+	ProtocolViolation
+)
+
+// Penalty added to the score of a peer for each kind of offense
+var offensePenalties = map[Offense]float64{
+	InvalidMessage:    10,
+	FailedHandshake:   20,
+	ProtocolViolation: 10,
+}
+
+func (o Offense) String() string {
+	switch o {
+	case InvalidMessage:
+		return "invalid_message"
+	case FailedHandshake:
+		return "failed_handshake"
+	case ProtocolViolation:
+		return "protocol_violation"
+	default:
+		return "unknown"
+	}
+}
+
+// ScoringConfig describes how peers are scored and when they're banned
+type ScoringConfig struct {
+	// If false, peers aren't scored and are never banned
+	Enabled bool `json:"enabled"`
+	// A peer is banned when its score reaches this threshold
+	BanThreshold float64 `json:"banThreshold"`
+	// How long a peer stays banned
+	BanDuration time.Duration `json:"banDuration"`
+	// Time it takes for the penalty of an offense to decay to half its value
+	HalfLife time.Duration `json:"halfLife"`
+}
+
+// Scorer tracks how much each peer misbehaves, and bans the peers that
+// misbehave too much. A higher score is worse. Scorer is safe for concurrent
+// access.
+type Scorer interface {
+	// RegisterOffense registers that [nodeID] committed [offense]. Returns
+	// true if [nodeID] was banned because of it.
+	RegisterOffense(nodeID ids.ShortID, offense Offense) bool
+	// RegisterResponse registers that [nodeID] responded to a request in time
+	RegisterResponse(nodeID ids.ShortID)
+	// RegisterTimeout registers that a request to [nodeID] timed out. Returns
+	// true if [nodeID] was banned because of it.
+	RegisterTimeout(nodeID ids.ShortID) bool
+	// IsBanned returns true if this node shouldn't be connected to [nodeID]
+	IsBanned(nodeID ids.ShortID) bool
+	// Score returns the current score of [nodeID]
+	Score(nodeID ids.ShortID) float64
+}
+
+type peerScore struct {
+	// Sum of the penalties of the offenses committed by the peer, decayed to
+	// [lastUpdated]
+	penalty     float64
+	lastUpdated time.Time
+	// Portion of the recent requests to the peer that timed out. Nil until a
+	// request to the peer finishes.
+	timeoutRate safemath.Averager
+	bannedUntil time.Time
+}
+
+type scorer struct {
+	config ScoringConfig
+	clock  timer.Clock
+
+	lock   sync.RWMutex
+	scores map[ids.ShortID]*peerScore
+
+	offenses *prometheus.CounterVec
+	bans     prometheus.Counter
+}
+
+// NewScorer returns a Scorer configured with [config]. If scoring isn't
+// enabled, the returned Scorer never bans any peer.
+func NewScorer(config ScoringConfig, registerer prometheus.Registerer) (Scorer, error) {
+	if !config.Enabled {
+		return NewNoScorer(), nil
+	}
+
+	s := &scorer{
+		config: config,
+		scores: make(map[ids.ShortID]*peerScore),
+		offenses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.PlatformName,
+			Name:      "peer_offenses",
+			Help:      "Number of offenses committed by peers",
+		}, []string{"offense"}),
+		bans: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: constants.PlatformName,
+			Name:      "peer_bans",
+			Help:      "Number of times a peer was banned",
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(s.offenses),
+		registerer.Register(s.bans),
+	)
+	return s, errs.Err
+}
+
+// RegisterOffense implements the Scorer interface
+func (s *scorer) RegisterOffense(nodeID ids.ShortID, offense Offense) bool {
+	s.offenses.WithLabelValues(offense.String()).Inc()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	score := s.getScore(nodeID, now)
+	score.decay(s.config.HalfLife, now)
+	score.penalty += offensePenalties[offense]
+	return s.tryBan(score, now)
+}
+
+// RegisterResponse implements the Scorer interface
+func (s *scorer) RegisterResponse(nodeID ids.ShortID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	s.getScore(nodeID, now).observeRequest(0, s.config.HalfLife, now)
+}
+
+// RegisterTimeout implements the Scorer interface
+func (s *scorer) RegisterTimeout(nodeID ids.ShortID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	score := s.getScore(nodeID, now)
+	score.observeRequest(1, s.config.HalfLife, now)
+	return s.tryBan(score, now)
+}
+
+// IsBanned implements the Scorer interface
+func (s *scorer) IsBanned(nodeID ids.ShortID) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	score, ok := s.scores[nodeID]
+	return ok && s.clock.Time().Before(score.bannedUntil)
+}
+
+// Score implements the Scorer interface
+func (s *scorer) Score(nodeID ids.ShortID) float64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	score, ok := s.scores[nodeID]
+	if !ok {
+		return 0
+	}
+	return score.read(s.config.HalfLife, s.clock.Time())
+}
+
+// getScore returns the score of [nodeID], which is tracked from now on if it
+// wasn't already. Assumes [s.lock] is held.
+func (s *scorer) getScore(nodeID ids.ShortID, now time.Time) *peerScore {
+	score, ok := s.scores[nodeID]
+	if ok {
+		return score
+	}
+	if len(s.scores) >= maxScoredPeers {
+		s.prune(now)
+	}
+	score = &peerScore{lastUpdated: now}
+	s.scores[nodeID] = score
+	return score
+}
+
+// prune stops tracking the peers that aren't banned and whose scores decayed
+// away. Assumes [s.lock] is held.
+func (s *scorer) prune(now time.Time) {
+	for nodeID, score := range s.scores {
+		if !now.Before(score.bannedUntil) && score.read(s.config.HalfLife, now) < 1 {
+			delete(s.scores, nodeID)
+		}
+	}
+}
+
+// tryBan bans the peer with [score] if its score reached the ban threshold.
+// Returns true if the peer was banned. Assumes [s.lock] is held.
+func (s *scorer) tryBan(score *peerScore, now time.Time) bool {
+	if now.Before(score.bannedUntil) || score.read(s.config.HalfLife, now) < s.config.BanThreshold {
+		return false
+	}
+
+	// The peer starts over once its ban expires
+	score.penalty = 0
+	score.timeoutRate = nil
+	score.bannedUntil = now.Add(s.config.BanDuration)
+	s.bans.Inc()
+	return true
+}
+
+// decay the penalty to [now]
+func (p *peerScore) decay(halfLife time.Duration, now time.Time) {
+	if elapsed := now.Sub(p.lastUpdated); elapsed > 0 {
+		p.penalty *= math.Exp2(-float64(elapsed) / float64(halfLife))
+		p.lastUpdated = now
+	}
+}
+
+func (p *peerScore) observeRequest(timedOut float64, halfLife time.Duration, now time.Time) {
+	if p.timeoutRate == nil {
+		p.timeoutRate = safemath.NewAverager(timedOut, halfLife, now)
+		return
+	}
+	p.timeoutRate.Observe(timedOut, now)
+}
+
+// read returns the score at [now], without modifying it
+func (p *peerScore) read(halfLife time.Duration, now time.Time) float64 {
+	penalty := p.penalty
+	if elapsed := now.Sub(p.lastUpdated); elapsed > 0 {
+		penalty *= math.Exp2(-float64(elapsed) / float64(halfLife))
+	}
+	if p.timeoutRate != nil {
+		penalty += timeoutRateWeight * p.timeoutRate.Read()
+	}
+	return penalty
+}
+
+type noScorer struct{}
+
+// NewNoScorer returns a Scorer that never bans any peer
+func NewNoScorer() Scorer { return &noScorer{} }
+
+func (noScorer) RegisterOffense(ids.ShortID, Offense) bool { return false }
+func (noScorer) RegisterResponse(ids.ShortID)              {}
+func (noScorer) RegisterTimeout(ids.ShortID) bool          { return false }
+func (noScorer) IsBanned(ids.ShortID) bool                 { return false }
+func (noScorer) Score(ids.ShortID) float64                 { return 0 }
+
+// scoringBenchlist scores peers on the requests to them that time out
+type scoringBenchlist struct {
+	benchlist.Manager
+	scorer Scorer
+}
+
+// NewScoringBenchlist returns a benchlist manager that forwards to
+// [benchlistManager], and that registers the responses and timeouts of
+// requests with [scorer]
+func NewScoringBenchlist(benchlistManager benchlist.Manager, scorer Scorer) benchlist.Manager {
+	return &scoringBenchlist{
+		Manager: benchlistManager,
+		scorer:  scorer,
+	}
+}
+
+// RegisterResponse implements the benchlist.Manager interface
+func (b *scoringBenchlist) RegisterResponse(chainID ids.ID, validatorID ids.ShortID) {
+	b.scorer.RegisterResponse(validatorID)
+	b.Manager.RegisterResponse(chainID, validatorID)
+}
+
+// RegisterFailure implements the benchlist.Manager interface. A peer that is
+// banned because of a timeout is disconnected from when it next sends a
+// message.
+func (b *scoringBenchlist) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
+	b.scorer.RegisterTimeout(validatorID)
+	b.Manager.RegisterFailure(chainID, validatorID)
+}
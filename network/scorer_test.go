@@ -0,0 +1,134 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+)
+
+func newTestScorer(t *testing.T, now time.Time) *scorer {
+	s, err := NewScorer(ScoringConfig{
+		Enabled:      true,
+		BanThreshold: 100,
+		BanDuration:  time.Hour,
+		HalfLife:     time.Minute,
+	}, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	sc := s.(*scorer)
+	sc.clock.Set(now)
+	return sc
+}
+
+func TestScorerBan(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	s := newTestScorer(t, now)
+	nodeID := ids.ShortID{1}
+
+	for i := 0; i < 4; i++ {
+		assert.False(t, s.RegisterOffense(nodeID, FailedHandshake))
+	}
+	assert.Equal(t, float64(80), s.Score(nodeID))
+	assert.False(t, s.IsBanned(nodeID))
+
+	assert.True(t, s.RegisterOffense(nodeID, FailedHandshake))
+	assert.True(t, s.IsBanned(nodeID))
+	assert.False(t, s.IsBanned(ids.ShortID{2}))
+
+	// Offenses of a banned peer don't extend its ban
+	assert.False(t, s.RegisterOffense(nodeID, InvalidMessage))
+
+	s.clock.Set(now.Add(time.Hour))
+	assert.False(t, s.IsBanned(nodeID))
+}
+
+func TestScorerDecay(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	s := newTestScorer(t, now)
+	nodeID := ids.ShortID{1}
+
+	for i := 0; i < 4; i++ {
+		assert.False(t, s.RegisterOffense(nodeID, FailedHandshake))
+	}
+
+	// After a half life, the peer's offenses count half as much
+	s.clock.Set(now.Add(time.Minute))
+	assert.InDelta(t, 40, s.Score(nodeID), 0.001)
+	assert.False(t, s.RegisterOffense(nodeID, FailedHandshake))
+	assert.InDelta(t, 60, s.Score(nodeID), 0.001)
+}
+
+func TestScorerTimeouts(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	s := newTestScorer(t, now)
+	nodeID := ids.ShortID{1}
+
+	// Timeouts alone don't get a peer banned
+	for i := 0; i < 10; i++ {
+		assert.False(t, s.RegisterTimeout(nodeID))
+	}
+	assert.InDelta(t, timeoutRateWeight, s.Score(nodeID), 0.001)
+
+	s.RegisterResponse(nodeID)
+	assert.Less(t, s.Score(nodeID), float64(timeoutRateWeight))
+
+	// Timeouts of a peer that misbehaves get it banned sooner
+	nodeID = ids.ShortID{2}
+	for i := 0; i < 2; i++ {
+		assert.False(t, s.RegisterOffense(nodeID, FailedHandshake))
+	}
+	assert.False(t, s.RegisterOffense(nodeID, InvalidMessage))
+	assert.True(t, s.RegisterTimeout(nodeID))
+	assert.True(t, s.IsBanned(nodeID))
+}
+
+func TestScorerPrune(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	s := newTestScorer(t, now)
+
+	bannedID := ids.ShortID{1}
+	for i := 0; i < 5; i++ {
+		s.RegisterOffense(bannedID, FailedHandshake)
+	}
+	for i := 1; i < maxScoredPeers; i++ {
+		s.RegisterOffense(ids.ShortID{0, byte(i), byte(i >> 8)}, InvalidMessage)
+	}
+	assert.Len(t, s.scores, maxScoredPeers)
+
+	// The scores of the peers that aren't banned decayed away
+	s.clock.Set(now.Add(10 * time.Minute))
+	s.RegisterOffense(ids.ShortID{2}, InvalidMessage)
+	assert.Len(t, s.scores, 2)
+	assert.True(t, s.IsBanned(bannedID))
+}
+
+func TestNoScorer(t *testing.T) {
+	s, err := NewScorer(ScoringConfig{}, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	nodeID := ids.ShortID{1}
+
+	for i := 0; i < 100; i++ {
+		assert.False(t, s.RegisterOffense(nodeID, FailedHandshake))
+	}
+	assert.False(t, s.IsBanned(nodeID))
+	assert.Equal(t, float64(0), s.Score(nodeID))
+}
+
+func TestScoringBenchlist(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	s := newTestScorer(t, now)
+	b := NewScoringBenchlist(benchlist.NewNoBenchlist(), s)
+	nodeID := ids.ShortID{1}
+
+	b.RegisterFailure(ids.Empty, nodeID)
+	assert.InDelta(t, timeoutRateWeight, s.Score(nodeID), 0.001)
+	b.RegisterResponse(ids.Empty, nodeID)
+	assert.Less(t, s.Score(nodeID), float64(timeoutRateWeight))
+}
@@ -0,0 +1,188 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// sendPriority is the class of a message sent to a peer. Queued messages of a
+// class are sent before the queued messages of the classes after it, so that
+// bulk transfers can't delay the messages that consensus is waiting on.
+type sendPriority int
+
+const (
+	// Handshake, ping and consensus messages
+	consensusPriority sendPriority = iota
+	// Peer lists and gossiped containers
+	gossipPriority
+	// Bootstrapping and state sync messages
+	bootstrapPriority
+
+	numSendPriorities
+)
+
+func (p sendPriority) String() string {
+	switch p {
+	case consensusPriority:
+		return "consensus"
+	case gossipPriority:
+		return "gossip"
+	case bootstrapPriority:
+		return "bootstrap"
+	default:
+		return "unknown"
+	}
+}
+
+// dropOldest is true for the classes that drop their oldest queued message
+// when a message is sent while the class is full. The other classes drop the
+// message that is sent. A consensus peer waits on the newest responses, while
+// the requests of a bulk transfer are retried once they time out.
+var dropOldest = [numSendPriorities]bool{
+	consensusPriority: true,
+}
+
+// sendPriorityOf returns the class of [msg]. [msg] must not be compressed.
+func sendPriorityOf(msg Msg) sendPriority {
+	switch msg.Op() {
+	case GetPeerList, PeerList:
+		return gossipPriority
+	case Put:
+		if requestID, _ := msg.Get(RequestID).(uint32); requestID == constants.GossipMsgRequestID {
+			return gossipPriority
+		}
+		return consensusPriority
+	case GetAcceptedFrontier, AcceptedFrontier, GetAccepted, Accepted,
+		GetAncestors, MultiPut, GetStateSummary, StateSummary:
+		return bootstrapPriority
+	default:
+		return consensusPriority
+	}
+}
+
+// sendQueue is the queue of messages waiting to be sent to a peer. Messages of
+// the same class are sent in the order they were queued. sendQueue isn't safe
+// for concurrent access. The zero value holds no messages and can't be pushed
+// to.
+type sendQueue struct {
+	queues [numSendPriorities][][]byte
+	// Max number of queued messages of each class. 0 means no limit.
+	maxSize int
+	metrics *sendQueueMetrics
+}
+
+func newSendQueue(maxSize int, metrics *sendQueueMetrics) sendQueue {
+	return sendQueue{
+		maxSize: maxSize,
+		metrics: metrics,
+	}
+}
+
+// push queues [msg] with [priority]. If the class is full, a message of the
+// class is dropped and returned. Returns false if [msg] is the message that was
+// dropped.
+func (q *sendQueue) push(priority sendPriority, msg []byte) ([]byte, bool) {
+	queue := q.queues[priority]
+	if q.maxSize > 0 && len(queue) >= q.maxSize {
+		q.metrics.dropped.WithLabelValues(priority.String()).Inc()
+		if !dropOldest[priority] {
+			return msg, false
+		}
+		dropped := queue[0]
+		queue[0] = nil
+		q.queues[priority] = queue[1:]
+		q.metrics.removed(priority, dropped)
+		q.queues[priority] = append(q.queues[priority], msg)
+		q.metrics.added(priority, msg)
+		return dropped, true
+	}
+
+	q.queues[priority] = append(queue, msg)
+	q.metrics.added(priority, msg)
+	return nil, true
+}
+
+// pop removes and returns the oldest message of the first class that has
+// queued messages. Returns false if no message is queued.
+func (q *sendQueue) pop() ([]byte, bool) {
+	for priority, queue := range q.queues {
+		if len(queue) == 0 {
+			continue
+		}
+		msg := queue[0]
+		queue[0] = nil
+		q.queues[priority] = queue[1:]
+		q.metrics.removed(sendPriority(priority), msg)
+		return msg, true
+	}
+	return nil, false
+}
+
+// len returns the number of queued messages
+func (q *sendQueue) len() int {
+	size := 0
+	for _, queue := range q.queues {
+		size += len(queue)
+	}
+	return size
+}
+
+// clear removes and returns all the queued messages
+func (q *sendQueue) clear() [][]byte {
+	msgs := make([][]byte, 0, q.len())
+	for priority, queue := range q.queues {
+		for _, msg := range queue {
+			q.metrics.removed(sendPriority(priority), msg)
+		}
+		msgs = append(msgs, queue...)
+		q.queues[priority] = nil
+	}
+	return msgs
+}
+
+// sendQueueMetrics are the backlogs of the send queues of all peers, by class
+type sendQueueMetrics struct {
+	queuedMsgs, queuedBytes *prometheus.GaugeVec
+	dropped                 *prometheus.CounterVec
+}
+
+func (m *sendQueueMetrics) initialize(registerer prometheus.Registerer) error {
+	m.queuedMsgs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "send_queue_messages",
+		Help:      "Number of messages waiting to be sent to peers",
+	}, []string{"priority"})
+	m.queuedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "send_queue_bytes",
+		Help:      "Number of bytes of the messages waiting to be sent to peers",
+	}, []string{"priority"})
+	m.dropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "send_queue_dropped",
+		Help:      "Number of messages dropped because the send queue of their priority was full",
+	}, []string{"priority"})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.queuedMsgs),
+		registerer.Register(m.queuedBytes),
+		registerer.Register(m.dropped),
+	)
+	return errs.Err
+}
+
+func (m *sendQueueMetrics) added(priority sendPriority, msg []byte) {
+	m.queuedMsgs.WithLabelValues(priority.String()).Inc()
+	m.queuedBytes.WithLabelValues(priority.String()).Add(float64(len(msg)))
+}
+
+func (m *sendQueueMetrics) removed(priority sendPriority, msg []byte) {
+	m.queuedMsgs.WithLabelValues(priority.String()).Dec()
+	m.queuedBytes.WithLabelValues(priority.String()).Sub(float64(len(msg)))
+}
@@ -0,0 +1,105 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func newTestSendQueue(t *testing.T, maxSize int) *sendQueue {
+	metrics := &sendQueueMetrics{}
+	assert.NoError(t, metrics.initialize(prometheus.NewRegistry()))
+	q := newSendQueue(maxSize, metrics)
+	return &q
+}
+
+func TestSendPriorityOf(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+
+	msg, err := TestBuilder.Chits(chainID, 1, []ids.ID{containerID})
+	assert.NoError(t, err)
+	assert.Equal(t, consensusPriority, sendPriorityOf(msg))
+
+	msg, err = TestBuilder.Put(chainID, 1, containerID, []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, consensusPriority, sendPriorityOf(msg))
+
+	msg, err = TestBuilder.Put(chainID, constants.GossipMsgRequestID, containerID, []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, gossipPriority, sendPriorityOf(msg))
+
+	msg, err = TestBuilder.GetPeerList()
+	assert.NoError(t, err)
+	assert.Equal(t, gossipPriority, sendPriorityOf(msg))
+
+	msg, err = TestBuilder.MultiPut(chainID, 1, [][]byte{{1}})
+	assert.NoError(t, err)
+	assert.Equal(t, bootstrapPriority, sendPriorityOf(msg))
+
+	msg, err = TestBuilder.Ping()
+	assert.NoError(t, err)
+	assert.Equal(t, consensusPriority, sendPriorityOf(msg))
+}
+
+func TestSendQueuePriorities(t *testing.T) {
+	q := newTestSendQueue(t, 0)
+
+	_, queued := q.push(bootstrapPriority, []byte{3})
+	assert.True(t, queued)
+	_, queued = q.push(gossipPriority, []byte{2})
+	assert.True(t, queued)
+	_, queued = q.push(consensusPriority, []byte{1, 1})
+	assert.True(t, queued)
+	_, queued = q.push(bootstrapPriority, []byte{4})
+	assert.True(t, queued)
+	assert.Equal(t, 4, q.len())
+	assert.Equal(t, float64(2), testutil.ToFloat64(q.metrics.queuedMsgs.WithLabelValues("bootstrap")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(q.metrics.queuedBytes.WithLabelValues("consensus")))
+
+	for _, expected := range [][]byte{{1, 1}, {2}, {3}, {4}} {
+		msg, ok := q.pop()
+		assert.True(t, ok)
+		assert.Equal(t, expected, msg)
+	}
+	_, ok := q.pop()
+	assert.False(t, ok)
+	assert.Equal(t, float64(0), testutil.ToFloat64(q.metrics.queuedMsgs.WithLabelValues("bootstrap")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(q.metrics.queuedBytes.WithLabelValues("consensus")))
+}
+
+func TestSendQueueDrop(t *testing.T) {
+	q := newTestSendQueue(t, 2)
+
+	for _, priority := range []sendPriority{consensusPriority, bootstrapPriority} {
+		for i := byte(0); i < 2; i++ {
+			dropped, queued := q.push(priority, []byte{i})
+			assert.True(t, queued)
+			assert.Nil(t, dropped)
+		}
+	}
+
+	// A full consensus queue drops its oldest message
+	dropped, queued := q.push(consensusPriority, []byte{2})
+	assert.True(t, queued)
+	assert.Equal(t, []byte{0}, dropped)
+
+	// A full bootstrap queue drops the new message
+	dropped, queued = q.push(bootstrapPriority, []byte{2})
+	assert.False(t, queued)
+	assert.Equal(t, []byte{2}, dropped)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(q.metrics.dropped.WithLabelValues("consensus")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(q.metrics.dropped.WithLabelValues("bootstrap")))
+	assert.Equal(t, [][]byte{{1}, {2}, {0}, {1}}, q.clear())
+	assert.Equal(t, 0, q.len())
+	assert.Equal(t, float64(0), testutil.ToFloat64(q.metrics.queuedMsgs.WithLabelValues("consensus")))
+}
@@ -0,0 +1,254 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Max number of messages written to a connection that wait to be delivered
+// before writes block
+const maxPendingFrames = 1 << 12
+
+var (
+	errClosed      = errors.New("closed")
+	errRefused     = errors.New("connection refused")
+	errUnknownConn = errors.New("connection wasn't opened by the simulator")
+)
+
+// droppable returns true if messages with [op] may be dropped by a link.
+// Handshake messages are never dropped, so that links with a drop rate still
+// connect nodes.
+func droppable(op network.Op) bool {
+	switch op {
+	case network.GetAcceptedFrontier, network.AcceptedFrontier,
+		network.GetAccepted, network.Accepted,
+		network.GetAncestors, network.MultiPut,
+		network.GetStateSummary, network.StateSummary,
+		network.Get, network.Put,
+		network.PushQuery, network.PullQuery, network.Chits,
+		network.Compressed:
+		return true
+	default:
+		return false
+	}
+}
+
+type frame struct {
+	bytes     []byte
+	deliverAt time.Time
+}
+
+// pipe delivers the bytes written to one end of a connection to the other end,
+// in the order they were written, each at the time it's due
+type pipe struct {
+	frames    chan frame
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	lock     sync.Mutex
+	cond     *sync.Cond
+	buffered []byte
+	done     bool
+}
+
+func newPipe() *pipe {
+	p := &pipe{
+		frames: make(chan frame, maxPendingFrames),
+		closed: make(chan struct{}),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	go p.deliver()
+	return p
+}
+
+func (p *pipe) deliver() {
+	for {
+		select {
+		case f := <-p.frames:
+			if delay := time.Until(f.deliverAt); delay > 0 {
+				t := time.NewTimer(delay)
+				select {
+				case <-t.C:
+				case <-p.closed:
+					t.Stop()
+					return
+				}
+			}
+			p.lock.Lock()
+			p.buffered = append(p.buffered, f.bytes...)
+			p.cond.Broadcast()
+			p.lock.Unlock()
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+func (p *pipe) write(f frame) error {
+	select {
+	case p.frames <- f:
+		return nil
+	case <-p.closed:
+		return errClosed
+	}
+}
+
+func (p *pipe) read(b []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for len(p.buffered) == 0 && !p.done {
+		p.cond.Wait()
+	}
+	if p.done {
+		return 0, errClosed
+	}
+	n := copy(b, p.buffered)
+	p.buffered = p.buffered[n:]
+	return n, nil
+}
+
+func (p *pipe) close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+
+		p.lock.Lock()
+		p.done = true
+		p.cond.Broadcast()
+		p.lock.Unlock()
+	})
+}
+
+// conn is one end of a connection between two simulated nodes. The messages
+// written to it go through the link from [local] to [remote].
+type conn struct {
+	sim           *Simulator
+	local, remote *Node
+	in, out       *pipe
+
+	// Bytes written that don't make up a complete message yet. Only accessed
+	// by the peer's writer goroutine.
+	partialWrite []byte
+}
+
+func (c *conn) Read(b []byte) (int, error) { return c.in.read(b) }
+
+// Write splits [b] into the messages the network writes, each prefixed with
+// its length, so that the link can drop or delay whole messages
+func (c *conn) Write(b []byte) (int, error) {
+	c.partialWrite = append(c.partialWrite, b...)
+	for len(c.partialWrite) >= wrappers.IntLen {
+		msgLen := int(binary.BigEndian.Uint32(c.partialWrite))
+		frameLen := wrappers.IntLen + msgLen
+		if len(c.partialWrite) < frameLen {
+			break
+		}
+		frameBytes := make([]byte, frameLen)
+		copy(frameBytes, c.partialWrite)
+		c.partialWrite = c.partialWrite[frameLen:]
+
+		var op network.Op
+		if msgLen > 0 {
+			op = network.Op(frameBytes[wrappers.IntLen])
+		}
+		deliverAt, drop := c.sim.transmit(c.local.ID, c.remote.ID, op)
+		if drop {
+			continue
+		}
+		if err := c.out.write(frame{bytes: frameBytes, deliverAt: deliverAt}); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (c *conn) Close() error {
+	c.in.close()
+	c.out.close()
+	c.sim.removeConn(c)
+	return nil
+}
+
+func (c *conn) LocalAddr() net.Addr              { return c.local.addr() }
+func (c *conn) RemoteAddr() net.Addr             { return c.remote.addr() }
+func (c *conn) SetDeadline(time.Time) error      { return nil }
+func (c *conn) SetReadDeadline(time.Time) error  { return nil }
+func (c *conn) SetWriteDeadline(time.Time) error { return nil }
+
+// listener accepts the connections other nodes open to a node
+type listener struct {
+	node    *Node
+	inbound chan net.Conn
+	once    sync.Once
+	closed  chan struct{}
+}
+
+func newListener(node *Node) *listener {
+	return &listener{
+		node:    node,
+		inbound: make(chan net.Conn),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.inbound:
+		return c, nil
+	case <-l.closed:
+		return nil, errClosed
+	}
+}
+
+func (l *listener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *listener) Addr() net.Addr { return l.node.addr() }
+
+// dialer opens connections from a node to the other nodes of the simulator
+type dialer struct {
+	sim  *Simulator
+	node *Node
+}
+
+func (d *dialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
+	client, server, err := d.sim.connect(d.node, ip)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case server.local.listener.inbound <- server:
+		return client, nil
+	case <-server.local.listener.closed:
+	case <-ctx.Done():
+	}
+	_ = client.Close()
+	return nil, errRefused
+}
+
+// upgrader authenticates the nodes of the simulator without TLS, so that links
+// can see the messages nodes exchange
+type upgrader struct{}
+
+func (upgrader) Upgrade(c net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+	simConn, ok := c.(*conn)
+	if !ok {
+		return ids.ShortID{}, nil, nil, errUnknownConn
+	}
+	return simConn.remote.ID, c, simConn.remote.cert, nil
+}
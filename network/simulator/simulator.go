@@ -0,0 +1,392 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulator runs in-process nodes connected by simulated links whose
+// latency, drop rate and partitions tests control. Each node runs the real
+// network stack, and routes the messages it receives to the router it was
+// added with, so tests can drive consensus engines over faulty links.
+package simulator
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/network/geoip"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// Nodes listen on consecutive ports of the loopback address
+	basePort = 9651
+
+	defaultInitialReconnectDelay = 10 * time.Millisecond
+	defaultMaxReconnectDelay     = 100 * time.Millisecond
+)
+
+var (
+	errUnknownNode = errors.New("unknown node")
+	errStarted     = errors.New("simulator already started")
+
+	appVersion = version.NewDefaultApplication("simulator", 1, 0, 0)
+)
+
+// LinkConfig describes the faults injected on the messages a node sends to
+// another node
+type LinkConfig struct {
+	// Time it takes a message to be delivered
+	Latency time.Duration
+	// Portion, in [0, 1], of the consensus messages that are dropped.
+	// Handshake messages are never dropped.
+	DropRate float64
+}
+
+// Config describes a simulator
+type Config struct {
+	Log       logging.Logger
+	NetworkID uint32
+	// Link between the nodes that SetLink wasn't called for
+	DefaultLink LinkConfig
+	// Seed of the randomness used to drop messages
+	Seed int64
+	// Delay before a node reconnects to a node it couldn't reach. The delay
+	// grows with each failed attempt, up to [MaxReconnectDelay]. If 0,
+	// defaults to 10ms and 100ms, so that nodes reconnect soon after a
+	// partition heals.
+	InitialReconnectDelay, MaxReconnectDelay time.Duration
+}
+
+// Node is a node run by a simulator
+type Node struct {
+	ID  ids.ShortID
+	IP  utils.IPDesc
+	Net network.Network
+
+	cert     *x509.Certificate
+	listener *listener
+}
+
+func (n *Node) addr() net.Addr {
+	return &net.TCPAddr{
+		IP:   n.IP.IP,
+		Port: int(n.IP.Port),
+	}
+}
+
+type link struct {
+	from, to ids.ShortID
+}
+
+// Simulator runs nodes connected by simulated links. Simulator is safe for
+// concurrent access.
+type Simulator struct {
+	config Config
+	vdrs   validators.Set
+
+	lock    sync.Mutex
+	started bool
+	rng     *rand.Rand
+	nodes   []*Node
+	// Node listening on each IP
+	nodesByIP map[string]*Node
+	links     map[link]LinkConfig
+	// Number of messages each link dropped
+	dropped map[link]int
+	// Partition each node is in. Nodes in different partitions can't reach
+	// each other.
+	partitions map[ids.ShortID]int
+	// Open connections
+	conns map[*conn]struct{}
+}
+
+// New returns a simulator without nodes
+func New(config Config) *Simulator {
+	if config.Log == nil {
+		config.Log = logging.NoLog{}
+	}
+	if config.InitialReconnectDelay == 0 {
+		config.InitialReconnectDelay = defaultInitialReconnectDelay
+	}
+	if config.MaxReconnectDelay == 0 {
+		config.MaxReconnectDelay = defaultMaxReconnectDelay
+	}
+	return &Simulator{
+		config: config,
+		vdrs:   validators.NewSet(),
+		// #nosec G404
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		nodesByIP:  make(map[string]*Node),
+		links:      make(map[link]LinkConfig),
+		dropped:    make(map[link]int),
+		partitions: make(map[ids.ShortID]int),
+		conns:      make(map[*conn]struct{}),
+	}
+}
+
+// AddNode adds a validator that routes the messages it receives to [router].
+// Nodes must be added before the simulator is started.
+func (s *Simulator) AddNode(router router.Router) (*Node, error) {
+	tlsCert, err := staking.NewTLSCert()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create staking certificate: %w", err)
+	}
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(tlsCert.Leaf.Raw))
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.started {
+		return nil, errStarted
+	}
+	if err := s.vdrs.AddWeight(nodeID, 1); err != nil {
+		return nil, err
+	}
+
+	node := &Node{
+		ID: nodeID,
+		IP: utils.IPDesc{
+			IP:   net.IPv4(127, 0, 0, 1),
+			Port: uint16(basePort + len(s.nodes)),
+		},
+		cert: tlsCert.Leaf,
+	}
+	node.listener = newListener(node)
+	node.Net = network.NewNetwork(
+		prometheus.NewRegistry(),
+		s.config.Log,
+		nodeID,
+		utils.NewDynamicIPDesc(node.IP.IP, node.IP.Port),
+		s.config.NetworkID,
+		version.NewCompatibility(
+			appVersion,
+			appVersion,
+			time.Unix(0, 0),
+			appVersion,
+			appVersion,
+			time.Unix(0, 0),
+			appVersion,
+		),
+		version.NewDefaultApplicationParser(),
+		node.listener,
+		&dialer{sim: s, node: node},
+		upgrader{},
+		upgrader{},
+		s.vdrs,
+		s.vdrs,
+		router,
+		s.config.InitialReconnectDelay,
+		s.config.MaxReconnectDelay,
+		network.DefaultMaxMessageSize,
+		1<<10,          // sendQueueSize
+		512*units.MiB,  // maxNetworkPendingSendBytes
+		128*units.MiB,  // networkPendingSendBytesToRateLimit
+		time.Minute,    // maxClockDifference
+		50,             // peerListSize
+		time.Minute,    // peerListGossipFreq
+		50,             // peerListGossipSize
+		2,              // peerListStakerGossipFraction
+		10*time.Second, // getVersionTimeout
+		true,           // allowPrivateIPs
+		35,             // gossipAcceptedFrontierSize
+		20,             // gossipOnAcceptSize
+		30*time.Second, // pingPongTimeout
+		20*time.Second, // pingFrequency
+		16*units.KiB,   // readBufferSize
+		15*time.Second, // readHandshakeTimeout
+		0,              // connMeterResetDuration
+		0,              // connMeterCacheSize
+		0,              // connMeterMaxConns
+		network.HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		10*time.Minute, // peerAliasTimeout
+		tlsCert.PrivateKey.(crypto.Signer),
+		false, // isFetchOnly
+		throttling.NewNoInboundThrottler(),
+		throttling.NewNoOutboundThrottler(),
+		&geoip.NoResolver{},
+		timer.NewNetworkClock(s.vdrs, 1),
+		time.Unix(0, 0), // epochFirstTransition
+		time.Hour,       // epochDuration
+		network.CompressionConfig{},
+		network.NewNoScorer(),
+		throttling.NewNoInboundConnThrottler(),
+		network.NewNoPeerStore(),
+		0,     // maxReconnectPeers
+		false, // peerMetricsEnabled
+	)
+
+	s.nodes = append(s.nodes, node)
+	s.nodesByIP[node.IP.String()] = node
+	return node, nil
+}
+
+// Start runs the nodes, and connects each node to every other node. Only the
+// node added first dials the other, as if both nodes dial each other at once,
+// both may drop the connection they dialed as a duplicate of the one they
+// accepted.
+func (s *Simulator) Start() {
+	s.lock.Lock()
+	s.started = true
+	nodes := s.nodes
+	s.lock.Unlock()
+
+	for _, node := range nodes {
+		go func(node *Node) {
+			if err := node.Net.Dispatch(); err != nil {
+				s.config.Log.Debug("node %s stopped dispatching: %s", node.ID, err)
+			}
+		}(node)
+	}
+	for i, node := range nodes {
+		for _, peer := range nodes[i+1:] {
+			node.Net.Track(peer.IP, peer.ID)
+		}
+	}
+}
+
+// SetLink sets the faults injected on the messages [from] sends to [to]
+func (s *Simulator) SetLink(from, to ids.ShortID, config LinkConfig) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.links[link{from: from, to: to}] = config
+}
+
+// Dropped returns the number of messages the link from [from] to [to] dropped
+func (s *Simulator) Dropped(from, to ids.ShortID) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.dropped[link{from: from, to: to}]
+}
+
+// Partition splits the nodes into [groups], and the group of the nodes that
+// aren't in [groups]. Nodes in different groups can't reach each other, and
+// their connections are closed.
+func (s *Simulator) Partition(groups ...[]ids.ShortID) {
+	s.lock.Lock()
+	s.partitions = make(map[ids.ShortID]int)
+	for i, group := range groups {
+		for _, nodeID := range group {
+			s.partitions[nodeID] = i + 1
+		}
+	}
+	var cut []*conn
+	for c := range s.conns {
+		if !s.reachable(c.local.ID, c.remote.ID) {
+			cut = append(cut, c)
+		}
+	}
+	s.lock.Unlock()
+
+	for _, c := range cut {
+		_ = c.Close()
+	}
+}
+
+// Heal removes the partitions. Nodes reconnect to the nodes they were
+// partitioned from.
+func (s *Simulator) Heal() {
+	s.Partition()
+}
+
+// Close stops the nodes
+func (s *Simulator) Close() error {
+	s.lock.Lock()
+	nodes := s.nodes
+	s.lock.Unlock()
+
+	for _, node := range nodes {
+		if err := node.Net.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connect opens a connection from [node] to the node listening on [ip]. Returns
+// the end of the connection of [node], and of the node it connected to.
+func (s *Simulator) connect(node *Node, ip utils.IPDesc) (*conn, *conn, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	remote, ok := s.nodesByIP[ip.String()]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w at %s", errUnknownNode, ip)
+	}
+	if !s.reachable(node.ID, remote.ID) {
+		return nil, nil, errRefused
+	}
+
+	toRemote := newPipe()
+	fromRemote := newPipe()
+	client := &conn{
+		sim:    s,
+		local:  node,
+		remote: remote,
+		in:     fromRemote,
+		out:    toRemote,
+	}
+	server := &conn{
+		sim:    s,
+		local:  remote,
+		remote: node,
+		in:     toRemote,
+		out:    fromRemote,
+	}
+	s.conns[client] = struct{}{}
+	s.conns[server] = struct{}{}
+	return client, server, nil
+}
+
+func (s *Simulator) removeConn(c *conn) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.conns, c)
+}
+
+// transmit returns when a message with [op] sent by [from] to [to] is
+// delivered, or true if it's dropped
+func (s *Simulator) transmit(from, to ids.ShortID, op network.Op) (time.Time, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	l := link{from: from, to: to}
+	config, ok := s.links[l]
+	if !ok {
+		config = s.config.DefaultLink
+	}
+	if droppable(op) && s.rng.Float64() < config.DropRate {
+		s.dropped[l]++
+		return time.Time{}, true
+	}
+	return time.Now().Add(config.Latency), false
+}
+
+// Assumes [s.lock] is held
+func (s *Simulator) reachable(nodeID0, nodeID1 ids.ShortID) bool {
+	return s.partitions[nodeID0] == s.partitions[nodeID1]
+}
@@ -0,0 +1,195 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+)
+
+const testTimeout = 10 * time.Second
+
+type testRouter struct {
+	router.Router
+
+	lock      sync.Mutex
+	connected ids.ShortSet
+	gets      map[uint32]time.Time
+}
+
+func newTestRouter() *testRouter {
+	return &testRouter{gets: make(map[uint32]time.Time)}
+}
+
+func (r *testRouter) Connected(nodeID ids.ShortID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.connected.Add(nodeID)
+}
+
+func (r *testRouter) Disconnected(nodeID ids.ShortID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.connected.Remove(nodeID)
+}
+
+func (r *testRouter) Get(_ ids.ShortID, _ ids.ID, requestID uint32, _ time.Time, _ ids.ID, onFinishedHandling func()) {
+	r.lock.Lock()
+	r.gets[requestID] = time.Now()
+	r.lock.Unlock()
+
+	onFinishedHandling()
+}
+
+func (r *testRouter) isConnected(nodeID ids.ShortID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.connected.Contains(nodeID)
+}
+
+func (r *testRouter) got(requestID uint32) (time.Time, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	at, ok := r.gets[requestID]
+	return at, ok
+}
+
+// eventually returns true once [f] returns true, or false if it doesn't
+// within [testTimeout]
+func eventually(f func() bool) bool {
+	for deadline := time.Now().Add(testTimeout); time.Now().Before(deadline); {
+		if f() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return f()
+}
+
+func newTestSimulator(t *testing.T, config Config, numNodes int) (*Simulator, []*Node, []*testRouter) {
+	sim := New(config)
+	nodes := make([]*Node, numNodes)
+	routers := make([]*testRouter, numNodes)
+	for i := range nodes {
+		routers[i] = newTestRouter()
+		node, err := sim.AddNode(routers[i])
+		assert.NoError(t, err)
+		nodes[i] = node
+	}
+	sim.Start()
+	t.Cleanup(func() { assert.NoError(t, sim.Close()) })
+	return sim, nodes, routers
+}
+
+func allConnected(nodes []*Node, routers []*testRouter) func() bool {
+	return func() bool {
+		for i, r := range routers {
+			for j, node := range nodes {
+				if i != j && !r.isConnected(node.ID) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
+func TestSimulatorConnects(t *testing.T) {
+	sim, nodes, routers := newTestSimulator(t, Config{}, 4)
+
+	assert.True(t, eventually(allConnected(nodes, routers)))
+
+	_, err := sim.AddNode(newTestRouter())
+	assert.ErrorIs(t, err, errStarted)
+}
+
+func TestSimulatorPartition(t *testing.T) {
+	sim, nodes, routers := newTestSimulator(t, Config{}, 4)
+	assert.True(t, eventually(allConnected(nodes, routers)))
+
+	sim.Partition(
+		[]ids.ShortID{nodes[0].ID, nodes[1].ID},
+		[]ids.ShortID{nodes[2].ID, nodes[3].ID},
+	)
+	assert.True(t, eventually(func() bool {
+		return !routers[0].isConnected(nodes[2].ID) &&
+			!routers[2].isConnected(nodes[0].ID) &&
+			!routers[1].isConnected(nodes[3].ID) &&
+			!routers[3].isConnected(nodes[1].ID)
+	}))
+	assert.True(t, routers[0].isConnected(nodes[1].ID))
+	assert.True(t, routers[2].isConnected(nodes[3].ID))
+
+	chainID := ids.ID{1}
+	assert.False(t, nodes[0].Net.Get(nodes[2].ID, chainID, 1, time.Second, ids.ID{2}))
+	assert.True(t, nodes[0].Net.Get(nodes[1].ID, chainID, 2, time.Second, ids.ID{2}))
+	assert.True(t, eventually(func() bool {
+		_, ok := routers[1].got(2)
+		return ok
+	}))
+
+	sim.Heal()
+	assert.True(t, eventually(allConnected(nodes, routers)))
+	assert.True(t, nodes[0].Net.Get(nodes[2].ID, chainID, 3, time.Second, ids.ID{2}))
+	assert.True(t, eventually(func() bool {
+		_, ok := routers[2].got(3)
+		return ok
+	}))
+}
+
+func TestSimulatorDropRate(t *testing.T) {
+	sim, nodes, routers := newTestSimulator(t, Config{
+		DefaultLink: LinkConfig{DropRate: 1},
+	}, 2)
+
+	// Handshake messages aren't dropped
+	assert.True(t, eventually(allConnected(nodes, routers)))
+
+	chainID := ids.ID{1}
+	assert.True(t, nodes[0].Net.Get(nodes[1].ID, chainID, 1, time.Second, ids.ID{2}))
+	// The Get is written asynchronously, so the link must not be changed
+	// before it's dropped
+	assert.True(t, eventually(func() bool {
+		return sim.Dropped(nodes[0].ID, nodes[1].ID) == 1
+	}))
+
+	sim.SetLink(nodes[0].ID, nodes[1].ID, LinkConfig{})
+	assert.True(t, nodes[0].Net.Get(nodes[1].ID, chainID, 2, time.Second, ids.ID{2}))
+	assert.True(t, eventually(func() bool {
+		_, ok := routers[1].got(2)
+		return ok
+	}))
+
+	// Messages are delivered in order, so the first Get was dropped
+	_, ok := routers[1].got(1)
+	assert.False(t, ok)
+}
+
+func TestSimulatorLatency(t *testing.T) {
+	latency := 200 * time.Millisecond
+	_, nodes, routers := newTestSimulator(t, Config{
+		DefaultLink: LinkConfig{Latency: latency},
+	}, 2)
+	assert.True(t, eventually(allConnected(nodes, routers)))
+
+	sent := time.Now()
+	assert.True(t, nodes[0].Net.Get(nodes[1].ID, ids.ID{1}, 1, time.Second, ids.ID{2}))
+	var received time.Time
+	assert.True(t, eventually(func() bool {
+		var ok bool
+		received, ok = routers[1].got(1)
+		return ok
+	}))
+	assert.GreaterOrEqual(t, int64(received.Sub(sent)), int64(latency))
+}
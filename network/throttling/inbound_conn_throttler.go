@@ -0,0 +1,315 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Max number of IPs whose rate limiters are kept in memory
+const ipLimitersCacheSize = 4096
+
+// Reasons an inbound connection is dropped
+const (
+	rejectBanned        = "banned"
+	rejectMaxConns      = "max_conns"
+	rejectHandshakeRate = "handshake_rate"
+)
+
+var (
+	_ InboundConnThrottler = &inboundConnThrottler{}
+	_ InboundConnThrottler = &noInboundConnThrottler{}
+)
+
+// InboundConnThrottlerConfig limits the inbound connections of a single IP.
+// A zero limit means that the IP isn't limited.
+type InboundConnThrottlerConfig struct {
+	// Max number of connections a single IP can have open with this node
+	MaxConnsPerIP int
+	// Max number of handshakes per second a single IP can start
+	HandshakesPerSecPerIP float64
+	// Max number of bytes per second read from the connections of a single IP
+	BytesPerSecPerIP int
+}
+
+// InboundConnThrottler limits the inbound connections of each IP, and keeps a
+// list of banned IPs that no connection is accepted from.
+type InboundConnThrottler interface {
+	// Accept returns true if the connection [conn] from [ip] should be
+	// upgraded. If so, the returned connection, whose reads are rate-limited,
+	// must be used in place of [conn]. [ip] may open another connection once
+	// the returned connection is closed.
+	Accept(ip string, conn net.Conn) (net.Conn, bool)
+
+	// Ban closes the connections of [ip] and doesn't accept any connection
+	// from [ip] for [duration]
+	Ban(ip string, duration time.Duration)
+
+	// Unban accepts connections from [ip] again. Returns false if [ip] wasn't
+	// banned.
+	Unban(ip string) bool
+
+	// BannedIPs returns the banned IPs and the time their bans expire
+	BannedIPs() map[string]time.Time
+}
+
+// Rate limiters shared by all the connections of an IP. Either may be nil.
+type ipLimiters struct {
+	handshakes *rate.Limiter
+	bytes      *rate.Limiter
+}
+
+type inboundConnThrottler struct {
+	config  InboundConnThrottlerConfig
+	clock   timer.Clock
+	metrics inboundConnThrottlerMetrics
+
+	lock sync.Mutex
+	// IP --> Open connections with the IP
+	conns map[string]map[*throttledConn]struct{}
+	// IP --> *ipLimiters. Kept after the connections of the IP are closed, so
+	// that reconnecting doesn't reset the limits.
+	limiters cache.LRU
+	// IP --> Time the ban of the IP expires
+	banned map[string]time.Time
+}
+
+// NewInboundConnThrottler returns a throttler that limits each IP as
+// described by [config]
+func NewInboundConnThrottler(
+	config InboundConnThrottlerConfig,
+	metricsRegisterer prometheus.Registerer,
+) (InboundConnThrottler, error) {
+	t := &inboundConnThrottler{
+		config:   config,
+		conns:    make(map[string]map[*throttledConn]struct{}),
+		limiters: cache.LRU{Size: ipLimitersCacheSize},
+		banned:   make(map[string]time.Time),
+	}
+	return t, t.metrics.initialize(metricsRegisterer)
+}
+
+// Accept implements the InboundConnThrottler interface
+func (t *inboundConnThrottler) Accept(ip string, conn net.Conn) (net.Conn, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.isBanned(ip) {
+		t.metrics.rejected.WithLabelValues(rejectBanned).Inc()
+		return nil, false
+	}
+	ipConns := t.conns[ip]
+	if t.config.MaxConnsPerIP > 0 && len(ipConns) >= t.config.MaxConnsPerIP {
+		t.metrics.rejected.WithLabelValues(rejectMaxConns).Inc()
+		return nil, false
+	}
+	limiters := t.getLimiters(ip)
+	if limiters.handshakes != nil && !limiters.handshakes.AllowN(t.clock.Time(), 1) {
+		t.metrics.rejected.WithLabelValues(rejectHandshakeRate).Inc()
+		return nil, false
+	}
+
+	throttled := &throttledConn{
+		Conn:      conn,
+		limiter:   limiters.bytes,
+		readDelay: t.metrics.readDelay,
+	}
+	throttled.onClose = func() { t.release(ip, throttled) }
+	if ipConns == nil {
+		ipConns = make(map[*throttledConn]struct{})
+		t.conns[ip] = ipConns
+	}
+	ipConns[throttled] = struct{}{}
+	t.metrics.conns.Inc()
+	return throttled, true
+}
+
+// Ban implements the InboundConnThrottler interface
+func (t *inboundConnThrottler) Ban(ip string, duration time.Duration) {
+	t.lock.Lock()
+	t.banned[ip] = t.clock.Time().Add(duration)
+	t.metrics.bannedIPs.Set(float64(len(t.banned)))
+	ipConns := t.conns[ip]
+	toClose := make([]*throttledConn, 0, len(ipConns))
+	for conn := range ipConns {
+		toClose = append(toClose, conn)
+	}
+	t.lock.Unlock()
+
+	// Closing a connection releases it, which grabs [t.lock]
+	for _, conn := range toClose {
+		_ = conn.Close()
+	}
+}
+
+// Unban implements the InboundConnThrottler interface
+func (t *inboundConnThrottler) Unban(ip string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	_, ok := t.banned[ip]
+	delete(t.banned, ip)
+	t.metrics.bannedIPs.Set(float64(len(t.banned)))
+	return ok
+}
+
+// BannedIPs implements the InboundConnThrottler interface
+func (t *inboundConnThrottler) BannedIPs() map[string]time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	banned := make(map[string]time.Time, len(t.banned))
+	for ip, until := range t.banned {
+		if now.Before(until) {
+			banned[ip] = until
+		}
+	}
+	return banned
+}
+
+// isBanned returns true if [ip] is banned, and forgets its ban if it
+// expired. Assumes [t.lock] is held.
+func (t *inboundConnThrottler) isBanned(ip string) bool {
+	until, ok := t.banned[ip]
+	if !ok {
+		return false
+	}
+	if t.clock.Time().Before(until) {
+		return true
+	}
+	delete(t.banned, ip)
+	t.metrics.bannedIPs.Set(float64(len(t.banned)))
+	return false
+}
+
+// getLimiters returns the rate limiters of [ip]. Assumes [t.lock] is held.
+func (t *inboundConnThrottler) getLimiters(ip string) *ipLimiters {
+	if limitersIntf, ok := t.limiters.Get(ip); ok {
+		return limitersIntf.(*ipLimiters)
+	}
+
+	limiters := &ipLimiters{}
+	if t.config.HandshakesPerSecPerIP > 0 {
+		burst := int(math.Ceil(t.config.HandshakesPerSecPerIP))
+		limiters.handshakes = rate.NewLimiter(rate.Limit(t.config.HandshakesPerSecPerIP), burst)
+	}
+	if t.config.BytesPerSecPerIP > 0 {
+		limiters.bytes = rate.NewLimiter(rate.Limit(t.config.BytesPerSecPerIP), t.config.BytesPerSecPerIP)
+	}
+	t.limiters.Put(ip, limiters)
+	return limiters
+}
+
+// release [conn], which was closed
+func (t *inboundConnThrottler) release(ip string, conn *throttledConn) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ipConns := t.conns[ip]
+	delete(ipConns, conn)
+	if len(ipConns) == 0 {
+		delete(t.conns, ip)
+	}
+	t.metrics.conns.Dec()
+}
+
+// throttledConn is an inbound connection whose reads are rate-limited by the
+// limiter of its IP
+type throttledConn struct {
+	net.Conn
+	// Nil if reads aren't limited
+	limiter   *rate.Limiter
+	readDelay prometheus.Counter
+
+	onClose   func()
+	closeOnce sync.Once
+}
+
+// Read blocks until the bytes read are allowed by the rate limit
+func (c *throttledConn) Read(b []byte) (int, error) {
+	if c.limiter == nil {
+		return c.Conn.Read(b)
+	}
+
+	// Reading more than the limiter's burst at once would never be allowed
+	if burst := c.limiter.Burst(); len(b) > burst {
+		b = b[:burst]
+	}
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		if delay := c.limiter.ReserveN(time.Now(), n).Delay(); delay > 0 {
+			c.readDelay.Add(float64(delay))
+			time.Sleep(delay)
+		}
+	}
+	return n, err
+}
+
+// Close the connection, and release it from the throttler
+func (c *throttledConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(c.onClose)
+	return err
+}
+
+type inboundConnThrottlerMetrics struct {
+	conns     prometheus.Gauge
+	bannedIPs prometheus.Gauge
+	rejected  *prometheus.CounterVec
+	readDelay prometheus.Counter
+}
+
+func (m *inboundConnThrottlerMetrics) initialize(metricsRegisterer prometheus.Registerer) error {
+	m.conns = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "throttler_inbound_conns",
+		Help:      "Number of inbound connections open",
+	})
+	m.bannedIPs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "throttler_inbound_banned_ips",
+		Help:      "Number of IPs that are banned, including the bans that expired but weren't cleared yet",
+	})
+	m.rejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "throttler_inbound_conns_rejected",
+		Help:      "Number of inbound connections dropped by the per-IP limits",
+	}, []string{"reason"})
+	m.readDelay = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "throttler_inbound_read_delay",
+		Help:      "Time, in nanoseconds, that reads from inbound connections were delayed by the per-IP byte rate limit",
+	})
+	errs := wrappers.Errs{}
+	errs.Add(
+		metricsRegisterer.Register(m.conns),
+		metricsRegisterer.Register(m.bannedIPs),
+		metricsRegisterer.Register(m.rejected),
+		metricsRegisterer.Register(m.readDelay),
+	)
+	return errs.Err
+}
+
+type noInboundConnThrottler struct{}
+
+// NewNoInboundConnThrottler returns a throttler that accepts every connection
+// and never bans any IP
+func NewNoInboundConnThrottler() InboundConnThrottler { return noInboundConnThrottler{} }
+
+func (noInboundConnThrottler) Accept(_ string, conn net.Conn) (net.Conn, bool) { return conn, true }
+func (noInboundConnThrottler) Ban(string, time.Duration)                       {}
+func (noInboundConnThrottler) Unban(string) bool                               { return false }
+func (noInboundConnThrottler) BannedIPs() map[string]time.Time                 { return nil }
@@ -0,0 +1,134 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestInboundConnThrottler(t *testing.T, config InboundConnThrottlerConfig, now time.Time) *inboundConnThrottler {
+	throttlerIntf, err := NewInboundConnThrottler(config, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	throttler := throttlerIntf.(*inboundConnThrottler)
+	throttler.clock.Set(now)
+	return throttler
+}
+
+func TestInboundConnThrottlerMaxConns(t *testing.T) {
+	throttler := newTestInboundConnThrottler(t, InboundConnThrottlerConfig{MaxConnsPerIP: 2}, time.Now())
+
+	conn1, _ := net.Pipe()
+	conn2, _ := net.Pipe()
+	throttledConn1, ok := throttler.Accept("1.2.3.4", conn1)
+	assert.True(t, ok)
+	_, ok = throttler.Accept("1.2.3.4", conn2)
+	assert.True(t, ok)
+
+	// The IP has as many connections open as it's allowed
+	conn3, _ := net.Pipe()
+	_, ok = throttler.Accept("1.2.3.4", conn3)
+	assert.False(t, ok)
+	_, ok = throttler.Accept("5.6.7.8", conn3)
+	assert.True(t, ok)
+
+	// Closing a connection lets the IP open another one. Closing it again
+	// doesn't.
+	assert.NoError(t, throttledConn1.Close())
+	_ = throttledConn1.Close()
+	conn4, _ := net.Pipe()
+	_, ok = throttler.Accept("1.2.3.4", conn4)
+	assert.True(t, ok)
+	conn5, _ := net.Pipe()
+	_, ok = throttler.Accept("1.2.3.4", conn5)
+	assert.False(t, ok)
+}
+
+func TestInboundConnThrottlerHandshakeRate(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	throttler := newTestInboundConnThrottler(t, InboundConnThrottlerConfig{HandshakesPerSecPerIP: 2}, now)
+
+	for i := 0; i < 2; i++ {
+		conn, _ := net.Pipe()
+		_, ok := throttler.Accept("1.2.3.4", conn)
+		assert.True(t, ok)
+	}
+	conn, _ := net.Pipe()
+	_, ok := throttler.Accept("1.2.3.4", conn)
+	assert.False(t, ok)
+	_, ok = throttler.Accept("5.6.7.8", conn)
+	assert.True(t, ok)
+
+	throttler.clock.Set(now.Add(time.Second))
+	_, ok = throttler.Accept("1.2.3.4", conn)
+	assert.True(t, ok)
+}
+
+func TestInboundConnThrottlerBan(t *testing.T) {
+	now := time.Unix(1607626800, 0)
+	throttler := newTestInboundConnThrottler(t, InboundConnThrottlerConfig{}, now)
+
+	conn, peerConn := net.Pipe()
+	_, ok := throttler.Accept("1.2.3.4", conn)
+	assert.True(t, ok)
+
+	// Banning the IP closes its connections
+	throttler.Ban("1.2.3.4", time.Hour)
+	_, err := peerConn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.Empty(t, throttler.conns)
+
+	conn, _ = net.Pipe()
+	_, ok = throttler.Accept("1.2.3.4", conn)
+	assert.False(t, ok)
+	assert.Equal(t, map[string]time.Time{"1.2.3.4": now.Add(time.Hour)}, throttler.BannedIPs())
+
+	// The ban expires
+	throttler.clock.Set(now.Add(time.Hour))
+	assert.Empty(t, throttler.BannedIPs())
+	_, ok = throttler.Accept("1.2.3.4", conn)
+	assert.True(t, ok)
+
+	throttler.Ban("5.6.7.8", time.Hour)
+	assert.True(t, throttler.Unban("5.6.7.8"))
+	assert.False(t, throttler.Unban("5.6.7.8"))
+	_, ok = throttler.Accept("5.6.7.8", conn)
+	assert.True(t, ok)
+}
+
+func TestInboundConnThrottlerBytesRate(t *testing.T) {
+	throttler := newTestInboundConnThrottler(t, InboundConnThrottlerConfig{BytesPerSecPerIP: 1024}, time.Now())
+
+	conn, peerConn := net.Pipe()
+	throttledConn, ok := throttler.Accept("1.2.3.4", conn)
+	assert.True(t, ok)
+
+	go func() {
+		_, _ = peerConn.Write(make([]byte, 2048))
+	}()
+
+	// A single read doesn't return more bytes than the IP may send per second
+	n, err := throttledConn.Read(make([]byte, 2048))
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, n, 1024)
+}
+
+func TestNoInboundConnThrottler(t *testing.T) {
+	throttler := NewNoInboundConnThrottler()
+
+	conn, _ := net.Pipe()
+	for i := 0; i < 100; i++ {
+		acceptedConn, ok := throttler.Accept("1.2.3.4", conn)
+		assert.True(t, ok)
+		assert.Equal(t, conn, acceptedConn)
+	}
+	throttler.Ban("1.2.3.4", time.Hour)
+	_, ok := throttler.Accept("1.2.3.4", conn)
+	assert.True(t, ok)
+	assert.Empty(t, throttler.BannedIPs())
+}
@@ -0,0 +1,108 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/network/geoip"
+)
+
+// Topology describes how the peers this node is connected to are distributed
+// across countries and autonomous systems.
+type Topology struct {
+	// True iff this node is able to resolve the location of its peers
+	Enabled bool `json:"enabled"`
+	// Number of peers that have finished the handshake
+	NumPeers int `json:"numPeers"`
+	// Country --> Number of peers in the country
+	Countries map[string]int `json:"countries"`
+	// ASN --> Number of peers in the autonomous system
+	AutonomousSystems map[string]ASTopology `json:"autonomousSystems"`
+}
+
+// ASTopology describes the peers in an autonomous system
+type ASTopology struct {
+	Organization string `json:"organization"`
+	NumPeers     int    `json:"numPeers"`
+}
+
+// Topology implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) Topology() Topology {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	topology := Topology{
+		Enabled:           n.geoIPResolver.IsResolver(),
+		Countries:         make(map[string]int),
+		AutonomousSystems: make(map[string]ASTopology),
+	}
+	for _, peer := range n.peers.peersList {
+		if !peer.finishedHandshake.GetValue() {
+			continue
+		}
+		topology.NumPeers++
+		if !topology.Enabled {
+			continue
+		}
+
+		country, asn := countryLabel(peer), asnLabel(peer)
+		topology.Countries[country]++
+		as := topology.AutonomousSystems[asn]
+		as.Organization = peer.location.ASOrganization
+		as.NumPeers++
+		topology.AutonomousSystems[asn] = as
+	}
+	return topology
+}
+
+// locate resolves the location of [p] and records it in the metrics.
+// Assumes [n.stateLock] is held.
+func (n *network) locate(p *peer) {
+	if !n.geoIPResolver.IsResolver() {
+		return
+	}
+
+	// The IP of the connection is used rather than the IP the peer claims to
+	// be reachable at, as the latter isn't always known.
+	if addr, ok := p.conn.RemoteAddr().(*net.TCPAddr); ok {
+		location, err := n.geoIPResolver.Lookup(addr.IP)
+		if err == nil {
+			p.location = location
+			p.hasLocation = true
+		} else {
+			n.log.Verbo("couldn't locate %s at %s: %s", p.nodeID, addr.IP, err)
+		}
+	}
+
+	n.peersByCountry.WithLabelValues(countryLabel(p)).Inc()
+	n.peersByASN.WithLabelValues(asnLabel(p)).Inc()
+}
+
+// unlocate removes [p] from the location metrics.
+// Assumes [n.stateLock] is held.
+func (n *network) unlocate(p *peer) {
+	if !n.geoIPResolver.IsResolver() {
+		return
+	}
+
+	n.peersByCountry.WithLabelValues(countryLabel(p)).Dec()
+	n.peersByASN.WithLabelValues(asnLabel(p)).Dec()
+}
+
+func countryLabel(p *peer) string {
+	if !p.hasLocation || p.location.Country == "" {
+		return geoip.Unknown
+	}
+	return p.location.Country
+}
+
+func asnLabel(p *peer) string {
+	if !p.hasLocation {
+		return geoip.Unknown
+	}
+	return strconv.FormatUint(uint64(p.location.ASN), 10)
+}
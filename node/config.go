@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/utils"
@@ -25,6 +26,11 @@ import (
 type Config struct {
 	genesis.Params
 
+	// Time of the transition from epoch 0 to epoch 1, from the genesis
+	EpochFirstTransition time.Time
+	// Duration of each epoch, from the genesis
+	EpochDuration time.Duration
+
 	// If true, bootstrap the current database version and then end the node.
 	FetchOnly bool
 
@@ -46,6 +52,9 @@ type Config struct {
 
 	// Crypto configuration
 	EnableCrypto bool
+	// Number of workers shared by all the chains to run cryptographic
+	// operations
+	CryptoWorkers int
 
 	// Path to database
 	DBPath string
@@ -65,6 +74,12 @@ type Config struct {
 	// Health
 	HealthCheckFreq time.Duration
 
+	// Endpoint signed health beacons are sent to. If empty, no beacons are
+	// sent.
+	HealthBeaconURL string
+	// Time between health beacons
+	HealthBeaconFreq time.Duration
+
 	// Network configuration
 	NetworkConfig      network.Config
 	PeerListSize       uint32
@@ -106,9 +121,72 @@ type Config struct {
 	// Plugin directory
 	PluginDir string
 
+	// If non-empty, the cgroup directory that the cgroups limiting the
+	// resources of VM plugins are created in
+	PluginCgroupDir string
+
+	// Max number of errors the VM and consensus engine of a chain may log
+	// within an hour before it's marked degraded and an incident snapshot of
+	// it is written to [ChainIncidentDir]. 0 disables the budget.
+	ChainErrorBudget int
+	ChainIncidentDir string
+
+	// Directory that chain bundles are exported to and imported from through
+	// the admin API
+	ChainExportDir string
+
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
+	// IDs or aliases of the DAG based chains that should use the input based
+	// conflict graph
+	InputConflictGraphChains []string
+
+	// If non-empty, the directory that the replay logs of the conflict graphs
+	// of DAG based chains are recorded to
+	ReplayLogDir string
+
+	// If positive, repolls favor validators that respond quickly
+	RepollLatencyBias float64
+
+	// If true, DAG based chains that this node doesn't validate observe the
+	// votes of validators rather than polling them
+	LightVerification bool
+
+	// If true, DAG based chains issue an additional repoll when a poll changes
+	// the virtuous frontier
+	VirtuousRepoll bool
+
+	// Max number of vertices DAG based chains keep after abandoning them for
+	// missing dependencies, and how long they're kept
+	MaxOrphans   int
+	OrphanExpiry time.Duration
+
+	// Limits on the vertices DAG based chains build
+	VertexLimits vertex.Limits
+
+	// If positive, DAG based chains try to recover when they haven't decided
+	// a vertex for this long
+	StallThreshold time.Duration
+
+	// If true, new DAG based chains sync their state from a beacon
+	StateSync bool
+
+	// If greater than 1, DAG based chains verify the txs of a vertex
+	// concurrently
+	TxVerifiers int
+
+	// If positive, how often DAG based chains fetch the vertices they're
+	// missing from the accepted frontier of a validator
+	FrontierSyncFrequency time.Duration
+
+	// If true, DAG based chains replace the benched validators sampled for a
+	// poll
+	SkipBenched bool
+
+	// If true, linear chains index their accepted blocks by height
+	HeightIndex bool
+
 	// IPC configuration
 	IPCAPIEnabled      bool
 	IPCPath            string
@@ -126,12 +204,18 @@ type Config struct {
 	ConsensusGossipAcceptedFrontierSize uint
 	// Number of peers to gossip each accepted container to
 	ConsensusGossipOnAcceptSize uint
+	// If positive, max number of messages waiting to be handled over all
+	// chains
+	RouterMaxPendingMsgs int
 
 	// Dynamic Update duration for IP or NAT traversal
 	DynamicUpdateDuration time.Duration
 
 	DynamicPublicIPResolver dynamicip.Resolver
 
+	// Points a hostname at our public IP when it changes. May be nil.
+	DynamicDNSUpdater dynamicip.DNSUpdater
+
 	// Throttling incoming connections
 	ConnMeterResetDuration time.Duration
 	ConnMeterMaxConns      int
@@ -139,6 +223,11 @@ type Config struct {
 	// Subnet Whitelist
 	WhitelistedSubnets ids.Set
 
+	// Chains of the whitelisted subnets to run. If empty, all of them are run.
+	WhitelistedChains ids.Set
+	// Chains of the whitelisted subnets not to run
+	BlacklistedChains ids.Set
+
 	IndexAllowIncomplete bool
 
 	// Should Bootstrap be retried
@@ -157,6 +246,18 @@ type Config struct {
 	// containers in a multiput it receives.
 	BootstrapMultiputMaxContainersReceived int
 
+	// Max number of GetAncestors requests a DAG sends concurrently while
+	// bootstrapping
+	BootstrapMaxOutstandingGetAncestors int
+
+	// If positive, a chain that hasn't accepted a container for this long
+	// while beacons report higher accepted containers is bootstrapped again
+	BootstrapStaleChainThreshold time.Duration
+
+	// If non-empty, the directory that containers fetched during bootstrapping
+	// are stored in until they are executed
+	BootstrapContainerDir string
+
 	// Peer alias configuration
 	PeerAliasTimeout time.Duration
 
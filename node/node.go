@@ -11,6 +11,7 @@ import (
 	"net"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api/admin"
 	"github.com/ava-labs/avalanchego/api/auth"
@@ -44,6 +45,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/workers"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
@@ -62,11 +64,16 @@ import (
 // Networking constants
 const (
 	TCP = "tcp"
+
+	// Minimum number of peers that must have reported their time before the
+	// network time is estimated
+	networkClockMinPeers = 5
 )
 
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey    = []byte("genesisID")
+	indexerDBPrefix   = []byte{0x00}
+	peerStoreDBPrefix = []byte("peers")
 
 	errPrimarySubnetNotBootstrapped = errors.New("primary subnet has not finished bootstrapping")
 	errInvalidTLSKey                = errors.New("invalid TLS key")
@@ -89,6 +96,9 @@ type Node struct {
 	// Profiles the process. Nil if continuous profiling is disabled.
 	profiler profiler.ContinuousProfiler
 
+	// Records runtime stats of the process. Nil if sampling is disabled.
+	sampler profiler.Sampler
+
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
@@ -101,6 +111,9 @@ type Node struct {
 	// Monitors node health and runs health checks
 	healthService health.Service
 
+	// Sends signed health beacons. Nil if no beacon endpoint is configured.
+	healthBeacon health.Beacon
+
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
@@ -110,15 +123,34 @@ type Node struct {
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
+	// Scores the misbehavior of peers, and bans peers that misbehave too much
+	peerScorer network.Scorer
+
+	// Limits the inbound connections of each IP, and keeps the IPs banned
+	// through the admin API
+	inboundConnThrottler throttling.InboundConnThrottler
+
+	// Persists the peers this node connects to
+	peerStore network.PeerStore
+
 	// dispatcher for events as they happen in consensus
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
+	// Dispatches events about this node, rather than about a chain, such as
+	// peer connections and health check transitions
+	NodeDispatcher *triggers.EventDispatcher
 
 	IPCs *ipcs.ChainIPCs
 
 	// Net runs the networking stack
 	Net network.Network
 
+	// Estimates the network time from the times reported by peers
+	networkClock *timer.NetworkClock
+
+	// Runs the cryptographic operations of the chains
+	cryptoPool *workers.Pool
+
 	// this node's initial connections to the network
 	beacons validators.Set
 
@@ -228,6 +260,11 @@ func (n *Node) initNetworking() error {
 		}
 	}
 
+	consensusRouter = &peerEventRouter{
+		Router: consensusRouter,
+		events: n.NodeDispatcher,
+	}
+
 	versionManager := version.GetCompatibility(n.Config.NetworkID)
 
 	inboundMsgThrottler, err := throttling.NewSybilInboundMsgThrottler(
@@ -250,6 +287,31 @@ func (n *Node) initNetworking() error {
 		return fmt.Errorf("initializing outbound message throttler failed with: %s", err)
 	}
 
+	n.peerScorer, err = network.NewScorer(
+		n.Config.NetworkConfig.ScoringConfig,
+		n.Config.NetworkConfig.MetricsRegisterer,
+	)
+	if err != nil {
+		return fmt.Errorf("initializing peer scorer failed with: %s", err)
+	}
+
+	n.inboundConnThrottler, err = throttling.NewInboundConnThrottler(
+		n.Config.NetworkConfig.InboundConnThrottlerConfig,
+		n.Config.NetworkConfig.MetricsRegisterer,
+	)
+	if err != nil {
+		return fmt.Errorf("initializing inbound connection throttler failed with: %s", err)
+	}
+
+	n.peerStore = network.NewNoPeerStore()
+	if n.Config.NetworkConfig.MaxReconnectPeers > 0 {
+		n.peerStore, err = network.NewPeerStore(prefixdb.New(peerStoreDBPrefix, n.DB))
+		if err != nil {
+			return fmt.Errorf("initializing peer store failed with: %s", err)
+		}
+	}
+
+	n.networkClock = timer.NewNetworkClock(primaryNetworkValidators, networkClockMinPeers)
 	n.Net = network.NewDefaultNetwork(
 		n.Config.ConsensusParams.Metrics,
 		n.Log,
@@ -280,8 +342,34 @@ func (n *Node) initNetworking() error {
 		n.Config.ConsensusGossipOnAcceptSize,
 		inboundMsgThrottler,
 		outboundMsgThrottler,
+		n.Config.NetworkConfig.GeoIPResolver,
+		n.networkClock,
+		n.Config.EpochFirstTransition,
+		n.Config.EpochDuration,
+		n.Config.NetworkConfig.CompressionConfig,
+		n.peerScorer,
+		n.inboundConnThrottler,
+		n.peerStore,
+		n.Config.NetworkConfig.MaxReconnectPeers,
+		n.Config.NetworkConfig.PeerMetricsEnabled,
 	)
-	return nil
+	return n.ConsensusDispatcher.Register("gossip", n.Net)
+}
+
+// peerEventRouter dispatches the peer events it routes to [events]
+type peerEventRouter struct {
+	router.Router
+	events *triggers.EventDispatcher
+}
+
+func (p *peerEventRouter) Connected(nodeID ids.ShortID) {
+	p.Router.Connected(nodeID)
+	p.events.Connected(nodeID)
+}
+
+func (p *peerEventRouter) Disconnected(nodeID ids.ShortID) {
+	p.Router.Disconnected(nodeID)
+	p.events.Disconnected(nodeID)
 }
 
 type insecureValidatorManager struct {
@@ -441,7 +529,14 @@ func (n *Node) initEventDispatcher() error {
 	n.ConsensusDispatcher = &triggers.EventDispatcher{}
 	n.ConsensusDispatcher.Initialize(n.Log)
 
-	return n.ConsensusDispatcher.Register("gossip", n.Net)
+	n.NodeDispatcher = &triggers.EventDispatcher{}
+	n.NodeDispatcher.Initialize(n.Log)
+
+	metrics, err := triggers.NewMetrics(n.Config.NetworkConfig.MetricsNamespace, n.Config.NetworkConfig.MetricsRegisterer)
+	if err != nil {
+		return err
+	}
+	return n.NodeDispatcher.Register("metrics", metrics)
 }
 
 func (n *Node) initIPCs() error {
@@ -556,7 +651,7 @@ func (n *Node) initVMManager(genesisBytes []byte) error {
 
 	for vmID, aliases := range vmAliases {
 		for _, alias := range aliases {
-			if err := n.vmManager.Alias(vmID, alias); err != nil {
+			if err := n.vmManager.RegisterAlias(vmID, alias, "genesis"); err != nil {
 				return err
 			}
 		}
@@ -565,7 +660,7 @@ func (n *Node) initVMManager(genesisBytes []byte) error {
 	// use aliases in given config
 	for vmID, aliases := range n.Config.VMAliases {
 		for _, alias := range aliases {
-			if err := n.vmManager.Alias(vmID, alias); err != nil {
+			if err := n.vmManager.RegisterAlias(vmID, alias, "VM aliases config"); err != nil {
 				return err
 			}
 		}
@@ -601,7 +696,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	timeoutManager := &timeout.Manager{}
 	if err := timeoutManager.Initialize(
 		&n.Config.NetworkConfig.AdaptiveTimeoutConfig,
-		n.benchlistManager,
+		network.NewScoringBenchlist(n.benchlistManager, n.peerScorer),
 		n.Config.NetworkConfig.MetricsNamespace,
 		n.Config.NetworkConfig.MetricsRegisterer,
 	); err != nil {
@@ -625,6 +720,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	if err != nil {
 		return fmt.Errorf("couldn't initialize chain router: %w", err)
 	}
+	n.Config.ConsensusRouter.SetMaxPendingMsgs(n.Config.RouterMaxPendingMsgs)
 
 	fetchOnlyFrom := validators.NewSet()
 	for _, peerID := range n.Config.BootstrapIDs {
@@ -633,6 +729,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		}
 	}
 
+	n.cryptoPool = workers.NewPool(n.Config.CryptoWorkers)
 	n.chainManager = chains.New(&chains.ManagerConfig{
 		FetchOnly:                              n.Config.FetchOnly,
 		FetchOnlyFrom:                          fetchOnlyFrom,
@@ -645,7 +742,23 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		DBManager:                              n.DBManager,
 		Router:                                 n.Config.ConsensusRouter,
 		Net:                                    n.Net,
+		NetworkClock:                           n.networkClock,
+		CryptoPool:                             n.cryptoPool,
 		ConsensusParams:                        n.Config.ConsensusParams,
+		InputConflictGraphChains:               n.Config.InputConflictGraphChains,
+		ReplayLogDir:                           n.Config.ReplayLogDir,
+		RepollLatencyBias:                      n.Config.RepollLatencyBias,
+		LightVerification:                      n.Config.LightVerification,
+		VirtuousRepoll:                         n.Config.VirtuousRepoll,
+		MaxOrphans:                             n.Config.MaxOrphans,
+		OrphanExpiry:                           n.Config.OrphanExpiry,
+		VertexLimits:                           n.Config.VertexLimits,
+		StallThreshold:                         n.Config.StallThreshold,
+		StateSync:                              n.Config.StateSync,
+		TxVerifiers:                            n.Config.TxVerifiers,
+		FrontierSyncFrequency:                  n.Config.FrontierSyncFrequency,
+		SkipBenched:                            n.Config.SkipBenched,
+		HeightIndex:                            n.Config.HeightIndex,
 		EpochFirstTransition:                   n.Config.EpochFirstTransition,
 		EpochDuration:                          n.Config.EpochDuration,
 		Validators:                             n.vdrs,
@@ -660,6 +773,8 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		TimeoutManager:                         timeoutManager,
 		HealthService:                          n.healthService,
 		WhitelistedSubnets:                     n.Config.WhitelistedSubnets,
+		WhitelistedChains:                      n.Config.WhitelistedChains,
+		BlacklistedChains:                      n.Config.BlacklistedChains,
 		RetryBootstrap:                         n.Config.RetryBootstrap,
 		RetryBootstrapMaxAttempts:              n.Config.RetryBootstrapMaxAttempts,
 		ShutdownNodeFunc:                       n.Shutdown,
@@ -668,6 +783,11 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		BootstrapMaxTimeGetAncestors:           n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapMultiputMaxContainersSent:     n.Config.BootstrapMultiputMaxContainersSent,
 		BootstrapMultiputMaxContainersReceived: n.Config.BootstrapMultiputMaxContainersReceived,
+		BootstrapMaxOutstandingGetAncestors:    n.Config.BootstrapMaxOutstandingGetAncestors,
+		BootstrapStaleChainThreshold:           n.Config.BootstrapStaleChainThreshold,
+		BootstrapContainerDir:                  n.Config.BootstrapContainerDir,
+		ChainErrorBudget:                       n.Config.ChainErrorBudget,
+		ChainIncidentDir:                       n.Config.ChainIncidentDir,
 	})
 
 	vdrs := n.vdrs
@@ -745,7 +865,8 @@ func (n *Node) registerRPCVMs() error {
 		}
 
 		if err = n.vmManager.RegisterFactory(vmID, &rpcchainvm.Factory{
-			Path: filepath.Join(n.Config.PluginDir, file.Name()),
+			Path:      filepath.Join(n.Config.PluginDir, file.Name()),
+			CgroupDir: n.Config.PluginCgroupDir,
 		}); err != nil {
 			return err
 		}
@@ -821,7 +942,7 @@ func (n *Node) initAdminAPI() error {
 		return nil
 	}
 	n.Log.Info("initializing admin API")
-	service, err := admin.NewService(n.Log, n.chainManager, &n.APIServer, n.Config.ProfilerConfig.Dir)
+	service, err := admin.NewService(n.Log, n.chainManager, &n.APIServer, n.Config.ProfilerConfig.Dir, n.sampler, n.inboundConnThrottler, n.Config.ChainExportDir)
 	if err != nil {
 		return err
 	}
@@ -850,6 +971,89 @@ func (n *Node) initProfiler() {
 	})
 }
 
+// initHealthBeacon starts sending signed health beacons to the configured
+// endpoint
+// Assumes n.healthService and n.chainManager are already initialized
+func (n *Node) initHealthBeacon() {
+	if n.Config.HealthBeaconURL == "" {
+		n.Log.Info("skipping health beacon initialization because no endpoint is configured")
+		return
+	}
+
+	n.Log.Info("initializing health beacon to %s", n.Config.HealthBeaconURL)
+	n.healthBeacon = health.NewBeacon(
+		n.Log,
+		n.Config.HealthBeaconURL,
+		n.Config.HealthBeaconFreq,
+		n.Config.StakingTLSCert,
+		n.healthBeaconMessage,
+	)
+	go n.Log.RecoverAndPanic(n.healthBeacon.Dispatch)
+}
+
+// healthBeaconMessage returns the current health of this node and the
+// progress of the primary network's chains
+func (n *Node) healthBeaconMessage() *health.BeaconMessage {
+	_, healthy := n.healthService.Results()
+	msg := &health.BeaconMessage{
+		NodeID:    n.ID,
+		Version:   version.CurrentApp.String(),
+		Timestamp: time.Now(),
+		Healthy:   healthy,
+		Chains:    make(map[string]health.ChainStatus),
+	}
+	for _, alias := range []string{"P", "X", "C"} {
+		chainID, err := n.chainManager.Lookup(alias)
+		if err != nil {
+			continue
+		}
+		status := health.ChainStatus{
+			Bootstrapped: n.chainManager.IsBootstrapped(chainID),
+		}
+		status.Frontier, status.Height, err = n.chainManager.Frontier(chainID)
+		if err != nil {
+			n.Log.Debug("couldn't get the frontier of chain %s: %s", alias, err)
+		}
+		msg.Chains[alias] = status
+	}
+	return msg
+}
+
+// initSampler initializes the runtime stats sampler
+// Assumes n.chainManager is already initialized
+func (n *Node) initSampler() {
+	if !n.Config.ProfilerConfig.SamplerEnabled {
+		n.Log.Info("skipping runtime stats sampler initialization because it has been disabled")
+		return
+	}
+
+	n.Log.Info("initializing runtime stats sampler")
+	n.sampler = profiler.NewSampler(
+		n.Config.ProfilerConfig.SamplerFreq,
+		n.Config.ProfilerConfig.SamplerMaxNumSamples,
+		n.chainSamples,
+	)
+	go n.Log.RecoverAndPanic(n.sampler.Dispatch)
+}
+
+// chainSamples returns the load on each chain, keyed by the chain's primary
+// alias
+func (n *Node) chainSamples() map[string]profiler.ChainSample {
+	stats := n.Config.ConsensusRouter.HandlerStats()
+	samples := make(map[string]profiler.ChainSample, len(stats))
+	for chainID, chainStats := range stats {
+		alias := chainID.String()
+		if aliases := n.chainManager.Aliases(chainID); len(aliases) > 0 {
+			alias = aliases[0]
+		}
+		samples[alias] = profiler.ChainSample{
+			QueueDepth: chainStats.NumUnprocessedMsgs,
+			LockWait:   chainStats.LockWait,
+		}
+	}
+	return samples
+}
+
 func (n *Node) initInfoAPI() error {
 	if !n.Config.InfoAPIEnabled {
 		n.Log.Info("skipping info API initialization because it has been disabled")
@@ -883,7 +1087,13 @@ func (n *Node) initHealthAPI() error {
 	}
 
 	n.Log.Info("initializing Health API")
-	healthService, err := health.NewService(n.Config.HealthCheckFreq, n.Log, n.Config.NetworkConfig.MetricsNamespace, n.Config.ConsensusParams.Metrics)
+	healthService, err := health.NewService(
+		n.Config.HealthCheckFreq,
+		n.Log,
+		n.Config.NetworkConfig.MetricsNamespace,
+		n.Config.ConsensusParams.Metrics,
+		n.NodeDispatcher.HealthChanged,
+	)
 	if err != nil {
 		return err
 	}
@@ -953,12 +1163,15 @@ func (n *Node) initChainAliases(genesisBytes []byte) error {
 
 	for chainID, aliases := range chainAliases {
 		for _, alias := range aliases {
-			if err := n.chainManager.Alias(chainID, alias); err != nil {
+			if err := n.chainManager.RegisterAlias(chainID, alias, "genesis"); err != nil {
 				return err
 			}
 		}
 	}
-	return nil
+
+	// Persisted aliases are loaded after the genesis aliases so that the
+	// genesis aliases take precedence
+	return n.chainManager.LoadAliases()
 }
 
 // APIs aliases as specified by the genesis information
@@ -1025,12 +1238,12 @@ func (n *Node) Initialize(
 		return fmt.Errorf("problem initializing shared memory: %w", err)
 	}
 
-	if err = n.initNetworking(); err != nil { // Set up all networking
-		return fmt.Errorf("problem initializing networking: %w", err)
-	}
 	if err = n.initEventDispatcher(); err != nil { // Set up the event dipatcher
 		return fmt.Errorf("problem initializing event dispatcher: %w", err)
 	}
+	if err = n.initNetworking(); err != nil { // Set up all networking
+		return fmt.Errorf("problem initializing networking: %w", err)
+	}
 
 	// Start the Health API
 	// Has to be initialized before chain manager
@@ -1044,6 +1257,8 @@ func (n *Node) Initialize(
 	if err := n.initChainManager(n.Config.AvaxAssetID); err != nil { // Set up the chain manager
 		return fmt.Errorf("couldn't initialize chain manager: %w", err)
 	}
+	n.initSampler()
+	n.initHealthBeacon()
 	if err := n.initAdminAPI(); err != nil { // Start the Admin API
 		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
@@ -1093,9 +1308,18 @@ func (n *Node) shutdown() {
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
+	if n.cryptoPool != nil {
+		n.cryptoPool.Shutdown()
+	}
 	if n.profiler != nil {
 		n.profiler.Shutdown()
 	}
+	if n.sampler != nil {
+		n.sampler.Shutdown()
+	}
+	if n.healthBeacon != nil {
+		n.healthBeacon.Shutdown()
+	}
 	if n.Net != nil {
 		// Close already logs its own error if one occurs, so the error is ignored here
 		_ = n.Net.Close()
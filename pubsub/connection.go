@@ -12,19 +12,25 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 )
 
 var (
 	ErrFilterNotInitialized        = errors.New("filter not initialized")
 	ErrAddressLimit                = errors.New("address limit exceeded")
+	ErrAssetLimit                  = errors.New("asset limit exceeded")
 	ErrInvalidFilterParam          = errors.New("invalid bloom filter params")
 	ErrInvalidCommand              = errors.New("invalid command")
 	_                       Filter = &connection{}
 )
 
 type Filter interface {
+	// Check returns true if [addr] passes the address filter
 	Check(addr []byte) bool
+	// CheckAsset returns true if [assetID] passes the asset filter
+	CheckAsset(assetID ids.ID) bool
 }
 
 // connection is a representation of the websocket connection.
@@ -40,12 +46,25 @@ type connection struct {
 	fp *FilterParam
 
 	active uint32
+
+	// If true, the buffered events published after [after] are sent when this
+	// connection subscribes. Only accessed while [s.eventsLock] is held.
+	resume bool
+	after  uint64
+
+	// Encoding of the messages sent to this connection. If nil, messages are
+	// sent as published.
+	encoding *formatting.Encoding
 }
 
 func (c *connection) Check(addr []byte) bool {
 	return c.fp.Check(addr)
 }
 
+func (c *connection) CheckAsset(assetID ids.ID) bool {
+	return c.fp.CheckAsset(assetID)
+}
+
 func (c *connection) isActive() bool {
 	active := atomic.LoadUint32(&c.active)
 	return active != 0
@@ -55,6 +74,13 @@ func (c *connection) deactivate() {
 	atomic.StoreUint32(&c.active, 0)
 }
 
+// close the connection. The read and write pumps return once they fail to use
+// the connection.
+func (c *connection) close() {
+	c.deactivate()
+	_ = c.conn.Close()
+}
+
 func (c *connection) Send(msg interface{}) bool {
 	if !c.isActive() {
 		return false
@@ -165,6 +191,8 @@ func (c *connection) readMessage() error {
 		c.handleNewSet(cmd.NewSet)
 	case cmd.AddAddresses != nil:
 		err = c.handleAddAddresses(cmd.AddAddresses)
+	case cmd.AddAssets != nil:
+		err = c.handleAddAssets(cmd.AddAssets)
 	default:
 		err = ErrInvalidCommand
 	}
@@ -200,6 +228,19 @@ func (c *connection) handleAddAddresses(cmd *AddAddresses) error {
 	if err != nil {
 		return fmt.Errorf("address append failed %w", err)
 	}
-	c.s.subscribedConnections.Add(c)
+	c.s.subscribe(c)
+	return nil
+}
+
+// handleAddAssets narrows the events sent to this connection to the ones
+// about the given assets. It doesn't subscribe the connection, which only
+// receives events once it added addresses.
+func (c *connection) handleAddAssets(cmd *AddAssets) error {
+	if err := cmd.parseAssetIDs(); err != nil {
+		return fmt.Errorf("asset parse failed %w", err)
+	}
+	if err := c.fp.AddAssets(cmd.assetIDs...); err != nil {
+		return fmt.Errorf("asset append failed %w", err)
+	}
 	return nil
 }
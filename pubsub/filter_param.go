@@ -6,6 +6,7 @@ package pubsub
 import (
 	"sync"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/bloom"
 )
 
@@ -13,6 +14,8 @@ type FilterParam struct {
 	lock   sync.RWMutex
 	set    map[string]struct{}
 	filter bloom.Filter
+	// If non-empty, only the events about these assets pass the filter
+	assets ids.Set
 }
 
 func NewFilterParam() *FilterParam {
@@ -21,12 +24,15 @@ func NewFilterParam() *FilterParam {
 	}
 }
 
+// NewSet resets the filter to an empty set of addresses, and removes the
+// asset filter
 func (f *FilterParam) NewSet() {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
 	f.set = make(map[string]struct{})
 	f.filter = nil
+	f.assets = nil
 }
 
 func (f *FilterParam) Filter() bloom.Filter {
@@ -79,6 +85,27 @@ func (f *FilterParam) Add(bl ...[]byte) error {
 	return nil
 }
 
+// CheckAsset returns true if [assetID] passes the asset filter. Every asset
+// passes if no asset was added.
+func (f *FilterParam) CheckAsset(assetID ids.ID) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.assets.Len() == 0 || f.assets.Contains(assetID)
+}
+
+// AddAssets adds [assetIDs] to the asset filter
+func (f *FilterParam) AddAssets(assetIDs ...ids.ID) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.assets.Len()+len(assetIDs) > MaxAssets {
+		return ErrAssetLimit
+	}
+	f.assets.Add(assetIDs...)
+	return nil
+}
+
 func (f *FilterParam) Len() int {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
@@ -92,3 +92,38 @@ func TestNewBloom(t *testing.T) {
 		t.Fatalf("new filter check failed")
 	}
 }
+
+func TestFilterParamAssets(t *testing.T) {
+	assert := assert.New(t)
+
+	fp := NewFilterParam()
+	assetID := ids.GenerateTestID()
+	otherAssetID := ids.GenerateTestID()
+
+	// Every asset passes if no asset was added
+	assert.True(fp.CheckAsset(assetID))
+	assert.True(fp.CheckAsset(otherAssetID))
+
+	assert.NoError(fp.AddAssets(assetID))
+	assert.True(fp.CheckAsset(assetID))
+	assert.False(fp.CheckAsset(otherAssetID))
+
+	// A new set removes the asset filter
+	fp.NewSet()
+	assert.True(fp.CheckAsset(otherAssetID))
+
+	tooMany := make([]ids.ID, MaxAssets+1)
+	assert.ErrorIs(fp.AddAssets(tooMany...), ErrAssetLimit)
+}
+
+func TestAddAssetsParseAssetIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	assetID := ids.GenerateTestID()
+	msg := &AddAssets{AssetIDs: []string{assetID.String()}}
+	assert.NoError(msg.parseAssetIDs())
+	assert.Equal([]ids.ID{assetID}, msg.assetIDs)
+
+	msg = &AddAssets{AssetIDs: []string{"not an ID"}}
+	assert.Error(msg.parseAssetIDs())
+}
@@ -3,6 +3,17 @@
 
 package pubsub
 
+import "github.com/ava-labs/avalanchego/utils/formatting"
+
 type Filterer interface {
 	Filter(connections []Filter) ([]bool, interface{})
 }
+
+// Formatter is implemented by the messages that are sent in the encoding
+// requested by each client. Clients request an encoding with the [encoding]
+// query parameter, and are sent the message as is if they didn't.
+type Formatter interface {
+	// Format returns the message sent to the clients that requested
+	// [encoding]
+	Format(encoding formatting.Encoding) (interface{}, error)
+}
@@ -5,10 +5,23 @@ package pubsub
 
 import (
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
 )
 
+// Event is a published event sent to a client
+type Event struct {
+	// Sequence number of the event. Each published event is numbered with the
+	// next sequence number, so a client skips the numbers of the events that
+	// don't match its filter. To resume after reconnecting, a client passes
+	// the sequence number of the last event it received as the [after] query
+	// parameter.
+	Seq json.Uint64 `json:"seq"`
+	// The event, as built by the publisher
+	Event interface{} `json:"event"`
+}
+
 // NewBloom command for a new bloom filter
 type NewBloom struct {
 	// MaxElements size of bloom filter
@@ -28,11 +41,20 @@ type AddAddresses struct {
 	addressIds [][]byte
 }
 
+// AddAssets command to only receive the events about the given assets
+type AddAssets struct {
+	AssetIDs []string `json:"assetIDs"`
+
+	// assetIDs the parsed [AssetIDs]
+	assetIDs []ids.ID
+}
+
 // Command execution command
 type Command struct {
 	NewBloom     *NewBloom     `json:"newBloom,omitempty"`
 	NewSet       *NewSet       `json:"newSet,omitempty"`
 	AddAddresses *AddAddresses `json:"addAddresses,omitempty"`
+	AddAssets    *AddAssets    `json:"addAssets,omitempty"`
 }
 
 func (c *Command) String() string {
@@ -43,6 +65,8 @@ func (c *Command) String() string {
 		return "newSet"
 	case c.AddAddresses != nil:
 		return "addAddresses"
+	case c.AddAssets != nil:
+		return "addAssets"
 	default:
 		return "unknown"
 	}
@@ -67,3 +91,16 @@ func (c *AddAddresses) parseAddresses() error {
 	}
 	return nil
 }
+
+// parseAssetIDs converts the asset IDs to their ID format.
+func (c *AddAssets) parseAssetIDs() error {
+	c.assetIDs = make([]ids.ID, len(c.AssetIDs))
+	for i, assetIDStr := range c.AssetIDs {
+		assetID, err := ids.FromString(assetIDStr)
+		if err != nil {
+			return err
+		}
+		c.assetIDs[i] = assetID
+	}
+	return nil
+}
@@ -4,12 +4,17 @@
 package pubsub
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/units"
 )
@@ -36,17 +41,42 @@ const (
 	// Maximum number of pending messages to send to a peer.
 	maxPendingMessages = 1024 // messages
 
+	// Maximum number of published events kept to be replayed to clients
+	// that reconnect.
+	maxBufferedEvents = 1024 // events
+
+	// Query parameter of the sequence number of the last event a
+	// reconnecting client received.
+	afterParam = "after"
+
+	// Query parameter of the encoding of the messages a client is sent
+	encodingParam = "encoding"
+
 	// MaxBytes the max number of bytes for a filter
 	MaxBytes = 1 * units.MiB
 
 	// MaxAddresses the max number of addresses allowed
 	MaxAddresses = 10000
+
+	// MaxAssets the max number of assets allowed
+	MaxAssets = 1000
 )
 
+// ErrMissedEvents is sent to a reconnecting client when some of the events
+// published after the sequence number it resumes from are no longer buffered
+var ErrMissedEvents = errors.New("events after the requested sequence number are no longer buffered")
+
 type errorMsg struct {
 	Error string `json:"error"`
 }
 
+// event is a published event. Its message is built for each client it's sent
+// to by [parser].
+type event struct {
+	seq    uint64
+	parser Filterer
+}
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  readBufferSize,
 	WriteBufferSize: writeBufferSize,
@@ -61,6 +91,15 @@ type Server struct {
 	conns map[*connection]struct{}
 	// subscribedConnections the connections that have activated subscriptions
 	subscribedConnections *connections
+
+	// eventsLock is held while an event is published, and while a connection
+	// subscribes, so that a reconnecting client receives the events it missed
+	// before the events published after it subscribed.
+	eventsLock sync.Mutex
+	// Sequence number of the last published event
+	lastSeq uint64
+	// The last [maxBufferedEvents] published events, oldest first
+	events []event
 }
 
 func New(networkID uint32, log logging.Logger) *Server {
@@ -72,31 +111,128 @@ func New(networkID uint32, log logging.Logger) *Server {
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	wsConn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		s.log.Debug("Failed to upgrade %s", err)
-		return
-	}
 	conn := &connection{
 		s:      s,
-		conn:   wsConn,
-		send:   make(chan interface{}, maxPendingMessages),
 		fp:     NewFilterParam(),
 		active: 1,
 	}
+	sendQueueSize := maxPendingMessages
+	if after := r.URL.Query().Get(afterParam); after != "" {
+		seq, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s: %s", afterParam, err), http.StatusBadRequest)
+			return
+		}
+		conn.resume = true
+		conn.after = seq
+		// Leave room for the replayed events, and the error sent if some
+		// were missed
+		sendQueueSize += maxBufferedEvents + 1
+	}
+	if encodingStr := r.URL.Query().Get(encodingParam); encodingStr != "" {
+		var encoding formatting.Encoding
+		if err := encoding.UnmarshalJSON([]byte(strconv.Quote(encodingStr))); err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s: %s", encodingParam, err), http.StatusBadRequest)
+			return
+		}
+		conn.encoding = &encoding
+	}
+	conn.send = make(chan interface{}, sendQueueSize)
+
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("Failed to upgrade %s", err)
+		return
+	}
+	conn.conn = wsConn
 	s.addConnection(conn)
 }
 
+// Publish sends the event filtered by [parser] to the subscribed connections
+// it matches. Each event is numbered with the next sequence number, and is
+// buffered so it can be replayed to clients that reconnect.
 func (s *Server) Publish(msg interface{}, parser Filterer) {
-	conns := s.subscribedConnections.Conns()
-	toNotify, msg := parser.Filter(conns)
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	s.lastSeq++
+	e := event{
+		seq:    s.lastSeq,
+		parser: parser,
+	}
+	if len(s.events) == maxBufferedEvents {
+		s.events[0] = event{}
+		s.events = s.events[1:]
+	}
+	s.events = append(s.events, e)
+
+	s.notify(s.subscribedConnections.Conns(), e)
+}
+
+// subscribe [conn] to the published events. If [conn] resumes from a
+// sequence number, the buffered events published after it are sent first.
+func (s *Server) subscribe(conn *connection) {
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	if conn.resume {
+		conn.resume = false
+		s.replay(conn, conn.after)
+	}
+	s.subscribedConnections.Add(conn)
+}
+
+// replay sends to [conn] the buffered events published after [after]
+// Assumes [s.eventsLock] is held
+func (s *Server) replay(conn *connection, after uint64) {
+	// [after] is past the last event if the node restarted since the client
+	// last received an event
+	if after > s.lastSeq || (len(s.events) > 0 && after+1 < s.events[0].seq) {
+		conn.Send(&errorMsg{
+			Error: ErrMissedEvents.Error(),
+		})
+		after = 0
+	}
+	filters := []Filter{conn}
+	for _, e := range s.events {
+		if e.seq <= after {
+			continue
+		}
+		s.notify(filters, e)
+	}
+}
+
+// notify sends [e] to the connections in [conns] it matches. A connection
+// that can't keep up is closed rather than silently missing the event, so its
+// client can reconnect and resume from the last event it received.
+// Assumes [s.eventsLock] is held
+func (s *Server) notify(conns []Filter, e event) {
+	toNotify, msg := e.parser.Filter(conns)
+	formatter, _ := msg.(Formatter)
+	// Messages formatted in each encoding requested so far
+	formatted := make(map[formatting.Encoding]interface{})
 	for i, shouldNotify := range toNotify {
 		if !shouldNotify {
 			continue
 		}
 		conn := conns[i].(*connection)
-		if !conn.Send(msg) {
-			s.log.Verbo("dropping message to subscribed connection due to too many pending messages")
+		connMsg := msg
+		if formatter != nil && conn.encoding != nil {
+			var ok bool
+			connMsg, ok = formatted[*conn.encoding]
+			if !ok {
+				var err error
+				connMsg, err = formatter.Format(*conn.encoding)
+				if err != nil {
+					s.log.Error("couldn't format event %d in %s: %s", e.seq, *conn.encoding, err)
+					connMsg = msg
+				}
+				formatted[*conn.encoding] = connMsg
+			}
+		}
+		if !conn.Send(&Event{Seq: json.Uint64(e.seq), Event: connMsg}) && conn.isActive() {
+			s.log.Verbo("closing subscribed connection due to too many pending messages")
+			conn.close()
 		}
 	}
 }
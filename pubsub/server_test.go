@@ -0,0 +1,131 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// testFilterer matches the connections that have [addr] in their filter
+type testFilterer struct {
+	addr []byte
+	msg  string
+}
+
+func (f *testFilterer) Filter(filters []Filter) ([]bool, interface{}) {
+	resp := make([]bool, len(filters))
+	for i, filter := range filters {
+		resp[i] = filter.Check(f.addr)
+	}
+	return resp, f.msg
+}
+
+func newTestConnection(s *Server, addrs ...[]byte) *connection {
+	conn := &connection{
+		s:      s,
+		send:   make(chan interface{}, maxPendingMessages+maxBufferedEvents+1),
+		fp:     NewFilterParam(),
+		active: 1,
+	}
+	_ = conn.fp.Add(addrs...)
+	return conn
+}
+
+func received(conn *connection) []interface{} {
+	var msgs []interface{}
+	for {
+		select {
+		case msg := <-conn.send:
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestServerPublishSequenceNumbers(t *testing.T) {
+	s := New(0, logging.NoLog{})
+	addr0 := []byte{0}
+	addr1 := []byte{1}
+	conn := newTestConnection(s, addr0)
+	s.subscribe(conn)
+
+	s.Publish(nil, &testFilterer{addr: addr0, msg: "a"})
+	s.Publish(nil, &testFilterer{addr: addr1, msg: "b"})
+	s.Publish(nil, &testFilterer{addr: addr0, msg: "c"})
+
+	// Events that don't match the filter are skipped, but still numbered
+	assert.Equal(t, []interface{}{
+		&Event{Seq: 1, Event: "a"},
+		&Event{Seq: 3, Event: "c"},
+	}, received(conn))
+}
+
+func TestServerReplay(t *testing.T) {
+	s := New(0, logging.NoLog{})
+	addr := []byte{0}
+	for i := 0; i < 3; i++ {
+		s.Publish(nil, &testFilterer{addr: addr, msg: "a"})
+	}
+
+	conn := newTestConnection(s, addr)
+	conn.resume = true
+	conn.after = 1
+	s.subscribe(conn)
+	s.Publish(nil, &testFilterer{addr: addr, msg: "b"})
+
+	assert.Equal(t, []interface{}{
+		&Event{Seq: 2, Event: "a"},
+		&Event{Seq: 3, Event: "a"},
+		&Event{Seq: 4, Event: "b"},
+	}, received(conn))
+
+	// Events are only replayed when the connection first subscribes
+	s.subscribe(conn)
+	assert.Empty(t, received(conn))
+}
+
+func TestServerReplayMissedEvents(t *testing.T) {
+	s := New(0, logging.NoLog{})
+	addr := []byte{0}
+	for i := 0; i < maxBufferedEvents+2; i++ {
+		s.Publish(nil, &testFilterer{addr: addr, msg: "a"})
+	}
+	assert.Len(t, s.events, maxBufferedEvents)
+
+	// The first two events are no longer buffered
+	conn := newTestConnection(s, addr)
+	conn.resume = true
+	conn.after = 1
+	s.subscribe(conn)
+	msgs := received(conn)
+	assert.Len(t, msgs, maxBufferedEvents+1)
+	assert.Equal(t, &errorMsg{Error: ErrMissedEvents.Error()}, msgs[0])
+	assert.Equal(t, &Event{Seq: 3, Event: "a"}, msgs[1])
+
+	// All the events after the second one are buffered
+	conn = newTestConnection(s, addr)
+	conn.resume = true
+	conn.after = 2
+	s.subscribe(conn)
+	msgs = received(conn)
+	assert.Len(t, msgs, maxBufferedEvents)
+	assert.Equal(t, &Event{Seq: 3, Event: "a"}, msgs[0])
+
+	// A sequence number past the last event was given by another run of the
+	// node
+	conn = newTestConnection(s, addr)
+	conn.resume = true
+	conn.after = maxBufferedEvents + 3
+	s.subscribe(conn)
+	msgs = received(conn)
+	assert.Len(t, msgs, maxBufferedEvents+1)
+	assert.Equal(t, &errorMsg{Error: ErrMissedEvents.Error()}, msgs[0])
+	assert.Equal(t, &Event{Seq: json.Uint64(maxBufferedEvents + 2), Event: "a"}, msgs[maxBufferedEvents])
+}
@@ -28,6 +28,11 @@ type Consensus interface {
 	// Returns the parameters that describe this avalanche instance
 	Parameters() Parameters
 
+	// Replaces the parameters of this avalanche instance, including the
+	// parameters of its conflict graph. Only K, Alpha and ConcurrentRepolls
+	// can be changed.
+	UpdateParameters(Parameters) error
+
 	// Returns the number of vertices processing
 	NumProcessing() int
 
@@ -40,6 +45,11 @@ type Consensus interface {
 	// critical error has occurred.
 	Add(Vertex) error
 
+	// Returns true if the MaxProcessing parameter number of vertices, or
+	// transactions, are processing. While saturated, new transactions shouldn't
+	// be issued locally. Vertices from the network are still added.
+	Saturated() bool
+
 	// VertexIssued returns true iff Vertex has been added
 	VertexIssued(Vertex) bool
 
@@ -53,14 +63,35 @@ type Consensus interface {
 	// Returns a set of vertex IDs that were virtuous at the last update.
 	Virtuous() ids.Set
 
+	// Registers a function to call when the set returned by Virtuous changes
+	// while adding a vertex or recording a poll. Functions are called in the
+	// order they were registered. Functions may be registered before the
+	// instance is initialized, and are kept when it's initialized again.
+	OnVirtuousChange(func())
+
 	// Returns a set of vertex IDs that are preferred
 	Preferences() ids.Set
 
+	// TxPollStats returns how the processing transaction with the given ID has
+	// fared in the polls recorded since it was added. Returns false if the
+	// transaction isn't processing.
+	TxPollStats(txID ids.ID) (snowstorm.TxPollStats, bool)
+
+	// StalledTxs returns the IDs of the processing transactions whose
+	// confidence hasn't increased recently. See snowstorm.Consensus.Stalled.
+	StalledTxs() []ids.ID
+
 	// RecordPoll collects the results of a network poll. If a result has not
 	// been added, the result is dropped. Returns if a critical error has
 	// occurred.
 	RecordPoll(ids.UniqueBag) error
 
+	// RecordPolls collects the results of multiple network polls, in the order
+	// that they finished. The votes of each poll are recorded in turn, but the
+	// preferences and frontiers are only updated once, after all of the polls
+	// are recorded. Returns if a critical error has occurred.
+	RecordPolls([]ids.UniqueBag) error
+
 	// Quiesce returns true iff all vertices that have been added but not been accepted or rejected are rogue.
 	// Note, it is possible that after returning quiesce, a new decision may be added such
 	// that this instance should no longer quiesce.
@@ -42,6 +42,9 @@ var Tests = []func(*testing.T, Factory){
 	ErrorOnVtxRejectTest,
 	ErrorOnParentVtxRejectTest,
 	ErrorOnTransitiveVtxRejectTest,
+	SaturatedTest,
+	VirtuousChangeTest,
+	RecordPollsTest,
 }
 
 func ConsensusTest(t *testing.T, factory Factory) {
@@ -2044,3 +2047,314 @@ func ErrorOnTransitiveVtxRejectTest(t *testing.T, factory Factory) {
 		t.Fatalf("Should have errored on vertex rejection")
 	}
 }
+
+func SaturatedTest(t *testing.T, factory Factory) {
+	avl := factory.New()
+
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:               prometheus.NewRegistry(),
+			K:                     1,
+			Alpha:                 1,
+			BetaVirtuous:          1,
+			BetaRogue:             2,
+			ConcurrentRepolls:     1,
+			OptimalProcessing:     1,
+			MaxOutstandingItems:   1,
+			MaxItemProcessingTime: 1,
+			MaxProcessing:         2,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	vts := []Vertex{&TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}}
+
+	if err := avl.Initialize(snow.DefaultContextTest(), params, vts); err != nil {
+		t.Fatal(err)
+	}
+
+	newTx := func() *snowstorm.TestTx {
+		return &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{ids.GenerateTestID()},
+		}
+	}
+	tx0 := newTx()
+	tx1 := newTx()
+	tx2 := newTx()
+
+	vtx0 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0, tx1},
+	}
+	vtx1 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx2},
+	}
+	vtx2 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+
+	if avl.Saturated() {
+		t.Fatalf("An empty avalanche instance is saturated")
+	}
+	if err := avl.Add(vtx0); err != nil {
+		t.Fatal(err)
+	}
+	if !avl.Saturated() {
+		t.Fatalf("Should be saturated with %d processing txs", params.MaxProcessing)
+	}
+
+	// Saturation only holds back locally issued txs, so vertices are still
+	// added
+	if err := avl.Add(vtx1); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case !avl.VertexIssued(vtx1):
+		t.Fatalf("Vertex should have been added")
+	case !avl.TxIssued(tx2):
+		t.Fatalf("Tx should have been added")
+	case !avl.Saturated():
+		t.Fatalf("Should still be saturated")
+	}
+
+	if err := avl.Add(vtx2); err != nil {
+		t.Fatal(err)
+	}
+	if !avl.VertexIssued(vtx2) {
+		t.Fatalf("Vertex should have been added")
+	}
+}
+
+func VirtuousChangeTest(t *testing.T, factory Factory) {
+	avl := factory.New()
+
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:               prometheus.NewRegistry(),
+			K:                     1,
+			Alpha:                 1,
+			BetaVirtuous:          1,
+			BetaRogue:             2,
+			ConcurrentRepolls:     1,
+			OptimalProcessing:     1,
+			MaxOutstandingItems:   1,
+			MaxItemProcessingTime: 1,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	vts := []Vertex{&TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}}
+	utxos := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+
+	// Functions may be registered before the instance is initialized
+	numChanges := 0
+	avl.OnVirtuousChange(func() { numChanges++ })
+
+	if err := avl.Initialize(snow.DefaultContextTest(), params, vts); err != nil {
+		t.Fatal(err)
+	}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, utxos[0])
+
+	tx1 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx1.InputIDsV = append(tx1.InputIDsV, utxos[0])
+
+	tx2 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx2.InputIDsV = append(tx2.InputIDsV, utxos[1])
+
+	vtx0 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+	vtx1 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx1},
+	}
+	vtx2 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []Vertex{vtx0},
+		HeightV:  2,
+		TxsV:     []snowstorm.Tx{tx2},
+	}
+
+	// The virtuous vertex replaces its parent in the virtuous frontier
+	if err := avl.Add(vtx0); err != nil {
+		t.Fatal(err)
+	} else if numChanges != 1 {
+		t.Fatalf("Should have notified of %d changes but notified of %d", 1, numChanges)
+	} else if !ids.UnsortedEquals([]ids.ID{vtx0.ID()}, avl.Virtuous().List()) {
+		t.Fatalf("Wrong virtuous frontier")
+	}
+
+	// A rogue vertex isn't part of the virtuous frontier
+	if err := avl.Add(vtx1); err != nil {
+		t.Fatal(err)
+	} else if numChanges != 1 {
+		t.Fatalf("Should have notified of %d changes but notified of %d", 1, numChanges)
+	}
+
+	if err := avl.Add(vtx2); err != nil {
+		t.Fatal(err)
+	} else if numChanges != 2 {
+		t.Fatalf("Should have notified of %d changes but notified of %d", 2, numChanges)
+	} else if !ids.UnsortedEquals([]ids.ID{vtx2.ID()}, avl.Virtuous().List()) {
+		t.Fatalf("Wrong virtuous frontier")
+	}
+
+	// Registered functions are kept when the instance is initialized again
+	if err := avl.Initialize(snow.DefaultContextTest(), params, vts); err != nil {
+		t.Fatal(err)
+	}
+	if err := avl.Add(vtx0); err != nil {
+		t.Fatal(err)
+	} else if numChanges != 3 {
+		t.Fatalf("Should have notified of %d changes but notified of %d", 3, numChanges)
+	}
+}
+
+func RecordPollsTest(t *testing.T, factory Factory) {
+	avl := factory.New()
+
+	params := Parameters{
+		Parameters: snowball.Parameters{
+			Metrics:               prometheus.NewRegistry(),
+			K:                     1,
+			Alpha:                 1,
+			BetaVirtuous:          2,
+			BetaRogue:             3,
+			ConcurrentRepolls:     1,
+			OptimalProcessing:     1,
+			MaxOutstandingItems:   1,
+			MaxItemProcessingTime: 1,
+		},
+		Parents:   2,
+		BatchSize: 1,
+	}
+	vts := []Vertex{&TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}}
+	utxos := []ids.ID{ids.GenerateTestID()}
+
+	if err := avl.Initialize(snow.DefaultContextTest(), params, vts); err != nil {
+		t.Fatal(err)
+	}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, utxos[0])
+
+	tx1 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx1.InputIDsV = append(tx1.InputIDsV, utxos[0])
+
+	vtx0 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+	vtx1 := &TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx1},
+	}
+
+	if err := avl.Add(vtx0); err != nil {
+		t.Fatal(err)
+	}
+	if err := avl.Add(vtx1); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := ids.UniqueBag{}
+	sm.Add(0, vtx1.IDV)
+
+	// The frontier is updated once after both polls are recorded
+	if err := avl.RecordPolls([]ids.UniqueBag{sm, sm}); err != nil {
+		t.Fatal(err)
+	}
+	if prefs := avl.Preferences(); prefs.Len() != 1 || !prefs.Contains(vtx1.IDV) {
+		t.Fatalf("Wrong preference. Expected %s got %s", vtx1.IDV, prefs.List())
+	}
+	if tx1.Status() != choices.Processing {
+		t.Fatalf("Tx should still be processing as it is rogue")
+	}
+
+	if err := avl.RecordPolls([]ids.UniqueBag{sm}); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case tx0.Status() != choices.Rejected:
+		t.Fatalf("Tx should have been rejected")
+	case tx1.Status() != choices.Accepted:
+		t.Fatalf("Tx should have been accepted")
+	case vtx0.Status() != choices.Rejected:
+		t.Fatalf("Vertex should have been rejected")
+	case vtx1.Status() != choices.Accepted:
+		t.Fatalf("Vertex should have been accepted")
+	case !avl.Finalized():
+		t.Fatalf("An avalanche instance finalized too late")
+	}
+}
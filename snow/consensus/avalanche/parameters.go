@@ -4,16 +4,26 @@
 package avalanche
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
 
+var errFixedParameters = errors.New("only k, alpha and concurrentRepolls can be updated")
+
 // Parameters the avalanche parameters include the snowball parameters and the
 // optimal number of parents
 type Parameters struct {
 	snowball.Parameters
 	Parents, BatchSize int
+
+	// MaxBatchSize is the max number of txs put into a vertex while there is a
+	// backlog of txs to issue. The number of txs put into a vertex grows from
+	// BatchSize to MaxBatchSize while there is a backlog, and shrinks back to
+	// BatchSize while consensus is quiescent. If 0, vertices are issued with at
+	// most BatchSize txs.
+	MaxBatchSize int
 }
 
 // Valid returns nil if the parameters describe a valid initialization.
@@ -23,7 +33,21 @@ func (p Parameters) Valid() error {
 		return fmt.Errorf("parents = %d: Fails the condition that: 1 < Parents", p.Parents)
 	case p.BatchSize <= 0:
 		return fmt.Errorf("batchSize = %d: Fails the condition that: 0 < BatchSize", p.BatchSize)
+	case p.MaxBatchSize != 0 && p.MaxBatchSize < p.BatchSize:
+		return fmt.Errorf("batchSize = %d, maxBatchSize = %d: Fails the condition that: BatchSize <= MaxBatchSize", p.BatchSize, p.MaxBatchSize)
 	default:
 		return p.Parameters.Verify()
 	}
 }
+
+// VerifyUpdate returns nil if [update] describes a valid initialization and
+// only changes the parameters that can be changed while consensus is running
+func (p Parameters) VerifyUpdate(update Parameters) error {
+	if p.Parents != update.Parents || p.BatchSize != update.BatchSize || p.MaxBatchSize != update.MaxBatchSize {
+		return errFixedParameters
+	}
+	if err := p.Parameters.VerifyUpdate(update.Parameters); err != nil {
+		return err
+	}
+	return update.Valid()
+}
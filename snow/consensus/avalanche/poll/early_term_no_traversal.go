@@ -52,6 +52,9 @@ func (p *earlyTermNoTraversalPoll) Vote(vdr ids.ShortID, votes []ids.ID) {
 	}
 }
 
+// Pending returns the validators the poll is still waiting on
+func (p *earlyTermNoTraversalPoll) Pending() []ids.ShortID { return p.polled.List() }
+
 // Finished returns true when all validators have voted
 func (p *earlyTermNoTraversalPoll) Finished() bool {
 	// If there are no outstanding queries, the poll is finished
@@ -43,6 +43,9 @@ func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, votes []ids.ID) {
 	}
 }
 
+// Pending returns the validators the poll is still waiting on
+func (p *noEarlyTermPoll) Pending() []ids.ShortID { return p.polled.List() }
+
 // Finished returns true when all validators have voted
 func (p *noEarlyTermPoll) Finished() bool { return p.polled.Len() == 0 }
 
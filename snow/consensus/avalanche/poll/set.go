@@ -22,15 +22,25 @@ var (
 
 type poll struct {
 	Poll
-	start time.Time
+	containerID ids.ID
+	vdrs        []ids.ShortID
+	numChits    int
+	start       time.Time
 }
 
+// responseRateBuckets buckets the fraction of queried validators that
+// responded to a poll
+var responseRateBuckets = []float64{.1, .2, .3, .4, .5, .6, .7, .8, .9, 1}
+
 type set struct {
-	log      logging.Logger
-	numPolls prometheus.Gauge
-	durPolls prometheus.Histogram
-	factory  Factory
-	polls    map[uint32]poll
+	log               logging.Logger
+	numPolls          prometheus.Gauge
+	durPolls          prometheus.Histogram
+	responseRate      prometheus.Histogram
+	numAbandonedPolls prometheus.Counter
+	queryFailures     *prometheus.CounterVec
+	factory           Factory
+	polls             map[uint32]*poll
 }
 
 // NewSet returns a new empty set of polls
@@ -59,19 +69,50 @@ func NewSet(
 		log.Error("failed to register poll_duration statistics due to %s", err)
 	}
 
+	responseRate := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "poll_response_rate",
+		Help:      "Fraction of the queried validators that responded with chits before the poll finished",
+		Buckets:   responseRateBuckets,
+	})
+	if err := registerer.Register(responseRate); err != nil {
+		log.Error("failed to register poll_response_rate statistics due to %s", err)
+	}
+
+	numAbandonedPolls := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "polls_abandoned",
+		Help:      "Number of polls that finished without any queried validator responding with chits",
+	})
+	if err := registerer.Register(numAbandonedPolls); err != nil {
+		log.Error("failed to register polls_abandoned statistics due to %s", err)
+	}
+
+	queryFailures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "poll_query_failures",
+		Help:      "Number of queries that failed or timed out, by validator",
+	}, []string{"validator"})
+	if err := registerer.Register(queryFailures); err != nil {
+		log.Error("failed to register poll_query_failures statistics due to %s", err)
+	}
+
 	return &set{
-		log:      log,
-		numPolls: numPolls,
-		durPolls: durPolls,
-		factory:  factory,
-		polls:    make(map[uint32]poll),
+		log:               log,
+		numPolls:          numPolls,
+		durPolls:          durPolls,
+		responseRate:      responseRate,
+		numAbandonedPolls: numAbandonedPolls,
+		queryFailures:     queryFailures,
+		factory:           factory,
+		polls:             make(map[uint32]*poll),
 	}
 }
 
 // Add to the current set of polls
 // Returns true if the poll was registered correctly and the network sample
 //         should be made.
-func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
+func (s *set) Add(requestID uint32, containerID ids.ID, vdrs ids.ShortBag) bool {
 	if _, exists := s.polls[requestID]; exists {
 		s.log.Debug("dropping poll due to duplicated requestID: %d", requestID)
 		return false
@@ -81,9 +122,11 @@ func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
 		requestID,
 		&vdrs)
 
-	s.polls[requestID] = poll{
-		Poll:  s.factory.New(vdrs), // create the new poll
-		start: time.Now(),
+	s.polls[requestID] = &poll{
+		containerID: containerID,
+		vdrs:        vdrs.List(),
+		Poll:        s.factory.New(vdrs), // create the new poll
+		start:       time.Now(),
 	}
 	s.numPolls.Inc() // increase the metrics
 	return true
@@ -109,7 +152,16 @@ func (s *set) Vote(
 		requestID,
 		votes)
 
+	numPending := len(poll.Pending())
 	poll.Vote(vdr, votes)
+	// A failed query is registered as a nil vote
+	if len(poll.Pending()) < numPending {
+		if votes == nil {
+			s.queryFailures.WithLabelValues(vdr.String()).Inc()
+		} else {
+			poll.numChits++
+		}
+	}
 	if !poll.Finished() {
 		return nil, false
 	}
@@ -118,6 +170,12 @@ func (s *set) Vote(
 
 	delete(s.polls, requestID) // remove the poll from the current set
 	s.durPolls.Observe(float64(time.Since(poll.start).Milliseconds()))
+	if len(poll.vdrs) > 0 {
+		s.responseRate.Observe(float64(poll.numChits) / float64(len(poll.vdrs)))
+	}
+	if poll.numChits == 0 {
+		s.numAbandonedPolls.Inc()
+	}
 	s.numPolls.Dec() // decrease the metrics
 	return poll.Result(), true
 }
@@ -125,6 +183,25 @@ func (s *set) Vote(
 // Len returns the number of outstanding polls
 func (s *set) Len() int { return len(s.polls) }
 
+// SetFactory replaces the factory that creates new polls
+func (s *set) SetFactory(factory Factory) { s.factory = factory }
+
+// Polls returns a description of the outstanding polls
+func (s *set) Polls() []Info {
+	infos := make([]Info, 0, len(s.polls))
+	for requestID, poll := range s.polls {
+		infos = append(infos, Info{
+			RequestID:   requestID,
+			ContainerID: poll.containerID,
+			Validators:  poll.vdrs,
+			Pending:     poll.Pending(),
+			NumChits:    poll.numChits,
+			Start:       poll.start,
+		})
+	}
+	return infos
+}
+
 func (s *set) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("current polls: (Size = %d)", len(s.polls)))
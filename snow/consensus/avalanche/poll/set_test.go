@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -58,11 +59,11 @@ func TestCreateAndFinishPoll(t *testing.T) {
 
 	if s.Len() != 0 {
 		t.Fatalf("Shouldn't have any active polls yet")
-	} else if !s.Add(0, vdrs) {
+	} else if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
-	} else if s.Add(0, vdrs) {
+	} else if s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Shouldn't have been able to add a duplicated poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
@@ -98,7 +99,7 @@ func TestSetString(t *testing.T) {
 	expected := "current polls: (Size = 1)\n" +
 		"    0: waiting on Bag: (Size = 1)\n" +
 		"        ID[6HgC8KRBEhXYbF4riJyJFLSHt37UNuRt]: Count = 1"
-	if !s.Add(0, vdrs) {
+	if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if str := s.String(); expected != str {
 		t.Fatalf("Set return wrong string, Expected:\n%s\nReturned:\n%s",
@@ -106,3 +107,96 @@ func TestSetString(t *testing.T) {
 			str)
 	}
 }
+
+func TestSetPolls(t *testing.T) {
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vtxID := ids.ID{1}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2} // k = 2
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+	)
+
+	if !s.Add(0, vtxID, vdrs) {
+		t.Fatalf("Should have been able to add a new poll")
+	} else if _, finished := s.Vote(0, vdr1, []ids.ID{vtxID}); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	}
+
+	polls := s.Polls()
+	if len(polls) != 1 {
+		t.Fatalf("Should only have one active poll")
+	}
+	poll := polls[0]
+	switch {
+	case poll.RequestID != 0:
+		t.Fatalf("Wrong requestID returned")
+	case poll.ContainerID != vtxID:
+		t.Fatalf("Wrong vertex returned")
+	case len(poll.Validators) != 2:
+		t.Fatalf("Wrong number of validators returned")
+	case len(poll.Pending) != 1 || poll.Pending[0] != vdr2:
+		t.Fatalf("Wrong pending validators returned")
+	case poll.NumChits != 1:
+		t.Fatalf("Wrong number of chits returned")
+	}
+}
+
+func TestSetMetrics(t *testing.T) {
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vtxID := ids.ID{1}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2} // k = 2
+
+	vdrs0 := ids.ShortBag{}
+	vdrs0.Add(
+		vdr1,
+		vdr2,
+	)
+	vdrs1 := ids.ShortBag{}
+	vdrs1.Add(
+		vdr1,
+		vdr2,
+	)
+
+	// The first poll gets one response, the second poll gets none
+	if !s.Add(0, vtxID, vdrs0) {
+		t.Fatalf("Should have been able to add a new poll")
+	} else if _, finished := s.Vote(0, vdr1, []ids.ID{vtxID}); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	} else if _, finished := s.Vote(0, vdr2, nil); !finished {
+		t.Fatalf("Should have finished the poll")
+	} else if !s.Add(1, vtxID, vdrs1) {
+		t.Fatalf("Should have been able to add a new poll")
+	} else if _, finished := s.Vote(1, vdr1, nil); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	} else if _, finished := s.Vote(1, vdr2, nil); !finished {
+		t.Fatalf("Should have finished the poll")
+	}
+
+	metrics := s.(*set)
+	if abandoned := testutil.ToFloat64(metrics.numAbandonedPolls); abandoned != 1 {
+		t.Fatalf("Should have abandoned one poll, abandoned %v", abandoned)
+	}
+	if failures := testutil.ToFloat64(metrics.queryFailures.WithLabelValues(vdr1.String())); failures != 1 {
+		t.Fatalf("Wrong number of query failures for %s: %v", vdr1, failures)
+	}
+	if failures := testutil.ToFloat64(metrics.queryFailures.WithLabelValues(vdr2.String())); failures != 2 {
+		t.Fatalf("Wrong number of query failures for %s: %v", vdr2, failures)
+	}
+}
@@ -25,10 +25,16 @@ var (
 var _ Consensus = &Topological{}
 
 // TopologicalFactory implements Factory by returning a topological struct
-type TopologicalFactory struct{}
+type TopologicalFactory struct {
+	// ConflictFactory creates the conflict graph used by the returned
+	// instances. If nil, snowstorm.DirectedFactory is used.
+	ConflictFactory snowstorm.Factory
+}
 
 // New implements Factory
-func (TopologicalFactory) New() Consensus { return &Topological{} }
+func (f TopologicalFactory) New() Consensus {
+	return &Topological{ConflictFactory: f.ConflictFactory}
+}
 
 // TODO: Implement pruning of decisions.
 // To perfectly preserve the protocol, this implementation will need to store
@@ -42,6 +48,10 @@ func (TopologicalFactory) New() Consensus { return &Topological{} }
 type Topological struct {
 	metrics.Metrics
 
+	// ConflictFactory creates the conflict graph that tracks the conflict
+	// relations. If nil, snowstorm.DirectedFactory is used.
+	ConflictFactory snowstorm.Factory
+
 	// Context used for logging
 	ctx *snow.Context
 	// Threshold for confidence increases
@@ -62,6 +72,10 @@ type Topological struct {
 	// virtuousCache is the cache for strongly virtuous checks
 	preferenceCache, virtuousCache map[ids.ID]bool
 
+	// called, in the order they were registered, when the virtuous frontier
+	// changes. Not reset by Initialize.
+	onVirtuousChange []func()
+
 	// Used in [calculateInDegree] and [markAncestorInDegrees].
 	// Should only be accessed in those methods.
 	// We use this one instance of ids.Set instead of creating a
@@ -95,19 +109,29 @@ func (ta *Topological) Initialize(
 		return err
 	}
 
+	if ta.ctx == nil {
+		if err := ta.Metrics.Initialize("vtx", "vertex/vertices", ctx.Log, params.Namespace, params.Metrics); err != nil {
+			return err
+		}
+
+		conflictFactory := ta.ConflictFactory
+		if conflictFactory == nil {
+			conflictFactory = snowstorm.DirectedFactory{}
+		}
+		ta.cg = conflictFactory.New()
+	} else {
+		// This instance is being reset, so the metrics that were registered
+		// when it was first initialized are reused, and the conflict graph
+		// resets itself
+		ta.Metrics.Reset()
+	}
+
 	ta.ctx = ctx
 	ta.params = params
 	ta.leaves = ids.Set{}
 	ta.votes = ids.UniqueBag{}
 	ta.kahnNodes = make(map[ids.ID]kahnNode)
-
-	if err := ta.Metrics.Initialize("vtx", "vertex/vertices", ctx.Log, params.Namespace, params.Metrics); err != nil {
-		return err
-	}
-
 	ta.nodes = make(map[ids.ID]Vertex, minMapSize)
-
-	ta.cg = &snowstorm.Directed{}
 	if err := ta.cg.Initialize(ctx, params.Parameters); err != nil {
 		return err
 	}
@@ -125,6 +149,18 @@ func (ta *Topological) NumProcessing() int { return len(ta.nodes) }
 // Parameters implements the Avalanche interface
 func (ta *Topological) Parameters() Parameters { return ta.params }
 
+// UpdateParameters implements the Avalanche interface
+func (ta *Topological) UpdateParameters(params Parameters) error {
+	if err := ta.params.VerifyUpdate(params); err != nil {
+		return err
+	}
+	if err := ta.cg.UpdateParameters(params.Parameters); err != nil {
+		return err
+	}
+	ta.params = params
+	return nil
+}
+
 // IsVirtuous implements the Avalanche interface
 func (ta *Topological) IsVirtuous(tx snowstorm.Tx) bool { return ta.cg.IsVirtuous(tx) }
 
@@ -159,7 +195,18 @@ func (ta *Topological) Add(vtx Vertex) error {
 	ta.nodes[vtxID] = vtx // Add this vertex to the set of nodes
 	ta.Metrics.Issued(vtxID)
 
-	return ta.update(vtx) // Update the vertex and it's ancestry
+	virtuous := ta.virtuousSnapshot()
+	if err := ta.update(vtx); err != nil { // Update the vertex and it's ancestry
+		return err
+	}
+	ta.notifyVirtuousChange(virtuous)
+	return nil
+}
+
+// Saturated implements the Avalanche interface
+func (ta *Topological) Saturated() bool {
+	return ta.params.MaxProcessing > 0 &&
+		(len(ta.nodes) >= ta.params.MaxProcessing || ta.cg.NumProcessing() >= ta.params.MaxProcessing)
 }
 
 // VertexIssued implements the Avalanche interface
@@ -183,42 +230,89 @@ func (ta *Topological) Virtuous() ids.Set { return ta.virtuous }
 // Preferences implements the Avalanche interface
 func (ta *Topological) Preferences() ids.Set { return ta.preferred }
 
+// TxPollStats implements the Avalanche interface
+func (ta *Topological) TxPollStats(txID ids.ID) (snowstorm.TxPollStats, bool) {
+	return ta.cg.PollStats(txID)
+}
+
+// StalledTxs implements the Avalanche interface
+func (ta *Topological) StalledTxs() []ids.ID { return ta.cg.Stalled() }
+
 // RecordPoll implements the Avalanche interface
 func (ta *Topological) RecordPoll(responses ids.UniqueBag) error {
-	// If it isn't possible to have alpha votes for any transaction, then we can
-	// just reset the confidence values in the conflict graph and not perform
-	// any traversals.
-	partialVotes := ids.BitSet(0)
-	for vote := range responses {
-		votes := responses.GetSet(vote)
-		partialVotes.Union(votes)
-		if partialVotes.Len() >= ta.params.Alpha {
-			break
+	return ta.RecordPolls([]ids.UniqueBag{responses})
+}
+
+// RecordPolls implements the Avalanche interface
+func (ta *Topological) RecordPolls(polls []ids.UniqueBag) error {
+	txVotes := make([]ids.Bag, len(polls))
+	for i, responses := range polls {
+		// If it isn't possible to have alpha votes for any transaction, then
+		// the poll only resets the confidence values in the conflict graph, so
+		// the traversals can be skipped.
+		partialVotes := ids.BitSet(0)
+		for vote := range responses {
+			votes := responses.GetSet(vote)
+			partialVotes.Union(votes)
+			if partialVotes.Len() >= ta.params.Alpha {
+				break
+			}
+		}
+		if partialVotes.Len() < ta.params.Alpha {
+			continue
 		}
-	}
-	if partialVotes.Len() < ta.params.Alpha {
-		// Skip the traversals.
-		_, err := ta.cg.RecordPoll(ids.Bag{})
-		return err
-	}
 
-	// Set up the topological sort: O(|Live Set|)
-	if err := ta.calculateInDegree(responses); err != nil {
-		return err
-	}
-	// Collect the votes for each transaction: O(|Live Set|)
-	votes, err := ta.pushVotes()
-	if err != nil {
-		return err
+		// Set up the topological sort: O(|Live Set|)
+		if err := ta.calculateInDegree(responses); err != nil {
+			return err
+		}
+		// Collect the votes for each transaction: O(|Live Set|)
+		votes, err := ta.pushVotes()
+		if err != nil {
+			return err
+		}
+		txVotes[i] = votes
 	}
 	// Update the conflict graph: O(|Transactions|)
-	if updated, err := ta.cg.RecordPoll(votes); !updated || err != nil {
+	if updated, err := ta.cg.RecordPolls(txVotes); !updated || err != nil {
 		// If the transaction statuses weren't changed, there is no need to
 		// perform a traversal.
 		return err
 	}
-	// Update the dag: O(|Live Set|)
-	return ta.updateFrontiers()
+	// Update the dag once for all of the polls: O(|Live Set|)
+	virtuous := ta.virtuousSnapshot()
+	if err := ta.updateFrontiers(); err != nil {
+		return err
+	}
+	ta.notifyVirtuousChange(virtuous)
+	return nil
+}
+
+// OnVirtuousChange implements the Avalanche interface
+func (ta *Topological) OnVirtuousChange(f func()) {
+	ta.onVirtuousChange = append(ta.onVirtuousChange, f)
+}
+
+// virtuousSnapshot returns a copy of the virtuous frontier, if anything is
+// notified when it changes
+func (ta *Topological) virtuousSnapshot() ids.Set {
+	if len(ta.onVirtuousChange) == 0 {
+		return nil
+	}
+	virtuous := ids.NewSet(ta.virtuous.Len())
+	virtuous.Union(ta.virtuous)
+	return virtuous
+}
+
+// notifyVirtuousChange notifies the registered functions if the virtuous
+// frontier isn't [before]
+func (ta *Topological) notifyVirtuousChange(before ids.Set) {
+	if len(ta.onVirtuousChange) == 0 || before.Equals(ta.virtuous) {
+		return
+	}
+	for _, f := range ta.onVirtuousChange {
+		f()
+	}
 }
 
 // Quiesce implements the Avalanche interface
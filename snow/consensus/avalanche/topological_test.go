@@ -5,6 +5,12 @@ package avalanche
 
 import (
 	"testing"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 )
 
 func TestTopological(t *testing.T) { ConsensusTest(t, TopologicalFactory{}) }
+
+func TestTopologicalInput(t *testing.T) {
+	ConsensusTest(t, TopologicalFactory{ConflictFactory: snowstorm.InputFactory{}})
+}
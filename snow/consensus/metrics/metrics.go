@@ -116,6 +116,13 @@ func (m *Metrics) Rejected(id ids.ID) {
 	m.numProcessing.Dec()
 }
 
+// Reset stops tracking the processing items. Should be called when the items
+// will never be decided, such as when the consensus instance is discarded.
+func (m *Metrics) Reset() {
+	m.numProcessing.Sub(float64(m.processingEntries.Len()))
+	m.processingEntries = linkedhashmap.New()
+}
+
 func (m *Metrics) MeasureAndGetOldestDuration() time.Duration {
 	now := m.Clock.Time()
 	oldestTimeIntf, exists := m.processingEntries.Oldest()
@@ -0,0 +1,122 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+// Default number of polls each node records before the simulation gives up
+const defaultMaxPolls = 1000
+
+var (
+	errNoEngine       = errors.New("no consensus engine was provided")
+	errNoLatency      = errors.New("no latency distribution was provided")
+	errTooFewNodes    = errors.New("the network must contain at least K nodes")
+	errNoTxs          = errors.New("at least one tx must be issued")
+	errInvalidRate    = errors.New("rates must be in [0, 1]")
+	errNegativePolls  = errors.New("max polls can't be negative")
+	errNegativeDelays = errors.New("latency can't be negative")
+)
+
+// Config describes a simulation
+type Config struct {
+	// Parameters that every node runs consensus with. The Metrics field is
+	// ignored, every node registers its metrics in its own registry.
+	Parameters sbcon.Parameters
+
+	// Engine is the consensus implementation that every node runs
+	Engine Engine
+
+	// NumNodes is the number of nodes in the network
+	NumNodes int
+
+	// NumTxs is the number of txs that every node issues
+	NumTxs int
+
+	// ConflictRate is the probability that a tx spends the input of an earlier
+	// tx, rather than a new input
+	ConflictRate float64
+
+	// VoteNoise is the probability that a peer responds to a query with a vote
+	// for a random tx, rather than with its preferences
+	VoteNoise float64
+
+	// Latency is the distribution of the time it takes a peer to respond to a
+	// query. A node records a poll once every sampled peer has responded.
+	Latency Latency
+
+	// MaxPolls is the number of polls each node records before it gives up on
+	// finalizing. If 0, defaults to 1000.
+	MaxPolls int
+
+	// Seed of the randomness used to generate the txs, sample peers, sample
+	// latencies, and add noise to votes
+	Seed int64
+}
+
+// Valid returns nil if the config describes a valid simulation
+func (c *Config) Valid() error {
+	switch {
+	case c.Engine == nil:
+		return errNoEngine
+	case c.Latency == nil:
+		return errNoLatency
+	case c.NumNodes < c.Parameters.K:
+		return fmt.Errorf("%w: numNodes = %d, k = %d", errTooFewNodes, c.NumNodes, c.Parameters.K)
+	case c.NumTxs <= 0:
+		return errNoTxs
+	case c.ConflictRate < 0 || c.ConflictRate > 1:
+		return fmt.Errorf("%w: conflictRate = %f", errInvalidRate, c.ConflictRate)
+	case c.VoteNoise < 0 || c.VoteNoise > 1:
+		return fmt.Errorf("%w: voteNoise = %f", errInvalidRate, c.VoteNoise)
+	case c.MaxPolls < 0:
+		return errNegativePolls
+	default:
+		return nil
+	}
+}
+
+// Result is the outcome of a simulation
+type Result struct {
+	// Finalized is the number of nodes that decided every tx
+	Finalized int
+
+	// FinalityTimes are the times that the nodes that decided every tx did so
+	// at, in increasing order
+	FinalityTimes []time.Duration
+
+	// Polls is the number of polls that were recorded across all the nodes
+	Polls int
+
+	// SafetyViolations is the number of txs that were accepted by some nodes
+	// and rejected by others
+	SafetyViolations int
+}
+
+// MeanFinalityTime returns the average time it took a node to decide every
+// tx, of the nodes that did so. Returns 0 if none did.
+func (r *Result) MeanFinalityTime() time.Duration {
+	if len(r.FinalityTimes) == 0 {
+		return 0
+	}
+	total := time.Duration(0)
+	for _, t := range r.FinalityTimes {
+		total += t
+	}
+	return total / time.Duration(len(r.FinalityTimes))
+}
+
+// MaxFinalityTime returns the time it took the slowest node to decide every
+// tx, of the nodes that did so. Returns 0 if none did.
+func (r *Result) MaxFinalityTime() time.Duration {
+	if len(r.FinalityTimes) == 0 {
+		return 0
+	}
+	return r.FinalityTimes[len(r.FinalityTimes)-1]
+}
@@ -0,0 +1,196 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+// Parameters that are used by Avalanche if they aren't provided
+const (
+	defaultParents   = 2
+	defaultBatchSize = 1
+)
+
+var (
+	// The accepted vertex that every vertex issued in an Avalanche simulation
+	// builds on
+	genesisVtxID = ids.Empty.Prefix(2)
+
+	_ Engine = &Snowstorm{}
+	_ Engine = &Avalanche{}
+)
+
+// Engine creates the consensus instances that the simulated nodes run
+type Engine interface {
+	// newNode returns a node that has issued every tx in [txs], in order.
+	// [txs] must only be used by the returned node.
+	newNode(params sbcon.Parameters, txs []*snowstorm.TestTx) (node, error)
+}
+
+// node is a consensus instance run by a simulated node
+type node interface {
+	// preferences returns what this node votes for when it is queried
+	preferences() []ids.ID
+
+	// voteID returns the ID that a vote for the [i]th tx is made with
+	voteID(i int) ids.ID
+
+	// recordPoll records the [responses] of the sampled peers
+	recordPoll(responses [][]ids.ID) error
+
+	// finalized returns true if every tx has been decided
+	finalized() bool
+}
+
+// Snowstorm runs snowstorm consensus on the txs directly
+type Snowstorm struct {
+	// Factory of the conflict graphs. If nil, snowstorm.DirectedFactory is
+	// used.
+	Factory snowstorm.Factory
+}
+
+func (s *Snowstorm) newNode(params sbcon.Parameters, txs []*snowstorm.TestTx) (node, error) {
+	factory := s.Factory
+	if factory == nil {
+		factory = snowstorm.DirectedFactory{}
+	}
+
+	n := &snowstormNode{
+		con: factory.New(),
+		txs: txs,
+	}
+	params.Metrics = prometheus.NewRegistry()
+	if err := n.con.Initialize(snow.DefaultContextTest(), params); err != nil {
+		return nil, err
+	}
+	n.con.OnAccept(func(tx snowstorm.Tx) {
+		n.accepted = append(n.accepted, tx.ID())
+	})
+	for _, tx := range txs {
+		if err := n.con.Add(tx); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+type snowstormNode struct {
+	con      snowstorm.Consensus
+	txs      []*snowstorm.TestTx
+	accepted []ids.ID
+}
+
+func (n *snowstormNode) preferences() []ids.ID {
+	return append(n.con.Preferences().List(), n.accepted...)
+}
+
+func (n *snowstormNode) voteID(i int) ids.ID { return n.txs[i].ID() }
+
+func (n *snowstormNode) recordPoll(responses [][]ids.ID) error {
+	votes := ids.Bag{}
+	for _, response := range responses {
+		votes.Add(response...)
+	}
+	_, err := n.con.RecordPoll(votes)
+	return err
+}
+
+func (n *snowstormNode) finalized() bool { return n.con.Finalized() }
+
+// Avalanche runs avalanche consensus, where every tx is issued in its own
+// vertex
+type Avalanche struct {
+	// Factory of the DAGs. If nil, avalanche.TopologicalFactory is used.
+	Factory avalanche.Factory
+
+	// Parents and BatchSize of the avalanche parameters. If 0, they default
+	// to 2 and 1.
+	Parents, BatchSize int
+}
+
+func (a *Avalanche) newNode(params sbcon.Parameters, txs []*snowstorm.TestTx) (node, error) {
+	factory := a.Factory
+	if factory == nil {
+		factory = avalanche.TopologicalFactory{}
+	}
+	avaParams := avalanche.Parameters{
+		Parameters: params,
+		Parents:    a.Parents,
+		BatchSize:  a.BatchSize,
+	}
+	if avaParams.Parents == 0 {
+		avaParams.Parents = defaultParents
+	}
+	if avaParams.BatchSize == 0 {
+		avaParams.BatchSize = defaultBatchSize
+	}
+	avaParams.Metrics = prometheus.NewRegistry()
+
+	genesis := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     genesisVtxID,
+		StatusV: choices.Accepted,
+	}}
+	n := &avalancheNode{
+		con:  factory.New(),
+		vtxs: make([]*avalanche.TestVertex, len(txs)),
+	}
+	if err := n.con.Initialize(snow.DefaultContextTest(), avaParams, []avalanche.Vertex{genesis}); err != nil {
+		return nil, err
+	}
+	for i, tx := range txs {
+		vtx := &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     vtxID(tx.ID()),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{genesis},
+			HeightV:  1,
+			TxsV:     []snowstorm.Tx{tx},
+		}
+		n.vtxs[i] = vtx
+		if err := n.con.Add(vtx); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+type avalancheNode struct {
+	con  avalanche.Consensus
+	vtxs []*avalanche.TestVertex
+}
+
+func (n *avalancheNode) preferences() []ids.ID {
+	prefs := n.con.Preferences().List()
+	for _, vtx := range n.vtxs {
+		if vtx.Status() == choices.Accepted {
+			prefs = append(prefs, vtx.ID())
+		}
+	}
+	return prefs
+}
+
+func (n *avalancheNode) voteID(i int) ids.ID { return n.vtxs[i].ID() }
+
+func (n *avalancheNode) recordPoll(responses [][]ids.ID) error {
+	votes := ids.UniqueBag{}
+	for i, response := range responses {
+		votes.Add(uint(i), response...)
+	}
+	return n.con.RecordPoll(votes)
+}
+
+func (n *avalancheNode) finalized() bool { return n.con.Finalized() }
+
+// vtxID returns the ID of the vertex that issues the tx [txID]
+func vtxID(txID ids.ID) ids.ID { return txID.Prefix(0) }
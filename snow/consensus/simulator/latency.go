@@ -0,0 +1,49 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Latency samples the time it takes a peer to respond to a query
+type Latency func(rng *rand.Rand) time.Duration
+
+// ConstantLatency returns a Latency where every response takes [d]
+func ConstantLatency(d time.Duration) Latency {
+	return func(*rand.Rand) time.Duration { return d }
+}
+
+// UniformLatency returns a Latency where responses take uniformly between [min]
+// and [max]
+func UniformLatency(min, max time.Duration) Latency {
+	return func(rng *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(rng.Int63n(int64(max-min)+1))
+	}
+}
+
+// NormalLatency returns a Latency where responses take a normally distributed
+// time with the provided [mean] and [stdDev]. Samples below 0 are treated as 0.
+func NormalLatency(mean, stdDev time.Duration) Latency {
+	return func(rng *rand.Rand) time.Duration {
+		d := time.Duration(rng.NormFloat64()*float64(stdDev)) + mean
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+}
+
+// ExponentialLatency returns a Latency where responses take an exponentially
+// distributed time with the provided [mean]. This models a long tail of slow
+// peers.
+func ExponentialLatency(mean time.Duration) Latency {
+	return func(rng *rand.Rand) time.Duration {
+		return time.Duration(rng.ExpFloat64() * float64(mean))
+	}
+}
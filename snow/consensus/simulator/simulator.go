@@ -0,0 +1,208 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulator runs the consensus implementations over a simulated
+// network, so that consensus parameters can be evaluated without deploying a
+// network.
+package simulator
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+var _ heap.Interface = &queryHeap{}
+
+// Run the simulation described by [config]. Every node issues the same txs, in
+// a random order, and then repeatedly queries K random peers, recording a poll
+// once every peer has responded, until it has decided every tx or recorded
+// MaxPolls polls. Peers respond with their preferences at the time they are
+// queried.
+func Run(config Config) (Result, error) {
+	if err := config.Valid(); err != nil {
+		return Result{}, err
+	}
+	maxPolls := config.MaxPolls
+	if maxPolls == 0 {
+		maxPolls = defaultMaxPolls
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed)) // #nosec G404
+	txs := newTxs(rng, config.NumTxs, config.ConflictRate)
+
+	// The txs issued by each node, indexed by the node and then the tx
+	nodeTxs := make([][]*snowstorm.TestTx, config.NumNodes)
+	nodes := make([]node, config.NumNodes)
+	for i := range nodes {
+		nodeTxs[i] = make([]*snowstorm.TestTx, len(txs))
+		issued := make([]*snowstorm.TestTx, len(txs))
+		for j, k := range rng.Perm(len(txs)) {
+			tx := &snowstorm.TestTx{
+				TestDecidable: choices.TestDecidable{
+					IDV:     txs[k].ID(),
+					StatusV: choices.Processing,
+				},
+				InputIDsV: txs[k].InputIDs(),
+			}
+			nodeTxs[i][k] = tx
+			issued[j] = tx
+		}
+
+		n, err := config.Engine.newNode(config.Parameters, issued)
+		if err != nil {
+			return Result{}, err
+		}
+		nodes[i] = n
+	}
+
+	s := &simulation{
+		config: config,
+		rng:    rng,
+		nodes:  nodes,
+	}
+	result := Result{}
+	for i, n := range nodes {
+		if n.finalized() {
+			result.Finalized++
+			result.FinalityTimes = append(result.FinalityTimes, 0)
+			continue
+		}
+		if err := s.query(i, 0); err != nil {
+			return Result{}, err
+		}
+	}
+
+	numPolls := make([]int, len(nodes))
+	for s.queries.Len() > 0 {
+		q := heap.Pop(&s.queries).(*query)
+		n := nodes[q.node]
+		if err := n.recordPoll(q.responses); err != nil {
+			return Result{}, err
+		}
+		numPolls[q.node]++
+		result.Polls++
+
+		switch {
+		case n.finalized():
+			result.Finalized++
+			result.FinalityTimes = append(result.FinalityTimes, q.time)
+		case numPolls[q.node] < maxPolls:
+			if err := s.query(q.node, q.time); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+
+	for i := range txs {
+		accepted, rejected := false, false
+		for _, issued := range nodeTxs {
+			switch issued[i].Status() {
+			case choices.Accepted:
+				accepted = true
+			case choices.Rejected:
+				rejected = true
+			}
+		}
+		if accepted && rejected {
+			result.SafetyViolations++
+		}
+	}
+	return result, nil
+}
+
+// newTxs returns [numTxs] txs that each spend one input. Each tx spends the
+// input of a random earlier tx with probability [conflictRate].
+func newTxs(rng *rand.Rand, numTxs int, conflictRate float64) []*snowstorm.TestTx {
+	txs := make([]*snowstorm.TestTx, numTxs)
+	for i := range txs {
+		inputID := ids.Empty.Prefix(1, uint64(i))
+		if i > 0 && rng.Float64() < conflictRate {
+			inputID = txs[rng.Intn(i)].InputIDs()[0]
+		}
+		txs[i] = &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(0, uint64(i)),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{inputID},
+		}
+	}
+	return txs
+}
+
+type simulation struct {
+	config  Config
+	rng     *rand.Rand
+	nodes   []node
+	queries queryHeap
+}
+
+// query K random peers on behalf of the [i]th node at [now]
+func (s *simulation) query(i int, now time.Duration) error {
+	k := s.config.Parameters.K
+	q := &query{
+		node:      i,
+		time:      now,
+		responses: make([][]ids.ID, k),
+	}
+	for j, peerIndex := range s.rng.Perm(len(s.nodes))[:k] {
+		peer := s.nodes[peerIndex]
+		if s.rng.Float64() < s.config.VoteNoise {
+			q.responses[j] = []ids.ID{peer.voteID(s.rng.Intn(s.config.NumTxs))}
+		} else {
+			q.responses[j] = peer.preferences()
+		}
+
+		latency := s.config.Latency(s.rng)
+		if latency < 0 {
+			return errNegativeDelays
+		}
+		if responseTime := now + latency; responseTime > q.time {
+			q.time = responseTime
+		}
+	}
+	heap.Push(&s.queries, q)
+	return nil
+}
+
+// query is an outstanding query, that will be recorded as a poll once every
+// sampled peer has responded
+type query struct {
+	// The index of the node that sent the query
+	node int
+	// The time that the last response arrives at
+	time time.Duration
+	// The response of each sampled peer
+	responses [][]ids.ID
+}
+
+// queryHeap orders queries by the time they finish, and then by the node that
+// sent them
+type queryHeap []*query
+
+func (h queryHeap) Len() int { return len(h) }
+
+func (h queryHeap) Less(i, j int) bool {
+	if h[i].time != h[j].time {
+		return h[i].time < h[j].time
+	}
+	return h[i].node < h[j].node
+}
+
+func (h queryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *queryHeap) Push(x interface{}) { *h = append(*h, x.(*query)) }
+
+func (h *queryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,89 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+var engines = map[string]Engine{
+	"directed":  &Snowstorm{Factory: snowstorm.DirectedFactory{}},
+	"input":     &Snowstorm{Factory: snowstorm.InputFactory{}},
+	"avalanche": &Avalanche{},
+}
+
+func testConfig(engine Engine) Config {
+	return Config{
+		Parameters: sbcon.Parameters{
+			K:                     10,
+			Alpha:                 7,
+			BetaVirtuous:          5,
+			BetaRogue:             10,
+			ConcurrentRepolls:     1,
+			OptimalProcessing:     1,
+			MaxOutstandingItems:   1,
+			MaxItemProcessingTime: 1,
+		},
+		Engine:       engine,
+		NumNodes:     20,
+		NumTxs:       10,
+		ConflictRate: .3,
+		Latency:      UniformLatency(10*time.Millisecond, 100*time.Millisecond),
+		Seed:         1,
+	}
+}
+
+func TestRunFinalizes(t *testing.T) {
+	for name, engine := range engines {
+		t.Run(name, func(t *testing.T) {
+			result, err := Run(testConfig(engine))
+			assert.NoError(t, err)
+			assert.Equal(t, 20, result.Finalized)
+			assert.Len(t, result.FinalityTimes, 20)
+			assert.Zero(t, result.SafetyViolations)
+			assert.Positive(t, result.Polls)
+
+			assert.Positive(t, result.MeanFinalityTime())
+			assert.LessOrEqual(t, result.MeanFinalityTime(), result.MaxFinalityTime())
+		})
+	}
+}
+
+func TestRunGivesUp(t *testing.T) {
+	config := testConfig(&Snowstorm{})
+	// Every vote is for a random tx, so no tx is ever preferred by alpha peers
+	config.VoteNoise = 1
+	config.MaxPolls = 5
+
+	result, err := Run(config)
+	assert.NoError(t, err)
+	assert.Zero(t, result.Finalized)
+	assert.Equal(t, 5*config.NumNodes, result.Polls)
+	assert.Zero(t, result.MaxFinalityTime())
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	config := testConfig(&Snowstorm{})
+	config.NumNodes = config.Parameters.K - 1
+	_, err := Run(config)
+	assert.True(t, errors.Is(err, errTooFewNodes))
+
+	config = testConfig(&Snowstorm{})
+	config.ConflictRate = 2
+	_, err = Run(config)
+	assert.True(t, errors.Is(err, errInvalidRate))
+
+	config = testConfig(&Snowstorm{})
+	config.Latency = ConstantLatency(-time.Second)
+	_, err = Run(config)
+	assert.True(t, errors.Is(err, errNegativeDelays))
+}
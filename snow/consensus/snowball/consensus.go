@@ -20,6 +20,10 @@ type Consensus interface {
 	// Returns the parameters that describe this snowball instance
 	Parameters() Parameters
 
+	// UpdateParameters replaces the parameters of this snowball instance. Only
+	// K, Alpha and ConcurrentRepolls can be changed.
+	UpdateParameters(Parameters) error
+
 	// Adds a new choice to vote on
 	Add(newChoice ids.ID)
 
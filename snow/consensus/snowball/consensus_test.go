@@ -29,6 +29,12 @@ func (b *Byzantine) Initialize(params Parameters, choice ids.ID) {
 // Parameters implements the Consensus interface
 func (b *Byzantine) Parameters() Parameters { return b.params }
 
+// UpdateParameters implements the Consensus interface
+func (b *Byzantine) UpdateParameters(params Parameters) error {
+	b.params = params
+	return nil
+}
+
 // Add implements the Consensus interface
 func (b *Byzantine) Add(choice ids.ID) {}
 
@@ -31,6 +31,15 @@ func (f *Flat) Initialize(params Parameters, choice ids.ID) {
 // Parameters implements the Consensus interface
 func (f *Flat) Parameters() Parameters { return f.params }
 
+// UpdateParameters implements the Consensus interface
+func (f *Flat) UpdateParameters(params Parameters) error {
+	if err := f.params.VerifyUpdate(params); err != nil {
+		return err
+	}
+	f.params = params
+	return nil
+}
+
 // RecordPoll implements the Consensus interface
 func (f *Flat) RecordPoll(votes ids.Bag) {
 	if pollMode, numVotes := votes.Mode(); numVotes >= f.params.Alpha {
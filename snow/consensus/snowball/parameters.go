@@ -4,7 +4,9 @@
 package snowball
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,6 +30,8 @@ const (
 		`        \/         \/         \/` + "\n"
 )
 
+var errFixedParameters = errors.New("only k, alpha and concurrentRepolls can be updated")
+
 // Parameters required for snowball consensus
 type Parameters struct {
 	Namespace                                                               string
@@ -40,32 +44,103 @@ type Parameters struct {
 	// Reports unhealthy if there is an item processing for longer than this
 	// duration.
 	MaxItemProcessingTime time.Duration
+
+	// DAG engines stop issuing local transactions while this many
+	// transactions, or vertices, are processing. If 0, the number of
+	// processing items isn't limited.
+	MaxProcessing int
+
+	// A processing item is stalled if its confidence hasn't increased for
+	// longer than this duration. If 0, items are never considered stalled.
+	MaxItemStalledTime time.Duration
+}
+
+// InvalidParametersError describes every constraint that a set of parameters
+// violates
+type InvalidParametersError struct {
+	// Each element describes a violated constraint
+	Violations []string
+}
+
+func (e *InvalidParametersError) Error() string {
+	return strings.Join(e.Violations, "; ")
 }
 
-// Verify returns nil if the parameters describe a valid initialization.
-func (p Parameters) Verify() error {
+// Verify returns nil if the parameters describe a valid initialization. It is
+// equivalent to Validate.
+func (p Parameters) Verify() error { return p.Validate() }
+
+// Validate returns nil if the parameters describe a valid initialization.
+// Otherwise, an *InvalidParametersError that describes every violated
+// constraint is returned.
+func (p Parameters) Validate() error {
+	var violations []string
+	violated := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	if p.K <= 0 {
+		violated("k = %d: fails the condition that: 0 < k", p.K)
+	}
+	if p.Alpha <= p.K/2 {
+		violated("k = %d, alpha = %d: fails the condition that: k/2 < alpha", p.K, p.Alpha)
+	}
+	if p.K < p.Alpha {
+		violated("k = %d, alpha = %d: fails the condition that: alpha <= k", p.K, p.Alpha)
+	}
+	if p.BetaVirtuous <= 0 {
+		violated("betaVirtuous = %d: fails the condition that: 0 < betaVirtuous", p.BetaVirtuous)
+	}
 	switch {
-	case p.Alpha <= p.K/2:
-		return fmt.Errorf("k = %d, alpha = %d: fails the condition that: k/2 < alpha", p.K, p.Alpha)
-	case p.K < p.Alpha:
-		return fmt.Errorf("k = %d, alpha = %d: fails the condition that: alpha <= k", p.K, p.Alpha)
-	case p.BetaVirtuous <= 0:
-		return fmt.Errorf("betaVirtuous = %d: fails the condition that: 0 < betaVirtuous", p.BetaVirtuous)
 	case p.BetaRogue == 3 && p.BetaVirtuous == 28:
-		return fmt.Errorf("betaVirtuous = %d, betaRogue = %d: fails the condition that: betaVirtuous <= betaRogue\n%s", p.BetaVirtuous, p.BetaRogue, errMsg)
+		violated("betaVirtuous = %d, betaRogue = %d: fails the condition that: betaVirtuous <= betaRogue\n%s", p.BetaVirtuous, p.BetaRogue, errMsg)
 	case p.BetaRogue < p.BetaVirtuous:
-		return fmt.Errorf("betaVirtuous = %d, betaRogue = %d: fails the condition that: betaVirtuous <= betaRogue", p.BetaVirtuous, p.BetaRogue)
-	case p.ConcurrentRepolls <= 0:
-		return fmt.Errorf("concurrentRepolls = %d: fails the condition that: 0 < concurrentRepolls", p.ConcurrentRepolls)
-	case p.ConcurrentRepolls > p.BetaRogue:
-		return fmt.Errorf("concurrentRepolls = %d, betaRogue = %d: fails the condition that: concurrentRepolls <= betaRogue", p.ConcurrentRepolls, p.BetaRogue)
-	case p.OptimalProcessing <= 0:
-		return fmt.Errorf("optimalProcessing = %d: fails the condition that: 0 < optimalProcessing", p.OptimalProcessing)
-	case p.MaxOutstandingItems <= 0:
-		return fmt.Errorf("maxOutstandingItems = %d: fails the condition that: 0 < maxOutstandingItems", p.MaxOutstandingItems)
-	case p.MaxItemProcessingTime <= 0:
-		return fmt.Errorf("maxItemProcessingTime = %d: fails the condition that: 0 < maxItemProcessingTime", p.MaxItemProcessingTime)
-	default:
+		violated("betaVirtuous = %d, betaRogue = %d: fails the condition that: betaVirtuous <= betaRogue", p.BetaVirtuous, p.BetaRogue)
+	}
+	if p.ConcurrentRepolls <= 0 {
+		violated("concurrentRepolls = %d: fails the condition that: 0 < concurrentRepolls", p.ConcurrentRepolls)
+	}
+	if p.ConcurrentRepolls > p.BetaRogue {
+		violated("concurrentRepolls = %d, betaRogue = %d: fails the condition that: concurrentRepolls <= betaRogue", p.ConcurrentRepolls, p.BetaRogue)
+	}
+	if p.OptimalProcessing <= 0 {
+		violated("optimalProcessing = %d: fails the condition that: 0 < optimalProcessing", p.OptimalProcessing)
+	}
+	if p.MaxOutstandingItems <= 0 {
+		violated("maxOutstandingItems = %d: fails the condition that: 0 < maxOutstandingItems", p.MaxOutstandingItems)
+	}
+	if p.MaxItemProcessingTime <= 0 {
+		violated("maxItemProcessingTime = %d: fails the condition that: 0 < maxItemProcessingTime", p.MaxItemProcessingTime)
+	}
+	if p.MaxProcessing < 0 {
+		violated("maxProcessing = %d: fails the condition that: 0 <= maxProcessing", p.MaxProcessing)
+	}
+	if p.MaxItemStalledTime < 0 {
+		violated("maxItemStalledTime = %d: fails the condition that: 0 <= maxItemStalledTime", p.MaxItemStalledTime)
+	}
+
+	if len(violations) == 0 {
 		return nil
 	}
+	return &InvalidParametersError{Violations: violations}
+}
+
+// VerifyUpdate returns nil if [update] describes a valid initialization and
+// only changes the parameters that can be changed while consensus is running:
+// K, Alpha and ConcurrentRepolls. The confidence thresholds are copied into
+// the snowflake instances when they're created, so they can't be changed.
+func (p Parameters) VerifyUpdate(update Parameters) error {
+	switch {
+	case p.Namespace != update.Namespace,
+		p.BetaVirtuous != update.BetaVirtuous,
+		p.BetaRogue != update.BetaRogue,
+		p.OptimalProcessing != update.OptimalProcessing,
+		p.MaxOutstandingItems != update.MaxOutstandingItems,
+		p.MaxItemProcessingTime != update.MaxItemProcessingTime,
+		p.MaxProcessing != update.MaxProcessing,
+		p.MaxItemStalledTime != update.MaxItemStalledTime:
+		return errFixedParameters
+	default:
+		return update.Validate()
+	}
 }
@@ -4,6 +4,7 @@
 package snowball
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -230,3 +231,74 @@ func TestParametersInvalidMaxItemProcessingTime(t *testing.T) {
 		t.Fatalf("Should have failed due to invalid max item processing time")
 	}
 }
+
+func TestParametersValidateReportsEveryViolation(t *testing.T) {
+	p := Parameters{
+		K:                     0,
+		Alpha:                 0,
+		BetaVirtuous:          2,
+		BetaRogue:             1,
+		ConcurrentRepolls:     2,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+
+	err := p.Validate()
+	invalidErr := &InvalidParametersError{}
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("Should have failed with an InvalidParametersError but got %v", err)
+	}
+
+	// k, alpha, betaRogue and concurrentRepolls are each violated
+	expected := []string{
+		"0 < k",
+		"k/2 < alpha",
+		"betaVirtuous <= betaRogue",
+		"concurrentRepolls <= betaRogue",
+	}
+	if len(invalidErr.Violations) != len(expected) {
+		t.Fatalf("Should have reported %d violations but reported %v", len(expected), invalidErr.Violations)
+	}
+	for i, violation := range invalidErr.Violations {
+		if !strings.HasSuffix(violation, expected[i]) {
+			t.Fatalf("Violation %q should have described %q", violation, expected[i])
+		}
+	}
+	if err.Error() != strings.Join(invalidErr.Violations, "; ") {
+		t.Fatalf("Should have described every violation")
+	}
+}
+
+func TestParametersVerifyUpdate(t *testing.T) {
+	p := Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+
+	update := p
+	update.K = 5
+	update.Alpha = 4
+	update.ConcurrentRepolls = 2
+	if err := p.VerifyUpdate(update); err != nil {
+		t.Fatalf("Should have been able to update the sampling parameters: %s", err)
+	}
+
+	invalid := update
+	invalid.Alpha = 2
+	if err := p.VerifyUpdate(invalid); err == nil {
+		t.Fatalf("Should have failed to update to invalid parameters")
+	}
+
+	fixed := update
+	fixed.BetaVirtuous = 2
+	if err := p.VerifyUpdate(fixed); err != errFixedParameters {
+		t.Fatalf("Should have failed to update betaVirtuous but got %v", err)
+	}
+}
@@ -60,6 +60,15 @@ func (t *Tree) Initialize(params Parameters, choice ids.ID) {
 // Parameters implements the Consensus interface
 func (t *Tree) Parameters() Parameters { return t.params }
 
+// UpdateParameters implements the Consensus interface
+func (t *Tree) UpdateParameters(params Parameters) error {
+	if err := t.params.VerifyUpdate(params); err != nil {
+		return err
+	}
+	t.params = params
+	return nil
+}
+
 // Add implements the Consensus interface
 func (t *Tree) Add(choice ids.ID) {
 	prefix := t.node.DecidedPrefix()
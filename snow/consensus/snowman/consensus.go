@@ -13,6 +13,8 @@ import (
 // process a series of dependent operations.
 type Consensus interface {
 	// Takes in the context, snowball parameters, and the last accepted block.
+	// May be called again to reset the instance to a new last accepted block,
+	// in which case the processing blocks are dropped.
 	Initialize(
 		ctx *snow.Context,
 		params snowball.Parameters,
@@ -23,6 +25,11 @@ type Consensus interface {
 	// Returns the parameters that describe this snowman instance
 	Parameters() snowball.Parameters
 
+	// Replaces the parameters of this snowman instance, including the
+	// parameters of the snowball instances of the processing blocks. Only K,
+	// Alpha and ConcurrentRepolls can be changed.
+	UpdateParameters(snowball.Parameters) error
+
 	// Returns the number of blocks processing
 	NumProcessing() int
 
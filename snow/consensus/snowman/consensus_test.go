@@ -51,6 +51,7 @@ var (
 		ErrorOnRejectSiblingTest,
 		ErrorOnTransitiveRejectionTest,
 		RandomizedConsistencyTest,
+		UpdateParametersTest,
 	}
 )
 
@@ -1494,3 +1495,69 @@ func RandomizedConsistencyTest(t *testing.T, factory Factory) {
 		t.Fatalf("Network agreed on inconsistent values")
 	}
 }
+
+// Make sure that updated parameters apply to the blocks that are processing
+func UpdateParametersTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	if err := sm.Initialize(ctx, params, GenesisID, GenesisHeight); err != nil {
+		t.Fatal(err)
+	}
+
+	block := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	if err := sm.Add(block); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidParams := params
+	invalidParams.BetaVirtuous = 2
+	invalidParams.BetaRogue = 2
+	if err := sm.UpdateParameters(invalidParams); err == nil {
+		t.Fatalf("shouldn't have been able to update the confidence thresholds")
+	}
+
+	updatedParams := params
+	updatedParams.K = 3
+	updatedParams.Alpha = 2
+	if err := sm.UpdateParameters(updatedParams); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Parameters().Alpha != updatedParams.Alpha {
+		t.Fatalf("should have updated alpha to %d", updatedParams.Alpha)
+	}
+
+	votes := ids.Bag{}
+	votes.Add(block.ID())
+	if err := sm.RecordPoll(votes); err != nil {
+		t.Fatal(err)
+	}
+	if status := block.Status(); status != choices.Processing {
+		t.Fatalf("a poll with fewer than alpha votes shouldn't have accepted the block")
+	}
+
+	votes.AddCount(block.ID(), 2)
+	if err := sm.RecordPoll(votes); err != nil {
+		t.Fatal(err)
+	}
+	if status := block.Status(); status != choices.Accepted {
+		t.Fatalf("a poll with alpha votes should have accepted the block")
+	}
+}
@@ -50,6 +50,9 @@ func (p *earlyTermNoTraversalPoll) Drop(vdr ids.ShortID) {
 	p.polled.Remove(vdr)
 }
 
+// Pending returns the validators the poll is still waiting on
+func (p *earlyTermNoTraversalPoll) Pending() []ids.ShortID { return p.polled.List() }
+
 // Finished returns true when all validators have voted
 func (p *earlyTermNoTraversalPoll) Finished() bool {
 	remaining := p.polled.Len()
@@ -5,6 +5,7 @@ package poll
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 )
@@ -13,10 +14,15 @@ import (
 type Set interface {
 	fmt.Stringer
 
-	Add(requestID uint32, vdrs ids.ShortBag) bool
+	Add(requestID uint32, containerID ids.ID, vdrs ids.ShortBag) bool
 	Vote(requestID uint32, vdr ids.ShortID, vote ids.ID) (ids.Bag, bool)
 	Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool)
 	Len() int
+	Polls() []Info
+
+	// SetFactory replaces the factory that creates the polls added from now
+	// on. Outstanding polls aren't changed.
+	SetFactory(Factory)
 }
 
 // Poll is an outstanding poll
@@ -26,10 +32,26 @@ type Poll interface {
 
 	Vote(vdr ids.ShortID, vote ids.ID)
 	Drop(vdr ids.ShortID)
+	Pending() []ids.ShortID
 	Finished() bool
 	Result() ids.Bag
 }
 
+// Info describes an outstanding poll
+type Info struct {
+	RequestID uint32
+	// ID of the container the poll was started for
+	ContainerID ids.ID
+	// Validators that were sent the query
+	Validators []ids.ShortID
+	// Validators that haven't responded yet
+	Pending []ids.ShortID
+	// Number of chits received
+	NumChits int
+	// Time the poll was started
+	Start time.Time
+}
+
 // Factory creates a new Poll
 type Factory interface {
 	New(vdrs ids.ShortBag) Poll
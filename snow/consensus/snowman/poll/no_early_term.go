@@ -39,6 +39,9 @@ func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, vote ids.ID) {
 // Drop any future response for this poll
 func (p *noEarlyTermPoll) Drop(vdr ids.ShortID) { p.polled.Remove(vdr) }
 
+// Pending returns the validators the poll is still waiting on
+func (p *noEarlyTermPoll) Pending() []ids.ShortID { return p.polled.List() }
+
 // Finished returns true when all validators have voted
 func (p *noEarlyTermPoll) Finished() bool { return p.polled.Len() == 0 }
 
@@ -17,7 +17,10 @@ import (
 
 type poll struct {
 	Poll
-	start time.Time
+	containerID ids.ID
+	vdrs        []ids.ShortID
+	numChits    int
+	start       time.Time
 }
 
 type set struct {
@@ -25,7 +28,7 @@ type set struct {
 	numPolls prometheus.Gauge
 	durPolls prometheus.Histogram
 	factory  Factory
-	polls    map[uint32]poll
+	polls    map[uint32]*poll
 }
 
 // NewSet returns a new empty set of polls
@@ -59,14 +62,14 @@ func NewSet(
 		numPolls: numPolls,
 		durPolls: durPolls,
 		factory:  factory,
-		polls:    make(map[uint32]poll),
+		polls:    make(map[uint32]*poll),
 	}
 }
 
 // Add to the current set of polls
 // Returns true if the poll was registered correctly and the network sample
 //         should be made.
-func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
+func (s *set) Add(requestID uint32, containerID ids.ID, vdrs ids.ShortBag) bool {
 	if _, exists := s.polls[requestID]; exists {
 		s.log.Debug("dropping poll due to duplicated requestID: %d", requestID)
 		return false
@@ -76,9 +79,11 @@ func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
 		requestID,
 		&vdrs)
 
-	s.polls[requestID] = poll{
-		Poll:  s.factory.New(vdrs), // create the new poll
-		start: time.Now(),
+	s.polls[requestID] = &poll{
+		containerID: containerID,
+		vdrs:        vdrs.List(),
+		Poll:        s.factory.New(vdrs), // create the new poll
+		start:       time.Now(),
 	}
 	s.numPolls.Inc() // increase the metrics
 	return true
@@ -104,7 +109,11 @@ func (s *set) Vote(
 		requestID,
 		vote)
 
+	numPending := len(poll.Pending())
 	poll.Vote(vdr, vote)
+	if len(poll.Pending()) < numPending {
+		poll.numChits++
+	}
 	if !poll.Finished() {
 		return ids.Bag{}, false
 	}
@@ -148,6 +157,25 @@ func (s *set) Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
 // Len returns the number of outstanding polls
 func (s *set) Len() int { return len(s.polls) }
 
+// SetFactory replaces the factory that creates new polls
+func (s *set) SetFactory(factory Factory) { s.factory = factory }
+
+// Polls returns a description of the outstanding polls
+func (s *set) Polls() []Info {
+	infos := make([]Info, 0, len(s.polls))
+	for requestID, poll := range s.polls {
+		infos = append(infos, Info{
+			RequestID:   requestID,
+			ContainerID: poll.containerID,
+			Validators:  poll.vdrs,
+			Pending:     poll.Pending(),
+			NumChits:    poll.numChits,
+			Start:       poll.start,
+		})
+	}
+	return infos
+}
+
 func (s *set) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("current polls: (Size = %d)", len(s.polls)))
@@ -58,11 +58,11 @@ func TestCreateAndFinishSuccessfulPoll(t *testing.T) {
 
 	if s.Len() != 0 {
 		t.Fatalf("Shouldn't have any active polls yet")
-	} else if !s.Add(0, vdrs) {
+	} else if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
-	} else if s.Add(0, vdrs) {
+	} else if s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Shouldn't have been able to add a duplicated poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
@@ -101,11 +101,11 @@ func TestCreateAndFinishFailedPoll(t *testing.T) {
 
 	if s.Len() != 0 {
 		t.Fatalf("Shouldn't have any active polls yet")
-	} else if !s.Add(0, vdrs) {
+	} else if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
-	} else if s.Add(0, vdrs) {
+	} else if s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Shouldn't have been able to add a duplicated poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
@@ -137,7 +137,7 @@ func TestSetString(t *testing.T) {
 	expected := "current polls: (Size = 1)\n" +
 		"    0: waiting on Bag: (Size = 1)\n" +
 		"        ID[6HgC8KRBEhXYbF4riJyJFLSHt37UNuRt]: Count = 1"
-	if !s.Add(0, vdrs) {
+	if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if str := s.String(); expected != str {
 		t.Fatalf("Set return wrong string, Expected:\n%s\nReturned:\n%s",
@@ -145,3 +145,52 @@ func TestSetString(t *testing.T) {
 			str)
 	}
 }
+
+func TestSetPolls(t *testing.T) {
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	blkID := ids.ID{1}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3} // k = 3
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+	)
+
+	if !s.Add(0, blkID, vdrs) {
+		t.Fatalf("Should have been able to add a new poll")
+	} else if _, finished := s.Vote(0, vdr1, blkID); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	} else if _, finished := s.Vote(0, vdr1, blkID); finished {
+		t.Fatalf("Should have dropped a duplicated vote")
+	} else if _, finished := s.Drop(0, vdr2); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	}
+
+	polls := s.Polls()
+	if len(polls) != 1 {
+		t.Fatalf("Should only have one active poll")
+	}
+	poll := polls[0]
+	switch {
+	case poll.RequestID != 0:
+		t.Fatalf("Wrong requestID returned")
+	case poll.ContainerID != blkID:
+		t.Fatalf("Wrong block returned")
+	case len(poll.Validators) != 3:
+		t.Fatalf("Wrong number of validators returned")
+	case len(poll.Pending) != 1 || poll.Pending[0] != vdr3:
+		t.Fatalf("Wrong pending validators returned")
+	case poll.NumChits != 1:
+		t.Fatalf("Wrong number of chits returned")
+	}
+}
@@ -84,10 +84,17 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 	if err := params.Verify(); err != nil {
 		return err
 	}
-	if err := ts.Metrics.Initialize("blks", "block(s)", ctx.Log, params.Namespace, params.Metrics); err != nil {
-		return err
+	if ts.ctx == nil {
+		if err := ts.Metrics.Initialize("blks", "block(s)", ctx.Log, params.Namespace, params.Metrics); err != nil {
+			return err
+		}
+	} else {
+		// This instance is being reset, so the metrics that were registered
+		// when it was first initialized are reused
+		ts.Metrics.Reset()
 	}
 	ts.leaves = ids.Set{}
+	ts.preferredIDs = ids.Set{}
 	ts.kahnNodes = make(map[ids.ID]kahnNode)
 	ts.ctx = ctx
 	ts.params = params
@@ -103,6 +110,23 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 // Parameters implements the Snowman interface
 func (ts *Topological) Parameters() snowball.Parameters { return ts.params }
 
+// UpdateParameters implements the Snowman interface
+func (ts *Topological) UpdateParameters(params snowball.Parameters) error {
+	if err := ts.params.VerifyUpdate(params); err != nil {
+		return err
+	}
+	for _, blk := range ts.blocks {
+		if blk.sb == nil {
+			continue
+		}
+		if err := blk.sb.UpdateParameters(params); err != nil {
+			return err
+		}
+	}
+	ts.params = params
+	return nil
+}
+
 // NumProcessing implements the Snowman interface
 func (ts *Topological) NumProcessing() int { return len(ts.blocks) - 1 }
 
@@ -4,10 +4,14 @@
 package snowstorm
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 
 	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
@@ -261,3 +265,207 @@ func BenchmarkMultiRogueInput(b *testing.B) {
 		}
 	}
 }
+
+/*
+ ******************************************************************************
+ ******************************** Synchronized ********************************
+ ******************************************************************************
+ */
+
+// newPollingGraph returns [graph] initialized with [numConflicts] txs that
+// conflict with each other, and the votes for one of them. Recording the votes
+// never finalizes the graph.
+func newPollingGraph(b *testing.B, graph Consensus, numConflicts int) (Consensus, ids.Bag) {
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1 << 30,
+		BetaRogue:             1 << 30,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	if err := graph.Initialize(snow.DefaultContextTest(), params); err != nil {
+		b.Fatal(err)
+	}
+
+	inputID := ids.Empty.Prefix(0)
+	votes := ids.Bag{}
+	for i := 0; i < numConflicts; i++ {
+		tx := &TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i) + 1),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{inputID},
+		}
+		if err := graph.Add(tx); err != nil {
+			b.Fatal(err)
+		}
+		if i == 0 {
+			votes.Add(tx.ID())
+		}
+	}
+	return graph, votes
+}
+
+// benchmarkRecordPoll records polls on [graph] while [numReaders] goroutines
+// query its preferences
+func benchmarkRecordPoll(b *testing.B, graph Consensus, numReaders int) {
+	graph, votes := newPollingGraph(b, graph, 16)
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < numReaders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					graph.Preferences()
+				}
+			}
+		}()
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := graph.RecordPoll(votes); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	close(done)
+	wg.Wait()
+}
+
+// BenchmarkRecordPollDirected is the lock-free path used by the engine
+func BenchmarkRecordPollDirected(b *testing.B) {
+	benchmarkRecordPoll(b, DirectedFactory{}.New(), 0)
+}
+
+func BenchmarkRecordPollSynchronized(b *testing.B) {
+	benchmarkRecordPoll(b, NewSynchronized(DirectedFactory{}.New()), 0)
+}
+
+func BenchmarkRecordPollSynchronizedContended(b *testing.B) {
+	benchmarkRecordPoll(b, NewSynchronized(DirectedFactory{}.New()), 4)
+}
+
+// BenchmarkPreferencesDirected is the lock-free path used by the engine
+func BenchmarkPreferencesDirected(b *testing.B) {
+	graph, _ := newPollingGraph(b, DirectedFactory{}.New(), 16)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		graph.Preferences()
+	}
+}
+
+func BenchmarkPreferencesSynchronized(b *testing.B) {
+	graph, _ := newPollingGraph(b, NewSynchronized(DirectedFactory{}.New()), 16)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			graph.Preferences()
+		}
+	})
+}
+
+func BenchmarkPreferencesSynchronizedContended(b *testing.B) {
+	graph, votes := newPollingGraph(b, NewSynchronized(DirectedFactory{}.New()), 16)
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				if _, err := graph.RecordPoll(votes); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			graph.Preferences()
+		}
+	})
+	b.StopTimer()
+
+	close(done)
+	wg.Wait()
+}
+
+/*
+ ******************************************************************************
+ ********************************* RecordPoll *********************************
+ ******************************************************************************
+ */
+
+// benchmarkRecordPollManyTxs records polls on a graph with [numTxs] txs, where
+// pairs of txs conflict. Every poll gives alpha votes to one tx of each pair.
+func benchmarkRecordPollManyTxs(b *testing.B, factory Factory, numTxs int) {
+	graph := factory.New()
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     20,
+		Alpha:                 15,
+		BetaVirtuous:          1 << 30,
+		BetaRogue:             1 << 30,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	if err := graph.Initialize(snow.DefaultContextTest(), params); err != nil {
+		b.Fatal(err)
+	}
+
+	votes := ids.Bag{}
+	for i := 0; i < numTxs; i++ {
+		tx := &TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i)),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{ids.Empty.Prefix(uint64(numTxs + i/2))},
+		}
+		if err := graph.Add(tx); err != nil {
+			b.Fatal(err)
+		}
+		if i%2 == 0 {
+			votes.AddCount(tx.ID(), params.Alpha)
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := graph.RecordPoll(votes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRecordPollManyTxsDirected(b *testing.B) {
+	benchmarkRecordPollManyTxs(b, DirectedFactory{}, 1024)
+}
+
+func BenchmarkRecordPollManyTxsInput(b *testing.B) {
+	benchmarkRecordPollManyTxs(b, InputFactory{}, 1024)
+}
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -16,12 +19,20 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/metrics"
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 
 	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
 
-var errUnhealthy = errors.New("snowstorm consensus is not healthy")
+var (
+	errUnhealthy = errors.New("snowstorm consensus is not healthy")
+
+	// ErrDependencyCycle is returned when a transaction is added that
+	// transitively depends on itself. Such a transaction could never be
+	// accepted, so it isn't added.
+	ErrDependencyCycle = errors.New("transaction transitively depends on itself")
+)
 
 type common struct {
 	// metrics that describe this consensus instance
@@ -45,6 +56,29 @@ type common struct {
 	// number of times RecordPoll has been called
 	currentVote int
 
+	// assigns each processing transaction a dense index
+	indices txIndices
+
+	// indices of the transactions that received alpha votes in the poll that
+	// is being recorded
+	metThreshold bitSet
+
+	// Key: ID of a processing transaction
+	// Value: The last time the transaction was added or received a successful
+	//        poll. Ordered from the least recent to the most recent.
+	progress linkedhashmap.LinkedHashmap
+
+	// number of transactions that were stalled the last time Stalled was
+	// called
+	numStalled prometheus.Gauge
+
+	// number of polls recorded while an accepted transaction was processing
+	pollsAccepted prometheus.Histogram
+
+	// number of transactions that conflicted with an accepted transaction when
+	// it was accepted
+	conflictsAccepted prometheus.Histogram
+
 	// keeps track of whether dependencies have been accepted
 	pendingAccept events.Blocker
 
@@ -53,28 +87,115 @@ type common struct {
 
 	// track any errors that occurred during callbacks
 	errs wrappers.Errs
+
+	// called, in the order they were registered, after a tx is decided
+	onAccept []func(Tx)
+	onReject []func(Tx, RejectionReason, ids.ID)
 }
 
 // Initialize implements the ConflictGraph interface
 func (c *common) Initialize(ctx *snow.Context, params sbcon.Parameters) error {
+	if c.ctx == nil {
+		if err := c.initializeMetrics(ctx, params); err != nil {
+			return err
+		}
+	} else {
+		// This instance is being reset, so the metrics that were registered
+		// when it was first initialized are reused
+		c.Metrics.Reset()
+		c.numStalled.Set(0)
+	}
+
 	c.ctx = ctx
 	c.params = params
+	c.progress = linkedhashmap.New()
+	c.preferences = ids.Set{}
+	c.virtuous = ids.Set{}
+	c.virtuousVoting = ids.Set{}
+	c.currentVote = 0
+	c.indices = txIndices{}
+	c.metThreshold = nil
+	c.pendingAccept = events.Blocker{}
+	c.pendingReject = events.Blocker{}
+	return params.Verify()
+}
 
+// initializeMetrics registers the metrics of this instance
+func (c *common) initializeMetrics(ctx *snow.Context, params sbcon.Parameters) error {
 	if err := c.Metrics.Initialize("txs", "transaction(s)", ctx.Log, params.Namespace, params.Metrics); err != nil {
 		return fmt.Errorf("failed to initialize metrics: %w", err)
 	}
-	return params.Verify()
+	c.numStalled = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: params.Namespace,
+		Name:      "txs_stalled",
+		Help:      "Number of processing transaction(s) whose confidence hasn't increased recently",
+	})
+	c.pollsAccepted = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: params.Namespace,
+		Name:      "txs_polls_accepted",
+		Help:      "Number of polls recorded from the time the transaction(s) were issued until they were accepted",
+		Buckets: []float64{
+			1,
+			2,
+			5,
+			10,
+			20,
+			50,
+			100,
+			200,
+			500,
+			1000,
+		},
+	})
+	c.conflictsAccepted = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: params.Namespace,
+		Name:      "txs_conflicts_accepted",
+		Help:      "Number of processing transaction(s) that conflicted with transaction(s) when they were accepted",
+		Buckets: []float64{
+			0,
+			1,
+			2,
+			5,
+			10,
+			20,
+			50,
+			100,
+		},
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		params.Metrics.Register(c.numStalled),
+		params.Metrics.Register(c.pollsAccepted),
+		params.Metrics.Register(c.conflictsAccepted),
+	)
+	if errs.Errored() {
+		return fmt.Errorf("failed to initialize metrics: %w", errs.Err)
+	}
+	return nil
 }
 
 // Parameters implements the Snowstorm interface
 func (c *common) Parameters() sbcon.Parameters { return c.params }
 
+// UpdateParameters implements the Snowstorm interface
+func (c *common) UpdateParameters(params sbcon.Parameters) error {
+	if err := c.params.VerifyUpdate(params); err != nil {
+		return err
+	}
+	c.params = params
+	return nil
+}
+
 // Virtuous implements the ConflictGraph interface
 func (c *common) Virtuous() ids.Set { return c.virtuous }
 
 // Preferences implements the ConflictGraph interface
 func (c *common) Preferences() ids.Set { return c.preferences }
 
+// NumProcessing implements the ConflictGraph interface
+func (c *common) NumProcessing() int { return c.Metrics.ProcessingLen() }
+
 // Quiesce implements the ConflictGraph interface
 func (c *common) Quiesce() bool {
 	numVirtuous := c.virtuousVoting.Len()
@@ -104,12 +225,59 @@ func (c *common) HealthCheck() (interface{}, error) {
 	healthy = healthy && timeReqRunning <= c.params.MaxItemProcessingTime
 	details["longestRunningTx"] = timeReqRunning.String()
 
+	if c.params.MaxItemStalledTime > 0 {
+		details["stalledTransactions"] = len(c.Stalled())
+	}
+
 	if !healthy {
 		return details, errUnhealthy
 	}
 	return details, nil
 }
 
+// Stalled implements the ConflictGraph interface
+func (c *common) Stalled() []ids.ID {
+	if c.params.MaxItemStalledTime == 0 {
+		return nil
+	}
+
+	stalledBefore := c.Clock.Time().Add(-c.params.MaxItemStalledTime)
+	stalled := []ids.ID(nil)
+	it := c.progress.NewIterator()
+	for it.Next() {
+		if !it.Value().(time.Time).Before(stalledBefore) {
+			// Every following tx progressed more recently
+			break
+		}
+		stalled = append(stalled, it.Key().(ids.ID))
+	}
+	c.numStalled.Set(float64(len(stalled)))
+	return stalled
+}
+
+// observeAccepted reports the [stats] of a tx that is being accepted
+func (c *common) observeAccepted(stats TxPollStats) {
+	c.pollsAccepted.Observe(float64(stats.NumPolls))
+	c.conflictsAccepted.Observe(float64(stats.NumConflicts))
+}
+
+// betas returns the betaVirtuous and betaRogue that [tx] is accepted with
+func (c *common) betas(tx Tx) (int, int) {
+	betaVirtuous, betaRogue := c.params.BetaVirtuous, c.params.BetaRogue
+	if thresholdTx, ok := tx.(ThresholdTx); ok {
+		if threshold := thresholdTx.DecisionThreshold(); threshold > betaRogue {
+			return threshold, threshold
+		}
+	}
+	return betaVirtuous, betaRogue
+}
+
+// progressed marks that the tx [txID] was just added or received a successful
+// poll
+func (c *common) progressed(txID ids.ID) {
+	c.progress.Put(txID, c.Clock.Time())
+}
+
 // shouldVote returns if the provided tx should be voted on to determine if it
 // can be accepted. If the tx can be vacuously accepted, the tx will be accepted
 // and will therefore not be valid to be voted on.
@@ -119,6 +287,10 @@ func (c *common) shouldVote(con Consensus, tx Tx) (bool, error) {
 		return false, nil
 	}
 
+	if dependsOnItself(tx) {
+		return false, fmt.Errorf("%w: %s", ErrDependencyCycle, tx.ID())
+	}
+
 	txID := tx.ID()
 	bytes := tx.Bytes()
 
@@ -152,9 +324,34 @@ func (c *common) shouldVote(con Consensus, tx Tx) (bool, error) {
 
 	// Notify the metrics that this transaction was accepted.
 	c.Metrics.Accepted(txID)
+	c.accepted(tx)
 	return false, nil
 }
 
+// dependsOnItself returns true if [tx] transitively depends on itself. Only
+// dependencies that aren't decided are followed.
+func dependsOnItself(tx Tx) bool {
+	txID := tx.ID()
+	visited := ids.Set{}
+	toVisit := append([]Tx(nil), tx.Dependencies()...)
+	for len(toVisit) > 0 {
+		newLen := len(toVisit) - 1
+		dep := toVisit[newLen]
+		toVisit = toVisit[:newLen]
+
+		depID := dep.ID()
+		if dep.Status().Decided() || visited.Contains(depID) {
+			continue
+		}
+		if depID == txID {
+			return true
+		}
+		visited.Add(depID)
+		toVisit = append(toVisit, dep.Dependencies()...)
+	}
+	return false
+}
+
 // accept the provided tx.
 func (c *common) acceptTx(tx Tx) error {
 	txID := tx.ID()
@@ -173,6 +370,8 @@ func (c *common) acceptTx(tx Tx) error {
 
 	// Update the metrics to account for this transaction's acceptance
 	c.Metrics.Accepted(txID)
+	c.progress.Delete(txID)
+	c.accepted(tx)
 	// If there is a tx that was accepted pending on this tx, the ancestor
 	// should be notified that it doesn't need to block on this tx anymore.
 	c.pendingAccept.Fulfill(txID)
@@ -183,14 +382,21 @@ func (c *common) acceptTx(tx Tx) error {
 	return nil
 }
 
-// reject the provided tx.
-func (c *common) rejectTx(tx Tx) error {
+// reject the provided tx because of [reason]. [causeID] is the accepted
+// conflict or the rejected dependency that caused the rejection.
+func (c *common) rejectTx(tx Tx, reason RejectionReason, causeID ids.ID) error {
 	txID := tx.ID()
-	c.ctx.Log.Trace("rejecting transaction %s due to a conflicting acceptance", txID)
+	c.ctx.Log.Trace("rejecting transaction %s: %s %s", txID, reason, causeID)
 
 	// Reject is called before notifying the IPC so that rejections that
 	// cause fatal errors aren't sent to an IPC peer.
-	if err := tx.Reject(); err != nil {
+	var err error
+	if receiver, ok := tx.(RejectionReceiver); ok {
+		err = receiver.RejectWithReason(reason, causeID)
+	} else {
+		err = tx.Reject()
+	}
+	if err != nil {
 		return err
 	}
 
@@ -201,6 +407,8 @@ func (c *common) rejectTx(tx Tx) error {
 
 	// Update the metrics to account for this transaction's rejection
 	c.Metrics.Rejected(txID)
+	c.progress.Delete(txID)
+	c.rejected(tx, reason, causeID)
 
 	// If there is a tx that was accepted pending on this tx, the ancestor
 	// tx can't be accepted.
@@ -303,14 +511,14 @@ type rejector struct {
 
 func (r *rejector) Dependencies() ids.Set { return r.deps }
 
-func (r *rejector) Fulfill(ids.ID) {
+func (r *rejector) Fulfill(depID ids.ID) {
 	if r.rejected || r.errs.Errored() {
 		return
 	}
 	r.rejected = true
 	asSet := ids.NewSet(1)
 	asSet.Add(r.txID)
-	r.errs.Add(r.g.reject(asSet))
+	r.errs.Add(r.g.reject(asSet, DependencyRejected, depID))
 }
 
 func (*rejector) Abandon(ids.ID) {}
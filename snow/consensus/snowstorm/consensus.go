@@ -5,6 +5,7 @@ package snowstorm
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -24,17 +25,25 @@ type Consensus interface {
 	// Returns the parameters that describe this snowstorm instance
 	Parameters() sbcon.Parameters
 
+	// Replaces the parameters of this snowstorm instance. Only K, Alpha and
+	// ConcurrentRepolls can be changed.
+	UpdateParameters(sbcon.Parameters) error
+
 	// Returns true if transaction <Tx> is virtuous.
 	// That is, no transaction has been added that conflicts with <Tx>
 	IsVirtuous(Tx) bool
 
-	// Adds a new transaction to vote on. Returns if a critical error has
-	// occurred.
+	// Adds a new transaction to vote on. Returns ErrDependencyCycle if the
+	// transaction transitively depends on itself. Otherwise, returns if a
+	// critical error has occurred.
 	Add(Tx) error
 
 	// Returns true iff transaction <Tx> has been added
 	Issued(Tx) bool
 
+	// Returns the number of transactions processing
+	NumProcessing() int
+
 	// Returns the set of virtuous transactions
 	// that have not yet been accepted or rejected
 	Virtuous() ids.Set
@@ -45,11 +54,37 @@ type Consensus interface {
 	// Returns the set of transactions conflicting with <Tx>
 	Conflicts(Tx) ids.Set
 
+	// Returns the IDs of the processing transactions that spend the input
+	// with the given ID. The returned set may be modified by the caller.
+	ConflictSet(inputID ids.ID) ids.Set
+
+	// Returns the processing transactions, in no particular order
+	ProcessingTxs() []Tx
+
+	// Returns how the processing transaction with the given ID has fared in
+	// the polls recorded since it was added. Returns false if the transaction
+	// isn't processing.
+	PollStats(txID ids.ID) (TxPollStats, bool)
+
+	// Returns the IDs of the processing transactions whose confidence hasn't
+	// increased for longer than the MaxItemStalledTime parameter, starting
+	// with the transaction that has been stalled the longest. These
+	// transactions may never gather enough votes to be decided. Returns nil if
+	// MaxItemStalledTime is 0.
+	Stalled() []ids.ID
+
 	// Collects the results of a network poll. Assumes all transactions
 	// have been previously added. Returns true is any statuses or preferences
 	// changed. Returns if a critical error has occurred.
 	RecordPoll(ids.Bag) (bool, error)
 
+	// Collects the results of multiple network polls, in the order that they
+	// finished. The confidence of each transaction is updated for each poll,
+	// but the preferences are only updated once, after all of the polls are
+	// recorded. Returns true if any statuses or preferences changed. Returns if
+	// a critical error has occurred.
+	RecordPolls([]ids.Bag) (bool, error)
+
 	// Returns true iff all remaining transactions are rogue. Note, it is
 	// possible that after returning quiesce, a new decision may be added such
 	// that this instance should no longer quiesce.
@@ -60,12 +95,27 @@ type Consensus interface {
 	// that this instance is no longer finalized.
 	Finalized() bool
 
+	// Writes the processing transactions, the conflicts between them and their
+	// dependencies that aren't accepted to the writer. The format is either
+	// DOTFormat or JSONFormat.
+	ExportGraph(w io.Writer, format string) error
+
+	// Registers a function to call after a transaction is accepted. Functions
+	// are called in the order they were registered.
+	OnAccept(func(Tx))
+
+	// Registers a function to call, with the reason and the ID of the
+	// transaction that caused it, after a transaction is rejected. Functions
+	// are called in the order they were registered.
+	OnReject(func(tx Tx, reason RejectionReason, causeID ids.ID))
+
 	// HealthCheck returns information about the consensus health.
 	HealthCheck() (interface{}, error)
 
 	// Accept the provided tx remove it from the graph
 	accept(txID ids.ID) error
 
-	// Reject all the provided txs and remove them from the graph
-	reject(txIDs ids.Set) error
+	// Reject all the provided txs, because of the reason and the tx that caused
+	// it, and remove them from the graph
+	reject(txIDs ids.Set, reason RejectionReason, causeID ids.ID) error
 }
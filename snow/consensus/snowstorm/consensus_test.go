@@ -4,8 +4,13 @@
 package snowstorm
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -42,6 +47,19 @@ var (
 		ErrorOnRejectingLowerConfidenceConflictTest,
 		ErrorOnRejectingHigherConfidenceConflictTest,
 		UTXOCleanupTest,
+		PollStatsTest,
+		RecordPollsTest,
+		StalledTest,
+		ExportGraphTest,
+		DecisionHooksTest,
+		NumProcessingTest,
+		ConflictSetTest,
+		DecisionThresholdTest,
+		AcceptedHistogramsTest,
+		ReplayTest,
+		DependencyCycleTest,
+		SynchronizedTest,
+		RejectionReceiverTest,
 	}
 
 	Red, Green, Blue, Alpha *TestTx
@@ -1363,6 +1381,90 @@ func UTXOCleanupTest(t *testing.T, factory Factory) {
 	assert.True(t, changed, "should have accepted the blue tx")
 
 	assert.Equal(t, choices.Accepted, Blue.Status())
+
+	// Decided txs, and the inputs they consumed, shouldn't be retained
+	assert.Zero(t, graph.NumProcessing())
+	assert.Empty(t, graph.ProcessingTxs())
+	for _, tx := range []Tx{Red, Green, Blue} {
+		for _, inputID := range tx.InputIDs() {
+			assert.Zero(t, graph.ConflictSet(inputID).Len())
+		}
+	}
+}
+
+func PollStatsTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     3,
+		Alpha:                 2,
+		BetaVirtuous:          2,
+		BetaRogue:             3,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Green)
+	assert.NoError(t, err)
+
+	err = graph.Add(Alpha)
+	assert.NoError(t, err)
+
+	votes := ids.Bag{}
+	votes.AddCount(Red.ID(), 2)
+	votes.AddCount(Green.ID(), 1)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	votes = ids.Bag{}
+	votes.AddCount(Red.ID(), 1)
+	votes.AddCount(Alpha.ID(), 2)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	stats, ok := graph.PollStats(Red.ID())
+	assert.True(t, ok)
+	assert.Equal(t, TxPollStats{
+		Confidence:         0,
+		NumSuccessfulPolls: 1,
+		NumPolls:           2,
+		NumVotes:           3,
+		NumConflicts:       1,
+		Rogue:              true,
+	}, stats)
+
+	stats, ok = graph.PollStats(Green.ID())
+	assert.True(t, ok)
+	assert.Equal(t, TxPollStats{
+		Confidence:         0,
+		NumSuccessfulPolls: 0,
+		NumPolls:           2,
+		NumVotes:           1,
+		NumConflicts:       1,
+		Rogue:              true,
+	}, stats)
+
+	stats, ok = graph.PollStats(Alpha.ID())
+	assert.True(t, ok)
+	assert.Equal(t, TxPollStats{
+		Confidence:         1,
+		NumSuccessfulPolls: 1,
+		NumPolls:           2,
+		NumVotes:           2,
+		NumConflicts:       0,
+		Rogue:              false,
+	}, stats)
+
+	_, ok = graph.PollStats(Blue.ID())
+	assert.False(t, ok, "blue was never added")
 }
 
 func StringTest(t *testing.T, factory Factory, prefix string) {
@@ -1596,3 +1698,760 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 		t.Fatalf("%s should have been rejected", Blue.ID())
 	}
 }
+
+func RecordPollsTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     2,
+		Alpha:                 2,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Green)
+	assert.NoError(t, err)
+
+	updated, err := graph.RecordPolls(nil)
+	assert.NoError(t, err)
+	assert.False(t, updated, "no polls were recorded")
+
+	// Neither poll is successful
+	votes := ids.Bag{}
+	votes.AddCount(Green.ID(), 1)
+	updated, err = graph.RecordPolls([]ids.Bag{votes, votes})
+	assert.NoError(t, err)
+	assert.False(t, updated, "no poll was successful")
+	assert.Equal(t, choices.Processing, Red.Status())
+	assert.Equal(t, choices.Processing, Green.Status())
+
+	stats, ok := graph.PollStats(Green.ID())
+	assert.True(t, ok)
+	assert.Equal(t, 2, stats.NumPolls)
+	assert.Equal(t, 2, stats.NumVotes)
+
+	// The first poll changes the preference to green and the second poll
+	// finalizes green
+	votes = ids.Bag{}
+	votes.AddCount(Green.ID(), 2)
+	updated, err = graph.RecordPolls([]ids.Bag{votes, {}, votes})
+	assert.NoError(t, err)
+	assert.True(t, updated, "should have changed the preference")
+	assert.Equal(t, choices.Processing, Green.Status(), "the empty poll should have reset green's confidence")
+	preferences := graph.Preferences()
+	assert.True(t, preferences.Contains(Green.ID()))
+	assert.False(t, preferences.Contains(Red.ID()))
+
+	updated, err = graph.RecordPolls([]ids.Bag{votes, votes})
+	assert.NoError(t, err)
+	assert.True(t, updated, "should have accepted green")
+	assert.Equal(t, choices.Rejected, Red.Status())
+	assert.Equal(t, choices.Accepted, Green.Status())
+	assert.True(t, graph.Finalized())
+}
+
+func StalledTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     2,
+		Alpha:                 2,
+		BetaVirtuous:          1,
+		BetaRogue:             3,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+		MaxItemStalledTime:    time.Minute,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	setTime := func(now time.Time) {
+		switch graph := graph.(type) {
+		case *Directed:
+			graph.Clock.Set(now)
+		case *Input:
+			graph.Clock.Set(now)
+		default:
+			t.Fatalf("unexpected consensus type %T", graph)
+		}
+	}
+
+	start := time.Now()
+	setTime(start)
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Green)
+	assert.NoError(t, err)
+
+	assert.Empty(t, graph.Stalled())
+
+	setTime(start.Add(30 * time.Second))
+	votes := ids.Bag{}
+	votes.AddCount(Red.ID(), 2)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	// A poll that isn't successful isn't progress
+	votes = ids.Bag{}
+	votes.AddCount(Green.ID(), 1)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	setTime(start.Add(61 * time.Second))
+	assert.Equal(t, []ids.ID{Green.ID()}, graph.Stalled())
+
+	setTime(start.Add(91 * time.Second))
+	assert.Equal(t, []ids.ID{Green.ID(), Red.ID()}, graph.Stalled())
+
+	// Decided txs aren't stalled
+	votes = ids.Bag{}
+	votes.AddCount(Red.ID(), 2)
+	_, err = graph.RecordPolls([]ids.Bag{votes, votes, votes})
+	assert.NoError(t, err)
+	assert.Equal(t, choices.Accepted, Red.Status())
+	assert.Equal(t, choices.Rejected, Green.Status())
+	assert.Empty(t, graph.Stalled())
+}
+
+func ExportGraphTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     2,
+		Alpha:                 2,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	purple := &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(7),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []Tx{Red, Alpha},
+		InputIDsV:     []ids.ID{ids.Empty.Prefix(8)},
+	}
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Green)
+	assert.NoError(t, err)
+
+	err = graph.Add(purple)
+	assert.NoError(t, err)
+
+	votes := ids.Bag{}
+	votes.AddCount(Red.ID(), 2)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	jsonBuf := bytes.Buffer{}
+	err = graph.ExportGraph(&jsonBuf, JSONFormat)
+	assert.NoError(t, err)
+
+	export := GraphExport{}
+	err = json.Unmarshal(jsonBuf.Bytes(), &export)
+	assert.NoError(t, err)
+
+	nodes := make(map[ids.ID]GraphNode)
+	for _, node := range export.Nodes {
+		nodes[node.ID] = node
+	}
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, GraphNode{
+		ID:                 Red.ID(),
+		Confidence:         1,
+		NumSuccessfulPolls: 1,
+		Rogue:              true,
+		Preferred:          true,
+	}, nodes[Red.ID()])
+	assert.Equal(t, GraphNode{
+		ID:    Green.ID(),
+		Rogue: true,
+	}, nodes[Green.ID()])
+	assert.Equal(t, GraphNode{
+		ID:        purple.ID(),
+		Preferred: true,
+	}, nodes[purple.ID()])
+
+	assert.Len(t, export.Conflicts, 1)
+	conflict := export.Conflicts[0]
+	assert.ElementsMatch(t, []ids.ID{Red.ID(), Green.ID()}, []ids.ID{conflict.From, conflict.To})
+
+	assert.ElementsMatch(t, []GraphDependency{
+		{
+			From:   purple.ID(),
+			To:     Red.ID(),
+			Status: choices.Processing,
+		},
+		{
+			From:   purple.ID(),
+			To:     Alpha.ID(),
+			Status: choices.Processing,
+		},
+	}, export.Dependencies)
+
+	dotBuf := bytes.Buffer{}
+	err = graph.ExportGraph(&dotBuf, DOTFormat)
+	assert.NoError(t, err)
+	dot := dotBuf.String()
+	assert.Contains(t, dot, "digraph snowstorm {")
+	assert.Contains(t, dot, fmt.Sprintf("%q -> %q [dir=none, color=red];", conflict.From, conflict.To))
+	assert.Contains(t, dot, fmt.Sprintf("%q -> %q [style=dashed, label=%q];", purple.ID(), Alpha.ID(), choices.Processing))
+
+	err = graph.ExportGraph(&bytes.Buffer{}, "svg")
+	assert.True(t, errors.Is(err, errUnknownGraphFormat))
+}
+
+func DecisionHooksTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     2,
+		Alpha:                 2,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	accepted := []ids.ID(nil)
+	graph.OnAccept(func(tx Tx) {
+		assert.Equal(t, choices.Accepted, tx.Status())
+		accepted = append(accepted, tx.ID())
+	})
+	rejected := make(map[ids.ID]RejectionReason)
+	causes := make(map[ids.ID]ids.ID)
+	graph.OnReject(func(tx Tx, reason RejectionReason, causeID ids.ID) {
+		assert.Equal(t, choices.Rejected, tx.Status())
+		rejected[tx.ID()] = reason
+		causes[tx.ID()] = causeID
+	})
+
+	purple := &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(7),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []Tx{Red},
+		InputIDsV:     []ids.ID{ids.Empty.Prefix(8)},
+	}
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Green)
+	assert.NoError(t, err)
+
+	err = graph.Add(purple)
+	assert.NoError(t, err)
+
+	votes := ids.Bag{}
+	votes.AddCount(Green.ID(), 2)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ids.ID{Green.ID()}, accepted)
+	assert.Equal(t, map[ids.ID]RejectionReason{
+		Red.ID():    ConflictAccepted,
+		purple.ID(): DependencyRejected,
+	}, rejected)
+	assert.Equal(t, map[ids.ID]ids.ID{
+		Red.ID():    Green.ID(),
+		purple.ID(): Red.ID(),
+	}, causes)
+}
+
+func NumProcessingTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+		MaxProcessing:         1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, graph.NumProcessing())
+
+	// The processing limit is enforced by the engine when issuing local txs,
+	// so txs are still added past it
+	err = graph.Add(Alpha)
+	assert.NoError(t, err)
+	assert.True(t, graph.Issued(Alpha))
+	assert.Equal(t, 2, graph.NumProcessing())
+
+	// Txs without inputs are accepted immediately
+	vacuous := &TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.Empty.Prefix(7),
+		StatusV: choices.Processing,
+	}}
+	err = graph.Add(vacuous)
+	assert.NoError(t, err)
+	assert.Equal(t, choices.Accepted, vacuous.Status())
+	assert.Equal(t, 2, graph.NumProcessing())
+
+	votes := ids.Bag{}
+	votes.Add(Red.ID())
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+	assert.Equal(t, choices.Accepted, Red.Status())
+	assert.Equal(t, 1, graph.NumProcessing())
+}
+
+func ConflictSetTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	for _, tx := range []Tx{Red, Green, Blue} {
+		err := graph.Add(tx)
+		assert.NoError(t, err)
+	}
+
+	X := Red.InputIDs()[0]
+	Y := Green.InputIDs()[1]
+	Z := Blue.InputIDs()[1]
+
+	assert.ElementsMatch(t, []ids.ID{Red.ID(), Green.ID()}, graph.ConflictSet(X).List())
+	assert.ElementsMatch(t, []ids.ID{Green.ID(), Blue.ID()}, graph.ConflictSet(Y).List())
+	assert.ElementsMatch(t, []ids.ID{Blue.ID()}, graph.ConflictSet(Z).List())
+	assert.Zero(t, graph.ConflictSet(ids.Empty.Prefix(7)).Len())
+
+	// Modifying the returned set doesn't modify the graph
+	spenders := graph.ConflictSet(X)
+	spenders.Clear()
+	assert.Equal(t, 2, graph.ConflictSet(X).Len())
+
+	assert.ElementsMatch(t, []Tx{Red, Green, Blue}, graph.ProcessingTxs())
+
+	votes := ids.Bag{}
+	votes.Add(Red.ID())
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	// Accepting Red rejects Green, which rejects nothing else because Blue
+	// doesn't depend on Green
+	assert.Equal(t, choices.Accepted, Red.Status())
+	assert.Equal(t, choices.Rejected, Green.Status())
+	assert.Zero(t, graph.ConflictSet(X).Len())
+	assert.ElementsMatch(t, []ids.ID{Blue.ID()}, graph.ConflictSet(Y).List())
+	assert.ElementsMatch(t, []Tx{Blue}, graph.ProcessingTxs())
+}
+
+type thresholdTx struct {
+	*TestTx
+	threshold int
+}
+
+func (t *thresholdTx) DecisionThreshold() int { return t.threshold }
+
+func DecisionThresholdTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	red := &thresholdTx{
+		TestTx:    Red,
+		threshold: 3,
+	}
+	err = graph.Add(red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Alpha)
+	assert.NoError(t, err)
+
+	votes := ids.Bag{}
+	votes.Add(Red.ID(), Alpha.ID())
+	for i := 0; i < 2; i++ {
+		_, err = graph.RecordPoll(votes)
+		assert.NoError(t, err)
+	}
+
+	// Virtuous txs are normally accepted after [BetaVirtuous] polls
+	assert.Equal(t, choices.Accepted, Alpha.Status())
+	assert.Equal(t, choices.Processing, Red.Status())
+
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+	assert.Equal(t, choices.Accepted, Red.Status())
+}
+
+func AcceptedHistogramsTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	registry := prometheus.NewRegistry()
+	params := sbcon.Parameters{
+		Metrics:               registry,
+		K:                     2,
+		Alpha:                 2,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	err = graph.Add(Green)
+	assert.NoError(t, err)
+
+	// An unsuccessful poll still counts towards the number of polls
+	votes := ids.Bag{}
+	votes.Add(Red.ID())
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	votes.Add(Red.ID())
+	for i := 0; i < 2; i++ {
+		_, err = graph.RecordPoll(votes)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, choices.Accepted, Red.Status())
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	sums := make(map[string]float64)
+	for _, family := range families {
+		switch name := family.GetName(); name {
+		case "txs_polls_accepted", "txs_conflicts_accepted":
+			histogram := family.GetMetric()[0].GetHistogram()
+			assert.Equal(t, uint64(1), histogram.GetSampleCount())
+			sums[name] = histogram.GetSampleSum()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"txs_polls_accepted":     3,
+		"txs_conflicts_accepted": 1,
+	}, sums)
+}
+
+func ReplayTest(t *testing.T, factory Factory) {
+	log := bytes.Buffer{}
+	graph := NewRecorder(factory.New(), &log)
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     2,
+		Alpha:                 2,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	purple := &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(7),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []Tx{Blue},
+		InputIDsV:     []ids.ID{ids.Empty.Prefix(8)},
+	}
+	for _, tx := range []Tx{Red, Green, Blue, purple} {
+		err := graph.Add(tx)
+		assert.NoError(t, err)
+	}
+
+	// Txs that aren't added aren't recorded
+	logLen := log.Len()
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+	cyclic := &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(9),
+			StatusV: choices.Processing,
+		},
+		InputIDsV: []ids.ID{ids.Empty.Prefix(10)},
+	}
+	cyclic.DependenciesV = []Tx{cyclic}
+	err = graph.Add(cyclic)
+	assert.Error(t, err)
+	assert.Equal(t, logLen, log.Len())
+
+	votes := ids.Bag{}
+	votes.AddCount(Green.ID(), 2)
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	votes = ids.Bag{}
+	votes.AddCount(Blue.ID(), 2)
+	_, err = graph.RecordPolls([]ids.Bag{votes, votes})
+	assert.NoError(t, err)
+
+	replayed, err := Replay(bytes.NewReader(log.Bytes()), factory, snow.DefaultContextTest())
+	assert.NoError(t, err)
+	assert.Equal(t, graph.Preferences(), replayed.Preferences())
+	assert.Equal(t, graph.Virtuous(), replayed.Virtuous())
+
+	expected := bytes.Buffer{}
+	err = graph.ExportGraph(&expected, JSONFormat)
+	assert.NoError(t, err)
+
+	actual := bytes.Buffer{}
+	err = replayed.ExportGraph(&actual, JSONFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.String(), actual.String())
+
+	// Initializing the graph again starts the replay over
+	err = graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+	err = graph.Add(Red)
+	assert.NoError(t, err)
+
+	replayed, err = Replay(&log, factory, snow.DefaultContextTest())
+	assert.NoError(t, err)
+	assert.Equal(t, graph.Preferences(), replayed.Preferences())
+	assert.Equal(t, 1, replayed.Preferences().Len())
+
+	// A truncated log can't be replayed
+	_, err = Replay(bytes.NewReader([]byte{0, 0, 0, 10, replayParams}), factory, snow.DefaultContextTest())
+	assert.Error(t, err)
+}
+
+func DependencyCycleTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	newTx := func(i uint64) *TestTx {
+		return &TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(i),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{ids.Empty.Prefix(i + 100)},
+		}
+	}
+
+	// tx0 -> tx1 -> tx2 -> tx0
+	tx0 := newTx(10)
+	tx1 := newTx(11)
+	tx2 := newTx(12)
+	tx0.DependenciesV = []Tx{tx1}
+	tx1.DependenciesV = []Tx{tx2}
+	tx2.DependenciesV = []Tx{tx0}
+
+	self := newTx(13)
+	self.DependenciesV = []Tx{self}
+
+	for _, tx := range []Tx{tx0, tx1, tx2, self} {
+		err := graph.Add(tx)
+		assert.True(t, errors.Is(err, ErrDependencyCycle))
+		assert.False(t, graph.Issued(tx))
+	}
+
+	// Accepted dependencies can't be part of a cycle
+	tx2.StatusV = choices.Accepted
+	err = graph.Add(tx1)
+	assert.NoError(t, err)
+	err = graph.Add(tx0)
+	assert.NoError(t, err)
+}
+
+func SynchronizedTest(t *testing.T, factory Factory) {
+	graph := NewSynchronized(factory.New())
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					graph.Preferences()
+					graph.IsVirtuous(Alpha)
+				}
+			}
+		}()
+	}
+
+	for _, tx := range []Tx{Red, Green} {
+		err := graph.Add(tx)
+		assert.NoError(t, err)
+	}
+
+	votes := ids.Bag{}
+	votes.Add(Red.ID())
+	for i := 0; i < 2; i++ {
+		_, err := graph.RecordPoll(votes)
+		assert.NoError(t, err)
+	}
+
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, choices.Accepted, Red.Status())
+	assert.Equal(t, choices.Rejected, Green.Status())
+	assert.True(t, graph.Finalized())
+
+	// Modifying the returned sets doesn't modify the graph
+	err = graph.Add(Blue)
+	assert.NoError(t, err)
+	prefs := graph.Preferences()
+	prefs.Clear()
+	prefs = graph.Preferences()
+	assert.True(t, prefs.Contains(Blue.ID()))
+}
+
+type rejectionReceiverTx struct {
+	*TestTx
+	reason  RejectionReason
+	causeID ids.ID
+}
+
+func (t *rejectionReceiverTx) RejectWithReason(reason RejectionReason, causeID ids.ID) error {
+	t.reason = reason
+	t.causeID = causeID
+	return t.Reject()
+}
+
+func RejectionReceiverTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultContextTest(), params)
+	assert.NoError(t, err)
+
+	red := &rejectionReceiverTx{TestTx: Red}
+	purple := &rejectionReceiverTx{TestTx: &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(7),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []Tx{red},
+		InputIDsV:     []ids.ID{ids.Empty.Prefix(8)},
+	}}
+
+	for _, tx := range []Tx{red, Green, purple} {
+		err := graph.Add(tx)
+		assert.NoError(t, err)
+	}
+
+	votes := ids.Bag{}
+	votes.Add(Green.ID())
+	_, err = graph.RecordPoll(votes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, choices.Rejected, red.Status())
+	assert.Equal(t, ConflictAccepted, red.reason)
+	assert.Equal(t, Green.ID(), red.causeID)
+
+	assert.Equal(t, choices.Rejected, purple.Status())
+	assert.Equal(t, DependencyRejected, purple.reason)
+	assert.Equal(t, red.ID(), purple.causeID)
+}
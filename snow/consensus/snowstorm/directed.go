@@ -4,6 +4,8 @@
 package snowstorm
 
 import (
+	"io"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -26,6 +28,11 @@ type Directed struct {
 	// Value: Node that represents this transaction in the conflict graph
 	txs map[ids.ID]*directedTx
 
+	// Index: Dense index of a transaction
+	// Value: Node that represents this transaction in the conflict graph, or
+	//        nil if the index isn't in use
+	nodes []*directedTx
+
 	// Key: UTXO ID
 	// Value: IDs of transactions that consume the UTXO specified in the key
 	utxos map[ids.ID]ids.Set
@@ -33,6 +40,10 @@ type Directed struct {
 
 type directedTx struct {
 	snowball
+	txVotes
+
+	// index is the dense index of this transaction
+	index int
 
 	// pendingAccept identifies if this transaction has been marked as accepted
 	// once its transitive dependencies have also been accepted
@@ -107,6 +118,22 @@ func (dg *Directed) Conflicts(tx Tx) ids.Set {
 	return conflicts
 }
 
+// ConflictSet implements the Consensus interface
+func (dg *Directed) ConflictSet(inputID ids.ID) ids.Set {
+	var spenders ids.Set
+	spenders.Union(dg.utxos[inputID])
+	return spenders
+}
+
+// ProcessingTxs implements the Consensus interface
+func (dg *Directed) ProcessingTxs() []Tx {
+	txs := make([]Tx, 0, len(dg.txs))
+	for _, txNode := range dg.txs {
+		txs = append(txs, txNode.tx)
+	}
+	return txs
+}
+
 // Add implements the Consensus interface
 func (dg *Directed) Add(tx Tx) error {
 	if shouldVote, err := dg.shouldVote(dg, tx); !shouldVote || err != nil {
@@ -114,7 +141,10 @@ func (dg *Directed) Add(tx Tx) error {
 	}
 
 	txID := tx.ID()
-	txNode := &directedTx{tx: tx}
+	txNode := &directedTx{
+		txVotes: txVotes{addedVote: dg.currentVote},
+		tx:      tx,
+	}
 
 	// For each UTXO consumed by the tx:
 	// * Add edges between this tx and txs that consume this UTXO
@@ -168,7 +198,8 @@ func (dg *Directed) Add(tx Tx) error {
 	}
 
 	// Add this tx to the set of currently processing txs
-	dg.txs[txID] = txNode
+	dg.addNode(txID, txNode)
+	dg.progressed(txID)
 
 	// If a tx that this tx depends on is rejected, this tx should also be
 	// rejected.
@@ -191,57 +222,120 @@ func (dg *Directed) Issued(tx Tx) bool {
 
 // RecordPoll implements the Consensus interface
 func (dg *Directed) RecordPoll(votes ids.Bag) (bool, error) {
-	// Increase the vote ID. This is only updated here and is used to reset the
-	// confidence values of transactions lazily.
-	dg.currentVote++
+	return dg.RecordPolls([]ids.Bag{votes})
+}
 
+// RecordPolls implements the Consensus interface
+func (dg *Directed) RecordPolls(polls []ids.Bag) (bool, error) {
 	// This flag tracks if the Avalanche instance needs to recompute its
 	// frontiers. Frontiers only need to be recalculated if preferences change
 	// or if a tx was accepted.
 	changed := false
 
-	// We only want to iterate over txs that received alpha votes
-	votes.SetThreshold(dg.params.Alpha)
-	// Get the set of IDs that meet this alpha threshold
-	metThreshold := votes.Threshold()
-	for txIDKey := range metThreshold {
-		// Get the node this tx represents
-		txNode, exist := dg.txs[txIDKey]
-		if !exist {
-			// This tx may have already been accepted because of tx
-			// dependencies. If this is the case, we can just drop the vote.
-			continue
+	// The txs that received alpha votes in any of the polls. Their edges are
+	// redirected once all of the polls are recorded.
+	voted := ids.Set{}
+	for _, votes := range polls {
+		// Increase the vote ID. This is only updated here and is used to reset
+		// the confidence values of transactions lazily.
+		dg.currentVote++
+
+		// Track the votes of every processing tx, including the txs that
+		// didn't receive alpha votes. The txs that received alpha votes are
+		// marked by their index, so that they don't need to be looked up
+		// again.
+		dg.metThreshold.Clear()
+		for _, txID := range votes.List() {
+			txNode, exists := dg.txs[txID]
+			if !exists {
+				// This tx may have already been decided. If this is the case,
+				// we can just drop the vote.
+				continue
+			}
+			numVotes := votes.Count(txID)
+			txNode.numVotes += numVotes
+			if numVotes >= dg.params.Alpha {
+				dg.metThreshold.Add(txNode.index)
+			}
 		}
 
-		txNode.RecordSuccessfulPoll(dg.currentVote)
-
-		// If the tx should be accepted, then we should defer its acceptance
-		// until its dependencies are decided. If this tx was already marked to
-		// be accepted, we shouldn't register it again.
-		if !txNode.pendingAccept &&
-			txNode.Finalized(dg.params.BetaVirtuous, dg.params.BetaRogue) {
-			// Mark that this tx is pending acceptance so acceptance is only
-			// registered once.
-			txNode.pendingAccept = true
+		// We only want to iterate over txs that received alpha votes. They are
+		// iterated over in the order of their indices.
+		for i, ok := dg.metThreshold.Next(0); ok; i, ok = dg.metThreshold.Next(i + 1) {
+			// Get the node this tx represents
+			txNode := dg.nodes[i]
+			if txNode == nil {
+				// This tx may have already been accepted because of tx
+				// dependencies. If this is the case, we can just drop the
+				// vote.
+				continue
+			}
+			txID := txNode.tx.ID()
+
+			txNode.RecordSuccessfulPoll(dg.currentVote)
+			dg.progressed(txID)
+
+			// If the tx should be accepted, then we should defer its
+			// acceptance until its dependencies are decided. If this tx was
+			// already marked to be accepted, we shouldn't register it again.
+			betaVirtuous, betaRogue := dg.betas(txNode.tx)
+			if !txNode.pendingAccept &&
+				txNode.Finalized(betaVirtuous, betaRogue) {
+				// Mark that this tx is pending acceptance so acceptance is only
+				// registered once.
+				txNode.pendingAccept = true
+
+				dg.registerAcceptor(dg, txNode.tx)
+				if dg.errs.Errored() {
+					return changed, dg.errs.Err
+				}
+			}
 
-			dg.registerAcceptor(dg, txNode.tx)
-			if dg.errs.Errored() {
-				return changed, dg.errs.Err
+			if txNode.tx.Status() == choices.Accepted {
+				// By accepting a tx, the state of this instance has changed.
+				changed = true
+			} else {
+				voted.Add(txID)
 			}
 		}
+	}
 
-		if txNode.tx.Status() != choices.Accepted {
-			// If this tx wasn't accepted, then this instance is only changed if
-			// preferences changed.
-			changed = dg.redirectEdges(txNode) || changed
-		} else {
-			// By accepting a tx, the state of this instance has changed.
-			changed = true
+	// If a tx wasn't accepted, then this instance is only changed if
+	// preferences changed. The preferences only depend on the number of
+	// successful polls of each tx, so they are updated once for all of the
+	// polls.
+	for txID := range voted {
+		txNode, exists := dg.txs[txID]
+		if !exists {
+			// This tx was decided while recording a later poll.
+			continue
 		}
+		changed = dg.redirectEdges(txNode) || changed
 	}
 	return changed, dg.errs.Err
 }
 
+// PollStats implements the Consensus interface
+func (dg *Directed) PollStats(txID ids.ID) (TxPollStats, bool) {
+	txNode, exists := dg.txs[txID]
+	if !exists {
+		return TxPollStats{}, false
+	}
+	return TxPollStats{
+		Confidence:         txNode.Confidence(dg.currentVote),
+		NumSuccessfulPolls: txNode.numSuccessfulPolls,
+		NumPolls:           dg.currentVote - txNode.addedVote,
+		NumVotes:           txNode.numVotes,
+		NumConflicts:       txNode.ins.Len() + txNode.outs.Len(),
+		Rogue:              txNode.rogue,
+	}, true
+}
+
+// ExportGraph implements the Consensus interface
+func (dg *Directed) ExportGraph(w io.Writer, format string) error {
+	return dg.exportGraph(w, format, dg, dg.ProcessingTxs())
+}
+
 func (dg *Directed) String() string {
 	nodes := make([]*snowballNode, 0, len(dg.txs))
 	for _, txNode := range dg.txs {
@@ -256,9 +350,13 @@ func (dg *Directed) String() string {
 
 // accept the named txID and remove it from the graph
 func (dg *Directed) accept(txID ids.ID) error {
+	if stats, ok := dg.PollStats(txID); ok {
+		dg.observeAccepted(stats)
+	}
+
 	txNode := dg.txs[txID]
 	// We are accepting the tx, so we should remove the node from the graph.
-	delete(dg.txs, txID)
+	dg.removeNode(txID, txNode)
 
 	// This tx is consuming all the UTXOs from its inputs, so we can prune them
 	// all from memory
@@ -272,20 +370,20 @@ func (dg *Directed) accept(txID ids.ID) error {
 	dg.preferences.Remove(txID)
 
 	// Reject all the txs that conflicted with this tx.
-	if err := dg.reject(txNode.ins); err != nil {
+	if err := dg.reject(txNode.ins, ConflictAccepted, txID); err != nil {
 		return err
 	}
 	// While it is typically true that a tx this is being accepted is preferred,
 	// it is possible for this to not be the case. So this is handled for
 	// completeness.
-	if err := dg.reject(txNode.outs); err != nil {
+	if err := dg.reject(txNode.outs, ConflictAccepted, txID); err != nil {
 		return err
 	}
 	return dg.acceptTx(txNode.tx)
 }
 
 // reject all the named txIDs and remove them from the graph
-func (dg *Directed) reject(conflictIDs ids.Set) error {
+func (dg *Directed) reject(conflictIDs ids.Set, reason RejectionReason, causeID ids.ID) error {
 	for conflictKey := range conflictIDs {
 		conflict := dg.txs[conflictKey]
 		// This tx is no longer an option for consuming the UTXOs from its
@@ -311,7 +409,7 @@ func (dg *Directed) reject(conflictIDs ids.Set) error {
 		}
 
 		// We are rejecting the tx, so we should remove it from the graph
-		delete(dg.txs, conflictKey)
+		dg.removeNode(conflictKey, conflict)
 
 		// While it's statistically unlikely that something being rejected is
 		// preferred, it is handled for completion.
@@ -321,7 +419,7 @@ func (dg *Directed) reject(conflictIDs ids.Set) error {
 		dg.removeConflict(conflictKey, conflict.ins)
 		dg.removeConflict(conflictKey, conflict.outs)
 
-		if err := dg.rejectTx(conflict.tx); err != nil {
+		if err := dg.rejectTx(conflict.tx, reason, causeID); err != nil {
 			return err
 		}
 	}
@@ -386,3 +484,21 @@ func (dg *Directed) removeConflict(txIDKey ids.ID, neighborIDs ids.Set) {
 		}
 	}
 }
+
+// addNode adds [txNode] to the processing txs and assigns it an index
+func (dg *Directed) addNode(txID ids.ID, txNode *directedTx) {
+	txNode.index = dg.indices.add()
+	if txNode.index == len(dg.nodes) {
+		dg.nodes = append(dg.nodes, txNode)
+	} else {
+		dg.nodes[txNode.index] = txNode
+	}
+	dg.txs[txID] = txNode
+}
+
+// removeNode removes [txNode] from the processing txs and releases its index
+func (dg *Directed) removeNode(txID ids.ID, txNode *directedTx) {
+	delete(dg.txs, txID)
+	dg.nodes[txNode.index] = nil
+	dg.indices.remove(txNode.index)
+}
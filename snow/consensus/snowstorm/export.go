@@ -0,0 +1,153 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+// Formats that a conflict graph can be exported in
+const (
+	DOTFormat  = "dot"
+	JSONFormat = "json"
+)
+
+var errUnknownGraphFormat = errors.New("unknown graph format")
+
+// GraphExport is a conflict graph, as exported in the JSON format
+type GraphExport struct {
+	// The processing transactions, sorted by ID
+	Nodes []GraphNode `json:"nodes"`
+	// Pairs of processing transactions that conflict
+	Conflicts []GraphEdge `json:"conflicts"`
+	// From a processing transaction to a transaction it depends on that isn't
+	// accepted
+	Dependencies []GraphDependency `json:"dependencies"`
+}
+
+// GraphNode is a processing transaction in an exported conflict graph
+type GraphNode struct {
+	ID                 ids.ID `json:"id"`
+	Confidence         int    `json:"confidence"`
+	NumSuccessfulPolls int    `json:"numSuccessfulPolls"`
+	Rogue              bool   `json:"rogue"`
+	Preferred          bool   `json:"preferred"`
+}
+
+// GraphEdge is a conflict between two transactions in an exported conflict
+// graph
+type GraphEdge struct {
+	From ids.ID `json:"from"`
+	To   ids.ID `json:"to"`
+}
+
+// GraphDependency is a dependency of a transaction in an exported conflict
+// graph
+type GraphDependency struct {
+	From ids.ID `json:"from"`
+	To   ids.ID `json:"to"`
+	// Status of [To]
+	Status choices.Status `json:"status"`
+}
+
+// exportGraph writes the conflict graph of [con], whose processing
+// transactions are [txs], to [w] in [format]
+func (c *common) exportGraph(w io.Writer, format string, con Consensus, txs []Tx) error {
+	sort.Slice(txs, func(i, j int) bool {
+		iID := txs[i].ID()
+		jID := txs[j].ID()
+		return bytes.Compare(iID[:], jID[:]) == -1
+	})
+
+	graph := GraphExport{
+		Nodes:        make([]GraphNode, 0, len(txs)),
+		Conflicts:    []GraphEdge{},
+		Dependencies: []GraphDependency{},
+	}
+	for _, tx := range txs {
+		txID := tx.ID()
+		stats, _ := con.PollStats(txID)
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			ID:                 txID,
+			Confidence:         stats.Confidence,
+			NumSuccessfulPolls: stats.NumSuccessfulPolls,
+			Rogue:              stats.Rogue,
+			Preferred:          c.preferences.Contains(txID),
+		})
+
+		conflicts := con.Conflicts(tx).List()
+		ids.SortIDs(conflicts)
+		for _, conflictID := range conflicts {
+			// Report each conflict once
+			if bytes.Compare(txID[:], conflictID[:]) == -1 {
+				graph.Conflicts = append(graph.Conflicts, GraphEdge{
+					From: txID,
+					To:   conflictID,
+				})
+			}
+		}
+
+		for _, dep := range tx.Dependencies() {
+			// Accepted dependencies can't prevent this tx from being accepted
+			if status := dep.Status(); status != choices.Accepted {
+				graph.Dependencies = append(graph.Dependencies, GraphDependency{
+					From:   txID,
+					To:     dep.ID(),
+					Status: status,
+				})
+			}
+		}
+	}
+
+	switch format {
+	case DOTFormat:
+		return writeDOT(w, &graph)
+	case JSONFormat:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(&graph)
+	default:
+		return fmt.Errorf("%w: %q", errUnknownGraphFormat, format)
+	}
+}
+
+// writeDOT writes [graph] to [w] in the DOT language. Conflicts are red and
+// undirected. Dependencies are dashed and point to the transaction that is
+// depended on.
+func writeDOT(w io.Writer, graph *GraphExport) error {
+	buf := bytes.Buffer{}
+	buf.WriteString("digraph snowstorm {\n")
+	for _, node := range graph.Nodes {
+		style := "solid"
+		if node.Preferred {
+			style = "bold"
+		}
+		fmt.Fprintf(&buf, "\t%q [label=\"%s\\nconfidence: %d\\nsuccessful polls: %d\\nrogue: %t\", style=%s];\n",
+			node.ID,
+			node.ID,
+			node.Confidence,
+			node.NumSuccessfulPolls,
+			node.Rogue,
+			style,
+		)
+	}
+	for _, edge := range graph.Conflicts {
+		fmt.Fprintf(&buf, "\t%q -> %q [dir=none, color=red];\n", edge.From, edge.To)
+	}
+	for _, edge := range graph.Dependencies {
+		fmt.Fprintf(&buf, "\t%q -> %q [style=dashed, label=%q];\n", edge.From, edge.To, edge.Status)
+	}
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
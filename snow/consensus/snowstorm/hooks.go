@@ -0,0 +1,55 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// RejectionReason is why a transaction was rejected
+type RejectionReason uint8
+
+// Reasons that a transaction can be rejected for
+const (
+	// A transaction that conflicts with the rejected transaction was accepted
+	ConflictAccepted RejectionReason = iota
+	// A transaction that the rejected transaction depends on was rejected
+	DependencyRejected
+)
+
+func (r RejectionReason) String() string {
+	switch r {
+	case ConflictAccepted:
+		return "conflict accepted"
+	case DependencyRejected:
+		return "dependency rejected"
+	default:
+		return "unknown rejection reason"
+	}
+}
+
+// OnAccept implements the ConflictGraph interface
+func (c *common) OnAccept(f func(Tx)) {
+	c.onAccept = append(c.onAccept, f)
+}
+
+// OnReject implements the ConflictGraph interface
+func (c *common) OnReject(f func(Tx, RejectionReason, ids.ID)) {
+	c.onReject = append(c.onReject, f)
+}
+
+// accepted notifies the registered accept hooks that [tx] was accepted
+func (c *common) accepted(tx Tx) {
+	for _, f := range c.onAccept {
+		f(tx)
+	}
+}
+
+// rejected notifies the registered reject hooks that [tx] was rejected for
+// [reason], because of [causeID]
+func (c *common) rejected(tx Tx, reason RejectionReason, causeID ids.ID) {
+	for _, f := range c.onReject {
+		f(tx, reason, causeID)
+	}
+}
@@ -0,0 +1,76 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"math/bits"
+)
+
+const wordLen = 64
+
+// txIndices assigns each processing tx a dense index, so that the votes a poll
+// gives each tx can be accumulated in a bitSet rather than in sets keyed by tx
+// IDs. The index of a decided tx is reused.
+type txIndices struct {
+	free []int
+	size int
+}
+
+// add returns an index that isn't in use
+func (t *txIndices) add() int {
+	if numFree := len(t.free); numFree > 0 {
+		index := t.free[numFree-1]
+		t.free = t.free[:numFree-1]
+		return index
+	}
+	index := t.size
+	t.size++
+	return index
+}
+
+// remove marks [index] as no longer in use
+func (t *txIndices) remove(index int) { t.free = append(t.free, index) }
+
+// bitSet is a set of dense indices
+type bitSet []uint64
+
+// Add [index] to the set
+func (b *bitSet) Add(index int) {
+	word := index / wordLen
+	for len(*b) <= word {
+		*b = append(*b, 0)
+	}
+	(*b)[word] |= 1 << uint(index%wordLen)
+}
+
+// Contains returns true if [index] is in the set
+func (b bitSet) Contains(index int) bool {
+	word := index / wordLen
+	return word < len(b) && b[word]&(1<<uint(index%wordLen)) != 0
+}
+
+// Clear removes every index from the set, without releasing its memory
+func (b bitSet) Clear() {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// Next returns the smallest index in the set that is at least [start]. Returns
+// false if there isn't one.
+func (b bitSet) Next(start int) (int, bool) {
+	word := start / wordLen
+	if word >= len(b) {
+		return 0, false
+	}
+	if remaining := b[word] >> uint(start%wordLen); remaining != 0 {
+		return start + bits.TrailingZeros64(remaining), true
+	}
+	for word++; word < len(b); word++ {
+		if b[word] != 0 {
+			return word*wordLen + bits.TrailingZeros64(b[word]), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,45 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxIndicesReuse(t *testing.T) {
+	indices := txIndices{}
+	assert.Equal(t, 0, indices.add())
+	assert.Equal(t, 1, indices.add())
+	assert.Equal(t, 2, indices.add())
+
+	indices.remove(1)
+	assert.Equal(t, 1, indices.add())
+	assert.Equal(t, 3, indices.add())
+}
+
+func TestBitSet(t *testing.T) {
+	set := bitSet{}
+	_, ok := set.Next(0)
+	assert.False(t, ok)
+
+	for _, index := range []int{130, 3, 64, 63} {
+		set.Add(index)
+	}
+	assert.True(t, set.Contains(63))
+	assert.False(t, set.Contains(62))
+	assert.False(t, set.Contains(1000))
+
+	found := []int(nil)
+	for i, ok := set.Next(0); ok; i, ok = set.Next(i + 1) {
+		found = append(found, i)
+	}
+	assert.Equal(t, []int{3, 63, 64, 130}, found)
+
+	set.Clear()
+	_, ok = set.Next(0)
+	assert.False(t, ok)
+	assert.Len(t, set, 3, "clearing shouldn't release memory")
+}
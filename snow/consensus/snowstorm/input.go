@@ -4,6 +4,7 @@
 package snowstorm
 
 import (
+	"io"
 	"math"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -20,7 +21,14 @@ type InputFactory struct{}
 func (InputFactory) New() Consensus { return &Input{} }
 
 // Input is an implementation of a multi-color, non-transitive, snowball
-// instance
+// instance. Rather than linking every transaction to each of its conflicts, as
+// Directed does, Input tracks the transactions consuming each input.
+//
+// Input supports everything Directed supports: virtuous and rogue tracking,
+// batched polls, poll stats, decision hooks, rejection reasons, graph exports
+// and the shared metrics. Both run the same ConsensusTest suite. Neither graph
+// supports epochs, transitions or restrictions, as this tree has no notion of
+// them.
 type Input struct {
 	common
 
@@ -28,6 +36,11 @@ type Input struct {
 	// Value: Node that represents this transaction in the conflict graph
 	txs map[ids.ID]*inputTx
 
+	// Index: Dense index of a transaction
+	// Value: Node that represents this transaction in the conflict graph, or
+	//        nil if the index isn't in use
+	nodes []*inputTx
+
 	// Key: UTXO ID
 	// Value: Node that represents the status of the transactions consuming this
 	//        input
@@ -35,6 +48,11 @@ type Input struct {
 }
 
 type inputTx struct {
+	txVotes
+
+	// index is the dense index of this transaction
+	index int
+
 	// pendingAccept identifies if this transaction has been marked as accepted
 	// once its transitive dependencies have also been accepted
 	pendingAccept bool
@@ -120,6 +138,22 @@ func (ig *Input) Conflicts(tx Tx) ids.Set {
 	return conflicts
 }
 
+// ConflictSet implements the ConflictGraph interface
+func (ig *Input) ConflictSet(inputID ids.ID) ids.Set {
+	var spenders ids.Set
+	spenders.Union(ig.utxos[inputID].spenders)
+	return spenders
+}
+
+// ProcessingTxs implements the ConflictGraph interface
+func (ig *Input) ProcessingTxs() []Tx {
+	txs := make([]Tx, 0, len(ig.txs))
+	for _, txNode := range ig.txs {
+		txs = append(txs, txNode.tx)
+	}
+	return txs
+}
+
 // Add implements the ConflictGraph interface
 func (ig *Input) Add(tx Tx) error {
 	if shouldVote, err := ig.shouldVote(ig, tx); !shouldVote || err != nil {
@@ -127,7 +161,10 @@ func (ig *Input) Add(tx Tx) error {
 	}
 
 	txID := tx.ID()
-	txNode := &inputTx{tx: tx}
+	txNode := &inputTx{
+		txVotes: txVotes{addedVote: ig.currentVote},
+		tx:      tx,
+	}
 
 	// This tx should be added to the virtuous sets and preferred sets if this
 	// tx is virtuous in all of the UTXOs it is trying to consume.
@@ -175,7 +212,8 @@ func (ig *Input) Add(tx Tx) error {
 	}
 
 	// Add this tx to the set of currently processing txs
-	ig.txs[txID] = txNode
+	ig.addNode(txID, txNode)
+	ig.progressed(txID)
 
 	// If a tx that this tx depends on is rejected, this tx should also be
 	// rejected.
@@ -198,152 +236,226 @@ func (ig *Input) Issued(tx Tx) bool {
 
 // RecordPoll implements the ConflictGraph interface
 func (ig *Input) RecordPoll(votes ids.Bag) (bool, error) {
-	// Increase the vote ID. This is only updated here and is used to reset the
-	// confidence values of transactions lazily.
-	ig.currentVote++
+	return ig.RecordPolls([]ids.Bag{votes})
+}
 
+// RecordPolls implements the ConflictGraph interface
+func (ig *Input) RecordPolls(polls []ids.Bag) (bool, error) {
 	// This flag tracks if the Avalanche instance needs to recompute its
 	// frontiers. Frontiers only need to be recalculated if preferences change
 	// or if a tx was accepted.
 	changed := false
 
-	// We only want to iterate over txs that received alpha votes
-	votes.SetThreshold(ig.params.Alpha)
-	// Get the set of IDs that meet this alpha threshold
-	metThreshold := votes.Threshold()
-	for txID := range metThreshold {
-		// Get the node this tx represents
-		txNode, exist := ig.txs[txID]
-		if !exist {
-			// This tx may have already been accepted because of tx
-			// dependencies. If this is the case, we can just drop the vote.
-			continue
+	// The txs whose preference in any of their conflict sets may have changed.
+	// Whether they are preferred in all of their conflict sets is updated once
+	// all of the polls are recorded.
+	updated := ids.Set{}
+	for _, votes := range polls {
+		// Increase the vote ID. This is only updated here and is used to reset
+		// the confidence values of transactions lazily.
+		ig.currentVote++
+
+		// Track the votes of every processing tx, including the txs that
+		// didn't receive alpha votes. The txs that received alpha votes are
+		// marked by their index, so that they don't need to be looked up
+		// again.
+		ig.metThreshold.Clear()
+		for _, txID := range votes.List() {
+			txNode, exists := ig.txs[txID]
+			if !exists {
+				// This tx may have already been decided. If this is the case,
+				// we can just drop the vote.
+				continue
+			}
+			numVotes := votes.Count(txID)
+			txNode.numVotes += numVotes
+			if numVotes >= ig.params.Alpha {
+				ig.metThreshold.Add(txNode.index)
+			}
 		}
 
-		txNode.numSuccessfulPolls++
-		txNode.lastVote = ig.currentVote
-
-		// This tx is preferred if it is preferred in all of its conflict sets
-		preferred := true
-		// This tx is rogue if any of its conflict sets are rogue
-		rogue := false
-		// The confidence of the tx is the minimum confidence of all the input's
-		// conflict sets
-		confidence := math.MaxInt32
-		for _, inputID := range txNode.tx.InputIDs() {
-			utxo := ig.utxos[inputID]
-
-			// If this tx wasn't voted for during the last poll, the confidence
-			// should have been reset during the last poll. So, we reset it now.
-			// Additionally, if a different tx was voted for in the last poll,
-			// the confidence should also be reset.
-			if utxo.lastVote+1 != ig.currentVote || txID != utxo.color {
-				utxo.confidence = 0
+		// We only want to iterate over txs that received alpha votes. They are
+		// iterated over in the order of their indices.
+		for i, ok := ig.metThreshold.Next(0); ok; i, ok = ig.metThreshold.Next(i + 1) {
+			// Get the node this tx represents
+			txNode := ig.nodes[i]
+			if txNode == nil {
+				// This tx may have already been accepted because of tx
+				// dependencies. If this is the case, we can just drop the
+				// vote.
+				continue
 			}
-			utxo.lastVote = ig.currentVote
-
-			// Update the Snowflake counter and preference.
-			utxo.color = txID
-			utxo.confidence++
-
-			// Update the Snowball preference.
-			if txNode.numSuccessfulPolls > utxo.numSuccessfulPolls {
-				// If this node didn't previous prefer this tx, then we need to
-				// update the preferences.
-				if txID != utxo.preference {
-					// If the previous preference lost it's preference in this
-					// input, it can't be preferred in all the inputs.
-					if ig.preferences.Contains(utxo.preference) {
-						ig.preferences.Remove(utxo.preference)
-						// Because there was a change in preferences, Avalanche
-						// will need to recompute its frontiers.
-						changed = true
+			txID := txNode.tx.ID()
+
+			txNode.numSuccessfulPolls++
+			txNode.lastVote = ig.currentVote
+			ig.progressed(txID)
+			updated.Add(txID)
+
+			// This tx is rogue if any of its conflict sets are rogue
+			rogue := false
+			// The confidence of the tx is the minimum confidence of all the
+			// input's conflict sets
+			confidence := math.MaxInt32
+			for _, inputID := range txNode.tx.InputIDs() {
+				utxo := ig.utxos[inputID]
+
+				// If this tx wasn't voted for during the last poll, the
+				// confidence should have been reset during the last poll. So,
+				// we reset it now. Additionally, if a different tx was voted
+				// for in the last poll, the confidence should also be reset.
+				if utxo.lastVote+1 != ig.currentVote || txID != utxo.color {
+					utxo.confidence = 0
+				}
+				utxo.lastVote = ig.currentVote
+
+				// Update the Snowflake counter and preference.
+				utxo.color = txID
+				utxo.confidence++
+
+				// Update the Snowball preference.
+				if txNode.numSuccessfulPolls > utxo.numSuccessfulPolls {
+					// If the previous preference lost its preference in this
+					// input, it may no longer be preferred in all of its
+					// inputs.
+					if txID != utxo.preference {
+						updated.Add(utxo.preference)
+						utxo.preference = txID
 					}
-					utxo.preference = txID
+					utxo.numSuccessfulPolls = txNode.numSuccessfulPolls
 				}
-				utxo.numSuccessfulPolls = txNode.numSuccessfulPolls
-			} else {
-				// This isn't the preferred choice in this conflict set so this
-				// tx isn't be preferred.
-				preferred = false
-			}
 
-			// If this utxo is rogue, the transaction must have at least one
-			// conflict.
-			rogue = rogue || utxo.rogue
+				// If this utxo is rogue, the transaction must have at least
+				// one conflict.
+				rogue = rogue || utxo.rogue
 
-			// The confidence of this tx is the minimum confidence of its
-			// inputs.
-			if confidence > utxo.confidence {
-				confidence = utxo.confidence
+				// The confidence of this tx is the minimum confidence of its
+				// inputs.
+				if confidence > utxo.confidence {
+					confidence = utxo.confidence
+				}
+
+				// The input isn't a pointer, so it must be written back.
+				ig.utxos[inputID] = utxo
 			}
 
-			// The input isn't a pointer, so it must be written back.
-			ig.utxos[inputID] = utxo
-		}
+			// If the tx should be accepted, then we should defer its
+			// acceptance until its dependencies are decided. If this tx was
+			// already marked to be accepted, we shouldn't register it again.
+			betaVirtuous, betaRogue := ig.betas(txNode.tx)
+			if !txNode.pendingAccept &&
+				((!rogue && confidence >= betaVirtuous) ||
+					confidence >= betaRogue) {
+				// Mark that this tx is pending acceptance so acceptance is only
+				// registered once.
+				txNode.pendingAccept = true
+
+				ig.registerAcceptor(ig, txNode.tx)
+				if ig.errs.Errored() {
+					return changed, ig.errs.Err
+				}
+			}
 
-		// If this tx is preferred and it isn't already marked as such, mark the
-		// tx as preferred and for Avalanche to recompute the frontiers.
-		if preferred && !ig.preferences.Contains(txID) {
-			ig.preferences.Add(txID)
-			changed = true
+			if txNode.tx.Status() == choices.Accepted {
+				// By accepting a tx, the state of this instance has changed.
+				changed = true
+			}
 		}
+	}
 
-		// If the tx should be accepted, then we should defer its acceptance
-		// until its dependencies are decided. If this tx was already marked to
-		// be accepted, we shouldn't register it again.
-		if !txNode.pendingAccept &&
-			((!rogue && confidence >= ig.params.BetaVirtuous) ||
-				confidence >= ig.params.BetaRogue) {
-			// Mark that this tx is pending acceptance so acceptance is only
-			// registered once.
-			txNode.pendingAccept = true
-
-			ig.registerAcceptor(ig, txNode.tx)
-			if ig.errs.Errored() {
-				return changed, ig.errs.Err
+	// A tx is preferred if it is preferred in all of its conflict sets. If the
+	// preferred txs change, Avalanche will need to recompute its frontiers.
+	for txID := range updated {
+		txNode, exists := ig.txs[txID]
+		if !exists {
+			// This tx was decided while recording the polls.
+			continue
+		}
+		preferred := true
+		for _, inputID := range txNode.tx.InputIDs() {
+			if ig.utxos[inputID].preference != txID {
+				preferred = false
+				break
 			}
 		}
-
-		if txNode.tx.Status() == choices.Accepted {
-			// By accepting a tx, the state of this instance has changed.
-			changed = true
+		if preferred == ig.preferences.Contains(txID) {
+			continue
+		}
+		if preferred {
+			ig.preferences.Add(txID)
+		} else {
+			ig.preferences.Remove(txID)
 		}
+		changed = true
 	}
 	return changed, ig.errs.Err
 }
 
+// PollStats implements the ConflictGraph interface
+func (ig *Input) PollStats(txID ids.ID) (TxPollStats, bool) {
+	txNode, exists := ig.txs[txID]
+	if !exists {
+		return TxPollStats{}, false
+	}
+
+	// The tx is rogue if any of its conflict sets are rogue
+	rogue := false
+	for _, inputID := range txNode.tx.InputIDs() {
+		rogue = rogue || ig.utxos[inputID].rogue
+	}
+	return TxPollStats{
+		Confidence:         ig.confidence(txNode),
+		NumSuccessfulPolls: txNode.numSuccessfulPolls,
+		NumPolls:           ig.currentVote - txNode.addedVote,
+		NumVotes:           txNode.numVotes,
+		NumConflicts:       ig.Conflicts(txNode.tx).Len(),
+		Rogue:              rogue,
+	}, true
+}
+
+// ExportGraph implements the ConflictGraph interface
+func (ig *Input) ExportGraph(w io.Writer, format string) error {
+	return ig.exportGraph(w, format, ig, ig.ProcessingTxs())
+}
+
 func (ig *Input) String() string {
 	nodes := make([]*snowballNode, 0, len(ig.txs))
 	for _, tx := range ig.txs {
-		txID := tx.tx.ID()
-
-		confidence := ig.params.BetaRogue
-		for _, inputID := range tx.tx.InputIDs() {
-			input := ig.utxos[inputID]
-			if input.lastVote != ig.currentVote || txID != input.color {
-				confidence = 0
-				break
-			}
-			if input.confidence < confidence {
-				confidence = input.confidence
-			}
-		}
-
 		nodes = append(nodes, &snowballNode{
-			txID:               txID,
+			txID:               tx.tx.ID(),
 			numSuccessfulPolls: tx.numSuccessfulPolls,
-			confidence:         confidence,
+			confidence:         ig.confidence(tx),
 		})
 	}
 	return ConsensusString("IG", nodes)
 }
 
+// confidence returns the minimum confidence of the tx in its conflict sets as
+// of the last poll
+func (ig *Input) confidence(tx *inputTx) int {
+	txID := tx.tx.ID()
+	_, confidence := ig.betas(tx.tx)
+	for _, inputID := range tx.tx.InputIDs() {
+		input := ig.utxos[inputID]
+		if input.lastVote != ig.currentVote || txID != input.color {
+			return 0
+		}
+		if input.confidence < confidence {
+			confidence = input.confidence
+		}
+	}
+	return confidence
+}
+
 // accept the named txID and remove it from the graph
 func (ig *Input) accept(txID ids.ID) error {
+	if stats, ok := ig.PollStats(txID); ok {
+		ig.observeAccepted(stats)
+	}
+
 	txNode := ig.txs[txID]
 	// We are accepting the tx, so we should remove the node from the graph.
-	delete(ig.txs, txID)
+	ig.removeNode(txID, txNode)
 
 	// Get the conflicts of this tx so that we can reject them
 	conflicts := ig.Conflicts(txNode.tx)
@@ -360,19 +472,19 @@ func (ig *Input) accept(txID ids.ID) error {
 	ig.preferences.Remove(txID)
 
 	// Reject all the txs that conflicted with this tx.
-	if err := ig.reject(conflicts); err != nil {
+	if err := ig.reject(conflicts, ConflictAccepted, txID); err != nil {
 		return err
 	}
 	return ig.acceptTx(txNode.tx)
 }
 
 // reject all the named txIDs and remove them from their conflict sets
-func (ig *Input) reject(conflictIDs ids.Set) error {
+func (ig *Input) reject(conflictIDs ids.Set, reason RejectionReason, causeID ids.ID) error {
 	for conflictKey := range conflictIDs {
 		conflict := ig.txs[conflictKey]
 
 		// We are rejecting the tx, so we should remove it from the graph
-		delete(ig.txs, conflictKey)
+		ig.removeNode(conflictKey, conflict)
 
 		// While it's statistically unlikely that something being rejected is
 		// preferred, it is handled for completion.
@@ -381,7 +493,7 @@ func (ig *Input) reject(conflictIDs ids.Set) error {
 		// Remove this tx from all the conflict sets it's currently in
 		ig.removeConflict(conflictKey, conflict.tx.InputIDs())
 
-		if err := ig.rejectTx(conflict.tx); err != nil {
+		if err := ig.rejectTx(conflict.tx, reason, causeID); err != nil {
 			return err
 		}
 	}
@@ -458,3 +570,21 @@ func (ig *Input) removeConflict(txID ids.ID, inputIDs []ids.ID) {
 		}
 	}
 }
+
+// addNode adds [txNode] to the processing txs and assigns it an index
+func (ig *Input) addNode(txID ids.ID, txNode *inputTx) {
+	txNode.index = ig.indices.add()
+	if txNode.index == len(ig.nodes) {
+		ig.nodes = append(ig.nodes, txNode)
+	} else {
+		ig.nodes[txNode.index] = txNode
+	}
+	ig.txs[txID] = txNode
+}
+
+// removeNode removes [txNode] from the processing txs and releases its index
+func (ig *Input) removeNode(txID ids.ID, txNode *inputTx) {
+	delete(ig.txs, txID)
+	ig.nodes[txNode.index] = nil
+	ig.indices.remove(txNode.index)
+}
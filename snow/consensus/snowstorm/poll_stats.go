@@ -0,0 +1,116 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"math"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+// TxPollStats describes how a processing transaction has fared in the polls
+// recorded since it was added
+type TxPollStats struct {
+	// Number of consecutive successful polls the tx is currently in
+	Confidence int
+	// Number of polls the tx received at least [alpha] votes in
+	NumSuccessfulPolls int
+	// Number of polls recorded since the tx was added
+	NumPolls int
+	// Total number of votes the tx received in those polls
+	NumVotes int
+	// Number of processing txs that conflict with the tx
+	NumConflicts int
+	// True if the tx has a known conflict
+	Rogue bool
+}
+
+// Beta returns the number of consecutive successful polls needed to accept the
+// tx
+func (s TxPollStats) Beta(params sbcon.Parameters) int {
+	if s.Rogue {
+		return params.BetaRogue
+	}
+	return params.BetaVirtuous
+}
+
+// RemainingPolls returns the number of consecutive successful polls the tx
+// still needs to be accepted
+func (s TxPollStats) RemainingPolls(params sbcon.Parameters) int {
+	remaining := s.Beta(params) - s.Confidence
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// VoteFraction returns the average portion of each poll's [k] sampled
+// validators that voted for the tx. Validators are sampled by stake, so this
+// estimates the portion of stake that prefers the tx. The estimate is smoothed
+// so that a tx that hasn't been polled yet is given even odds.
+func (s TxPollStats) VoteFraction(params sbcon.Parameters) float64 {
+	return float64(s.NumVotes+1) / float64(s.NumPolls*params.K+2)
+}
+
+// PollSuccessProbability returns the estimated probability that the next poll
+// gives the tx at least [alpha] of its [k] votes
+func (s TxPollStats) PollSuccessProbability(params sbcon.Parameters) float64 {
+	return binomialTail(params.K, params.Alpha, s.VoteFraction(params))
+}
+
+// AcceptanceProbability returns the estimated probability that the next
+// [RemainingPolls] polls are all successful, which would accept the tx
+func (s TxPollStats) AcceptanceProbability(params sbcon.Parameters) float64 {
+	p := s.PollSuccessProbability(params)
+	return math.Pow(p, float64(s.RemainingPolls(params)))
+}
+
+// ExpectedPolls returns the expected number of polls until the tx has
+// [RemainingPolls] consecutive successful polls. Returns +Inf if the tx is
+// never expected to be accepted.
+func (s TxPollStats) ExpectedPolls(params sbcon.Parameters) float64 {
+	remaining := float64(s.RemainingPolls(params))
+	p := s.PollSuccessProbability(params)
+	switch {
+	case remaining == 0:
+		return 0
+	case p >= 1:
+		return remaining
+	case p <= 0:
+		return math.Inf(1)
+	}
+	// Expected number of trials until [remaining] consecutive successes
+	pr := math.Pow(p, remaining)
+	return (1 - pr) / ((1 - p) * pr)
+}
+
+// binomialTail returns the probability that at least [min] of [n] independent
+// trials succeed, when each trial succeeds with probability [p]
+func binomialTail(n, min int, p float64) float64 {
+	if min <= 0 {
+		return 1
+	}
+	if min > n {
+		return 0
+	}
+	total := 0.
+	coefficient := 1. // n choose i
+	for i := 0; i <= n; i++ {
+		if i >= min {
+			total += coefficient * math.Pow(p, float64(i)) * math.Pow(1-p, float64(n-i))
+		}
+		coefficient = coefficient * float64(n-i) / float64(i+1)
+	}
+	return math.Min(total, 1)
+}
+
+// txVotes tracks the votes a processing tx has received
+type txVotes struct {
+	// addedVote is the value of [currentVote] when the tx was added
+	addedVote int
+
+	// numVotes is the total number of votes the tx received in the polls
+	// recorded since it was added
+	numVotes int
+}
@@ -0,0 +1,69 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+func TestBinomialTail(t *testing.T) {
+	assert.Equal(t, 1., binomialTail(5, 0, .3))
+	assert.Equal(t, 0., binomialTail(5, 6, .3))
+	assert.InDelta(t, 1., binomialTail(5, 5, 1), 1e-9)
+	assert.InDelta(t, 0., binomialTail(5, 1, 0), 1e-9)
+	// P(X >= 2) for X ~ Binomial(3, 1/2) = (3 + 1) / 8
+	assert.InDelta(t, .5, binomialTail(3, 2, .5), 1e-9)
+	// P(X >= 1) for X ~ Binomial(4, 1/4) = 1 - (3/4)^4
+	assert.InDelta(t, 1-math.Pow(.75, 4), binomialTail(4, 1, .25), 1e-9)
+}
+
+func TestTxPollStatsEstimates(t *testing.T) {
+	params := sbcon.Parameters{
+		K:            3,
+		Alpha:        2,
+		BetaVirtuous: 2,
+		BetaRogue:    4,
+	}
+
+	unpolled := TxPollStats{}
+	assert.Equal(t, 2, unpolled.RemainingPolls(params))
+	assert.InDelta(t, .5, unpolled.VoteFraction(params), 1e-9)
+	assert.InDelta(t, .5, unpolled.PollSuccessProbability(params), 1e-9)
+	assert.InDelta(t, .25, unpolled.AcceptanceProbability(params), 1e-9)
+	// Expected trials until 2 consecutive successes with p = 1/2 is 6
+	assert.InDelta(t, 6, unpolled.ExpectedPolls(params), 1e-9)
+
+	rogue := TxPollStats{
+		Confidence: 1,
+		NumPolls:   10,
+		NumVotes:   25,
+		Rogue:      true,
+	}
+	assert.Equal(t, 4, rogue.Beta(params))
+	assert.Equal(t, 3, rogue.RemainingPolls(params))
+	assert.InDelta(t, 26./32, rogue.VoteFraction(params), 1e-9)
+	p := rogue.PollSuccessProbability(params)
+	assert.InDelta(t, math.Pow(p, 3), rogue.AcceptanceProbability(params), 1e-9)
+	assert.Greater(t, p, unpolled.PollSuccessProbability(params), "more votes should make a successful poll more likely")
+
+	finalized := TxPollStats{
+		Confidence: 2,
+		NumPolls:   2,
+		NumVotes:   6,
+	}
+	assert.Equal(t, 0, finalized.RemainingPolls(params))
+	assert.Equal(t, 1., finalized.AcceptanceProbability(params))
+	assert.Equal(t, 0., finalized.ExpectedPolls(params))
+
+	ignored := TxPollStats{
+		NumPolls: 1000,
+	}
+	assert.Less(t, ignored.AcceptanceProbability(params), 1e-6)
+	assert.Greater(t, ignored.ExpectedPolls(params), 1e6)
+}
@@ -0,0 +1,380 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+// Types of the entries in a replay log
+const (
+	replayParams byte = iota
+	replayAdd
+	replayPoll
+)
+
+// Max size of an entry in a replay log, not including its length prefix
+const maxReplayEntrySize = 1 << 24
+
+var (
+	errUnknownReplayEntry  = errors.New("unknown replay log entry")
+	errReplayEntryTooLarge = errors.New("replay log entry is too large")
+	errReplayMissingParams = errors.New("replay log doesn't start with the consensus parameters")
+	errReplayTrailingBytes = errors.New("replay log entry has trailing bytes")
+	errRecordedParams      = errors.New("parameters of a recorded conflict graph can't be updated")
+
+	_ Factory   = RecorderFactory{}
+	_ Consensus = &Recorder{}
+)
+
+// RecorderFactory implements Factory by returning Recorders of the instances
+// returned by [Factory]. The Recorders record to [W], so only one of them
+// should be in use at a time.
+type RecorderFactory struct {
+	Factory Factory
+	W       io.Writer
+}
+
+// New implements Factory
+func (f RecorderFactory) New() Consensus { return NewRecorder(f.Factory.New(), f.W) }
+
+// Recorder is a Consensus that appends the parameters it is initialized with,
+// the txs that are added to it, and the inputs of every call to RecordPoll, to
+// a replay log. Each entry of the log is timestamped. The graph can be
+// reconstructed from the log with Replay.
+//
+// Calls to Add that don't add the tx, because it was already issued or is
+// invalid, aren't recorded. Each time the Recorder is initialized, a new set
+// of parameters is recorded, and Replay initializes the graph again.
+//
+// If writing to the log fails, recording stops but consensus continues.
+type Recorder struct {
+	Consensus
+
+	// Clock gives the time that entries are recorded at
+	Clock timer.Clock
+
+	log    logging.Logger
+	w      io.Writer
+	failed bool
+}
+
+// NewRecorder returns a Recorder that records the use of [con] to [w]
+func NewRecorder(con Consensus, w io.Writer) *Recorder {
+	return &Recorder{
+		Consensus: con,
+		w:         w,
+	}
+}
+
+// Initialize implements the Consensus interface
+func (r *Recorder) Initialize(ctx *snow.Context, params sbcon.Parameters) error {
+	r.log = ctx.Log
+
+	p := r.newEntry(replayParams)
+	p.PackStr(params.Namespace)
+	for _, param := range []int{
+		params.K,
+		params.Alpha,
+		params.BetaVirtuous,
+		params.BetaRogue,
+		params.ConcurrentRepolls,
+		params.OptimalProcessing,
+		params.MaxOutstandingItems,
+		params.MaxProcessing,
+	} {
+		p.PackInt(uint32(param))
+	}
+	p.PackLong(uint64(params.MaxItemProcessingTime))
+	p.PackLong(uint64(params.MaxItemStalledTime))
+	r.record(p)
+
+	return r.Consensus.Initialize(ctx, params)
+}
+
+// UpdateParameters implements the Consensus interface. A replay log holds one
+// set of parameters, so the parameters can't be updated while recording.
+func (r *Recorder) UpdateParameters(sbcon.Parameters) error {
+	return errRecordedParams
+}
+
+// Add implements the Consensus interface
+func (r *Recorder) Add(tx Tx) error {
+	issued := r.Consensus.Issued(tx)
+
+	// The entry is packed before the tx is added, as adding it may decide it
+	p := r.newEntry(replayAdd)
+	txID := tx.ID()
+	p.PackFixedBytes(txID[:])
+	inputIDs := tx.InputIDs()
+	p.PackInt(uint32(len(inputIDs)))
+	for _, inputID := range inputIDs {
+		p.PackFixedBytes(inputID[:])
+	}
+	// Accepted dependencies don't affect consensus, so they aren't recorded
+	depIDs := []ids.ID(nil)
+	for _, dep := range tx.Dependencies() {
+		if dep.Status() != choices.Accepted {
+			depIDs = append(depIDs, dep.ID())
+		}
+	}
+	p.PackInt(uint32(len(depIDs)))
+	for _, depID := range depIDs {
+		p.PackFixedBytes(depID[:])
+	}
+
+	if err := r.Consensus.Add(tx); err != nil || issued {
+		return err
+	}
+	r.record(p)
+	return nil
+}
+
+// RecordPoll implements the Consensus interface
+func (r *Recorder) RecordPoll(votes ids.Bag) (bool, error) {
+	r.recordPoll(votes)
+	return r.Consensus.RecordPoll(votes)
+}
+
+// RecordPolls implements the Consensus interface
+func (r *Recorder) RecordPolls(polls []ids.Bag) (bool, error) {
+	for _, votes := range polls {
+		r.recordPoll(votes)
+	}
+	return r.Consensus.RecordPolls(polls)
+}
+
+func (r *Recorder) recordPoll(votes ids.Bag) {
+	p := r.newEntry(replayPoll)
+	voteIDs := votes.List()
+	p.PackInt(uint32(len(voteIDs)))
+	for _, voteID := range voteIDs {
+		p.PackFixedBytes(voteID[:])
+		p.PackInt(uint32(votes.Count(voteID)))
+	}
+	r.record(p)
+}
+
+// newEntry returns a packer that an entry of type [entryType] can be packed
+// into. The entry is prefixed by its length once it is recorded.
+func (r *Recorder) newEntry(entryType byte) *wrappers.Packer {
+	p := &wrappers.Packer{MaxSize: wrappers.IntLen + maxReplayEntrySize}
+	p.PackInt(0) // Replaced by the length of the entry
+	p.PackByte(entryType)
+	p.PackLong(uint64(r.Clock.Time().UnixNano()))
+	return p
+}
+
+// record the entry packed in [p] to the log
+func (r *Recorder) record(p *wrappers.Packer) {
+	if r.failed {
+		return
+	}
+
+	err := p.Err
+	if err == nil {
+		binary.BigEndian.PutUint32(p.Bytes, uint32(p.Offset-wrappers.IntLen))
+		_, err = r.w.Write(p.Bytes[:p.Offset])
+	}
+	if err != nil {
+		r.failed = true
+		r.log.Warn("stopped recording the consensus replay log due to %s", err)
+	}
+}
+
+// Replay reconstructs the graph recorded in the replay log read from [r]. The
+// graph is a new instance from [factory], initialized with [ctx] and the
+// recorded parameters. The recorded calls to Add and RecordPoll are made, in
+// order, while the clock of the instance is set to the time they were
+// recorded at. The added transactions are TestTxs. If the log has multiple
+// sets of parameters, the graph is initialized again with each of them, and
+// the transactions added before are forgotten.
+//
+// Dependencies that weren't added before the transactions that depend on them
+// are replaced by processing transactions that are never added.
+func Replay(r io.Reader, factory Factory, ctx *snow.Context) (Consensus, error) {
+	var (
+		con Consensus
+		txs = make(map[ids.ID]*TestTx)
+	)
+	for {
+		entry, err := readReplayEntry(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p := wrappers.Packer{Bytes: entry}
+		entryType := p.UnpackByte()
+		timestamp := time.Unix(0, int64(p.UnpackLong()))
+		if p.Errored() {
+			return nil, p.Err
+		}
+
+		if entryType != replayParams && con == nil {
+			return nil, errReplayMissingParams
+		}
+
+		switch entryType {
+		case replayParams:
+			params := sbcon.Parameters{
+				Namespace: p.UnpackStr(),
+				Metrics:   prometheus.NewRegistry(),
+			}
+			for _, param := range []*int{
+				&params.K,
+				&params.Alpha,
+				&params.BetaVirtuous,
+				&params.BetaRogue,
+				&params.ConcurrentRepolls,
+				&params.OptimalProcessing,
+				&params.MaxOutstandingItems,
+				&params.MaxProcessing,
+			} {
+				*param = int(p.UnpackInt())
+			}
+			params.MaxItemProcessingTime = time.Duration(p.UnpackLong())
+			params.MaxItemStalledTime = time.Duration(p.UnpackLong())
+			if err := replayEntryErr(&p); err != nil {
+				return nil, err
+			}
+
+			if con == nil {
+				con = factory.New()
+			}
+			txs = make(map[ids.ID]*TestTx)
+			setTime(con, timestamp)
+			if err := con.Initialize(ctx, params); err != nil {
+				return nil, err
+			}
+		case replayAdd:
+			tx := &TestTx{
+				TestDecidable: choices.TestDecidable{
+					IDV:     unpackID(&p),
+					StatusV: choices.Processing,
+				},
+			}
+			numInputs := p.UnpackInt()
+			for i := uint32(0); i < numInputs && !p.Errored(); i++ {
+				tx.InputIDsV = append(tx.InputIDsV, unpackID(&p))
+			}
+			numDeps := p.UnpackInt()
+			for i := uint32(0); i < numDeps && !p.Errored(); i++ {
+				depID := unpackID(&p)
+				dep, exists := txs[depID]
+				if !exists {
+					dep = &TestTx{TestDecidable: choices.TestDecidable{
+						IDV:     depID,
+						StatusV: choices.Processing,
+					}}
+				}
+				tx.DependenciesV = append(tx.DependenciesV, dep)
+			}
+			if err := replayEntryErr(&p); err != nil {
+				return nil, err
+			}
+
+			// The same tx may be added multiple times
+			if existing, exists := txs[tx.ID()]; exists {
+				tx = existing
+			}
+			txs[tx.ID()] = tx
+
+			setTime(con, timestamp)
+			if err := con.Add(tx); err != nil {
+				return nil, err
+			}
+		case replayPoll:
+			votes := ids.Bag{}
+			numVoteIDs := p.UnpackInt()
+			for i := uint32(0); i < numVoteIDs && !p.Errored(); i++ {
+				voteID := unpackID(&p)
+				votes.AddCount(voteID, int(p.UnpackInt()))
+			}
+			if err := replayEntryErr(&p); err != nil {
+				return nil, err
+			}
+
+			setTime(con, timestamp)
+			if _, err := con.RecordPoll(votes); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: type %d", errUnknownReplayEntry, entryType)
+		}
+	}
+	if con == nil {
+		return nil, errReplayMissingParams
+	}
+	return con, nil
+}
+
+// readReplayEntry returns the next entry of the replay log read from [r].
+// Returns io.EOF if there are no more entries.
+func readReplayEntry(r io.Reader) ([]byte, error) {
+	lenBytes := [wrappers.IntLen]byte{}
+	if _, err := io.ReadFull(r, lenBytes[:]); err != nil {
+		return nil, err
+	}
+	entryLen := binary.BigEndian.Uint32(lenBytes[:])
+	if entryLen > maxReplayEntrySize {
+		return nil, fmt.Errorf("%w: %d bytes", errReplayEntryTooLarge, entryLen)
+	}
+
+	entry := make([]byte, entryLen)
+	if _, err := io.ReadFull(r, entry); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// replayEntryErr returns an error if [p] failed to unpack an entry or didn't
+// unpack all of it
+func replayEntryErr(p *wrappers.Packer) error {
+	switch {
+	case p.Errored():
+		return p.Err
+	case p.Offset != len(p.Bytes):
+		return errReplayTrailingBytes
+	default:
+		return nil
+	}
+}
+
+func unpackID(p *wrappers.Packer) ids.ID {
+	id := ids.ID{}
+	copy(id[:], p.UnpackFixedBytes(hashing.HashLen))
+	return id
+}
+
+// setTime sets the clock of [con] to [t], if [con] has a clock
+func setTime(con Consensus, t time.Time) {
+	switch con := con.(type) {
+	case *Directed:
+		con.Clock.Set(t)
+	case *Input:
+		con.Clock.Set(t)
+	}
+}
@@ -0,0 +1,213 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"io"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+
+	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+var _ Consensus = &synchronized{}
+
+// synchronized guards a Consensus with a RWMutex
+type synchronized struct {
+	lock sync.RWMutex
+	con  Consensus
+}
+
+// NewSynchronized returns a Consensus that can be used from multiple
+// goroutines. Each method of [con] is called while holding a lock; methods that
+// don't modify the graph share the lock. Returned sets are copies.
+//
+// The functions registered with OnAccept and OnReject are called while the
+// lock is held, so they must not call the returned Consensus.
+func NewSynchronized(con Consensus) Consensus {
+	return &synchronized{con: con}
+}
+
+func (s *synchronized) String() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.String()
+}
+
+func (s *synchronized) Initialize(ctx *snow.Context, params sbcon.Parameters) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.Initialize(ctx, params)
+}
+
+func (s *synchronized) Parameters() sbcon.Parameters {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.Parameters()
+}
+
+func (s *synchronized) UpdateParameters(params sbcon.Parameters) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.UpdateParameters(params)
+}
+
+func (s *synchronized) IsVirtuous(tx Tx) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.IsVirtuous(tx)
+}
+
+func (s *synchronized) Add(tx Tx) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.Add(tx)
+}
+
+func (s *synchronized) Issued(tx Tx) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.Issued(tx)
+}
+
+func (s *synchronized) NumProcessing() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.NumProcessing()
+}
+
+func (s *synchronized) Virtuous() ids.Set {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return copySet(s.con.Virtuous())
+}
+
+func (s *synchronized) Preferences() ids.Set {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return copySet(s.con.Preferences())
+}
+
+func (s *synchronized) Conflicts(tx Tx) ids.Set {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return copySet(s.con.Conflicts(tx))
+}
+
+func (s *synchronized) ConflictSet(inputID ids.ID) ids.Set {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.ConflictSet(inputID)
+}
+
+func (s *synchronized) ProcessingTxs() []Tx {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.ProcessingTxs()
+}
+
+func (s *synchronized) PollStats(txID ids.ID) (TxPollStats, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.PollStats(txID)
+}
+
+func (s *synchronized) Stalled() []ids.ID {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.Stalled()
+}
+
+func (s *synchronized) RecordPoll(votes ids.Bag) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.RecordPoll(votes)
+}
+
+func (s *synchronized) RecordPolls(polls []ids.Bag) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.RecordPolls(polls)
+}
+
+func (s *synchronized) Quiesce() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.Quiesce()
+}
+
+func (s *synchronized) Finalized() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.Finalized()
+}
+
+func (s *synchronized) ExportGraph(w io.Writer, format string) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.ExportGraph(w, format)
+}
+
+func (s *synchronized) OnAccept(f func(Tx)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.con.OnAccept(f)
+}
+
+func (s *synchronized) OnReject(f func(Tx, RejectionReason, ids.ID)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.con.OnReject(f)
+}
+
+func (s *synchronized) HealthCheck() (interface{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.con.HealthCheck()
+}
+
+func (s *synchronized) accept(txID ids.ID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.accept(txID)
+}
+
+func (s *synchronized) reject(txIDs ids.Set, reason RejectionReason, causeID ids.ID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.con.reject(txIDs, reason, causeID)
+}
+
+func copySet(set ids.Set) ids.Set {
+	copied := ids.NewSet(set.Len())
+	copied.Union(set)
+	return copied
+}
@@ -25,6 +25,13 @@ type Tx interface {
 	//
 	// In the context of a UTXO-based payments system, for example, this would
 	// be the IDs of the UTXOs consumed by this transaction
+	//
+	// Two transactions conflict iff they share an input ID, so these IDs are
+	// how a VM defines its conflict rules. The IDs don't need to refer to
+	// UTXOs. For example, an account based VM could derive an ID from an
+	// account and nonce, so that only one transaction can use each nonce, and
+	// a name registry could derive an ID from each name a transaction
+	// registers.
 	InputIDs() []ids.ID
 
 	// Verify that the state transition this transaction would make if it were
@@ -41,3 +48,29 @@ type Tx interface {
 	// able to parse these bytes to the same transaction.
 	Bytes() []byte
 }
+
+// ThresholdTx is a Tx that requires more confidence than the BetaRogue
+// parameter to be accepted. For example, a VM may require large transfers to
+// be finalized with a higher confidence. Implementing ThresholdTx is optional.
+type ThresholdTx interface {
+	Tx
+
+	// DecisionThreshold returns the number of consecutive successful polls
+	// this transaction must receive to be accepted, whether or not it has
+	// conflicts. A threshold that isn't greater than the BetaRogue parameter
+	// is ignored.
+	DecisionThreshold() int
+}
+
+// RejectionReceiver is a Tx that is told why it was rejected, so that the
+// reason can be reported to its issuer. Consensus calls RejectWithReason
+// instead of Reject on these transactions. Implementing RejectionReceiver is
+// optional.
+type RejectionReceiver interface {
+	Tx
+
+	// RejectWithReason rejects this transaction because of [reason]. [causeID]
+	// is the ID of the accepted conflicting transaction, or of the rejected
+	// dependency, that caused the rejection.
+	RejectWithReason(reason RejectionReason, causeID ids.ID) error
+}
@@ -14,8 +14,10 @@ import (
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/epochs"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/workers"
 )
 
 // EventDispatcher ...
@@ -39,6 +41,12 @@ type SubnetLookup interface {
 	SubnetID(chainID ids.ID) (ids.ID, error)
 }
 
+// ContextInitializable is initialized with the context of the chain it's used
+// on
+type ContextInitializable interface {
+	InitCtx(ctx *Context)
+}
+
 // Context is information about the current execution.
 // [NetworkID] is the ID of the network this context exists within.
 // [ChainID] is the ID of the chain this context exists within.
@@ -68,6 +76,13 @@ type Context struct {
 	EpochDuration        time.Duration
 	Clock                timer.Clock
 
+	// Runs the cryptographic operations of this chain
+	CryptoExecutor workers.Executor
+
+	// Estimates the offset of the local clock from the clocks of this node's
+	// peers. May be nil.
+	NetworkClock *timer.NetworkClock
+
 	// Non-zero iff this chain bootstrapped. Should only be accessed atomically.
 	bootstrapped uint32
 }
@@ -82,16 +97,33 @@ func (ctx *Context) Bootstrapped() {
 	stdatomic.StoreUint32(&ctx.bootstrapped, 1)
 }
 
-// Epoch this context thinks it's in based on the wall clock time.
-func (ctx *Context) Epoch() uint32 {
+// Unbootstrapped marks this chain as bootstrapping again
+func (ctx *Context) Unbootstrapped() {
+	stdatomic.StoreUint32(&ctx.bootstrapped, 0)
+}
+
+// NetworkTime returns the local time adjusted by the estimated offset of the
+// local clock from the clocks of this node's peers. If there is no estimate,
+// the local time is returned.
+func (ctx *Context) NetworkTime() time.Time {
 	now := ctx.Clock.Time()
-	timeSinceFirstEpochTransition := now.Sub(ctx.EpochFirstTransition)
-	epochsSinceFirstTransition := timeSinceFirstEpochTransition / ctx.EpochDuration
-	currentEpoch := epochsSinceFirstTransition + 1
-	if currentEpoch < 0 {
-		return 0
+	if ctx.NetworkClock == nil {
+		return now
 	}
-	return uint32(currentEpoch)
+	return ctx.NetworkClock.Time(now)
+}
+
+// EpochSchedule returns the schedule of epoch transitions of this chain
+func (ctx *Context) EpochSchedule() epochs.Schedule {
+	return epochs.Schedule{
+		FirstTransition: ctx.EpochFirstTransition,
+		Duration:        ctx.EpochDuration,
+	}
+}
+
+// Epoch this context thinks it's in based on the wall clock time.
+func (ctx *Context) Epoch() uint32 {
+	return ctx.EpochSchedule().Epoch(ctx.Clock.Time())
 }
 
 // DefaultContextTest ...
@@ -109,6 +141,7 @@ func DefaultContextTest() *Context {
 		BCLookup:            aliaser,
 		Namespace:           "",
 		Metrics:             prometheus.NewRegistry(),
+		CryptoExecutor:      workers.Inline{},
 	}
 }
 
@@ -0,0 +1,40 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+// batchSizer adapts the max number of txs that are put into a vertex. While
+// the backlog of txs doesn't fit into a vertex, the size doubles, up to [max].
+// While consensus is quiescent, the size halves, down to [min].
+type batchSizer struct {
+	min, max, size int
+}
+
+func newBatchSizer(min, max int) batchSizer {
+	if max < min {
+		max = min
+	}
+	return batchSizer{
+		min:  min,
+		max:  max,
+		size: min,
+	}
+}
+
+// update the size based on the number of txs waiting to be issued and whether
+// consensus is quiescent. Returns the new size.
+func (b *batchSizer) update(backlog int, quiescent bool) int {
+	switch {
+	case backlog > b.size && b.size < b.max:
+		b.size *= 2
+		if b.size > b.max {
+			b.size = b.max
+		}
+	case quiescent && backlog <= b.size && b.size > b.min:
+		b.size /= 2
+		if b.size < b.min {
+			b.size = b.min
+		}
+	}
+	return b.size
+}
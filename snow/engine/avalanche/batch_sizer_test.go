@@ -0,0 +1,37 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"testing"
+)
+
+func TestBatchSizerGrowsWithBacklog(t *testing.T) {
+	b := newBatchSizer(30, 100)
+	for _, expected := range []int{60, 100, 100} {
+		if size := b.update(1000, false); size != expected {
+			t.Fatalf("expected size %d but got %d", expected, size)
+		}
+	}
+
+	// Consensus isn't quiescent, so the size shouldn't shrink
+	if size := b.update(0, false); size != 100 {
+		t.Fatalf("expected size %d but got %d", 100, size)
+	}
+	for _, expected := range []int{50, 30, 30} {
+		if size := b.update(0, true); size != expected {
+			t.Fatalf("expected size %d but got %d", expected, size)
+		}
+	}
+}
+
+func TestBatchSizerFixed(t *testing.T) {
+	b := newBatchSizer(30, 0)
+	if size := b.update(1000, false); size != 30 {
+		t.Fatalf("expected size %d but got %d", 30, size)
+	}
+	if size := b.update(0, true); size != 30 {
+		t.Fatalf("expected size %d but got %d", 30, size)
+	}
+}
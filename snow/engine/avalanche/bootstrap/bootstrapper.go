@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
@@ -31,10 +32,16 @@ const (
 
 	// Parameters for delaying bootstrapping to avoid potential CPU burns
 	bootstrappingDelay = 10 * time.Second
+
+	// Number of beacons sampled when choosing which beacon to send a
+	// GetAncestors request to. The one with the fewest outstanding requests is
+	// chosen, which spreads concurrent requests across the beacons.
+	beaconSampleSize = 2
 )
 
 var (
 	errUnexpectedTimeout                      = errors.New("unexpected timeout fired")
+	errNoBeacons                              = errors.New("no beacons to sample")
 	_                    common.Bootstrapable = &Bootstrapper{}
 )
 
@@ -49,6 +56,16 @@ type Config struct {
 
 	Manager vertex.Manager
 	VM      vertex.DAGVM
+
+	// If true, the progress of bootstrapping is persisted alongside
+	// [VtxBlocked] so that it can be resumed after a restart
+	Checkpoint bool
+
+	// If true, a chain that hasn't accepted anything but its genesis syncs
+	// the VM's state to the accepted frontier of a beacon, rather than
+	// executing every accepted vertex. Requires the VM to implement
+	// vertex.StateSyncableVM.
+	StateSync bool
 }
 
 // Bootstrapper ...
@@ -65,15 +82,35 @@ type Bootstrapper struct {
 	Manager vertex.Manager
 	VM      vertex.DAGVM
 
+	// Checkpoint persists the progress of bootstrapping. Its writes are
+	// committed along with [VtxBlocked]. Nil if progress isn't checkpointed.
+	Checkpoint database.Database
+
 	// IDs of vertices that we will send a GetAncestors request for once we are
 	// not at the max number of outstanding requests
 	needToFetch ids.Set
+	// Max number of GetAncestors requests that can be outstanding at once
+	maxOutstanding int
 
 	// Contains IDs of vertices that have recently been processed
 	processedCache *cache.LRU
+	// IDs of vertices that were processed since the last checkpoint was
+	// discarded. Unlike [processedCache], this isn't bounded so that every
+	// processed vertex is recorded in the checkpoint.
+	processed ids.Set
 	// number of state transitions executed
 	executedStateTransitions int
 
+	// If true, the VM's state may be synced rather than executing every
+	// accepted vertex
+	stateSync bool
+	// True while a GetStateSummary request is outstanding
+	awaitingStateSummary  bool
+	stateSummaryVdr       ids.ShortID
+	stateSummaryRequestID uint32
+	// Accepted frontier to bootstrap from if state sync fails
+	stateSyncFallback []ids.ID
+
 	awaitingTimeout bool
 }
 
@@ -88,9 +125,17 @@ func (b *Bootstrapper) Initialize(
 	b.TxBlocked = config.TxBlocked
 	b.Manager = config.Manager
 	b.VM = config.VM
+	if config.Checkpoint {
+		b.Checkpoint = config.VtxBlocked.Metadata()
+	}
+	b.stateSync = config.StateSync
 	b.processedCache = &cache.LRU{Size: cacheSize}
 	b.OnFinished = onFinished
 	b.executedStateTransitions = math.MaxInt32
+	b.maxOutstanding = config.MaxOutstandingGetAncestors
+	if b.maxOutstanding <= 0 {
+		b.maxOutstanding = common.MaxOutstandingGetAncestorsRequests
+	}
 
 	if err := b.metrics.Initialize(namespace, registerer); err != nil {
 		return err
@@ -140,7 +185,7 @@ func (b *Bootstrapper) FilterAccepted(containerIDs []ids.ID) []ids.ID {
 // to fetch or we are at the maximum number of outstanding requests.
 func (b *Bootstrapper) fetch(vtxIDs ...ids.ID) error {
 	b.needToFetch.Add(vtxIDs...)
-	for b.needToFetch.Len() > 0 && b.OutstandingRequests.Len() < common.MaxOutstandingGetAncestorsRequests {
+	for b.needToFetch.Len() > 0 && b.OutstandingRequests.Len() < b.maxOutstanding {
 		vtxID := b.needToFetch.CappedList(1)[0]
 		b.needToFetch.Remove(vtxID)
 
@@ -154,11 +199,10 @@ func (b *Bootstrapper) fetch(vtxIDs ...ids.ID) error {
 			continue
 		}
 
-		validators, err := b.Beacons.Sample(1) // validator to send request to
+		validatorID, err := b.sampleBeacon() // validator to send request to
 		if err != nil {
 			return fmt.Errorf("dropping request for %s as there are no validators", vtxID)
 		}
-		validatorID := validators[0].ID()
 		b.RequestID++
 
 		b.OutstandingRequests.Add(validatorID, b.RequestID, vtxID)
@@ -167,6 +211,28 @@ func (b *Bootstrapper) fetch(vtxIDs ...ids.ID) error {
 	return b.checkFinish()
 }
 
+// sampleBeacon returns the beacon to send the next GetAncestors request to
+func (b *Bootstrapper) sampleBeacon() (ids.ShortID, error) {
+	sampleSize := beaconSampleSize
+	if numBeacons := b.Beacons.Len(); numBeacons < sampleSize {
+		sampleSize = numBeacons
+	}
+	beacons, err := b.Beacons.Sample(sampleSize)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	if len(beacons) == 0 {
+		return ids.ShortID{}, errNoBeacons
+	}
+	beaconID := beacons[0].ID()
+	for _, beacon := range beacons[1:] {
+		if candidateID := beacon.ID(); b.OutstandingRequests.LenOf(candidateID) < b.OutstandingRequests.LenOf(beaconID) {
+			beaconID = candidateID
+		}
+	}
+	return beaconID, nil
+}
+
 // Process the vertices in [vtxs].
 func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 	// Vertices that we need to process. Store them in a heap for deduplication
@@ -262,7 +328,7 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 				return err
 			}
 			if height%stripeDistance < stripeWidth { // See comment for stripeDistance
-				b.processedCache.Put(vtxID, nil)
+				b.markProcessed(vtxID)
 			}
 			if height == prevHeight {
 				vtxHeightSet.Add(vtxID)
@@ -278,6 +344,11 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 	if err := b.TxBlocked.Commit(); err != nil {
 		return err
 	}
+	// The checkpoint describes the queued vertices, so it's committed along
+	// with them
+	if err := b.saveCheckpoint(); err != nil {
+		return err
+	}
 	if err := b.VtxBlocked.Commit(); err != nil {
 		return err
 	}
@@ -285,6 +356,13 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 	return b.fetch()
 }
 
+// markProcessed marks that [vtxID] and all of its ancestors have been
+// processed, so they won't be traversed again
+func (b *Bootstrapper) markProcessed(vtxID ids.ID) {
+	b.processedCache.Put(vtxID, nil)
+	b.processed.Add(vtxID)
+}
+
 // MultiPut handles the receipt of multiple containers. Should be received in response to a GetAncestors message to [vdr]
 // with request ID [requestID]. Expects vtxs[0] to be the vertex requested in the corresponding GetAncestors.
 func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) error {
@@ -396,7 +474,19 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs []ids.ID) error {
 	}
 
 	b.NumFetched = 0
+	if err := b.loadCheckpoint(); err != nil {
+		return err
+	}
 
+	if b.canStateSync(acceptedContainerIDs) {
+		return b.requestStateSummary(acceptedContainerIDs)
+	}
+	return b.startFetching(acceptedContainerIDs)
+}
+
+// startFetching fetches and processes the vertices in [acceptedContainerIDs],
+// along with the vertices that were missing when bootstrapping last stopped
+func (b *Bootstrapper) startFetching(acceptedContainerIDs []ids.ID) error {
 	pendingContainerIDs := b.VtxBlocked.MissingIDs()
 	// Append the list of accepted container IDs to pendingContainerIDs to ensure
 	// we iterate over every container that must be traversed.
@@ -423,7 +513,7 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs []ids.ID) error {
 func (b *Bootstrapper) checkFinish() error {
 	// If there are outstanding requests for vertices or we still need to fetch vertices, we can't finish
 	pendingJobs := b.VtxBlocked.MissingIDs()
-	if b.Ctx.IsBootstrapped() || len(pendingJobs) > 0 || b.awaitingTimeout {
+	if b.Ctx.IsBootstrapped() || len(pendingJobs) > 0 || b.awaitingTimeout || b.awaitingStateSummary {
 		return nil
 	}
 
@@ -459,6 +549,12 @@ func (b *Bootstrapper) checkFinish() error {
 		return b.RestartBootstrap(true)
 	}
 
+	// The queued vertices were executed, so the checkpoint no longer describes
+	// them
+	if err := b.deleteCheckpoint(); err != nil {
+		return err
+	}
+
 	// Notify the subnet that this chain is synced
 	b.Subnet.Bootstrapped(b.Ctx.ChainID)
 	b.processedCache.Flush()
@@ -497,6 +593,22 @@ func (b *Bootstrapper) finish() error {
 	return nil
 }
 
+// Rebootstrap moves this chain, which finished bootstrapping, back into
+// bootstrapping so that it can catch up with the network. [OnFinished] is
+// called again once bootstrapping finishes.
+func (b *Bootstrapper) Rebootstrap() error {
+	b.needToFetch.Clear()
+	b.processedCache.Flush()
+	b.executedStateTransitions = math.MaxInt32
+
+	b.Ctx.Unbootstrapped()
+	if err := b.VM.Bootstrapping(); err != nil {
+		return fmt.Errorf("failed to notify VM that bootstrapping has started: %w",
+			err)
+	}
+	return b.Bootstrapper.Rebootstrap()
+}
+
 // Connected implements the Engine interface.
 func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
 	err := b.VM.Connected(validatorID)
@@ -1363,3 +1363,111 @@ func TestRestartBootstrapping(t *testing.T) {
 		t.Fatalf("Vertex should be accepted")
 	}
 }
+
+// Two vertices in the accepted frontier are missing, and one is the parent of
+// the other. Both should be requested concurrently, and the request for the
+// parent should be dropped once it's received as an ancestor of the child.
+func TestBootstrapperParallelGetAncestors(t *testing.T) {
+	config, peerID, sender, manager, vm := newConfig(t)
+	config.MaxOutstandingGetAncestors = 2
+
+	vtxID0 := ids.Empty.Prefix(0)
+	vtxID1 := ids.Empty.Prefix(1)
+
+	vtxBytes0 := []byte{0}
+	vtxBytes1 := []byte{1}
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     vtxID0,
+			StatusV: choices.Unknown,
+		},
+		HeightV: 0,
+		BytesV:  vtxBytes0,
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     vtxID1,
+			StatusV: choices.Unknown,
+		},
+		ParentsV: []avalanche.Vertex{vtx0},
+		HeightV:  1,
+		BytesV:   vtxBytes1,
+	}
+
+	bs := Bootstrapper{}
+	finished := new(bool)
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s_bs", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case vtxID == vtxID0 && vtx0.StatusV != choices.Unknown:
+			return vtx0, nil
+		case vtxID == vtxID1 && vtx1.StatusV != choices.Unknown:
+			return vtx1, nil
+		case vtxID == vtxID0, vtxID == vtxID1:
+			return nil, errUnknownVertex
+		default:
+			t.Fatal(errUnknownVertex)
+			panic(errUnknownVertex)
+		}
+	}
+	manager.ParseVtxF = func(vtxBytes []byte) (avalanche.Vertex, error) {
+		switch {
+		case bytes.Equal(vtxBytes, vtxBytes0):
+			vtx0.StatusV = choices.Processing
+			return vtx0, nil
+		case bytes.Equal(vtxBytes, vtxBytes1):
+			vtx1.StatusV = choices.Processing
+			return vtx1, nil
+		}
+		t.Fatal(errParsedUnknownVertex)
+		return nil, errParsedUnknownVertex
+	}
+	requested := map[ids.ID]uint32{}
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+		if vdr != peerID {
+			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
+		}
+		requested[vtxID] = reqID
+	}
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted([]ids.ID{vtxID0, vtxID1}); err != nil {
+		t.Fatal(err)
+	} else if len(requested) != 2 {
+		t.Fatalf("should have requested both vertices concurrently, requested %d", len(requested))
+	}
+
+	vm.CantBootstrapped = false
+
+	err = bs.MultiPut(peerID, requested[vtxID1], [][]byte{vtxBytes1, vtxBytes0})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case bs.OutstandingRequests.Len() != 1:
+		t.Fatalf("should have kept the other request outstanding, %d outstanding", bs.OutstandingRequests.Len())
+	case !bs.Ctx.IsBootstrapped():
+		t.Fatal("should have finished")
+	case vtx0.Status() != choices.Accepted:
+		t.Fatal("should be accepted")
+	case vtx1.Status() != choices.Accepted:
+		t.Fatal("should be accepted")
+	}
+
+	// The response to the other request should still be accepted
+	if err := bs.MultiPut(peerID, requested[vtxID0], [][]byte{vtxBytes0}); err != nil {
+		t.Fatal(err)
+	} else if bs.OutstandingRequests.Len() != 0 {
+		t.Fatalf("should have no outstanding requests, %d outstanding", bs.OutstandingRequests.Len())
+	}
+}
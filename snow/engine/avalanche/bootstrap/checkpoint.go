@@ -0,0 +1,165 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	checkpointKey = []byte("checkpoint")
+
+	errCorruptCheckpoint = errors.New("bootstrap checkpoint failed its integrity check")
+	errStaleCheckpoint   = errors.New("bootstrap checkpoint was made from a different accepted frontier")
+)
+
+// checkpoint is the progress of bootstrapping, persisted so that a node that
+// stops while bootstrapping doesn't traverse the vertices it already fetched
+// again. The vertices that are missing are persisted by the vertex queue.
+type checkpoint struct {
+	// Accepted frontier of this node when the checkpoint was made. Vertices
+	// are only executed once fetching finishes, so a different frontier means
+	// the checkpoint no longer describes the queued vertices.
+	edge []ids.ID
+
+	// Vertices that were processed, along with all of their ancestors
+	processed []ids.ID
+
+	// Number of vertices that had been fetched
+	numFetched uint32
+}
+
+// Bytes returns the checkpoint prefixed by its checksum
+func (c *checkpoint) Bytes() ([]byte, error) {
+	p := wrappers.Packer{MaxSize: (len(c.edge)+len(c.processed))*hashing.HashLen + 3*wrappers.IntLen}
+	packIDs(&p, c.edge)
+	packIDs(&p, c.processed)
+	p.PackInt(c.numFetched)
+	if p.Errored() {
+		return nil, p.Err
+	}
+	return append(hashing.ComputeHash256(p.Bytes), p.Bytes...), nil
+}
+
+// parseCheckpoint parses a checkpoint returned by Bytes. Returns an error if it
+// fails its integrity check.
+func parseCheckpoint(b []byte) (*checkpoint, error) {
+	if len(b) < hashing.HashLen {
+		return nil, errCorruptCheckpoint
+	}
+	checksum, body := b[:hashing.HashLen], b[hashing.HashLen:]
+	if !bytes.Equal(checksum, hashing.ComputeHash256(body)) {
+		return nil, errCorruptCheckpoint
+	}
+
+	p := wrappers.Packer{Bytes: body}
+	c := &checkpoint{
+		edge:      unpackIDs(&p),
+		processed: unpackIDs(&p),
+	}
+	c.numFetched = p.UnpackInt()
+	if p.Errored() || p.Offset != len(body) {
+		return nil, errCorruptCheckpoint
+	}
+	return c, nil
+}
+
+func packIDs(p *wrappers.Packer, idList []ids.ID) {
+	p.PackInt(uint32(len(idList)))
+	for _, id := range idList {
+		p.PackFixedBytes(id[:])
+	}
+}
+
+func unpackIDs(p *wrappers.Packer) []ids.ID {
+	numIDs := p.UnpackInt()
+	if p.Errored() || int(numIDs) > (len(p.Bytes)-p.Offset)/hashing.HashLen {
+		p.Add(errCorruptCheckpoint)
+		return nil
+	}
+	idList := make([]ids.ID, numIDs)
+	for i := range idList {
+		copy(idList[i][:], p.UnpackFixedBytes(hashing.HashLen))
+	}
+	return idList
+}
+
+// saveCheckpoint writes the current progress of bootstrapping. It's persisted
+// by the next commit of [b.VtxBlocked].
+func (b *Bootstrapper) saveCheckpoint() error {
+	if b.Checkpoint == nil {
+		return nil
+	}
+	c := checkpoint{
+		edge:       b.Manager.Edge(),
+		processed:  b.processed.List(),
+		numFetched: b.NumFetched,
+	}
+	cBytes, err := c.Bytes()
+	if err != nil {
+		return err
+	}
+	return b.Checkpoint.Put(checkpointKey, cBytes)
+}
+
+// loadCheckpoint restores the progress of bootstrapping from the persisted
+// checkpoint, if there is a valid one. An invalid checkpoint is deleted.
+func (b *Bootstrapper) loadCheckpoint() error {
+	if b.Checkpoint == nil {
+		return nil
+	}
+	cBytes, err := b.Checkpoint.Get(checkpointKey)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c, err := parseCheckpoint(cBytes)
+	if err == nil && !ids.UnsortedEquals(c.edge, b.Manager.Edge()) {
+		err = errStaleCheckpoint
+	}
+	if err != nil {
+		b.Ctx.Log.Warn("discarding bootstrap checkpoint: %s", err)
+		return b.deleteCheckpoint()
+	}
+
+	for _, vtxID := range c.processed {
+		// Only vertices that are still queued are trusted to have had their
+		// ancestors processed
+		queued, err := b.VtxBlocked.Has(vtxID)
+		if err != nil {
+			return err
+		}
+		if !queued {
+			b.Ctx.Log.Warn("discarding bootstrap checkpoint: processed vertex %s isn't queued", vtxID)
+			return b.deleteCheckpoint()
+		}
+	}
+	for _, vtxID := range c.processed {
+		b.markProcessed(vtxID)
+	}
+	b.NumFetched = c.numFetched
+	b.Ctx.Log.Info("resuming bootstrapping from a checkpoint with %d fetched vertices", b.NumFetched)
+	return nil
+}
+
+// deleteCheckpoint removes the persisted checkpoint
+func (b *Bootstrapper) deleteCheckpoint() error {
+	b.processed.Clear()
+	if b.Checkpoint == nil {
+		return nil
+	}
+	if err := b.Checkpoint.Delete(checkpointKey); err != nil {
+		return err
+	}
+	return b.VtxBlocked.Commit()
+}
@@ -0,0 +1,144 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+func TestCheckpointBytes(t *testing.T) {
+	c := checkpoint{
+		edge:       []ids.ID{ids.Empty.Prefix(0), ids.Empty.Prefix(1)},
+		processed:  []ids.ID{ids.Empty.Prefix(2)},
+		numFetched: 5,
+	}
+	cBytes, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := parseCheckpoint(cBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ids.Equals(parsed.edge, c.edge) {
+		t.Fatalf("wrong edge %v, expected %v", parsed.edge, c.edge)
+	}
+	if !ids.Equals(parsed.processed, c.processed) {
+		t.Fatalf("wrong processed vertices %v, expected %v", parsed.processed, c.processed)
+	}
+	if parsed.numFetched != c.numFetched {
+		t.Fatalf("wrong number fetched %d, expected %d", parsed.numFetched, c.numFetched)
+	}
+
+	// Flipping any bit should fail the integrity check
+	cBytes[len(cBytes)-1] ^= 1
+	if _, err := parseCheckpoint(cBytes); err != errCorruptCheckpoint {
+		t.Fatalf("expected %s but got %v", errCorruptCheckpoint, err)
+	}
+	if _, err := parseCheckpoint(cBytes[:hashing.HashLen-1]); err != errCorruptCheckpoint {
+		t.Fatalf("expected %s but got %v", errCorruptCheckpoint, err)
+	}
+}
+
+func TestBootstrapperCheckpoint(t *testing.T) {
+	config, _, _, manager, _ := newConfig(t)
+	config.Checkpoint = true
+
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+	}
+	edge := []ids.ID{ids.GenerateTestID()}
+	manager.EdgeF = func() []ids.ID { return edge }
+
+	bs := Bootstrapper{}
+	if err := bs.Initialize(config, nil, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bs.VtxBlocked.Push(&vertexJob{
+		log:         bs.Ctx.Log,
+		numAccepted: bs.numAcceptedVts,
+		numDropped:  bs.numDroppedVts,
+		vtx:         vtx,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	bs.markProcessed(vtx.ID())
+	bs.NumFetched = 1
+	if err := bs.saveCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs.processed.Clear()
+	bs.processedCache.Flush()
+	bs.NumFetched = 0
+	if err := bs.loadCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if !bs.processed.Contains(vtx.ID()) {
+		t.Fatal("should have restored the processed vertex")
+	}
+	if _, ok := bs.processedCache.Get(vtx.ID()); !ok {
+		t.Fatal("should have cached the processed vertex")
+	}
+	if bs.NumFetched != 1 {
+		t.Fatalf("should have restored the number fetched, got %d", bs.NumFetched)
+	}
+
+	// The checkpoint was made from a different accepted frontier, so it should
+	// be discarded
+	edge = []ids.ID{ids.GenerateTestID()}
+	bs.processed.Clear()
+	bs.NumFetched = 0
+	if err := bs.loadCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if bs.processed.Len() != 0 || bs.NumFetched != 0 {
+		t.Fatal("shouldn't have restored a stale checkpoint")
+	}
+	if _, err := bs.Checkpoint.Get(checkpointKey); err != database.ErrNotFound {
+		t.Fatalf("stale checkpoint should have been deleted, got %v", err)
+	}
+}
+
+func TestBootstrapperCheckpointUnqueuedVertex(t *testing.T) {
+	config, _, _, manager, _ := newConfig(t)
+	config.Checkpoint = true
+
+	edge := []ids.ID{ids.GenerateTestID()}
+	manager.EdgeF = func() []ids.ID { return edge }
+
+	bs := Bootstrapper{}
+	if err := bs.Initialize(config, nil, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The processed vertex was never queued, so the checkpoint can't be trusted
+	bs.markProcessed(ids.GenerateTestID())
+	bs.NumFetched = 1
+	if err := bs.saveCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs.processed.Clear()
+	bs.NumFetched = 0
+	if err := bs.loadCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if bs.processed.Len() != 0 || bs.NumFetched != 0 {
+		t.Fatal("shouldn't have restored an untrusted checkpoint")
+	}
+}
@@ -0,0 +1,157 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var (
+	errEmptyFrontier     = errors.New("state summary has an empty accepted frontier")
+	errFrontierTooLarge  = errors.New("state summary has too many vertices in its accepted frontier")
+	errDuplicateFrontier = errors.New("state summary has duplicated vertices in its accepted frontier")
+
+	_ common.StateSummaryHandler = &Bootstrapper{}
+)
+
+// canStateSync returns true if this node should sync the VM's state to the
+// accepted frontier of a beacon, rather than fetching and executing every
+// vertex up to [acceptedContainerIDs]
+func (b *Bootstrapper) canStateSync(acceptedContainerIDs []ids.ID) bool {
+	if !b.stateSync || b.Restarted || b.NumFetched > 0 || b.VtxBlocked.NumMissingIDs() > 0 {
+		return false
+	}
+	if _, ok := b.VM.(vertex.StateSyncableVM); !ok {
+		return false
+	}
+	if _, ok := b.Manager.(vertex.EdgeSyncer); !ok {
+		return false
+	}
+	if len(b.FilterAccepted(acceptedContainerIDs)) == len(acceptedContainerIDs) {
+		// There is nothing to bootstrap
+		return false
+	}
+
+	// Syncing replaces the VM's state, so only a chain that hasn't accepted
+	// anything but its genesis is synced
+	for _, vtxID := range b.Manager.Edge() {
+		vtx, err := b.Manager.GetVtx(vtxID)
+		if err != nil {
+			return false
+		}
+		if height, err := vtx.Height(); err != nil || height != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// requestStateSummary requests the accepted frontier and state summary of a
+// beacon. If the request fails, bootstrapping falls back to fetching
+// [acceptedContainerIDs].
+func (b *Bootstrapper) requestStateSummary(acceptedContainerIDs []ids.ID) error {
+	validatorID, err := b.sampleBeacon()
+	if err != nil {
+		return err
+	}
+	b.RequestID++
+
+	b.awaitingStateSummary = true
+	b.stateSummaryVdr = validatorID
+	b.stateSummaryRequestID = b.RequestID
+	b.stateSyncFallback = acceptedContainerIDs
+
+	b.Ctx.Log.Info("requesting a state summary from %s%s", constants.NodeIDPrefix, validatorID)
+	b.Sender.GetStateSummary(validatorID, b.RequestID)
+	return nil
+}
+
+// isStateSummaryRequest returns true if [requestID] to [vdr] is the outstanding
+// GetStateSummary request
+func (b *Bootstrapper) isStateSummaryRequest(vdr ids.ShortID, requestID uint32) bool {
+	return b.awaitingStateSummary && vdr == b.stateSummaryVdr && requestID == b.stateSummaryRequestID
+}
+
+// GetStateSummary implements the common.StateSummaryHandler interface. A node
+// that is bootstrapping doesn't have a state to summarize, so the request is
+// dropped.
+func (b *Bootstrapper) GetStateSummary(vdr ids.ShortID, requestID uint32) error {
+	b.Ctx.Log.Debug("dropping GetStateSummary(%s, %d) due to bootstrapping", vdr, requestID)
+	return nil
+}
+
+// StateSummary implements the common.StateSummaryHandler interface
+func (b *Bootstrapper) StateSummary(vdr ids.ShortID, requestID uint32, frontier [][]byte, summary []byte) error {
+	if !b.isStateSummaryRequest(vdr, requestID) {
+		b.Ctx.Log.Debug("dropping unexpected StateSummary(%s, %d)", vdr, requestID)
+		return nil
+	}
+	b.awaitingStateSummary = false
+
+	vtxIDs, err := b.parseFrontier(frontier)
+	if err == nil {
+		// canStateSync verified that the VM can be synced
+		err = b.VM.(vertex.StateSyncableVM).SyncState(vtxIDs, summary)
+	}
+	if err != nil {
+		b.Ctx.Log.Warn("failed to sync state from %s%s, falling back to executing every vertex: %s",
+			constants.NodeIDPrefix, vdr, err)
+		return b.startFetching(b.stateSyncFallback)
+	}
+
+	// The VM's state was replaced, so failing to record the synced frontier
+	// is fatal
+	if err := b.Manager.(vertex.EdgeSyncer).SyncEdge(vtxIDs); err != nil {
+		return fmt.Errorf("failed to set the synced accepted frontier: %w", err)
+	}
+	b.Ctx.Log.Info("synced state to an accepted frontier of %d vertices from %s%s",
+		len(vtxIDs), constants.NodeIDPrefix, vdr)
+	return b.checkFinish()
+}
+
+// GetStateSummaryFailed implements the common.StateSummaryHandler interface
+func (b *Bootstrapper) GetStateSummaryFailed(vdr ids.ShortID, requestID uint32) error {
+	if !b.isStateSummaryRequest(vdr, requestID) {
+		b.Ctx.Log.Debug("GetStateSummaryFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+		return nil
+	}
+	b.awaitingStateSummary = false
+
+	b.Ctx.Log.Info("failed to get a state summary from %s%s, falling back to executing every vertex",
+		constants.NodeIDPrefix, vdr)
+	return b.startFetching(b.stateSyncFallback)
+}
+
+// parseFrontier parses the vertices of a synced accepted frontier and returns
+// their IDs
+func (b *Bootstrapper) parseFrontier(frontier [][]byte) ([]ids.ID, error) {
+	switch {
+	case len(frontier) == 0:
+		return nil, errEmptyFrontier
+	case len(frontier) > b.MultiputMaxContainersReceived:
+		return nil, errFrontierTooLarge
+	}
+
+	vtxIDs := make([]ids.ID, len(frontier))
+	vtxIDSet := ids.NewSet(len(frontier))
+	for i, vtxBytes := range frontier {
+		vtx, err := b.Manager.ParseVtx(vtxBytes)
+		if err != nil {
+			return nil, err
+		}
+		vtxID := vtx.ID()
+		if vtxIDSet.Contains(vtxID) {
+			return nil, errDuplicateFrontier
+		}
+		vtxIDSet.Add(vtxID)
+		vtxIDs[i] = vtxID
+	}
+	return vtxIDs, nil
+}
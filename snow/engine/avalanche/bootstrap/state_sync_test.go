@@ -0,0 +1,208 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var _ vertex.StateSyncableVM = &stateSyncVM{}
+
+type stateSyncVM struct {
+	*vertex.TestVM
+
+	StateSummaryF func([]ids.ID) ([]byte, error)
+	SyncStateF    func([]ids.ID, []byte) error
+}
+
+func (vm *stateSyncVM) StateSummary(edge []ids.ID) ([]byte, error) {
+	return vm.StateSummaryF(edge)
+}
+
+func (vm *stateSyncVM) SyncState(edge []ids.ID, summary []byte) error {
+	return vm.SyncStateF(edge, summary)
+}
+
+// newStateSyncTest returns a bootstrapper of a chain that has only accepted
+// its genesis vertex, after it requested a state summary for the frontier
+// vertex [vtx1] of a beacon
+func newStateSyncTest(t *testing.T) (*Bootstrapper, *bool, ids.ShortID, *common.SenderTest, *stateSyncVM, *avalanche.TestVertex) {
+	config, peerID, sender, manager, vm := newConfig(t)
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(0),
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+		BytesV:  []byte{0},
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Unknown,
+		},
+		ParentsV: []avalanche.Vertex{vtx0},
+		HeightV:  1,
+		BytesV:   []byte{1},
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{vtx0.ID()} }
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case vtxID == vtx0.ID():
+			return vtx0, nil
+		case vtxID == vtx1.ID() && vtx1.Status() != choices.Unknown:
+			return vtx1, nil
+		default:
+			return nil, errUnknownVertex
+		}
+	}
+	manager.ParseVtxF = func(vtxBytes []byte) (avalanche.Vertex, error) {
+		if bytes.Equal(vtxBytes, vtx1.Bytes()) {
+			return vtx1, nil
+		}
+		t.Fatal(errParsedUnknownVertex)
+		return nil, errParsedUnknownVertex
+	}
+	manager.SyncEdgeF = func(vtxIDs []ids.ID) error {
+		for _, vtxID := range vtxIDs {
+			if vtxID == vtx1.ID() {
+				vtx1.StatusV = choices.Accepted
+			}
+		}
+		return nil
+	}
+
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+	syncVM := &stateSyncVM{TestVM: vm}
+	config.VM = syncVM
+	config.StateSync = true
+
+	requestID := new(uint32)
+	sender.GetStateSummaryF = func(vdr ids.ShortID, reqID uint32) {
+		if vdr != peerID {
+			t.Fatalf("should have requested a state summary from %s, requested from %s", peerID, vdr)
+		}
+		*requestID = reqID
+	}
+
+	bs := &Bootstrapper{}
+	finished := new(bool)
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s_bs", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.ForceAccepted([]ids.ID{vtx1.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if *requestID == 0 {
+		t.Fatalf("should have requested a state summary")
+	}
+	return bs, finished, peerID, sender, syncVM, vtx1
+}
+
+func TestBootstrapperStateSync(t *testing.T) {
+	bs, finished, peerID, _, vm, vtx1 := newStateSyncTest(t)
+
+	summary := []byte{2}
+	synced := false
+	vm.SyncStateF = func(edge []ids.ID, s []byte) error {
+		switch {
+		case len(edge) != 1 || edge[0] != vtx1.ID():
+			t.Fatalf("should have synced to the frontier %s, synced to %s", vtx1.ID(), edge)
+		case !bytes.Equal(s, summary):
+			t.Fatalf("should have synced to the summary %v, synced to %v", summary, s)
+		}
+		synced = true
+		return nil
+	}
+
+	if err := bs.StateSummary(peerID, bs.RequestID, [][]byte{vtx1.Bytes()}, summary); err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case !synced:
+		t.Fatalf("should have synced the VM's state")
+	case vtx1.Status() != choices.Accepted:
+		t.Fatalf("synced frontier should be accepted")
+	case !*finished:
+		t.Fatalf("bootstrapping should have finished")
+	}
+}
+
+func TestBootstrapperStateSyncFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(*Bootstrapper, ids.ShortID, *stateSyncVM, *avalanche.TestVertex) error
+	}{
+		{
+			name: "request failed",
+			fail: func(bs *Bootstrapper, peerID ids.ShortID, _ *stateSyncVM, _ *avalanche.TestVertex) error {
+				return bs.GetStateSummaryFailed(peerID, bs.RequestID)
+			},
+		},
+		{
+			name: "empty frontier",
+			fail: func(bs *Bootstrapper, peerID ids.ShortID, _ *stateSyncVM, _ *avalanche.TestVertex) error {
+				return bs.StateSummary(peerID, bs.RequestID, nil, []byte{2})
+			},
+		},
+		{
+			name: "sync failed",
+			fail: func(bs *Bootstrapper, peerID ids.ShortID, vm *stateSyncVM, vtx1 *avalanche.TestVertex) error {
+				vm.SyncStateF = func([]ids.ID, []byte) error { return errors.New("invalid summary") }
+				return bs.StateSummary(peerID, bs.RequestID, [][]byte{vtx1.Bytes()}, []byte{2})
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bs, finished, peerID, sender, vm, vtx1 := newStateSyncTest(t)
+
+			requested := false
+			sender.GetAncestorsF = func(vdr ids.ShortID, _ uint32, vtxID ids.ID) {
+				switch {
+				case vdr != peerID:
+					t.Fatalf("should have requested vertex from %s, requested from %s", peerID, vdr)
+				case vtxID != vtx1.ID():
+					t.Fatalf("should have requested %s, requested %s", vtx1.ID(), vtxID)
+				}
+				requested = true
+			}
+
+			if err := test.fail(bs, peerID, vm, vtx1); err != nil {
+				t.Fatal(err)
+			}
+
+			switch {
+			case !requested:
+				t.Fatalf("should have fallen back to fetching the accepted frontier")
+			case vtx1.Status() == choices.Accepted:
+				t.Fatalf("frontier shouldn't be accepted before it's fetched")
+			case *finished:
+				t.Fatalf("bootstrapping shouldn't have finished")
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/bootstrap"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 )
 
 // Config wraps all the parameters needed for an avalanche engine
@@ -14,4 +17,50 @@ type Config struct {
 
 	Params    avalanche.Parameters
 	Consensus avalanche.Consensus
+
+	// If true, the engine doesn't poll validators while this node isn't a
+	// validator. Instead, vertices that validators gossip as accepted are
+	// recorded as their votes.
+	LightVerification bool
+
+	// If true, the engine issues an additional repoll when a poll changes the
+	// virtuous frontier, rather than waiting for an outstanding poll to
+	// finish.
+	VirtuousRepoll bool
+
+	// Max number of vertices kept after being abandoned for missing
+	// dependencies, so they can be issued once their dependencies are. If 0,
+	// abandoned vertices are dropped.
+	MaxOrphans int
+
+	// Time an abandoned vertex is kept waiting on its dependencies. If 0,
+	// orphans are only dropped to make room for newer ones.
+	OrphanExpiry time.Duration
+
+	// Limits on the vertices this node builds. Should match the limits of the
+	// vertex builder.
+	VertexLimits vertex.Limits
+
+	// If positive, the engine reports that it's stalled and tries to recover
+	// when no vertex is decided for this long while vertices are processing
+	StallThreshold time.Duration
+
+	// If positive, bootstrapping is restarted once no vertex has been accepted
+	// for [StaleThreshold] while beacons holding at least [Alpha] weight
+	// gossip higher accepted vertices
+	StaleThreshold time.Duration
+
+	// If greater than 1, the txs of an issued vertex are verified
+	// concurrently by up to this many goroutines, rather than one at a time.
+	// The VM's txs must be safe to verify concurrently.
+	TxVerifiers int
+
+	// If positive, the engine requests the accepted frontier of a validator
+	// this often, and fetches the vertices in it that this node is missing
+	FrontierSyncFrequency time.Duration
+
+	// If true, benched validators sampled for a poll are replaced by
+	// validators drawn again, since their queries would fail immediately.
+	// Requires [Benchlist].
+	SkipBenched bool
 }
@@ -0,0 +1,119 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// Max number of pieces of evidence kept by the engine
+const maxEvidence = 1024
+
+// Evidence implements the common.EvidenceReporter interface
+func (t *Transitive) Evidence() []common.Evidence { return t.evidence.List() }
+
+// recordEvidence that a peer misbehaved
+func (t *Transitive) recordEvidence(evidence common.Evidence) {
+	evidence.Time = time.Now()
+	t.Ctx.Log.Info("recorded %s evidence against %s%s: %s",
+		evidence.Kind, constants.NodeIDPrefix, evidence.NodeID, evidence.Description)
+	t.evidence.Add(evidence)
+	t.numEvidence.WithLabelValues(string(evidence.Kind)).Inc()
+}
+
+// checkChits records evidence if [vdr] voted for vertices whose txs conflict.
+// The preferences of an honest validator never conflict. Only the votes that
+// this node has are checked.
+func (t *Transitive) checkChits(vdr ids.ShortID, requestID uint32, votes []ids.ID) {
+	// input ID --> the vote whose tx consumes it
+	consumers := make(map[ids.ID]ids.ID)
+	// input ID --> the tx that consumes it
+	consumerTxs := make(map[ids.ID]ids.ID)
+	for _, vtxID := range votes {
+		vtx, err := t.Manager.GetVtx(vtxID)
+		if err != nil || !vtx.Status().Fetched() {
+			continue
+		}
+		txs, err := vtx.Txs()
+		if err != nil {
+			continue
+		}
+		for _, tx := range txs {
+			txID := tx.ID()
+			for _, inputID := range tx.InputIDs() {
+				consumerTxID, ok := consumerTxs[inputID]
+				if !ok {
+					consumers[inputID] = vtxID
+					consumerTxs[inputID] = txID
+					continue
+				}
+				if consumerTxID == txID {
+					continue
+				}
+				t.recordEvidence(common.Evidence{
+					NodeID:       vdr,
+					Kind:         common.ConflictingChits,
+					RequestID:    requestID,
+					ContainerIDs: []ids.ID{consumers[inputID], vtxID},
+					Description: fmt.Sprintf("voted for txs %s and %s, which both consume %s",
+						consumerTxID, txID, inputID),
+				})
+				return
+			}
+		}
+	}
+}
+
+// checkAncestry records evidence if the height of [vtx], which [vdr] sent, isn't
+// one more than the height of its tallest parent. Assumes the parents of [vtx]
+// have been fetched.
+func (t *Transitive) checkAncestry(vdr ids.ShortID, vtx avalanche.Vertex, parents []avalanche.Vertex) {
+	height, err := vtx.Height()
+	if err != nil {
+		return
+	}
+	expectedHeight := uint64(0)
+	for _, parent := range parents {
+		parentHeight, err := parent.Height()
+		if err != nil {
+			return
+		}
+		if parentHeight+1 > expectedHeight {
+			expectedHeight = parentHeight + 1
+		}
+	}
+	if height != expectedHeight {
+		t.recordEvidence(common.Evidence{
+			NodeID:       vdr,
+			Kind:         common.InvalidAncestry,
+			ContainerIDs: []ids.ID{vtx.ID()},
+			Description:  fmt.Sprintf("sent a vertex with height %d, whose parents imply height %d", height, expectedHeight),
+		})
+	}
+}
+
+// checkPut records evidence if [vdr] responded to the request [requestID] for
+// a vertex with vertex [vtxID]
+func (t *Transitive) checkPut(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+	if requestID == constants.GossipMsgRequestID {
+		return
+	}
+	requestedID, ok := t.outstandingVtxReqs.Get(vdr, requestID)
+	if !ok || requestedID == vtxID {
+		return
+	}
+	t.recordEvidence(common.Evidence{
+		NodeID:       vdr,
+		Kind:         common.UnrequestedContainer,
+		RequestID:    requestID,
+		ContainerIDs: []ids.ID{requestedID, vtxID},
+		Description:  fmt.Sprintf("responded to a request for %s with %s", requestedID, vtxID),
+	})
+}
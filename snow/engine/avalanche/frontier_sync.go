@@ -0,0 +1,82 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// syncFrontier requests the accepted frontier of a sampled validator if
+// [frontierSyncFrequency] passed since the last request. Vertices are
+// otherwise only learned through push gossip and queries, so a node that was
+// partitioned may not hear of the vertices accepted meanwhile.
+func (t *Transitive) syncFrontier(now time.Time) {
+	if t.frontierSyncFrequency <= 0 || !t.Ctx.IsBootstrapped() || now.Sub(t.lastFrontierSync) < t.frontierSyncFrequency {
+		return
+	}
+	vdrs, err := t.Validators.Sample(1)
+	if err != nil || len(vdrs) == 0 {
+		return
+	}
+	vdrID := vdrs[0].ID()
+	if vdrID == t.Ctx.NodeID {
+		return
+	}
+	t.lastFrontierSync = now
+
+	t.RequestID++
+	t.frontierSyncPending = true
+	t.frontierSyncVdr = vdrID
+	t.frontierSyncReqID = t.RequestID
+
+	vdrSet := ids.NewShortSet(1)
+	vdrSet.Add(vdrID)
+	t.Sender.GetAcceptedFrontier(vdrSet, t.RequestID)
+}
+
+// isFrontierSyncRequest returns true if [requestID] to [vdr] is the
+// outstanding accepted frontier request
+func (t *Transitive) isFrontierSyncRequest(vdr ids.ShortID, requestID uint32) bool {
+	return t.frontierSyncPending && vdr == t.frontierSyncVdr && requestID == t.frontierSyncReqID
+}
+
+// AcceptedFrontier implements the Engine interface. Once bootstrapped, the
+// vertices in the accepted frontier of [vdr] that this node doesn't have are
+// fetched from [vdr].
+func (t *Transitive) AcceptedFrontier(vdr ids.ShortID, requestID uint32, containerIDs []ids.ID) error {
+	if !t.Ctx.IsBootstrapped() {
+		return t.Bootstrapper.AcceptedFrontier(vdr, requestID, containerIDs)
+	}
+	if !t.isFrontierSyncRequest(vdr, requestID) {
+		t.Ctx.Log.Debug("dropping unexpected AcceptedFrontier(%s, %d)", vdr, requestID)
+		return nil
+	}
+	t.frontierSyncPending = false
+
+	if len(containerIDs) > t.MultiputMaxContainersReceived {
+		containerIDs = containerIDs[:t.MultiputMaxContainersReceived]
+	}
+	for _, vtxID := range containerIDs {
+		if _, err := t.Manager.GetVtx(vtxID); err == nil {
+			continue
+		}
+		t.Ctx.Log.Debug("fetching %s from the accepted frontier of %s", vtxID, vdr)
+		t.numFrontierFetches.Inc()
+		t.sendRequest(vdr, vtxID)
+	}
+	return nil
+}
+
+// GetAcceptedFrontierFailed implements the Engine interface
+func (t *Transitive) GetAcceptedFrontierFailed(vdr ids.ShortID, requestID uint32) error {
+	if !t.Ctx.IsBootstrapped() {
+		return t.Bootstrapper.GetAcceptedFrontierFailed(vdr, requestID)
+	}
+	if t.isFrontierSyncRequest(vdr, requestID) {
+		t.frontierSyncPending = false
+	}
+	return nil
+}
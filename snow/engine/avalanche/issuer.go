@@ -4,9 +4,10 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
-	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 )
 
 // issuer issues [vtx] into consensus after its dependencies are met.
@@ -15,6 +16,8 @@ type issuer struct {
 	vtx               avalanche.Vertex
 	issued, abandoned bool
 	vtxDeps, txDeps   ids.Set
+	// Time [vtx] started waiting on its dependencies
+	start time.Time
 }
 
 // Register that a vertex we were waiting on has been issued to consensus.
@@ -33,8 +36,16 @@ func (i *issuer) FulfillTx(id ids.ID) {
 func (i *issuer) Abandon() {
 	if !i.abandoned {
 		vtxID := i.vtx.ID()
-		i.t.pending.Remove(vtxID)
+		delete(i.t.pending, vtxID)
 		i.abandoned = true
+
+		// Keep the vertex so it can be issued once its dependencies are
+		deps := ids.NewSet(i.vtxDeps.Len() + i.txDeps.Len())
+		deps.Union(i.vtxDeps)
+		deps.Union(i.txDeps)
+		i.t.orphans.add(i.vtx, deps)
+		i.t.numOrphanVts.Set(float64(i.t.orphans.Len()))
+
 		i.t.vtxBlocked.Abandon(vtxID) // Inform vertices waiting on this vtx that it won't be issued
 	}
 }
@@ -48,7 +59,7 @@ func (i *issuer) Update() {
 	i.issued = true
 
 	vtxID := i.vtx.ID()
-	i.t.pending.Remove(vtxID) // Remove from set of vertices waiting to be issued.
+	delete(i.t.pending, vtxID) // Remove from set of vertices waiting to be issued.
 
 	// Make sure the transactions in this vertex are valid
 	txs, err := i.vtx.Txs()
@@ -56,14 +67,7 @@ func (i *issuer) Update() {
 		i.t.errs.Add(err)
 		return
 	}
-	validTxs := make([]snowstorm.Tx, 0, len(txs))
-	for _, tx := range txs {
-		if err := tx.Verify(); err != nil {
-			i.t.Ctx.Log.Debug("Transaction %s failed verification due to %s", tx.ID(), err)
-		} else {
-			validTxs = append(validTxs, tx)
-		}
-	}
+	validTxs := i.t.verifyTxs(txs)
 
 	// Some of the transactions weren't valid. Abandon this vertex.
 	// Take the valid transactions and issue a new vertex with them.
@@ -83,31 +87,44 @@ func (i *issuer) Update() {
 		i.t.errs.Add(err)
 		return
 	}
-
-	// Issue a poll for this vertex.
-	p := i.t.Consensus.Parameters()
-	vdrs, err := i.t.Validators.Sample(p.K) // Validators to sample
-
-	vdrBag := ids.ShortBag{} // Validators to sample repr. as a set
-	for _, vdr := range vdrs {
-		vdrBag.Add(vdr.ID())
+	// This vertex is polled below, so adding it to the virtuous frontier
+	// doesn't require a repoll
+	i.t.virtuousChanged = false
+	if i.t.Consensus.NumProcessing() == 1 {
+		// Consensus was idle, so the time it was idle isn't a stall
+		i.t.progressed(time.Now())
 	}
 
-	vdrList := vdrBag.List()
-	vdrSet := ids.NewShortSet(len(vdrList))
-	vdrSet.Add(vdrList...)
+	// Issue a poll for this vertex, unless votes are only observed.
+	if !i.t.light() {
+		vdrBag, err := i.t.samplePoll(false) // Validators to sample
+
+		vdrList := vdrBag.List()
+		vdrSet := ids.NewShortSet(len(vdrList))
+		vdrSet.Add(vdrList...)
 
-	i.t.RequestID++
-	if err == nil && i.t.polls.Add(i.t.RequestID, vdrBag) {
-		i.t.Sender.PushQuery(vdrSet, i.t.RequestID, vtxID, i.vtx.Bytes())
-	} else if err != nil {
-		i.t.Ctx.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
+		i.t.RequestID++
+		if err == nil && i.t.polls.Add(i.t.RequestID, vtxID, vdrBag) {
+			i.t.Sender.PushQuery(vdrSet, i.t.RequestID, vtxID, i.vtx.Bytes())
+		} else if err != nil {
+			i.t.Ctx.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
+		}
 	}
 
 	// Notify vertices waiting on this one that it (and its transactions) have been issued.
 	i.t.vtxBlocked.Fulfill(vtxID)
+	issuedIDs := make([]ids.ID, 1, len(txs)+1)
+	issuedIDs[0] = vtxID
 	for _, tx := range txs {
-		i.t.txBlocked.Fulfill(tx.ID())
+		txID := tx.ID()
+		i.t.txBlocked.Fulfill(txID)
+		issuedIDs = append(issuedIDs, txID)
+	}
+
+	// Issue the orphans that were waiting on this vertex or its transactions
+	if err := i.t.reissueOrphans(issuedIDs...); err != nil {
+		i.t.errs.Add(err)
+		return
 	}
 
 	// Issue a repoll
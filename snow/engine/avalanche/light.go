@@ -0,0 +1,65 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// light returns true if the engine should observe the votes of validators
+// rather than poll them. A node that becomes a validator starts polling, since
+// its votes are then sampled by other validators.
+func (t *Transitive) light() bool {
+	return t.lightVerification && !t.Validators.Contains(t.Ctx.NodeID)
+}
+
+// observe that [vdr] gossiped [vtxID] as accepted, which is counted as its
+// vote for [vtxID] and the ancestry of [vtxID]. Once the votes of [K]
+// validators have been observed, or of every validator if there are fewer, the
+// votes are recorded as a poll.
+//
+// Unlike a poll, the observed validators aren't sampled by stake. Light
+// verification trusts that the validators that gossip to this node are
+// representative of the validator set, so it's only suitable for nodes that
+// serve reads.
+func (t *Transitive) observe(vdr ids.ShortID, vtxID ids.ID) error {
+	if !t.Validators.Contains(vdr) {
+		return nil
+	}
+	t.observedVotes[vdr] = vtxID
+
+	numVdrs := t.Validators.Len()
+	if len(t.observedVotes) < t.Params.K && len(t.observedVotes) < numVdrs {
+		return nil
+	}
+
+	// If there are fewer than [K] validators, each of their votes is counted
+	// multiple times, as it would be if they were sampled
+	count := 1
+	if numVdrs < t.Params.K {
+		count = (t.Params.K + numVdrs - 1) / numVdrs
+	}
+	vdrBag := ids.ShortBag{}
+	for vdr := range t.observedVotes {
+		vdrBag.AddCount(vdr, count)
+	}
+
+	votes := t.observedVotes
+	t.observedVotes = make(map[ids.ShortID]ids.ID, len(votes))
+
+	t.RequestID++
+	requestID := t.RequestID
+	if !t.polls.Add(requestID, vtxID, vdrBag) {
+		return nil
+	}
+	t.numObservedPolls.Inc()
+	t.Ctx.Log.Verbo("recording the votes of %d observed validators as poll %d", len(votes), requestID)
+
+	for vdr, vote := range votes {
+		if err := t.Chits(vdr, requestID, []ids.ID{vote}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
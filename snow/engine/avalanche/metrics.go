@@ -11,7 +11,14 @@ import (
 
 type metrics struct {
 	numVtxRequests, numPendingVts, numMissingTxs prometheus.Gauge
+	numOrphanVts                                 prometheus.Gauge
+	batchSize                                    prometheus.Gauge
 	getAncestorsVtxs                             prometheus.Histogram
+	numObservedPolls, numVirtuousRepolls         prometheus.Counter
+	numStalls, numRebootstraps                   prometheus.Counter
+	numFrontierFetches                           prometheus.Counter
+	numEvidence                                  *prometheus.CounterVec
+	numBenchedRedraws, numDegradedSamples        prometheus.Counter
 }
 
 // Initialize implements the Engine interface
@@ -31,6 +38,16 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 		Name:      "missing_txs",
 		Help:      "Number of missing transactions",
 	})
+	m.numOrphanVts = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphan_vts",
+		Help:      "Number of abandoned vertices waiting on their dependencies to be issued again",
+	})
+	m.batchSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "batch_size",
+		Help:      "Max number of transactions put into a vertex",
+	})
 	m.getAncestorsVtxs = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespace,
 		Name:      "get_ancestors_vtxs",
@@ -47,13 +64,69 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 			2000,
 		},
 	})
+	m.numObservedPolls = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "observed_polls",
+		Help:      "Number of polls recorded from the votes of validators observed in light verification",
+	})
+
+	m.numVirtuousRepolls = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "virtuous_repolls",
+		Help:      "Number of polls issued because the virtuous frontier changed",
+	})
+
+	m.numStalls = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stalls",
+		Help:      "Number of times no vertex was decided for longer than the stall threshold while vertices were processing",
+	})
+
+	m.numRebootstraps = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rebootstraps",
+		Help:      "Number of times bootstrapping was restarted because the chain fell behind the network",
+	})
+
+	m.numFrontierFetches = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "frontier_fetches",
+		Help:      "Number of vertices requested because a peer's accepted frontier contained them",
+	})
+
+	m.numEvidence = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "evidence",
+		Help:      "Number of times evidence of a peer misbehaving was recorded",
+	}, []string{"kind"})
+
+	m.numBenchedRedraws = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "poll_benched_redraws",
+		Help:      "Number of benched validators sampled for a poll that were replaced by drawing again",
+	})
+	m.numDegradedSamples = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "poll_degraded_samples",
+		Help:      "Number of polls that queried benched validators because they couldn't be replaced",
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(m.numVtxRequests),
 		registerer.Register(m.numPendingVts),
 		registerer.Register(m.numMissingTxs),
+		registerer.Register(m.numOrphanVts),
+		registerer.Register(m.batchSize),
 		registerer.Register(m.getAncestorsVtxs),
+		registerer.Register(m.numObservedPolls),
+		registerer.Register(m.numVirtuousRepolls),
+		registerer.Register(m.numStalls),
+		registerer.Register(m.numRebootstraps),
+		registerer.Register(m.numFrontierFetches),
+		registerer.Register(m.numEvidence),
+		registerer.Register(m.numBenchedRedraws),
+		registerer.Register(m.numDegradedSamples),
 	)
 	return errs.Err
 }
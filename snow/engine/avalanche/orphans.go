@@ -0,0 +1,140 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// orphan is a vertex that was abandoned because it depended on vertices or
+// txs that weren't issued
+type orphan struct {
+	vtx avalanche.Vertex
+	// Dependencies that haven't been issued yet
+	deps ids.Set
+	// Time the vertex was orphaned
+	added time.Time
+}
+
+// orphanPool holds orphaned vertices so that they can be issued again once
+// their dependencies are, rather than relying on the vertex being gossiped
+// again. The pool holds at most [maxSize] orphans, evicting the oldest first,
+// and drops orphans that have waited for [expiry].
+type orphanPool struct {
+	maxSize int
+	expiry  time.Duration
+	clock   timer.Clock
+
+	// vertex ID --> *orphan, ordered from oldest to newest
+	orphans linkedhashmap.LinkedHashmap
+	// dependency ID --> IDs of the orphans waiting on it
+	waiting map[ids.ID]ids.Set
+}
+
+func newOrphanPool(maxSize int, expiry time.Duration) *orphanPool {
+	return &orphanPool{
+		maxSize: maxSize,
+		expiry:  expiry,
+		orphans: linkedhashmap.New(),
+		waiting: make(map[ids.ID]ids.Set),
+	}
+}
+
+// Len returns the number of orphans in the pool
+func (p *orphanPool) Len() int { return p.orphans.Len() }
+
+// add [vtx] to the pool, to be issued again once [deps] have been issued. If
+// the pool is disabled, [vtx] is dropped.
+func (p *orphanPool) add(vtx avalanche.Vertex, deps ids.Set) {
+	if p.maxSize <= 0 || deps.Len() == 0 {
+		return
+	}
+	p.expire()
+
+	vtxID := vtx.ID()
+	p.remove(vtxID)
+	for p.orphans.Len() >= p.maxSize {
+		oldest, _ := p.orphans.Oldest()
+		p.remove(oldest.(*orphan).vtx.ID())
+	}
+
+	o := &orphan{
+		vtx:   vtx,
+		deps:  ids.NewSet(deps.Len()),
+		added: p.clock.Time(),
+	}
+	o.deps.Union(deps)
+	p.orphans.Put(vtxID, o)
+	for depID := range deps {
+		orphanIDs := p.waiting[depID]
+		orphanIDs.Add(vtxID)
+		p.waiting[depID] = orphanIDs
+	}
+}
+
+// fulfill marks that [depID] was issued. Returns the orphans that no longer
+// wait on any dependencies, which are removed from the pool.
+func (p *orphanPool) fulfill(depID ids.ID) []avalanche.Vertex {
+	orphanIDs, ok := p.waiting[depID]
+	if !ok {
+		return nil
+	}
+	delete(p.waiting, depID)
+
+	var ready []avalanche.Vertex
+	for vtxID := range orphanIDs {
+		oIntf, ok := p.orphans.Get(vtxID)
+		if !ok {
+			continue
+		}
+		o := oIntf.(*orphan)
+		o.deps.Remove(depID)
+		if o.deps.Len() == 0 {
+			p.orphans.Delete(vtxID)
+			ready = append(ready, o.vtx)
+		}
+	}
+	p.expire()
+	return ready
+}
+
+// remove the orphan [vtxID] from the pool, if it's there
+func (p *orphanPool) remove(vtxID ids.ID) {
+	oIntf, ok := p.orphans.Get(vtxID)
+	if !ok {
+		return
+	}
+	p.orphans.Delete(vtxID)
+	for depID := range oIntf.(*orphan).deps {
+		orphanIDs := p.waiting[depID]
+		orphanIDs.Remove(vtxID)
+		if orphanIDs.Len() == 0 {
+			delete(p.waiting, depID)
+		}
+	}
+}
+
+// expire removes the orphans that have waited for longer than [expiry]
+func (p *orphanPool) expire() {
+	if p.expiry <= 0 {
+		return
+	}
+	now := p.clock.Time()
+	for {
+		oldest, ok := p.orphans.Oldest()
+		if !ok {
+			return
+		}
+		o := oldest.(*orphan)
+		if now.Sub(o.added) < p.expiry {
+			return
+		}
+		p.remove(o.vtx.ID())
+	}
+}
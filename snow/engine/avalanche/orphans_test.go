@@ -0,0 +1,105 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+)
+
+func newOrphanVtx() *avalanche.TestVertex {
+	return &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+}
+
+func TestOrphanPoolFulfill(t *testing.T) {
+	p := newOrphanPool(2, 0)
+
+	vtx := newOrphanVtx()
+	dep0 := ids.GenerateTestID()
+	dep1 := ids.GenerateTestID()
+	p.add(vtx, ids.Set{dep0: struct{}{}, dep1: struct{}{}})
+	if p.Len() != 1 {
+		t.Fatalf("should have one orphan, has %d", p.Len())
+	}
+
+	if ready := p.fulfill(dep0); len(ready) != 0 {
+		t.Fatal("orphan is still waiting on a dependency")
+	}
+	if ready := p.fulfill(dep0); len(ready) != 0 {
+		t.Fatal("fulfilling a dependency twice shouldn't do anything")
+	}
+	ready := p.fulfill(dep1)
+	switch {
+	case len(ready) != 1:
+		t.Fatalf("should have returned the orphan, returned %d vertices", len(ready))
+	case ready[0].ID() != vtx.ID():
+		t.Fatalf("returned the wrong vertex %s", ready[0].ID())
+	case p.Len() != 0:
+		t.Fatal("shouldn't hold the returned orphan")
+	case len(p.waiting) != 0:
+		t.Fatal("shouldn't be waiting on any dependencies")
+	}
+}
+
+func TestOrphanPoolEvictsOldest(t *testing.T) {
+	p := newOrphanPool(2, 0)
+
+	vtx0 := newOrphanVtx()
+	vtx1 := newOrphanVtx()
+	vtx2 := newOrphanVtx()
+	dep := ids.GenerateTestID()
+	deps := ids.Set{dep: struct{}{}}
+	p.add(vtx0, deps)
+	p.add(vtx1, deps)
+	p.add(vtx2, deps)
+	if p.Len() != 2 {
+		t.Fatalf("should have two orphans, has %d", p.Len())
+	}
+
+	ready := ids.Set{}
+	for _, vtx := range p.fulfill(dep) {
+		ready.Add(vtx.ID())
+	}
+	if ready.Len() != 2 || ready.Contains(vtx0.ID()) {
+		t.Fatalf("should have evicted the oldest orphan, returned %s", ready)
+	}
+}
+
+func TestOrphanPoolExpiry(t *testing.T) {
+	p := newOrphanPool(2, time.Minute)
+	now := time.Now()
+	p.clock.Set(now)
+
+	vtx0 := newOrphanVtx()
+	vtx1 := newOrphanVtx()
+	dep := ids.GenerateTestID()
+	deps := ids.Set{dep: struct{}{}}
+	p.add(vtx0, deps)
+
+	p.clock.Set(now.Add(time.Minute))
+	p.add(vtx1, deps)
+	if p.Len() != 1 {
+		t.Fatalf("should have expired the oldest orphan, has %d orphans", p.Len())
+	}
+
+	ready := p.fulfill(dep)
+	if len(ready) != 1 || ready[0].ID() != vtx1.ID() {
+		t.Fatal("should only have returned the orphan that didn't expire")
+	}
+}
+
+func TestOrphanPoolDisabled(t *testing.T) {
+	p := newOrphanPool(0, 0)
+	p.add(newOrphanVtx(), ids.Set{ids.GenerateTestID(): struct{}{}})
+	if p.Len() != 0 {
+		t.Fatal("shouldn't hold orphans while disabled")
+	}
+}
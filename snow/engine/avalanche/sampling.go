@@ -0,0 +1,57 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Max number of times benched validators sampled for a poll are drawn again
+const maxBenchedRedraws = 3
+
+// samplePoll samples the validators queried by a poll. If [outstanding],
+// another poll is outstanding. If [skipBenched], each sampled validator that
+// is benched is replaced by drawing again, since its query would fail
+// immediately. Benched validators that are still sampled after
+// [maxBenchedRedraws] draws are queried anyway.
+func (t *Transitive) samplePoll(outstanding bool) (ids.ShortBag, error) {
+	vdrBag := ids.ShortBag{}
+	vdrs, err := t.repollSampler.Sample(t.Params.K, outstanding)
+	if err != nil {
+		return vdrBag, err
+	}
+
+	benched := []ids.ShortID(nil)
+	for _, vdr := range vdrs {
+		vdrID := vdr.ID()
+		if t.skipBenched && t.Benchlist.IsBenched(vdrID, t.Ctx.ChainID) {
+			benched = append(benched, vdrID)
+		} else {
+			vdrBag.Add(vdrID)
+		}
+	}
+
+	for i := 0; i < maxBenchedRedraws && len(benched) > 0; i++ {
+		redrawn, err := t.repollSampler.Sample(len(benched), outstanding)
+		if err != nil {
+			break
+		}
+		t.numBenchedRedraws.Add(float64(len(benched)))
+		benched = benched[:0]
+		for _, vdr := range redrawn {
+			vdrID := vdr.ID()
+			if t.Benchlist.IsBenched(vdrID, t.Ctx.ChainID) {
+				benched = append(benched, vdrID)
+			} else {
+				vdrBag.Add(vdrID)
+			}
+		}
+	}
+
+	if len(benched) > 0 {
+		t.numDegradedSamples.Inc()
+		vdrBag.Add(benched...)
+	}
+	return vdrBag, nil
+}
@@ -0,0 +1,95 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"time"
+)
+
+// progressed records that consensus made progress at [now], either because a
+// vertex was decided or because consensus started processing vertices after
+// being idle
+func (t *Transitive) progressed(now time.Time) {
+	if t.stalled {
+		t.Ctx.Log.Info("avalanche engine recovered after stalling for %s", now.Sub(t.lastProgress))
+	}
+	t.lastProgress = now
+	t.stalled = false
+}
+
+// checkStalled detects if no vertex has been decided for [stallThreshold]
+// while vertices are processing. While stalled, every check logs the state of
+// the engine at debug level, repolls the network, re-gossips a preferred
+// vertex, and requests the vertices that pending vertices are blocked on.
+func (t *Transitive) checkStalled(now time.Time) {
+	if t.stallThreshold <= 0 || !t.Ctx.IsBootstrapped() {
+		return
+	}
+	if t.Consensus.NumProcessing() == 0 {
+		t.progressed(now)
+		return
+	}
+	stalledFor := now.Sub(t.lastProgress)
+	if stalledFor <= t.stallThreshold {
+		return
+	}
+
+	preferredIDs := t.Consensus.Preferences()
+	blocked := t.BlockedContainers()
+	if !t.stalled {
+		t.stalled = true
+		t.numStalls.Inc()
+		t.Ctx.Log.Warn("avalanche engine hasn't decided a vertex for %s. Processing %d vertices, %d preferred, %d pending on missing dependencies, %d outstanding polls, %d outstanding vertex requests",
+			stalledFor,
+			t.Consensus.NumProcessing(),
+			preferredIDs.Len(),
+			len(blocked),
+			t.polls.Len(),
+			t.outstandingVtxReqs.Len(),
+		)
+	}
+	t.Ctx.Log.Debug("stalled avalanche engine state:\naccepted frontier: %s\npreferences: %s\npolls: %s",
+		t.Manager.Edge(),
+		preferredIDs,
+		t.polls,
+	)
+	for _, info := range blocked {
+		t.Ctx.Log.Debug("vertex %s is blocked on vertices %s and txs %s since %s",
+			info.ContainerID,
+			info.MissingContainers,
+			info.MissingTxs,
+			info.Since,
+		)
+	}
+
+	// Poll the network again, even if polls are outstanding, in case their
+	// responses were lost
+	t.issueRepoll(true)
+
+	// Re-gossip a preferred vertex, in case it wasn't received by the
+	// validators
+	if preferredIDs.Len() > 0 {
+		vtxID := preferredIDs.CappedList(1)[0]
+		if vtx, err := t.Manager.GetVtx(vtxID); err == nil {
+			t.Sender.Gossip(vtxID, vtx.Bytes())
+		}
+	}
+
+	// Request the vertices that pending vertices are blocked on, in case the
+	// earlier requests were dropped
+	if len(blocked) > 0 {
+		vdrs, err := t.Validators.Sample(1)
+		if err != nil {
+			return
+		}
+		vdrID := vdrs[0].ID()
+		for _, info := range blocked {
+			for _, missingID := range info.MissingContainers {
+				if vtx, err := t.Manager.GetVtx(missingID); err != nil || !vtx.Status().Fetched() {
+					t.sendRequest(vdrID, missingID)
+				}
+			}
+		}
+	}
+}
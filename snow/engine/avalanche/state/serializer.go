@@ -30,7 +30,10 @@ var (
 	errWrongChainID  = errors.New("wrong ChainID in vertex")
 )
 
-var _ vertex.Manager = &Serializer{}
+var (
+	_ vertex.Manager    = &Serializer{}
+	_ vertex.EdgeSyncer = &Serializer{}
+)
 
 // Serializer manages the state of multiple vertices
 type Serializer struct {
@@ -39,6 +42,9 @@ type Serializer struct {
 	state *prefixedState
 	db    *versiondb.Database
 	edge  ids.Set
+
+	// limits on the vertices that are built
+	limits vertex.Limits
 }
 
 // Initialize implements the avalanche.State interface
@@ -59,6 +65,9 @@ func (s *Serializer) Initialize(ctx *snow.Context, vm vertex.DAGVM, db database.
 	s.edge.Add(s.state.Edge()...)
 }
 
+// SetLimits sets the limits that vertices built by BuildVtx must be within
+func (s *Serializer) SetLimits(limits vertex.Limits) { s.limits = limits }
+
 // Parse implements the avalanche.State interface
 func (s *Serializer) ParseVtx(b []byte) (avalanche.Vertex, error) {
 	return newUniqueVertex(s, b)
@@ -90,7 +99,8 @@ func (s *Serializer) BuildVtx(
 		txBytes[i] = tx.Bytes()
 	}
 
-	vtx, err := vertex.Build(
+	vtx, err := vertex.BuildWithLimits(
+		s.limits,
 		s.ctx.ChainID,
 		height,
 		epoch,
@@ -117,6 +127,28 @@ func (s *Serializer) GetVtx(vtxID ids.ID) (avalanche.Vertex, error) { return s.g
 // Edge implements the avalanche.State interface
 func (s *Serializer) Edge() []ids.ID { return s.edge.List() }
 
+// SyncEdge implements the vertex.EdgeSyncer interface
+func (s *Serializer) SyncEdge(vtxIDs []ids.ID) error {
+	for _, vtxID := range vtxIDs {
+		vtx, err := s.getVertex(vtxID)
+		if err != nil {
+			return err
+		}
+		if err := vtx.setStatus(choices.Accepted); err != nil {
+			return err
+		}
+		// The ancestors of a synced vertex aren't known
+		vtx.v.parents = nil
+	}
+
+	s.edge.Clear()
+	s.edge.Add(vtxIDs...)
+	if err := s.state.SetEdge(s.edge.List()); err != nil {
+		return err
+	}
+	return s.db.Commit()
+}
+
 func (s *Serializer) parseVertex(b []byte) (vertex.StatelessVertex, error) {
 	vtx, err := vertex.Parse(b)
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
@@ -27,9 +28,26 @@ const (
 	// TODO define this constant in one place rather than here and in snowman
 	// Max containers size in a MultiPut message
 	maxContainersLen = int(4 * network.DefaultMaxMessageSize / 5)
+
+	// Halflife of the average time between polls finishing
+	pollIntervalHalflife = 10 * time.Second
+
+	// Estimated times until acceptance that are longer than this aren't
+	// reported
+	maxETA = 24 * time.Hour
 )
 
-var _ Engine = &Transitive{}
+var (
+	_ Engine                  = &Transitive{}
+	_ common.PollReporter     = &Transitive{}
+	_ common.FrontierReporter = &Transitive{}
+	_ common.ParameterUpdater = &Transitive{}
+
+	_ common.TxFinalityReporter  = &Transitive{}
+	_ common.BlockedReporter     = &Transitive{}
+	_ common.StateSummaryHandler = &Transitive{}
+	_ common.EvidenceReporter    = &Transitive{}
+)
 
 // Transitive implements the Engine interface by attempting to fetch all
 // transitive dependencies.
@@ -42,15 +60,36 @@ type Transitive struct {
 
 	polls poll.Set // track people I have asked for their preference
 
+	// Results of the polls that finished while handling the current message.
+	// They are recorded together once the message is handled.
+	finishedPolls []ids.UniqueBag
+
+	// If true, no polls are issued while this node isn't a validator
+	lightVerification bool
+
+	// If true, a repoll is issued when a poll changes the virtuous frontier
+	virtuousRepoll bool
+
+	// Validator --> the vertex it most recently gossiped as accepted, since
+	// the last observed poll was recorded. Only used in light verification.
+	observedVotes map[ids.ShortID]ids.ID
+
+	// samples the validators queried by repolls
+	repollSampler *common.RepollSampler
+
 	// The set of vertices that have been requested in Get messages but not yet received
 	outstandingVtxReqs common.Requests
 
 	// missingTxs tracks transaction that are missing
 	missingTxs ids.Set
 
-	// IDs of vertices that are queued to be added to consensus but haven't yet been
-	// because of missing dependencies
-	pending ids.Set
+	// Vertices that are queued to be added to consensus but haven't yet been
+	// because of missing dependencies, mapped to their issuers
+	pending map[ids.ID]*issuer
+
+	// Vertices that were abandoned because of missing dependencies, which are
+	// issued again once their dependencies are
+	orphans *orphanPool
 
 	// vtxBlocked tracks operations that are blocked on vertices
 	// txBlocked tracks operations that are blocked on transactions
@@ -61,9 +100,64 @@ type Transitive struct {
 	// optimal number.
 	pendingTxs []snowstorm.Tx
 
+	// adapts the max number of txs put into a vertex to the backlog of
+	// [pendingTxs]
+	batchSizer batchSizer
+
+	// limits on the vertices this node builds
+	vtxLimits vertex.Limits
+
+	// If greater than 1, the max number of goroutines that verify the txs of
+	// an issued vertex concurrently
+	txVerifiers int
+
+	// If positive, how often the accepted frontier of a validator is
+	// requested
+	frontierSyncFrequency time.Duration
+	// Last time the accepted frontier of a validator was requested
+	lastFrontierSync time.Time
+	// The outstanding accepted frontier request, if [frontierSyncPending]
+	frontierSyncPending bool
+	frontierSyncVdr     ids.ShortID
+	frontierSyncReqID   uint32
+
+	// evidence of peers misbehaving
+	evidence *common.EvidenceStore
+
+	// If true, benched validators sampled for a poll are drawn again
+	skipBenched bool
+
+	// If positive, the engine is stalled if no vertex is decided for this
+	// long while vertices are processing
+	stallThreshold time.Duration
+	// Last time a vertex was decided, or consensus started processing
+	// vertices after being idle
+	lastProgress time.Time
+	// True if the engine is currently stalled
+	stalled bool
+
 	// A uniform sampler without replacement
 	uniformSampler sampler.Uniform
 
+	// Time the last poll finished. Zero if the engine quiesced after the last
+	// poll finished.
+	lastPollFinished time.Time
+
+	// Average time between polls finishing. Nil until two consecutive polls
+	// have finished.
+	pollInterval math.Averager
+
+	// True if a poll changed the virtuous frontier since the last repoll. Only
+	// used if [virtuousRepoll].
+	virtuousChanged bool
+
+	// Restarts bootstrapping once no vertex has been accepted for a while
+	// although a quorum of beacons gossiped higher accepted vertices
+	stale *common.StaleDetector
+
+	// The accepted frontier the last time it was observed
+	lastEdge []ids.ID
+
 	errs wrappers.Errs
 }
 
@@ -73,6 +167,37 @@ func (t *Transitive) Initialize(config Config) error {
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.lightVerification = config.LightVerification
+	t.virtuousRepoll = config.VirtuousRepoll
+	if t.virtuousRepoll {
+		// Registered once, as consensus keeps the function when it's
+		// initialized again after bootstrapping again
+		t.Consensus.OnVirtuousChange(func() { t.virtuousChanged = true })
+	}
+	t.observedVotes = make(map[ids.ShortID]ids.ID)
+	t.pending = make(map[ids.ID]*issuer)
+	t.orphans = newOrphanPool(config.MaxOrphans, config.OrphanExpiry)
+	t.stallThreshold = config.StallThreshold
+	t.txVerifiers = config.TxVerifiers
+	t.frontierSyncFrequency = config.FrontierSyncFrequency
+	t.evidence = common.NewEvidenceStore(maxEvidence)
+	t.skipBenched = config.SkipBenched && config.Benchlist != nil
+	t.stale = common.NewStaleDetector(config.Beacons, config.Alpha, config.StaleThreshold)
+
+	if err := config.VertexLimits.Valid(); err != nil {
+		return fmt.Errorf("invalid vertex limits: %w", err)
+	}
+	t.vtxLimits = config.VertexLimits.WithDefaults()
+
+	minBatchSize := config.Params.BatchSize
+	if minBatchSize > t.vtxLimits.MaxTxs {
+		minBatchSize = t.vtxLimits.MaxTxs
+	}
+	maxBatchSize := config.Params.MaxBatchSize
+	if maxBatchSize > t.vtxLimits.MaxTxs {
+		maxBatchSize = t.vtxLimits.MaxTxs
+	}
+	t.batchSizer = newBatchSizer(minBatchSize, maxBatchSize)
 
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t.polls = poll.NewSet(factory,
@@ -86,6 +211,18 @@ func (t *Transitive) Initialize(config Config) error {
 		return err
 	}
 
+	var err error
+	t.repollSampler, err = common.NewRepollSampler(
+		config.Validators,
+		config.Latencies,
+		config.RepollLatencyBias,
+		config.Params.Namespace,
+		config.Params.Metrics,
+	)
+	if err != nil {
+		return err
+	}
+
 	return t.Bootstrapper.Initialize(
 		config.Config,
 		t.finishBootstrapping,
@@ -107,12 +244,21 @@ func (t *Transitive) finishBootstrapping() error {
 	}
 
 	t.Ctx.Log.Info("bootstrapping finished with %d vertices in the accepted frontier", len(frontier))
+	t.lastEdge = edge
+	t.stale.Accepted(t.edgeHeight(frontier), time.Now())
 	return t.Consensus.Initialize(t.Ctx, t.Params, frontier)
 }
 
 // Gossip implements the Engine interface
 func (t *Transitive) Gossip() error {
+	now := time.Now()
+	t.checkStalled(now)
+	t.syncFrontier(now)
+
 	edge := t.Manager.Edge()
+	if !t.observeEdge(edge, now) && t.Ctx.IsBootstrapped() && t.stale.Stale(now) {
+		return t.rebootstrap()
+	}
 	if len(edge) == 0 {
 		t.Ctx.Log.Verbo("dropping gossip request as no vertices have been accepted")
 		return nil
@@ -140,7 +286,13 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
-	return t.VM.Shutdown()
+	errs := wrappers.Errs{}
+	errs.Add(
+		t.VM.Shutdown(),
+		t.VtxBlocked.Close(),
+		t.TxBlocked.Close(),
+	)
+	return errs.Err
 }
 
 // Get implements the Engine interface
@@ -201,6 +353,42 @@ func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, vtxID ids.I
 	return nil
 }
 
+// GetStateSummary implements the common.StateSummaryHandler interface
+func (t *Transitive) GetStateSummary(vdr ids.ShortID, requestID uint32) error {
+	if !t.Ctx.IsBootstrapped() {
+		return t.Bootstrapper.GetStateSummary(vdr, requestID)
+	}
+	vm, ok := t.VM.(vertex.StateSyncableVM)
+	if !ok {
+		t.Ctx.Log.Verbo("dropping GetStateSummary(%s, %d) as the VM doesn't support state sync", vdr, requestID)
+		return nil
+	}
+
+	edge := t.Manager.Edge()
+	frontier := make([][]byte, 0, len(edge))
+	frontierLen := 0
+	for _, vtxID := range edge {
+		vtx, err := t.Manager.GetVtx(vtxID)
+		if err != nil {
+			return err
+		}
+		vtxBytes := vtx.Bytes()
+		frontier = append(frontier, vtxBytes)
+		frontierLen += wrappers.IntLen + len(vtxBytes)
+	}
+	summary, err := vm.StateSummary(edge)
+	if err != nil {
+		t.Ctx.Log.Debug("dropping GetStateSummary(%s, %d) as the state couldn't be summarized: %s", vdr, requestID, err)
+		return nil
+	}
+	if frontierLen+len(summary) >= maxContainersLen {
+		t.Ctx.Log.Debug("dropping GetStateSummary(%s, %d) as the summary of %d bytes is too large", vdr, requestID, len(summary))
+		return nil
+	}
+	t.Sender.StateSummary(vdr, requestID, frontier, summary)
+	return nil
+}
+
 // Put implements the Engine interface
 func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxBytes []byte) error {
 	t.Ctx.Log.Verbo("Put(%s, %d, %s) called", vdr, requestID, vtxID)
@@ -220,9 +408,24 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
 		return t.GetFailed(vdr, requestID)
 	}
+	t.checkPut(vdr, requestID, vtx.ID())
+
+	// Peers gossip the vertices they accepted
+	if requestID == constants.GossipMsgRequestID {
+		if height, err := vtx.Height(); err == nil {
+			t.stale.Gossiped(vdr, height)
+		}
+	}
+
 	if _, err := t.issueFrom(vdr, vtx); err != nil {
 		return err
 	}
+	if requestID == constants.GossipMsgRequestID && t.light() {
+		// [vdr] gossips vertices after accepting them
+		if err := t.observe(vdr, vtx.ID()); err != nil {
+			return err
+		}
+	}
 	return t.attemptToIssueTxs()
 }
 
@@ -314,6 +517,8 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID,
 func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) error {
 	if !t.Ctx.IsBootstrapped() {
 		t.Ctx.Log.Debug("dropping Chits(%s, %d) due to bootstrapping", vdr, requestID)
+		// Discard the poll if it was issued before bootstrapping was restarted
+		t.polls.Vote(requestID, vdr, nil)
 		return nil
 	}
 
@@ -362,17 +567,87 @@ func (t *Transitive) attemptToIssueTxs() error {
 	if err != nil {
 		return err
 	}
+	if err := t.recordPolls(); err != nil {
+		return err
+	}
+
+	size := t.batchSizer.update(len(t.pendingTxs), t.Consensus.Quiesce())
+	t.batchSize.Set(float64(size))
 
 	t.pendingTxs, err = t.batch(t.pendingTxs, false /*=force*/, false /*=empty*/, true /*=limit*/)
 	return err
 }
 
+// recordPolls records the results of the finished polls in one pass, so that
+// the preferences and frontiers are only updated once for all of them.
+// Recording the polls may issue vertices that finish more polls, so this
+// repeats until no finished polls are left.
+func (t *Transitive) recordPolls() error {
+	for len(t.finishedPolls) > 0 && !t.errs.Errored() {
+		polls := t.finishedPolls
+		t.finishedPolls = nil
+
+		numProcessing := t.Consensus.NumProcessing()
+		if err := t.Consensus.RecordPolls(polls); err != nil {
+			return err
+		}
+		now := time.Now()
+		for range polls {
+			t.pollFinished(now)
+		}
+		if t.Consensus.NumProcessing() < numProcessing {
+			t.progressed(now)
+		}
+
+		orphans := t.Consensus.Orphans()
+		txs := make([]snowstorm.Tx, 0, orphans.Len())
+		for orphanID := range orphans {
+			if tx, err := t.VM.GetTx(orphanID); err == nil {
+				txs = append(txs, tx)
+			} else {
+				t.Ctx.Log.Warn("Failed to fetch %s during attempted re-issuance", orphanID)
+			}
+		}
+		if len(txs) > 0 {
+			t.Ctx.Log.Debug("Re-issuing %d transactions", len(txs))
+		}
+		if _, err := t.batch(txs, true /*=force*/, false /*empty*/, false /*=limit*/); err != nil {
+			return err
+		}
+
+		if t.Consensus.Quiesce() {
+			t.Ctx.Log.Debug("Avalanche engine can quiesce")
+			// The time until the next poll finishes depends on when new txs are
+			// issued, so it shouldn't be counted as time between polls.
+			t.lastPollFinished = time.Time{}
+			// Nothing virtuous is left to poll
+			t.virtuousChanged = false
+			continue
+		}
+
+		t.Ctx.Log.Debug("Avalanche engine can't quiesce")
+		t.repoll()
+	}
+	return t.errs.Err
+}
+
 // If there are pending transactions from the VM, issue them.
 // If we're not already at the limit for number of concurrent polls, issue a new
 // query.
 func (t *Transitive) repoll() {
 	for i := t.polls.Len(); i < t.Params.ConcurrentRepolls && !t.errs.Errored(); i++ {
-		t.issueRepoll()
+		t.issueRepoll(i > 0)
+	}
+
+	// If the virtuous frontier changed, it is polled immediately rather than
+	// once an outstanding poll finishes. At most [ConcurrentRepolls] additional
+	// polls are outstanding.
+	if t.virtuousChanged {
+		t.virtuousChanged = false
+		if t.polls.Len() < 2*t.Params.ConcurrentRepolls && !t.errs.Errored() {
+			t.numVirtuousRepolls.Inc()
+			t.issueRepoll(true)
+		}
 	}
 }
 
@@ -407,7 +682,7 @@ func (t *Transitive) issueFrom(vdr ids.ShortID, vtx avalanche.Vertex) (bool, err
 			// No need to try to issue it or its ancestors
 			continue
 		}
-		if t.pending.Contains(vtx.ID()) {
+		if _, ok := t.pending[vtx.ID()]; ok {
 			issued = false
 			continue
 		}
@@ -417,17 +692,22 @@ func (t *Transitive) issueFrom(vdr ids.ShortID, vtx avalanche.Vertex) (bool, err
 			return false, err
 		}
 		// Ensure we have ancestors of this vertex
+		fetchedParents := true
 		for _, parent := range parents {
 			if !parent.Status().Fetched() {
 				// We don't have the parent. Request it.
 				t.sendRequest(vdr, parent.ID())
 				// We're missing an ancestor so we can't have issued the vtx in this method's argument
 				issued = false
+				fetchedParents = false
 			} else {
 				// Come back to this vertex later to make sure it and its ancestors have been fetched/issued
 				ancestry.Push(parent)
 			}
 		}
+		if fetchedParents {
+			t.checkAncestry(vdr, vtx, parents)
+		}
 
 		// Queue up this vertex to be issued once its dependencies are met
 		if err := t.issue(vtx); err != nil {
@@ -442,16 +722,17 @@ func (t *Transitive) issueFrom(vdr ids.ShortID, vtx avalanche.Vertex) (bool, err
 func (t *Transitive) issue(vtx avalanche.Vertex) error {
 	vtxID := vtx.ID()
 
-	// Add to set of vertices that have been queued up to be issued but haven't been yet
-	t.pending.Add(vtxID)
-	t.outstandingVtxReqs.RemoveAny(vtxID)
-
 	// Will put [vtx] into consensus once dependencies are met
 	i := &issuer{
-		t:   t,
-		vtx: vtx,
+		t:     t,
+		vtx:   vtx,
+		start: time.Now(),
 	}
 
+	// Add to set of vertices that have been queued up to be issued but haven't been yet
+	t.pending[vtxID] = i
+	t.outstandingVtxReqs.RemoveAny(vtxID)
+
 	parents, err := vtx.Parents()
 	if err != nil {
 		return err
@@ -502,7 +783,7 @@ func (t *Transitive) issue(vtx avalanche.Vertex) error {
 	// Track performance statistics
 	t.numVtxRequests.Set(float64(t.outstandingVtxReqs.Len()))
 	t.numMissingTxs.Set(float64(t.missingTxs.Len()))
-	t.numPendingVts.Set(float64(t.pending.Len()))
+	t.numPendingVts.Set(float64(len(t.pending)))
 	return t.errs.Err
 }
 
@@ -511,7 +792,9 @@ func (t *Transitive) issue(vtx avalanche.Vertex) error {
 // Otherwise, some txs may not be put into vertices that are issued.
 // If [empty], will always result in a new poll.
 func (t *Transitive) batch(txs []snowstorm.Tx, force, empty, limit bool) ([]snowstorm.Tx, error) {
-	if limit && t.Params.OptimalProcessing <= t.Consensus.NumProcessing() {
+	// While consensus is saturated, locally issued txs are held until some are
+	// decided
+	if limit && (t.Params.OptimalProcessing <= t.Consensus.NumProcessing() || t.Consensus.Saturated()) {
 		return txs, nil
 	}
 	issuedTxs := ids.Set{}
@@ -520,29 +803,35 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty, limit bool) ([]snow
 	orphans := t.Consensus.Orphans()
 	start := 0
 	end := 0
+	headerSize := vertex.HeaderSize(t.Params.Parents)
+	size := headerSize // serialized size of the vertex txs[start:end] is put in
 	for end < len(txs) {
 		tx := txs[end]
+		txSize := vertex.TxSize(len(tx.Bytes()))
 		inputs := ids.Set{}
 		inputs.Add(tx.InputIDs()...)
 		overlaps := consumed.Overlaps(inputs)
-		if end-start >= t.Params.BatchSize || (force && overlaps) {
+		if end-start >= t.batchSizer.size || (force && overlaps) || (end > start && size+txSize > t.vtxLimits.MaxBytes) {
 			if err := t.issueBatch(txs[start:end]); err != nil {
 				return nil, err
 			}
-			if limit && t.Params.OptimalProcessing <= t.Consensus.NumProcessing() {
+			if limit && (t.Params.OptimalProcessing <= t.Consensus.NumProcessing() || t.Consensus.Saturated()) {
 				return txs[end:], nil
 			}
 			start = end
+			size = headerSize
 			consumed.Clear()
 			issued = true
 			overlaps = false
 		}
 
-		if txID := tx.ID(); !overlaps && // should never allow conflicting txs in the same vertex
+		if txID := tx.ID(); headerSize+txSize <= t.vtxLimits.MaxBytes && // a tx that doesn't fit in a vertex can't be issued
+			!overlaps && // should never allow conflicting txs in the same vertex
 			!issuedTxs.Contains(txID) && // shouldn't issue duplicated transactions to the same vertex
 			(force || t.Consensus.IsVirtuous(tx)) && // force allows for a conflict to be issued
 			(!t.Consensus.TxIssued(tx) || orphans.Contains(txID)) { // should only reissue orphaned txs
 			end++
+			size += txSize
 			issuedTxs.Add(txID)
 			consumed.Union(inputs)
 		} else {
@@ -557,13 +846,19 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty, limit bool) ([]snow
 		return txs[end:], t.issueBatch(txs[start:end])
 	}
 	if empty && !issued {
-		t.issueRepoll()
+		t.issueRepoll(t.polls.Len() > 0)
 	}
 	return txs[end:], nil
 }
 
-// Issues a new poll for a preferred vertex in order to move consensus along
-func (t *Transitive) issueRepoll() {
+// Issues a new poll for a preferred vertex in order to move consensus along.
+// [outstanding] is true if another poll is outstanding.
+func (t *Transitive) issueRepoll(outstanding bool) {
+	if t.light() {
+		t.Ctx.Log.Verbo("dropping re-query attempt due to light verification")
+		return
+	}
+
 	preferredIDs := t.Consensus.Preferences()
 	if preferredIDs.Len() == 0 {
 		t.Ctx.Log.Error("re-query attempt was dropped due to no pending vertices")
@@ -571,11 +866,7 @@ func (t *Transitive) issueRepoll() {
 	}
 
 	vtxID := preferredIDs.CappedList(1)[0]
-	vdrs, err := t.Validators.Sample(t.Params.K) // Validators to sample
-	vdrBag := ids.ShortBag{}                     // IDs of validators to be sampled
-	for _, vdr := range vdrs {
-		vdrBag.Add(vdr.ID())
-	}
+	vdrBag, err := t.samplePoll(outstanding) // IDs of validators to be sampled
 
 	vdrList := vdrBag.List()
 	vdrSet := ids.NewShortSet(len(vdrList))
@@ -583,7 +874,7 @@ func (t *Transitive) issueRepoll() {
 
 	// Poll the network
 	t.RequestID++
-	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+	if err == nil && t.polls.Add(t.RequestID, vtxID, vdrBag) {
 		t.Sender.PullQuery(vdrSet, t.RequestID, vtxID)
 	} else if err != nil {
 		t.Ctx.Log.Error("re-query for %s was dropped due to an insufficient number of validators", vtxID)
@@ -617,9 +908,33 @@ func (t *Transitive) issueBatch(txs []snowstorm.Tx) error {
 			len(parentIDs), len(txs))
 		return nil
 	}
+	if t.light() {
+		// This node won't query validators about [vtx], so they must learn
+		// about it from gossip
+		t.Sender.Gossip(vtx.ID(), vtx.Bytes())
+	}
 	return t.issue(vtx)
 }
 
+// reissueOrphans issues the orphaned vertices that were only waiting on
+// [depIDs], which were just issued
+func (t *Transitive) reissueOrphans(depIDs ...ids.ID) error {
+	for _, depID := range depIDs {
+		for _, vtx := range t.orphans.fulfill(depID) {
+			if _, ok := t.pending[vtx.ID()]; ok || t.Consensus.VertexIssued(vtx) {
+				// This vertex was received again since it was orphaned
+				continue
+			}
+			t.Ctx.Log.Verbo("re-issuing orphaned vertex %s", vtx.ID())
+			if err := t.issue(vtx); err != nil {
+				return err
+			}
+		}
+	}
+	t.numOrphanVts.Set(float64(t.orphans.Len()))
+	return nil
+}
+
 // Send a request to [vdr] asking them to send us vertex [vtxID]
 func (t *Transitive) sendRequest(vdr ids.ShortID, vtxID ids.ID) {
 	if t.outstandingVtxReqs.Contains(vtxID) {
@@ -632,27 +947,204 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, vtxID ids.ID) {
 	t.numVtxRequests.Set(float64(t.outstandingVtxReqs.Len())) // Tracks performance statistics
 }
 
+// OutstandingPolls implements the common.PollReporter interface
+func (t *Transitive) OutstandingPolls() []common.PollInfo {
+	polls := t.polls.Polls()
+	infos := make([]common.PollInfo, len(polls))
+	for i, poll := range polls {
+		infos[i] = common.PollInfo(poll)
+	}
+	return infos
+}
+
+// BlockedContainers implements the common.BlockedReporter interface
+func (t *Transitive) BlockedContainers() []common.BlockedInfo {
+	infos := make([]common.BlockedInfo, 0, len(t.pending))
+	for vtxID, i := range t.pending {
+		infos = append(infos, common.BlockedInfo{
+			ContainerID:       vtxID,
+			MissingContainers: i.vtxDeps.List(),
+			MissingTxs:        i.txDeps.List(),
+			Since:             i.start,
+		})
+	}
+	return infos
+}
+
+// Frontier implements the common.FrontierReporter interface
+func (t *Transitive) Frontier() ([]ids.ID, uint64, error) {
+	edge := t.Manager.Edge()
+	height := uint64(0)
+	for _, vtxID := range edge {
+		vtx, err := t.Manager.GetVtx(vtxID)
+		if err != nil {
+			return nil, 0, err
+		}
+		vtxHeight, err := vtx.Height()
+		if err != nil {
+			return nil, 0, err
+		}
+		if vtxHeight > height {
+			height = vtxHeight
+		}
+	}
+	return edge, height, nil
+}
+
+// TxFinality implements the common.TxFinalityReporter interface
+func (t *Transitive) TxFinality(txID ids.ID) (common.TxFinality, bool) {
+	if !t.Ctx.IsBootstrapped() {
+		return common.TxFinality{}, false
+	}
+	stats, ok := t.Consensus.TxPollStats(txID)
+	if !ok {
+		return common.TxFinality{}, false
+	}
+
+	params := t.Params.Parameters
+	finality := common.TxFinality{
+		Confidence:         stats.Confidence,
+		Beta:               stats.Beta(params),
+		NumConflicts:       stats.NumConflicts,
+		NumPolls:           stats.NumPolls,
+		NumSuccessfulPolls: stats.NumSuccessfulPolls,
+		VoteFraction:       stats.VoteFraction(params),
+		Probability:        stats.AcceptanceProbability(params),
+	}
+	if t.pollInterval != nil {
+		// If the tx isn't expected to be accepted, the expected number of polls
+		// is infinite, so no ETA is reported.
+		if eta := stats.ExpectedPolls(params) * t.pollInterval.Read(); eta <= float64(maxETA) {
+			finality.ETA = time.Duration(eta)
+		}
+	}
+	return finality, true
+}
+
+// pollFinished records that a poll finished at the given time
+func (t *Transitive) pollFinished(currentTime time.Time) {
+	if !t.lastPollFinished.IsZero() {
+		interval := float64(currentTime.Sub(t.lastPollFinished))
+		if t.pollInterval == nil {
+			t.pollInterval = math.NewAverager(interval, pollIntervalHalflife, currentTime)
+		} else {
+			t.pollInterval.Observe(interval, currentTime)
+		}
+	}
+	t.lastPollFinished = currentTime
+}
+
+// SamplingParameters implements the common.ParameterUpdater interface
+func (t *Transitive) SamplingParameters() common.SamplingParameters {
+	return common.SamplingParameters{
+		K:                 t.Params.K,
+		Alpha:             t.Params.Alpha,
+		ConcurrentRepolls: t.Params.ConcurrentRepolls,
+	}
+}
+
+// UpdateSamplingParameters implements the common.ParameterUpdater interface
+func (t *Transitive) UpdateSamplingParameters(update common.SamplingParameters) error {
+	params := t.Params
+	params.K = update.K
+	params.Alpha = update.Alpha
+	params.ConcurrentRepolls = update.ConcurrentRepolls
+	if err := t.Params.VerifyUpdate(params); err != nil {
+		return err
+	}
+
+	// Consensus is initialized with [t.Params] once bootstrapping finishes
+	if t.Ctx.IsBootstrapped() {
+		if err := t.Consensus.UpdateParameters(params); err != nil {
+			return err
+		}
+	}
+	t.Params = params
+	t.polls.SetFactory(poll.NewEarlyTermNoTraversalFactory(params.Alpha))
+	t.Ctx.Log.Info("updated sampling parameters to k = %d, alpha = %d, concurrentRepolls = %d",
+		params.K, params.Alpha, params.ConcurrentRepolls)
+	return nil
+}
+
+// observeEdge records that [edge] is the accepted frontier at [now]. A vertex
+// was accepted since the frontier was last observed if it changed. Returns
+// true if it changed.
+func (t *Transitive) observeEdge(edge []ids.ID, now time.Time) bool {
+	if ids.UnsortedEquals(edge, t.lastEdge) {
+		return false
+	}
+	t.lastEdge = edge
+
+	frontier := make([]avalanche.Vertex, 0, len(edge))
+	for _, vtxID := range edge {
+		if vtx, err := t.Manager.GetVtx(vtxID); err == nil {
+			frontier = append(frontier, vtx)
+		}
+	}
+	t.stale.Accepted(t.edgeHeight(frontier), now)
+	return true
+}
+
+// edgeHeight returns the max height of the vertices in [frontier]
+func (t *Transitive) edgeHeight(frontier []avalanche.Vertex) uint64 {
+	maxHeight := uint64(0)
+	for _, vtx := range frontier {
+		height, err := vtx.Height()
+		if err != nil {
+			t.Ctx.Log.Debug("couldn't get the height of %s due to %s", vtx.ID(), err)
+			continue
+		}
+		if height > maxHeight {
+			maxHeight = height
+		}
+	}
+	return maxHeight
+}
+
+// rebootstrap moves the chain back into bootstrapping. Consensus is
+// re-initialized once bootstrapping finishes, so everything waiting on the
+// current consensus instance is dropped.
+func (t *Transitive) rebootstrap() error {
+	_, lastAcceptedTime := t.stale.LastAccepted()
+	t.Ctx.Log.Warn("no vertex has been accepted since %s while beacons report higher vertices. Restarting bootstrapping",
+		lastAcceptedTime)
+	t.numRebootstraps.Inc()
+
+	t.finishedPolls = nil
+	t.observedVotes = make(map[ids.ShortID]ids.ID)
+	t.outstandingVtxReqs = common.Requests{}
+	t.missingTxs.Clear()
+	t.pending = make(map[ids.ID]*issuer)
+	t.orphans = newOrphanPool(t.orphans.maxSize, t.orphans.expiry)
+	t.vtxBlocked = events.Blocker{}
+	t.txBlocked = events.Blocker{}
+	t.numVtxRequests.Set(0)
+	t.numMissingTxs.Set(0)
+	t.numPendingVts.Set(0)
+	t.numOrphanVts.Set(0)
+	return t.Bootstrapper.Rebootstrap()
+}
+
 // Health implements the common.Engine interface
 func (t *Transitive) HealthCheck() (interface{}, error) {
+	engineHealth := common.EngineHealth{
+		Bootstrapped:     t.Ctx.IsBootstrapped(),
+		OutstandingPolls: t.polls.Len(),
+	}
 	var (
 		consensusIntf interface{} = struct{}{}
 		consensusErr  error
 	)
-	if t.Ctx.IsBootstrapped() {
+	if engineHealth.Bootstrapped {
+		now := time.Now()
+		t.observeEdge(t.Manager.Edge(), now)
+		_, lastAcceptedTime := t.stale.LastAccepted()
+		engineHealth.Processing = t.Consensus.NumProcessing()
+		engineHealth.LastAcceptedAge = now.Sub(lastAcceptedTime).String()
 		consensusIntf, consensusErr = t.Consensus.HealthCheck()
 	}
 	vmIntf, vmErr := t.VM.HealthCheck()
-	intf := map[string]interface{}{
-		"consensus": consensusIntf,
-		"vm":        vmIntf,
-	}
-	if consensusErr == nil {
-		return intf, vmErr
-	}
-	if vmErr == nil {
-		return intf, consensusErr
-	}
-	return intf, fmt.Errorf("vm: %s ; consensus: %s", vmErr, consensusErr)
+	return common.ChainHealth(engineHealth, consensusIntf, consensusErr, vmIntf, vmErr)
 }
 
 // GetVtx returns a vertex by its ID.
@@ -7,10 +7,12 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -20,6 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
@@ -668,6 +671,173 @@ func TestEngineBlockedIssue(t *testing.T) {
 	}
 }
 
+func TestEngineBlockedContainers(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, ids.GenerateTestID())
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{gVtx},
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{
+			&avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+				IDV:     vtx0.IDV,
+				StatusV: choices.Unknown,
+			}},
+		},
+		HeightV: 2,
+		TxsV:    []snowstorm.Tx{tx0},
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if blocked := te.BlockedContainers(); len(blocked) != 0 {
+		t.Fatalf("shouldn't have any blocked containers, got %d", len(blocked))
+	}
+
+	if err := te.issue(vtx1); err != nil {
+		t.Fatal(err)
+	}
+
+	blocked := te.BlockedContainers()
+	switch {
+	case len(blocked) != 1:
+		t.Fatalf("should have one blocked container, got %d", len(blocked))
+	case blocked[0].ContainerID != vtx1.ID():
+		t.Fatalf("wrong container %s is blocked", blocked[0].ContainerID)
+	case !ids.Equals(blocked[0].MissingContainers, []ids.ID{vtx0.ID()}):
+		t.Fatalf("should be blocked on %s, but is blocked on %v", vtx0.ID(), blocked[0].MissingContainers)
+	case len(blocked[0].MissingTxs) != 0:
+		t.Fatalf("shouldn't be blocked on txs, but is blocked on %v", blocked[0].MissingTxs)
+	case blocked[0].Since.IsZero():
+		t.Fatal("should have recorded when the container was blocked")
+	}
+
+	vtx1.ParentsV[0] = vtx0
+	if err := te.issue(vtx0); err != nil {
+		t.Fatal(err)
+	}
+
+	if blocked := te.BlockedContainers(); len(blocked) != 0 {
+		t.Fatalf("shouldn't have any blocked containers, got %d", len(blocked))
+	}
+}
+
+func TestEngineReissueOrphan(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxOrphans = 1
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, ids.GenerateTestID())
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{gVtx},
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{
+			&avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+				IDV:     vtx0.IDV,
+				StatusV: choices.Unknown,
+			}},
+		},
+		HeightV: 2,
+		TxsV:    []snowstorm.Tx{tx0},
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := te.issue(vtx1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fetching vtx0 failed, so vtx1 is abandoned
+	te.vtxBlocked.Abandon(vtx0.ID())
+	if len(te.pending) != 0 {
+		t.Fatal("vtx1 should have been abandoned")
+	}
+	if te.orphans.Len() != 1 {
+		t.Fatal("vtx1 should have been orphaned")
+	}
+
+	vtx1.ParentsV[0] = vtx0
+	if err := te.issue(vtx0); err != nil {
+		t.Fatal(err)
+	}
+
+	if prefs := te.Consensus.Preferences(); prefs.Len() != 1 || !prefs.Contains(vtx1.ID()) {
+		t.Fatalf("Should have re-issued vtx1")
+	}
+	if te.orphans.Len() != 0 {
+		t.Fatal("vtx1 should no longer be orphaned")
+	}
+}
+
 func TestEngineAbandonResponse(t *testing.T) {
 	config := DefaultConfig()
 
@@ -4068,6 +4238,107 @@ func TestEngineBubbleVotes(t *testing.T) {
 	assert.Equal(t, choices.Processing, tx1.Status(), "wrong tx status")
 }
 
+func TestEngineVertexByteLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.Params.BatchSize = 2
+	config.VertexLimits.MaxBytes = vertex.HeaderSize(config.Params.Parents) + vertex.TxSize(10)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	newTx := func(size int) *snowstorm.TestTx {
+		return &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{ids.GenerateTestID()},
+			BytesV:    make([]byte, size),
+		}
+	}
+	tx0 := newTx(10)
+	tx1 := newTx(10)
+	tx2 := newTx(11) // doesn't fit in a vertex
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id == gVtx.ID() {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.CantBootstrapping = true
+	vm.CantBootstrapped = true
+
+	built := [][]snowstorm.Tx{}
+	manager.BuildVtxF = func(_ uint32, _ []ids.ID, txs []snowstorm.Tx, _ []ids.ID) (avalanche.Vertex, error) {
+		built = append(built, txs)
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{1},
+		}, nil
+	}
+	sender.CantPushQuery = false
+
+	remaining, err := te.batch([]snowstorm.Tx{tx0, tx1, tx2}, false /*=force*/, false /*=empty*/, false /*=limit*/)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(remaining) != 0:
+		t.Fatalf("shouldn't have left %d txs to issue", len(remaining))
+	case len(built) != 2:
+		t.Fatalf("should have built 2 vertices, built %d", len(built))
+	case len(built[0]) != 1 || len(built[1]) != 1:
+		t.Fatal("each vertex should have had one tx")
+	case te.Consensus.TxIssued(tx2):
+		t.Fatal("shouldn't have issued a tx that doesn't fit in a vertex")
+	}
+}
+
 func TestEngineIssue(t *testing.T) {
 	config := DefaultConfig()
 	config.Params.BatchSize = 1
@@ -4213,3 +4484,706 @@ func TestEngineIssue(t *testing.T) {
 		t.Fatalf("Should have issued txs differently")
 	}
 }
+
+func TestEngineLightVerification(t *testing.T) {
+	config := DefaultConfig()
+	config.LightVerification = true
+	config.Params.K = 2
+	config.Params.Alpha = 2
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	nonVdr := ids.GenerateTestShortID()
+	errs := wrappers.Errs{}
+	errs.Add(
+		vals.AddWeight(vdr0, 1),
+		vals.AddWeight(vdr1, 1),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	// Any query sent by the engine fails the test
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	mVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	vts := []avalanche.Vertex{gVtx, mVtx}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, ids.GenerateTestID())
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+		BytesV:   []byte{0, 1, 2, 3},
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{vts[0].ID(), vts[1].ID()} }
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		switch id {
+		case gVtx.ID():
+			return gVtx, nil
+		case mVtx.ID():
+			return mVtx, nil
+		case vtx0.ID():
+			return vtx0, nil
+		}
+		return nil, errUnknownVertex
+	}
+	manager.ParseVtxF = func(b []byte) (avalanche.Vertex, error) {
+		if !bytes.Equal(b, vtx0.Bytes()) {
+			t.Fatalf("Wrong bytes")
+		}
+		return vtx0, nil
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither a vertex gossiped by a non-validator nor a vertex sent in
+	// response to a request is a vote
+	if err := te.Put(nonVdr, constants.GossipMsgRequestID, vtx0.ID(), vtx0.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Put(vdr0, 0, vtx0.ID(), vtx0.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Put(vdr0, constants.GossipMsgRequestID, vtx0.ID(), vtx0.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Consensus.VertexIssued(vtx0) {
+		t.Fatalf("Should have issued the vertex")
+	}
+	if status := vtx0.Status(); status != choices.Processing {
+		t.Fatalf("Vertex should be processing but is %s", status)
+	}
+	if te.polls.Len() != 0 {
+		t.Fatalf("Shouldn't have recorded a poll with only one observed validator")
+	}
+
+	if err := te.Put(vdr1, constants.GossipMsgRequestID, vtx0.ID(), vtx0.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if status := vtx0.Status(); status != choices.Accepted {
+		t.Fatalf("Vertex should be accepted but is %s", status)
+	}
+	if status := tx0.Status(); status != choices.Accepted {
+		t.Fatalf("Tx should be accepted but is %s", status)
+	}
+	if te.polls.Len() != 0 {
+		t.Fatalf("Observed poll should have finished")
+	}
+
+	// Once this node is a validator, it polls validators
+	if err := vals.AddWeight(config.Ctx.NodeID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if te.light() {
+		t.Fatalf("Validators shouldn't use light verification")
+	}
+}
+
+func TestEngineStallRecovery(t *testing.T) {
+	config := DefaultConfig()
+	config.StallThreshold = time.Minute
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(false)
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx.InputIDsV = append(tx.InputIDsV, ids.GenerateTestID())
+
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{gVtx},
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx},
+		BytesV:   []byte{1},
+	}
+
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch vtxID {
+		case gVtx.ID():
+			return gVtx, nil
+		case vtx.ID():
+			return vtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := te.issue(vtx); err != nil {
+		t.Fatal(err)
+	}
+	start := te.lastProgress
+	if start.IsZero() {
+		t.Fatal("issuing into idle consensus should have recorded progress")
+	}
+
+	repolled := false
+	sender.PullQueryF = func(vdrs ids.ShortSet, _ uint32, vtxID ids.ID) {
+		if !vdrs.Contains(vdr) || vtxID != vtx.ID() {
+			t.Fatalf("repolled wrong validators %s for %s", vdrs, vtxID)
+		}
+		repolled = true
+	}
+	gossiped := false
+	sender.GossipF = func(vtxID ids.ID, vtxBytes []byte) {
+		if vtxID != vtx.ID() || !bytes.Equal(vtxBytes, vtx.Bytes()) {
+			t.Fatalf("gossiped wrong vertex %s", vtxID)
+		}
+		gossiped = true
+	}
+
+	te.checkStalled(start.Add(time.Minute))
+	if te.stalled || repolled || gossiped {
+		t.Fatal("shouldn't have stalled before the threshold")
+	}
+
+	te.checkStalled(start.Add(2 * time.Minute))
+	if !te.stalled {
+		t.Fatal("should have stalled")
+	}
+	if !repolled {
+		t.Fatal("should have repolled the preferred vertex")
+	}
+	if !gossiped {
+		t.Fatal("should have gossiped the preferred vertex")
+	}
+
+	te.progressed(start.Add(3 * time.Minute))
+	if te.stalled {
+		t.Fatal("should have recovered")
+	}
+}
+
+func TestEngineFrontierSync(t *testing.T) {
+	config := DefaultConfig()
+	config.FrontierSyncFrequency = time.Minute
+	config.Ctx.Bootstrapped()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(false)
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	missingID := ids.GenerateTestID()
+
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		if vtxID == gVtx.ID() {
+			return gVtx, nil
+		}
+		return nil, errUnknownVertex
+	}
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	reqID := new(uint32)
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, requestID uint32) {
+		if vdrs.Len() != 1 || !vdrs.Contains(vdr) {
+			t.Fatalf("requested the accepted frontier of the wrong validators %s", vdrs)
+		}
+		*reqID = requestID
+	}
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if *reqID == 0 {
+		t.Fatal("should have requested the accepted frontier of a validator")
+	}
+
+	// The frontier was requested less than [FrontierSyncFrequency] ago
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) {
+		t.Fatal("shouldn't have requested the accepted frontier again")
+	}
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unexpected response is dropped
+	if err := te.AcceptedFrontier(vdr, *reqID+1, []ids.ID{missingID}); err != nil {
+		t.Fatal(err)
+	}
+
+	requested := ids.Set{}
+	sender.GetF = func(vdrID ids.ShortID, _ uint32, vtxID ids.ID) {
+		if vdrID != vdr {
+			t.Fatalf("requested %s from the wrong validator %s", vtxID, vdrID)
+		}
+		requested.Add(vtxID)
+	}
+	if err := te.AcceptedFrontier(vdr, *reqID, []ids.ID{gVtx.ID(), missingID}); err != nil {
+		t.Fatal(err)
+	}
+	if requested.Len() != 1 || !requested.Contains(missingID) {
+		t.Fatalf("should have only requested the missing vertex, requested %s", requested)
+	}
+
+	// The response was already handled
+	if err := te.AcceptedFrontier(vdr, *reqID, []ids.ID{ids.GenerateTestID()}); err != nil {
+		t.Fatal(err)
+	}
+	if requested.Len() != 1 {
+		t.Fatalf("shouldn't have handled the response twice, requested %s", requested)
+	}
+}
+
+func TestEngineEvidence(t *testing.T) {
+	config := DefaultConfig()
+	config.Ctx.Bootstrapped()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+	sender.Default(false)
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	inputID := ids.GenerateTestID()
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, inputID)
+	tx1 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx1.InputIDsV = append(tx1.InputIDsV, inputID)
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{gVtx},
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{gVtx},
+		HeightV:  5,
+		TxsV:     []snowstorm.Tx{tx1},
+	}
+
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch vtxID {
+		case vtx0.ID():
+			return vtx0, nil
+		case vtx1.ID():
+			return vtx1, nil
+		}
+		return nil, errUnknownVertex
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	vdr := ids.GenerateTestShortID()
+
+	// Votes that don't conflict aren't evidence
+	te.checkChits(vdr, 1, []ids.ID{vtx0.ID(), vtx0.ID()})
+	te.checkAncestry(vdr, vtx0, vtx0.ParentsV)
+	if len(te.Evidence()) != 0 {
+		t.Fatalf("shouldn't have recorded evidence, recorded %v", te.Evidence())
+	}
+
+	te.checkChits(vdr, 2, []ids.ID{vtx0.ID(), vtx1.ID()})
+	te.checkAncestry(vdr, vtx1, vtx1.ParentsV)
+	te.sendRequest(vdr, vtx0.ID())
+	te.checkPut(vdr, te.RequestID, vtx1.ID())
+
+	evidence := te.Evidence()
+	switch {
+	case len(evidence) != 3:
+		t.Fatalf("should have recorded 3 pieces of evidence, recorded %v", evidence)
+	case evidence[0].Kind != common.ConflictingChits || evidence[0].RequestID != 2:
+		t.Fatalf("should have recorded conflicting chits, recorded %v", evidence[0])
+	case evidence[1].Kind != common.InvalidAncestry || evidence[1].ContainerIDs[0] != vtx1.ID():
+		t.Fatalf("should have recorded invalid ancestry, recorded %v", evidence[1])
+	case evidence[2].Kind != common.UnrequestedContainer || evidence[2].RequestID != te.RequestID:
+		t.Fatalf("should have recorded an unrequested container, recorded %v", evidence[2])
+	}
+	for _, e := range evidence {
+		if e.NodeID != vdr {
+			t.Fatalf("should have recorded evidence against %s, recorded against %s", vdr, e.NodeID)
+		}
+	}
+}
+
+type testBenchlist map[ids.ShortID]bool
+
+func (b testBenchlist) IsBenched(vdrID ids.ShortID, _ ids.ID) bool { return b[vdrID] }
+
+func TestEngineSkipBenched(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	benchedVdr := ids.GenerateTestShortID()
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(benchedVdr, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	benchlist := testBenchlist{benchedVdr: true}
+	config.Benchlist = benchlist
+	config.SkipBenched = true
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// [benchedVdr] is almost always sampled first, but is drawn again. It's
+	// only queried if it's drawn every time.
+	for i := 0; i < 10; i++ {
+		degraded := testutil.ToFloat64(te.numDegradedSamples)
+		vdrBag, err := te.samplePoll(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vdrBag.Len() != 1 {
+			t.Fatalf("should have sampled one validator, sampled %s", vdrBag.List())
+		}
+		if vdrBag.Count(benchedVdr) == 1 && testutil.ToFloat64(te.numDegradedSamples) != degraded+1 {
+			t.Fatalf("querying a benched validator should have been reported")
+		}
+	}
+	if testutil.ToFloat64(te.numBenchedRedraws) == 0 {
+		t.Fatalf("should have drawn again after sampling a benched validator")
+	}
+
+	// Benched validators are queried if they can't be replaced
+	benchlist[vdr] = true
+	degraded := testutil.ToFloat64(te.numDegradedSamples)
+	vdrBag, err := te.samplePoll(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vdrBag.Len() != 1 {
+		t.Fatalf("should have sampled one validator, sampled %s", vdrBag.List())
+	}
+	if testutil.ToFloat64(te.numDegradedSamples) != degraded+1 {
+		t.Fatalf("querying a benched validator should have been reported")
+	}
+
+	// Without [SkipBenched], benched validators aren't drawn again
+	te.skipBenched = false
+	delete(benchlist, vdr)
+	redraws := testutil.ToFloat64(te.numBenchedRedraws)
+	for i := 0; i < 10; i++ {
+		vdrBag, err := te.samplePoll(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vdrBag.Len() != 1 {
+			t.Fatalf("should have sampled one validator, sampled %s", vdrBag.List())
+		}
+	}
+	if testutil.ToFloat64(te.numBenchedRedraws) != redraws {
+		t.Fatalf("shouldn't have drawn again")
+	}
+}
+
+func TestEngineObserveEdge(t *testing.T) {
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{IDV: ids.GenerateTestID()},
+		HeightV:       1,
+	}
+	vtx1 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{IDV: ids.GenerateTestID()},
+		HeightV:       2,
+	}
+	manager := vertex.NewTestManager(t)
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch vtxID {
+		case vtx0.ID():
+			return vtx0, nil
+		case vtx1.ID():
+			return vtx1, nil
+		}
+		return nil, errUnknownVertex
+	}
+
+	te := &Transitive{}
+	te.Manager = manager
+	te.stale = common.NewStaleDetector(validators.NewSet(), 1, time.Minute)
+	start := time.Unix(1000, 0)
+
+	te.observeEdge([]ids.ID{vtx0.ID()}, start)
+	if height, lastAcceptedTime := te.stale.LastAccepted(); height != 1 || !lastAcceptedTime.Equal(start) {
+		t.Fatalf("should have recorded the first observed frontier")
+	}
+
+	te.observeEdge([]ids.ID{vtx0.ID()}, start.Add(time.Minute))
+	if _, lastAcceptedTime := te.stale.LastAccepted(); !lastAcceptedTime.Equal(start) {
+		t.Fatalf("shouldn't have recorded an acceptance when the frontier didn't change")
+	}
+
+	te.observeEdge([]ids.ID{vtx1.ID(), vtx0.ID()}, start.Add(2*time.Minute))
+	if height, lastAcceptedTime := te.stale.LastAccepted(); height != 2 || !lastAcceptedTime.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("should have recorded an acceptance of the highest vertex when the frontier changed")
+	}
+}
+
+func TestEngineRebootstrapsWhenStale(t *testing.T) {
+	config := DefaultConfig()
+	config.StaleThreshold = time.Minute
+	config.Alpha = 2
+
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	peer := ids.GenerateTestShortID()
+
+	// Beacons are added after the initial bootstrapping so that it finishes
+	// immediately
+	beacons := validators.NewSet()
+	config.Beacons = beacons
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGossip = false
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	// Records the calls to the VM's Bootstrapping and Bootstrapped
+	var vmCalls []string
+	vm.BootstrappingF = func() error {
+		vmCalls = append(vmCalls, "bootstrapping")
+		return nil
+	}
+	vm.BootstrappedF = func() error {
+		vmCalls = append(vmCalls, "bootstrapped")
+		return nil
+	}
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: []avalanche.Vertex{gVtx},
+		HeightV:  1,
+		BytesV:   []byte{1},
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch vtxID {
+		case gVtx.ID():
+			return gVtx, nil
+		case vtx.ID():
+			return vtx, nil
+		}
+		return nil, errUnknownVertex
+	}
+	manager.ParseVtxF = func(b []byte) (avalanche.Vertex, error) {
+		if bytes.Equal(b, vtx.Bytes()) {
+			return vtx, nil
+		}
+		return nil, errUnknownVertex
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Should have finished bootstrapping")
+	}
+
+	if err := beacons.AddWeight(vdr0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := beacons.AddWeight(vdr1, 1); err != nil {
+		t.Fatal(err)
+	}
+	te.SampleK = 2
+
+	// No vertex has been accepted for an hour, but no peer reported a higher
+	// vertex, so the chain isn't stale
+	te.stale.Accepted(0, time.Now().Add(-time.Hour))
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Shouldn't have restarted bootstrapping")
+	}
+
+	// Peers that aren't beacons can't make the chain stale, and neither can a
+	// single beacon holding less than alpha weight
+	for _, vdr := range []ids.ShortID{peer, vdr0} {
+		if err := te.Put(vdr, constants.GossipMsgRequestID, vtx.ID(), vtx.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err := te.Gossip(); err != nil {
+			t.Fatal(err)
+		}
+		if !te.Ctx.IsBootstrapped() {
+			t.Fatalf("Shouldn't have restarted bootstrapping")
+		}
+	}
+
+	if err := te.Put(vdr1, constants.GossipMsgRequestID, vtx.ID(), vtx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	reqID := new(uint32)
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, requestID uint32) {
+		if !vdrs.Contains(vdr0) || !vdrs.Contains(vdr1) {
+			t.Fatalf("Should have requested the accepted frontier from the beacons")
+		}
+		*reqID = requestID
+	}
+	sender.GetAcceptedF = func(vdrs ids.ShortSet, requestID uint32, containerIDs []ids.ID) {
+		*reqID = requestID
+	}
+
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if te.Ctx.IsBootstrapped() {
+		t.Fatalf("Should have restarted bootstrapping")
+	}
+	if vmCalls[len(vmCalls)-1] != "bootstrapping" {
+		t.Fatalf("Should have moved the VM back into bootstrapping, VM calls: %v", vmCalls)
+	}
+	if testutil.ToFloat64(te.numRebootstraps) != 1 {
+		t.Fatalf("Should have reported restarting bootstrapping")
+	}
+
+	for _, beacon := range []ids.ShortID{vdr0, vdr1} {
+		if err := te.AcceptedFrontier(beacon, *reqID, []ids.ID{gVtx.ID()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, beacon := range []ids.ShortID{vdr0, vdr1} {
+		if err := te.Accepted(beacon, *reqID, []ids.ID{gVtx.ID()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Should have finished bootstrapping again")
+	}
+	if vmCalls[len(vmCalls)-1] != "bootstrapped" {
+		t.Fatalf("Should have told the VM that bootstrapping finished again, VM calls: %v", vmCalls)
+	}
+	if te.Consensus.NumProcessing() != 0 {
+		t.Fatalf("Consensus should have been reset")
+	}
+}
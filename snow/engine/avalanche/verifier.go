@@ -0,0 +1,55 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+// verifyTxs verifies [txs] and returns the valid txs, in the order they were
+// given. If [txVerifiers] is greater than 1, the txs are verified concurrently
+// by up to that many goroutines, and verifyTxs returns once every verification
+// has returned.
+func (t *Transitive) verifyTxs(txs []snowstorm.Tx) []snowstorm.Tx {
+	errs := make([]error, len(txs))
+	numVerifiers := t.txVerifiers
+	if numVerifiers > len(txs) {
+		numVerifiers = len(txs)
+	}
+	if numVerifiers <= 1 {
+		for i, tx := range txs {
+			errs[i] = tx.Verify()
+		}
+	} else {
+		toVerify := make(chan int, len(txs))
+		for i := range txs {
+			toVerify <- i
+		}
+		close(toVerify)
+
+		wg := sync.WaitGroup{}
+		wg.Add(numVerifiers)
+		for j := 0; j < numVerifiers; j++ {
+			go func() {
+				defer wg.Done()
+				for i := range toVerify {
+					errs[i] = txs[i].Verify()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	validTxs := make([]snowstorm.Tx, 0, len(txs))
+	for i, tx := range txs {
+		if err := errs[i]; err != nil {
+			t.Ctx.Log.Debug("Transaction %s failed verification due to %s", tx.ID(), err)
+		} else {
+			validTxs = append(validTxs, tx)
+		}
+	}
+	return validTxs
+}
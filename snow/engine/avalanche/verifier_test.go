@@ -0,0 +1,50 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+func TestVerifyTxs(t *testing.T) {
+	errInvalid := errors.New("invalid tx")
+
+	txs := make([]snowstorm.Tx, 10)
+	for i := range txs {
+		tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		}}
+		if i%3 == 0 {
+			tx.VerifyV = errInvalid
+		}
+		txs[i] = tx
+	}
+
+	for _, numVerifiers := range []int{0, 1, 4, 20} {
+		te := &Transitive{txVerifiers: numVerifiers}
+		te.Ctx = snow.DefaultContextTest()
+
+		validTxs := te.verifyTxs(txs)
+		if len(validTxs) != 6 {
+			t.Fatalf("with %d verifiers, should have returned 6 valid txs, returned %d", numVerifiers, len(validTxs))
+		}
+		j := 0
+		for i, tx := range txs {
+			if i%3 == 0 {
+				continue
+			}
+			if validTxs[j].ID() != tx.ID() {
+				t.Fatalf("with %d verifiers, valid txs should keep their order", numVerifiers)
+			}
+			j++
+		}
+	}
+}
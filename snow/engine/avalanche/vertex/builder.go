@@ -29,6 +29,20 @@ func Build(
 	parentIDs []ids.ID,
 	txs [][]byte,
 	restrictions []ids.ID,
+) (StatelessVertex, error) {
+	return BuildWithLimits(Limits{}, chainID, height, epoch, parentIDs, txs, restrictions)
+}
+
+// BuildWithLimits builds a new stateless vertex from the contents of a vertex.
+// Returns an error if the vertex exceeds [limits].
+func BuildWithLimits(
+	limits Limits,
+	chainID ids.ID,
+	height uint64,
+	epoch uint32,
+	parentIDs []ids.ID,
+	txs [][]byte,
+	restrictions []ids.ID,
 ) (StatelessVertex, error) {
 	ids.SortIDs(parentIDs)
 	SortHashOf(txs)
@@ -53,5 +67,8 @@ func Build(
 		id:                   hashing.ComputeHash256Array(vtxBytes),
 		bytes:                vtxBytes,
 	}
-	return vtx, err
+	if err != nil {
+		return vtx, err
+	}
+	return vtx, limits.verify(vtx)
 }
@@ -1,11 +1,13 @@
 package vertex
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 func TestBuildInvalid(t *testing.T) {
@@ -49,3 +51,34 @@ func TestBuildValid(t *testing.T) {
 	assert.Equal(t, txs, vtx.Txs())
 	assert.Equal(t, restrictions, vtx.Restrictions())
 }
+
+func TestBuildWithLimits(t *testing.T) {
+	chainID := ids.ID{1}
+	height := uint64(2)
+	epoch := uint32(0)
+	parentIDs := []ids.ID{{4}, {5}}
+	txs := [][]byte{{7}, {6}}
+
+	_, err := BuildWithLimits(Limits{MaxTxs: 1}, chainID, height, epoch, parentIDs, txs, nil)
+	assert.True(t, errors.Is(err, errExceedsTxLimit), "build should have errored because there are too many txs")
+
+	vtxSize := HeaderSize(len(parentIDs)) + TxSize(1) + TxSize(1)
+	_, err = BuildWithLimits(Limits{MaxBytes: vtxSize - wrappers.IntLen - 1}, chainID, height, epoch, parentIDs, txs, nil)
+	assert.True(t, errors.Is(err, errExceedsSizeLimit), "build should have errored because the vertex is too large")
+
+	vtx, err := BuildWithLimits(Limits{MaxTxs: 2, MaxBytes: vtxSize}, chainID, height, epoch, parentIDs, txs, nil)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(vtx.Bytes()), vtxSize, "size estimate should bound the vertex size")
+}
+
+func TestLimitsValid(t *testing.T) {
+	assert.NoError(t, Limits{}.Valid())
+	assert.NoError(t, Limits{MaxTxs: MaxTxsPerVtx, MaxRestrictions: 1, MaxBytes: maxSize}.Valid())
+	assert.Error(t, Limits{MaxTxs: -1}.Valid())
+	assert.Error(t, Limits{MaxTxs: MaxTxsPerVtx + 1}.Valid())
+	assert.Error(t, Limits{MaxRestrictions: MaxTxsPerVtx + 1}.Valid())
+	assert.Error(t, Limits{MaxBytes: maxSize + 1}.Valid())
+
+	limits := Limits{MaxTxs: 1}.WithDefaults()
+	assert.Equal(t, Limits{MaxTxs: 1, MaxRestrictions: MaxTxsPerVtx, MaxBytes: maxSize}, limits)
+}
@@ -0,0 +1,101 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vertex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// vtxHeaderLen is the length of a serialized vertex that has no parents, txs,
+// or restrictions
+const vtxHeaderLen = wrappers.ShortLen + // version
+	hashing.HashLen + // chainID
+	wrappers.LongLen + // height
+	wrappers.IntLen + // epoch
+	3*wrappers.IntLen // number of parents, txs, and restrictions
+
+var (
+	errNegativeLimit           = errors.New("vertex limits can't be negative")
+	errTxLimitTooHigh          = fmt.Errorf("vertex transaction limit can't exceed %d", MaxTxsPerVtx)
+	errRestrictionLimitTooHigh = fmt.Errorf("vertex restriction limit can't exceed %d", MaxTxsPerVtx)
+	errSizeLimitTooHigh        = fmt.Errorf("vertex size limit can't exceed %d", maxSize)
+	errExceedsTxLimit          = errors.New("vertex contains more transactions than allowed")
+	errExceedsRestrictionLimit = errors.New("vertex contains more restrictions than allowed")
+	errExceedsSizeLimit        = errors.New("vertex is larger than allowed")
+)
+
+// Limits restricts the vertices that are built, so that they fit the gossip
+// and verification budgets of a chain. The limits can only be tighter than the
+// limits every vertex must satisfy. A zero limit is the limit every vertex must
+// satisfy.
+type Limits struct {
+	// Max number of transactions in a vertex
+	MaxTxs int
+	// Max number of restrictions in a vertex
+	MaxRestrictions int
+	// Max length of a serialized vertex
+	MaxBytes int
+}
+
+// Valid returns nil if the limits can be satisfied by valid vertices
+func (l Limits) Valid() error {
+	switch {
+	case l.MaxTxs < 0 || l.MaxRestrictions < 0 || l.MaxBytes < 0:
+		return errNegativeLimit
+	case l.MaxTxs > MaxTxsPerVtx:
+		return errTxLimitTooHigh
+	case l.MaxRestrictions > MaxTxsPerVtx:
+		return errRestrictionLimitTooHigh
+	case l.MaxBytes > maxSize:
+		return errSizeLimitTooHigh
+	default:
+		return nil
+	}
+}
+
+// WithDefaults returns the limits with each zero limit replaced by the limit
+// every vertex must satisfy
+func (l Limits) WithDefaults() Limits {
+	if l.MaxTxs == 0 {
+		l.MaxTxs = MaxTxsPerVtx
+	}
+	if l.MaxRestrictions == 0 {
+		l.MaxRestrictions = MaxTxsPerVtx
+	}
+	if l.MaxBytes == 0 {
+		l.MaxBytes = maxSize
+	}
+	return l
+}
+
+// verify that [vtx] is within the limits
+func (l Limits) verify(vtx StatelessVertex) error {
+	l = l.WithDefaults()
+	switch {
+	case len(vtx.Txs()) > l.MaxTxs:
+		return fmt.Errorf("%w: %d > %d", errExceedsTxLimit, len(vtx.Txs()), l.MaxTxs)
+	case len(vtx.Restrictions()) > l.MaxRestrictions:
+		return fmt.Errorf("%w: %d > %d", errExceedsRestrictionLimit, len(vtx.Restrictions()), l.MaxRestrictions)
+	case len(vtx.Bytes()) > l.MaxBytes:
+		return fmt.Errorf("%w: %d > %d", errExceedsSizeLimit, len(vtx.Bytes()), l.MaxBytes)
+	default:
+		return nil
+	}
+}
+
+// HeaderSize returns the length of a serialized vertex with [numParents]
+// parents, no txs and no restrictions
+func HeaderSize(numParents int) int {
+	return vtxHeaderLen + numParents*hashing.HashLen
+}
+
+// TxSize returns the number of bytes that adding a tx of length [txLen] adds
+// to a serialized vertex
+func TxSize(txLen int) int {
+	return wrappers.IntLen + txLen
+}
@@ -14,7 +14,9 @@ type Parser interface {
 	ParseVtx(vertex []byte) (avalanche.Vertex, error)
 }
 
-// Parse the provided vertex bytes into a stateless vertex
+// Parse the provided vertex bytes into a stateless vertex. The transactions of
+// the returned vertex reference [vertex], so [vertex] must not be modified
+// afterwards.
 func Parse(vertex []byte) (StatelessVertex, error) {
 	vtx := innerStatelessVertex{}
 	version, err := c.Unmarshal(vertex, &vtx)
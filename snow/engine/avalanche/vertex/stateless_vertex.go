@@ -15,8 +15,8 @@ const (
 	// maxNumParents is the max number of parents a vertex may have
 	maxNumParents = 128
 
-	// maxTxsPerVtx is the max number of transactions a vertex may have
-	maxTxsPerVtx = 128
+	// MaxTxsPerVtx is the max number of transactions a vertex may have
+	MaxTxsPerVtx = 128
 )
 
 var (
@@ -25,8 +25,8 @@ var (
 	errFutureField         = errors.New("field specified in a previous version")
 	errTooManyparentIDs    = fmt.Errorf("vertex contains more than %d parentIDs", maxNumParents)
 	errNoOperations        = errors.New("vertex contains no operations")
-	errTooManyTxs          = fmt.Errorf("vertex contains more than %d transactions", maxTxsPerVtx)
-	errTooManyRestrictions = fmt.Errorf("vertex contains more than %d restrictions", maxTxsPerVtx)
+	errTooManyTxs          = fmt.Errorf("vertex contains more than %d transactions", MaxTxsPerVtx)
+	errTooManyRestrictions = fmt.Errorf("vertex contains more than %d restrictions", MaxTxsPerVtx)
 	errInvalidParents      = errors.New("vertex contains non-sorted or duplicated parentIDs")
 	errInvalidRestrictions = errors.New("vertex contains non-sorted or duplicated restrictions")
 	errInvalidTxs          = errors.New("vertex contains non-sorted or duplicated transactions")
@@ -92,9 +92,9 @@ func (v innerStatelessVertex) Verify() error {
 		return errTooManyparentIDs
 	case len(v.Txs)+len(v.Restrictions) == 0:
 		return errNoOperations
-	case len(v.Txs) > maxTxsPerVtx:
+	case len(v.Txs) > MaxTxsPerVtx:
 		return errTooManyTxs
-	case len(v.Restrictions) > maxTxsPerVtx:
+	case len(v.Restrictions) > MaxTxsPerVtx:
 		return errTooManyRestrictions
 	case !ids.IsSortedAndUniqueIDs(v.ParentIDs):
 		return errInvalidParents
@@ -14,11 +14,11 @@ func TestVertexVerify(t *testing.T) {
 	for i := range tooManyParents {
 		tooManyParents[i][0] = byte(i)
 	}
-	tooManyTxs := make([][]byte, maxTxsPerVtx+1)
+	tooManyTxs := make([][]byte, MaxTxsPerVtx+1)
 	for i := range tooManyTxs {
 		tooManyTxs[i] = []byte{byte(i)}
 	}
-	tooManyRestrictions := make([]ids.ID, maxTxsPerVtx+1)
+	tooManyRestrictions := make([]ids.ID, MaxTxsPerVtx+1)
 	for i := range tooManyRestrictions {
 		tooManyRestrictions[i][0] = byte(i)
 	}
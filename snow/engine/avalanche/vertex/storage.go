@@ -17,3 +17,12 @@ type Storage interface {
 	// Edge returns a list of accepted vertex IDs with no accepted children.
 	Edge() (vtxIDs []ids.ID)
 }
+
+// EdgeSyncer is implemented by storage whose accepted frontier can be replaced
+// once the VM's state was synced to it
+type EdgeSyncer interface {
+	// SyncEdge marks the vertices [vtxIDs] as accepted and makes them the
+	// accepted frontier, without accepting their ancestors. The vertices must
+	// have been parsed.
+	SyncEdge(vtxIDs []ids.ID) error
+}
@@ -14,20 +14,24 @@ import (
 var (
 	errGet  = errors.New("unexpectedly called Get")
 	errEdge = errors.New("unexpectedly called Edge")
+	errSync = errors.New("unexpectedly called SyncEdge")
 
-	_ Storage = &TestStorage{}
+	_ Storage    = &TestStorage{}
+	_ EdgeSyncer = &TestStorage{}
 )
 
 type TestStorage struct {
-	T                    *testing.T
-	CantGetVtx, CantEdge bool
-	GetVtxF              func(ids.ID) (avalanche.Vertex, error)
-	EdgeF                func() []ids.ID
+	T                                  *testing.T
+	CantGetVtx, CantEdge, CantSyncEdge bool
+	GetVtxF                            func(ids.ID) (avalanche.Vertex, error)
+	EdgeF                              func() []ids.ID
+	SyncEdgeF                          func([]ids.ID) error
 }
 
 func (s *TestStorage) Default(cant bool) {
 	s.CantGetVtx = cant
 	s.CantEdge = cant
+	s.CantSyncEdge = cant
 }
 
 func (s *TestStorage) GetVtx(id ids.ID) (avalanche.Vertex, error) {
@@ -49,3 +53,13 @@ func (s *TestStorage) Edge() []ids.ID {
 	}
 	return nil
 }
+
+func (s *TestStorage) SyncEdge(vtxIDs []ids.ID) error {
+	if s.SyncEdgeF != nil {
+		return s.SyncEdgeF(vtxIDs)
+	}
+	if s.CantSyncEdge && s.T != nil {
+		s.T.Fatal(errSync)
+	}
+	return errSync
+}
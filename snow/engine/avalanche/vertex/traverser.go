@@ -0,0 +1,130 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vertex
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+var (
+	errInvalidToken = errors.New("invalid traversal token")
+	errInvalidLimit = errors.New("traversal page limit must be positive")
+)
+
+// VertexInfo describes an accepted vertex
+type VertexInfo struct {
+	ID        ids.ID
+	Height    uint64
+	Epoch     uint32
+	ParentIDs []ids.ID
+	TxIDs     []ids.ID
+}
+
+// Traverser walks the accepted DAG backwards from the accepted frontier, so
+// that indexers can read the DAG without parsing vertices themselves.
+// Vertices are returned in order of decreasing height, and each vertex is
+// returned once.
+type Traverser struct {
+	storage Storage
+}
+
+// NewTraverser returns a Traverser that reads vertices from [storage]
+func NewTraverser(storage Storage) *Traverser {
+	return &Traverser{storage: storage}
+}
+
+// Page returns up to [limit] accepted vertices. If [token] is nil, the walk
+// starts at the accepted frontier. Otherwise, it continues from where the page
+// that returned [token] stopped. Returns the token of the next page, which is
+// nil once every vertex has been returned.
+//
+// The token only remains valid while the vertices it refers to are stored.
+func (t *Traverser) Page(token []byte, limit int) ([]VertexInfo, []byte, error) {
+	if limit <= 0 {
+		return nil, nil, errInvalidLimit
+	}
+
+	var startIDs []ids.ID
+	if token == nil {
+		startIDs = t.storage.Edge()
+	} else {
+		var err error
+		startIDs, err = parseToken(token)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	toVisit := NewHeap()
+	for _, vtxID := range startIDs {
+		vtx, err := t.storage.GetVtx(vtxID)
+		if err != nil {
+			return nil, nil, err
+		}
+		toVisit.Push(vtx)
+	}
+
+	infos := make([]VertexInfo, 0, limit)
+	for len(infos) < limit && toVisit.Len() > 0 {
+		vtx := toVisit.Pop()
+		height, err := vtx.Height()
+		if err != nil {
+			return nil, nil, err
+		}
+		epoch, err := vtx.Epoch()
+		if err != nil {
+			return nil, nil, err
+		}
+		parents, err := vtx.Parents()
+		if err != nil {
+			return nil, nil, err
+		}
+		txs, err := vtx.Txs()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		info := VertexInfo{
+			ID:        vtx.ID(),
+			Height:    height,
+			Epoch:     epoch,
+			ParentIDs: make([]ids.ID, len(parents)),
+			TxIDs:     make([]ids.ID, len(txs)),
+		}
+		for i, parent := range parents {
+			info.ParentIDs[i] = parent.ID()
+			// Every child of a vertex is taller than it, so a vertex is only
+			// pushed again while it's still in the heap
+			toVisit.Push(parent)
+		}
+		for i, tx := range txs {
+			info.TxIDs[i] = tx.ID()
+		}
+		infos = append(infos, info)
+	}
+
+	if toVisit.Len() == 0 {
+		return infos, nil, nil
+	}
+	next := make([]byte, 0, toVisit.Len()*hashing.HashLen)
+	for toVisit.Len() > 0 {
+		vtxID := toVisit.Pop().ID()
+		next = append(next, vtxID[:]...)
+	}
+	return infos, next, nil
+}
+
+func parseToken(token []byte) ([]ids.ID, error) {
+	if len(token) == 0 || len(token)%hashing.HashLen != 0 {
+		return nil, errInvalidToken
+	}
+	vtxIDs := make([]ids.ID, len(token)/hashing.HashLen)
+	for i := range vtxIDs {
+		copy(vtxIDs[i][:], token[i*hashing.HashLen:])
+	}
+	return vtxIDs, nil
+}
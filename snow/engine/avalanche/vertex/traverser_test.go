@@ -0,0 +1,91 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vertex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+func TestTraverserPage(t *testing.T) {
+	newVtx := func(height uint64, parents ...avalanche.Vertex) *avalanche.TestVertex {
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Accepted,
+			},
+			ParentsV: parents,
+			HeightV:  height,
+		}
+	}
+	// gVtx <- vtx0, vtx1 <- vtx2
+	gVtx := newVtx(0)
+	vtx0 := newVtx(1, gVtx)
+	vtx1 := newVtx(1, gVtx)
+	vtx2 := newVtx(2, vtx0, vtx1)
+
+	tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	vtx2.TxsV = []snowstorm.Tx{tx}
+
+	vts := map[ids.ID]avalanche.Vertex{}
+	for _, vtx := range []avalanche.Vertex{gVtx, vtx0, vtx1, vtx2} {
+		vts[vtx.ID()] = vtx
+	}
+	storage := &TestStorage{
+		T:     t,
+		EdgeF: func() []ids.ID { return []ids.ID{vtx2.ID()} },
+		GetVtxF: func(vtxID ids.ID) (avalanche.Vertex, error) {
+			if vtx, ok := vts[vtxID]; ok {
+				return vtx, nil
+			}
+			return nil, errGet
+		},
+	}
+	traverser := NewTraverser(storage)
+
+	infos, token, err := traverser.Page(nil, 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, token)
+	assert.Len(t, infos, 2)
+	assert.Equal(t, VertexInfo{
+		ID:        vtx2.ID(),
+		Height:    2,
+		ParentIDs: []ids.ID{vtx0.ID(), vtx1.ID()},
+		TxIDs:     []ids.ID{tx.ID()},
+	}, infos[0])
+	assert.Equal(t, uint64(1), infos[1].Height)
+
+	moreInfos, token, err := traverser.Page(token, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, token, "should have returned every vertex")
+	assert.Len(t, moreInfos, 2)
+	assert.Equal(t, uint64(1), moreInfos[0].Height)
+	assert.Equal(t, gVtx.ID(), moreInfos[1].ID)
+	assert.Empty(t, moreInfos[1].ParentIDs)
+
+	visited := ids.Set{}
+	for _, info := range append(infos, moreInfos...) {
+		visited.Add(info.ID)
+	}
+	assert.Equal(t, len(vts), visited.Len(), "should have returned each vertex once")
+}
+
+func TestTraverserInvalidPage(t *testing.T) {
+	traverser := NewTraverser(&TestStorage{T: t})
+
+	_, _, err := traverser.Page(nil, 0)
+	assert.Equal(t, errInvalidLimit, err)
+
+	_, _, err = traverser.Page([]byte{1}, 1)
+	assert.Equal(t, errInvalidToken, err)
+}
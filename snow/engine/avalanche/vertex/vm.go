@@ -23,3 +23,21 @@ type DAGVM interface {
 	// Retrieve a transaction that was submitted previously
 	GetTx(ids.ID) (snowstorm.Tx, error)
 }
+
+// StateSyncableVM is implemented by DAG VMs whose state can be synced from a
+// summary of another node's state, rather than by executing every accepted
+// transaction while bootstrapping
+type StateSyncableVM interface {
+	DAGVM
+
+	// StateSummary returns a summary of the VM's current state, which is the
+	// state as of the accepted frontier [edge]. The summary is sent to peers
+	// in a single message, so it must be smaller than the max message size.
+	StateSummary(edge []ids.ID) ([]byte, error)
+
+	// SyncState replaces the VM's state with the state described by
+	// [summary], which is the state as of the accepted frontier [edge]. Only
+	// called while bootstrapping, before any transaction was executed. If an
+	// error is returned, the VM's state must be unchanged.
+	SyncState(edge []ids.ID, summary []byte) error
+}
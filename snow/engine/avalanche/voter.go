@@ -5,7 +5,6 @@ package avalanche
 
 import (
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 )
 
@@ -34,6 +33,7 @@ func (v *voter) Update() {
 		return
 	}
 
+	v.t.checkChits(v.vdr, v.requestID, v.response)
 	results, finished := v.t.polls.Vote(v.requestID, v.vdr, v.response)
 	if !finished {
 		return
@@ -45,35 +45,9 @@ func (v *voter) Update() {
 	}
 
 	v.t.Ctx.Log.Debug("Finishing poll with:\n%s", &results)
-	if err := v.t.Consensus.RecordPoll(results); err != nil {
-		v.t.errs.Add(err)
-		return
-	}
-
-	orphans := v.t.Consensus.Orphans()
-	txs := make([]snowstorm.Tx, 0, orphans.Len())
-	for orphanID := range orphans {
-		if tx, err := v.t.VM.GetTx(orphanID); err == nil {
-			txs = append(txs, tx)
-		} else {
-			v.t.Ctx.Log.Warn("Failed to fetch %s during attempted re-issuance", orphanID)
-		}
-	}
-	if len(txs) > 0 {
-		v.t.Ctx.Log.Debug("Re-issuing %d transactions", len(txs))
-	}
-	if _, err := v.t.batch(txs, true /*=force*/, false /*empty*/, false /*=limit*/); err != nil {
-		v.t.errs.Add(err)
-		return
-	}
-
-	if v.t.Consensus.Quiesce() {
-		v.t.Ctx.Log.Debug("Avalanche engine can quiesce")
-		return
-	}
-
-	v.t.Ctx.Log.Debug("Avalanche engine can't quiesce")
-	v.t.repoll()
+	// The poll is recorded with the other polls that finish while handling the
+	// current message
+	v.t.finishedPolls = append(v.t.finishedPolls, results)
 }
 
 func (v *voter) bubbleVotes(votes ids.UniqueBag) (ids.UniqueBag, error) {
@@ -0,0 +1,30 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// BlockedInfo describes a container that was received but can't be issued to
+// consensus until its dependencies are
+type BlockedInfo struct {
+	ContainerID ids.ID
+	// Containers the container is waiting on
+	MissingContainers []ids.ID
+	// Transactions the container is waiting on
+	MissingTxs []ids.ID
+	// Time the container started waiting
+	Since time.Time
+}
+
+// BlockedReporter is implemented by engines that can report the containers
+// that are blocked on missing dependencies
+type BlockedReporter interface {
+	// BlockedContainers returns the containers that are waiting to be issued.
+	// Assumes the context lock is held.
+	BlockedContainers() []BlockedInfo
+}
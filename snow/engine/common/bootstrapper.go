@@ -329,6 +329,14 @@ func (b *Bootstrapper) RestartBootstrap(reset bool) error {
 	return b.startup()
 }
 
+// Rebootstrap starts bootstrapping again after this chain finished
+// bootstrapping
+func (b *Bootstrapper) Rebootstrap() error {
+	b.Restarted = true
+	b.bootstrapAttempts = 0
+	return b.startup()
+}
+
 func (b *Bootstrapper) startup() error {
 	b.started = true
 
@@ -41,6 +41,21 @@ type Config struct {
 	// This node will only consider the first [MultiputMaxContainersReceived]
 	// containers in a multiput it receives.
 	MultiputMaxContainersReceived int
+
+	// Max number of GetAncestors requests sent concurrently while bootstrapping
+	// a DAG. If 0, MaxOutstandingGetAncestorsRequests is used.
+	MaxOutstandingGetAncestors int
+
+	// Estimates how long validators take to respond. Only used if
+	// [RepollLatencyBias] is positive.
+	Latencies LatencyEstimator
+
+	// If positive, polls issued while another poll is outstanding favor
+	// validators that respond quickly. See RepollSampler.
+	RepollLatencyBias float64
+
+	// Reports which validators are benched. May be nil.
+	Benchlist BenchedChecker
 }
 
 // Context implements the Engine interface
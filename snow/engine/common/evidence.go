@@ -0,0 +1,82 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// EvidenceKind is the kind of misbehavior that a piece of evidence records
+type EvidenceKind string
+
+const (
+	// ConflictingChits is evidence that a peer voted for containers that
+	// conflict with each other in the same response, which an honest peer's
+	// preferences never do
+	ConflictingChits EvidenceKind = "conflictingChits"
+	// InvalidAncestry is evidence that a peer sent a container whose height
+	// doesn't follow from the heights of its parents
+	InvalidAncestry EvidenceKind = "invalidAncestry"
+	// UnrequestedContainer is evidence that a peer responded to a request for
+	// a container with a different container
+	UnrequestedContainer EvidenceKind = "unrequestedContainer"
+)
+
+// Evidence records that a peer misbehaved
+type Evidence struct {
+	NodeID ids.ShortID
+	Kind   EvidenceKind
+	// ID of the request the peer responded to. 0 if the message wasn't a
+	// response to a request.
+	RequestID uint32
+	// Containers that show the misbehavior
+	ContainerIDs []ids.ID
+	Description  string
+	Time         time.Time
+}
+
+// EvidenceReporter is implemented by engines that record evidence of peers
+// misbehaving
+type EvidenceReporter interface {
+	// Evidence returns the recorded evidence, ordered from oldest to newest.
+	// Assumes the context lock is held.
+	Evidence() []Evidence
+}
+
+// EvidenceStore holds the most recent evidence
+type EvidenceStore struct {
+	maxSize int
+	// Evidence ordered from oldest to newest
+	evidence []Evidence
+}
+
+// NewEvidenceStore returns a store that holds up to [maxSize] pieces of
+// evidence, dropping the oldest first
+func NewEvidenceStore(maxSize int) *EvidenceStore {
+	return &EvidenceStore{maxSize: maxSize}
+}
+
+// Add [evidence] to the store
+func (s *EvidenceStore) Add(evidence Evidence) {
+	if s.maxSize <= 0 {
+		return
+	}
+	if len(s.evidence) >= s.maxSize {
+		copy(s.evidence, s.evidence[1:])
+		s.evidence = s.evidence[:len(s.evidence)-1]
+	}
+	s.evidence = append(s.evidence, evidence)
+}
+
+// List returns the stored evidence, ordered from oldest to newest
+func (s *EvidenceStore) List() []Evidence {
+	evidence := make([]Evidence, len(s.evidence))
+	copy(evidence, s.evidence)
+	return evidence
+}
+
+// Len returns the number of pieces of evidence in the store
+func (s *EvidenceStore) Len() int { return len(s.evidence) }
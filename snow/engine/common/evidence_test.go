@@ -0,0 +1,33 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvidenceStore(t *testing.T) {
+	s := NewEvidenceStore(2)
+
+	s.Add(Evidence{RequestID: 1})
+	s.Add(Evidence{RequestID: 2})
+	assert.Equal(t, 2, s.Len())
+
+	// The oldest evidence is dropped to make room
+	s.Add(Evidence{RequestID: 3})
+	evidence := s.List()
+	assert.Len(t, evidence, 2)
+	assert.Equal(t, uint32(2), evidence[0].RequestID)
+	assert.Equal(t, uint32(3), evidence[1].RequestID)
+
+	// The returned evidence is a copy
+	evidence[0].RequestID = 4
+	assert.Equal(t, uint32(2), s.List()[0].RequestID)
+
+	disabled := NewEvidenceStore(0)
+	disabled.Add(Evidence{RequestID: 1})
+	assert.Equal(t, 0, disabled.Len())
+}
@@ -0,0 +1,43 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// TxFinality estimates how close a processing transaction is to being accepted
+type TxFinality struct {
+	// Number of consecutive successful polls the tx is currently in
+	Confidence int
+	// Number of consecutive successful polls needed to accept the tx
+	Beta int
+	// Number of processing txs that conflict with the tx
+	NumConflicts int
+	// Number of polls finished since the tx was issued
+	NumPolls int
+	// Number of those polls that the tx received at least alpha votes in
+	NumSuccessfulPolls int
+	// Average portion of each poll's sampled validators that voted for the tx.
+	// Validators are sampled by stake, so this estimates the portion of stake
+	// that prefers the tx.
+	VoteFraction float64
+	// Estimated probability that the tx is accepted by the next
+	// [Beta - Confidence] polls
+	Probability float64
+	// Estimated time until the tx is accepted. Zero if there isn't enough
+	// information to make an estimate.
+	ETA time.Duration
+}
+
+// TxFinalityReporter is implemented by engines that can estimate how close
+// their processing transactions are to being accepted
+type TxFinalityReporter interface {
+	// TxFinality returns the finality estimate of the processing tx with the
+	// given ID. Returns false if the tx isn't processing.
+	// Assumes the context lock is held.
+	TxFinality(txID ids.ID) (TxFinality, bool)
+}
@@ -0,0 +1,17 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// FrontierReporter is implemented by engines that can report the last
+// containers they accepted
+type FrontierReporter interface {
+	// Frontier returns the IDs of the last accepted containers and the height
+	// of the highest of them.
+	// Assumes the context lock is held.
+	Frontier() ([]ids.ID, uint64, error)
+}
@@ -0,0 +1,47 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"fmt"
+)
+
+// EngineHealth describes the state of a consensus engine. Every engine reports
+// it in its health check, so that the health of every chain is described by
+// the same fields.
+type EngineHealth struct {
+	// True if the chain finished bootstrapping
+	Bootstrapped bool `json:"bootstrapped"`
+	// Number of containers processing in consensus
+	Processing int `json:"processing"`
+	// Number of polls that haven't finished
+	OutstandingPolls int `json:"outstandingPolls"`
+	// Time since the engine first saw its last accepted containers. Empty
+	// while the chain is bootstrapping.
+	LastAcceptedAge string `json:"lastAcceptedAge,omitempty"`
+}
+
+// ChainHealth returns the health check result of a chain whose engine is
+// described by [engine], and whose consensus and VM returned the given results.
+// The chain is unhealthy if its consensus or its VM is.
+func ChainHealth(
+	engine EngineHealth,
+	consensusIntf interface{},
+	consensusErr error,
+	vmIntf interface{},
+	vmErr error,
+) (interface{}, error) {
+	intf := map[string]interface{}{
+		"engine":    engine,
+		"consensus": consensusIntf,
+		"vm":        vmIntf,
+	}
+	if consensusErr == nil {
+		return intf, vmErr
+	}
+	if vmErr == nil {
+		return intf, consensusErr
+	}
+	return intf, fmt.Errorf("vm: %s ; consensus: %s", vmErr, consensusErr)
+}
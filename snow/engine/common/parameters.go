@@ -0,0 +1,28 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// SamplingParameters are the consensus parameters that can be changed while a
+// chain is running
+type SamplingParameters struct {
+	// Number of validators sampled for a poll
+	K int
+	// Number of votes a choice needs for a poll to be successful
+	Alpha int
+	// Number of polls kept outstanding while consensus is processing
+	ConcurrentRepolls int
+}
+
+// ParameterUpdater is implemented by engines whose sampling parameters can be
+// changed while the chain is running
+type ParameterUpdater interface {
+	// SamplingParameters returns the current sampling parameters
+	SamplingParameters() SamplingParameters
+
+	// UpdateSamplingParameters replaces the sampling parameters of the engine
+	// and of its consensus instance. Polls that are outstanding finish with
+	// the parameters they were started with. If the parameters are invalid, an
+	// error is returned and no parameter is changed.
+	UpdateSamplingParameters(SamplingParameters) error
+}
@@ -0,0 +1,33 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// PollInfo describes a poll of the network that hasn't finished yet
+type PollInfo struct {
+	RequestID uint32
+	// ID of the block or vertex the network was queried about
+	ContainerID ids.ID
+	// Validators that were queried
+	Validators []ids.ShortID
+	// Validators that haven't responded yet
+	Pending []ids.ShortID
+	// Number of chits received
+	NumChits int
+	// Time the poll was started
+	Start time.Time
+}
+
+// PollReporter is implemented by engines that can report the polls they are
+// waiting on
+type PollReporter interface {
+	// OutstandingPolls returns the polls that haven't finished yet.
+	// Assumes the context lock is held.
+	OutstandingPolls() []PollInfo
+}
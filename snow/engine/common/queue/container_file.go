@@ -0,0 +1,300 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package queue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Each record is the job ID followed by the length of the job's bytes
+	recordHeaderLen = len(ids.ID{}) + 4
+	// A record with this length marks the job as deleted and has no body
+	tombstoneLen = math.MaxUint32
+	// Minimum number of bytes to map when the file is first read from
+	minMappingLen = 1 << 20
+)
+
+var (
+	committedSizeKey = []byte("committed size")
+
+	errCorruptContainerFile = errors.New("container file is corrupt")
+
+	_ containerStore = &containerFile{}
+)
+
+// containerStore is where the queue keeps the bytes of jobs that haven't been
+// executed yet
+type containerStore interface {
+	database.KeyValueReader
+	database.KeyValueWriter
+}
+
+type record struct {
+	offset int64
+	length uint32
+}
+
+// containerFile stores the bytes of jobs in an append-only file rather than in
+// the database. The location of every job in the file is kept in memory and
+// the file is memory-mapped for reads, so storing a job costs one sequential
+// write instead of the several a database insert and its later deletion incur.
+//
+// Deleting a job appends a tombstone for it. Once every job in the file has
+// been deleted, the file is truncated on the next commit.
+//
+// The size of the file that the committed queue refers to is stored in [db],
+// so records written after the last commit are dropped on restart.
+type containerFile struct {
+	db   containerStore
+	file *os.File
+	// Number of bytes written to [file]
+	size int64
+	// Size of the file that is stored in [db] on the next commit
+	pendingSize int64
+	// Memory-mapping of the start of [file]. May be shorter than [size].
+	mapping []byte
+	// job ID --> location of the job's bytes in [file]
+	index map[ids.ID]record
+}
+
+func newContainerFile(db containerStore, path string) (*containerFile, error) {
+	committedSize, err := database.GetUInt64(db, committedSizeKey)
+	if err == database.ErrNotFound {
+		committedSize = 0
+	} else if err != nil {
+		return nil, fmt.Errorf("couldn't read committed size of container file: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, perms.ReadWrite)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open container file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if uint64(info.Size()) < committedSize {
+		_ = file.Close()
+		return nil, fmt.Errorf("%w: %s is %d bytes but %d bytes were committed",
+			errCorruptContainerFile, path, info.Size(), committedSize)
+	}
+	// Drop anything written after the last commit
+	if err := file.Truncate(int64(committedSize)); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	f := &containerFile{
+		db:          db,
+		file:        file,
+		size:        int64(committedSize),
+		pendingSize: int64(committedSize),
+		index:       make(map[ids.ID]record),
+	}
+	if err := f.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// load rebuilds the index from the records in the file
+func (f *containerFile) load() error {
+	reader := bufio.NewReader(io.NewSectionReader(f.file, 0, f.size))
+	header := make([]byte, recordHeaderLen)
+	offset := int64(0)
+	for offset < f.size {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return fmt.Errorf("%w: couldn't read record at offset %d: %s", errCorruptContainerFile, offset, err)
+		}
+		offset += int64(recordHeaderLen)
+
+		jobID, err := ids.ToID(header[:len(ids.ID{})])
+		if err != nil {
+			return err
+		}
+		length := binary.BigEndian.Uint32(header[len(ids.ID{}):])
+		if length == tombstoneLen {
+			delete(f.index, jobID)
+			continue
+		}
+		if offset+int64(length) > f.size {
+			return fmt.Errorf("%w: record at offset %d runs past the end of the file", errCorruptContainerFile, offset)
+		}
+		if _, err := reader.Discard(int(length)); err != nil {
+			return err
+		}
+		f.index[jobID] = record{
+			offset: offset,
+			length: length,
+		}
+		offset += int64(length)
+	}
+	return nil
+}
+
+// Has returns true if the bytes of job [key] are in the file
+func (f *containerFile) Has(key []byte) (bool, error) {
+	jobID, err := ids.ToID(key)
+	if err != nil {
+		return false, err
+	}
+	_, exists := f.index[jobID]
+	return exists, nil
+}
+
+// Get returns a copy of the bytes of job [key]
+func (f *containerFile) Get(key []byte) ([]byte, error) {
+	jobID, err := ids.ToID(key)
+	if err != nil {
+		return nil, err
+	}
+	rec, exists := f.index[jobID]
+	if !exists {
+		return nil, database.ErrNotFound
+	}
+
+	value := make([]byte, rec.length)
+	end := rec.offset + int64(rec.length)
+	if end > int64(len(f.mapping)) {
+		if err := f.remap(end); err != nil {
+			return nil, err
+		}
+	}
+	if f.mapping == nil {
+		// Memory-mapping isn't supported on this platform
+		_, err := f.file.ReadAt(value, rec.offset)
+		return value, err
+	}
+	copy(value, f.mapping[rec.offset:end])
+	return value, nil
+}
+
+// Put appends the bytes of job [key] to the file
+func (f *containerFile) Put(key []byte, value []byte) error {
+	jobID, err := ids.ToID(key)
+	if err != nil {
+		return err
+	}
+	if len(value) >= tombstoneLen {
+		return fmt.Errorf("job %s is too large to store", jobID)
+	}
+
+	buf := make([]byte, recordHeaderLen+len(value))
+	copy(buf, jobID[:])
+	binary.BigEndian.PutUint32(buf[len(ids.ID{}):], uint32(len(value)))
+	copy(buf[recordHeaderLen:], value)
+	if _, err := f.file.WriteAt(buf, f.size); err != nil {
+		return err
+	}
+
+	f.index[jobID] = record{
+		offset: f.size + int64(recordHeaderLen),
+		length: uint32(len(value)),
+	}
+	f.size += int64(len(buf))
+	f.pendingSize = f.size
+	return nil
+}
+
+// Delete appends a tombstone for job [key] to the file
+func (f *containerFile) Delete(key []byte) error {
+	jobID, err := ids.ToID(key)
+	if err != nil {
+		return err
+	}
+	if _, exists := f.index[jobID]; !exists {
+		return nil
+	}
+
+	header := make([]byte, recordHeaderLen)
+	copy(header, jobID[:])
+	binary.BigEndian.PutUint32(header[len(ids.ID{}):], tombstoneLen)
+	if _, err := f.file.WriteAt(header, f.size); err != nil {
+		return err
+	}
+
+	delete(f.index, jobID)
+	f.size += int64(recordHeaderLen)
+	f.pendingSize = f.size
+	if len(f.index) == 0 {
+		// Nothing in the file is needed anymore, so the next commit can
+		// discard it.
+		f.pendingSize = 0
+	}
+	return nil
+}
+
+// Sync flushes the records written to the file to disk. Should be called
+// before the size of the file is written to [db], so that the committed size
+// never refers to records that could be lost.
+func (f *containerFile) Sync() error {
+	return f.file.Sync()
+}
+
+// WriteSize writes the size of the file to [db]. Should be called before [db]
+// is committed.
+func (f *containerFile) WriteSize() error {
+	return database.PutUInt64(f.db, committedSizeKey, uint64(f.pendingSize))
+}
+
+// Compact truncates the file if none of its records are needed anymore. Should
+// be called after [db] is committed.
+func (f *containerFile) Compact() error {
+	if f.pendingSize == f.size {
+		return nil
+	}
+	if err := f.file.Truncate(f.pendingSize); err != nil {
+		return err
+	}
+	f.size = f.pendingSize
+	return nil
+}
+
+// Close unmaps and closes the file
+func (f *containerFile) Close() error {
+	errs := wrappers.Errs{}
+	if f.mapping != nil {
+		errs.Add(munmap(f.mapping))
+		f.mapping = nil
+	}
+	errs.Add(f.file.Close())
+	return errs.Err
+}
+
+// remap maps at least the first [minLen] bytes of the file into memory
+func (f *containerFile) remap(minLen int64) error {
+	newLen := int64(len(f.mapping)) * 2
+	if newLen < minMappingLen {
+		newLen = minMappingLen
+	}
+	for newLen < minLen {
+		newLen *= 2
+	}
+	if f.mapping != nil {
+		if err := munmap(f.mapping); err != nil {
+			return err
+		}
+		f.mapping = nil
+	}
+	mapping, err := mmap(f.file, int(newLen))
+	if err != nil {
+		return fmt.Errorf("couldn't memory-map container file: %w", err)
+	}
+	f.mapping = mapping
+	return nil
+}
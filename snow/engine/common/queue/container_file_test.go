@@ -0,0 +1,187 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerFile(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	path := filepath.Join(t.TempDir(), "containers")
+
+	f, err := newContainerFile(db, path)
+	assert.NoError(err)
+
+	id0 := ids.GenerateTestID()
+	id1 := ids.GenerateTestID()
+	assert.NoError(f.Put(id0[:], []byte{1, 2, 3}))
+	assert.NoError(f.Put(id1[:], []byte{4, 5}))
+
+	has, err := f.Has(id0[:])
+	assert.NoError(err)
+	assert.True(has)
+
+	value, err := f.Get(id0[:])
+	assert.NoError(err)
+	assert.Equal([]byte{1, 2, 3}, value)
+
+	value, err = f.Get(id1[:])
+	assert.NoError(err)
+	assert.Equal([]byte{4, 5}, value)
+
+	assert.NoError(f.Delete(id0[:]))
+	has, err = f.Has(id0[:])
+	assert.NoError(err)
+	assert.False(has)
+	_, err = f.Get(id0[:])
+	assert.Equal(database.ErrNotFound, err)
+
+	assert.NoError(f.WriteSize())
+	assert.NoError(f.Compact())
+
+	// Writes after the last commit should be dropped on restart
+	id2 := ids.GenerateTestID()
+	assert.NoError(f.Put(id2[:], []byte{6}))
+
+	f, err = newContainerFile(db, path)
+	assert.NoError(err)
+
+	has, err = f.Has(id0[:])
+	assert.NoError(err)
+	assert.False(has)
+	has, err = f.Has(id2[:])
+	assert.NoError(err)
+	assert.False(has)
+	value, err = f.Get(id1[:])
+	assert.NoError(err)
+	assert.Equal([]byte{4, 5}, value)
+}
+
+func TestContainerFileTruncatesWhenEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	path := filepath.Join(t.TempDir(), "containers")
+
+	f, err := newContainerFile(db, path)
+	assert.NoError(err)
+
+	jobID := ids.GenerateTestID()
+	assert.NoError(f.Put(jobID[:], []byte{1, 2, 3}))
+	assert.NoError(f.WriteSize())
+	assert.NoError(f.Compact())
+
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.NotZero(info.Size())
+
+	assert.NoError(f.Delete(jobID[:]))
+	assert.NoError(f.WriteSize())
+	assert.NoError(f.Compact())
+
+	info, err = os.Stat(path)
+	assert.NoError(err)
+	assert.Zero(info.Size())
+
+	// The file can be written to again after being truncated
+	assert.NoError(f.Put(jobID[:], []byte{4}))
+	value, err := f.Get(jobID[:])
+	assert.NoError(err)
+	assert.Equal([]byte{4}, value)
+}
+
+func TestContainerFileShorterThanCommitted(t *testing.T) {
+	db := memdb.New()
+	path := filepath.Join(t.TempDir(), "containers")
+
+	f, err := newContainerFile(db, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobID := ids.GenerateTestID()
+	if err := f.Put(jobID[:], []byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteSize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newContainerFile(db, path); err == nil {
+		t.Fatal("should have failed to open a truncated container file")
+	}
+}
+
+// Test that a file-backed queue keeps jobs across a restart and empties the
+// file once every job has been executed
+func TestFileBackedPushAndExecute(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &TestParser{T: t}
+	db := memdb.New()
+	path := filepath.Join(t.TempDir(), "containers")
+
+	jobs, err := NewFileBacked(db, path, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+
+	jobID := ids.GenerateTestID()
+	job := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return jobID },
+		MissingDependenciesF: func() (ids.Set, error) { return ids.Set{}, nil },
+		ExecuteF:             func() error { return nil },
+		BytesF:               func() []byte { return []byte{0} },
+	}
+
+	pushed, err := jobs.Push(job)
+	assert.NoError(err)
+	assert.True(pushed)
+	assert.NoError(jobs.Commit())
+	assert.NoError(jobs.Close())
+
+	jobs, err = NewFileBacked(db, path, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+
+	has, err := jobs.Has(jobID)
+	assert.NoError(err)
+	assert.True(has)
+
+	parser.ParseF = func(b []byte) (Job, error) {
+		assert.Equal([]byte{0}, b)
+		return job, nil
+	}
+
+	count, err := jobs.ExecuteAll(snow.DefaultContextTest(), &common.Halter{}, false)
+	assert.NoError(err)
+	assert.Equal(1, count)
+
+	has, err = jobs.Has(jobID)
+	assert.NoError(err)
+	assert.False(has)
+
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Zero(info.Size())
+
+	assert.NoError(jobs.Close())
+	_, err = jobs.Push(job)
+	assert.Error(err, "shouldn't be able to write to a closed queue")
+}
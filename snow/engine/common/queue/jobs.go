@@ -34,9 +34,30 @@ func New(
 	db database.Database,
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
+) (*Jobs, error) {
+	return newJobs(db, "", metricsNamespace, metricsRegisterer)
+}
+
+// NewFileBacked attempts to create a new job queue from the provided database
+// that stores the bytes of jobs in a memory-mapped file at [containerPath]
+// rather than in the database.
+func NewFileBacked(
+	db database.Database,
+	containerPath string,
+	metricsNamespace string,
+	metricsRegisterer prometheus.Registerer,
+) (*Jobs, error) {
+	return newJobs(db, containerPath, metricsNamespace, metricsRegisterer)
+}
+
+func newJobs(
+	db database.Database,
+	containerPath string,
+	metricsNamespace string,
+	metricsRegisterer prometheus.Registerer,
 ) (*Jobs, error) {
 	vdb := versiondb.New(db)
-	state, err := newState(vdb, metricsNamespace, metricsRegisterer)
+	state, err := newState(vdb, containerPath, metricsNamespace, metricsRegisterer)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create new jobs state: %s", err)
 	}
@@ -169,9 +190,35 @@ func (j *Jobs) ExecuteAll(ctx *snow.Context, halter common.Haltable, restarted b
 	return numExecuted, nil
 }
 
+// Metadata returns a database, partitioned from the jobs, for data that
+// describes the queue. Writes to it are persisted by Commit, atomically with
+// the changes to the queue.
+func (j *Jobs) Metadata() database.Database { return j.state.metadata }
+
 // Commit the versionDB to the underlying database.
 func (j *Jobs) Commit() error {
-	return j.db.Commit()
+	if j.state.file == nil {
+		return j.db.Commit()
+	}
+	if err := j.state.file.Sync(); err != nil {
+		return err
+	}
+	if err := j.state.file.WriteSize(); err != nil {
+		return err
+	}
+	if err := j.db.Commit(); err != nil {
+		return err
+	}
+	return j.state.file.Compact()
+}
+
+// Close releases the file that the bytes of jobs are stored in, if any. The
+// queue must not be used afterwards.
+func (j *Jobs) Close() error {
+	if j.state.file == nil {
+		return nil
+	}
+	return j.state.file.Close()
 }
 
 type JobsWithMissing struct {
@@ -192,7 +239,25 @@ func NewWithMissing(
 	if err != nil {
 		return nil, err
 	}
+	return newWithMissing(innerJobs)
+}
+
+// NewFileBackedWithMissing is NewWithMissing for a job queue that stores the
+// bytes of jobs in a memory-mapped file at [containerPath].
+func NewFileBackedWithMissing(
+	db database.Database,
+	containerPath string,
+	metricsNamespace string,
+	metricsRegisterer prometheus.Registerer,
+) (*JobsWithMissing, error) {
+	innerJobs, err := NewFileBacked(db, containerPath, metricsNamespace, metricsRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	return newWithMissing(innerJobs)
+}
 
+func newWithMissing(innerJobs *Jobs) (*JobsWithMissing, error) {
 	jobs := &JobsWithMissing{
 		Jobs: innerJobs,
 	}
@@ -339,6 +339,37 @@ func TestMissingJobs(t *testing.T) {
 	assert.False(containsJob1ID)
 }
 
+// Test that the metadata of a queue is only persisted when the queue is
+// committed.
+func TestMetadataCommittedWithQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+
+	jobs, err := NewWithMissing(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	jobID := ids.GenerateTestID()
+	jobs.AddMissingID(jobID)
+	err = jobs.Metadata().Put([]byte("key"), []byte("value"))
+	assert.NoError(err)
+
+	dbSize, err := database.Size(db)
+	assert.NoError(err)
+	assert.Zero(dbSize)
+
+	err = jobs.Commit()
+	assert.NoError(err)
+
+	jobs, err = NewWithMissing(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.Equal([]ids.ID{jobID}, jobs.MissingIDs())
+
+	value, err := jobs.Metadata().Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+}
+
 func TestHandleJobWithMissingDependencyOnRunnableStack(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,19 @@
+// +build !windows
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package queue
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps the first [length] bytes of [file] into memory for reading. Only
+// the bytes that have been written to [file] may be read from the mapping.
+func mmap(file *os.File, length int) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, length, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func munmap(mapping []byte) error { return syscall.Munmap(mapping) }
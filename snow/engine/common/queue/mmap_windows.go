@@ -0,0 +1,13 @@
+// +build windows
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package queue
+
+import "os"
+
+// mmap isn't supported on windows, so container files are read with ReadAt
+func mmap(*os.File, int) ([]byte, error) { return nil, nil }
+
+func munmap([]byte) error { return nil }
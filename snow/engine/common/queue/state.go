@@ -25,6 +25,8 @@ var (
 	jobsKey           = []byte("jobs")
 	dependenciesKey   = []byte("dependencies")
 	missingJobIDsKey  = []byte("missing job IDs")
+	containerFileKey  = []byte("container file")
+	metadataKey       = []byte("metadata")
 )
 
 type state struct {
@@ -32,7 +34,9 @@ type state struct {
 	runnableJobIDs linkeddb.LinkedDB
 	cachingEnabled bool
 	jobsCache      cache.Cacher
-	jobs           database.Database
+	jobs           containerStore
+	// If non-nil, [jobs] is this file rather than a database
+	file *containerFile
 	// Should be prefixed with the jobID that we are attempting to find the
 	// dependencies of. This prefixdb.Database should then be wrapped in a
 	// linkeddb.LinkedDB to read the dependencies.
@@ -41,10 +45,15 @@ type state struct {
 	// made.
 	dependentsCache cache.Cacher
 	missingJobIDs   linkeddb.LinkedDB
+	// Data stored alongside the jobs by the user of the queue
+	metadata database.Database
 }
 
+// If [containerPath] is non-empty, the bytes of jobs are stored in the file at
+// that path rather than in [db].
 func newState(
 	db database.Database,
+	containerPath string,
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 ) (*state, error) {
@@ -53,7 +62,7 @@ func newState(
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create metered cache: %s", err)
 	}
-	return &state{
+	s := &state{
 		runnableJobIDs:  linkeddb.NewDefault(prefixdb.New(runnableJobIDsKey, db)),
 		cachingEnabled:  true,
 		jobsCache:       jobsCache,
@@ -61,7 +70,16 @@ func newState(
 		dependencies:    prefixdb.New(dependenciesKey, db),
 		dependentsCache: &cache.LRU{Size: dependentsCacheSize},
 		missingJobIDs:   linkeddb.NewDefault(prefixdb.New(missingJobIDsKey, db)),
-	}, nil
+		metadata:        prefixdb.New(metadataKey, db),
+	}
+	if containerPath != "" {
+		s.file, err = newContainerFile(prefixdb.New(containerFileKey, db), containerPath)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open container file: %w", err)
+		}
+		s.jobs = s.file
+	}
+	return s, nil
 }
 
 // AddRunnableJob adds [jobID] to the runnable queue
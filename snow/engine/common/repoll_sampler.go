@@ -0,0 +1,157 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var errNoLatencyEstimator = errors.New("biasing repolls by latency requires a latency estimator")
+
+// LatencyEstimator estimates how long validators take to respond to requests
+type LatencyEstimator interface {
+	// Latency returns the estimated latency of the responses of
+	// [validatorID]. Returns false if there is no estimate.
+	Latency(validatorID ids.ShortID) (time.Duration, bool)
+
+	// TimeoutDuration returns the current network timeout
+	TimeoutDuration() time.Duration
+}
+
+// BenchedChecker reports which validators are benched. Requests to a benched
+// validator fail immediately rather than being sent.
+type BenchedChecker interface {
+	// IsBenched returns true if requests to [validatorID] regarding [chainID]
+	// fail immediately
+	IsBenched(validatorID ids.ShortID, chainID ids.ID) bool
+}
+
+// RepollSampler samples the validators queried by polls.
+//
+// Polls issued while another poll is outstanding may be biased towards
+// validators that are estimated to respond quickly, to reduce the tail latency
+// of polls. The weight of a validator in such a sample is its stake multiplied
+// by 1 + [bias]*(1 - latency/timeout), so a validator that responds instantly
+// is at most 1 + [bias] times as likely to be sampled as one that times out.
+// Validators without a latency estimate aren't favored. Other polls are sampled
+// by stake only.
+type RepollSampler struct {
+	validators validators.Set
+	latencies  LatencyEstimator
+	bias       float64
+
+	numBiasedSamples prometheus.Counter
+	// Mean estimated latency, in milliseconds, of the validators sampled for a
+	// poll, by whether the sample was biased
+	biasedSampleLatency, unbiasedSampleLatency prometheus.Histogram
+}
+
+// NewRepollSampler returns a sampler of [vdrs]. If [bias] is 0, samples
+// aren't biased and no metrics are registered.
+func NewRepollSampler(
+	vdrs validators.Set,
+	latencies LatencyEstimator,
+	bias float64,
+	namespace string,
+	registerer prometheus.Registerer,
+) (*RepollSampler, error) {
+	s := &RepollSampler{
+		validators: vdrs,
+		latencies:  latencies,
+		bias:       bias,
+	}
+	if bias == 0 {
+		return s, nil
+	}
+	if latencies == nil {
+		return nil, errNoLatencyEstimator
+	}
+
+	s.numBiasedSamples = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "repoll_biased_samples",
+		Help:      "Number of polls whose validators were sampled with a bias towards low latency",
+	})
+	s.biasedSampleLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repoll_biased_sample_latency",
+		Help:      "Mean estimated latency, in milliseconds, of the validators sampled for a poll with a bias towards low latency",
+		Buckets:   metric.MillisecondsBuckets,
+	})
+	s.unbiasedSampleLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repoll_unbiased_sample_latency",
+		Help:      "Mean estimated latency, in milliseconds, of the validators sampled for a poll by stake only",
+		Buckets:   metric.MillisecondsBuckets,
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(s.numBiasedSamples),
+		registerer.Register(s.biasedSampleLatency),
+		registerer.Register(s.unbiasedSampleLatency),
+	)
+	return s, errs.Err
+}
+
+// Sample [size] validators for a poll. If [outstanding], another poll is
+// outstanding and the sample may be biased towards low latency.
+func (s *RepollSampler) Sample(size int, outstanding bool) ([]validators.Validator, error) {
+	if s.bias == 0 {
+		return s.validators.Sample(size)
+	}
+
+	if !outstanding {
+		vdrs, err := s.validators.Sample(size)
+		if err == nil {
+			s.unbiasedSampleLatency.Observe(s.meanLatency(vdrs))
+		}
+		return vdrs, err
+	}
+
+	timeout := s.latencies.TimeoutDuration()
+	vdrs, err := s.validators.SampleBiased(size, func(vdrID ids.ShortID) float64 {
+		latency, ok := s.latencies.Latency(vdrID)
+		if !ok || timeout <= 0 {
+			return 1
+		}
+		if latency > timeout {
+			latency = timeout
+		}
+		return 1 + s.bias*(1-float64(latency)/float64(timeout))
+	})
+	if err != nil {
+		// The biased weights may overflow when the stake is large. Fall back
+		// to sampling by stake.
+		return s.validators.Sample(size)
+	}
+	s.numBiasedSamples.Inc()
+	s.biasedSampleLatency.Observe(s.meanLatency(vdrs))
+	return vdrs, nil
+}
+
+// meanLatency returns the mean estimated latency, in milliseconds, of the
+// validators in [vdrs] that have an estimate
+func (s *RepollSampler) meanLatency(vdrs []validators.Validator) float64 {
+	total := time.Duration(0)
+	numEstimated := 0
+	for _, vdr := range vdrs {
+		if latency, ok := s.latencies.Latency(vdr.ID()); ok {
+			total += latency
+			numEstimated++
+		}
+	}
+	if numEstimated == 0 {
+		return 0
+	}
+	return float64(total/time.Duration(numEstimated)) / float64(time.Millisecond)
+}
@@ -0,0 +1,84 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+type testLatencyEstimator struct {
+	latencies map[ids.ShortID]time.Duration
+	timeout   time.Duration
+}
+
+func (e *testLatencyEstimator) Latency(vdrID ids.ShortID) (time.Duration, bool) {
+	latency, ok := e.latencies[vdrID]
+	return latency, ok
+}
+
+func (e *testLatencyEstimator) TimeoutDuration() time.Duration { return e.timeout }
+
+func TestRepollSamplerNeedsEstimator(t *testing.T) {
+	_, err := NewRepollSampler(validators.NewSet(), nil, 0.5, "", prometheus.NewRegistry())
+	assert.Equal(t, errNoLatencyEstimator, err)
+
+	// Without a bias, the estimator isn't used
+	s, err := NewRepollSampler(validators.NewSet(), nil, 0, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+	_, err = s.Sample(1, true)
+	assert.Error(t, err, "should have errored due to an insufficient number of validators")
+}
+
+func TestRepollSamplerBias(t *testing.T) {
+	assert := assert.New(t)
+
+	fast := ids.GenerateTestShortID()
+	slow := ids.GenerateTestShortID()
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(fast, 1000))
+	assert.NoError(vdrs.AddWeight(slow, 1000))
+
+	latencies := &testLatencyEstimator{
+		latencies: map[ids.ShortID]time.Duration{
+			fast: 0,
+			slow: 2 * time.Second,
+		},
+		timeout: time.Second,
+	}
+	s, err := NewRepollSampler(vdrs, latencies, 1, "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	// The weight of [fast] is doubled and the weight of [slow] isn't changed,
+	// so all of the weight of [fast] and half of the weight of [slow] can be
+	// sampled without replacement
+	sampled, err := s.Sample(3000, true)
+	assert.NoError(err)
+	counts := map[ids.ShortID]int{}
+	for _, vdr := range sampled {
+		counts[vdr.ID()]++
+	}
+	assert.Equal(2000, counts[fast])
+	assert.Equal(1000, counts[slow])
+	_, err = s.Sample(3001, true)
+	assert.Error(err)
+
+	// Without an outstanding poll, the sample isn't biased
+	_, err = s.Sample(2000, false)
+	assert.NoError(err)
+	_, err = s.Sample(2001, false)
+	assert.Error(err)
+
+	assert.Equal(float64(1000), s.meanLatency([]validators.Validator{
+		validators.NewValidator(fast, 1),
+		validators.NewValidator(slow, 1),
+		validators.NewValidator(ids.GenerateTestShortID(), 1),
+	}))
+}
@@ -70,6 +70,13 @@ func (r *Requests) Remove(vdr ids.ShortID, requestID uint32) (ids.ID, bool) {
 	return containerID, true
 }
 
+// Get returns the container ID of the request [requestID] sent to [vdr], if
+// the request is outstanding
+func (r *Requests) Get(vdr ids.ShortID, requestID uint32) (ids.ID, bool) {
+	containerID, ok := r.reqsToID[vdr][requestID]
+	return containerID, ok
+}
+
 // RemoveAny outstanding requests for the container ID. True is returned if the
 // container ID had an outstanding request.
 func (r *Requests) RemoveAny(containerID ids.ID) bool {
@@ -85,6 +92,9 @@ func (r *Requests) RemoveAny(containerID ids.ID) bool {
 // Len returns the total number of outstanding requests.
 func (r *Requests) Len() int { return len(r.idToReq) }
 
+// LenOf returns the number of outstanding requests sent to [vdr].
+func (r *Requests) LenOf(vdr ids.ShortID) int { return len(r.reqsToID[vdr]) }
+
 // Contains returns true if there is an outstanding request for the container
 // ID.
 func (r *Requests) Contains(containerID ids.ID) bool {
@@ -88,3 +88,21 @@ func TestRequests(t *testing.T) {
 	length = req.Len()
 	assert.Equal(t, 0, length, "should have had no outstanding requests")
 }
+
+func TestRequestsLenOf(t *testing.T) {
+	req := Requests{}
+	vdr0 := ids.ShortID{0}
+	vdr1 := ids.ShortID{1}
+
+	assert.Equal(t, 0, req.LenOf(vdr0), "shouldn't have any requests to vdr0")
+
+	req.Add(vdr0, 0, ids.Empty.Prefix(0))
+	req.Add(vdr0, 1, ids.Empty.Prefix(1))
+	req.Add(vdr1, 2, ids.Empty.Prefix(2))
+
+	assert.Equal(t, 2, req.LenOf(vdr0), "should have two requests to vdr0")
+	assert.Equal(t, 1, req.LenOf(vdr1), "should have one request to vdr1")
+
+	req.RemoveAny(ids.Empty.Prefix(2))
+	assert.Equal(t, 0, req.LenOf(vdr1), "shouldn't have any requests to vdr1")
+}
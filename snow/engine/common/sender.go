@@ -14,6 +14,7 @@ type Sender interface {
 	AcceptedSender
 	FetchSender
 	QuerySender
+	StateSummarySender
 	Gossiper
 }
 
@@ -80,6 +81,20 @@ type QuerySender interface {
 	Chits(validatorID ids.ShortID, requestID uint32, votes []ids.ID)
 }
 
+// StateSummarySender defines how a consensus engine sends state sync messages
+// to other validators
+type StateSummarySender interface {
+	// GetStateSummary requests that the validator with ID [validatorID] send
+	// its accepted frontier and a summary of the chain's state as of that
+	// frontier.
+	GetStateSummary(validatorID ids.ShortID, requestID uint32)
+
+	// StateSummary responds to a GetStateSummary message with this engine's
+	// accepted frontier, [frontier], and a summary of the chain's state as of
+	// that frontier, [summary].
+	StateSummary(validatorID ids.ShortID, requestID uint32, frontier [][]byte, summary []byte)
+}
+
 // Gossiper defines how a consensus engine gossips a container on the accepted
 // frontier to other validators
 type Gossiper interface {
@@ -0,0 +1,88 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	stdmath "math"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+// StaleDetector reports that a chain fell behind the network. A chain is stale
+// if nothing was accepted for longer than a threshold while beacons holding at
+// least [alpha] weight gossiped accepted containers higher than the chain's
+// last accepted container.
+//
+// The heights of gossiped containers aren't verified, so a single peer can't
+// make the chain stale. Only the beacons, weighted by their stake, count.
+type StaleDetector struct {
+	beacons   validators.Set
+	alpha     uint64
+	threshold time.Duration
+
+	// Height of the last accepted container, and when it was accepted
+	lastAcceptedHeight uint64
+	lastAcceptedTime   time.Time
+
+	// Beacons that gossiped a container higher than [lastAcceptedHeight], and
+	// their total weight
+	ahead       ids.ShortSet
+	aheadWeight uint64
+}
+
+// NewStaleDetector returns a detector that reports the chain as stale once
+// nothing was accepted for longer than [threshold] while beacons holding at
+// least [alpha] weight gossiped higher containers. If [threshold] isn't
+// positive, the chain is never reported as stale.
+func NewStaleDetector(beacons validators.Set, alpha uint64, threshold time.Duration) *StaleDetector {
+	return &StaleDetector{
+		beacons:   beacons,
+		alpha:     alpha,
+		threshold: threshold,
+	}
+}
+
+// Accepted records that a container at [height] was accepted at [now], or
+// that the chain started at [now] with a container at [height] as its last
+// accepted container. The gossip received before is discarded.
+func (s *StaleDetector) Accepted(height uint64, now time.Time) {
+	s.lastAcceptedHeight = height
+	s.lastAcceptedTime = now
+	s.ahead.Clear()
+	s.aheadWeight = 0
+}
+
+// Gossiped records that [vdr] gossiped an accepted container at [height]
+func (s *StaleDetector) Gossiped(vdr ids.ShortID, height uint64) {
+	if height <= s.lastAcceptedHeight || s.ahead.Contains(vdr) {
+		return
+	}
+	weight, ok := s.beacons.GetWeight(vdr)
+	if !ok {
+		return
+	}
+	newWeight, err := math.Add64(s.aheadWeight, weight)
+	if err != nil {
+		newWeight = stdmath.MaxUint64
+	}
+	s.ahead.Add(vdr)
+	s.aheadWeight = newWeight
+}
+
+// Stale returns true if the chain is stale at [now]
+func (s *StaleDetector) Stale(now time.Time) bool {
+	return s.threshold > 0 &&
+		s.aheadWeight >= s.alpha &&
+		s.ahead.Len() > 0 &&
+		now.Sub(s.lastAcceptedTime) > s.threshold
+}
+
+// LastAccepted returns the height of the last accepted container and when it
+// was accepted
+func (s *StaleDetector) LastAccepted() (uint64, time.Time) {
+	return s.lastAcceptedHeight, s.lastAcceptedTime
+}
@@ -0,0 +1,55 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestStaleDetector(t *testing.T) {
+	beacons := validators.NewSet()
+	beacon0 := ids.GenerateTestShortID()
+	beacon1 := ids.GenerateTestShortID()
+	peer := ids.GenerateTestShortID()
+	assert.NoError(t, beacons.AddWeight(beacon0, 1))
+	assert.NoError(t, beacons.AddWeight(beacon1, 2))
+
+	s := NewStaleDetector(beacons, 3, time.Minute)
+	start := time.Unix(1000, 0)
+	s.Accepted(5, start)
+	later := start.Add(time.Hour)
+	assert.False(t, s.Stale(later))
+
+	// Gossip from peers that aren't beacons, or of containers that aren't
+	// higher, is ignored
+	s.Gossiped(peer, 6)
+	s.Gossiped(beacon1, 5)
+	assert.False(t, s.Stale(later))
+
+	// A beacon is only counted once
+	s.Gossiped(beacon1, 6)
+	s.Gossiped(beacon1, 7)
+	assert.False(t, s.Stale(later))
+
+	s.Gossiped(beacon0, 6)
+	assert.True(t, s.Stale(later))
+	assert.False(t, s.Stale(start.Add(time.Minute)))
+
+	// Accepting a container discards the gossip
+	s.Accepted(6, later)
+	height, lastAcceptedTime := s.LastAccepted()
+	assert.Equal(t, uint64(6), height)
+	assert.Equal(t, later, lastAcceptedTime)
+	assert.False(t, s.Stale(later.Add(time.Hour)))
+
+	disabled := NewStaleDetector(beacons, 1, 0)
+	disabled.Gossiped(beacon1, 1)
+	assert.False(t, disabled.Stale(later))
+}
@@ -0,0 +1,33 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// StateSummaryHandler is implemented by engines that support state sync.
+// State sync messages sent to engines that don't implement it are dropped.
+type StateSummaryHandler interface {
+	// Notify this engine of a request for its accepted frontier and a summary
+	// of the chain's state as of that frontier.
+	//
+	// This engine should respond with a StateSummary message with the same
+	// requestID, or drop the request if it can't summarize its state.
+	GetStateSummary(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of the accepted frontier and state summary of
+	// another validator.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetStateSummary message, is utilizing a
+	// unique requestID, or that [frontier] or [summary] are valid.
+	StateSummary(validatorID ids.ShortID, requestID uint32, frontier [][]byte, summary []byte) error
+
+	// Notify this engine that a GetStateSummary request it issued has failed.
+	//
+	// This function will be called if the engine sent a GetStateSummary
+	// message that is not anticipated to be responded to.
+	GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error
+}
@@ -16,6 +16,7 @@ type SenderTest struct {
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
 	CantGet, CantGetAncestors, CantPut, CantMultiPut,
+	CantGetStateSummary, CantStateSummary,
 	CantPullQuery, CantPushQuery, CantChits,
 	CantGossip bool
 
@@ -27,6 +28,8 @@ type SenderTest struct {
 	GetAncestorsF        func(ids.ShortID, uint32, ids.ID)
 	PutF                 func(ids.ShortID, uint32, ids.ID, []byte)
 	MultiPutF            func(ids.ShortID, uint32, [][]byte)
+	GetStateSummaryF     func(ids.ShortID, uint32)
+	StateSummaryF        func(ids.ShortID, uint32, [][]byte, []byte)
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
 	PullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	ChitsF               func(ids.ShortID, uint32, []ids.ID)
@@ -43,6 +46,8 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantGetAccepted = cant
 	s.CantPut = cant
 	s.CantMultiPut = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
 	s.CantPullQuery = cant
 	s.CantPushQuery = cant
 	s.CantChits = cant
@@ -137,6 +142,28 @@ func (s *SenderTest) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte)
 	}
 }
 
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetStateSummary(vdr ids.ShortID, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(vdr, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) StateSummary(vdr ids.ShortID, requestID uint32, frontier [][]byte, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(vdr, requestID, frontier, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
 // PushQuery calls PushQueryF if it was initialized. If it wasn't initialized
 // and this function shouldn't be called and testing was initialized, then
 // testing will fail.
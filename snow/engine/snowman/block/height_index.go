@@ -0,0 +1,187 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// Max number of bytes written to the database at once while backfilling
+const backfillBatchSize = 256 * units.KiB
+
+var (
+	heightPrefix  byte = 0x00
+	blockIDPrefix byte = 0x01
+	// Maps to the ID of the next block to index, while a backfill is ongoing
+	backfillKey = []byte{0x02}
+
+	errHeightNotIndexed = errors.New("no accepted block is indexed at this height")
+	errNotAccepted      = errors.New("backfilled block isn't accepted")
+
+	_ HeightIndexer     = &HeightIndex{}
+	_ triggers.Acceptor = &HeightIndex{}
+)
+
+// HeightIndexer looks up accepted blocks by their height
+type HeightIndexer interface {
+	// GetBlockIDAtHeight returns the ID of the accepted block at [height]
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+}
+
+// HeightIndexedChainVM is a ChainVM that indexes its accepted blocks by
+// height. The engine doesn't keep its own index of a chain whose VM
+// implements this interface.
+type HeightIndexedChainVM interface {
+	ChainVM
+	HeightIndexer
+}
+
+// HeightIndex persists the mapping between the IDs and the heights of the
+// accepted blocks of a chain. It implements triggers.Acceptor, so that it
+// learns about blocks as they're accepted.
+//
+// HeightIndex isn't thread-safe. It assumes that it's only used while the
+// chain's context lock is held.
+type HeightIndex struct {
+	db database.Database
+	vm ChainVM
+}
+
+// NewHeightIndex returns an index, stored in [db], of the blocks accepted by
+// [vm]
+func NewHeightIndex(db database.Database, vm ChainVM) *HeightIndex {
+	return &HeightIndex{
+		db: db,
+		vm: vm,
+	}
+}
+
+// Accept implements the triggers.Acceptor interface
+func (i *HeightIndex) Accept(_ *snow.Context, blkID ids.ID, blkBytes []byte) error {
+	blk, err := i.vm.ParseBlock(blkBytes)
+	if err != nil {
+		return err
+	}
+	batch := i.db.NewBatch()
+	if err := putBlock(batch, blkID, blk.Height()); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// GetBlockIDAtHeight implements the HeightIndexer interface
+func (i *HeightIndex) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	blkID, err := database.GetID(i.db, heightKey(height))
+	if err == database.ErrNotFound {
+		return ids.ID{}, errHeightNotIndexed
+	}
+	return blkID, err
+}
+
+// GetHeight returns the height of the accepted block [blkID]
+func (i *HeightIndex) GetHeight(blkID ids.ID) (uint64, error) {
+	return database.GetUInt64(i.db, blockIDKey(blkID))
+}
+
+// Backfill indexes the blocks that were accepted before the index was
+// created, or while it wasn't registered, by walking back from the accepted
+// block [lastAcceptedID] until it reaches a block that is already indexed or
+// the genesis block. A backfill that was interrupted is finished first.
+// Returns the number of blocks that were indexed.
+func (i *HeightIndex) Backfill(lastAcceptedID ids.ID) (int, error) {
+	numIndexed := 0
+	resumeID, err := database.GetID(i.db, backfillKey)
+	switch err {
+	case nil:
+		numIndexed, err = i.backfill(resumeID)
+		if err != nil {
+			return numIndexed, err
+		}
+	case database.ErrNotFound:
+	default:
+		return 0, err
+	}
+
+	numBackfilled, err := i.backfill(lastAcceptedID)
+	return numIndexed + numBackfilled, err
+}
+
+// backfill indexes [blkID] and its ancestors until it reaches a block that is
+// already indexed or the genesis block. Blocks are written in batches, along
+// with the ID of the next block to index, so that an interrupted backfill can
+// be resumed without leaving a gap in the index.
+func (i *HeightIndex) backfill(blkID ids.ID) (int, error) {
+	blk, err := i.vm.GetBlock(blkID)
+	if err != nil {
+		return 0, err
+	}
+
+	batch := i.db.NewBatch()
+	numIndexed := 0
+	for {
+		if blk.Status() != choices.Accepted {
+			return numIndexed, fmt.Errorf("%w: %s", errNotAccepted, blk.ID())
+		}
+
+		blkID := blk.ID()
+		height := blk.Height()
+		indexedID, err := i.GetBlockIDAtHeight(height)
+		switch {
+		case err == nil && indexedID == blkID:
+			// The ancestors of an indexed block are already indexed
+			return numIndexed, i.finishBackfill(batch)
+		case err != nil && err != errHeightNotIndexed:
+			return numIndexed, err
+		}
+
+		if err := putBlock(batch, blkID, height); err != nil {
+			return numIndexed, err
+		}
+		numIndexed++
+		if height == 0 {
+			return numIndexed, i.finishBackfill(batch)
+		}
+
+		blk = blk.Parent()
+		if batch.Size() >= backfillBatchSize {
+			if err := database.PutID(batch, backfillKey, blk.ID()); err != nil {
+				return numIndexed, err
+			}
+			if err := batch.Write(); err != nil {
+				return numIndexed, err
+			}
+			batch.Reset()
+		}
+	}
+}
+
+func (i *HeightIndex) finishBackfill(batch database.Batch) error {
+	if err := batch.Delete(backfillKey); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func putBlock(db database.KeyValueWriter, blkID ids.ID, height uint64) error {
+	if err := database.PutID(db, heightKey(height), blkID); err != nil {
+		return err
+	}
+	return database.PutUInt64(db, blockIDKey(blkID), height)
+}
+
+func heightKey(height uint64) []byte {
+	return append([]byte{heightPrefix}, database.PackUInt64(height)...)
+}
+
+func blockIDKey(blkID ids.ID) []byte {
+	return append([]byte{blockIDPrefix}, blkID[:]...)
+}
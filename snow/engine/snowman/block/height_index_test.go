@@ -0,0 +1,153 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+// newHeightIndexTest returns an empty height index of a chain of [numBlks]
+// accepted blocks
+func newHeightIndexTest(t *testing.T, numBlks int) (*HeightIndex, []*snowman.TestBlock) {
+	blks := make([]*snowman.TestBlock, numBlks)
+	for i := range blks {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i)),
+				StatusV: choices.Accepted,
+			},
+			HeightV: uint64(i),
+			BytesV:  []byte{byte(i)},
+		}
+		if i > 0 {
+			blks[i].ParentV = blks[i-1]
+		}
+	}
+
+	vm := &TestVM{}
+	vm.T = t
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errors.New("unknown block")
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(blk.Bytes(), blkBytes) {
+				return blk, nil
+			}
+		}
+		return nil, errors.New("unknown block")
+	}
+	return NewHeightIndex(memdb.New(), vm), blks
+}
+
+func checkIndexed(t *testing.T, index *HeightIndex, blks []*snowman.TestBlock) {
+	for _, blk := range blks {
+		blkID, err := index.GetBlockIDAtHeight(blk.Height())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blkID != blk.ID() {
+			t.Fatalf("should have indexed %s at height %d, indexed %s", blk.ID(), blk.Height(), blkID)
+		}
+		height, err := index.GetHeight(blk.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != blk.Height() {
+			t.Fatalf("should have indexed %s at height %d, indexed it at %d", blk.ID(), blk.Height(), height)
+		}
+	}
+}
+
+func TestHeightIndexAccept(t *testing.T) {
+	index, blks := newHeightIndexTest(t, 3)
+
+	if _, err := index.GetBlockIDAtHeight(1); err != errHeightNotIndexed {
+		t.Fatalf("should have failed to look up an unindexed height, got %v", err)
+	}
+
+	for _, blk := range blks {
+		if err := index.Accept(nil, blk.ID(), blk.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkIndexed(t, index, blks)
+}
+
+func TestHeightIndexBackfill(t *testing.T) {
+	index, blks := newHeightIndexTest(t, 5)
+
+	// Blocks accepted after the index was registered are indexed already
+	if err := index.Accept(nil, blks[4].ID(), blks[4].Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	numIndexed, err := index.Backfill(blks[3].ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numIndexed != 4 {
+		t.Fatalf("should have backfilled 4 blocks, backfilled %d", numIndexed)
+	}
+	checkIndexed(t, index, blks)
+
+	numIndexed, err = index.Backfill(blks[4].ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numIndexed != 0 {
+		t.Fatalf("shouldn't have backfilled an indexed chain, backfilled %d blocks", numIndexed)
+	}
+}
+
+func TestHeightIndexResumeBackfill(t *testing.T) {
+	index, blks := newHeightIndexTest(t, 5)
+
+	// Simulate a backfill that was interrupted after writing its first batch
+	for _, blk := range blks[3:] {
+		if err := putBlock(index.db, blk.ID(), blk.Height()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.PutID(index.db, backfillKey, blks[2].ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	numIndexed, err := index.Backfill(blks[4].ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numIndexed != 3 {
+		t.Fatalf("should have backfilled 3 blocks, backfilled %d", numIndexed)
+	}
+	checkIndexed(t, index, blks)
+
+	if has, err := index.db.Has(backfillKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("should have finished the backfill")
+	}
+}
+
+func TestHeightIndexBackfillUnaccepted(t *testing.T) {
+	index, blks := newHeightIndexTest(t, 3)
+	blks[0].StatusV = choices.Unknown
+
+	if _, err := index.Backfill(blks[2].ID()); !errors.Is(err, errNotAccepted) {
+		t.Fatalf("should have failed to backfill an unaccepted block, got %v", err)
+	}
+}
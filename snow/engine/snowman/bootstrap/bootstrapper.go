@@ -402,6 +402,31 @@ func (b *Bootstrapper) finish() error {
 	return nil
 }
 
+// Rebootstrap moves this chain, which finished bootstrapping, back into
+// bootstrapping so that it can catch up with the network. [OnFinished] is
+// called again once bootstrapping finishes.
+func (b *Bootstrapper) Rebootstrap() error {
+	lastAcceptedID, err := b.VM.LastAccepted()
+	if err != nil {
+		return fmt.Errorf("couldn't get last accepted ID: %s", err)
+	}
+	lastAccepted, err := b.VM.GetBlock(lastAcceptedID)
+	if err != nil {
+		return fmt.Errorf("couldn't get last accepted block: %s", err)
+	}
+	b.startingHeight = lastAccepted.Height()
+	b.tipHeight = b.startingHeight
+	b.startingAcceptedFrontier.Clear()
+	b.executedStateTransitions = math.MaxInt32
+
+	b.Ctx.Unbootstrapped()
+	if err := b.VM.Bootstrapping(); err != nil {
+		return fmt.Errorf("failed to notify VM that bootstrapping has started: %w",
+			err)
+	}
+	return b.Bootstrapper.Rebootstrap()
+}
+
 // Connected implements the Engine interface.
 func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
 	if connector, ok := b.VM.(validators.Connector); ok {
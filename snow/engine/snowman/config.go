@@ -4,8 +4,11 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 )
 
@@ -15,4 +18,13 @@ type Config struct {
 
 	Params    snowball.Parameters
 	Consensus snowman.Consensus
+
+	// If positive, bootstrapping is restarted once no block has been accepted
+	// for [StaleThreshold] while beacons holding at least [Alpha] weight
+	// gossip higher accepted blocks
+	StaleThreshold time.Duration
+
+	// If non-nil, the index of the accepted blocks by height. Ignored if the
+	// VM indexes its accepted blocks by height itself.
+	HeightIndex *block.HeightIndex
 }
@@ -12,6 +12,7 @@ import (
 type metrics struct {
 	numRequests, numBlocked prometheus.Gauge
 	getAncestorsBlks        prometheus.Histogram
+	numRebootstraps         prometheus.Counter
 }
 
 // Initialize the metrics
@@ -42,12 +43,18 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 			2000,
 		},
 	})
+	m.numRebootstraps = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rebootstraps",
+		Help:      "Number of times bootstrapping was restarted because the chain fell behind the network",
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(m.numRequests),
 		registerer.Register(m.numBlocked),
 		registerer.Register(m.getAncestorsBlks),
+		registerer.Register(m.numRebootstraps),
 	)
 	return errs.Err
 }
@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -27,7 +29,15 @@ const (
 	maxContainersLen = int(4 * network.DefaultMaxMessageSize / 5)
 )
 
-var _ Engine = &Transitive{}
+var (
+	errNoHeightIndex = errors.New("accepted blocks aren't indexed by height")
+
+	_ Engine                  = &Transitive{}
+	_ common.PollReporter     = &Transitive{}
+	_ common.FrontierReporter = &Transitive{}
+	_ common.ParameterUpdater = &Transitive{}
+	_ block.HeightIndexer     = &Transitive{}
+)
 
 // Transitive implements the Engine interface by attempting to fetch all
 // transitive dependencies.
@@ -41,6 +51,9 @@ type Transitive struct {
 	// track outstanding preference requests
 	polls poll.Set
 
+	// samples the validators queried by repolls
+	repollSampler *common.RepollSampler
+
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
 
@@ -57,6 +70,16 @@ type Transitive struct {
 
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
+
+	// Restarts bootstrapping once no block has been accepted for a while
+	// although a quorum of beacons gossiped higher accepted blocks
+	stale *common.StaleDetector
+
+	// The last accepted block the last time it was checked
+	lastAcceptedID ids.ID
+
+	// If non-nil, the index of the accepted blocks by height
+	heightIndex *block.HeightIndex
 }
 
 // Initialize implements the Engine interface
@@ -65,6 +88,8 @@ func (t *Transitive) Initialize(config Config) error {
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.stale = common.NewStaleDetector(config.Beacons, config.Alpha, config.StaleThreshold)
+	t.heightIndex = config.HeightIndex
 
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t.polls = poll.NewSet(factory,
@@ -77,6 +102,18 @@ func (t *Transitive) Initialize(config Config) error {
 		return err
 	}
 
+	var err error
+	t.repollSampler, err = common.NewRepollSampler(
+		config.Validators,
+		config.Latencies,
+		config.RepollLatencyBias,
+		config.Params.Namespace,
+		config.Params.Metrics,
+	)
+	if err != nil {
+		return err
+	}
+
 	return t.Bootstrapper.Initialize(
 		config.Config,
 		t.finishBootstrapping,
@@ -103,6 +140,9 @@ func (t *Transitive) finishBootstrapping() error {
 		return err
 	}
 
+	t.lastAcceptedID = lastAcceptedID
+	t.stale.Accepted(lastAccepted.Height(), t.Ctx.Clock.Time())
+
 	// to maintain the invariant that oracle blocks are issued in the correct
 	// preferences, we need to handle the case that we are bootstrapping into an oracle block
 	switch blk := lastAccepted.(type) {
@@ -140,15 +180,63 @@ func (t *Transitive) Gossip() error {
 		t.Ctx.Log.Warn("dropping gossip request as %s couldn't be loaded due to %s", blkID, err)
 		return nil
 	}
+	if t.isStale(blk) {
+		return t.rebootstrap()
+	}
 	t.Ctx.Log.Verbo("gossiping %s as accepted to the network", blkID)
 	t.Sender.Gossip(blkID, blk.Bytes())
 	return nil
 }
 
+// isStale returns true if [lastAccepted], the last accepted block, was accepted
+// too long ago while a quorum of beacons has since gossiped higher blocks.
+func (t *Transitive) isStale(lastAccepted snowman.Block) bool {
+	now := t.Ctx.Clock.Time()
+	if t.observeLastAccepted(lastAccepted, now) {
+		return false
+	}
+	return t.stale.Stale(now)
+}
+
+// observeLastAccepted records that [lastAccepted] is the last accepted block
+// at [now]. Returns true if it wasn't the last accepted block the last time it
+// was observed.
+func (t *Transitive) observeLastAccepted(lastAccepted snowman.Block, now time.Time) bool {
+	blkID := lastAccepted.ID()
+	if blkID == t.lastAcceptedID {
+		return false
+	}
+	t.lastAcceptedID = blkID
+	t.stale.Accepted(lastAccepted.Height(), now)
+	return true
+}
+
+// rebootstrap moves the chain back into bootstrapping. Consensus is
+// re-initialized once bootstrapping finishes, so everything waiting on the
+// current consensus instance is dropped.
+func (t *Transitive) rebootstrap() error {
+	_, lastAcceptedTime := t.stale.LastAccepted()
+	t.Ctx.Log.Warn("no block has been accepted since %s while beacons report blocks higher than %s. Restarting bootstrapping",
+		lastAcceptedTime, t.lastAcceptedID)
+	t.numRebootstraps.Inc()
+
+	t.blkReqs = common.Requests{}
+	t.pending.Clear()
+	t.blocked = events.Blocker{}
+	t.numRequests.Set(0)
+	t.numBlocked.Set(0)
+	return t.Bootstrapper.Rebootstrap()
+}
+
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
-	return t.VM.Shutdown()
+	errs := wrappers.Errs{}
+	errs.Add(
+		t.VM.Shutdown(),
+		t.Blocked.Close(),
+	)
+	return errs.Err
 }
 
 // Get implements the Engine interface
@@ -225,6 +313,11 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		return t.GetFailed(vdr, requestID)
 	}
 
+	// Peers gossip their last accepted block
+	if requestID == constants.GossipMsgRequestID {
+		t.stale.Gossiped(vdr, blk.Height())
+	}
+
 	// issue the block into consensus. If the block has already been issued,
 	// this will be a noop. If this block has missing dependencies, vdr will
 	// receive requests to fill the ancestry. dependencies that have already
@@ -324,6 +417,8 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 	// if the engine hasn't been bootstrapped, we shouldn't be receiving chits
 	if !t.Ctx.IsBootstrapped() {
 		t.Ctx.Log.Debug("dropping Chits(%s, %d) due to bootstrapping", vdr, requestID)
+		// Drop the poll if it was issued before bootstrapping was restarted
+		t.polls.Drop(requestID, vdr)
 		return nil
 	}
 
@@ -366,6 +461,8 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 	// If the engine hasn't been bootstrapped, we didn't issue a query
 	if !t.Ctx.IsBootstrapped() {
 		t.Ctx.Log.Warn("dropping QueryFailed(%s, %d) due to bootstrapping", vdr, requestID)
+		// Drop the poll if it was issued before bootstrapping was restarted
+		t.polls.Drop(requestID, vdr)
 		return nil
 	}
 
@@ -448,7 +545,7 @@ func (t *Transitive) repoll() {
 	prefID := t.Consensus.Preference()
 
 	for i := t.polls.Len(); i < t.Params.ConcurrentRepolls; i++ {
-		t.pullQuery(prefID)
+		t.pullQuery(prefID, i > 0)
 	}
 }
 
@@ -582,18 +679,19 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, blkID ids.ID) {
 	t.numRequests.Set(float64(t.blkReqs.Len()))
 }
 
-// send a pull query for this block ID
-func (t *Transitive) pullQuery(blkID ids.ID) {
+// send a pull query for this block ID. [outstanding] is true if another poll
+// is outstanding.
+func (t *Transitive) pullQuery(blkID ids.ID, outstanding bool) {
 	t.Ctx.Log.Verbo("about to sample from: %s", t.Validators)
 	// The validators we will query
-	vdrs, err := t.Validators.Sample(t.Params.K)
+	vdrs, err := t.repollSampler.Sample(t.Params.K, outstanding)
 	vdrBag := ids.ShortBag{}
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
 	}
 
 	t.RequestID++
-	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+	if err == nil && t.polls.Add(t.RequestID, blkID, vdrBag) {
 		vdrList := vdrBag.List()
 		vdrSet := ids.NewShortSet(len(vdrList))
 		vdrSet.Add(vdrList...)
@@ -613,7 +711,7 @@ func (t *Transitive) pushQuery(blk snowman.Block) {
 	}
 
 	t.RequestID++
-	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+	if err == nil && t.polls.Add(t.RequestID, blk.ID(), vdrBag) {
 		vdrList := vdrBag.List()
 		vdrSet := ids.NewShortSet(len(vdrList))
 		vdrSet.Add(vdrList...)
@@ -727,27 +825,105 @@ func (t *Transitive) IsBootstrapped() bool {
 	return t.Ctx.IsBootstrapped()
 }
 
+// OutstandingPolls implements the common.PollReporter interface
+func (t *Transitive) OutstandingPolls() []common.PollInfo {
+	polls := t.polls.Polls()
+	infos := make([]common.PollInfo, len(polls))
+	for i, poll := range polls {
+		infos[i] = common.PollInfo(poll)
+	}
+	return infos
+}
+
+// Frontier implements the common.FrontierReporter interface
+func (t *Transitive) Frontier() ([]ids.ID, uint64, error) {
+	lastAcceptedID, err := t.VM.LastAccepted()
+	if err != nil {
+		return nil, 0, err
+	}
+	lastAccepted, err := t.VM.GetBlock(lastAcceptedID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return []ids.ID{lastAcceptedID}, lastAccepted.Height(), nil
+}
+
+// SamplingParameters implements the common.ParameterUpdater interface
+func (t *Transitive) SamplingParameters() common.SamplingParameters {
+	return common.SamplingParameters{
+		K:                 t.Params.K,
+		Alpha:             t.Params.Alpha,
+		ConcurrentRepolls: t.Params.ConcurrentRepolls,
+	}
+}
+
+// UpdateSamplingParameters implements the common.ParameterUpdater interface
+func (t *Transitive) UpdateSamplingParameters(update common.SamplingParameters) error {
+	params := t.Params
+	params.K = update.K
+	params.Alpha = update.Alpha
+	params.ConcurrentRepolls = update.ConcurrentRepolls
+	if err := t.Params.VerifyUpdate(params); err != nil {
+		return err
+	}
+
+	// Consensus is initialized with [t.Params] once bootstrapping finishes
+	if t.Ctx.IsBootstrapped() {
+		if err := t.Consensus.UpdateParameters(params); err != nil {
+			return err
+		}
+	}
+	t.Params = params
+	t.polls.SetFactory(poll.NewEarlyTermNoTraversalFactory(params.Alpha))
+	t.Ctx.Log.Info("updated sampling parameters to k = %d, alpha = %d, concurrentRepolls = %d",
+		params.K, params.Alpha, params.ConcurrentRepolls)
+	return nil
+}
+
+// GetBlockIDAtHeight implements the block.HeightIndexer interface
+func (t *Transitive) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	if vm, ok := t.VM.(block.HeightIndexedChainVM); ok {
+		return vm.GetBlockIDAtHeight(height)
+	}
+	if t.heightIndex == nil {
+		return ids.ID{}, errNoHeightIndex
+	}
+	return t.heightIndex.GetBlockIDAtHeight(height)
+}
+
 // Health implements the common.Engine interface
 func (t *Transitive) HealthCheck() (interface{}, error) {
+	engineHealth := common.EngineHealth{
+		Bootstrapped:     t.Ctx.IsBootstrapped(),
+		OutstandingPolls: t.polls.Len(),
+	}
 	var (
 		consensusIntf interface{} = struct{}{}
 		consensusErr  error
 	)
-	if t.Ctx.IsBootstrapped() {
+	if engineHealth.Bootstrapped {
+		now := t.Ctx.Clock.Time()
+		if lastAccepted, err := t.lastAccepted(); err == nil {
+			t.observeLastAccepted(lastAccepted, now)
+		} else {
+			t.Ctx.Log.Debug("couldn't load the last accepted block during the health check due to %s", err)
+		}
+		engineHealth.Processing = t.Consensus.NumProcessing()
+		_, lastAcceptedTime := t.stale.LastAccepted()
+		engineHealth.LastAcceptedAge = now.Sub(lastAcceptedTime).String()
 		consensusIntf, consensusErr = t.Consensus.HealthCheck()
 	}
 	vmIntf, vmErr := t.VM.HealthCheck()
-	intf := map[string]interface{}{
-		"consensus": consensusIntf,
-		"vm":        vmIntf,
-	}
-	if consensusErr == nil {
-		return intf, vmErr
-	}
-	if vmErr == nil {
-		return intf, consensusErr
+	return common.ChainHealth(engineHealth, consensusIntf, consensusErr, vmIntf, vmErr)
+}
+
+// lastAccepted returns the last accepted block of the VM
+func (t *Transitive) lastAccepted() (snowman.Block, error) {
+	lastAcceptedID, err := t.VM.LastAccepted()
+	if err != nil {
+		return nil, err
 	}
-	return intf, fmt.Errorf("vm: %s ; consensus: %s", vmErr, consensusErr)
+	return t.VM.GetBlock(lastAcceptedID)
 }
 
 // GetBlock implements the snowman.Engine interface
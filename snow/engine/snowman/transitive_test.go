@@ -7,9 +7,11 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
@@ -17,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
@@ -2472,3 +2475,299 @@ func TestEngineNonPreferredAmplification(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestEngineRebootstrapsWhenStale(t *testing.T) {
+	config := DefaultConfig()
+	config.StaleThreshold = time.Minute
+	config.Alpha = 2
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	peer := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr0, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Beacons are added after the initial bootstrapping so that it finishes
+	// immediately
+	beacons := validators.NewSet()
+	config.Beacons = beacons
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGossip = false
+	sender.CantPushQuery = false
+	sender.CantPullQuery = false
+
+	vm := &block.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+	vm.CantSetPreference = false
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	// Records the calls to the VM's Bootstrapping and Bootstrapped
+	var vmCalls []string
+	vm.BootstrappingF = func() error {
+		vmCalls = append(vmCalls, "bootstrapping")
+		return nil
+	}
+	vm.BootstrappedF = func() error {
+		vmCalls = append(vmCalls, "bootstrapped")
+		return nil
+	}
+
+	gBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     Genesis,
+		StatusV: choices.Accepted,
+	}}
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.LastAcceptedF = func() (ids.ID, error) { return gBlk.ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk.ID():
+			return blk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		if bytes.Equal(b, blk.Bytes()) {
+			return blk, nil
+		}
+		return nil, errUnknownBytes
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Should have finished bootstrapping")
+	}
+
+	if err := beacons.AddWeight(vdr0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := beacons.AddWeight(vdr1, 1); err != nil {
+		t.Fatal(err)
+	}
+	te.SampleK = 2
+
+	// No peer reported a higher block, so the chain isn't stale
+	now := te.Ctx.Clock.Time().Add(time.Hour)
+	te.Ctx.Clock.Set(now)
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Shouldn't have restarted bootstrapping")
+	}
+
+	// Peers that aren't beacons can't make the chain stale
+	if err := te.Put(peer, constants.GossipMsgRequestID, blk.ID(), blk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if te.Consensus.NumProcessing() != 1 {
+		t.Fatalf("Should have issued the gossiped block")
+	}
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Shouldn't have restarted bootstrapping due to a peer that isn't a beacon")
+	}
+
+	// A single beacon holds less than alpha weight
+	if err := te.Put(vdr0, constants.GossipMsgRequestID, blk.ID(), blk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Shouldn't have restarted bootstrapping due to less than alpha weight")
+	}
+
+	if err := te.Put(vdr1, constants.GossipMsgRequestID, blk.ID(), blk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	reqID := new(uint32)
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, requestID uint32) {
+		if !vdrs.Contains(vdr0) || !vdrs.Contains(vdr1) {
+			t.Fatalf("Should have requested the accepted frontier from the beacons")
+		}
+		*reqID = requestID
+	}
+	sender.GetAcceptedF = func(vdrs ids.ShortSet, requestID uint32, containerIDs []ids.ID) {
+		*reqID = requestID
+	}
+
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if te.Ctx.IsBootstrapped() {
+		t.Fatalf("Should have restarted bootstrapping")
+	}
+	if vmCalls[len(vmCalls)-1] != "bootstrapping" {
+		t.Fatalf("Should have moved the VM back into bootstrapping, VM calls: %v", vmCalls)
+	}
+
+	for _, beacon := range []ids.ShortID{vdr0, vdr1} {
+		if err := te.AcceptedFrontier(beacon, *reqID, []ids.ID{gBlk.ID()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, beacon := range []ids.ShortID{vdr0, vdr1} {
+		if err := te.Accepted(beacon, *reqID, []ids.ID{gBlk.ID()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !te.Ctx.IsBootstrapped() {
+		t.Fatalf("Should have finished bootstrapping again")
+	}
+	if vmCalls[len(vmCalls)-1] != "bootstrapped" {
+		t.Fatalf("Should have told the VM that bootstrapping finished again, VM calls: %v", vmCalls)
+	}
+	if te.Consensus.NumProcessing() != 0 {
+		t.Fatalf("Consensus should have been reset")
+	}
+}
+
+func TestEngineGetBlockIDAtHeight(t *testing.T) {
+	_, _, _, vm, te, gBlk := setup(t)
+
+	if _, err := te.GetBlockIDAtHeight(0); err != errNoHeightIndex {
+		t.Fatalf("should have failed without a height index, got %v", err)
+	}
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk.ID():
+			return blk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	te.heightIndex = block.NewHeightIndex(memdb.New(), vm)
+	if _, err := te.heightIndex.Backfill(blk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []snowman.Block{gBlk, blk} {
+		blkID, err := te.GetBlockIDAtHeight(expected.Height())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blkID != expected.ID() {
+			t.Fatalf("should have returned %s at height %d, returned %s", expected.ID(), expected.Height(), blkID)
+		}
+	}
+}
+
+func TestEngineHealthCheck(t *testing.T) {
+	_, _, _, vm, te, gBlk := setup(t)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+	}
+	lastAccepted := snowman.Block(gBlk)
+	vm.LastAcceptedF = func() (ids.ID, error) { return lastAccepted.ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID != lastAccepted.ID() {
+			return nil, errUnknownBlock
+		}
+		return lastAccepted, nil
+	}
+	vm.HealthCheckF = func() (interface{}, error) { return nil, nil }
+
+	checkHealth := func(expectedAge time.Duration) {
+		intf, err := te.HealthCheck()
+		if err != nil {
+			t.Fatal(err)
+		}
+		engineHealth, ok := intf.(map[string]interface{})["engine"].(common.EngineHealth)
+		switch {
+		case !ok:
+			t.Fatalf("should have reported the health of the engine")
+		case !engineHealth.Bootstrapped:
+			t.Fatalf("should have reported that the chain is bootstrapped")
+		case engineHealth.Processing != 0:
+			t.Fatalf("shouldn't have reported processing blocks")
+		case engineHealth.LastAcceptedAge != expectedAge.String():
+			t.Fatalf("should have reported a last accepted age of %s, reported %s", expectedAge, engineHealth.LastAcceptedAge)
+		}
+	}
+
+	_, lastAcceptedTime := te.stale.LastAccepted()
+	now := lastAcceptedTime.Add(time.Minute)
+	te.Ctx.Clock.Set(now)
+	checkHealth(time.Minute)
+
+	// The age is reset once a new block is accepted
+	lastAccepted = blk
+	checkHealth(0)
+
+	te.Ctx.Clock.Set(now.Add(time.Second))
+	checkHealth(time.Second)
+}
+
+func TestEngineUpdateSamplingParameters(t *testing.T) {
+	_, _, _, _, te, _ := setup(t)
+
+	update := te.SamplingParameters()
+	update.ConcurrentRepolls = 2
+	if err := te.UpdateSamplingParameters(update); err != nil {
+		t.Fatal(err)
+	}
+	if te.Params.ConcurrentRepolls != 2 {
+		t.Fatalf("should have updated the engine's parameters")
+	}
+	if te.Consensus.Parameters().ConcurrentRepolls != 2 {
+		t.Fatalf("should have updated the consensus parameters")
+	}
+
+	invalid := update
+	invalid.Alpha = 0
+	if err := te.UpdateSamplingParameters(invalid); err == nil {
+		t.Fatalf("shouldn't have updated to invalid parameters")
+	}
+	if te.SamplingParameters() != update {
+		t.Fatalf("shouldn't have changed the parameters after a failed update")
+	}
+}
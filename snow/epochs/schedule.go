@@ -0,0 +1,52 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package epochs
+
+import (
+	"math"
+	"time"
+)
+
+// Schedule describes when epochs transition. Epoch 0 lasts until
+// [FirstTransition], and every later epoch lasts [Duration]. If [Duration]
+// isn't positive, epoch 1 never ends.
+type Schedule struct {
+	FirstTransition time.Time
+	Duration        time.Duration
+}
+
+// Epoch returns the epoch at time [t]
+func (s Schedule) Epoch(t time.Time) uint32 {
+	if t.Before(s.FirstTransition) {
+		return 0
+	}
+	if s.Duration <= 0 {
+		return 1
+	}
+	epochsSinceFirstTransition := uint64(t.Sub(s.FirstTransition) / s.Duration)
+	if epochsSinceFirstTransition >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(epochsSinceFirstTransition) + 1
+}
+
+// TimeOf returns the time [epoch] starts. Epoch 0 has always started, so the
+// zero time is returned for it. If [epoch] never starts, ok is false.
+func (s Schedule) TimeOf(epoch uint32) (t time.Time, ok bool) {
+	switch {
+	case epoch == 0:
+		return time.Time{}, true
+	case epoch == 1:
+		return s.FirstTransition, true
+	case s.Duration <= 0:
+		return time.Time{}, false
+	}
+
+	// Avoid overflowing the duration of a far away epoch
+	maxEpochs := uint64(math.MaxInt64 / s.Duration)
+	if uint64(epoch-1) > maxEpochs {
+		return time.Time{}, false
+	}
+	return s.FirstTransition.Add(time.Duration(epoch-1) * s.Duration), true
+}
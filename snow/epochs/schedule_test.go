@@ -0,0 +1,72 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package epochs
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestScheduleEpoch(t *testing.T) {
+	first := time.Unix(1000, 0)
+	s := Schedule{
+		FirstTransition: first,
+		Duration:        time.Minute,
+	}
+
+	tests := []struct {
+		time  time.Time
+		epoch uint32
+	}{
+		{time: time.Unix(0, 0), epoch: 0},
+		{time: first.Add(-time.Second), epoch: 0},
+		{time: first, epoch: 1},
+		{time: first.Add(time.Minute - 1), epoch: 1},
+		{time: first.Add(time.Minute), epoch: 2},
+		{time: first.Add(10 * time.Minute), epoch: 11},
+		{time: first.Add(math.MaxInt64), epoch: uint32(math.MaxInt64/time.Minute) + 1},
+	}
+	for _, test := range tests {
+		if epoch := s.Epoch(test.time); epoch != test.epoch {
+			t.Fatalf("at %s, should be in epoch %d, is in %d", test.time, test.epoch, epoch)
+		}
+	}
+
+	s.Duration = time.Nanosecond
+	if epoch := s.Epoch(first.Add(math.MaxInt64)); epoch != math.MaxUint32 {
+		t.Fatalf("epochs past the max should be capped, is in %d", epoch)
+	}
+
+	s.Duration = 0
+	if epoch := s.Epoch(first.Add(time.Hour)); epoch != 1 {
+		t.Fatalf("without a duration, epoch 1 shouldn't end, is in %d", epoch)
+	}
+}
+
+func TestScheduleTimeOf(t *testing.T) {
+	first := time.Unix(1000, 0)
+	s := Schedule{
+		FirstTransition: first,
+		Duration:        time.Minute,
+	}
+
+	for _, epoch := range []uint32{1, 2, 11} {
+		start, ok := s.TimeOf(epoch)
+		if !ok {
+			t.Fatalf("epoch %d should start", epoch)
+		}
+		if s.Epoch(start) != epoch || s.Epoch(start.Add(-1)) != epoch-1 {
+			t.Fatalf("epoch %d shouldn't start at %s", epoch, start)
+		}
+	}
+	if start, ok := s.TimeOf(0); !ok || !start.IsZero() {
+		t.Fatalf("epoch 0 should have always started")
+	}
+
+	s.Duration = 0
+	if _, ok := s.TimeOf(2); ok {
+		t.Fatalf("without a duration, epoch 2 shouldn't start")
+	}
+}
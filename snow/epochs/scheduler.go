@@ -0,0 +1,125 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package epochs
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// Scheduler reports the current epoch of a schedule and notifies its
+// subscribers when the epoch rolls over, so that epoch dependent behavior
+// doesn't need to keep its own timers.
+type Scheduler struct {
+	schedule Schedule
+	clock    *timer.Clock
+
+	lock sync.Mutex
+	// Last epoch the subscribers were notified of
+	epoch       uint32
+	subscribers []func(epoch uint32)
+	// Fires at the next epoch transition. Nil if there is no subscriber, no
+	// next transition, or the scheduler was shutdown.
+	timer *time.Timer
+	// True once the first subscriber started the timer
+	started bool
+	closed  bool
+}
+
+// NewScheduler returns a scheduler of [schedule] that tells time with [clock]
+func NewScheduler(schedule Schedule, clock *timer.Clock) *Scheduler {
+	return &Scheduler{
+		schedule: schedule,
+		clock:    clock,
+		epoch:    schedule.Epoch(clock.Time()),
+	}
+}
+
+// Schedule returns the schedule of epoch transitions
+func (s *Scheduler) Schedule() Schedule { return s.schedule }
+
+// Current returns the current epoch
+func (s *Scheduler) Current() uint32 { return s.schedule.Epoch(s.clock.Time()) }
+
+// TimeOf returns the time [epoch] starts. If [epoch] never starts, ok is
+// false.
+func (s *Scheduler) TimeOf(epoch uint32) (time.Time, bool) { return s.schedule.TimeOf(epoch) }
+
+// Subscribe registers [f] to be called with the new epoch each time the epoch
+// rolls over. Subscribers are called in order, on a goroutine of the
+// scheduler, and must not block.
+func (s *Scheduler) Subscribe(f func(epoch uint32)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.subscribers = append(s.subscribers, f)
+	if !s.started {
+		s.started = true
+		s.scheduleRollover()
+	}
+}
+
+// Shutdown stops notifying the subscribers
+func (s *Scheduler) Shutdown() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// rollover notifies the subscribers if the epoch rolled over, and then waits
+// for the next transition
+func (s *Scheduler) rollover() {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	epoch := s.schedule.Epoch(s.clock.Time())
+	rolledOver := epoch > s.epoch
+	if rolledOver {
+		s.epoch = epoch
+	}
+	subscribers := s.subscribers
+	s.lock.Unlock()
+
+	// The timer isn't reset until the subscribers return, so that they are
+	// notified of each epoch in order
+	if rolledOver {
+		for _, f := range subscribers {
+			f(epoch)
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.closed {
+		s.scheduleRollover()
+	}
+}
+
+// scheduleRollover starts the timer of the next epoch transition. Assumes
+// [s.lock] is held.
+func (s *Scheduler) scheduleRollover() {
+	s.timer = nil
+	if s.epoch == math.MaxUint32 {
+		return
+	}
+	next, ok := s.schedule.TimeOf(s.epoch + 1)
+	if !ok {
+		return
+	}
+	s.timer = time.AfterFunc(next.Sub(s.clock.Time()), s.rollover)
+}
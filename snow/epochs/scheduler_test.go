@@ -0,0 +1,58 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package epochs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+func TestSchedulerRollover(t *testing.T) {
+	first := time.Unix(1000, 0)
+	clock := &timer.Clock{}
+	// The timers of the scheduler don't fire during the test, because the
+	// transitions are an hour away from the faked time
+	clock.Set(first.Add(-time.Hour))
+
+	s := NewScheduler(Schedule{
+		FirstTransition: first,
+		Duration:        time.Hour,
+	}, clock)
+	defer s.Shutdown()
+
+	if epoch := s.Current(); epoch != 0 {
+		t.Fatalf("should be in epoch 0, is in %d", epoch)
+	}
+
+	notified := []uint32(nil)
+	s.Subscribe(func(epoch uint32) { notified = append(notified, epoch) })
+
+	// The epoch didn't change, so the subscribers aren't notified
+	s.rollover()
+	if len(notified) != 0 {
+		t.Fatalf("shouldn't have notified the subscribers, notified %v", notified)
+	}
+
+	clock.Set(first)
+	s.rollover()
+	clock.Set(first.Add(2 * time.Hour))
+	s.rollover()
+	switch {
+	case len(notified) != 2:
+		t.Fatalf("should have notified the subscribers twice, notified %v", notified)
+	case notified[0] != 1 || notified[1] != 3:
+		t.Fatalf("should have notified epochs 1 and 3, notified %v", notified)
+	case s.Current() != 3:
+		t.Fatalf("should be in epoch 3, is in %d", s.Current())
+	}
+
+	s.Shutdown()
+	clock.Set(first.Add(5 * time.Hour))
+	s.rollover()
+	if len(notified) != 2 {
+		t.Fatalf("shouldn't notify the subscribers after shutdown, notified %v", notified)
+	}
+}
@@ -0,0 +1,132 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// Handling time a chain may use up front, as a multiple of its share of a
+// second, before its handler is throttled. This lets chains absorb short
+// bursts of expensive messages.
+const cpuBudgetBurst = time.Second
+
+var (
+	errInvalidRequestRate  = errors.New("requests per second must be a non-negative number")
+	errInvalidRequestBurst = errors.New("request burst must be non-negative")
+	errInvalidHandlingCPUs = errors.New("handling CPUs must be a non-negative number")
+	errInvalidQueueWeight  = errors.New("queue weight must be a non-negative number")
+)
+
+// Budget bounds the load a chain's handler puts on the node, so that a
+// misbehaving chain can't starve the other chains on the node. Unlike
+// vms.ResourceLimits, budgets apply to every chain, whether its VM runs as a
+// plugin or not. A zero value means that the resource isn't limited.
+type Budget struct {
+	// Max number of requests per second from peers that the chain handles.
+	// Requests past the budget are dropped, and time out on the peer that
+	// sent them. Responses to the chain's own requests are never dropped.
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	// Max number of requests the chain handles at once before
+	// [RequestsPerSecond] applies. Defaults to one second of requests.
+	RequestBurst int `json:"requestBurst"`
+	// Number of CPUs worth of time the handler may spend handling messages.
+	// Once the handler used more than its share, it waits before handling
+	// the next message.
+	HandlingCPUs float64 `json:"handlingCPUs"`
+	// Share of the node-wide bound on pending messages the chain is entitled
+	// to, relative to the other chains. Defaults to 1.
+	QueueWeight float64 `json:"queueWeight"`
+}
+
+// IsZero returns true if nothing is limited
+func (b Budget) IsZero() bool { return b == Budget{} }
+
+// Verify that the budget is well formed
+func (b Budget) Verify() error {
+	switch {
+	case b.RequestsPerSecond < 0 || math.IsNaN(b.RequestsPerSecond) || math.IsInf(b.RequestsPerSecond, 0):
+		return errInvalidRequestRate
+	case b.RequestBurst < 0:
+		return errInvalidRequestBurst
+	case b.HandlingCPUs < 0 || math.IsNaN(b.HandlingCPUs) || math.IsInf(b.HandlingCPUs, 0):
+		return errInvalidHandlingCPUs
+	case b.QueueWeight < 0 || math.IsNaN(b.QueueWeight) || math.IsInf(b.QueueWeight, 0):
+		return errInvalidQueueWeight
+	default:
+		return nil
+	}
+}
+
+func (b Budget) newRequestLimiter() *rate.Limiter {
+	if b.RequestsPerSecond == 0 {
+		return nil
+	}
+	burst := b.RequestBurst
+	if burst == 0 {
+		burst = int(math.Ceil(b.RequestsPerSecond))
+	}
+	return rate.NewLimiter(rate.Limit(b.RequestsPerSecond), burst)
+}
+
+// isRequest returns true if [msg] is a request, or a gossiped container, sent
+// by a peer. These are the messages that may be dropped when a chain is over
+// its budget, as the chain isn't waiting for them.
+func isRequest(msg message) bool {
+	switch msg.messageType {
+	case constants.GetAcceptedFrontierMsg, constants.GetAcceptedMsg,
+		constants.GetAncestorsMsg, constants.GetStateSummaryMsg,
+		constants.GetMsg, constants.PushQueryMsg, constants.PullQueryMsg:
+		return true
+	case constants.PutMsg:
+		return msg.requestID == constants.GossipMsgRequestID
+	default:
+		return false
+	}
+}
+
+// cpuBudget tracks the time a handler spent handling messages against the
+// time it's allowed to spend. cpuBudget isn't safe for concurrent access.
+type cpuBudget struct {
+	cpus float64
+	// Handling time not paid back yet
+	debt time.Duration
+	// Last time [debt] was updated
+	last time.Time
+}
+
+// spend records that [duration] was spent handling a message at [now]
+func (b *cpuBudget) spend(now time.Time, duration time.Duration) {
+	b.repay(now)
+	b.debt += duration
+}
+
+// wait returns how long the handler must wait at [now] before it's within its
+// budget again
+func (b *cpuBudget) wait(now time.Time) time.Duration {
+	b.repay(now)
+	burst := time.Duration(b.cpus * float64(cpuBudgetBurst))
+	if b.debt <= burst {
+		return 0
+	}
+	return time.Duration(float64(b.debt-burst) / b.cpus)
+}
+
+func (b *cpuBudget) repay(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 && !b.last.IsZero() {
+		b.debt -= time.Duration(b.cpus * float64(elapsed))
+		if b.debt < 0 {
+			b.debt = 0
+		}
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+}
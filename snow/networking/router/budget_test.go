@@ -0,0 +1,42 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetVerify(t *testing.T) {
+	assert.NoError(t, Budget{}.Verify())
+	assert.NoError(t, Budget{RequestsPerSecond: 10, RequestBurst: 5, HandlingCPUs: 0.5}.Verify())
+	assert.ErrorIs(t, Budget{RequestsPerSecond: -1}.Verify(), errInvalidRequestRate)
+	assert.ErrorIs(t, Budget{RequestsPerSecond: math.Inf(1)}.Verify(), errInvalidRequestRate)
+	assert.ErrorIs(t, Budget{RequestBurst: -1}.Verify(), errInvalidRequestBurst)
+	assert.ErrorIs(t, Budget{HandlingCPUs: math.NaN()}.Verify(), errInvalidHandlingCPUs)
+	assert.ErrorIs(t, Budget{QueueWeight: -1}.Verify(), errInvalidQueueWeight)
+}
+
+func TestCPUBudget(t *testing.T) {
+	b := cpuBudget{cpus: 0.5}
+	now := time.Unix(1607626800, 0)
+
+	// Half a second of handling time is within the burst
+	b.spend(now, 500*time.Millisecond)
+	assert.Zero(t, b.wait(now))
+
+	// Another 100ms puts the handler 100ms over, which takes 200ms to repay
+	// at half a CPU
+	b.spend(now, 100*time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, b.wait(now))
+	assert.Equal(t, 100*time.Millisecond, b.wait(now.Add(100*time.Millisecond)))
+	assert.Zero(t, b.wait(now.Add(200*time.Millisecond)))
+
+	// The debt is fully repaid, and never goes negative
+	assert.Zero(t, b.wait(now.Add(time.Hour)))
+	assert.Zero(t, b.debt)
+}
@@ -40,6 +40,14 @@ type requestEntry struct {
 	msgType constants.MsgType
 }
 
+// inflightGet identifies a Get or GetAncestors request that a peer sent us
+type inflightGet struct {
+	validatorID ids.ShortID
+	chainID     ids.ID
+	containerID ids.ID
+	msgType     constants.MsgType
+}
+
 // ChainRouter routes incoming messages from the validator network
 // to the consensus engines that the messages are intended for.
 // Note that consensus engines are uniquely identified by the ID of the chain
@@ -77,6 +85,14 @@ type ChainRouter struct {
 	// Should only be accessed in that method.
 	// [lock] should be held when [requestIDBytes] is accessed.
 	requestIDBytes []byte
+
+	// Get and GetAncestors requests from peers that are being handled. An
+	// identical request that arrives while one is being handled is dropped.
+	inflightGetsLock sync.Mutex
+	inflightGets     map[inflightGet]struct{}
+
+	// Bounds the messages pending across all chains. May be nil.
+	pending *pendingMsgs
 }
 
 // Initialize the router.
@@ -109,6 +125,7 @@ func (cr *ChainRouter) Initialize(
 	cr.criticalChains = criticalChains
 	cr.onFatal = onFatal
 	cr.timedRequests = linkedhashmap.New()
+	cr.inflightGets = make(map[inflightGet]struct{})
 	cr.peers.Add(nodeID)
 	// Set up meter to count dropped messages
 	cr.dropRateCalculator = math.NewAverager(0, cr.healthConfig.MaxDropRateHalflife, cr.clock.Time())
@@ -127,6 +144,20 @@ func (cr *ChainRouter) Initialize(
 	return nil
 }
 
+// SetMaxPendingMsgs bounds the number of messages waiting to be handled across
+// all chains added after this call. If [max] is 0, the number of messages isn't
+// bounded. Must be called after [Initialize].
+func (cr *ChainRouter) SetMaxPendingMsgs(max int) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	if max > 0 {
+		cr.pending = newPendingMsgs(max, cr.metrics.pendingMsgs)
+	} else {
+		cr.pending = nil
+	}
+}
+
 // Remove a request from [cr.requests]
 // Assumes [cr.lock] is held
 func (cr *ChainRouter) removeRequest(id ids.ID) {
@@ -168,6 +199,8 @@ func (cr *ChainRouter) RegisterRequest(
 		timeoutHandler = func() { cr.GetFailed(validatorID, chainID, requestID) }
 	case constants.GetAncestorsMsg:
 		timeoutHandler = func() { cr.GetAncestorsFailed(validatorID, chainID, requestID) }
+	case constants.GetStateSummaryMsg:
+		timeoutHandler = func() { cr.GetStateSummaryFailed(validatorID, chainID, requestID) }
 	case constants.GetAcceptedMsg:
 		timeoutHandler = func() { cr.GetAcceptedFailed(validatorID, chainID, requestID) }
 	case constants.GetAcceptedFrontierMsg:
@@ -218,8 +251,11 @@ func (cr *ChainRouter) AddChain(chain *Handler) {
 
 	chainID := chain.Context().ChainID
 	cr.log.Debug("registering chain %s with chain router", chainID)
-	chain.onCloseF = func() { cr.removeChain(chainID) }
+	chain.onCloseF = func() { cr.RemoveChain(chainID) }
 	cr.chains[chainID] = chain
+	if cr.pending != nil {
+		chain.setPendingMsgs(cr.pending)
+	}
 
 	for validatorID := range cr.peers {
 		// If this validator is benched on any chain, treat them as disconnected on all chains
@@ -229,9 +265,25 @@ func (cr *ChainRouter) AddChain(chain *Handler) {
 	}
 }
 
+// HandlerStats implements the Router interface
+func (cr *ChainRouter) HandlerStats() map[ids.ID]HandlerStats {
+	cr.lock.Lock()
+	chains := make(map[ids.ID]*Handler, len(cr.chains))
+	for chainID, chain := range cr.chains {
+		chains[chainID] = chain
+	}
+	cr.lock.Unlock()
+
+	stats := make(map[ids.ID]HandlerStats, len(chains))
+	for chainID, chain := range chains {
+		stats[chainID] = chain.Stats()
+	}
+	return stats
+}
+
 // RemoveChain removes the specified chain so that incoming
-// messages can't be routed to it
-func (cr *ChainRouter) removeChain(chainID ids.ID) {
+// messages can't be routed to it, and shuts it down
+func (cr *ChainRouter) RemoveChain(chainID ids.ID) {
 	cr.lock.Lock()
 	chain, exists := cr.chains[chainID]
 	if !exists {
@@ -252,6 +304,10 @@ func (cr *ChainRouter) removeChain(chainID ids.ID) {
 	}
 	ticker.Stop()
 
+	if cr.pending != nil {
+		cr.pending.deregister(chainID)
+	}
+
 	if cr.onFatal != nil && cr.criticalChains.Contains(chainID) {
 		go cr.onFatal(1)
 	}
@@ -481,6 +537,19 @@ func (cr *ChainRouter) GetAncestors(
 		return
 	}
 
+	get := inflightGet{
+		validatorID: validatorID,
+		chainID:     chainID,
+		containerID: containerID,
+		msgType:     constants.GetAncestorsMsg,
+	}
+	onFinishedHandling, isDuplicate := cr.markGetInflight(get, onFinishedHandling)
+	if isDuplicate {
+		onFinishedHandling()
+		cr.log.Debug("GetAncestors(%s, %s, %d, %s) dropped due to an identical request being handled", validatorID, chainID, requestID, containerID)
+		return
+	}
+
 	// Pass the message to the chain
 	chain.GetAncestors(validatorID, requestID, deadline, containerID, onFinishedHandling)
 }
@@ -559,6 +628,105 @@ func (cr *ChainRouter) GetAncestorsFailed(
 	chain.GetAncestorsFailed(validatorID, requestID)
 }
 
+// GetStateSummary routes an incoming GetStateSummary message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (cr *ChainRouter) GetStateSummary(
+	validatorID ids.ShortID,
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Time,
+	onFinishedHandling func(),
+) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		onFinishedHandling()
+		cr.log.Debug("GetStateSummary(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		return
+	}
+
+	// Pass the message to the chain
+	chain.GetStateSummary(validatorID, requestID, deadline, onFinishedHandling)
+}
+
+// StateSummary routes an incoming StateSummary message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (cr *ChainRouter) StateSummary(
+	validatorID ids.ShortID,
+	chainID ids.ID,
+	requestID uint32,
+	frontier [][]byte,
+	summary []byte,
+	onFinishedHandling func(),
+) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		cr.log.Debug("StateSummary(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID, len(summary))
+		onFinishedHandling()
+		return
+	}
+
+	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
+
+	// Mark that an outstanding request has been fulfilled
+	requestIntf, exists := cr.timedRequests.Get(uniqueRequestID)
+	if !exists {
+		// We didn't request this message. Ignore.
+		onFinishedHandling()
+		return
+	}
+	request := requestIntf.(requestEntry)
+	if request.msgType != constants.GetStateSummaryMsg {
+		// We got back a reply of wrong type. Ignore.
+		onFinishedHandling()
+		return
+	}
+	cr.timedRequests.Delete(uniqueRequestID)
+
+	// Calculate how long it took [validatorID] to reply
+	latency := cr.clock.Time().Sub(request.time)
+
+	// Tell the timeout manager we got a response
+	cr.timeoutManager.RegisterResponse(validatorID, chainID, uniqueRequestID, constants.GetStateSummaryMsg, latency)
+
+	// Pass the response to the chain
+	chain.StateSummary(validatorID, requestID, frontier, summary, onFinishedHandling)
+}
+
+// GetStateSummaryFailed routes an incoming GetStateSummaryFailed message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (cr *ChainRouter) GetStateSummaryFailed(
+	validatorID ids.ShortID,
+	chainID ids.ID,
+	requestID uint32,
+) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
+
+	// Remove the outstanding request
+	cr.removeRequest(uniqueRequestID)
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		// Should only happen if shutting down
+		cr.log.Debug("GetStateSummaryFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		return
+	}
+
+	// Pass the response to the chain
+	chain.GetStateSummaryFailed(validatorID, requestID)
+}
+
 // Get routes an incoming Get request from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
 func (cr *ChainRouter) Get(
@@ -580,6 +748,19 @@ func (cr *ChainRouter) Get(
 		return
 	}
 
+	get := inflightGet{
+		validatorID: validatorID,
+		chainID:     chainID,
+		containerID: containerID,
+		msgType:     constants.GetMsg,
+	}
+	onFinishedHandling, isDuplicate := cr.markGetInflight(get, onFinishedHandling)
+	if isDuplicate {
+		cr.log.Debug("Get(%s, %s, %d, %s) dropped due to an identical request being handled", validatorID, chainID, requestID, containerID)
+		onFinishedHandling()
+		return
+	}
+
 	// Pass the message to the chain
 	chain.Get(validatorID, requestID, deadline, containerID, onFinishedHandling)
 }
@@ -799,6 +980,7 @@ func (cr *ChainRouter) Connected(validatorID ids.ShortID) {
 	defer cr.lock.Unlock()
 
 	cr.peers.Add(validatorID)
+	cr.timeoutManager.Connected(validatorID)
 	// If this validator is benched on any chain, treat them as disconnected on all chains
 	if _, benched := cr.benched[validatorID]; benched {
 		return
@@ -815,6 +997,7 @@ func (cr *ChainRouter) Disconnected(validatorID ids.ShortID) {
 	defer cr.lock.Unlock()
 
 	cr.peers.Remove(validatorID)
+	cr.timeoutManager.Disconnected(validatorID)
 	if _, benched := cr.benched[validatorID]; benched {
 		return
 	}
@@ -922,6 +1105,29 @@ func (cr *ChainRouter) HealthCheck() (interface{}, error) {
 	return details, nil
 }
 
+// markGetInflight records that [get] is being handled. If an identical request
+// is already being handled, returns true and [onFinishedHandling] unchanged.
+// Otherwise, returns false and a function that calls [onFinishedHandling] and
+// records that [get] is no longer being handled.
+// Assumes [cr.inflightGetsLock] isn't held.
+func (cr *ChainRouter) markGetInflight(get inflightGet, onFinishedHandling func()) (func(), bool) {
+	cr.inflightGetsLock.Lock()
+	defer cr.inflightGetsLock.Unlock()
+
+	if _, exists := cr.inflightGets[get]; exists {
+		cr.metrics.droppedDuplicateGets.Inc()
+		return onFinishedHandling, true
+	}
+	cr.inflightGets[get] = struct{}{}
+	return func() {
+		cr.inflightGetsLock.Lock()
+		delete(cr.inflightGets, get)
+		cr.inflightGetsLock.Unlock()
+
+		onFinishedHandling()
+	}, false
+}
+
 // Assumes [cr.lock] is held
 func (cr *ChainRouter) createRequestID(validatorID ids.ShortID, chainID ids.ID, requestID uint32) ids.ID {
 	copy(cr.requestIDBytes, validatorID[:])
@@ -13,6 +13,8 @@ type routerMetrics struct {
 	outstandingRequests   prometheus.Gauge
 	msgDropRate           prometheus.Gauge
 	longestRunningRequest prometheus.Gauge
+	droppedDuplicateGets  prometheus.Counter
+	pendingMsgs           prometheus.Gauge
 }
 
 func newRouterMetrics(namespace string, registerer prometheus.Registerer) (*routerMetrics, error) {
@@ -38,12 +40,29 @@ func newRouterMetrics(namespace string, registerer prometheus.Registerer) (*rout
 			Help:      "Time the longest request took in milliseconds",
 		},
 	)
+	rMetrics.droppedDuplicateGets = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped_duplicate_gets",
+			Help:      "Number of Get and GetAncestors requests dropped because an identical request from the same peer was being handled",
+		},
+	)
+
+	rMetrics.pendingMsgs = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pending_msgs",
+			Help:      "Number of messages waiting to be handled, over the chains whose messages are bounded",
+		},
+	)
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(rMetrics.outstandingRequests),
 		registerer.Register(rMetrics.msgDropRate),
 		registerer.Register(rMetrics.longestRunningRequest),
+		registerer.Register(rMetrics.droppedDuplicateGets),
+		registerer.Register(rMetrics.pendingMsgs),
 	)
 	return rMetrics, errs.Err
 }
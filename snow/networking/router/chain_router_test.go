@@ -341,3 +341,82 @@ func TestRouterClearTimeouts(t *testing.T) {
 
 	assert.Equal(t, chainRouter.timedRequests.Len(), 0)
 }
+
+// Test that a Get or GetAncestors request is dropped while an identical request
+// from the same peer is being handled
+func TestRouterDropsDuplicateGets(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(err)
+	tm := timeout.Manager{}
+	err = tm.Initialize(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     time.Second,
+			MinimumTimeout:     time.Second,
+			MaximumTimeout:     10 * time.Second,
+			TimeoutCoefficient: 1.25,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	// The handler isn't dispatched, so pushed messages stay queued
+	handler := &Handler{}
+	err = handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	chainRouter.AddChain(handler)
+	// Drop the Connected messages pushed when the chain was added
+	for handler.unprocessedMsgs.Len() > 0 {
+		handler.unprocessedMsgs.Pop()
+	}
+
+	chainID := handler.Context().ChainID
+	validatorID := ids.GenerateTestShortID()
+	containerID := ids.GenerateTestID()
+	deadline := time.Now().Add(time.Minute)
+	finished := 0
+	onFinished := func() { finished++ }
+
+	chainRouter.Get(validatorID, chainID, 0, deadline, containerID, onFinished)
+	chainRouter.Get(validatorID, chainID, 1, deadline, containerID, onFinished)
+	assert.Equal(1, handler.unprocessedMsgs.Len())
+	assert.Equal(1, finished)
+
+	// A GetAncestors for the same container isn't a duplicate of the Get
+	chainRouter.GetAncestors(validatorID, chainID, 2, deadline, containerID, onFinished)
+	chainRouter.GetAncestors(validatorID, chainID, 3, deadline, containerID, onFinished)
+	assert.Equal(2, handler.unprocessedMsgs.Len())
+	assert.Equal(2, finished)
+
+	// Neither is the same request from another peer
+	chainRouter.Get(ids.GenerateTestShortID(), chainID, 4, deadline, containerID, onFinished)
+	assert.Equal(3, handler.unprocessedMsgs.Len())
+
+	// Once the request has been handled, an identical one is accepted again
+	msg := handler.unprocessedMsgs.Pop()
+	msg.doneHandling()
+	assert.Equal(3, finished)
+	chainRouter.Get(validatorID, chainID, 5, deadline, containerID, onFinished)
+	assert.Equal(3, handler.unprocessedMsgs.Len())
+	assert.Equal(3, finished)
+}
@@ -6,9 +6,11 @@ package router
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -24,6 +26,10 @@ import (
 // Handler passes incoming messages from the network to the consensus engine.
 // (Actually, it receives the incoming messages from a ChainRouter, but same difference.)
 type Handler struct {
+	// Total nanoseconds spent waiting to grab [ctx.Lock] before handling a
+	// message. Must be accessed atomically. Kept first to be 64-bit aligned.
+	lockWait int64
+
 	ctx *snow.Context
 	// Useful for faking time in tests
 	clock   timer.Clock
@@ -46,6 +52,31 @@ type Handler struct {
 	// [unprocessedMsgsCond.L] must be held while accessing [unprocessedMsgs].
 	unprocessedMsgs unprocessedMsgs
 	closing         utils.AtomicBool
+	// Recently rejected containers that aren't passed to [engine] again.
+	// May be nil.
+	rejected *RejectedCache
+	// Limits the rate of the requests from peers that are handled. May be
+	// nil.
+	requestLimiter *rate.Limiter
+	// Limits the time spent handling messages. May be nil. Only accessed by
+	// [Dispatch].
+	cpuBudget *cpuBudget
+	// Closed when this handler starts shutting down
+	halted   chan struct{}
+	haltOnce sync.Once
+	// Bounds the messages pending across all chains. May be nil.
+	// [unprocessedMsgsCond.L] must be held while accessing [pending].
+	pending *pendingMsgs
+	// This chain's share of [pending], relative to the other chains
+	queueWeight float64
+}
+
+// HandlerStats is a snapshot of the load on a Handler
+type HandlerStats struct {
+	// Number of messages waiting to be handled
+	NumUnprocessedMsgs int
+	// Total time spent waiting to grab the context lock
+	LockWait time.Duration
 }
 
 // Initialize this consensus handler
@@ -62,6 +93,8 @@ func (h *Handler) Initialize(
 		return fmt.Errorf("initializing handler metrics errored with: %s", err)
 	}
 	h.closed = make(chan struct{})
+	h.halted = make(chan struct{})
+	h.queueWeight = defaultQueueWeight
 	h.msgFromVMChan = msgFromVMChan
 	h.engine = engine
 	h.validators = validators
@@ -82,6 +115,45 @@ func (h *Handler) Engine() common.Engine { return h.engine }
 // SetEngine sets the engine for this handler to dispatch to
 func (h *Handler) SetEngine(engine common.Engine) { h.engine = engine }
 
+// SetRejectedCache sets the recently rejected containers that this handler
+// drops instead of passing to the engine
+func (h *Handler) SetRejectedCache(rejected *RejectedCache) { h.rejected = rejected }
+
+// SetBudget sets the load this handler may put on the node. Must be called
+// before [Dispatch].
+func (h *Handler) SetBudget(budget Budget) {
+	h.requestLimiter = budget.newRequestLimiter()
+	if budget.HandlingCPUs > 0 {
+		h.cpuBudget = &cpuBudget{cpus: budget.HandlingCPUs}
+	}
+	if budget.QueueWeight > 0 {
+		h.queueWeight = budget.QueueWeight
+	}
+}
+
+// setPendingMsgs sets the bound on the messages pending across all chains
+// that this handler's messages count towards
+func (h *Handler) setPendingMsgs(pending *pendingMsgs) {
+	h.unprocessedMsgsCond.L.Lock()
+	defer h.unprocessedMsgsCond.L.Unlock()
+
+	h.pending = pending
+	pending.register(h.ctx.ChainID, h.queueWeight)
+	pending.setLen(h.ctx.ChainID, h.unprocessedMsgs.Len())
+}
+
+// Stats returns a snapshot of the load on this handler
+func (h *Handler) Stats() HandlerStats {
+	h.unprocessedMsgsCond.L.Lock()
+	numUnprocessedMsgs := h.unprocessedMsgs.Len()
+	h.unprocessedMsgsCond.L.Unlock()
+
+	return HandlerStats{
+		NumUnprocessedMsgs: numUnprocessedMsgs,
+		LockWait:           time.Duration(atomic.LoadInt64(&h.lockWait)),
+	}
+}
+
 // Dispatch waits for incoming messages from the router
 // and, when they arrive, sends them to the consensus engine
 func (h *Handler) Dispatch() {
@@ -109,6 +181,7 @@ func (h *Handler) Dispatch() {
 
 		// Get the next message we should process
 		msg := h.unprocessedMsgs.Pop()
+		h.updatePending()
 		h.unprocessedMsgsCond.L.Unlock()
 
 		// If this message's deadline has passed, don't process it.
@@ -126,6 +199,32 @@ func (h *Handler) Dispatch() {
 			h.StartShutdown()
 			return
 		}
+
+		if !h.waitForBudget() {
+			return
+		}
+	}
+}
+
+// waitForBudget waits until this handler is within its CPU budget. Returns
+// false if the handler started shutting down while waiting.
+func (h *Handler) waitForBudget() bool {
+	if h.cpuBudget == nil {
+		return true
+	}
+	wait := h.cpuBudget.wait(h.clock.Time())
+	if wait <= 0 {
+		return true
+	}
+	h.metrics.throttled.Add(float64(wait))
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-h.halted:
+		return false
 	}
 }
 
@@ -142,6 +241,8 @@ func (h *Handler) handleMsg(msg message) error {
 
 	h.ctx.Lock.Lock()
 	defer h.ctx.Lock.Unlock()
+	lockedTime := h.clock.Time()
+	atomic.AddInt64(&h.lockWait, int64(lockedTime.Sub(startTime)))
 
 	var err error
 	switch msg.messageType {
@@ -165,6 +266,11 @@ func (h *Handler) handleMsg(msg message) error {
 
 	msg.doneHandling()
 
+	if h.cpuBudget != nil {
+		now := h.clock.Time()
+		h.cpuBudget.spend(now, now.Sub(lockedTime))
+	}
+
 	if isPeriodic {
 		h.ctx.Log.Verbo("Finished handling message: %s", msg.messageType)
 	} else {
@@ -195,13 +301,27 @@ func (h *Handler) handleConsensusMsg(msg message) error {
 		err = h.engine.GetAncestorsFailed(msg.nodeID, msg.requestID)
 	case constants.MultiPutMsg:
 		err = h.engine.MultiPut(msg.nodeID, msg.requestID, msg.containers)
+	case constants.GetStateSummaryMsg, constants.StateSummaryMsg, constants.GetStateSummaryFailedMsg:
+		err = h.handleStateSummaryMsg(msg)
 	case constants.GetMsg:
 		err = h.engine.Get(msg.nodeID, msg.requestID, msg.containerID)
 	case constants.GetFailedMsg:
 		err = h.engine.GetFailed(msg.nodeID, msg.requestID)
 	case constants.PutMsg:
+		if h.wasRejected(msg) {
+			// Don't leave a request for the container outstanding
+			if msg.requestID != constants.GossipMsgRequestID {
+				err = h.engine.GetFailed(msg.nodeID, msg.requestID)
+			}
+			break
+		}
 		err = h.engine.Put(msg.nodeID, msg.requestID, msg.containerID, msg.container)
 	case constants.PushQueryMsg:
+		if h.wasRejected(msg) {
+			// Respond with our preferences without parsing the container
+			err = h.engine.PullQuery(msg.nodeID, msg.requestID, msg.containerID)
+			break
+		}
 		err = h.engine.PushQuery(msg.nodeID, msg.requestID, msg.containerID, msg.container)
 	case constants.PullQueryMsg:
 		err = h.engine.PullQuery(msg.nodeID, msg.requestID, msg.containerID)
@@ -217,6 +337,35 @@ func (h *Handler) handleConsensusMsg(msg message) error {
 	return err
 }
 
+// handleStateSummaryMsg passes a state sync message to the engine, if the engine
+// supports state sync. Otherwise, the message is dropped.
+// Assumes [h.ctx.Lock] is locked
+func (h *Handler) handleStateSummaryMsg(msg message) error {
+	engine, ok := h.engine.(common.StateSummaryHandler)
+	if !ok {
+		h.ctx.Log.Verbo("dropping %s as the engine doesn't support state sync", msg)
+		return nil
+	}
+	switch msg.messageType {
+	case constants.GetStateSummaryMsg:
+		return engine.GetStateSummary(msg.nodeID, msg.requestID)
+	case constants.StateSummaryMsg:
+		return engine.StateSummary(msg.nodeID, msg.requestID, msg.containers, msg.container)
+	default:
+		return engine.GetStateSummaryFailed(msg.nodeID, msg.requestID)
+	}
+}
+
+// wasRejected returns true if the container in [msg] was recently rejected
+func (h *Handler) wasRejected(msg message) bool {
+	if h.rejected == nil || !h.rejected.Contains(msg.containerID) {
+		return false
+	}
+	h.ctx.Log.Verbo("dropping recently rejected container %s from %s%s", msg.containerID, constants.NodeIDPrefix, msg.nodeID)
+	h.metrics.rejected.Inc()
+	return true
+}
+
 // GetAcceptedFrontier passes a GetAcceptedFrontier message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFrontier(
@@ -356,6 +505,51 @@ func (h *Handler) GetAncestorsFailed(nodeID ids.ShortID, requestID uint32) {
 	})
 }
 
+// GetStateSummary passes a GetStateSummary message received from the network to the consensus engine.
+func (h *Handler) GetStateSummary(
+	nodeID ids.ShortID,
+	requestID uint32,
+	deadline time.Time,
+	onDoneHandling func(),
+) {
+	h.push(message{
+		messageType:    constants.GetStateSummaryMsg,
+		nodeID:         nodeID,
+		requestID:      requestID,
+		deadline:       deadline,
+		received:       h.clock.Time(),
+		onDoneHandling: onDoneHandling,
+	})
+}
+
+// StateSummary passes a StateSummary message received from the network to the consensus engine.
+func (h *Handler) StateSummary(
+	nodeID ids.ShortID,
+	requestID uint32,
+	frontier [][]byte,
+	summary []byte,
+	onDoneHandling func(),
+) {
+	h.push(message{
+		messageType:    constants.StateSummaryMsg,
+		nodeID:         nodeID,
+		requestID:      requestID,
+		containers:     frontier,
+		container:      summary,
+		received:       h.clock.Time(),
+		onDoneHandling: onDoneHandling,
+	})
+}
+
+// GetStateSummaryFailed passes a GetStateSummaryFailed message to the consensus engine.
+func (h *Handler) GetStateSummaryFailed(nodeID ids.ShortID, requestID uint32) {
+	h.push(message{
+		messageType: constants.GetStateSummaryFailedMsg,
+		nodeID:      nodeID,
+		requestID:   requestID,
+	})
+}
+
 // Timeout passes a new timeout notification to the consensus engine
 func (h *Handler) Timeout() {
 	h.push(message{
@@ -511,6 +705,7 @@ func (h *Handler) StartShutdown() {
 	h.unprocessedMsgsCond.L.Lock()
 	h.closing.SetValue(true)
 	h.unprocessedMsgsCond.L.Unlock()
+	h.haltOnce.Do(func() { close(h.halted) })
 
 	// If we're waiting in [Dispatch] wake up.
 	h.unprocessedMsgsCond.Signal()
@@ -551,14 +746,53 @@ func (h *Handler) push(msg message) {
 		// This should never happen
 		h.ctx.Log.Warn("message has message type %s", constants.NullMsg)
 	}
+	if h.requestLimiter != nil && isRequest(msg) && !h.requestLimiter.AllowN(h.clock.Time(), 1) {
+		h.ctx.Log.Verbo("dropping %s from %s%s as the chain is over its request budget", msg.messageType, constants.NodeIDPrefix, msg.nodeID)
+		h.metrics.overBudget.Inc()
+		msg.doneHandling()
+		return
+	}
 
 	h.unprocessedMsgsCond.L.Lock()
 	defer h.unprocessedMsgsCond.L.Unlock()
 
+	if h.pending != nil && h.pending.overShare(h.ctx.ChainID) && !h.makeRoom(msg) {
+		return
+	}
 	h.unprocessedMsgs.Push(msg)
+	h.updatePending()
 	h.unprocessedMsgsCond.Signal()
 }
 
+// makeRoom drops the oldest pending request to make room for [msg], as this
+// chain has its share of the pending messages queued. If no pending message
+// can be dropped, [msg] is dropped if it's a request. Responses, failures and
+// internal messages are never dropped, as the engine is waiting for them.
+// Returns false if [msg] was dropped.
+// Assumes [h.unprocessedMsgsCond.L] is held
+func (h *Handler) makeRoom(msg message) bool {
+	if dropped, ok := h.unprocessedMsgs.DropOldest(isRequest); ok {
+		h.ctx.Log.Verbo("dropping %s from %s%s to make room for newer messages", dropped.messageType, constants.NodeIDPrefix, dropped.nodeID)
+		h.metrics.queueFull.Inc()
+		dropped.doneHandling()
+		return true
+	}
+	if !isRequest(msg) {
+		return true
+	}
+	h.ctx.Log.Verbo("dropping %s from %s%s as the queue is full", msg.messageType, constants.NodeIDPrefix, msg.nodeID)
+	h.metrics.queueFull.Inc()
+	msg.doneHandling()
+	return false
+}
+
+// Assumes [h.unprocessedMsgsCond.L] is held
+func (h *Handler) updatePending() {
+	if h.pending != nil {
+		h.pending.setLen(h.ctx.ChainID, h.unprocessedMsgs.Len())
+	}
+}
+
 func (h *Handler) dispatchInternal() {
 	for {
 		select {
@@ -36,9 +36,14 @@ type handlerMetrics struct {
 	namespace  string
 	registerer prometheus.Registerer
 	expired    prometheus.Counter
+	rejected   prometheus.Counter
+	overBudget prometheus.Counter
+	queueFull  prometheus.Counter
+	throttled  prometheus.Counter
 	getAcceptedFrontier, acceptedFrontier, getAcceptedFrontierFailed,
 	getAccepted, accepted, getAcceptedFailed,
 	getAncestors, multiPut, getAncestorsFailed,
+	getStateSummary, stateSummary, getStateSummaryFailed,
 	get, put, getFailed,
 	pushQuery, pullQuery, chits, queryFailed,
 	connected, disconnected,
@@ -62,6 +67,34 @@ func (m *handlerMetrics) Initialize(namespace string, registerer prometheus.Regi
 	})
 	errs.Add(registerer.Register(m.expired))
 
+	m.rejected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_rejected",
+		Help:      "Incoming containers dropped because they were recently rejected",
+	})
+	errs.Add(registerer.Register(m.rejected))
+
+	m.overBudget = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_over_budget",
+		Help:      "Incoming requests dropped because the chain was over its request budget",
+	})
+	errs.Add(registerer.Register(m.overBudget))
+
+	m.queueFull = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_queue_full",
+		Help:      "Incoming requests dropped because the chain had its share of the pending messages queued",
+	})
+	errs.Add(registerer.Register(m.queueFull))
+
+	m.throttled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cpu_throttled",
+		Help:      "Time spent waiting because the chain was over its CPU budget in nanoseconds",
+	})
+	errs.Add(registerer.Register(m.throttled))
+
 	m.getAcceptedFrontier = initHistogram(namespace, "get_accepted_frontier", registerer, &errs)
 	m.acceptedFrontier = initHistogram(namespace, "accepted_frontier", registerer, &errs)
 	m.getAcceptedFrontierFailed = initHistogram(namespace, "get_accepted_frontier_failed", registerer, &errs)
@@ -71,6 +104,9 @@ func (m *handlerMetrics) Initialize(namespace string, registerer prometheus.Regi
 	m.getAncestors = initHistogram(namespace, "get_ancestors", registerer, &errs)
 	m.multiPut = initHistogram(namespace, "multi_put", registerer, &errs)
 	m.getAncestorsFailed = initHistogram(namespace, "get_ancestors_failed", registerer, &errs)
+	m.getStateSummary = initHistogram(namespace, "get_state_summary", registerer, &errs)
+	m.stateSummary = initHistogram(namespace, "state_summary", registerer, &errs)
+	m.getStateSummaryFailed = initHistogram(namespace, "get_state_summary_failed", registerer, &errs)
 	m.get = initHistogram(namespace, "get", registerer, &errs)
 	m.put = initHistogram(namespace, "put", registerer, &errs)
 	m.getFailed = initHistogram(namespace, "get_failed", registerer, &errs)
@@ -122,6 +158,12 @@ func (m *handlerMetrics) getMSGHistogram(msg constants.MsgType) prometheus.Histo
 		return m.getAncestorsFailed
 	case constants.MultiPutMsg:
 		return m.multiPut
+	case constants.GetStateSummaryMsg:
+		return m.getStateSummary
+	case constants.StateSummaryMsg:
+		return m.stateSummary
+	case constants.GetStateSummaryFailedMsg:
+		return m.getStateSummaryFailed
 	case constants.TimeoutMsg:
 		return m.timeout
 	case constants.GetMsg:
@@ -219,3 +219,93 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	case <-calledNotify:
 	}
 }
+
+func TestHandlerDropsRequestsOverBudget(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(t, err)
+	handler := &Handler{}
+	err = handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+	handler.SetBudget(Budget{
+		RequestsPerSecond: 1,
+		RequestBurst:      1,
+	})
+	currentTime := time.Now()
+	handler.clock.Set(currentTime)
+
+	handled := 0
+	onDoneHandling := func() { handled++ }
+	handler.GetAcceptedFrontier(ids.ShortID{1}, 1, currentTime.Add(time.Second), onDoneHandling)
+	handler.GetAcceptedFrontier(ids.ShortID{1}, 2, currentTime.Add(time.Second), onDoneHandling)
+	// Responses and failures aren't dropped
+	handler.AcceptedFrontier(ids.ShortID{1}, 3, nil, onDoneHandling)
+	handler.GetAcceptedFrontierFailed(ids.ShortID{1}, 4)
+
+	// The second request was dropped, and marked as handled
+	assert.Equal(t, 3, handler.unprocessedMsgs.Len())
+	assert.Equal(t, 1, handled)
+
+	// Once the budget is replenished, requests are handled again
+	handler.clock.Set(currentTime.Add(time.Second))
+	handler.GetAcceptedFrontier(ids.ShortID{1}, 5, currentTime.Add(2*time.Second), onDoneHandling)
+	assert.Equal(t, 4, handler.unprocessedMsgs.Len())
+	assert.Equal(t, 1, handled)
+}
+
+func TestHandlerDropsOldestRequestWhenQueueFull(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(t, err)
+	handler := &Handler{}
+	err = handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+	handler.setPendingMsgs(newPendingMsgs(2, prometheus.NewGauge(prometheus.GaugeOpts{})))
+	currentTime := time.Now()
+	handler.clock.Set(currentTime)
+
+	dropped := []uint32(nil)
+	onDoneHandling := func(requestID uint32) func() {
+		return func() { dropped = append(dropped, requestID) }
+	}
+	deadline := currentTime.Add(time.Second)
+	handler.GetAcceptedFrontier(ids.ShortID{1}, 1, deadline, onDoneHandling(1))
+	handler.AcceptedFrontier(ids.ShortID{1}, 2, nil, onDoneHandling(2))
+	assert.Empty(t, dropped)
+
+	// The queue is full, so the oldest request makes room for the new one
+	handler.GetAcceptedFrontier(ids.ShortID{1}, 3, deadline, onDoneHandling(3))
+	assert.Equal(t, []uint32{1}, dropped)
+	assert.Equal(t, 2, handler.unprocessedMsgs.Len())
+
+	// Responses are queued even if no request can be dropped
+	handler.AcceptedFrontier(ids.ShortID{1}, 4, nil, onDoneHandling(4))
+	handler.AcceptedFrontier(ids.ShortID{1}, 5, nil, onDoneHandling(5))
+	assert.Equal(t, []uint32{1, 3}, dropped)
+	assert.Equal(t, 3, handler.unprocessedMsgs.Len())
+
+	// New requests are dropped once there is no request left to drop
+	handler.GetAcceptedFrontier(ids.ShortID{1}, 6, deadline, onDoneHandling(6))
+	assert.Equal(t, []uint32{1, 3, 6}, dropped)
+	assert.Equal(t, 3, handler.unprocessedMsgs.Len())
+}
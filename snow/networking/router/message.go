@@ -58,6 +58,8 @@ func (m message) String() string {
 		sb.WriteString(fmt.Sprintf(", ContainerID: %s)", m.containerID))
 	case constants.MultiPutMsg:
 		sb.WriteString(fmt.Sprintf(", NumContainers: %d)", len(m.containers)))
+	case constants.StateSummaryMsg:
+		sb.WriteString(fmt.Sprintf(", NumContainers: %d, SummaryLen: %d)", len(m.containers), len(m.container)))
 	case constants.NotifyMsg:
 		sb.WriteString(fmt.Sprintf(", Notification: %s)", m.notification))
 	default:
@@ -0,0 +1,102 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Weight of a chain whose budget doesn't set one
+const defaultQueueWeight = 1
+
+type pendingChain struct {
+	weight float64
+	len    int
+}
+
+// pendingMsgs bounds the number of messages waiting to be handled across all
+// chains, and shares the bound between the chains in proportion to their
+// weights. While the bound isn't reached, any chain may queue more than its
+// share. Once it's reached, a chain with at least its share queued must drop
+// a message to queue another, so that a chain under load can't hold the
+// inbound message budget of its peers at the expense of the other chains.
+// pendingMsgs is safe for concurrent access.
+type pendingMsgs struct {
+	max   int
+	gauge prometheus.Gauge
+
+	lock        sync.Mutex
+	chains      map[ids.ID]*pendingChain
+	total       int
+	totalWeight float64
+}
+
+func newPendingMsgs(max int, gauge prometheus.Gauge) *pendingMsgs {
+	return &pendingMsgs{
+		max:    max,
+		gauge:  gauge,
+		chains: make(map[ids.ID]*pendingChain),
+	}
+}
+
+// register chain [chainID], whose share of the bound is proportional to
+// [weight]
+func (p *pendingMsgs) register(chainID ids.ID, weight float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, exists := p.chains[chainID]; exists {
+		return
+	}
+	p.chains[chainID] = &pendingChain{weight: weight}
+	p.totalWeight += weight
+}
+
+// deregister chain [chainID]. Its queued messages no longer count towards the
+// bound.
+func (p *pendingMsgs) deregister(chainID ids.ID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	chain, exists := p.chains[chainID]
+	if !exists {
+		return
+	}
+	delete(p.chains, chainID)
+	p.totalWeight -= chain.weight
+	p.total -= chain.len
+	p.gauge.Set(float64(p.total))
+}
+
+// setLen records that chain [chainID] has [numMsgs] messages queued
+func (p *pendingMsgs) setLen(chainID ids.ID, numMsgs int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	chain, exists := p.chains[chainID]
+	if !exists {
+		return
+	}
+	p.total += numMsgs - chain.len
+	chain.len = numMsgs
+	p.gauge.Set(float64(p.total))
+}
+
+// overShare returns true if the bound is reached and chain [chainID] has at
+// least its share of the bound queued
+func (p *pendingMsgs) overShare(chainID ids.ID) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	chain, exists := p.chains[chainID]
+	if !exists || p.total < p.max {
+		return false
+	}
+	share := float64(p.max) * chain.weight / p.totalWeight
+	return float64(chain.len) >= share
+}
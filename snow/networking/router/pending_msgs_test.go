@@ -0,0 +1,61 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestPendingMsgsShares(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{})
+	p := newPendingMsgs(8, gauge)
+	chainID0 := ids.ID{1}
+	chainID1 := ids.ID{2}
+	p.register(chainID0, 1)
+	p.register(chainID1, 3)
+
+	// While the bound isn't reached, a chain may queue more than its share
+	p.setLen(chainID0, 7)
+	assert.False(t, p.overShare(chainID0))
+
+	// Once it's reached, only the chains with at least their share queued
+	// are over their share
+	p.setLen(chainID1, 1)
+	assert.True(t, p.overShare(chainID0))
+	assert.False(t, p.overShare(chainID1))
+
+	p.setLen(chainID0, 1)
+	p.setLen(chainID1, 7)
+	assert.False(t, p.overShare(chainID0))
+	assert.True(t, p.overShare(chainID1))
+
+	// A chain with exactly its share queued is at its share
+	p.setLen(chainID0, 2)
+	p.setLen(chainID1, 6)
+	assert.True(t, p.overShare(chainID0))
+	assert.True(t, p.overShare(chainID1))
+
+	// The messages of deregistered chains don't count towards the bound
+	p.deregister(chainID1)
+	assert.False(t, p.overShare(chainID0))
+	assert.False(t, p.overShare(chainID1))
+	assert.Equal(t, 2, p.total)
+	assert.Equal(t, float64(1), p.totalWeight)
+}
+
+func TestPendingMsgsUnknownChain(t *testing.T) {
+	p := newPendingMsgs(1, prometheus.NewGauge(prometheus.GaugeOpts{}))
+	chainID := ids.ID{1}
+
+	p.setLen(chainID, 5)
+	assert.Zero(t, p.total)
+	assert.False(t, p.overShare(chainID))
+	p.deregister(chainID)
+	assert.Zero(t, p.totalWeight)
+}
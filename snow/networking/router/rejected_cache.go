@@ -0,0 +1,129 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+)
+
+var (
+	errInvalidRejectedCacheSize = errors.New("rejected cache size must be positive")
+
+	_ triggers.Rejector = &RejectedCache{}
+)
+
+type rejectedEntry struct {
+	containerID ids.ID
+	index       uint64
+}
+
+// RejectedCache is a persisted set of the IDs of the most recently rejected
+// containers of a chain. Copies of these containers that are gossiped again
+// can be dropped without being parsed, verified or issued into consensus.
+//
+// Each ID is stored in [db] under the index it was added at, so the oldest ID
+// is evicted first once the cache is full.
+type RejectedCache struct {
+	lock sync.Mutex
+	db   database.Database
+	size int
+	// Container ID --> rejectedEntry, from oldest to newest
+	entries   linkedhashmap.LinkedHashmap
+	nextIndex uint64
+}
+
+// NewRejectedCache returns a cache of up to [size] IDs stored in [db]
+func NewRejectedCache(db database.Database, size int) (*RejectedCache, error) {
+	if size <= 0 {
+		return nil, errInvalidRejectedCacheSize
+	}
+	c := &RejectedCache{
+		db:      db,
+		size:    size,
+		entries: linkedhashmap.New(),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	// Keys are big endian, so the IDs are iterated from oldest to newest
+	for it.Next() {
+		index, err := database.ParseUInt64(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		containerID, err := database.ParseID(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		c.entries.Put(containerID, rejectedEntry{
+			containerID: containerID,
+			index:       index,
+		})
+		c.nextIndex = index + 1
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	// The size may have been reduced since the IDs were stored
+	batch := db.NewBatch()
+	if err := c.evict(batch); err != nil {
+		return nil, err
+	}
+	return c, batch.Write()
+}
+
+// Reject implements the triggers.Rejector interface
+func (c *RejectedCache) Reject(_ *snow.Context, containerID ids.ID, _ []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.entries.Get(containerID); ok {
+		return nil
+	}
+
+	batch := c.db.NewBatch()
+	if err := batch.Put(database.PackUInt64(c.nextIndex), containerID[:]); err != nil {
+		return err
+	}
+	c.entries.Put(containerID, rejectedEntry{
+		containerID: containerID,
+		index:       c.nextIndex,
+	})
+	c.nextIndex++
+	if err := c.evict(batch); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Contains returns true if [containerID] was recently rejected
+func (c *RejectedCache) Contains(containerID ids.ID) bool {
+	_, ok := c.entries.Get(containerID)
+	return ok
+}
+
+// Len returns the number of IDs in the cache
+func (c *RejectedCache) Len() int { return c.entries.Len() }
+
+// evict the oldest IDs until there are at most [c.size] IDs in the cache
+func (c *RejectedCache) evict(batch database.Batch) error {
+	for c.entries.Len() > c.size {
+		oldest, _ := c.entries.Oldest()
+		entry := oldest.(rejectedEntry)
+		if err := batch.Delete(database.PackUInt64(entry.index)); err != nil {
+			return err
+		}
+		c.entries.Delete(entry.containerID)
+	}
+	return nil
+}
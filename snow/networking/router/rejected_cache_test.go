@@ -0,0 +1,136 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestRejectedCache(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewRejectedCache(memdb.New(), 0)
+	assert.Equal(errInvalidRejectedCacheSize, err)
+
+	db := memdb.New()
+	cache, err := NewRejectedCache(db, 2)
+	assert.NoError(err)
+
+	id0 := ids.GenerateTestID()
+	id1 := ids.GenerateTestID()
+	id2 := ids.GenerateTestID()
+	assert.NoError(cache.Reject(nil, id0, nil))
+	assert.NoError(cache.Reject(nil, id1, nil))
+	assert.NoError(cache.Reject(nil, id1, nil))
+	assert.True(cache.Contains(id0))
+	assert.True(cache.Contains(id1))
+	assert.Equal(2, cache.Len())
+
+	// The oldest ID is evicted
+	assert.NoError(cache.Reject(nil, id2, nil))
+	assert.False(cache.Contains(id0))
+	assert.True(cache.Contains(id1))
+	assert.True(cache.Contains(id2))
+
+	// The IDs are persisted
+	reloaded, err := NewRejectedCache(db, 2)
+	assert.NoError(err)
+	assert.False(reloaded.Contains(id0))
+	assert.True(reloaded.Contains(id1))
+	assert.True(reloaded.Contains(id2))
+
+	// Reloading with a smaller size evicts the oldest IDs
+	reloaded, err = NewRejectedCache(db, 1)
+	assert.NoError(err)
+	assert.Equal(1, reloaded.Len())
+	assert.True(reloaded.Contains(id2))
+
+	reloaded, err = NewRejectedCache(db, 2)
+	assert.NoError(err)
+	assert.Equal(1, reloaded.Len())
+
+	// New IDs are added after the existing ones
+	assert.NoError(reloaded.Reject(nil, id0, nil))
+	assert.NoError(reloaded.Reject(nil, id1, nil))
+	assert.False(reloaded.Contains(id2))
+	assert.True(reloaded.Contains(id0))
+	assert.True(reloaded.Contains(id1))
+}
+
+func TestHandlerDropsRejectedContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = snow.DefaultContextTest
+
+	handler := &Handler{}
+	vdrs := validators.NewSet()
+	assert.NoError(handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	))
+
+	rejected, err := NewRejectedCache(memdb.New(), 1)
+	assert.NoError(err)
+	handler.SetRejectedCache(rejected)
+
+	vdr := ids.GenerateTestShortID()
+	containerID := ids.GenerateTestID()
+	assert.NoError(rejected.Reject(nil, containerID, nil))
+
+	// Gossiped copies are dropped
+	assert.NoError(handler.handleMsg(message{
+		messageType: constants.PutMsg,
+		nodeID:      vdr,
+		requestID:   constants.GossipMsgRequestID,
+		containerID: containerID,
+	}))
+
+	// Requested copies fail the request
+	getFailed := false
+	engine.GetFailedF = func(validatorID ids.ShortID, requestID uint32) error {
+		assert.Equal(vdr, validatorID)
+		assert.Equal(uint32(1), requestID)
+		getFailed = true
+		return nil
+	}
+	assert.NoError(handler.handleMsg(message{
+		messageType: constants.PutMsg,
+		nodeID:      vdr,
+		requestID:   1,
+		containerID: containerID,
+	}))
+	assert.True(getFailed)
+
+	// Queries are answered without parsing the container
+	pullQuery := false
+	engine.PullQueryF = func(validatorID ids.ShortID, requestID uint32, queriedID ids.ID) error {
+		assert.Equal(vdr, validatorID)
+		assert.Equal(uint32(2), requestID)
+		assert.Equal(containerID, queriedID)
+		pullQuery = true
+		return nil
+	}
+	assert.NoError(handler.handleMsg(message{
+		messageType: constants.PushQueryMsg,
+		nodeID:      vdr,
+		requestID:   2,
+		containerID: containerID,
+	}))
+	assert.True(pullQuery)
+}
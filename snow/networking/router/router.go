@@ -34,7 +34,17 @@ type Router interface {
 		metricsRegisterer prometheus.Registerer,
 	) error
 	Shutdown()
+	// SetMaxPendingMsgs bounds the number of messages waiting to be handled
+	// across all chains added after this call. If 0, the number isn't
+	// bounded. Must be called after Initialize.
+	SetMaxPendingMsgs(max int)
 	AddChain(chain *Handler)
+	// RemoveChain shuts down the chain with the given ID, and stops routing
+	// messages to it
+	RemoveChain(chainID ids.ID)
+	// HandlerStats returns a snapshot of the load on the handler of each
+	// chain
+	HandlerStats() map[ids.ID]HandlerStats
 	health.Checkable
 }
 
@@ -91,6 +101,21 @@ type ExternalRouter interface {
 		containers [][]byte,
 		onFinishedHandling func(),
 	)
+	GetStateSummary(
+		validatorID ids.ShortID,
+		chainID ids.ID,
+		requestID uint32,
+		deadline time.Time,
+		onFinishedHandling func(),
+	)
+	StateSummary(
+		validatorID ids.ShortID,
+		chainID ids.ID,
+		requestID uint32,
+		frontier [][]byte,
+		summary []byte,
+		onFinishedHandling func(),
+	)
 	Get(
 		validatorID ids.ShortID,
 		chainID ids.ID,
@@ -139,6 +164,7 @@ type InternalRouter interface {
 	GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 
 	Connected(validatorID ids.ShortID)
@@ -25,6 +25,9 @@ type unprocessedMsgs interface {
 	Pop() message
 	// Returns the number of unprocessed messages
 	Len() int
+	// Removes and returns the oldest unprocessed message for which
+	// [droppable] returns true. Returns false if there is no such message.
+	DropOldest(droppable func(message) bool) (message, bool)
 }
 
 func newUnprocessedMsgs(
@@ -109,6 +112,25 @@ func (u *unprocessedMsgsImpl) Len() int {
 	return len(u.msgs)
 }
 
+func (u *unprocessedMsgsImpl) DropOldest(droppable func(message) bool) (message, bool) {
+	for i, msg := range u.msgs {
+		if !droppable(msg) {
+			continue
+		}
+		copy(u.msgs[i:], u.msgs[i+1:])
+		u.msgs[len(u.msgs)-1] = message{}
+		u.msgs = u.msgs[:len(u.msgs)-1]
+		u.nodeToUnprocessedMsgs[msg.nodeID]--
+		if u.nodeToUnprocessedMsgs[msg.nodeID] == 0 {
+			delete(u.nodeToUnprocessedMsgs, msg.nodeID)
+		}
+		u.metrics.nodesWithUnprocessedMsgs.Set(float64(len(u.nodeToUnprocessedMsgs)))
+		u.metrics.len.Dec()
+		return msg, true
+	}
+	return message{}, false
+}
+
 // canPop will return true for at least one message in [u.msgs]
 func (u *unprocessedMsgsImpl) canPop(msg *message) bool {
 	// If the deadline to handle [msg] has passed, always pop it.
@@ -30,6 +30,13 @@ type ExternalSender interface {
 	GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool
 	MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte)
 
+	// Request the accepted frontier and a summary of the state of chain
+	// [chainID] from validator [validatorID].
+	// The validator should reply by [deadline].
+	// Returns true if the validator may receive the message.
+	GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration) bool
+	StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, frontier [][]byte, summary []byte)
+
 	Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool
 	Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 
@@ -48,6 +48,7 @@ func (s *Sender) Initialize(
 		constants.GetAcceptedMsg:         "get_accepted",
 		constants.GetAcceptedFrontierMsg: "get_accepted_frontier",
 		constants.GetAncestorsMsg:        "get_ancestors",
+		constants.GetStateSummaryMsg:     "get_state_summary",
 		constants.PullQueryMsg:           "pull_query",
 		constants.PushQueryMsg:           "push_query",
 	}
@@ -222,6 +223,46 @@ func (s *Sender) MultiPut(validatorID ids.ShortID, requestID uint32, containers
 	s.sender.MultiPut(validatorID, s.ctx.ChainID, requestID, containers)
 }
 
+// GetStateSummary sends a GetStateSummary message
+func (s *Sender) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	s.ctx.Log.Verbo("Sending GetStateSummary to validator %s. RequestID: %d", validatorID, requestID)
+	// Sending a GetStateSummary to myself will always fail
+	if validatorID == s.ctx.NodeID {
+		go s.router.GetStateSummaryFailed(validatorID, s.ctx.ChainID, requestID)
+		return
+	}
+
+	// [validatorID] may be benched. That is, they've been unresponsive
+	// so we don't even bother sending requests to them. We just have them immediately fail.
+	if s.timeouts.IsBenched(validatorID, s.ctx.ChainID) {
+		s.failedDueToBench[constants.GetStateSummaryMsg].Inc() // update metric
+		s.timeouts.RegisterRequestToUnreachableValidator()
+		go s.router.GetStateSummaryFailed(validatorID, s.ctx.ChainID, requestID)
+		return
+	}
+
+	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
+	timeoutDuration := s.timeouts.TimeoutDuration()
+	sent := s.sender.GetStateSummary(validatorID, s.ctx.ChainID, requestID, timeoutDuration)
+
+	if sent {
+		// Tell the router to expect a reply message from this validator
+		s.router.RegisterRequest(validatorID, s.ctx.ChainID, requestID, constants.GetStateSummaryMsg)
+		return
+	}
+	s.timeouts.RegisterRequestToUnreachableValidator()
+	go s.router.GetStateSummaryFailed(validatorID, s.ctx.ChainID, requestID)
+}
+
+// StateSummary sends a StateSummary message to the consensus engine running on
+// the specified chain on the specified validator.
+// The StateSummary message gives the recipient the accepted frontier of this
+// node and a summary of the chain's state as of that frontier.
+func (s *Sender) StateSummary(validatorID ids.ShortID, requestID uint32, frontier [][]byte, summary []byte) {
+	s.ctx.Log.Verbo("Sending StateSummary to validator %s. RequestID: %d. NumContainers: %d. SummaryLen: %d", validatorID, requestID, len(frontier), len(summary))
+	s.sender.StateSummary(validatorID, s.ctx.ChainID, requestID, frontier, summary)
+}
+
 // Get sends a Get message to the consensus engine running on the specified
 // chain to the specified validator. The Get message signifies that this
 // consensus engine would like the recipient to send this consensus engine the
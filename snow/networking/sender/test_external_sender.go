@@ -18,6 +18,7 @@ type ExternalSenderTest struct {
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
 	CantGetAncestors, CantMultiPut,
+	CantGetStateSummary, CantStateSummary,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits,
 	CantGossip bool
@@ -31,6 +32,9 @@ type ExternalSenderTest struct {
 	GetAncestorsF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool
 	MultiPutF     func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte)
 
+	GetStateSummaryF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration) bool
+	StateSummaryF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, frontier [][]byte, summary []byte)
+
 	GetF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool
 	PutF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 
@@ -52,6 +56,9 @@ func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantGetAncestors = cant
 	s.CantMultiPut = cant
 
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+
 	s.CantGet = cant
 	s.CantPut = cant
 
@@ -149,6 +156,35 @@ func (s *ExternalSenderTest) MultiPut(vdr ids.ShortID, chainID ids.ID, requestID
 	}
 }
 
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateSummary(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration) bool {
+	switch {
+	case s.GetStateSummaryF != nil:
+		return s.GetStateSummaryF(vdr, chainID, requestID, deadline)
+	case s.CantGetStateSummary && s.T != nil:
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	case s.CantGetStateSummary && s.B != nil:
+		s.B.Fatalf("Unexpectedly called GetStateSummary")
+	}
+	return false
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) StateSummary(vdr ids.ShortID, chainID ids.ID, requestID uint32, frontier [][]byte, summary []byte) {
+	switch {
+	case s.StateSummaryF != nil:
+		s.StateSummaryF(vdr, chainID, requestID, frontier, summary)
+	case s.CantStateSummary && s.T != nil:
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	case s.CantStateSummary && s.B != nil:
+		s.B.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
 // Get calls GetF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
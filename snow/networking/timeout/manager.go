@@ -16,12 +16,21 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Weight of a new latency observation in the estimated latency of a
+// validator, as in the smoothed round trip time of TCP
+const latencyEWMAWeight = 0.125
+
 // Manager registers and fires timeouts for the snow API.
 type Manager struct {
 	lock         sync.Mutex
 	tm           timer.AdaptiveTimeoutManager
 	benchlistMgr benchlist.Manager
 	metrics      metrics
+
+	// Validators that are connected. Only their latencies are estimated.
+	connected ids.ShortSet
+	// Validator ID --> Estimated latency of its responses
+	latencies map[ids.ShortID]time.Duration
 }
 
 // Initialize this timeout manager.
@@ -75,6 +84,10 @@ func (m *Manager) RegisterRequest(
 	newTimeoutHandler := func() {
 		// If this request timed out, tell the benchlist manager
 		m.benchlistMgr.RegisterFailure(chainID, validatorID)
+		timeout := m.TimeoutDuration()
+		m.lock.Lock()
+		m.observeLatency(validatorID, timeout)
+		m.lock.Unlock()
 		timeoutHandler()
 	}
 	return m.tm.Put(uniqueRequestID, msgType, newTimeoutHandler), true
@@ -91,6 +104,7 @@ func (m *Manager) RegisterResponse(
 ) {
 	m.lock.Lock()
 	m.metrics.observe(chainID, msgType, latency)
+	m.observeLatency(validatorID, latency)
 	m.lock.Unlock()
 	m.benchlistMgr.RegisterResponse(chainID, validatorID)
 	m.tm.Remove(uniqueRequestID)
@@ -104,3 +118,50 @@ func (m *Manager) RegisterResponse(
 func (m *Manager) RegisterRequestToUnreachableValidator() {
 	m.tm.ObserveLatency(m.TimeoutDuration())
 }
+
+// Latency returns the estimated latency of the responses of [validatorID].
+// Requests that timed out count as responses that took as long as the timeout.
+// Returns false if no response from [validatorID] was registered.
+func (m *Manager) Latency(validatorID ids.ShortID) (time.Duration, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	latency, ok := m.latencies[validatorID]
+	return latency, ok
+}
+
+// Connected records that [validatorID] connected, so the latency of its
+// responses is estimated
+func (m *Manager) Connected(validatorID ids.ShortID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.connected.Add(validatorID)
+}
+
+// Disconnected discards the estimated latency of [validatorID], which
+// disconnected. Requests to it that time out afterwards aren't observed.
+func (m *Manager) Disconnected(validatorID ids.ShortID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.connected.Remove(validatorID)
+	delete(m.latencies, validatorID)
+}
+
+// observeLatency updates the estimated latency of [validatorID] with a
+// response that took [latency]. Assumes [m.lock] is held.
+func (m *Manager) observeLatency(validatorID ids.ShortID, latency time.Duration) {
+	if !m.connected.Contains(validatorID) {
+		return
+	}
+	if m.latencies == nil {
+		m.latencies = make(map[ids.ShortID]time.Duration)
+	}
+	estimate, ok := m.latencies[validatorID]
+	if !ok {
+		m.latencies[validatorID] = latency
+		return
+	}
+	m.latencies[validatorID] = estimate + time.Duration(latencyEWMAWeight*float64(latency-estimate))
+}
@@ -81,3 +81,51 @@ func TestManagerCancel(t *testing.T) {
 		t.Fatalf("Should have cancelled the function")
 	}
 }
+
+func TestManagerLatency(t *testing.T) {
+	manager := Manager{}
+	benchlist := benchlist.NewNoBenchlist()
+	err := manager.Initialize(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     time.Millisecond,
+			MinimumTimeout:     time.Millisecond,
+			MaximumTimeout:     10 * time.Second,
+			TimeoutCoefficient: 1.25,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist,
+		"",
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vdrID := ids.GenerateTestShortID()
+	manager.Connected(vdrID)
+	if _, ok := manager.Latency(vdrID); ok {
+		t.Fatalf("Shouldn't have estimated the latency of a validator that never responded")
+	}
+
+	manager.RegisterResponse(vdrID, ids.ID{}, ids.GenerateTestID(), constants.ChitsMsg, time.Second)
+	if latency, ok := manager.Latency(vdrID); !ok || latency != time.Second {
+		t.Fatalf("Expected latency %s but got %s", time.Second, latency)
+	}
+
+	manager.RegisterResponse(vdrID, ids.ID{}, ids.GenerateTestID(), constants.ChitsMsg, 2*time.Second)
+	expected := time.Second + time.Second/8
+	if latency, ok := manager.Latency(vdrID); !ok || latency != expected {
+		t.Fatalf("Expected latency %s but got %s", expected, latency)
+	}
+
+	// The latency of a disconnected validator is discarded and no longer
+	// estimated
+	manager.Disconnected(vdrID)
+	if _, ok := manager.Latency(vdrID); ok {
+		t.Fatalf("Shouldn't have kept the latency of a disconnected validator")
+	}
+	manager.RegisterResponse(vdrID, ids.ID{}, ids.GenerateTestID(), constants.ChitsMsg, time.Second)
+	if _, ok := manager.Latency(vdrID); ok {
+		t.Fatalf("Shouldn't have estimated the latency of a disconnected validator")
+	}
+}
@@ -142,6 +142,43 @@ func (ed *EventDispatcher) Issue(ctx *snow.Context, containerID ids.ID, containe
 	return nil
 }
 
+// Connected is called when this node connects to [nodeID]
+func (ed *EventDispatcher) Connected(nodeID ids.ShortID) {
+	ed.lock.Lock()
+	defer ed.lock.Unlock()
+
+	for _, handler := range ed.handlers {
+		if handler, ok := handler.(PeerObserver); ok {
+			handler.Connected(nodeID)
+		}
+	}
+}
+
+// Disconnected is called when this node disconnects from [nodeID]
+func (ed *EventDispatcher) Disconnected(nodeID ids.ShortID) {
+	ed.lock.Lock()
+	defer ed.lock.Unlock()
+
+	for _, handler := range ed.handlers {
+		if handler, ok := handler.(PeerObserver); ok {
+			handler.Disconnected(nodeID)
+		}
+	}
+}
+
+// HealthChanged is called when the health check [name] starts passing or
+// starts failing
+func (ed *EventDispatcher) HealthChanged(name string, healthy bool) {
+	ed.lock.Lock()
+	defer ed.lock.Unlock()
+
+	for _, handler := range ed.handlers {
+		if handler, ok := handler.(HealthObserver); ok {
+			handler.HealthChanged(name, healthy)
+		}
+	}
+}
+
 // RegisterChain causes [handlerFunc] to be invoked every time a container is issued, accepted or rejected on chain [chainID].
 // [handlerFunc] should implement at least one of Acceptor, Rejector, Issuer.
 // If [dieOnError], chain [chainID] stops if [handler].Accept is invoked and returns a non-nil error.
@@ -188,7 +225,8 @@ func (ed *EventDispatcher) DeregisterChain(chainID ids.ID, identifier string) er
 	return nil
 }
 
-// Register places a new handler into the system
+// Register places a new handler into the system. [handler] should implement
+// at least one of Acceptor, Rejector, Issuer, PeerObserver, HealthObserver.
 func (ed *EventDispatcher) Register(identifier string, handler interface{}) error {
 	ed.lock.Lock()
 	defer ed.lock.Unlock()
@@ -0,0 +1,17 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package triggers dispatches events to the observers registered for them.
+//
+// The node runs three dispatchers. The decision and consensus dispatchers
+// dispatch the containers each chain issues, accepts and rejects. The IPC
+// sockets, the indexer and the chain manager's caches observe them. Accept is
+// dispatched before the container is committed, so that an observer that
+// fails can stop the chain. The node dispatcher dispatches the events of the
+// node itself, that is peer connections and health check transitions, which
+// the node's metrics observe.
+//
+// VMs publish their own accepted txs to the subscribers of their pubsub
+// servers, since those subscribers expect to be able to fetch a tx once it's
+// published, which requires the tx to be committed.
+package triggers
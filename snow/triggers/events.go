@@ -22,3 +22,18 @@ type Rejector interface {
 type Issuer interface {
 	Issue(ctx *snow.Context, containerID ids.ID, container []byte) error
 }
+
+// PeerObserver is implemented when a struct is monitoring the peers this node
+// is connected to
+type PeerObserver interface {
+	Connected(nodeID ids.ShortID)
+	Disconnected(nodeID ids.ShortID)
+}
+
+// HealthObserver is implemented when a struct is monitoring the results of
+// this node's health checks
+type HealthObserver interface {
+	// HealthChanged is called when the health check [name] starts passing or
+	// starts failing
+	HealthChanged(name string, healthy bool)
+}
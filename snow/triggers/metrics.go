@@ -0,0 +1,63 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package triggers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	_ PeerObserver   = &Metrics{}
+	_ HealthObserver = &Metrics{}
+)
+
+// Metrics counts the peer and health events dispatched to it
+type Metrics struct {
+	connected, disconnected prometheus.Counter
+	healthChanges           *prometheus.CounterVec
+}
+
+// NewMetrics returns a new Metrics whose metrics are registered with
+// [registerer]
+func NewMetrics(namespace string, registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		connected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "peer_connected_events",
+			Help:      "Number of times this node connected to a peer",
+		}),
+		disconnected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "peer_disconnected_events",
+			Help:      "Number of times this node disconnected from a peer",
+		}),
+		healthChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "health_change_events",
+			Help:      "Number of times a health check started passing or started failing",
+		}, []string{"check", "healthy"}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.connected),
+		registerer.Register(m.disconnected),
+		registerer.Register(m.healthChanges),
+	)
+	return m, errs.Err
+}
+
+func (m *Metrics) Connected(ids.ShortID)    { m.connected.Inc() }
+func (m *Metrics) Disconnected(ids.ShortID) { m.disconnected.Inc() }
+
+func (m *Metrics) HealthChanged(name string, healthy bool) {
+	label := "false"
+	if healthy {
+		label = "true"
+	}
+	m.healthChanges.WithLabelValues(name, label).Inc()
+}
@@ -4,7 +4,9 @@
 package validators
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"sync"
 
@@ -28,6 +30,11 @@ const (
 	capacityReductionFactor = 2
 )
 
+var (
+	errInvalidBias  = errors.New("sampling bias must be a non-negative number")
+	errBiasOverflow = errors.New("biased weight overflows")
+)
+
 // Set of validators that can be sampled
 type Set interface {
 	fmt.Stringer
@@ -65,6 +72,11 @@ type Set interface {
 	// If sampling the requested size isn't possible, an error will be returned.
 	Sample(size int) ([]Validator, error)
 
+	// SampleBiased returns a collection of validators sampled like Sample,
+	// except that the weight of each validator is multiplied by the
+	// non-negative factor returned by [bias].
+	SampleBiased(size int, bias func(ids.ShortID) float64) ([]Validator, error)
+
 	// MaskValidator hides the named validator from future samplings
 	MaskValidator(ids.ShortID) error
 
@@ -402,6 +414,45 @@ func (s *set) sample(size int) ([]Validator, error) {
 	return list, nil
 }
 
+// SampleBiased implements the Group interface.
+func (s *set) SampleBiased(size int, bias func(ids.ShortID) float64) ([]Validator, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	weights := make([]uint64, len(s.vdrMaskedWeights))
+	for i, weight := range s.vdrMaskedWeights {
+		factor := bias(s.vdrSlice[i].ID())
+		if factor < 0 || math.IsNaN(factor) {
+			return nil, errInvalidBias
+		}
+		biasedWeight := float64(weight) * factor
+		if biasedWeight >= math.MaxUint64 {
+			return nil, errBiasOverflow
+		}
+		weights[i] = uint64(biasedWeight)
+	}
+
+	// The biased weights differ between calls, so the set's sampler can't be
+	// reused
+	biasedSampler := sampler.NewWeightedWithoutReplacement()
+	if err := biasedSampler.Initialize(weights); err != nil {
+		return nil, err
+	}
+	indices, err := biasedSampler.Sample(size)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Validator, size)
+	for i, index := range indices {
+		list[i] = s.vdrSlice[index]
+	}
+	return list, nil
+}
+
 func (s *set) Weight() uint64 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
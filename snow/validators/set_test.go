@@ -75,6 +75,40 @@ func TestSamplerSample(t *testing.T) {
 	assert.Equal(t, vdr1, sampled[2].ID(), "should have sampled vdr1")
 }
 
+func TestSamplerSampleBiased(t *testing.T) {
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	vdr2 := ids.GenerateTestShortID()
+
+	s := NewSet()
+	assert.NoError(t, s.AddWeight(vdr0, 1))
+	assert.NoError(t, s.AddWeight(vdr1, 1))
+	assert.NoError(t, s.AddWeight(vdr2, math.MaxInt64-2))
+	assert.NoError(t, s.MaskValidator(vdr2))
+
+	// The weight of vdr0 is cancelled by the bias and vdr2 is masked
+	sampled, err := s.SampleBiased(2, func(vdrID ids.ShortID) float64 {
+		if vdrID == vdr0 {
+			return 0
+		}
+		return 2
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 2, "should have sampled two validators")
+	assert.Equal(t, vdr1, sampled[0].ID(), "should have sampled vdr1")
+	assert.Equal(t, vdr1, sampled[1].ID(), "should have sampled vdr1")
+
+	_, err = s.SampleBiased(3, func(ids.ShortID) float64 { return 1 })
+	assert.Error(t, err, "should have errored during sampling")
+
+	_, err = s.SampleBiased(1, func(ids.ShortID) float64 { return -1 })
+	assert.Error(t, err, "should have errored on a negative bias")
+
+	assert.NoError(t, s.RevealValidator(vdr2))
+	_, err = s.SampleBiased(1, func(ids.ShortID) float64 { return math.MaxUint32 })
+	assert.Error(t, err, "should have errored on an overflowing weight")
+}
+
 func TestSamplerDuplicate(t *testing.T) {
 	vdr0 := ids.GenerateTestShortID()
 	vdr1 := ids.GenerateTestShortID()
@@ -30,6 +30,9 @@ const (
 	GetAncestorsMsg
 	MultiPutMsg
 	GetAncestorsFailedMsg
+	GetStateSummaryMsg
+	StateSummaryMsg
+	GetStateSummaryFailedMsg
 	TimeoutMsg
 )
 
@@ -55,6 +58,12 @@ func (t MsgType) String() string {
 		return "Get Ancestors"
 	case GetAncestorsFailedMsg:
 		return "Get Ancestors Failed"
+	case GetStateSummaryMsg:
+		return "Get State Summary"
+	case StateSummaryMsg:
+		return "State Summary"
+	case GetStateSummaryFailedMsg:
+		return "Get State Summary Failed"
 	case TimeoutMsg:
 		return "Timeout"
 	case PutMsg:
@@ -0,0 +1,96 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDNSTTL = 300
+
+	// Timeout of a single request to a DNS provider
+	dnsUpdateTimeout = 10 * time.Second
+)
+
+var (
+	errUnknownDNSProvider = errors.New("unknown dynamic DNS provider")
+	errNoHostname         = errors.New("dynamic DNS hostname is empty")
+	errMissingCredentials = errors.New("dynamic DNS credentials are missing")
+)
+
+// DNSUpdater points a hostname at this node's public IP
+type DNSUpdater interface {
+	// Update the record of the hostname to [ip]. The record is an A record if
+	// [ip] is an IPv4 address, and an AAAA record otherwise.
+	Update(ip net.IP) error
+}
+
+// DNSConfig describes how the hostname of this node is updated when its
+// public IP changes. It holds credentials, so it's read from a file rather
+// than passed on the command line.
+type DNSConfig struct {
+	// Provider is "rfc2136", "cloudflare" or "route53"
+	Provider string `json:"provider"`
+	// Hostname that points at this node
+	Hostname string `json:"hostname"`
+	// TTL of the record in seconds. Defaults to 5 minutes.
+	TTL uint32 `json:"ttl"`
+
+	RFC2136    RFC2136Config    `json:"rfc2136"`
+	Cloudflare CloudflareConfig `json:"cloudflare"`
+	Route53    Route53Config    `json:"route53"`
+}
+
+// LoadDNSConfig reads the JSON encoded DNSConfig at [path]
+func LoadDNSConfig(path string) (DNSConfig, error) {
+	configBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return DNSConfig{}, fmt.Errorf("couldn't read dynamic DNS config: %w", err)
+	}
+	config := DNSConfig{}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return DNSConfig{}, fmt.Errorf("couldn't parse dynamic DNS config: %w", err)
+	}
+	return config, nil
+}
+
+// NewDNSUpdater returns the DNSUpdater described by [config]. If [proxyURL]
+// isn't nil, the providers with an HTTP API are called through that proxy.
+// RFC 2136 updates don't use the proxy.
+func NewDNSUpdater(config DNSConfig, proxyURL *url.URL) (DNSUpdater, error) {
+	config.Hostname = strings.TrimSuffix(config.Hostname, ".")
+	if config.Hostname == "" {
+		return nil, errNoHostname
+	}
+	if config.TTL == 0 {
+		config.TTL = defaultDNSTTL
+	}
+
+	switch config.Provider {
+	case "rfc2136":
+		return newRFC2136Updater(config.Hostname, config.TTL, config.RFC2136)
+	case "cloudflare":
+		return newCloudflareUpdater(config.Hostname, config.TTL, config.Cloudflare, proxyURL)
+	case "route53":
+		return newRoute53Updater(config.Hostname, config.TTL, config.Route53, proxyURL)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownDNSProvider, config.Provider)
+	}
+}
+
+// recordType returns the type of the record that points at [ip]
+func recordType(ip net.IP) string {
+	if ip.To4() != nil {
+		return "A"
+	}
+	return "AAAA"
+}
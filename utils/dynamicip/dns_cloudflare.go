@@ -0,0 +1,130 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const cloudflareEndpoint = "https://api.cloudflare.com/client/v4"
+
+// CloudflareConfig are the credentials used to update a record in a
+// Cloudflare zone
+type CloudflareConfig struct {
+	// ID of the zone the hostname is in
+	ZoneID string `json:"zoneID"`
+	// API token with permission to edit the zone's DNS records
+	APIToken string `json:"apiToken"`
+}
+
+type cloudflareUpdater struct {
+	hostname string
+	ttl      uint32
+	config   CloudflareConfig
+	endpoint string
+	client   http.Client
+}
+
+func newCloudflareUpdater(hostname string, ttl uint32, config CloudflareConfig, proxyURL *url.URL) (*cloudflareUpdater, error) {
+	if config.ZoneID == "" || config.APIToken == "" {
+		return nil, fmt.Errorf("%w: cloudflare requires a zone ID and an API token", errMissingCredentials)
+	}
+	return &cloudflareUpdater{
+		hostname: hostname,
+		ttl:      ttl,
+		config:   config,
+		endpoint: cloudflareEndpoint,
+		client:   newHTTPClient(dnsUpdateTimeout, proxyURL),
+	}, nil
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     uint32 `json:"ttl"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+// Update implements the DNSUpdater interface. The record is created if it
+// doesn't exist yet.
+func (u *cloudflareUpdater) Update(ip net.IP) error {
+	record := cloudflareRecord{
+		Type:    recordType(ip),
+		Name:    u.hostname,
+		Content: ip.String(),
+		TTL:     u.ttl,
+	}
+
+	query := url.Values{}
+	query.Set("type", record.Type)
+	query.Set("name", record.Name)
+	var existing []cloudflareRecord
+	if err := u.do(http.MethodGet, "/dns_records?"+query.Encode(), nil, &existing); err != nil {
+		return err
+	}
+
+	if len(existing) == 0 {
+		return u.do(http.MethodPost, "/dns_records", &record, nil)
+	}
+	return u.do(http.MethodPut, "/dns_records/"+url.PathEscape(existing[0].ID), &record, nil)
+}
+
+// do sends a request to [path] of the zone's API, and unmarshals the result
+// of the response into [result] if it isn't nil
+func (u *cloudflareUpdater) do(method, path string, body, result interface{}) error {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	reqURL := fmt.Sprintf("%s/zones/%s%s", u.endpoint, url.PathEscape(u.config.ZoneID), path)
+	req, err := http.NewRequest(method, reqURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+u.config.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudflare request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response := cloudflareResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("couldn't parse cloudflare response with status %d: %w", resp.StatusCode, err)
+	}
+	if !response.Success {
+		errs := make([]string, len(response.Errors))
+		for i, err := range response.Errors {
+			errs[i] = fmt.Sprintf("%d: %s", err.Code, err.Message)
+		}
+		return fmt.Errorf("cloudflare request failed with status %d: %s", resp.StatusCode, strings.Join(errs, "; "))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,286 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNS constants used by dynamic updates. See RFC 1035, RFC 2136 and RFC 8945.
+const (
+	dnsOpcodeUpdate = 5
+	dnsTypeA        = 1
+	dnsTypeSOA      = 6
+	dnsTypeAAAA     = 28
+	dnsTypeTSIG     = 250
+	dnsClassIN      = 1
+	dnsClassANY     = 255
+	dnsHeaderLen    = 12
+
+	// The only TSIG algorithm supported
+	tsigAlgorithm = "hmac-sha256"
+	// Seconds the clocks of this node and the DNS server may differ by
+	tsigFudge = 300
+)
+
+var (
+	errBadDNSName     = errors.New("invalid DNS name")
+	errBadDNSResponse = errors.New("invalid DNS response")
+
+	dnsRcodes = map[uint16]string{
+		1:  "FORMERR",
+		2:  "SERVFAIL",
+		3:  "NXDOMAIN",
+		4:  "NOTIMP",
+		5:  "REFUSED",
+		6:  "YXDOMAIN",
+		7:  "YXRRSET",
+		8:  "NXRRSET",
+		9:  "NOTAUTH",
+		10: "NOTZONE",
+	}
+)
+
+// RFC2136Config are the credentials used to update a record with a dynamic
+// DNS update, signed with a TSIG key
+type RFC2136Config struct {
+	// Address of the primary DNS server of the zone, e.g. "ns1.example.com:53"
+	Server string `json:"server"`
+	// Zone the hostname is in, e.g. "example.com"
+	Zone string `json:"zone"`
+	// Name of the TSIG key. The key's algorithm must be hmac-sha256.
+	KeyName string `json:"keyName"`
+	// Base64 encoded secret of the TSIG key
+	KeySecret string `json:"keySecret"`
+}
+
+type rfc2136Updater struct {
+	hostname string
+	ttl      uint32
+	config   RFC2136Config
+	secret   []byte
+	now      func() time.Time
+}
+
+func newRFC2136Updater(hostname string, ttl uint32, config RFC2136Config) (*rfc2136Updater, error) {
+	if config.Server == "" || config.Zone == "" || config.KeyName == "" || config.KeySecret == "" {
+		return nil, fmt.Errorf("%w: rfc2136 requires a server, a zone and a TSIG key", errMissingCredentials)
+	}
+	secret, err := base64.StdEncoding.DecodeString(config.KeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode TSIG key secret: %w", err)
+	}
+	if _, _, err := net.SplitHostPort(config.Server); err != nil {
+		config.Server = net.JoinHostPort(config.Server, "53")
+	}
+	return &rfc2136Updater{
+		hostname: hostname,
+		ttl:      ttl,
+		config:   config,
+		secret:   secret,
+		now:      time.Now,
+	}, nil
+}
+
+// Update implements the DNSUpdater interface. The records of the hostname of
+// the same type as [ip] are replaced by one that points at [ip].
+func (u *rfc2136Updater) Update(ip net.IP) error {
+	idBytes := [2]byte{}
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return err
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+
+	msg, err := buildDNSUpdate(id, u.config.Zone, u.hostname, u.ttl, ip)
+	if err != nil {
+		return err
+	}
+	msg, err = signTSIG(msg, u.config.KeyName, u.secret, u.now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", u.config.Server, dnsUpdateTimeout)
+	if err != nil {
+		return fmt.Errorf("couldn't connect to DNS server: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(dnsUpdateTimeout)); err != nil {
+		return err
+	}
+
+	// Over TCP, DNS messages are prefixed by their length
+	lenBytes := [2]byte{}
+	binary.BigEndian.PutUint16(lenBytes[:], uint16(len(msg)))
+	if _, err := conn.Write(append(lenBytes[:], msg...)); err != nil {
+		return fmt.Errorf("couldn't send DNS update: %w", err)
+	}
+	if _, err := io.ReadFull(conn, lenBytes[:]); err != nil {
+		return fmt.Errorf("couldn't read DNS response: %w", err)
+	}
+	resp := make([]byte, binary.BigEndian.Uint16(lenBytes[:]))
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("couldn't read DNS response: %w", err)
+	}
+	return checkDNSResponse(resp, id)
+}
+
+// buildDNSUpdate returns an unsigned DNS update message that replaces the
+// records of [hostname], in [zone], of the type of [ip] by one that points
+// at [ip]
+func buildDNSUpdate(id uint16, zone, hostname string, ttl uint32, ip net.IP) ([]byte, error) {
+	rrType := uint16(dnsTypeAAAA)
+	rdata := ip.To16()
+	if ip4 := ip.To4(); ip4 != nil {
+		rrType = dnsTypeA
+		rdata = ip4
+	}
+
+	msg := make([]byte, dnsHeaderLen, 512)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], dnsOpcodeUpdate<<11)
+	binary.BigEndian.PutUint16(msg[4:], 1)  // Zone count
+	binary.BigEndian.PutUint16(msg[6:], 0)  // Prerequisite count
+	binary.BigEndian.PutUint16(msg[8:], 2)  // Update count
+	binary.BigEndian.PutUint16(msg[10:], 0) // Additional count
+
+	// Zone section
+	msg, err := appendDNSName(msg, zone)
+	if err != nil {
+		return nil, err
+	}
+	msg = appendUint16(msg, dnsTypeSOA)
+	msg = appendUint16(msg, dnsClassIN)
+
+	// Delete the records of the type...
+	msg, err = appendDNSName(msg, hostname)
+	if err != nil {
+		return nil, err
+	}
+	msg = appendUint16(msg, rrType)
+	msg = appendUint16(msg, dnsClassANY)
+	msg = appendUint32(msg, 0) // TTL
+	msg = appendUint16(msg, 0) // RDATA length
+
+	// ...and add the new one
+	msg, err = appendDNSName(msg, hostname)
+	if err != nil {
+		return nil, err
+	}
+	msg = appendUint16(msg, rrType)
+	msg = appendUint16(msg, dnsClassIN)
+	msg = appendUint32(msg, ttl)
+	msg = appendUint16(msg, uint16(len(rdata)))
+	msg = append(msg, rdata...)
+	return msg, nil
+}
+
+// signTSIG returns [msg] with a TSIG record, signed with the hmac-sha256 key
+// named [keyName], appended
+func signTSIG(msg []byte, keyName string, secret []byte, now time.Time) ([]byte, error) {
+	keyNameBytes, err := appendDNSName(nil, keyName)
+	if err != nil {
+		return nil, err
+	}
+	algorithmBytes, err := appendDNSName(nil, tsigAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	timeSigned := uint64(now.Unix())
+
+	// The MAC covers the message and the TSIG variables
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(msg)
+	_, _ = mac.Write(keyNameBytes)
+	_, _ = mac.Write(appendUint32(appendUint16(nil, dnsClassANY), 0))
+	_, _ = mac.Write(algorithmBytes)
+	_, _ = mac.Write(appendTSIGTime(nil, timeSigned))
+	_, _ = mac.Write(appendUint16(nil, tsigFudge))
+	_, _ = mac.Write(appendUint16(nil, 0)) // Error
+	_, _ = mac.Write(appendUint16(nil, 0)) // Other length
+	macBytes := mac.Sum(nil)
+
+	rdata := append([]byte(nil), algorithmBytes...)
+	rdata = appendTSIGTime(rdata, timeSigned)
+	rdata = appendUint16(rdata, tsigFudge)
+	rdata = appendUint16(rdata, uint16(len(macBytes)))
+	rdata = append(rdata, macBytes...)
+	rdata = append(rdata, msg[0:2]...) // Original ID
+	rdata = appendUint16(rdata, 0)     // Error
+	rdata = appendUint16(rdata, 0)     // Other length
+
+	signed := append([]byte(nil), msg...)
+	signed = append(signed, keyNameBytes...)
+	signed = appendUint16(signed, dnsTypeTSIG)
+	signed = appendUint16(signed, dnsClassANY)
+	signed = appendUint32(signed, 0) // TTL
+	signed = appendUint16(signed, uint16(len(rdata)))
+	signed = append(signed, rdata...)
+
+	additionalCount := binary.BigEndian.Uint16(signed[10:])
+	binary.BigEndian.PutUint16(signed[10:], additionalCount+1)
+	return signed, nil
+}
+
+// checkDNSResponse returns an error if [resp] isn't a successful response to
+// the message with [id]
+func checkDNSResponse(resp []byte, id uint16) error {
+	if len(resp) < dnsHeaderLen {
+		return fmt.Errorf("%w: %d bytes", errBadDNSResponse, len(resp))
+	}
+	if respID := binary.BigEndian.Uint16(resp[0:]); respID != id {
+		return fmt.Errorf("%w: ID %d doesn't match %d", errBadDNSResponse, respID, id)
+	}
+	flags := binary.BigEndian.Uint16(resp[2:])
+	if flags&(1<<15) == 0 {
+		return fmt.Errorf("%w: not a response", errBadDNSResponse)
+	}
+	if rcode := flags & 0xF; rcode != 0 {
+		name, ok := dnsRcodes[rcode]
+		if !ok {
+			name = fmt.Sprintf("rcode %d", rcode)
+		}
+		return fmt.Errorf("DNS update failed with %s", name)
+	}
+	return nil
+}
+
+// appendDNSName appends [name], lower cased, in DNS wire format
+func appendDNSName(b []byte, name string) ([]byte, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" || len(name) > 253 {
+		return nil, fmt.Errorf("%w: %q", errBadDNSName, name)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("%w: %q", errBadDNSName, name)
+		}
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0), nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// appendTSIGTime appends the 48 bit time of a TSIG record
+func appendTSIGTime(b []byte, v uint64) []byte {
+	return append(b, byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
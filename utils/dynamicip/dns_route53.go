@@ -0,0 +1,205 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	route53Endpoint = "https://route53.amazonaws.com"
+	// Route53 is a global service whose requests are signed for us-east-1
+	route53Region  = "us-east-1"
+	route53Service = "route53"
+	route53XMLNS   = "https://route53.amazonaws.com/doc/2013-04-01/"
+)
+
+// Route53Config are the credentials used to update a record in a Route53
+// hosted zone
+type Route53Config struct {
+	// ID of the hosted zone the hostname is in
+	HostedZoneID string `json:"hostedZoneID"`
+	// Access key of an IAM user allowed to change the zone's record sets
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+}
+
+type route53Updater struct {
+	hostname string
+	ttl      uint32
+	config   Route53Config
+	endpoint string
+	client   http.Client
+	now      func() time.Time
+}
+
+func newRoute53Updater(hostname string, ttl uint32, config Route53Config, proxyURL *url.URL) (*route53Updater, error) {
+	if config.HostedZoneID == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("%w: route53 requires a hosted zone ID and an access key", errMissingCredentials)
+	}
+	return &route53Updater{
+		hostname: hostname,
+		ttl:      ttl,
+		config:   config,
+		endpoint: route53Endpoint,
+		client:   newHTTPClient(dnsUpdateTimeout, proxyURL),
+		now:      time.Now,
+	}, nil
+}
+
+type route53ChangeRequest struct {
+	XMLName xml.Name        `xml:"ChangeResourceRecordSetsRequest"`
+	XMLNS   string          `xml:"xmlns,attr"`
+	Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type route53Change struct {
+	Action            string           `xml:"Action"`
+	ResourceRecordSet route53RecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53RecordSet struct {
+	Name   string   `xml:"Name"`
+	Type   string   `xml:"Type"`
+	TTL    uint32   `xml:"TTL"`
+	Values []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+// Update implements the DNSUpdater interface. The record is created if it
+// doesn't exist yet.
+func (u *route53Updater) Update(ip net.IP) error {
+	request := route53ChangeRequest{
+		XMLNS: route53XMLNS,
+		Changes: []route53Change{{
+			Action: "UPSERT",
+			ResourceRecordSet: route53RecordSet{
+				Name:   u.hostname,
+				Type:   recordType(ip),
+				TTL:    u.ttl,
+				Values: []string{ip.String()},
+			},
+		}},
+	}
+	body, err := xml.Marshal(&request)
+	if err != nil {
+		return err
+	}
+	body = append([]byte(xml.Header), body...)
+
+	zoneID := strings.TrimPrefix(u.config.HostedZoneID, "/hostedzone/")
+	reqURL := fmt.Sprintf("%s/2013-04-01/hostedzone/%s/rrset/", u.endpoint, url.PathEscape(zoneID))
+	req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	signV4(req, body, u.config.AccessKeyID, u.config.SecretAccessKey, route53Region, route53Service, u.now())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("route53 request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Route53 describes the error in the body
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("route53 request failed with status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// signV4 signs [req], whose body is [body], with AWS Signature Version 4.
+// The host and X-Amz-Date headers are signed.
+func signV4(req *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		"host:" + host + "\n" + "x-amz-date:" + amzDate + "\n",
+		"host;x-amz-date",
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=host;x-amz-date, Signature=%s",
+		accessKeyID, scope, signature,
+	))
+}
+
+func canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := []string(nil)
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// awsEscape percent-encodes every byte of [s] other than the unreserved
+// characters of RFC 3986, as AWS requires
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,350 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestNewDNSUpdater(t *testing.T) {
+	_, err := NewDNSUpdater(DNSConfig{Provider: "cloudflare"}, nil)
+	assert.True(t, errors.Is(err, errNoHostname))
+
+	_, err = NewDNSUpdater(DNSConfig{Provider: "unknown", Hostname: "node.example.com"}, nil)
+	assert.True(t, errors.Is(err, errUnknownDNSProvider))
+
+	_, err = NewDNSUpdater(DNSConfig{Provider: "cloudflare", Hostname: "node.example.com"}, nil)
+	assert.True(t, errors.Is(err, errMissingCredentials))
+
+	_, err = NewDNSUpdater(DNSConfig{Provider: "route53", Hostname: "node.example.com"}, nil)
+	assert.True(t, errors.Is(err, errMissingCredentials))
+
+	_, err = NewDNSUpdater(DNSConfig{Provider: "rfc2136", Hostname: "node.example.com"}, nil)
+	assert.True(t, errors.Is(err, errMissingCredentials))
+
+	_, err = NewDNSUpdater(DNSConfig{
+		Provider: "rfc2136",
+		Hostname: "node.example.com",
+		RFC2136: RFC2136Config{
+			Server:    "ns1.example.com",
+			Zone:      "example.com",
+			KeyName:   "key",
+			KeySecret: "not base64!",
+		},
+	}, nil)
+	assert.Error(t, err)
+
+	updater, err := NewDNSUpdater(DNSConfig{
+		Provider: "rfc2136",
+		Hostname: "node.example.com.",
+		RFC2136: RFC2136Config{
+			Server:    "ns1.example.com",
+			Zone:      "example.com",
+			KeyName:   "key",
+			KeySecret: base64.StdEncoding.EncodeToString([]byte("secret")),
+		},
+	}, nil)
+	assert.NoError(t, err)
+	rfc2136 := updater.(*rfc2136Updater)
+	assert.Equal(t, "node.example.com", rfc2136.hostname)
+	assert.Equal(t, uint32(defaultDNSTTL), rfc2136.ttl)
+	assert.Equal(t, "ns1.example.com:53", rfc2136.config.Server)
+}
+
+func TestCloudflareUpdater(t *testing.T) {
+	var records []cloudflareRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var result interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones/zone/dns_records":
+			assert.Equal(t, "node.example.com", r.URL.Query().Get("name"))
+			result = records
+		case r.Method == http.MethodPost && r.URL.Path == "/zones/zone/dns_records":
+			record := cloudflareRecord{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+			record.ID = "record"
+			records = append(records, record)
+			result = record
+		case r.Method == http.MethodPut && r.URL.Path == "/zones/zone/dns_records/record":
+			record := cloudflareRecord{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+			records[0].Content = record.Content
+			result = record
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":7003,"message":"not found"}]}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"result":  result,
+		})
+	}))
+	defer server.Close()
+
+	updater, err := newCloudflareUpdater("node.example.com", 60, CloudflareConfig{ZoneID: "zone", APIToken: "token"}, nil)
+	assert.NoError(t, err)
+	updater.endpoint = server.URL
+
+	// The record is created...
+	assert.NoError(t, updater.Update(net.ParseIP("1.2.3.4")))
+	assert.Len(t, records, 1)
+	assert.Equal(t, "A", records[0].Type)
+	assert.Equal(t, "1.2.3.4", records[0].Content)
+	assert.Equal(t, uint32(60), records[0].TTL)
+
+	// ...then updated
+	assert.NoError(t, updater.Update(net.ParseIP("5.6.7.8")))
+	assert.Len(t, records, 1)
+	assert.Equal(t, "5.6.7.8", records[0].Content)
+
+	updater.config.ZoneID = "other"
+	err = updater.Update(net.ParseIP("5.6.7.8"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestRoute53Updater(t *testing.T) {
+	var request route53ChangeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/2013-04-01/hostedzone/ZONE/rrset/", r.URL.Path)
+		assert.Equal(t, "20210102T030405Z", r.Header.Get("X-Amz-Date"))
+		assert.True(t, strings.HasPrefix(
+			r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20210102/us-east-1/route53/aws4_request, SignedHeaders=host;x-amz-date, Signature=",
+		))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, xml.Unmarshal(body, &request))
+		_, _ = w.Write([]byte("<ChangeResourceRecordSetsResponse/>"))
+	}))
+	defer server.Close()
+
+	updater, err := newRoute53Updater("node.example.com", 60, Route53Config{
+		HostedZoneID:    "/hostedzone/ZONE",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	}, nil)
+	assert.NoError(t, err)
+	updater.endpoint = server.URL
+	updater.now = func() time.Time { return time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	assert.NoError(t, updater.Update(net.ParseIP("2001:db8::1")))
+	assert.Len(t, request.Changes, 1)
+	change := request.Changes[0]
+	assert.Equal(t, "UPSERT", change.Action)
+	assert.Equal(t, "node.example.com", change.ResourceRecordSet.Name)
+	assert.Equal(t, "AAAA", change.ResourceRecordSet.Type)
+	assert.Equal(t, uint32(60), change.ResourceRecordSet.TTL)
+	assert.Equal(t, []string{"2001:db8::1"}, change.ResourceRecordSet.Values)
+}
+
+// Test vector from the AWS Signature Version 4 test suite
+func TestSignV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	assert.NoError(t, err)
+	signV4(
+		req,
+		nil,
+		"AKIDEXAMPLE",
+		"wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		"us-east-1",
+		"service",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC),
+	)
+	assert.Equal(
+		t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"),
+	)
+}
+
+func TestBuildDNSUpdate(t *testing.T) {
+	msg, err := buildDNSUpdate(0x1234, "example.com.", "Node.example.com", 60, net.ParseIP("1.2.3.4"))
+	assert.NoError(t, err)
+
+	expected := []byte{
+		0x12, 0x34, // ID
+		0x28, 0x00, // Opcode UPDATE
+		0, 1, 0, 0, 0, 2, 0, 0, // Section counts
+		// Zone
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0, dnsTypeSOA, 0, dnsClassIN,
+		// Delete the A records
+		4, 'n', 'o', 'd', 'e', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0, dnsTypeA, 0, dnsClassANY, 0, 0, 0, 0, 0, 0,
+		// Add the A record
+		4, 'n', 'o', 'd', 'e', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0, dnsTypeA, 0, dnsClassIN, 0, 0, 0, 60, 0, 4, 1, 2, 3, 4,
+	}
+	assert.Equal(t, expected, msg)
+
+	msg, err = buildDNSUpdate(0x1234, "example.com", "node.example.com", 60, net.ParseIP("2001:db8::1"))
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("2001:db8::1").To16(), net.IP(msg[len(msg)-16:]))
+
+	_, err = buildDNSUpdate(0x1234, "example.com", "node..example.com", 60, net.ParseIP("1.2.3.4"))
+	assert.True(t, errors.Is(err, errBadDNSName))
+}
+
+func TestRFC2136Updater(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1609556645, 0)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	rcodes := make(chan uint16, 2)
+	rcodes <- 0
+	rcodes <- 5 // REFUSED
+	close(rcodes)
+	go func() {
+		for rcode := range rcodes {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			lenBytes := [2]byte{}
+			_, err = io.ReadFull(conn, lenBytes[:])
+			assert.NoError(t, err)
+			msg := make([]byte, binary.BigEndian.Uint16(lenBytes[:]))
+			_, err = io.ReadFull(conn, msg)
+			assert.NoError(t, err)
+
+			// The message is the update followed by a TSIG record
+			id := binary.BigEndian.Uint16(msg)
+			assert.Equal(t, uint16(1), binary.BigEndian.Uint16(msg[10:]))
+			unsigned, err := buildDNSUpdate(id, "example.com", "node.example.com", 60, net.ParseIP("1.2.3.4"))
+			assert.NoError(t, err)
+			assert.Equal(t, unsigned[12:], msg[12:len(unsigned)])
+
+			// Check the MAC
+			tsig := msg[len(unsigned):]
+			keyName := []byte{3, 'k', 'e', 'y', 0}
+			algorithm := []byte{11, 'h', 'm', 'a', 'c', '-', 's', 'h', 'a', '2', '5', '6', 0}
+			assert.Equal(t, keyName, tsig[:len(keyName)])
+			rdata := tsig[len(keyName)+10:]
+			assert.Equal(t, algorithm, rdata[:len(algorithm)])
+			variables := rdata[len(algorithm) : len(algorithm)+8] // Time signed and fudge
+			macSize := binary.BigEndian.Uint16(rdata[len(algorithm)+8:])
+			receivedMAC := rdata[len(algorithm)+10 : len(algorithm)+10+int(macSize)]
+
+			mac := hmac.New(sha256.New, secret)
+			_, _ = mac.Write(unsigned)
+			_, _ = mac.Write(keyName)
+			_, _ = mac.Write([]byte{0, dnsClassANY, 0, 0, 0, 0})
+			_, _ = mac.Write(algorithm)
+			_, _ = mac.Write(variables)
+			_, _ = mac.Write([]byte{0, 0, 0, 0})
+			assert.Equal(t, mac.Sum(nil), receivedMAC)
+
+			resp := make([]byte, dnsHeaderLen)
+			binary.BigEndian.PutUint16(resp, id)
+			binary.BigEndian.PutUint16(resp[2:], 1<<15|dnsOpcodeUpdate<<11|rcode)
+			binary.BigEndian.PutUint16(lenBytes[:], uint16(len(resp)))
+			_, _ = conn.Write(append(lenBytes[:], resp...))
+			conn.Close()
+		}
+	}()
+
+	updater, err := newRFC2136Updater("node.example.com", 60, RFC2136Config{
+		Server:    listener.Addr().String(),
+		Zone:      "example.com",
+		KeyName:   "Key.",
+		KeySecret: base64.StdEncoding.EncodeToString(secret),
+	})
+	assert.NoError(t, err)
+	updater.now = func() time.Time { return now }
+
+	assert.NoError(t, updater.Update(net.ParseIP("1.2.3.4")))
+	err = updater.Update(net.ParseIP("1.2.3.4"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "REFUSED")
+}
+
+func TestCheckDNSResponse(t *testing.T) {
+	assert.True(t, errors.Is(checkDNSResponse([]byte{0, 1}, 1), errBadDNSResponse))
+
+	resp := make([]byte, dnsHeaderLen)
+	binary.BigEndian.PutUint16(resp, 1)
+	binary.BigEndian.PutUint16(resp[2:], 1<<15)
+	assert.NoError(t, checkDNSResponse(resp, 1))
+	assert.True(t, errors.Is(checkDNSResponse(resp, 2), errBadDNSResponse))
+
+	binary.BigEndian.PutUint16(resp[2:], 0)
+	assert.True(t, errors.Is(checkDNSResponse(resp, 1), errBadDNSResponse))
+}
+
+type testDNSUpdater struct {
+	failures int
+	updates  []net.IP
+}
+
+func (u *testDNSUpdater) Update(ip net.IP) error {
+	if u.failures > 0 {
+		u.failures--
+		return errors.New("unreachable")
+	}
+	u.updates = append(u.updates, ip)
+	return nil
+}
+
+func TestDynamicIPUpdateDNS(t *testing.T) {
+	ipDesc := utils.NewDynamicIPDesc(net.ParseIP("1.2.3.4"), 9651)
+	dnsUpdater := &testDNSUpdater{failures: 2}
+	dynamicIP := &DynamicIP{
+		DynamicIPDesc: &ipDesc,
+		tickerCloser:  make(chan struct{}),
+		log:           logging.NoLog{},
+		dnsUpdater:    dnsUpdater,
+		dnsRetryDelay: time.Millisecond,
+	}
+
+	// Failed updates are retried
+	dynamicIP.updateDNS()
+	assert.Len(t, dnsUpdater.updates, 1)
+	assert.True(t, net.ParseIP("1.2.3.4").Equal(dnsUpdater.updates[0]))
+
+	// The record isn't updated if the IP didn't change
+	dynamicIP.updateDNS()
+	assert.Len(t, dnsUpdater.updates, 1)
+
+	dynamicIP.update(&StaticResolver{IP: net.ParseIP("5.6.7.8")})
+	dynamicIP.updateDNS()
+	assert.Len(t, dnsUpdater.updates, 2)
+	assert.True(t, net.ParseIP("5.6.7.8").Equal(dnsUpdater.updates[1]))
+
+	// After too many failures, the update is tried again on the next call
+	dnsUpdater.failures = maxDNSUpdateAttempts
+	dynamicIP.update(&StaticResolver{IP: net.ParseIP("9.9.9.9")})
+	dynamicIP.updateDNS()
+	assert.Len(t, dnsUpdater.updates, 2)
+	dynamicIP.updateDNS()
+	assert.Len(t, dnsUpdater.updates, 3)
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -14,7 +15,23 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
-var errOpenDNSNoIP = errors.New("opendns returned no ip")
+const (
+	resolveTimeout = 10 * time.Second
+
+	// Delay before the first retry of a failed DNS update. The delay doubles
+	// after every attempt, up to [maxDNSRetryDelay].
+	initialDNSRetryDelay = time.Second
+	maxDNSRetryDelay     = time.Minute
+	// Number of attempts made to update the DNS record to an IP before
+	// waiting for the next update of the IP
+	maxDNSUpdateAttempts = 5
+)
+
+var (
+	errOpenDNSNoIP     = errors.New("opendns returned no ip")
+	errNoResolver      = errors.New("no resolver")
+	errUnknownResolver = errors.New("unknown resolver")
+)
 
 // Resolver resolves our public IP
 type Resolver interface {
@@ -32,10 +49,24 @@ func (r *NoResolver) IsResolver() bool {
 }
 
 func (r *NoResolver) Resolve() (net.IP, error) {
-	return nil, errors.New("invalid resolver")
+	return nil, errNoResolver
+}
+
+// StaticResolver always resolves our public IP to the same IP. Useful when the
+// IP is known, and in tests.
+type StaticResolver struct {
+	IP net.IP
+}
+
+func (r *StaticResolver) IsResolver() bool {
+	return true
+}
+
+func (r *StaticResolver) Resolve() (net.IP, error) {
+	return r.IP, nil
 }
 
-// IFConfigResolves resolves our public IP using openDNS
+// OpenDNSResolver resolves our public IP using openDNS
 type OpenDNSResolver struct {
 	*net.Resolver
 }
@@ -45,7 +76,7 @@ func NewOpenDNSResolver() *OpenDNSResolver {
 		PreferGo: true,
 		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
 			d := net.Dialer{
-				Timeout: 10 * time.Second,
+				Timeout: resolveTimeout,
 			}
 			return d.DialContext(ctx, "udp", "resolver1.opendns.com:53")
 		},
@@ -57,7 +88,10 @@ func (r *OpenDNSResolver) IsResolver() bool {
 }
 
 func (r *OpenDNSResolver) Resolve() (net.IP, error) {
-	ip, err := r.Resolver.LookupHost(context.Background(), "myip.opendns.com")
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	ip, err := r.Resolver.LookupHost(ctx, "myip.opendns.com")
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +108,23 @@ func (r *OpenDNSResolver) Resolve() (net.IP, error) {
 	if ipResolved == nil {
 		return nil, fmt.Errorf("invalid ip %s", ip[0])
 	}
-	return net.ParseIP(ip[0]), nil
+	return ipResolved, nil
 }
 
 // IFConfigResolves resolves our public IP using ifconfig's format
 type IFConfigResolver struct {
-	url string
+	url    string
+	client http.Client
+}
+
+// NewIFConfigResolver returns a resolver that fetches our public IP from
+// [resolverURL], which must respond with the IP as plain text. If [proxyURL]
+// isn't nil, the IP is fetched through that proxy.
+func NewIFConfigResolver(resolverURL string, proxyURL *url.URL) *IFConfigResolver {
+	return &IFConfigResolver{
+		url:    resolverURL,
+		client: newHTTPClient(resolveTimeout, proxyURL),
+	}
 }
 
 func (r *IFConfigResolver) IsResolver() bool {
@@ -87,7 +132,7 @@ func (r *IFConfigResolver) IsResolver() bool {
 }
 
 func (r *IFConfigResolver) Resolve() (net.IP, error) {
-	resp, err := http.Get(r.url)
+	resp, err := r.client.Get(r.url)
 	if err != nil {
 		return nil, err
 	}
@@ -97,10 +142,7 @@ func (r *IFConfigResolver) Resolve() (net.IP, error) {
 		_ = resp.Body.Close()
 		return nil, fmt.Errorf("failed to read response from %q: %w", r.url, err)
 	}
-	ipStr := string(ip)
-	ipStr = strings.ReplaceAll(ipStr, "\r\n", "")
-	ipStr = strings.ReplaceAll(ipStr, "\r", "")
-	ipStr = strings.ReplaceAll(ipStr, "\n", "")
+	ipStr := strings.TrimSpace(string(ip))
 	ipResolved := net.ParseIP(ipStr)
 	if ipResolved == nil {
 		// Drop any error to report the original error
@@ -110,19 +152,102 @@ func (r *IFConfigResolver) Resolve() (net.IP, error) {
 	return ipResolved, resp.Body.Close()
 }
 
+// newHTTPClient returns a client whose requests time out after [timeout].
+// If [proxyURL] isn't nil, the requests go through that proxy.
+func newHTTPClient(timeout time.Duration, proxyURL *url.URL) http.Client {
+	client := http.Client{Timeout: timeout}
+	if proxyURL != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		client.Transport = transport
+	}
+	return client
+}
+
+// FallbackResolver resolves our public IP with the first of its resolvers
+// that succeeds, so that one service being down doesn't prevent the IP from
+// being resolved
+type FallbackResolver struct {
+	resolvers []Resolver
+}
+
+// NewFallbackResolver returns a resolver that tries [resolvers] in order
+func NewFallbackResolver(resolvers ...Resolver) *FallbackResolver {
+	return &FallbackResolver{resolvers: resolvers}
+}
+
+func (r *FallbackResolver) IsResolver() bool {
+	return len(r.resolvers) > 0
+}
+
+func (r *FallbackResolver) Resolve() (net.IP, error) {
+	errs := make([]string, 0, len(r.resolvers))
+	for _, resolver := range r.resolvers {
+		ip, err := resolver.Resolve()
+		if err == nil {
+			return ip, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return nil, errNoResolver
+	}
+	return nil, fmt.Errorf("all resolvers failed: %s", strings.Join(errs, "; "))
+}
+
+// NewResolver returns the resolver named [opt]. If [opt] isn't the name of a
+// resolver, the returned resolver doesn't resolve our public IP.
 func NewResolver(opt string) Resolver {
-	switch opt {
+	resolver, err := newResolver(opt, nil)
+	if err != nil {
+		return &NoResolver{}
+	}
+	return resolver
+}
+
+// ParseResolver returns the resolver described by [str], a comma separated
+// list of resolver names that are tried in order. A resolver name is one of
+// "ifconfigco" (alias "ifconfig"), "ifconfigme", "opendns", or "static:<ip>".
+// If [str] is empty, the returned resolver doesn't resolve our public IP.
+// If [proxyURL] isn't nil, the resolvers that fetch our public IP over HTTP do
+// so through that proxy. The opendns resolver doesn't use the proxy.
+func ParseResolver(str string, proxyURL *url.URL) (Resolver, error) {
+	if str == "" {
+		return &NoResolver{}, nil
+	}
+
+	names := strings.Split(str, ",")
+	resolvers := make([]Resolver, len(names))
+	for i, name := range names {
+		resolver, err := newResolver(strings.TrimSpace(name), proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		resolvers[i] = resolver
+	}
+	if len(resolvers) == 1 {
+		return resolvers[0], nil
+	}
+	return NewFallbackResolver(resolvers...), nil
+}
+
+func newResolver(name string, proxyURL *url.URL) (Resolver, error) {
+	switch name {
 	case "opendns":
-		return NewOpenDNSResolver()
-	case "ifconfig":
-		return &IFConfigResolver{url: "http://ifconfig.co"}
-	case "ifconfigco":
-		return &IFConfigResolver{url: "http://ifconfig.co"}
+		return NewOpenDNSResolver(), nil
+	case "ifconfig", "ifconfigco":
+		return NewIFConfigResolver("https://ifconfig.co", proxyURL), nil
 	case "ifconfigme":
-		return &IFConfigResolver{url: "http://ifconfig.me"}
-	default:
-		return &NoResolver{}
+		return NewIFConfigResolver("https://ifconfig.me", proxyURL), nil
+	}
+	if ipStr := strings.TrimPrefix(name, "static:"); ipStr != name {
+		ip := utils.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid static ip %q", ipStr)
+		}
+		return &StaticResolver{IP: ip}, nil
 	}
+	return nil, fmt.Errorf("%w: %q", errUnknownResolver, name)
 }
 
 func FetchExternalIP(resolver Resolver) (net.IP, error) {
@@ -139,8 +264,16 @@ func (noDynamicIP *NoDynamicIP) Stop() {}
 
 // Returns a new dynamic IP that resolves and updates [ip] to our public IP every [updateTimeout].
 // Uses [dynamicResolver] to resolve our public ip.
+// If [dnsUpdater] isn't nil, it's used to point our hostname at our public IP
+// whenever it changes.
 // Stops updating when Stop() is called.
-func NewDynamicIPManager(resolver Resolver, updateTimeout time.Duration, log logging.Logger, ip *utils.DynamicIPDesc) IPManager {
+func NewDynamicIPManager(
+	resolver Resolver,
+	updateTimeout time.Duration,
+	log logging.Logger,
+	ip *utils.DynamicIPDesc,
+	dnsUpdater DNSUpdater,
+) IPManager {
 	if resolver.IsResolver() {
 		updater := &DynamicIP{
 			DynamicIPDesc: ip,
@@ -148,6 +281,8 @@ func NewDynamicIPManager(resolver Resolver, updateTimeout time.Duration, log log
 			log:           log,
 			updateTimeout: updateTimeout,
 			resolver:      resolver,
+			dnsUpdater:    dnsUpdater,
+			dnsRetryDelay: initialDNSRetryDelay,
 		}
 		go updater.UpdateExternalIP()
 		return updater
@@ -162,6 +297,12 @@ type DynamicIP struct {
 	log           logging.Logger
 	updateTimeout time.Duration
 	resolver      Resolver
+
+	// Points our hostname at our public IP. May be nil.
+	dnsUpdater DNSUpdater
+	// Last IP our hostname was pointed at. Nil if it hasn't been yet.
+	dnsIP         net.IP
+	dnsRetryDelay time.Duration
 }
 
 func (dynamicIP *DynamicIP) Stop() {
@@ -170,6 +311,10 @@ func (dynamicIP *DynamicIP) Stop() {
 
 // Update our public IP address in a loop
 func (dynamicIP *DynamicIP) UpdateExternalIP() {
+	// Our public IP was resolved on startup, so the hostname can be pointed
+	// at it right away
+	dynamicIP.updateDNS()
+
 	timer := time.NewTimer(dynamicIP.updateTimeout)
 	defer timer.Stop()
 
@@ -177,6 +322,7 @@ func (dynamicIP *DynamicIP) UpdateExternalIP() {
 		select {
 		case <-timer.C:
 			dynamicIP.update(dynamicIP.resolver)
+			dynamicIP.updateDNS()
 			timer.Reset(dynamicIP.updateTimeout)
 		case <-dynamicIP.tickerCloser:
 			return
@@ -197,3 +343,41 @@ func (dynamicIP *DynamicIP) update(resolver Resolver) {
 		dynamicIP.log.Info("ExternalIP updated to %s", newIP)
 	}
 }
+
+// Point our hostname at our public IP, if it isn't already. Failed updates
+// are retried with exponential backoff. If every attempt fails, the update is
+// tried again after the next update of our public IP.
+func (dynamicIP *DynamicIP) updateDNS() {
+	if dynamicIP.dnsUpdater == nil {
+		return
+	}
+	ip := dynamicIP.IP().IP
+	if ip == nil || ip.IsUnspecified() || ip.Equal(dynamicIP.dnsIP) {
+		return
+	}
+
+	delay := dynamicIP.dnsRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := dynamicIP.dnsUpdater.Update(ip)
+		if err == nil {
+			dynamicIP.dnsIP = ip
+			dynamicIP.log.Info("Dynamic DNS record updated to %s", ip)
+			return
+		}
+		if attempt == maxDNSUpdateAttempts {
+			dynamicIP.log.Warn("Dynamic DNS update to %s failed after %d attempts: %s", ip, attempt, err)
+			return
+		}
+		dynamicIP.log.Debug("Dynamic DNS update to %s failed, retrying in %s: %s", ip, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-dynamicIP.tickerCloser:
+			return
+		}
+		delay *= 2
+		if delay > maxDNSRetryDelay {
+			delay = maxDNSRetryDelay
+		}
+	}
+}
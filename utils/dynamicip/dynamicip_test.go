@@ -0,0 +1,85 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResolver(t *testing.T) {
+	resolver, err := ParseResolver("", nil)
+	assert.NoError(t, err)
+	assert.False(t, resolver.IsResolver())
+
+	resolver, err = ParseResolver("ifconfigco", nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &IFConfigResolver{}, resolver)
+	assert.Equal(t, "https://ifconfig.co", resolver.(*IFConfigResolver).url)
+
+	resolver, err = ParseResolver("static:[2001:db8::1]", nil)
+	assert.NoError(t, err)
+	ip, err := resolver.Resolve()
+	assert.NoError(t, err)
+	assert.True(t, net.ParseIP("2001:db8::1").Equal(ip))
+
+	resolver, err = ParseResolver("opendns, ifconfigme,static:1.2.3.4", nil)
+	assert.NoError(t, err)
+	fallback, ok := resolver.(*FallbackResolver)
+	assert.True(t, ok)
+	assert.Len(t, fallback.resolvers, 3)
+	assert.IsType(t, &OpenDNSResolver{}, fallback.resolvers[0])
+	assert.Equal(t, "https://ifconfig.me", fallback.resolvers[1].(*IFConfigResolver).url)
+	assert.IsType(t, &StaticResolver{}, fallback.resolvers[2])
+
+	_, err = ParseResolver("ifconfigco,unknown", nil)
+	assert.True(t, errors.Is(err, errUnknownResolver))
+	_, err = ParseResolver("static:abc", nil)
+	assert.Error(t, err)
+}
+
+type failingResolver struct{}
+
+func (failingResolver) IsResolver() bool         { return true }
+func (failingResolver) Resolve() (net.IP, error) { return nil, errors.New("unreachable") }
+
+func TestFallbackResolver(t *testing.T) {
+	ip := net.ParseIP("1.2.3.4")
+	resolver := NewFallbackResolver(failingResolver{}, &StaticResolver{IP: ip}, failingResolver{})
+	assert.True(t, resolver.IsResolver())
+	resolvedIP, err := resolver.Resolve()
+	assert.NoError(t, err)
+	assert.True(t, ip.Equal(resolvedIP))
+
+	resolver = NewFallbackResolver(failingResolver{}, failingResolver{})
+	_, err = resolver.Resolve()
+	assert.Error(t, err)
+
+	resolver = NewFallbackResolver()
+	assert.False(t, resolver.IsResolver())
+	_, err = resolver.Resolve()
+	assert.True(t, errors.Is(err, errNoResolver))
+}
+
+func TestIFConfigResolverProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxied request holds the URL of the resolver
+		assert.Equal(t, "ifconfig.example.com", r.URL.Host)
+		_, _ = w.Write([]byte("1.2.3.4\n"))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	assert.NoError(t, err)
+	resolver := NewIFConfigResolver("http://ifconfig.example.com", proxyURL)
+	ip, err := resolver.Resolve()
+	assert.NoError(t, err)
+	assert.True(t, net.ParseIP("1.2.3.4").Equal(ip))
+}
@@ -37,6 +37,7 @@ var (
 	errMissingChecksum  = errors.New("input string is smaller than the checksum size")
 	errBadChecksum      = errors.New("invalid input checksum")
 	errMissingHexPrefix = errors.New("missing 0x prefix to hex encoding")
+	errJSONEncoding     = errors.New("json encoding only applies to structured data, not bytes")
 )
 
 // Encoding defines how bytes are converted to a string and vice versa
@@ -47,6 +48,9 @@ const (
 	CB58 Encoding = iota
 	// Hex specifies a hex plus 4 byte checksum encoding format
 	Hex
+	// JSON specifies that the data is returned decoded, as a JSON structure.
+	// Only APIs that return structured data support it.
+	JSON
 )
 
 // String ...
@@ -56,6 +60,8 @@ func (enc Encoding) String() string {
 		return "hex"
 	case CB58:
 		return "cb58"
+	case JSON:
+		return "json"
 	default:
 		return errInvalidEncoding.Error()
 	}
@@ -63,7 +69,7 @@ func (enc Encoding) String() string {
 
 func (enc Encoding) valid() bool {
 	switch enc {
-	case Hex, CB58:
+	case Hex, CB58, JSON:
 		return true
 	}
 	return false
@@ -88,6 +94,8 @@ func (enc *Encoding) UnmarshalJSON(b []byte) error {
 		*enc = Hex
 	case "\"cb58\"":
 		*enc = CB58
+	case "\"json\"":
+		*enc = JSON
 	default:
 		return errInvalidEncoding
 	}
@@ -101,6 +109,8 @@ func Encode(encoding Encoding, bytes []byte) (string, error) {
 	switch {
 	case !encoding.valid():
 		return "", errInvalidEncoding
+	case encoding == JSON:
+		return "", errJSONEncoding
 	case encoding == CB58 && len(bytes) > maxCB58EncodeSize:
 		return "", fmt.Errorf("byte slice length (%d) > maximum for cb58 (%d)", len(bytes), maxCB58EncodeSize)
 	}
@@ -124,6 +134,8 @@ func Decode(encoding Encoding, str string) ([]byte, error) {
 	switch {
 	case !encoding.valid():
 		return nil, errInvalidEncoding
+	case encoding == JSON:
+		return nil, errJSONEncoding
 	case len(str) == 0:
 		return nil, nil
 	case encoding == CB58 && len(str) > maxCB58DecodeSize:
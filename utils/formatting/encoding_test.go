@@ -48,6 +48,14 @@ func TestEncodingUnmarshalJSON(t *testing.T) {
 		t.Fatal("should be cb58")
 	}
 
+	jsonBytes = []byte("\"json\"")
+	if err := json.Unmarshal(jsonBytes, &enc); err != nil {
+		t.Fatal(err)
+	}
+	if enc != JSON {
+		t.Fatal("should be json")
+	}
+
 	jsonBytes = []byte("")
 	if err := json.Unmarshal(jsonBytes, &enc); err == nil {
 		t.Fatal("should have errored due to invalid encoding")
@@ -65,6 +73,9 @@ func TestEncodingString(t *testing.T) {
 
 	enc2 := CB58
 	assert.Equal(t, enc2.String(), "cb58")
+
+	enc3 := JSON
+	assert.Equal(t, enc3.String(), "json")
 }
 
 // Test encoding bytes to a string and decoding back to bytes
@@ -203,3 +214,12 @@ func TestDecodeNil(t *testing.T) {
 		t.Fatal("should both be nil")
 	}
 }
+
+func TestEncodeDecodeJSON(t *testing.T) {
+	if _, err := Encode(JSON, []byte{1}); err != errJSONEncoding {
+		t.Fatalf("expected %q but got %v", errJSONEncoding, err)
+	}
+	if _, err := Decode(JSON, "0x01"); err != errJSONEncoding {
+		t.Fatalf("expected %q but got %v", errJSONEncoding, err)
+	}
+}
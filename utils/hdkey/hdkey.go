@@ -0,0 +1,221 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hdkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+
+	secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v3"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+const (
+	// HardenedOffset is the first child index that is derived using hardened
+	// derivation.
+	HardenedOffset uint32 = 1 << 31
+
+	// AVAXCoinType is the SLIP-44 coin type registered for AVAX.
+	AVAXCoinType uint32 = 9000
+
+	// MinSeedLen is the minimum number of bytes a BIP-32 seed may contain.
+	MinSeedLen = 16
+	// MaxSeedLen is the maximum number of bytes a BIP-32 seed may contain.
+	MaxSeedLen = 64
+
+	seedIterations = 2048
+	seedLen        = 64
+)
+
+var (
+	masterKey = []byte("Bitcoin seed")
+
+	errInvalidSeedLen      = fmt.Errorf("seed must be between %d and %d bytes", MinSeedLen, MaxSeedLen)
+	errInvalidMnemonicLen  = errors.New("mnemonic must contain 12, 15, 18, 21 or 24 words")
+	errUnknownWord         = errors.New("mnemonic contains a word that isn't in the BIP-39 English wordlist")
+	errInvalidChecksum     = errors.New("mnemonic has an invalid checksum")
+	errInvalidKey          = errors.New("derived key is invalid")
+	errInvalidPath         = errors.New("derivation path must start with \"m\"")
+	errInvalidPathSegment  = errors.New("invalid derivation path segment")
+	errHardenedPathSegment = errors.New("derivation path segment is too large to be hardened")
+)
+
+// SeedFromMnemonic returns the BIP-39 seed of [mnemonic] protected by
+// [passphrase]. The mnemonic must consist of words of the BIP-39 English
+// wordlist and have a valid checksum.
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if err := verifyMnemonic(words); err != nil {
+		return nil, err
+	}
+	return pbkdf2.Key(
+		[]byte(strings.Join(words, " ")),
+		[]byte("mnemonic"+norm.NFKD.String(passphrase)),
+		seedIterations,
+		seedLen,
+		sha512.New,
+	), nil
+}
+
+// verifyMnemonic returns an error if [words] isn't a BIP-39 mnemonic. Each
+// word encodes 11 bits. A mnemonic of n words encodes 32n/3 bits of entropy
+// followed by the first n/3 bits of the SHA-256 hash of the entropy.
+func verifyMnemonic(words []string) error {
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return errInvalidMnemonicLen
+	}
+
+	bits := make([]byte, (11*len(words)+7)/8)
+	for i, word := range words {
+		index, ok := englishIndices[word]
+		if !ok {
+			return fmt.Errorf("%w: %q", errUnknownWord, word)
+		}
+		for j := 0; j < 11; j++ {
+			if index&(1<<(10-j)) == 0 {
+				continue
+			}
+			bit := 11*i + j
+			bits[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+
+	checksumLen := uint(len(words) / 3)
+	entropy := bits[:4*checksumLen]
+	hash := sha256.Sum256(entropy)
+	if bits[len(entropy)]>>(8-checksumLen) != hash[0]>>(8-checksumLen) {
+		return errInvalidChecksum
+	}
+	return nil
+}
+
+// AccountPath returns the BIP-44 path of the [index]'th external address of
+// the first AVAX account.
+func AccountPath(index uint32) []uint32 {
+	return []uint32{
+		44 + HardenedOffset,
+		AVAXCoinType + HardenedOffset,
+		HardenedOffset,
+		0,
+		index,
+	}
+}
+
+// ParsePath parses a derivation path such as "m/44'/9000'/0'/0/0". Hardened
+// segments may be marked with either ' or h.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, errInvalidPath
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %s", errInvalidPathSegment, segment, err)
+		}
+		if hardened {
+			if uint32(index) >= HardenedOffset {
+				return nil, errHardenedPathSegment
+			}
+			index += uint64(HardenedOffset)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// ExtendedKey is a BIP-32 extended private key.
+type ExtendedKey struct {
+	key       secp256k1.ModNScalar
+	chainCode [32]byte
+}
+
+// NewMaster returns the master extended key derived from [seed].
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MinSeedLen || len(seed) > MaxSeedLen {
+		return nil, errInvalidSeedLen
+	}
+	mac := hmac.New(sha512.New, masterKey)
+	_, _ = mac.Write(seed)
+	return newExtendedKey(mac.Sum(nil))
+}
+
+func newExtendedKey(i []byte) (*ExtendedKey, error) {
+	k := &ExtendedKey{}
+	if overflow := k.key.SetByteSlice(i[:32]); overflow || k.key.IsZero() {
+		return nil, errInvalidKey
+	}
+	copy(k.chainCode[:], i[32:])
+	return k, nil
+}
+
+// Child returns the child of this key at [index]. Indices at or above
+// [HardenedOffset] use hardened derivation.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		keyBytes := k.key.Bytes()
+		data = append(data, 0)
+		data = append(data, keyBytes[:]...)
+	} else {
+		data = append(data, secp256k1.NewPrivateKey(&k.key).PubKey().SerializeCompressed()...)
+	}
+	data = data[:len(data)+4]
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode[:])
+	_, _ = mac.Write(data)
+	child, err := newExtendedKey(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	if child.key.Add(&k.key).IsZero() {
+		return nil, errInvalidKey
+	}
+	return child, nil
+}
+
+// Derive returns the descendant of this key reached by following [path].
+func (k *ExtendedKey) Derive(path []uint32) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ChainCode returns the chain code of this key.
+func (k *ExtendedKey) ChainCode() []byte {
+	chainCode := k.chainCode
+	return chainCode[:]
+}
+
+// PrivateKey returns the private key of this extended key.
+func (k *ExtendedKey) PrivateKey() *crypto.PrivateKeySECP256K1R {
+	keyBytes := k.key.Bytes()
+	factory := crypto.FactorySECP256K1R{}
+	sk, _ := factory.ToPrivateKey(keyBytes[:])
+	return sk.(*crypto.PrivateKeySECP256K1R)
+}
@@ -0,0 +1,96 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hdkey
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vector 1 from BIP-32
+func TestDerive(t *testing.T) {
+	assert := assert.New(t)
+
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMaster(seed)
+	assert.NoError(err)
+	assert.Equal("e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", hex.EncodeToString(master.PrivateKey().Bytes()))
+	assert.Equal("873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", hex.EncodeToString(master.ChainCode()))
+
+	tests := map[string]string{
+		"m/0'":      "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		"m/0h/1":    "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		"m/0'/1/2'": "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+	}
+	for pathStr, expected := range tests {
+		path, err := ParsePath(pathStr)
+		assert.NoError(err)
+		key, err := master.Derive(path)
+		assert.NoError(err)
+		assert.Equal(expected, hex.EncodeToString(key.PrivateKey().Bytes()), pathStr)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ParsePath("m/44'/9000'/0'/0/3")
+	assert.NoError(err)
+	assert.Equal(AccountPath(3), path)
+
+	path, err = ParsePath("m")
+	assert.NoError(err)
+	assert.Empty(path)
+
+	_, err = ParsePath("44'/9000'")
+	assert.Error(err)
+	_, err = ParsePath("m/x")
+	assert.Error(err)
+	_, err = ParsePath("m/2147483648'")
+	assert.Error(err)
+}
+
+// Test vector from the reference BIP-39 implementation
+func TestSeedFromMnemonic(t *testing.T) {
+	assert := assert.New(t)
+
+	seed, err := SeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	assert.NoError(err)
+	assert.Equal("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	_, err = SeedFromMnemonic("abandon about", "")
+	assert.Error(err)
+}
+
+// Test vectors from the BIP-39 specification
+func TestVerifyMnemonic(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, mnemonic := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"scheme spot photo card baby mountain device kick cradle pact join borrow",
+		"vessel ladder alter error federal sibling chat ability sun glass valve picture",
+		"legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth title",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		"void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+	} {
+		assert.NoError(verifyMnemonic(strings.Fields(mnemonic)), mnemonic)
+	}
+
+	// The last word of a mnemonic contains its checksum
+	err := verifyMnemonic(strings.Fields("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"))
+	assert.True(errors.Is(err, errInvalidChecksum))
+
+	err = verifyMnemonic(strings.Fields("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abuot"))
+	assert.True(errors.Is(err, errUnknownWord))
+
+	assert.Len(englishWords, 2048)
+}
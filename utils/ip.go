@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -82,6 +83,19 @@ func (ipDesc IPDesc) IsZero() bool {
 		ip.Equal(net.IPv6zero)
 }
 
+// ParseIP parses [str] as an IPv4 or IPv6 address. Unlike net.ParseIP, an IPv6
+// address may be enclosed in brackets, as in "[::1]", and may have a zone, as
+// in "fe80::1%eth0". The zone is dropped. Returns nil if [str] isn't an IP.
+func ParseIP(str string) net.IP {
+	if strings.HasPrefix(str, "[") && strings.HasSuffix(str, "]") {
+		str = str[1 : len(str)-1]
+	}
+	if i := strings.LastIndexByte(str, '%'); i >= 0 && strings.Contains(str, ":") {
+		str = str[:i]
+	}
+	return net.ParseIP(str)
+}
+
 // ToIPDesc ...
 func ToIPDesc(str string) (IPDesc, error) {
 	host, portStr, err := net.SplitHostPort(str)
@@ -93,7 +107,7 @@ func ToIPDesc(str string) (IPDesc, error) {
 		// TODO: Should this return a locally defined error? (e.g. errBadPort)
 		return IPDesc{}, err
 	}
-	ip := net.ParseIP(host)
+	ip := ParseIP(host)
 	if ip == nil {
 		return IPDesc{}, errBadIP
 	}
@@ -140,6 +140,8 @@ func TestToIPDesc(t *testing.T) {
 	}{
 		{"127.0.0.1:42", IPDesc{net.ParseIP("127.0.0.1"), 42}},
 		{"[::1]:42", IPDesc{net.ParseIP("::1"), 42}},
+		{"[2001:db8::1]:9651", IPDesc{net.ParseIP("2001:db8::1"), 9651}},
+		{"[fe80::1%eth0]:42", IPDesc{net.ParseIP("fe80::1"), 42}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.in, func(t *testing.T) {
@@ -153,3 +155,30 @@ func TestToIPDesc(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIP(t *testing.T) {
+	tests := []struct {
+		in  string
+		out net.IP
+	}{
+		{"127.0.0.1", net.ParseIP("127.0.0.1")},
+		{"::1", net.ParseIP("::1")},
+		{"[::1]", net.ParseIP("::1")},
+		{"2001:db8::1", net.ParseIP("2001:db8::1")},
+		{"[2001:db8::1]", net.ParseIP("2001:db8::1")},
+		{"fe80::1%eth0", net.ParseIP("fe80::1")},
+		{"", nil},
+		{"[]", nil},
+		{"[127.0.0.1", nil},
+		{"127.0.0.1%eth0", nil},
+		{"abc", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			result := ParseIP(tt.in)
+			if !tt.out.Equal(result) {
+				t.Errorf("Expected %v, got %v", tt.out, result)
+			}
+		})
+	}
+}
@@ -18,6 +18,11 @@ type Config struct {
 	Enabled     bool
 	Freq        time.Duration
 	MaxNumFiles int
+
+	// Options of the runtime stats sampler
+	SamplerEnabled       bool
+	SamplerFreq          time.Duration
+	SamplerMaxNumSamples int
 }
 
 // ContinuousProfiler periodically captures CPU, memory, and lock profiles
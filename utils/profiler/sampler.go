@@ -0,0 +1,147 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ChainSample is a snapshot of the load on a chain
+type ChainSample struct {
+	// Number of messages waiting to be handled by the chain
+	QueueDepth int `json:"queueDepth"`
+	// Total time spent waiting to grab the chain's context lock
+	LockWait time.Duration `json:"lockWait"`
+}
+
+// Sample is a snapshot of the runtime stats of this process
+type Sample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	NumGoroutines int       `json:"numGoroutines"`
+	// Bytes of allocated heap objects
+	HeapAlloc uint64 `json:"heapAlloc"`
+	// Bytes in in-use heap spans
+	HeapInuse uint64 `json:"heapInuse"`
+	// Number of completed GC cycles
+	NumGC uint32 `json:"numGC"`
+	// Duration of the most recent GC stop-the-world pause
+	LastGCPause time.Duration `json:"lastGCPause"`
+	// Total duration of GC stop-the-world pauses since the process started
+	TotalGCPause time.Duration `json:"totalGCPause"`
+	// Chain alias --> load on that chain
+	Chains map[string]ChainSample `json:"chains"`
+}
+
+// Sampler periodically records runtime stats into a fixed size ring buffer
+type Sampler interface {
+	Dispatch()
+	Shutdown()
+	// Samples returns the recorded samples taken at or after [start], ordered
+	// from oldest to newest.
+	Samples(start time.Time) []Sample
+}
+
+type sampler struct {
+	freq time.Duration
+	// Returns the load on each chain. May be nil.
+	chainSamples func() map[string]ChainSample
+
+	lock sync.RWMutex
+	// Ring buffer of samples. [next] is the index the next sample is written
+	// to. Once [full] is true, [next] is also the index of the oldest sample.
+	samples []Sample
+	next    int
+	full    bool
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// NewSampler returns a sampler that records a sample every [freq] and keeps
+// the last [maxNumSamples] samples. [chainSamples] is called to get the load on
+// each chain and may be nil.
+func NewSampler(freq time.Duration, maxNumSamples int, chainSamples func() map[string]ChainSample) Sampler {
+	return &sampler{
+		freq:         freq,
+		chainSamples: chainSamples,
+		samples:      make([]Sample, maxNumSamples),
+		closer:       make(chan struct{}),
+	}
+}
+
+func (s *sampler) Dispatch() {
+	t := time.NewTicker(s.freq)
+	defer t.Stop()
+
+	for {
+		s.record(s.sample())
+
+		select {
+		case <-s.closer:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *sampler) Shutdown() {
+	close(s.closer)
+}
+
+func (s *sampler) Samples(start time.Time) []Sample {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ordered := make([]Sample, 0, len(s.samples))
+	if s.full {
+		ordered = append(ordered, s.samples[s.next:]...)
+	}
+	ordered = append(ordered, s.samples[:s.next]...)
+
+	for i, sample := range ordered {
+		if !sample.Timestamp.Before(start) {
+			return ordered[i:]
+		}
+	}
+	return nil
+}
+
+func (s *sampler) sample() Sample {
+	memStats := runtime.MemStats{}
+	runtime.ReadMemStats(&memStats)
+
+	sample := Sample{
+		Timestamp:     time.Now(),
+		NumGoroutines: runtime.NumGoroutine(),
+		HeapAlloc:     memStats.HeapAlloc,
+		HeapInuse:     memStats.HeapInuse,
+		NumGC:         memStats.NumGC,
+		TotalGCPause:  time.Duration(memStats.PauseTotalNs),
+	}
+	if memStats.NumGC > 0 {
+		lastPause := memStats.PauseNs[(memStats.NumGC+255)%256]
+		sample.LastGCPause = time.Duration(lastPause)
+	}
+	if s.chainSamples != nil {
+		sample.Chains = s.chainSamples()
+	}
+	return sample
+}
+
+func (s *sampler) record(sample Sample) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.samples) == 0 {
+		return
+	}
+	s.samples[s.next] = sample
+	s.next++
+	if s.next == len(s.samples) {
+		s.next = 0
+		s.full = true
+	}
+}
@@ -0,0 +1,45 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplerRingBuffer(t *testing.T) {
+	s := NewSampler(time.Second, 3, nil).(*sampler)
+
+	start := time.Unix(0, 0)
+	for i := 0; i < 5; i++ {
+		s.record(Sample{Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	samples := s.Samples(start)
+	assert.Len(t, samples, 3)
+	for i, sample := range samples {
+		assert.Equal(t, start.Add(time.Duration(i+2)*time.Second), sample.Timestamp)
+	}
+
+	samples = s.Samples(start.Add(4 * time.Second))
+	assert.Len(t, samples, 1)
+
+	samples = s.Samples(start.Add(5 * time.Second))
+	assert.Len(t, samples, 0)
+}
+
+func TestSamplerSample(t *testing.T) {
+	s := NewSampler(time.Second, 1, func() map[string]ChainSample {
+		return map[string]ChainSample{
+			"X": {QueueDepth: 5, LockWait: time.Second},
+		}
+	}).(*sampler)
+
+	sample := s.sample()
+	assert.Positive(t, sample.NumGoroutines)
+	assert.Positive(t, sample.HeapAlloc)
+	assert.Equal(t, ChainSample{QueueDepth: 5, LockWait: time.Second}, sample.Chains["X"])
+}
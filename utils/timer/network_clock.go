@@ -0,0 +1,136 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	stdmath "math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Weights gives the weight of the time reported by each peer
+type Weights interface {
+	// GetWeight returns the weight of [nodeID], and false if it has none
+	GetWeight(nodeID ids.ShortID) (uint64, bool)
+}
+
+// NetworkClock estimates how far the local clock is from the clocks of the
+// peers of this node. The estimate is the median of the offsets reported by
+// the peers, weighted by their stake, so it isn't swayed by peers holding a
+// minority of the stake with wildly wrong clocks. Peers without stake are
+// ignored.
+type NetworkClock struct {
+	lock sync.RWMutex
+	// Stake of the peers. Read whenever a peer reports its time or is removed.
+	weights Weights
+	// Minimum number of staking peers that must have reported their time
+	// before the estimate is non-zero
+	minPeers int
+	// Peer ID --> peer time minus local time when the peer reported its time
+	offsets map[ids.ShortID]time.Duration
+	// Weighted median of [offsets], if at least [minPeers] staking peers
+	// reported their time
+	offset time.Duration
+}
+
+// NewNetworkClock returns a new network clock that weights the time reported
+// by each peer by its stake in [weights], and only estimates an offset once at
+// least [minPeers] staking peers reported their time
+func NewNetworkClock(weights Weights, minPeers int) *NetworkClock {
+	return &NetworkClock{
+		weights:  weights,
+		minPeers: minPeers,
+		offsets:  make(map[ids.ShortID]time.Duration),
+	}
+}
+
+// Observe that [nodeID] reported its time as [peerTime] when the local time was
+// [localTime]
+func (c *NetworkClock) Observe(nodeID ids.ShortID, peerTime, localTime time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.offsets[nodeID] = peerTime.Sub(localTime)
+	c.update()
+}
+
+// Remove the time reported by [nodeID]
+func (c *NetworkClock) Remove(nodeID ids.ShortID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.offsets[nodeID]; !ok {
+		return
+	}
+	delete(c.offsets, nodeID)
+	c.update()
+}
+
+// Offset returns the estimated network time minus the local time
+func (c *NetworkClock) Offset() time.Duration {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.offset
+}
+
+// Time returns the estimated network time, given that the local time is
+// [localTime]
+func (c *NetworkClock) Time(localTime time.Time) time.Time {
+	return localTime.Add(c.Offset())
+}
+
+type weightedOffset struct {
+	offset time.Duration
+	weight uint64
+}
+
+// update recalculates the weighted median offset.
+// Assumes [c.lock] is held.
+func (c *NetworkClock) update() {
+	offsets := make([]weightedOffset, 0, len(c.offsets))
+	totalWeight := uint64(0)
+	for nodeID, offset := range c.offsets {
+		weight, ok := c.weights.GetWeight(nodeID)
+		if !ok || weight == 0 {
+			continue
+		}
+		newTotalWeight, err := math.Add64(totalWeight, weight)
+		if err != nil {
+			newTotalWeight = stdmath.MaxUint64
+			weight = newTotalWeight - totalWeight
+		}
+		offsets = append(offsets, weightedOffset{
+			offset: offset,
+			weight: weight,
+		})
+		totalWeight = newTotalWeight
+	}
+	if len(offsets) == 0 || len(offsets) < c.minPeers {
+		c.offset = 0
+		return
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i].offset < offsets[j].offset })
+
+	// The median is the offset at which half of the weight is reached. If
+	// exactly half of the weight is at or below an offset, the median is
+	// halfway to the next offset.
+	weightBelow := uint64(0)
+	for i, offset := range offsets {
+		weightBelow += offset.weight
+		weightAbove := totalWeight - weightBelow
+		switch {
+		case weightBelow > weightAbove:
+			c.offset = offset.offset
+			return
+		case weightBelow == weightAbove:
+			c.offset = (offset.offset + offsets[i+1].offset) / 2
+			return
+		}
+	}
+}
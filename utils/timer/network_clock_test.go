@@ -0,0 +1,81 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type testWeights map[ids.ShortID]uint64
+
+func (w testWeights) GetWeight(nodeID ids.ShortID) (uint64, bool) {
+	weight, ok := w[nodeID]
+	return weight, ok
+}
+
+func TestNetworkClock(t *testing.T) {
+	weights := testWeights{
+		{1}: 1,
+		{2}: 1,
+		{3}: 1,
+		{4}: 1,
+	}
+	c := NewNetworkClock(weights, 3)
+	now := time.Unix(1000, 0)
+
+	c.Observe(ids.ShortID{1}, now.Add(time.Second), now)
+	c.Observe(ids.ShortID{2}, now.Add(2*time.Second), now)
+	// Not enough peers reported their time yet
+	assert.Equal(t, time.Duration(0), c.Offset())
+
+	// Peers without stake aren't counted
+	c.Observe(ids.ShortID{5}, now.Add(time.Hour), now)
+	assert.Equal(t, time.Duration(0), c.Offset())
+	c.Remove(ids.ShortID{5})
+
+	// An outlier doesn't move the median
+	c.Observe(ids.ShortID{3}, now.Add(time.Hour), now)
+	assert.Equal(t, 2*time.Second, c.Offset())
+	assert.Equal(t, now.Add(2*time.Second), c.Time(now))
+
+	c.Observe(ids.ShortID{4}, now.Add(-time.Hour), now)
+	assert.Equal(t, 1500*time.Millisecond, c.Offset())
+
+	// Re-observing a peer replaces its previous offset
+	c.Observe(ids.ShortID{4}, now.Add(3*time.Second), now)
+	assert.Equal(t, 2500*time.Millisecond, c.Offset())
+
+	c.Remove(ids.ShortID{3})
+	assert.Equal(t, 2*time.Second, c.Offset())
+
+	c.Remove(ids.ShortID{1})
+	assert.Equal(t, time.Duration(0), c.Offset())
+}
+
+func TestNetworkClockWeighted(t *testing.T) {
+	weights := testWeights{
+		{1}: 10,
+		{2}: 1,
+		{3}: 1,
+	}
+	c := NewNetworkClock(weights, 1)
+	now := time.Unix(1000, 0)
+
+	// A peer holding most of the stake sets the median, even when the other
+	// peers agree with each other
+	c.Observe(ids.ShortID{1}, now.Add(time.Second), now)
+	c.Observe(ids.ShortID{2}, now.Add(time.Hour), now)
+	c.Observe(ids.ShortID{3}, now.Add(time.Hour), now)
+	assert.Equal(t, time.Second, c.Offset())
+
+	// Peers holding most of the stake together outweigh the largest peer
+	weights[ids.ShortID{2}] = 10
+	c.Observe(ids.ShortID{3}, now.Add(2*time.Hour), now)
+	assert.Equal(t, time.Hour, c.Offset())
+}
@@ -0,0 +1,168 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workers
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	_ Executor = Inline{}
+	_ Executor = &chainQueue{}
+)
+
+// Executor runs functions, such as cryptographic operations, on behalf of a
+// chain
+type Executor interface {
+	// Run runs all of [fs], possibly concurrently, and returns once they have
+	// all finished
+	Run(fs ...func())
+}
+
+// Inline is an executor that runs the functions on the calling goroutine
+type Inline struct{}
+
+// Run implements the Executor interface
+func (Inline) Run(fs ...func()) {
+	for _, f := range fs {
+		f()
+	}
+}
+
+// Pool is a bounded set of workers shared by all the chains of this node. The
+// chains with pending functions take turns to have one of their functions
+// run, so that a burst of functions submitted by one chain doesn't delay the
+// functions of the other chains behind it.
+type Pool struct {
+	lock sync.Mutex
+	// Signalled when a function is pushed or the pool is shutdown
+	cond *sync.Cond
+
+	// Chain ID --> the queue of functions of that chain
+	queues map[ids.ID]*chainQueue
+	// Queues with pending functions, in the order they will be served
+	ready []*chainQueue
+
+	closed bool
+	// Tracks the running workers
+	wg sync.WaitGroup
+}
+
+// NewPool returns a pool running [numWorkers] workers
+func NewPool(numWorkers int) *Pool {
+	p := &Pool{
+		queues: make(map[ids.ID]*chainQueue),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	p.wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Executor returns the executor that runs the functions of [chainID] on this
+// pool
+func (p *Pool) Executor(chainID ids.ID) Executor {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	q, ok := p.queues[chainID]
+	if !ok {
+		q = &chainQueue{pool: p}
+		p.queues[chainID] = q
+	}
+	return q
+}
+
+// Shutdown stops the workers once the pending functions have been run. Any
+// function run after Shutdown is called is run on the calling goroutine.
+func (p *Pool) Shutdown() {
+	p.lock.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.lock.Unlock()
+
+	p.wg.Wait()
+}
+
+// push [f] to the queue [q]. Returns false if the pool was shutdown.
+func (p *Pool) push(q *chainQueue, f func()) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return false
+	}
+	q.pending = append(q.pending, f)
+	if !q.ready {
+		q.ready = true
+		p.ready = append(p.ready, q)
+	}
+	p.cond.Signal()
+	return true
+}
+
+// work runs the pending functions until the pool is shutdown
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		p.lock.Lock()
+		for len(p.ready) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.ready) == 0 {
+			p.lock.Unlock()
+			return
+		}
+
+		// Take the next function of the queue at the front, and move the queue
+		// to the back if it has more pending functions
+		q := p.ready[0]
+		p.ready[0] = nil
+		p.ready = p.ready[1:]
+		f := q.pending[0]
+		q.pending[0] = nil
+		q.pending = q.pending[1:]
+		if len(q.pending) > 0 {
+			p.ready = append(p.ready, q)
+		} else {
+			q.ready = false
+		}
+		p.lock.Unlock()
+
+		f()
+	}
+}
+
+// chainQueue holds the pending functions of a chain
+type chainQueue struct {
+	pool *Pool
+
+	// The following fields are protected by [pool.lock]
+	pending []func()
+	// True iff this queue is in [pool.ready]
+	ready bool
+}
+
+// Run implements the Executor interface
+func (q *chainQueue) Run(fs ...func()) {
+	wg := sync.WaitGroup{}
+	wg.Add(len(fs))
+	for _, f := range fs {
+		f := f
+		ok := q.pool.push(q, func() {
+			defer wg.Done()
+			f()
+		})
+		if !ok {
+			f()
+			wg.Done()
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,91 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workers
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestPoolRun(t *testing.T) {
+	p := NewPool(4)
+	defer p.Shutdown()
+
+	e := p.Executor(ids.ID{1})
+	assert.Equal(t, e, p.Executor(ids.ID{1}))
+
+	results := make([]int, 100)
+	fs := make([]func(), len(results))
+	for i := range fs {
+		i := i
+		fs[i] = func() { results[i] = i }
+	}
+	e.Run(fs...)
+	for i, result := range results {
+		assert.Equal(t, i, result)
+	}
+}
+
+func TestPoolFairness(t *testing.T) {
+	p := NewPool(1)
+	defer p.Shutdown()
+
+	a := p.Executor(ids.ID{1}).(*chainQueue)
+	b := p.Executor(ids.ID{2}).(*chainQueue)
+
+	// Block the only worker until all the functions are pushed
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	a.Run(func() {}) // Make sure the worker is running
+	assert.True(t, p.push(a, func() {
+		close(started)
+		<-unblock
+	}))
+	<-started
+
+	var (
+		lock  sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	record := func(name string) func() {
+		wg.Add(1)
+		return func() {
+			lock.Lock()
+			order = append(order, name)
+			lock.Unlock()
+			wg.Done()
+		}
+	}
+	assert.True(t, p.push(a, record("a1")))
+	assert.True(t, p.push(a, record("a2")))
+	assert.True(t, p.push(a, record("a3")))
+	assert.True(t, p.push(b, record("b1")))
+	assert.True(t, p.push(b, record("b2")))
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, []string{"a1", "b1", "a2", "b2", "a3"}, order)
+}
+
+func TestPoolShutdown(t *testing.T) {
+	p := NewPool(2)
+	e := p.Executor(ids.ID{1})
+	p.Shutdown()
+
+	// Functions are run inline once the pool is shutdown
+	ran := false
+	e.Run(func() { ran = true })
+	assert.True(t, ran)
+}
+
+func TestInline(t *testing.T) {
+	ran := 0
+	Inline{}.Run(func() { ran++ }, func() { ran++ })
+	assert.Equal(t, 2, ran)
+}
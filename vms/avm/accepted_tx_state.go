@@ -0,0 +1,110 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	// Max number of accepted txs whose IDs are remembered
+	maxRecentlyAcceptedTxs = 4096
+)
+
+var (
+	// Keys of the accepted txs are 8 bytes long, so this can't collide with
+	// one of them
+	nextAcceptedIndexKey = []byte("next")
+
+	_ AcceptedTxState = &acceptedTxState{}
+)
+
+// AcceptedTxState remembers the IDs of the most recently accepted txs, in the
+// order they were accepted.
+type AcceptedTxState interface {
+	// AddAcceptedTx records that [txID] was accepted after every tx that has
+	// been recorded so far. Only the last [maxRecentlyAcceptedTxs] txs are
+	// remembered.
+	AddAcceptedTx(txID ids.ID) error
+
+	// RecentlyAcceptedTxs returns the IDs of up to the last [limit] accepted
+	// txs, oldest first.
+	RecentlyAcceptedTxs(limit int) ([]ids.ID, error)
+}
+
+type acceptedTxState struct {
+	db database.Database
+
+	nextIndex       uint64
+	nextIndexLoaded bool
+}
+
+func NewAcceptedTxState(db database.Database) AcceptedTxState {
+	return &acceptedTxState{db: db}
+}
+
+func (s *acceptedTxState) getNextIndex() (uint64, error) {
+	if s.nextIndexLoaded {
+		return s.nextIndex, nil
+	}
+	nextIndex, err := database.GetUInt64(s.db, nextAcceptedIndexKey)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		nextIndex = 0
+	default:
+		return 0, err
+	}
+	s.nextIndex = nextIndex
+	s.nextIndexLoaded = true
+	return nextIndex, nil
+}
+
+func (s *acceptedTxState) AddAcceptedTx(txID ids.ID) error {
+	index, err := s.getNextIndex()
+	if err != nil {
+		return err
+	}
+	if err := s.db.Put(database.PackUInt64(index), txID[:]); err != nil {
+		return err
+	}
+	if index >= maxRecentlyAcceptedTxs {
+		if err := s.db.Delete(database.PackUInt64(index - maxRecentlyAcceptedTxs)); err != nil {
+			return err
+		}
+	}
+	if err := database.PutUInt64(s.db, nextAcceptedIndexKey, index+1); err != nil {
+		return err
+	}
+	s.nextIndex = index + 1
+	return nil
+}
+
+func (s *acceptedTxState) RecentlyAcceptedTxs(limit int) ([]ids.ID, error) {
+	nextIndex, err := s.getNextIndex()
+	if err != nil {
+		return nil, err
+	}
+	if limit > maxRecentlyAcceptedTxs {
+		limit = maxRecentlyAcceptedTxs
+	}
+	if uint64(limit) > nextIndex {
+		limit = int(nextIndex)
+	}
+
+	txIDs := make([]ids.ID, limit)
+	for i := range txIDs {
+		txIDBytes, err := s.db.Get(database.PackUInt64(nextIndex - uint64(limit-i)))
+		if err != nil {
+			return nil, err
+		}
+		txID, err := ids.ToID(txIDBytes)
+		if err != nil {
+			return nil, err
+		}
+		txIDs[i] = txID
+	}
+	return txIDs, nil
+}
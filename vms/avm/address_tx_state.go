@@ -0,0 +1,120 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	// Keys of the addresses are 20 bytes long, and keys of their txs are 28
+	// bytes long, so this can't collide with one of them
+	addressTxsStartKey = []byte("start")
+
+	_ AddressTxState = &addressTxState{}
+)
+
+// AddressTxState indexes the accepted txs that consumed or created the UTXOs
+// of each address, in the order they were accepted.
+type AddressTxState interface {
+	// AddAddressTx records that [txID] touched [addr]. [txID] must be recorded
+	// at most once per address.
+	AddAddressTx(addr ids.ShortID, txID ids.ID) error
+
+	// AddressTxs returns the IDs of up to [limit] txs that touched [addr],
+	// oldest first, starting with the [startIndex]th one. Also returns the
+	// index of the next tx to fetch.
+	AddressTxs(addr ids.ShortID, startIndex uint64, limit int) ([]ids.ID, uint64, error)
+
+	// StartAddressTxs records when the index started indexing txs. If
+	// [fromGenesis], no tx was accepted before the index started, so it holds
+	// every accepted tx. Otherwise, the txs accepted before [now] are missing.
+	// It is a no-op after the first call.
+	StartAddressTxs(fromGenesis bool, now time.Time) error
+
+	// AddressTxsStart returns when the index started indexing txs. The txs
+	// accepted before then are missing. Returns the zero time if the index
+	// holds every accepted tx.
+	AddressTxsStart() (time.Time, error)
+}
+
+// The number of txs that touched an address is stored at the address' key.
+// Its [i]th tx is stored at the address' key followed by [i]. When the index
+// started is stored at [addressTxsStartKey].
+type addressTxState struct {
+	db database.Database
+}
+
+func NewAddressTxState(db database.Database) AddressTxState {
+	return &addressTxState{db: db}
+}
+
+func (s *addressTxState) numTxs(addr ids.ShortID) (uint64, error) {
+	numTxs, err := database.GetUInt64(s.db, addr[:])
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return numTxs, err
+}
+
+func addressTxKey(addr ids.ShortID, index uint64) []byte {
+	return append(addr[:], database.PackUInt64(index)...)
+}
+
+func (s *addressTxState) AddAddressTx(addr ids.ShortID, txID ids.ID) error {
+	index, err := s.numTxs(addr)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Put(addressTxKey(addr, index), txID[:]); err != nil {
+		return err
+	}
+	return database.PutUInt64(s.db, addr[:], index+1)
+}
+
+func (s *addressTxState) StartAddressTxs(fromGenesis bool, now time.Time) error {
+	started, err := s.db.Has(addressTxsStartKey)
+	if err != nil || started {
+		return err
+	}
+	start := time.Time{}
+	if !fromGenesis {
+		start = now
+	}
+	return database.PutTimestamp(s.db, addressTxsStartKey, start)
+}
+
+func (s *addressTxState) AddressTxsStart() (time.Time, error) {
+	return database.GetTimestamp(s.db, addressTxsStartKey)
+}
+
+func (s *addressTxState) AddressTxs(addr ids.ShortID, startIndex uint64, limit int) ([]ids.ID, uint64, error) {
+	numTxs, err := s.numTxs(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if startIndex >= numTxs {
+		return nil, numTxs, nil
+	}
+	if remaining := numTxs - startIndex; uint64(limit) > remaining {
+		limit = int(remaining)
+	}
+
+	txIDs := make([]ids.ID, limit)
+	for i := range txIDs {
+		txIDBytes, err := s.db.Get(addressTxKey(addr, startIndex+uint64(i)))
+		if err != nil {
+			return nil, 0, err
+		}
+		txID, err := ids.ToID(txIDBytes)
+		if err != nil {
+			return nil, 0, err
+		}
+		txIDs[i] = txID
+	}
+	return txIDs, startIndex + uint64(limit), nil
+}
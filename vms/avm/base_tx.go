@@ -21,6 +21,11 @@ type BaseTx struct {
 	avax.BaseTx `serialize:"true"`
 }
 
+// InitCtx sets the context of the outputs of this transaction
+func (t *BaseTx) InitCtx(ctx *snow.Context) {
+	initOutsCtx(ctx, t.Outs)
+}
+
 // SyntacticVerify that this transaction is well-formed.
 func (t *BaseTx) SyntacticVerify(
 	ctx *snow.Context,
@@ -68,3 +73,11 @@ func (t *BaseTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiable
 
 // ExecuteWithSideEffects writes the batch with any additional side effects
 func (t *BaseTx) ExecuteWithSideEffects(_ *VM, batch database.Batch) error { return batch.Write() }
+
+func initOutsCtx(ctx *snow.Context, outs []*avax.TransferableOutput) {
+	for _, out := range outs {
+		if out, ok := out.Out.(snow.ContextInitializable); ok {
+			out.InitCtx(ctx)
+		}
+	}
+}
@@ -42,6 +42,67 @@ func (c *Client) IssueTx(txBytes []byte) (ids.ID, error) {
 	return res.TxID, err
 }
 
+// SimulateOperationTx verifies an operation transaction against the current
+// state of the node without issuing it
+func (c *Client) SimulateOperationTx(txBytes []byte) (*SimulateOperationTxReply, error) {
+	txStr, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	res := &SimulateOperationTxReply{}
+	err = c.requester.SendRequest("simulateOperationTx", &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res)
+	return res, err
+}
+
+// ReplayAcceptedTxs verifies the last [numTxs] accepted transactions against
+// the rules of the node without modifying its state
+func (c *Client) ReplayAcceptedTxs(numTxs uint32) (*ReplayAcceptedTxsReply, error) {
+	res := &ReplayAcceptedTxsReply{}
+	err := c.requester.SendRequest("replayAcceptedTxs", &ReplayAcceptedTxsArgs{
+		NumTxs: cjson.Uint32(numTxs),
+	}, res)
+	return res, err
+}
+
+// GetAcceptedOperations returns the number of fx operations of each type that
+// the node accepted since it started
+func (c *Client) GetAcceptedOperations() (map[string]cjson.Uint64, error) {
+	res := &GetAcceptedOperationsReply{}
+	err := c.requester.SendRequest("getAcceptedOperations", struct{}{}, res)
+	return res.Operations, err
+}
+
+// EstimateFee returns the fee that a tx issued with API method [txType] must
+// burn if it spends UTXOs of [from]
+func (c *Client) EstimateFee(txType string, from []string) (*EstimateFeeReply, error) {
+	res := &EstimateFeeReply{}
+	err := c.requester.SendRequest("estimateFee", &EstimateFeeArgs{
+		TxType: txType,
+		From:   from,
+	}, res)
+	return res, err
+}
+
+// GetPendingTxs returns the txs issued through the node that are waiting to be
+// passed to consensus, and the txs issued through its wallet API that aren't
+// decided yet
+func (c *Client) GetPendingTxs() (*GetPendingTxsReply, error) {
+	res := &GetPendingTxsReply{}
+	err := c.requester.SendRequest("getPendingTxs", struct{}{}, res)
+	return res, err
+}
+
+// GetPendingTxsStats returns the number and size of the txs GetPendingTxs
+// would return
+func (c *Client) GetPendingTxsStats() (*GetPendingTxsStatsReply, error) {
+	res := &GetPendingTxsStatsReply{}
+	err := c.requester.SendRequest("getPendingTxsStats", struct{}{}, res)
+	return res, err
+}
+
 // GetTxStatus returns the status of [txID]
 func (c *Client) GetTxStatus(txID ids.ID) (choices.Status, error) {
 	res := &GetTxStatusReply{}
@@ -124,6 +185,37 @@ func (c *Client) GetAtomicUTXOs(addrs []string, sourceChain string, limit uint32
 	return utxos, res.EndIndex, nil
 }
 
+// GetAddressTxs returns the IDs of up to [pageSize] accepted txs that touched
+// [addr], starting at [cursor], and the cursor of the next page
+func (c *Client) GetAddressTxs(addr string, cursor, pageSize uint64) ([]ids.ID, uint64, error) {
+	res := &GetAddressTxsReply{}
+	err := c.requester.SendRequest("getAddressTxs", &GetAddressTxsArgs{
+		Address:  addr,
+		Cursor:   cjson.Uint64(cursor),
+		PageSize: cjson.Uint64(pageSize),
+	}, res)
+	return res.TxIDs, uint64(res.Cursor), err
+}
+
+// CreateSnapshot pins the current state of the chain for [ttl] and returns the
+// ID of the snapshot
+func (c *Client) CreateSnapshot(ttl time.Duration) (ids.ID, error) {
+	res := &CreateSnapshotReply{}
+	err := c.requester.SendRequest("createSnapshot", &CreateSnapshotArgs{
+		TTL: cjson.Uint64(ttl / time.Second),
+	}, res)
+	return res.Snapshot, err
+}
+
+// ReleaseSnapshot releases the snapshot [snapshotID]
+func (c *Client) ReleaseSnapshot(snapshotID ids.ID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("releaseSnapshot", &ReleaseSnapshotArgs{
+		Snapshot: snapshotID,
+	}, res)
+	return res.Success, err
+}
+
 // GetAssetDescription returns a description of [assetID]
 func (c *Client) GetAssetDescription(assetID string) (*GetAssetDescriptionReply, error) {
 	res := &GetAssetDescriptionReply{}
@@ -145,6 +237,20 @@ func (c *Client) GetBalance(addr string, assetID string, includePartial bool) (*
 	return res, err
 }
 
+// GetBalanceAt returns the balance of [assetID] held by [addr] at the end of
+// [epoch]. The node must keep a UTXO history.
+func (c *Client) GetBalanceAt(addr string, assetID string, includePartial bool, epoch uint32) (*GetBalanceReply, error) {
+	res := &GetBalanceReply{}
+	jsonEpoch := cjson.Uint32(epoch)
+	err := c.requester.SendRequest("getBalance", &GetBalanceArgs{
+		Address:        addr,
+		AssetID:        assetID,
+		IncludePartial: includePartial,
+		Epoch:          &jsonEpoch,
+	}, res)
+	return res, err
+}
+
 // GetAllBalances returns all asset balances for [addr]
 func (c *Client) GetAllBalances(addr string, includePartial bool) (*GetAllBalancesReply, error) {
 	res := &GetAllBalancesReply{}
@@ -155,6 +261,21 @@ func (c *Client) GetAllBalances(addr string, includePartial bool) (*GetAllBalanc
 	return res, err
 }
 
+// GetNFTs returns at most [limit] NFTs held by [addr], starting after the NFT
+// held by [startUTXO]. If [assetID] is non-empty, only NFTs of that asset are
+// returned.
+func (c *Client) GetNFTs(addr, assetID, startUTXO string, limit uint32) (*GetNFTsReply, error) {
+	res := &GetNFTsReply{}
+	err := c.requester.SendRequest("getNFTs", &GetNFTsArgs{
+		JSONAddress: api.JSONAddress{Address: addr},
+		AssetID:     assetID,
+		StartUTXO:   startUTXO,
+		Limit:       cjson.Uint32(limit),
+		Encoding:    formatting.Hex,
+	}, res)
+	return res, err
+}
+
 // CreateAsset creates a new asset and returns its assetID
 func (c *Client) CreateAsset(
 	user api.UserPass,
@@ -269,6 +390,36 @@ func (c *Client) ListAddresses(user api.UserPass) ([]string, error) {
 	return res.Addresses, err
 }
 
+// RegisterWebhook registers a webhook of [user] that notifies [url] of the
+// accepted txs touching one of [addrs]. Returns the ID of the webhook and the
+// secret its notifications are signed with.
+func (c *Client) RegisterWebhook(user api.UserPass, url string, addrs []string) (*RegisterWebhookReply, error) {
+	res := &RegisterWebhookReply{}
+	err := c.requester.SendRequest("registerWebhook", &RegisterWebhookArgs{
+		UserPass:  user,
+		URL:       url,
+		Addresses: addrs,
+	}, res)
+	return res, err
+}
+
+// ListWebhooks returns the webhooks registered by [user]
+func (c *Client) ListWebhooks(user api.UserPass) ([]APIWebhook, error) {
+	res := &ListWebhooksReply{}
+	err := c.requester.SendRequest("listWebhooks", &user, res)
+	return res.Webhooks, err
+}
+
+// RemoveWebhook removes the webhook [webhookID] registered by [user]
+func (c *Client) RemoveWebhook(user api.UserPass, webhookID ids.ID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("removeWebhook", &RemoveWebhookArgs{
+		UserPass:  user,
+		WebhookID: webhookID,
+	}, res)
+	return res.Success, err
+}
+
 // ExportKey returns the private key corresponding to [addr] controlled by [user]
 func (c *Client) ExportKey(user api.UserPass, addr string) (string, error) {
 	res := &ExportKeyReply{}
@@ -289,6 +440,39 @@ func (c *Client) ImportKey(user api.UserPass, privateKey string) (string, error)
 	return res.Address, err
 }
 
+// ImportMnemonic sets the seed of [user] to the seed of [mnemonic] protected by
+// [passphrase] and returns the first address derived from it
+func (c *Client) ImportMnemonic(user api.UserPass, mnemonic, passphrase string) (string, error) {
+	res := &api.JSONAddress{}
+	err := c.requester.SendRequest("importMnemonic", &ImportMnemonicArgs{
+		UserPass:   user,
+		Mnemonic:   mnemonic,
+		Passphrase: passphrase,
+	}, res)
+	return res.Address, err
+}
+
+// DeriveAddresses derives the next [count] addresses from the seed of [user]
+func (c *Client) DeriveAddresses(user api.UserPass, count uint32) ([]string, error) {
+	res := &api.JSONAddresses{}
+	err := c.requester.SendRequest("deriveAddresses", &DeriveAddressesArgs{
+		UserPass: user,
+		Count:    cjson.Uint32(count),
+	}, res)
+	return res.Addresses, err
+}
+
+// ScanAddresses adds the used addresses derived from the seed of [user] to
+// [user] and returns them along with the index of the next address to derive
+func (c *Client) ScanAddresses(user api.UserPass, gapLimit uint32) ([]string, uint32, error) {
+	res := &ScanAddressesReply{}
+	err := c.requester.SendRequest("scanAddresses", &ScanAddressesArgs{
+		UserPass: user,
+		GapLimit: cjson.Uint32(gapLimit),
+	}, res)
+	return res.Addresses, uint32(res.NextIndex), err
+}
+
 // Send [amount] of [assetID] to address [to]
 func (c *Client) Send(
 	user api.UserPass,
@@ -360,6 +544,88 @@ func (c *Client) Mint(
 	return res.TxID, err
 }
 
+// BuildSendMultiple returns an unsigned tx that sends [outputs] with the funds
+// of [from], encoded in hex
+func (c *Client) BuildSendMultiple(
+	from []string,
+	changeAddr string,
+	outputs []SendOutput,
+	memo string,
+) (*BuildTxReply, error) {
+	res := &BuildTxReply{}
+	err := c.requester.SendRequest("buildSendMultiple", &BuildSendMultipleArgs{
+		JSONFromAddrs:  api.JSONFromAddrs{From: from},
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		Outputs:        outputs,
+		Memo:           memo,
+		Encoding:       formatting.Hex,
+	}, res)
+	return res, err
+}
+
+// BuildMint returns an unsigned tx that mints [amount] of [assetID] to be owned
+// by [to] with the mint outputs of [from], encoded in hex
+func (c *Client) BuildMint(
+	from []string,
+	changeAddr string,
+	amount uint64,
+	assetID,
+	to string,
+) (*BuildTxReply, error) {
+	res := &BuildTxReply{}
+	err := c.requester.SendRequest("buildMint", &BuildMintArgs{
+		JSONFromAddrs:  api.JSONFromAddrs{From: from},
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		Amount:         cjson.Uint64(amount),
+		AssetID:        assetID,
+		To:             to,
+		Encoding:       formatting.Hex,
+	}, res)
+	return res, err
+}
+
+// IssueSignedTx issues [unsignedTx] with the [signatures] of each of its
+// credentials
+func (c *Client) IssueSignedTx(unsignedTx []byte, signatures [][][]byte) (ids.ID, error) {
+	txStr, err := formatting.Encode(formatting.Hex, unsignedTx)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	sigStrs := make([][]string, len(signatures))
+	for i, sigs := range signatures {
+		sigStrs[i] = make([]string, len(sigs))
+		for j, sig := range sigs {
+			sigStrs[i][j], err = formatting.Encode(formatting.Hex, sig)
+			if err != nil {
+				return ids.ID{}, err
+			}
+		}
+	}
+	res := &api.JSONTxID{}
+	err = c.requester.SendRequest("issueSignedTx", &IssueSignedTxArgs{
+		UnsignedTx: txStr,
+		Signatures: sigStrs,
+		Encoding:   formatting.Hex,
+	}, res)
+	return res.TxID, err
+}
+
+// AppendSignatures adds the signatures of [user] to [partialTx], and issues
+// the tx once it's fully signed
+func (c *Client) AppendSignatures(user api.UserPass, partialTx []byte) (*AppendSignaturesReply, error) {
+	txStr, err := formatting.Encode(formatting.Hex, partialTx)
+	if err != nil {
+		return nil, err
+	}
+	res := &AppendSignaturesReply{}
+	err = c.requester.SendRequest("appendSignatures", &AppendSignaturesArgs{
+		UserPass:  user,
+		PartialTx: txStr,
+		Encoding:  formatting.Hex,
+	}, res)
+	return res, err
+}
+
 // SendNFT sends an NFT and returns the ID of the newly created transaction
 func (c *Client) SendNFT(
 	user api.UserPass,
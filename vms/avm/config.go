@@ -0,0 +1,67 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Config contains the chain specific configuration of the AVM, which is read
+// from the chain's config file.
+type Config struct {
+	// Limits the number of txs that may be issued locally per address
+	IssuanceRateLimit IssuanceRateLimitConfig `json:"issuanceRateLimit"`
+	// Configures the notifications sent to the webhooks registered by
+	// keystore users
+	Webhooks WebhookConfig `json:"webhooks"`
+	// If true, the UTXOs of each address are kept after they're spent, so
+	// that balances and UTXOs can be queried at the end of a past epoch. The
+	// history starts when it's first enabled, and again if it's enabled after
+	// the node ran without it.
+	IndexUTXOHistory bool `json:"indexUTXOHistory"`
+}
+
+// IssuanceRateLimitConfig configures the limit on the number of txs spending
+// the UTXOs of an address that may be issued through this node's API.
+type IssuanceRateLimitConfig struct {
+	// Max number of txs per address that may be issued during [Window]. If 0,
+	// issuance isn't limited.
+	MaxTxs uint32 `json:"maxTxs"`
+	// Length of the sliding window, e.g. "1m"
+	Window string `json:"window"`
+	// A tx that exceeds the limit is issued anyway if it burns at least
+	// [FeeMultiplier] times the usual fee. If 0, such txs are always rejected.
+	FeeMultiplier uint64 `json:"feeMultiplier"`
+}
+
+// WebhookConfig configures how the notifications of accepted txs are sent to
+// webhooks.
+type WebhookConfig struct {
+	// If false, keystore users can't register webhooks. The webhooks
+	// registered before are still notified.
+	Enabled bool `json:"enabled"`
+	// Hosts that webhooks may be registered at. If empty, webhooks may be
+	// registered at any host that doesn't resolve to a private IP.
+	AllowedHosts []string `json:"allowedHosts"`
+	// Number of times a notification is sent before it's dropped. If 0,
+	// defaults to 10.
+	MaxAttempts uint32 `json:"maxAttempts"`
+	// Max duration of a request to a webhook, e.g. "10s". If empty, defaults
+	// to 10 seconds.
+	Timeout string `json:"timeout"`
+}
+
+// parseConfig parses [configBytes]. Empty [configBytes] result in the default
+// config.
+func parseConfig(configBytes []byte) (Config, error) {
+	config := Config{}
+	if len(configBytes) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return config, fmt.Errorf("couldn't parse config: %w", err)
+	}
+	return config, nil
+}
@@ -45,6 +45,19 @@ type CreateAssetTx struct {
 	States       []*InitialState `serialize:"true" json:"initialStates"`
 }
 
+// InitCtx sets the context of the outputs and the initial states of this
+// transaction
+func (t *CreateAssetTx) InitCtx(ctx *snow.Context) {
+	t.BaseTx.InitCtx(ctx)
+	for _, state := range t.States {
+		for _, out := range state.Outs {
+			if out, ok := out.(snow.ContextInitializable); ok {
+				out.InitCtx(ctx)
+			}
+		}
+	}
+}
+
 // InitialStates track which virtual machines, and the initial state of these
 // machines, this asset uses. The returned array should not be modified.
 func (t *CreateAssetTx) InitialStates() []*InitialState { return t.States }
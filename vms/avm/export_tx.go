@@ -29,6 +29,12 @@ type ExportTx struct {
 	ExportedOuts []*avax.TransferableOutput `serialize:"true" json:"exportedOutputs"`
 }
 
+// InitCtx sets the context of the outputs of this transaction
+func (t *ExportTx) InitCtx(ctx *snow.Context) {
+	t.BaseTx.InitCtx(ctx)
+	initOutsCtx(ctx, t.ExportedOuts)
+}
+
 // SyntacticVerify that this transaction is well-formed.
 func (t *ExportTx) SyntacticVerify(
 	ctx *snow.Context,
@@ -47,6 +47,14 @@ type Fx interface {
 	VerifyOperation(tx, op, cred interface{}, utxos []interface{}) error
 }
 
+// UnsignedOperationVerifier is implemented by feature extensions that can
+// verify an operation before it's signed
+type UnsignedOperationVerifier interface {
+	// VerifyUnsignedOperation performs the checks of VerifyOperation that
+	// don't depend on the credential
+	VerifyUnsignedOperation(tx, op interface{}, utxos []interface{}) error
+}
+
 // FxOperation ...
 type FxOperation interface {
 	verify.Verifiable
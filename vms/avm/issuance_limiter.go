@@ -0,0 +1,203 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var errIssuanceRateLimited = errors.New("address exceeded its tx issuance rate limit")
+
+type issuance struct {
+	time  time.Time
+	addrs []ids.ShortID
+}
+
+// issuanceLimiter limits the number of txs that may be issued per address
+// during a sliding window of time.
+type issuanceLimiter struct {
+	maxTxs        uint32
+	window        time.Duration
+	feeMultiplier uint64
+
+	// Issuances during the current window, ordered by time
+	issuances []issuance
+	// Address --> Number of issuances during the current window
+	counts map[ids.ShortID]uint32
+}
+
+// newIssuanceLimiter returns a limiter configured by [config], or nil if
+// issuance isn't limited.
+func newIssuanceLimiter(config IssuanceRateLimitConfig) (*issuanceLimiter, error) {
+	if config.MaxTxs == 0 {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(config.Window)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse issuance rate limit window: %w", err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("issuance rate limit window must be positive but is %s", window)
+	}
+	return &issuanceLimiter{
+		maxTxs:        config.MaxTxs,
+		window:        window,
+		feeMultiplier: config.FeeMultiplier,
+		counts:        make(map[ids.ShortID]uint32),
+	}, nil
+}
+
+// expire drops the issuances that happened before the window ending at [now]
+func (l *issuanceLimiter) expire(now time.Time) {
+	cutoff := now.Add(-l.window)
+	i := 0
+	for ; i < len(l.issuances) && !l.issuances[i].time.After(cutoff); i++ {
+		for _, addr := range l.issuances[i].addrs {
+			if l.counts[addr] <= 1 {
+				delete(l.counts, addr)
+			} else {
+				l.counts[addr]--
+			}
+		}
+	}
+	l.issuances = l.issuances[i:]
+}
+
+// Limited returns true if any of [addrs] already had [maxTxs] txs issued
+// during the window ending at [now].
+func (l *issuanceLimiter) Limited(addrs ids.ShortSet, now time.Time) bool {
+	l.expire(now)
+	for addr := range addrs {
+		if l.counts[addr] >= l.maxTxs {
+			return true
+		}
+	}
+	return false
+}
+
+// Issued records that a tx spending the UTXOs of [addrs] was issued at [now].
+func (l *issuanceLimiter) Issued(addrs ids.ShortSet, now time.Time) {
+	if addrs.Len() == 0 {
+		return
+	}
+	for addr := range addrs {
+		l.counts[addr]++
+	}
+	l.issuances = append(l.issuances, issuance{
+		time:  now,
+		addrs: addrs.List(),
+	})
+}
+
+// spenders returns the addresses that own the UTXOs consumed by [tx] that are
+// in this chain's state. Imported UTXOs aren't considered.
+func (vm *VM) spenders(tx UnsignedTx) ids.ShortSet {
+	addrs := ids.ShortSet{}
+	for _, utxoID := range tx.InputUTXOs() {
+		if utxoID.Symbolic() {
+			continue
+		}
+		utxo, err := vm.state.GetUTXO(utxoID.InputID())
+		if err != nil {
+			continue
+		}
+		addressable, ok := utxo.Out.(avax.Addressable)
+		if !ok {
+			continue
+		}
+		for _, addrBytes := range addressable.Addresses() {
+			addr, err := ids.ToShortID(addrBytes)
+			if err != nil {
+				continue
+			}
+			addrs.Add(addr)
+		}
+	}
+	return addrs
+}
+
+// paidFee returns the amount of the fee asset burned by [tx]
+func (vm *VM) paidFee(tx UnsignedTx) uint64 {
+	switch tx := tx.(type) {
+	case *BaseTx:
+		return burnedAsset(tx.Ins, tx.Outs, vm.feeAssetID)
+	case *CreateAssetTx:
+		return burnedAsset(tx.Ins, tx.Outs, vm.feeAssetID)
+	case *OperationTx:
+		return burnedAsset(tx.Ins, tx.Outs, vm.feeAssetID)
+	case *ImportTx:
+		ins := make([]*avax.TransferableInput, 0, len(tx.Ins)+len(tx.ImportedIns))
+		ins = append(ins, tx.Ins...)
+		ins = append(ins, tx.ImportedIns...)
+		return burnedAsset(ins, tx.Outs, vm.feeAssetID)
+	case *ExportTx:
+		outs := make([]*avax.TransferableOutput, 0, len(tx.Outs)+len(tx.ExportedOuts))
+		outs = append(outs, tx.Outs...)
+		outs = append(outs, tx.ExportedOuts...)
+		return burnedAsset(tx.Ins, outs, vm.feeAssetID)
+	default:
+		return 0
+	}
+}
+
+// minFee returns the fee that a tx must burn, depending on whether it
+// [createsAsset]
+func (vm *VM) minFee(createsAsset bool) uint64 {
+	if createsAsset {
+		return vm.creationTxFee
+	}
+	return vm.txFee
+}
+
+// requiredFee returns the fee that [tx] must burn to be issued while one of
+// its spenders is rate limited
+func (vm *VM) requiredFee(tx UnsignedTx) (uint64, error) {
+	_, createsAsset := tx.(*CreateAssetTx)
+	return safemath.Mul64(vm.minFee(createsAsset), vm.issuanceLimiter.feeMultiplier)
+}
+
+// estimateFee returns the fee that a tx, depending on whether it
+// [createsAsset], must burn to be issued through this node if it spends UTXOs
+// of [spenders]. Also returns true if the fee is raised because one of
+// [spenders] is rate limited.
+func (vm *VM) estimateFee(createsAsset bool, spenders ids.ShortSet) (uint64, bool, error) {
+	fee := vm.minFee(createsAsset)
+	if vm.issuanceLimiter == nil || !vm.issuanceLimiter.Limited(spenders, vm.clock.Time()) {
+		return fee, false, nil
+	}
+	if vm.issuanceLimiter.feeMultiplier == 0 {
+		return 0, true, errIssuanceRateLimited
+	}
+	fee, err := safemath.Mul64(fee, vm.issuanceLimiter.feeMultiplier)
+	return fee, true, err
+}
+
+// checkIssuanceRate returns the addresses whose issuance should be recorded if
+// [tx] is issued, or an error if [tx] must not be issued because one of the
+// addresses spending UTXOs in [tx] is rate limited.
+func (vm *VM) checkIssuanceRate(tx UnsignedTx) (ids.ShortSet, error) {
+	now := vm.clock.Time()
+	addrs := vm.spenders(tx)
+	if !vm.issuanceLimiter.Limited(addrs, now) {
+		return addrs, nil
+	}
+	if vm.issuanceLimiter.feeMultiplier == 0 {
+		return nil, errIssuanceRateLimited
+	}
+	requiredFee, err := vm.requiredFee(tx)
+	if err != nil {
+		return nil, err
+	}
+	if paidFee := vm.paidFee(tx); paidFee < requiredFee {
+		return nil, fmt.Errorf("%w: tx burns %d but must burn at least %d", errIssuanceRateLimited, paidFee, requiredFee)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,76 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestNewIssuanceLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	l, err := newIssuanceLimiter(IssuanceRateLimitConfig{})
+	assert.NoError(err)
+	assert.Nil(l)
+
+	_, err = newIssuanceLimiter(IssuanceRateLimitConfig{MaxTxs: 1})
+	assert.Error(err)
+
+	_, err = newIssuanceLimiter(IssuanceRateLimitConfig{MaxTxs: 1, Window: "-1s"})
+	assert.Error(err)
+
+	config, err := parseConfig([]byte(`{"issuanceRateLimit":{"maxTxs":2,"window":"1m","feeMultiplier":10}}`))
+	assert.NoError(err)
+	l, err = newIssuanceLimiter(config.IssuanceRateLimit)
+	assert.NoError(err)
+	assert.EqualValues(2, l.maxTxs)
+	assert.Equal(time.Minute, l.window)
+	assert.EqualValues(10, l.feeMultiplier)
+}
+
+func TestIssuanceLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	l, err := newIssuanceLimiter(IssuanceRateLimitConfig{
+		MaxTxs: 2,
+		Window: "10s",
+	})
+	assert.NoError(err)
+
+	addr0 := ids.GenerateTestShortID()
+	addr1 := ids.GenerateTestShortID()
+	both := ids.ShortSet{}
+	both.Add(addr0, addr1)
+	only1 := ids.ShortSet{}
+	only1.Add(addr1)
+
+	now := time.Unix(1000, 0)
+	assert.False(l.Limited(both, now))
+	l.Issued(both, now)
+
+	now = now.Add(5 * time.Second)
+	assert.False(l.Limited(only1, now))
+	l.Issued(only1, now)
+
+	// [addr1] has issued 2 txs during the window
+	assert.True(l.Limited(only1, now))
+	assert.True(l.Limited(both, now))
+
+	// The first issuance falls out of the window
+	now = now.Add(5 * time.Second)
+	assert.False(l.Limited(both, now))
+	assert.Len(l.issuances, 1)
+	assert.Len(l.counts, 1)
+
+	// Every issuance falls out of the window
+	now = now.Add(5 * time.Second)
+	assert.False(l.Limited(both, now))
+	assert.Empty(l.issuances)
+	assert.Empty(l.counts)
+}
@@ -8,11 +8,29 @@ import (
 
 	"github.com/ava-labs/avalanchego/utils/metric"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// Names of the fx operations that are counted once accepted
+const (
+	secp256k1fxMintOp = "secp256k1fx_mint"
+	nftfxMintOp       = "nftfx_mint"
+	nftfxTransferOp   = "nftfx_transfer"
+	propertyfxMintOp  = "propertyfx_mint"
+	propertyfxBurnOp  = "propertyfx_burn"
+	unknownOp         = "unknown"
 )
 
 type metrics struct {
 	numTxRefreshes, numTxRefreshHits, numTxRefreshMisses prometheus.Counter
 
+	// Number of accepted fx operations of each type
+	numAcceptedOps *prometheus.CounterVec
+	// Fx operation name --> number of them accepted since the node started
+	acceptedOps map[string]uint64
+
 	apiRequestMetric metric.APIInterceptor
 }
 
@@ -35,6 +53,15 @@ func (m *metrics) Initialize(
 		Name:      "tx_refresh_misses",
 		Help:      "Number of times unique txs have not been unique and weren't cached",
 	})
+	m.numAcceptedOps = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "accepted_ops",
+			Help:      "Number of accepted fx operations of each type",
+		},
+		[]string{"op"},
+	)
+	m.acceptedOps = make(map[string]uint64)
 
 	apiRequestMetric, err := metric.NewAPIInterceptor(namespace, registerer)
 	m.apiRequestMetric = apiRequestMetric
@@ -44,6 +71,34 @@ func (m *metrics) Initialize(
 		registerer.Register(m.numTxRefreshes),
 		registerer.Register(m.numTxRefreshHits),
 		registerer.Register(m.numTxRefreshMisses),
+		registerer.Register(m.numAcceptedOps),
 	)
 	return errs.Err
 }
+
+// acceptOps counts the fx operations of an accepted tx
+func (m *metrics) acceptOps(ops []*Operation) {
+	for _, op := range ops {
+		name := fxOperationName(op.Op)
+		m.numAcceptedOps.WithLabelValues(name).Inc()
+		m.acceptedOps[name]++
+	}
+}
+
+// fxOperationName returns the name [op] is counted under
+func fxOperationName(op FxOperation) string {
+	switch op.(type) {
+	case *secp256k1fx.MintOperation:
+		return secp256k1fxMintOp
+	case *nftfx.MintOperation:
+		return nftfxMintOp
+	case *nftfx.TransferOperation:
+		return nftfxTransferOp
+	case *propertyfx.MintOperation:
+		return propertyfxMintOp
+	case *propertyfx.BurnOperation:
+		return propertyfxBurnOp
+	default:
+		return unknownOp
+	}
+}
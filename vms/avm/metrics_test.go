@@ -0,0 +1,42 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestAcceptOps(t *testing.T) {
+	assert := assert.New(t)
+
+	vm := &VM{ctx: snow.DefaultContextTest()}
+	assert.NoError(vm.metrics.Initialize("", prometheus.NewRegistry()))
+
+	vm.acceptOps([]*Operation{
+		{Op: &secp256k1fx.MintOperation{}},
+		{Op: &nftfx.TransferOperation{}},
+		{Op: &nftfx.TransferOperation{}},
+	})
+	vm.acceptOps([]*Operation{
+		{Op: &propertyfx.BurnOperation{}},
+	})
+
+	service := &Service{vm: vm}
+	reply := GetAcceptedOperationsReply{}
+	assert.NoError(service.GetAcceptedOperations(nil, nil, &reply))
+	assert.Equal(map[string]json.Uint64{
+		secp256k1fxMintOp: 1,
+		nftfxTransferOp:   2,
+		propertyfxBurnOp:  1,
+	}, reply.Operations)
+}
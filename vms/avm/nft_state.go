@@ -0,0 +1,161 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/linkeddb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+)
+
+const (
+	nftIndexCacheSize = 64
+)
+
+var (
+	// Marks that the NFTs held by the UTXOs that existed before the NFT index
+	// was introduced have been indexed
+	nftsIndexedKey = []byte{avax.IsInitializedKey + 1}
+
+	_ UTXOState = &utxoState{}
+)
+
+// UTXOState is an avax.UTXOState that also maintains an index of the UTXOs
+// holding an NFT, by address.
+type UTXOState interface {
+	avax.UTXOState
+
+	// NFTUTXOIDs returns the slice of IDs of UTXOs holding an NFT that are
+	// associated with [addr], starting after [previous].
+	// If [previous] is not in the list, starts at beginning.
+	// Returns at most [limit] IDs.
+	NFTUTXOIDs(addr []byte, previous ids.ID, limit int) ([]ids.ID, error)
+
+	// IndexNFTs adds the NFTs held by the UTXOs stored before the NFT index
+	// was introduced to the index. It is a no-op after the first call.
+	IndexNFTs() error
+}
+
+type utxoState struct {
+	avax.UTXOState
+
+	codec       codec.Manager
+	utxoDB      database.Database
+	singletonDB database.Database
+
+	nftIndexDB    database.Database
+	nftIndexCache cache.Cacher
+}
+
+func newUTXOState(
+	state avax.UTXOState,
+	codec codec.Manager,
+	utxoDB,
+	singletonDB,
+	nftIndexDB database.Database,
+) UTXOState {
+	return &utxoState{
+		UTXOState:     state,
+		codec:         codec,
+		utxoDB:        utxoDB,
+		singletonDB:   singletonDB,
+		nftIndexDB:    nftIndexDB,
+		nftIndexCache: &cache.LRU{Size: nftIndexCacheSize},
+	}
+}
+
+func (s *utxoState) PutUTXO(utxoID ids.ID, utxo *avax.UTXO) error {
+	if err := s.UTXOState.PutUTXO(utxoID, utxo); err != nil {
+		return err
+	}
+	return s.indexNFT(utxoID, utxo)
+}
+
+func (s *utxoState) DeleteUTXO(utxoID ids.ID) error {
+	utxo, err := s.GetUTXO(utxoID)
+	if err != nil {
+		return err
+	}
+	if err := s.UTXOState.DeleteUTXO(utxoID); err != nil {
+		return err
+	}
+
+	out, ok := utxo.Out.(*nftfx.TransferOutput)
+	if !ok {
+		return nil
+	}
+	for _, addr := range out.Addresses() {
+		if err := s.getNFTIndexDB(addr).Delete(utxoID[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *utxoState) NFTUTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error) {
+	indexList := s.getNFTIndexDB(addr)
+	iter := indexList.NewIteratorWithStart(start[:])
+	defer iter.Release()
+
+	utxoIDs := []ids.ID(nil)
+	for len(utxoIDs) < limit && iter.Next() {
+		utxoID, err := ids.ToID(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		if utxoID == start {
+			continue
+		}
+
+		start = ids.Empty
+		utxoIDs = append(utxoIDs, utxoID)
+	}
+	return utxoIDs, iter.Error()
+}
+
+func (s *utxoState) IndexNFTs() error {
+	indexed, err := s.singletonDB.Has(nftsIndexedKey)
+	if err != nil || indexed {
+		return err
+	}
+
+	err = avax.ForEachUTXO(s.utxoDB, s.codec, func(utxo *avax.UTXO) error {
+		return s.indexNFT(utxo.InputID(), utxo)
+	})
+	if err != nil {
+		return err
+	}
+	return s.singletonDB.Put(nftsIndexedKey, nil)
+}
+
+// indexNFT adds [utxo] to the NFT index if it holds an NFT
+func (s *utxoState) indexNFT(utxoID ids.ID, utxo *avax.UTXO) error {
+	out, ok := utxo.Out.(*nftfx.TransferOutput)
+	if !ok {
+		return nil
+	}
+	for _, addr := range out.Addresses() {
+		if err := s.getNFTIndexDB(addr).Put(utxoID[:], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *utxoState) getNFTIndexDB(addr []byte) linkeddb.LinkedDB {
+	addrStr := string(addr)
+	if indexList, exists := s.nftIndexCache.Get(addrStr); exists {
+		return indexList.(linkeddb.LinkedDB)
+	}
+
+	indexDB := prefixdb.NewNested(addr, s.nftIndexDB)
+	indexList := linkeddb.NewDefault(indexDB)
+	s.nftIndexCache.Put(addrStr, indexList)
+	return indexList
+}
@@ -25,6 +25,17 @@ type OperationTx struct {
 	Ops    []*Operation `serialize:"true" json:"operations"`
 }
 
+// InitCtx sets the context of the outputs and the operations of this
+// transaction
+func (t *OperationTx) InitCtx(ctx *snow.Context) {
+	t.BaseTx.InitCtx(ctx)
+	for _, op := range t.Ops {
+		if op, ok := op.Op.(snow.ContextInitializable); ok {
+			op.InitCtx(ctx)
+		}
+	}
+}
+
 // Operations track which ops this transaction is performing. The returned array
 // should not be modified.
 func (t *OperationTx) Operations() []*Operation { return t.Ops }
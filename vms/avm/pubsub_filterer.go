@@ -2,21 +2,60 @@ package avm
 
 import (
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 )
 
-var _ pubsub.Filterer = &filterer{}
+var (
+	_ pubsub.Filterer  = &filterer{}
+	_ pubsub.Formatter = &pubsubTx{}
+)
 
 type filterer struct {
+	vm *VM
+	tx *Tx
+}
+
+func NewPubSubFilterer(vm *VM, tx *Tx) pubsub.Filterer {
+	return &filterer{
+		vm: vm,
+		tx: tx,
+	}
+}
+
+// PubSubTx is the message sent for an accepted tx to the subscribers that
+// requested an encoding
+type PubSubTx struct {
+	TxID ids.ID `json:"txID"`
+	GetTxReply
+}
+
+// pubsubTx is the message published for an accepted tx. It's sent as
+// {"txID": ...} to the subscribers that didn't request an encoding.
+type pubsubTx struct {
+	api.JSONTxID
+	vm *VM
 	tx *Tx
 }
 
-func NewPubSubFilterer(tx *Tx) pubsub.Filterer {
-	return &filterer{tx: tx}
+func (t *pubsubTx) Format(encoding formatting.Encoding) (interface{}, error) {
+	tx, err := t.vm.formatTx(t.tx.Bytes(), encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &PubSubTx{
+		TxID: t.TxID,
+		GetTxReply: GetTxReply{
+			Tx:       tx,
+			Encoding: encoding,
+		},
+	}, nil
 }
 
-// Apply the filter on the addresses.
+// Apply the filter on the addresses and the assets. A filter matches the tx
+// if one of the tx's outputs has an address and an asset that pass the filter.
 func (f *filterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
 	resp := make([]bool, len(filters))
 	for _, utxo := range f.tx.UTXOs() {
@@ -25,16 +64,21 @@ func (f *filterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
 			continue
 		}
 
+		assetID := utxo.AssetID()
 		for _, address := range addressable.Addresses() {
 			for i, c := range filters {
 				if resp[i] {
 					continue
 				}
-				resp[i] = c.Check(address)
+				resp[i] = c.Check(address) && c.CheckAsset(assetID)
 			}
 		}
 	}
-	return resp, api.JSONTxID{
-		TxID: f.tx.ID(),
+	return resp, &pubsubTx{
+		JSONTxID: api.JSONTxID{
+			TxID: f.tx.ID(),
+		},
+		vm: f.vm,
+		tx: f.tx,
 	}
 }
@@ -13,20 +13,27 @@ import (
 )
 
 type mockFilter struct {
-	addr []byte
+	addr    []byte
+	assetID *ids.ID
 }
 
 func (f *mockFilter) Check(addr []byte) bool {
 	return bytes.Equal(addr, f.addr)
 }
 
+func (f *mockFilter) CheckAsset(assetID ids.ID) bool {
+	return f.assetID == nil || *f.assetID == assetID
+}
+
 func TestFilter(t *testing.T) {
 	assert := assert.New(t)
 
 	addrID := ids.ShortID{1}
+	assetID := ids.ID{2}
 	tx := Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
 		Outs: []*avax.TransferableOutput{
 			{
+				Asset: avax.Asset{ID: assetID},
 				Out: &secp256k1fx.TransferOutput{
 					OutputOwners: secp256k1fx.OutputOwners{
 						Addrs: []ids.ShortID{addrID},
@@ -41,7 +48,13 @@ func TestFilter(t *testing.T) {
 	err := fp.Add(addrBytes)
 	assert.NoError(err)
 
-	parser := NewPubSubFilterer(&tx)
-	fr, _ := parser.Filter([]pubsub.Filter{&mockFilter{addr: addrBytes}})
-	assert.Equal([]bool{true}, fr)
+	otherAssetID := ids.ID{3}
+	parser := NewPubSubFilterer(nil, &tx)
+	fr, _ := parser.Filter([]pubsub.Filter{
+		&mockFilter{addr: addrBytes},
+		&mockFilter{addr: addrBytes, assetID: &assetID},
+		&mockFilter{addr: addrBytes, assetID: &otherAssetID},
+		&mockFilter{addr: []byte{4}, assetID: &assetID},
+	})
+	assert.Equal([]bool{true, true, false, false}, fr)
 }
@@ -0,0 +1,61 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// Length of a stored rejection: the reason followed by the ID of its cause
+const rejectionLen = 1 + hashing.HashLen
+
+var (
+	errMalformedRejection = errors.New("malformed rejection")
+
+	_ RejectedTxState = &rejectedTxState{}
+)
+
+// RejectedTxState remembers why txs were rejected
+type RejectedTxState interface {
+	// PutRejection records that [txID] was rejected because of [reason]. [causeID]
+	// is the accepted conflict, or the rejected dependency, that caused it.
+	PutRejection(txID ids.ID, reason snowstorm.RejectionReason, causeID ids.ID) error
+
+	// GetRejection returns the reason, and the ID of the tx that caused it,
+	// that [txID] was rejected for. Returns database.ErrNotFound if the reason
+	// wasn't recorded.
+	GetRejection(txID ids.ID) (snowstorm.RejectionReason, ids.ID, error)
+}
+
+type rejectedTxState struct {
+	db database.Database
+}
+
+func NewRejectedTxState(db database.Database) RejectedTxState {
+	return &rejectedTxState{db: db}
+}
+
+func (s *rejectedTxState) PutRejection(txID ids.ID, reason snowstorm.RejectionReason, causeID ids.ID) error {
+	value := make([]byte, rejectionLen)
+	value[0] = byte(reason)
+	copy(value[1:], causeID[:])
+	return s.db.Put(txID[:], value)
+}
+
+func (s *rejectedTxState) GetRejection(txID ids.ID) (snowstorm.RejectionReason, ids.ID, error) {
+	value, err := s.db.Get(txID[:])
+	if err != nil {
+		return 0, ids.ID{}, err
+	}
+	if len(value) != rejectionLen {
+		return 0, ids.ID{}, errMalformedRejection
+	}
+	causeID, err := ids.ToID(value[1:])
+	return snowstorm.RejectionReason(value[0]), causeID, err
+}
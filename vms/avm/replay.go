@@ -0,0 +1,88 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+// divergence describes an accepted tx that doesn't pass the verification
+// rules of this node when it is replayed
+type divergence struct {
+	txID ids.ID
+	err  error
+}
+
+// replayAcceptedTxs re-verifies the last [numTxs] accepted txs, oldest first,
+// against the parsing and verification rules of this node. State isn't
+// modified. The UTXOs consumed by the replayed txs are rebuilt from the txs
+// that produced them. The UTXOs imported from other chains can't be rebuilt,
+// so only the inputs of import txs that spend UTXOs of this chain are
+// verified.
+//
+// Returns the number of replayed txs and the txs that failed verification.
+func (vm *VM) replayAcceptedTxs(numTxs int) (int, []divergence, error) {
+	txIDs, err := vm.state.RecentlyAcceptedTxs(numTxs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	vm.replaying = true
+	defer func() { vm.replaying = false }()
+
+	divergences := []divergence(nil)
+	for _, txID := range txIDs {
+		if err := vm.replayTx(txID); err != nil {
+			divergences = append(divergences, divergence{
+				txID: txID,
+				err:  err,
+			})
+		}
+	}
+	return len(txIDs), divergences, nil
+}
+
+// replayTx verifies the accepted tx [txID] as if it were being issued
+func (vm *VM) replayTx(txID ids.ID) error {
+	storedTx, err := vm.state.GetTx(txID)
+	if err != nil {
+		return fmt.Errorf("couldn't get tx: %w", err)
+	}
+	tx, err := vm.parsePrivateTx(storedTx.Bytes())
+	if err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+	if parsedID := tx.ID(); parsedID != txID {
+		return fmt.Errorf("parsed tx has ID %s", parsedID)
+	}
+	if err := tx.SyntacticVerify(vm.ctx, vm.codec, vm.feeAssetID, vm.txFee, vm.creationTxFee, len(vm.fxs)); err != nil {
+		return fmt.Errorf("syntactic verification failed: %w", err)
+	}
+
+	if importTx, ok := tx.UnsignedTx.(*ImportTx); ok {
+		err = importTx.BaseTx.SemanticVerify(vm, tx.UnsignedTx, tx.Creds)
+	} else {
+		err = tx.UnsignedTx.SemanticVerify(vm, tx.UnsignedTx, tx.Creds)
+	}
+	if err != nil {
+		return fmt.Errorf("semantic verification failed: %w", err)
+	}
+	return nil
+}
+
+// getReplayedUTXO returns the output at [outputIndex] of the stored tx [txID]
+func (vm *VM) getReplayedUTXO(txID ids.ID, outputIndex uint32) (*avax.UTXO, error) {
+	tx, err := vm.state.GetTx(txID)
+	if err != nil {
+		return nil, errMissingUTXO
+	}
+	utxos := tx.UTXOs()
+	if uint32(len(utxos)) <= outputIndex {
+		return nil, errInvalidUTXO
+	}
+	return utxos[int(outputIndex)], nil
+}
@@ -0,0 +1,47 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayAcceptedTxs(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	numReplayed, divergences, err := vm.replayAcceptedTxs(10)
+	assert.NoError(t, err)
+	assert.Zero(t, numReplayed)
+	assert.Empty(t, divergences)
+
+	newTx := NewTx(t, genesisBytes, vm)
+	tx, err := vm.ParseTx(newTx.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Verify())
+	assert.NoError(t, tx.Accept())
+
+	// The consumed UTXO is rebuilt from the genesis tx that produced it
+	numReplayed, divergences, err = vm.replayAcceptedTxs(10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, numReplayed)
+	assert.Empty(t, divergences)
+
+	// Under a rule requiring a higher fee, the accepted tx is invalid
+	vm.txFee = 1 << 40
+	numReplayed, divergences, err = vm.replayAcceptedTxs(10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, numReplayed)
+	assert.Len(t, divergences, 1)
+	assert.Equal(t, tx.ID(), divergences[0].txID)
+	assert.False(t, vm.replaying)
+}
@@ -9,13 +9,17 @@ import (
 	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/hdkey"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -31,6 +35,16 @@ const (
 
 	// Max number of addresses allowed for a single keystore user
 	maxKeystoreAddresses = 5000
+
+	// Number of consecutive unused addresses after which ScanAddresses stops
+	// by default, as recommended by BIP-44
+	defaultGapLimit = 20
+
+	// Max gap limit that can be passed in as argument to ScanAddresses
+	maxGapLimit = 1000
+
+	// Max number of txs returned by a single call to GetAddressTxs
+	maxAddressTxsPageSize = 1024
 )
 
 var (
@@ -38,6 +52,7 @@ var (
 	errTxNotCreateAsset       = errors.New("transaction doesn't create an asset")
 	errNoMinters              = errors.New("no minters provided")
 	errNoHoldersOrMinters     = errors.New("no minters or initialHolders provided")
+	errNoHolderAddresses      = errors.New("holder has no addresses")
 	errZeroAmount             = errors.New("amount must be positive")
 	errNoOutputs              = errors.New("no outputs to send")
 	errSpendOverflow          = errors.New("spent amount overflows uint64")
@@ -47,6 +62,32 @@ var (
 	errNilTxID                = errors.New("nil transaction ID")
 	errNoAddresses            = errors.New("no addresses provided")
 	errNoKeys                 = errors.New("from addresses have no keys or funds")
+	errMnemonicExists         = errors.New("user already has a mnemonic")
+	errNoMnemonic             = errors.New("user wasn't created from a mnemonic")
+	errUnknownTxType          = errors.New("unknown tx type")
+	errFeeTooLow              = errors.New("fee is below the minimum")
+	errNoFromAddrs            = errors.New("no from addresses provided")
+	errInvalidSignature       = errors.New("invalid signature")
+
+	// API methods that issue txs --> true if the tx creates an asset. Txs that
+	// create an asset burn the creation fee, and every other tx burns the tx
+	// fee.
+	txTypeCreatesAsset = map[string]bool{
+		"send":                   false,
+		"sendMultiple":           false,
+		"sendNFT":                false,
+		"mint":                   false,
+		"buildSend":              false,
+		"buildSendMultiple":      false,
+		"buildMint":              false,
+		"mintNFT":                false,
+		"import":                 false,
+		"export":                 false,
+		"createAsset":            true,
+		"createFixedCapAsset":    true,
+		"createVariableCapAsset": true,
+		"createNFTAsset":         true,
+	}
 )
 
 // Service defines the base service for the asset vm
@@ -74,9 +115,305 @@ func (service *Service) IssueTx(r *http.Request, args *api.FormattedTx, reply *a
 	return nil
 }
 
+// SimulateOperationTxReply defines the SimulateOperationTx replies returned
+// from the API
+type SimulateOperationTxReply struct {
+	// UTXOs the transaction would produce
+	UTXOs    []string            `json:"utxos"`
+	Encoding formatting.Encoding `json:"encoding"`
+	// Amount of AVAX the transaction would burn
+	Fee json.Uint64 `json:"fee"`
+	// True if the credentials of the transaction were verified. False if the
+	// transaction wasn't signed.
+	CredentialsVerified bool `json:"credentialsVerified"`
+	// Reason the transaction would be rejected, if it would be
+	Error string `json:"error,omitempty"`
+}
+
+// SimulateOperationTx verifies an operation transaction against the current
+// state without issuing it, and returns the outputs it would produce. If the
+// transaction isn't signed, everything but its credentials is verified.
+func (service *Service) SimulateOperationTx(_ *http.Request, args *api.FormattedTx, reply *SimulateOperationTxReply) error {
+	service.vm.ctx.Log.Info("AVM: SimulateOperationTx called with %s", args.Tx)
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	s, err := service.vm.simulateOperationTx(txBytes)
+	if err != nil {
+		return err
+	}
+
+	reply.UTXOs = make([]string, len(s.utxos))
+	for i, utxo := range s.utxos {
+		b, err := service.vm.codec.Marshal(codecVersion, utxo)
+		if err != nil {
+			return fmt.Errorf("problem marshalling UTXO: %w", err)
+		}
+		reply.UTXOs[i], err = formatting.Encode(args.Encoding, b)
+		if err != nil {
+			return fmt.Errorf("couldn't encode UTXO %s as string: %w", utxo.InputID(), err)
+		}
+	}
+	reply.Encoding = args.Encoding
+	reply.Fee = json.Uint64(s.fee)
+	reply.CredentialsVerified = s.credentialsVerified
+	if s.err != nil {
+		reply.Error = s.err.Error()
+	}
+	return nil
+}
+
+// ReplayAcceptedTxsArgs are the arguments for calling ReplayAcceptedTxs
+type ReplayAcceptedTxsArgs struct {
+	// Number of the most recently accepted transactions to replay
+	NumTxs json.Uint32 `json:"numTxs"`
+}
+
+// Divergence is an accepted transaction that fails verification when it is
+// replayed
+type Divergence struct {
+	TxID  ids.ID `json:"txID"`
+	Error string `json:"error"`
+}
+
+// ReplayAcceptedTxsReply defines the ReplayAcceptedTxs replies returned from
+// the API
+type ReplayAcceptedTxsReply struct {
+	// Number of transactions that were replayed
+	NumReplayed json.Uint32 `json:"numReplayed"`
+	// Replayed transactions that this node would reject
+	Divergences []Divergence `json:"divergences"`
+}
+
+// ReplayAcceptedTxs verifies the most recently accepted transactions against
+// the rules of this node without modifying any state. This allows the rules of
+// a new version of the node to be checked against real history before they
+// activate.
+func (service *Service) ReplayAcceptedTxs(_ *http.Request, args *ReplayAcceptedTxsArgs, reply *ReplayAcceptedTxsReply) error {
+	service.vm.ctx.Log.Info("AVM: ReplayAcceptedTxs called with %d", args.NumTxs)
+
+	if args.NumTxs > maxRecentlyAcceptedTxs {
+		return fmt.Errorf("can't replay more than the last %d accepted transactions", maxRecentlyAcceptedTxs)
+	}
+	numReplayed, divergences, err := service.vm.replayAcceptedTxs(int(args.NumTxs))
+	if err != nil {
+		return fmt.Errorf("problem replaying transactions: %w", err)
+	}
+
+	reply.NumReplayed = json.Uint32(numReplayed)
+	reply.Divergences = make([]Divergence, len(divergences))
+	for i, d := range divergences {
+		reply.Divergences[i] = Divergence{
+			TxID:  d.txID,
+			Error: d.err.Error(),
+		}
+	}
+	return nil
+}
+
+// GetAcceptedOperationsReply is the response from calling
+// GetAcceptedOperations
+type GetAcceptedOperationsReply struct {
+	// Fx operation name --> number of them accepted since the node started
+	Operations map[string]json.Uint64 `json:"operations"`
+}
+
+// GetAcceptedOperations returns the number of fx operations of each type that
+// this node accepted since it started, such as secp256k1fx mints and nftfx
+// transfers. Operation types that weren't accepted are omitted.
+func (service *Service) GetAcceptedOperations(_ *http.Request, _ *struct{}, reply *GetAcceptedOperationsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetAcceptedOperations called")
+
+	reply.Operations = make(map[string]json.Uint64, len(service.vm.acceptedOps))
+	for name, count := range service.vm.acceptedOps {
+		reply.Operations[name] = json.Uint64(count)
+	}
+	return nil
+}
+
+// PendingTx describes a tx issued through this node that isn't decided yet
+type PendingTx struct {
+	TxID ids.ID `json:"txID"`
+	// Size of the tx in bytes
+	Size     json.Uint64 `json:"size"`
+	IssuedAt time.Time   `json:"issuedAt"`
+	// Time since the tx was issued, e.g. "1.5s"
+	TimeInMempool string `json:"timeInMempool"`
+	// True if the tx is waiting for the batch timer to pass it to consensus.
+	// Otherwise, consensus is deciding it.
+	Batched bool           `json:"batched"`
+	Status  choices.Status `json:"status"`
+	// Why the tx fails verification, if it currently does
+	VerificationError string `json:"verificationError,omitempty"`
+}
+
+// GetPendingTxsReply is the response from calling GetPendingTxs
+type GetPendingTxsReply struct {
+	// Txs waiting to be passed to consensus, in the order they were issued
+	Batched []PendingTx `json:"batched"`
+	// Txs issued through the wallet API that aren't decided yet, in the order
+	// they were issued
+	Wallet []PendingTx `json:"wallet"`
+}
+
+// GetPendingTxs returns the txs issued through this node that are waiting to
+// be passed to consensus, and the txs issued through the wallet API that
+// aren't decided yet
+func (service *Service) GetPendingTxs(_ *http.Request, _ *struct{}, reply *GetPendingTxsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetPendingTxs called")
+
+	batched := ids.Set{}
+	reply.Batched = make([]PendingTx, 0, len(service.vm.txs))
+	for i, tx := range service.vm.txs {
+		batched.Add(tx.ID())
+		reply.Batched = append(reply.Batched, service.vm.describePendingTx(
+			tx.ID(),
+			len(tx.Bytes()),
+			service.vm.txIssueTimes[i],
+			true,
+		))
+	}
+
+	wallet := &service.vm.walletService
+	reply.Wallet = make([]PendingTx, 0, wallet.pendingTxOrdering.Len())
+	for e := wallet.pendingTxOrdering.Front(); e != nil; e = e.Next() {
+		pending := e.Value.(*walletTx)
+		txID := pending.tx.ID()
+		reply.Wallet = append(reply.Wallet, service.vm.describePendingTx(
+			txID,
+			len(pending.tx.Bytes()),
+			pending.issued,
+			batched.Contains(txID),
+		))
+	}
+	return nil
+}
+
+// describePendingTx returns the description of tx [txID] of [size] bytes,
+// which was issued at [issued]
+func (vm *VM) describePendingTx(txID ids.ID, size int, issued time.Time, batched bool) PendingTx {
+	tx := UniqueTx{
+		vm:   vm,
+		txID: txID,
+	}
+	pending := PendingTx{
+		TxID:          txID,
+		Size:          json.Uint64(size),
+		IssuedAt:      issued,
+		TimeInMempool: vm.clock.Time().Sub(issued).String(),
+		Batched:       batched,
+		Status:        tx.Status(),
+	}
+	if err := tx.verifyWithoutCacheWrites(); err != nil {
+		pending.VerificationError = err.Error()
+	}
+	return pending
+}
+
+// GetPendingTxsStatsReply is the response from calling GetPendingTxsStats
+type GetPendingTxsStatsReply struct {
+	// Number of txs waiting to be passed to consensus, and their total size
+	// in bytes
+	NumBatched   json.Uint64 `json:"numBatched"`
+	BatchedBytes json.Uint64 `json:"batchedBytes"`
+	// Number of txs issued through the wallet API that aren't decided yet,
+	// and their total size in bytes
+	NumWallet   json.Uint64 `json:"numWallet"`
+	WalletBytes json.Uint64 `json:"walletBytes"`
+}
+
+// GetPendingTxsStats returns the number and size of the txs GetPendingTxs
+// would return
+func (service *Service) GetPendingTxsStats(_ *http.Request, _ *struct{}, reply *GetPendingTxsStatsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetPendingTxsStats called")
+
+	reply.NumBatched = json.Uint64(len(service.vm.txs))
+	for _, tx := range service.vm.txs {
+		reply.BatchedBytes += json.Uint64(len(tx.Bytes()))
+	}
+
+	wallet := &service.vm.walletService
+	reply.NumWallet = json.Uint64(wallet.pendingTxOrdering.Len())
+	for e := wallet.pendingTxOrdering.Front(); e != nil; e = e.Next() {
+		reply.WalletBytes += json.Uint64(len(e.Value.(*walletTx).tx.Bytes()))
+	}
+	return nil
+}
+
 // GetTxStatusReply defines the GetTxStatus replies returned from the API
 type GetTxStatusReply struct {
 	Status choices.Status `json:"status"`
+	// Why the tx was rejected, if it was rejected and the reason is known
+	Reason string `json:"reason,omitempty"`
+	// The accepted conflict, or rejected dependency, that caused the rejection
+	CausedBy *ids.ID `json:"causedBy,omitempty"`
+}
+
+// CreateSnapshotArgs are the arguments for calling CreateSnapshot
+type CreateSnapshotArgs struct {
+	// Number of seconds the snapshot is kept open for
+	TTL json.Uint64 `json:"ttl"`
+}
+
+// CreateSnapshotReply is the response from calling CreateSnapshot
+type CreateSnapshotReply struct {
+	Snapshot ids.ID `json:"snapshot"`
+	// Unix time at which the snapshot expires
+	Expiry json.Uint64 `json:"expiry"`
+}
+
+// CreateSnapshot pins the current state of the chain. The returned snapshot ID
+// can be passed to GetTx, GetUTXOs, GetBalance, and GetAllBalances so that a
+// sequence of calls is answered from the same state, even if transactions are
+// accepted in between. The snapshot is released after its TTL expires or when
+// ReleaseSnapshot is called, whichever happens first.
+func (service *Service) CreateSnapshot(_ *http.Request, args *CreateSnapshotArgs, reply *CreateSnapshotReply) error {
+	service.vm.ctx.Log.Info("AVM: CreateSnapshot called with TTL: %d", args.TTL)
+
+	snapshotID, expiry, err := service.vm.createSnapshot(time.Duration(args.TTL) * time.Second)
+	if err != nil {
+		return err
+	}
+	reply.Snapshot = snapshotID
+	reply.Expiry = json.Uint64(expiry.Unix())
+	return nil
+}
+
+// ReleaseSnapshotArgs are the arguments for calling ReleaseSnapshot
+type ReleaseSnapshotArgs struct {
+	Snapshot ids.ID `json:"snapshot"`
+}
+
+// ReleaseSnapshot releases a snapshot created by CreateSnapshot
+func (service *Service) ReleaseSnapshot(_ *http.Request, args *ReleaseSnapshotArgs, reply *api.SuccessResponse) error {
+	service.vm.ctx.Log.Info("AVM: ReleaseSnapshot called with %s", args.Snapshot)
+
+	if err := service.vm.releaseSnapshot(args.Snapshot); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// getStateAt returns the state read by a query of the snapshot [snapshotID],
+// or of the end of [epoch] if it's non-nil. Also returns the unix time the
+// locktimes of the UTXOs are compared to.
+func (service *Service) getStateAt(snapshotID ids.ID, epoch *json.Uint32) (State, uint64, error) {
+	now := service.vm.clock.Unix()
+	if epoch == nil {
+		state, err := service.vm.getState(snapshotID)
+		return state, now, err
+	}
+	if snapshotID != ids.Empty {
+		return nil, 0, errSnapshotAndEpoch
+	}
+	state, err := service.vm.getStateAtEpoch(uint32(*epoch))
+	if err != nil {
+		return nil, 0, err
+	}
+	return state, service.vm.epochEnd(uint32(*epoch), now), nil
 }
 
 // GetTxStatus returns the status of the specified transaction
@@ -93,34 +430,134 @@ func (service *Service) GetTxStatus(r *http.Request, args *api.JSONTxID, reply *
 	}
 
 	reply.Status = tx.Status()
-	return nil
+	if reply.Status != choices.Rejected {
+		return nil
+	}
+
+	reason, causeID, err := service.vm.state.GetRejection(args.TxID)
+	switch err {
+	case nil:
+		reply.Reason = reason.String()
+		reply.CausedBy = &causeID
+		return nil
+	case database.ErrNotFound:
+		// The tx was rejected before reasons were recorded
+		return nil
+	default:
+		return fmt.Errorf("couldn't get the rejection reason of %s: %w", args.TxID, err)
+	}
 }
 
 // GetTx returns the specified transaction
-func (service *Service) GetTx(r *http.Request, args *api.GetTxArgs, reply *api.FormattedTx) error {
+// GetTxReply defines the GetTx replies returned from the API
+type GetTxReply struct {
+	// If [Encoding] is [formatting.JSON], [Tx] is the decoded tx. Otherwise,
+	// it's the string representation of the tx's bytes in [Encoding].
+	Tx       interface{}         `json:"tx"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+func (service *Service) GetTx(r *http.Request, args *api.GetTxArgs, reply *GetTxReply) error {
 	service.vm.ctx.Log.Info("AVM: GetTx called with %s", args.TxID)
 
 	if args.TxID == ids.Empty {
 		return errNilTxID
 	}
 
-	tx := UniqueTx{
-		vm:   service.vm,
-		txID: args.TxID,
-	}
-	if status := tx.Status(); !status.Fetched() {
-		return errUnknownTx
+	var txBytes []byte
+	if args.Snapshot == ids.Empty {
+		tx := UniqueTx{
+			vm:   service.vm,
+			txID: args.TxID,
+		}
+		if status := tx.Status(); !status.Fetched() {
+			return errUnknownTx
+		}
+		txBytes = tx.Bytes()
+	} else {
+		state, err := service.vm.getState(args.Snapshot)
+		if err != nil {
+			return err
+		}
+		if status, err := state.GetStatus(args.TxID); err != nil || !status.Fetched() {
+			return errUnknownTx
+		}
+		tx, err := state.GetTx(args.TxID)
+		if err != nil {
+			return errUnknownTx
+		}
+		txBytes = tx.Bytes()
 	}
 
 	var err error
-	reply.Tx, err = formatting.Encode(args.Encoding, tx.Bytes())
+	reply.Tx, err = service.vm.formatTx(txBytes, args.Encoding)
 	if err != nil {
-		return fmt.Errorf("couldn't encode tx as string: %s", err)
+		return fmt.Errorf("couldn't format tx: %s", err)
 	}
 	reply.Encoding = args.Encoding
 	return nil
 }
 
+// GetAddressTxsArgs are the arguments for calling GetAddressTxs
+type GetAddressTxsArgs struct {
+	Address string `json:"address"`
+	// Index of the first tx to return. 0 to start with the oldest tx.
+	Cursor json.Uint64 `json:"cursor"`
+	// Max number of txs to return. If 0 or more than [maxAddressTxsPageSize],
+	// up to [maxAddressTxsPageSize] txs are returned.
+	PageSize json.Uint64 `json:"pageSize"`
+}
+
+// GetAddressTxsReply defines the GetAddressTxs replies returned from the API
+type GetAddressTxsReply struct {
+	TxIDs []ids.ID `json:"txIDs"`
+	// Cursor to pass to get the next page of txs
+	Cursor json.Uint64 `json:"cursor"`
+	// False if the txs accepted before [IndexStart] aren't indexed, because
+	// the node started indexing txs by address after the chain accepted txs
+	Complete bool `json:"complete"`
+	// Unix time that the node started indexing txs by address. 0 if
+	// [Complete].
+	IndexStart json.Uint64 `json:"indexStart"`
+}
+
+// GetAddressTxs returns the IDs of the accepted txs that consumed or created
+// the UTXOs of an address, oldest first. The txs accepted before the node
+// started indexing txs by address are missing, which the reply reports.
+func (service *Service) GetAddressTxs(_ *http.Request, args *GetAddressTxsArgs, reply *GetAddressTxsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetAddressTxs called with address: %s, cursor: %d", args.Address, args.Cursor)
+
+	addr, err := service.vm.ParseLocalAddress(args.Address)
+	if err != nil {
+		return fmt.Errorf("couldn't parse address %q: %w", args.Address, err)
+	}
+
+	pageSize := int(maxAddressTxsPageSize)
+	if args.PageSize != 0 && args.PageSize < maxAddressTxsPageSize {
+		pageSize = int(args.PageSize)
+	}
+
+	txIDs, cursor, err := service.vm.state.AddressTxs(addr, uint64(args.Cursor), pageSize)
+	if err != nil {
+		return fmt.Errorf("couldn't get the txs of %s: %w", args.Address, err)
+	}
+	reply.TxIDs = txIDs
+	if reply.TxIDs == nil {
+		reply.TxIDs = []ids.ID{}
+	}
+	reply.Cursor = json.Uint64(cursor)
+
+	indexStart, err := service.vm.state.AddressTxsStart()
+	if err != nil {
+		return fmt.Errorf("couldn't get the start of the address index: %w", err)
+	}
+	reply.Complete = indexStart.IsZero()
+	if !reply.Complete {
+		reply.IndexStart = json.Uint64(indexStart.Unix())
+	}
+	return nil
+}
+
 // GetUTXOs gets all utxos for passed in addresses
 func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply *api.GetUTXOsReply) error {
 	service.vm.ctx.Log.Info("AVM: GetUTXOs called for with %s", args.Addresses)
@@ -173,7 +610,13 @@ func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply
 		err       error
 	)
 	if sourceChain == service.vm.ctx.ChainID {
-		utxos, endAddr, endUTXOID, err = service.vm.getPaginatedUTXOs(
+		var state State
+		state, _, err = service.getStateAt(args.Snapshot, args.Epoch)
+		if err != nil {
+			return err
+		}
+		utxos, endAddr, endUTXOID, err = service.vm.getPaginatedUTXOsAt(
+			state,
 			addrSet,
 			startAddr,
 			startUTXO,
@@ -263,6 +706,10 @@ type GetBalanceArgs struct {
 	Address        string `json:"address"`
 	AssetID        string `json:"assetID"`
 	IncludePartial bool   `json:"includePartial"`
+	// If non-empty, the balance is read from this snapshot
+	Snapshot ids.ID `json:"snapshot"`
+	// If non-nil, the balance at the end of this epoch is returned
+	Epoch *json.Uint32 `json:"epoch"`
 }
 
 // GetBalanceReply defines the GetBalance replies returned from the API
@@ -292,12 +739,15 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 	addrSet := ids.ShortSet{}
 	addrSet.Add(addr)
 
-	utxos, err := service.vm.getAllUTXOs(addrSet)
+	state, now, err := service.getStateAt(args.Snapshot, args.Epoch)
+	if err != nil {
+		return err
+	}
+	utxos, err := service.vm.getAllUTXOsAt(state, addrSet)
 	if err != nil {
 		return fmt.Errorf("problem retrieving UTXOs: %w", err)
 	}
 
-	now := service.vm.Clock().Unix()
 	reply.UTXOIDs = make([]avax.UTXOID, 0, len(utxos))
 	for _, utxo := range utxos {
 		if utxo.AssetID() != assetID {
@@ -333,6 +783,10 @@ type Balance struct {
 type GetAllBalancesArgs struct {
 	api.JSONAddress
 	IncludePartial bool `json:"includePartial"`
+	// If non-empty, the balances are read from this snapshot
+	Snapshot ids.ID `json:"snapshot"`
+	// If non-nil, the balances at the end of this epoch are returned
+	Epoch *json.Uint32 `json:"epoch"`
 }
 
 // GetAllBalancesReply is the response from a call to GetAllBalances
@@ -356,12 +810,15 @@ func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs
 	addrSet := ids.ShortSet{}
 	addrSet.Add(address)
 
-	utxos, err := service.vm.getAllUTXOs(addrSet)
+	state, now, err := service.getStateAt(args.Snapshot, args.Epoch)
+	if err != nil {
+		return err
+	}
+	utxos, err := service.vm.getAllUTXOsAt(state, addrSet)
 	if err != nil {
 		return fmt.Errorf("couldn't get address's UTXOs: %w", err)
 	}
 
-	now := service.vm.Clock().Unix()
 	assetIDs := ids.Set{}               // IDs of assets the address has a non-zero balance of
 	balances := make(map[ids.ID]uint64) // key: ID (as bytes). value: balance of that asset
 	for _, utxo := range utxos {
@@ -405,10 +862,158 @@ func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs
 	return nil
 }
 
+// GetNFTsArgs are arguments for passing into GetNFTs requests
+type GetNFTsArgs struct {
+	api.JSONAddress
+	// If non-empty, only the NFTs of this asset are returned
+	AssetID string `json:"assetID"`
+	// If non-empty, the NFTs are returned starting after the NFT held by
+	// this UTXO
+	StartUTXO string `json:"startUTXO"`
+	// Max number of NFTs to return. If 0, or greater than the maximum, the
+	// maximum is used.
+	Limit    json.Uint32         `json:"limit"`
+	Encoding formatting.Encoding `json:"encoding"`
+	// If non-empty, the NFTs are read from this snapshot
+	Snapshot ids.ID `json:"snapshot"`
+}
+
+// NFT describes an NFT held by an address
+type NFT struct {
+	UTXOID  ids.ID      `json:"utxoID"`
+	AssetID ids.ID      `json:"assetID"`
+	GroupID json.Uint32 `json:"groupID"`
+	Payload string      `json:"payload"`
+}
+
+// GetNFTsReply defines the GetNFTs replies returned from the API
+type GetNFTsReply struct {
+	NFTs []NFT `json:"nfts"`
+	// The UTXO to pass as StartUTXO to fetch the next page
+	EndUTXO  ids.ID              `json:"endUTXO"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetNFTs returns the NFTs held by an address, ordered by the ID of the UTXO
+// holding them.
+func (service *Service) GetNFTs(_ *http.Request, args *GetNFTsArgs, reply *GetNFTsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetNFTs called with address: %s assetID: %s", args.Address, args.AssetID)
+
+	addr, err := service.vm.ParseLocalAddress(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Address, err)
+	}
+
+	assetID := ids.Empty
+	if args.AssetID != "" {
+		assetID, err = service.vm.lookupAssetID(args.AssetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	startUTXO := ids.Empty
+	if args.StartUTXO != "" {
+		startUTXO, err = ids.FromString(args.StartUTXO)
+		if err != nil {
+			return fmt.Errorf("couldn't parse start utxo: %w", err)
+		}
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxUTXOsToFetch {
+		limit = maxUTXOsToFetch
+	}
+
+	state, err := service.vm.getState(args.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	reply.NFTs = []NFT{}
+	reply.EndUTXO = startUTXO
+	reply.Encoding = args.Encoding
+	for len(reply.NFTs) < limit {
+		utxoIDs, err := state.NFTUTXOIDs(addr.Bytes(), reply.EndUTXO, limit-len(reply.NFTs))
+		if err != nil {
+			return fmt.Errorf("couldn't get NFTs for address %s: %w", args.Address, err)
+		}
+		if len(utxoIDs) == 0 {
+			break
+		}
+		for _, utxoID := range utxoIDs {
+			reply.EndUTXO = utxoID
+
+			utxo, err := state.GetUTXO(utxoID)
+			if err != nil {
+				return fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+			}
+			if assetID != ids.Empty && utxo.AssetID() != assetID {
+				continue
+			}
+			out, ok := utxo.Out.(*nftfx.TransferOutput)
+			if !ok {
+				return fmt.Errorf("UTXO %s doesn't hold an NFT", utxoID)
+			}
+			payload, err := formatting.Encode(args.Encoding, out.Payload)
+			if err != nil {
+				return fmt.Errorf("couldn't encode payload as string: %w", err)
+			}
+			reply.NFTs = append(reply.NFTs, NFT{
+				UTXOID:  utxoID,
+				AssetID: utxo.AssetID(),
+				GroupID: json.Uint32(out.GroupID),
+				Payload: payload,
+			})
+		}
+	}
+	return nil
+}
+
 // Holder describes how much an address owns of an asset
 type Holder struct {
 	Amount  json.Uint64 `json:"amount"`
 	Address string      `json:"address"`
+	// Addresses that own the output in addition to [Address]. Either may be
+	// empty, but not both.
+	Addresses []string `json:"addresses"`
+	// Number of the owners that must sign to spend the output. Defaults to 1.
+	Threshold json.Uint32 `json:"threshold"`
+	// Unix time before which the output can't be spent
+	Locktime json.Uint64 `json:"locktime"`
+}
+
+// outputOwners returns the owners of the output described by [h]. Addresses
+// are parsed with [parseAddress].
+func (h *Holder) outputOwners(parseAddress func(string) (ids.ShortID, error)) (secp256k1fx.OutputOwners, error) {
+	addrStrs := h.Addresses
+	if h.Address != "" {
+		addrStrs = append([]string{h.Address}, addrStrs...)
+	}
+	if len(addrStrs) == 0 {
+		return secp256k1fx.OutputOwners{}, errNoHolderAddresses
+	}
+
+	owners := secp256k1fx.OutputOwners{
+		Locktime:  uint64(h.Locktime),
+		Threshold: uint32(h.Threshold),
+		Addrs:     make([]ids.ShortID, 0, len(addrStrs)),
+	}
+	if owners.Threshold == 0 {
+		owners.Threshold = 1
+	}
+	for _, addrStr := range addrStrs {
+		addr, err := parseAddress(addrStr)
+		if err != nil {
+			return secp256k1fx.OutputOwners{}, fmt.Errorf("problem parsing holder address %s: %w", addrStr, err)
+		}
+		owners.Addrs = append(owners.Addrs, addr)
+	}
+	owners.Sort()
+	if err := owners.Verify(); err != nil {
+		return secp256k1fx.OutputOwners{}, fmt.Errorf("invalid holder of %d: %w", h.Amount, err)
+	}
+	return owners, nil
 }
 
 // Owners describes who can perform an action
@@ -477,6 +1082,7 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 		map[ids.ID]uint64{
 			service.vm.feeAssetID: service.vm.creationTxFee,
 		},
+		false,
 	)
 	if err != nil {
 		return err
@@ -502,16 +1108,13 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 		Outs: make([]verify.State, 0, len(args.InitialHolders)+len(args.MinterSets)),
 	}
 	for _, holder := range args.InitialHolders {
-		addr, err := service.vm.ParseLocalAddress(holder.Address)
+		owners, err := holder.outputOwners(service.vm.ParseLocalAddress)
 		if err != nil {
 			return err
 		}
 		initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-			Amt: uint64(holder.Amount),
-			OutputOwners: secp256k1fx.OutputOwners{
-				Threshold: 1,
-				Addrs:     []ids.ShortID{addr},
-			},
+			Amt:          uint64(holder.Amount),
+			OutputOwners: owners,
 		})
 	}
 	for _, owner := range args.MinterSets {
@@ -632,6 +1235,7 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 		map[ids.ID]uint64{
 			service.vm.feeAssetID: service.vm.creationTxFee,
 		},
+		false,
 	)
 	if err != nil {
 		return err
@@ -776,66 +1380,180 @@ func (service *Service) ListAddresses(_ *http.Request, args *api.UserPass, respo
 	return db.Close()
 }
 
-// ExportKeyArgs are arguments for ExportKey
-type ExportKeyArgs struct {
+// RegisterWebhookArgs are the arguments for calling RegisterWebhook
+type RegisterWebhookArgs struct {
 	api.UserPass
-	Address string `json:"address"`
+	// URL that notifications are POSTed to
+	URL string `json:"url"`
+	// Addresses whose activity is notified
+	Addresses []string `json:"addresses"`
 }
 
-// ExportKeyReply is the response for ExportKey
-type ExportKeyReply struct {
-	// The decrypted PrivateKey for the Address provided in the arguments
-	PrivateKey string `json:"privateKey"`
+// RegisterWebhookReply is the response from calling RegisterWebhook
+type RegisterWebhookReply struct {
+	WebhookID ids.ID `json:"webhookID"`
+	// Key that notifications are signed with. It's only returned once.
+	Secret string `json:"secret"`
 }
 
-// ExportKey returns a private key from the provided user
-func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *ExportKeyReply) error {
-	service.vm.ctx.Log.Info("AVM: ExportKey called for user %q", args.Username)
-
-	addr, err := service.vm.ParseLocalAddress(args.Address)
-	if err != nil {
-		return fmt.Errorf("problem parsing address %q: %w", args.Address, err)
-	}
+// RegisterWebhook registers a webhook of user [args.Username] that is notified
+// of every accepted transaction that spends or produces a UTXO owned by one of
+// [args.Addresses]. Notifications are signed with the returned secret and are
+// retried until the webhook responds with a 2xx status or they run out of
+// attempts. Pending notifications are kept across restarts. Webhooks can only
+// be registered if the node enables them, and only at https URLs of public
+// hosts.
+func (service *Service) RegisterWebhook(_ *http.Request, args *RegisterWebhookArgs, reply *RegisterWebhookReply) error {
+	service.vm.ctx.Log.Info("AVM: RegisterWebhook called for user %q with URL %s", args.Username, args.URL)
 
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
 		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
 	}
-	defer db.Close()
-
-	user := userState{vm: service.vm}
+	if err := db.Close(); err != nil {
+		return err
+	}
 
-	sk, err := user.Key(db, addr)
-	if err != nil {
-		return fmt.Errorf("problem retrieving private key: %w", err)
+	addrs := ids.ShortSet{}
+	for _, addrStr := range args.Addresses {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addrs.Add(addr)
 	}
 
-	// We assume that the maximum size of a byte slice that
-	// can be stringified is at least the length of a SECP256K1 private key
-	privKeyStr, _ := formatting.Encode(formatting.CB58, sk.Bytes())
-	reply.PrivateKey = constants.SecretKeyPrefix + privKeyStr
-	return db.Close()
+	reply.WebhookID, reply.Secret, err = service.vm.webhooks.register(args.Username, args.URL, addrs)
+	return err
 }
 
-// ImportKeyArgs are arguments for ImportKey
-type ImportKeyArgs struct {
-	api.UserPass
-	PrivateKey string `json:"privateKey"`
+// APIWebhook is a webhook returned by ListWebhooks
+type APIWebhook struct {
+	WebhookID ids.ID   `json:"webhookID"`
+	URL       string   `json:"url"`
+	Addresses []string `json:"addresses"`
 }
 
-// ImportKeyReply is the response for ImportKey
-type ImportKeyReply struct {
-	// The address controlled by the PrivateKey provided in the arguments
-	Address string `json:"address"`
+// ListWebhooksReply is the response from calling ListWebhooks
+type ListWebhooksReply struct {
+	Webhooks []APIWebhook `json:"webhooks"`
 }
 
-// ImportKey adds a private key to the provided user
-func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *api.JSONAddress) error {
-	service.vm.ctx.Log.Info("AVM: ImportKey called for user '%s'", args.Username)
+// ListWebhooks returns the webhooks registered by user [args.Username]
+func (service *Service) ListWebhooks(_ *http.Request, args *api.UserPass, reply *ListWebhooksReply) error {
+	service.vm.ctx.Log.Info("AVM: ListWebhooks called for user %q", args.Username)
 
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving data: %w", err)
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	hookIDs := service.vm.webhooks.list(args.Username)
+	reply.Webhooks = make([]APIWebhook, len(hookIDs))
+	for i, hookID := range hookIDs {
+		hook := service.vm.webhooks.hooks[hookID]
+		addrs, err := service.vm.webhooks.formatAddresses(hook.Addresses)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+		reply.Webhooks[i] = APIWebhook{
+			WebhookID: hookID,
+			URL:       hook.URL,
+			Addresses: addrs,
+		}
+	}
+	return nil
+}
+
+// RemoveWebhookArgs are the arguments for calling RemoveWebhook
+type RemoveWebhookArgs struct {
+	api.UserPass
+	WebhookID ids.ID `json:"webhookID"`
+}
+
+// RemoveWebhook removes a webhook registered by user [args.Username]. Its
+// pending notifications are dropped.
+func (service *Service) RemoveWebhook(_ *http.Request, args *RemoveWebhookArgs, reply *api.SuccessResponse) error {
+	service.vm.ctx.Log.Info("AVM: RemoveWebhook called for user %q with %s", args.Username, args.WebhookID)
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	if err := service.vm.webhooks.remove(args.Username, args.WebhookID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ExportKeyArgs are arguments for ExportKey
+type ExportKeyArgs struct {
+	api.UserPass
+	Address string `json:"address"`
+}
+
+// ExportKeyReply is the response for ExportKey
+type ExportKeyReply struct {
+	// The decrypted PrivateKey for the Address provided in the arguments
+	PrivateKey string `json:"privateKey"`
+}
+
+// ExportKey returns a private key from the provided user
+func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *ExportKeyReply) error {
+	service.vm.ctx.Log.Info("AVM: ExportKey called for user %q", args.Username)
+
+	addr, err := service.vm.ParseLocalAddress(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address %q: %w", args.Address, err)
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	user := userState{vm: service.vm}
+
+	sk, err := user.Key(db, addr)
+	if err != nil {
+		return fmt.Errorf("problem retrieving private key: %w", err)
+	}
+
+	// We assume that the maximum size of a byte slice that
+	// can be stringified is at least the length of a SECP256K1 private key
+	privKeyStr, _ := formatting.Encode(formatting.CB58, sk.Bytes())
+	reply.PrivateKey = constants.SecretKeyPrefix + privKeyStr
+	return db.Close()
+}
+
+// ImportKeyArgs are arguments for ImportKey
+type ImportKeyArgs struct {
+	api.UserPass
+	PrivateKey string `json:"privateKey"`
+}
+
+// ImportKeyReply is the response for ImportKey
+type ImportKeyReply struct {
+	// The address controlled by the PrivateKey provided in the arguments
+	Address string `json:"address"`
+}
+
+// ImportKey adds a private key to the provided user
+func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *api.JSONAddress) error {
+	service.vm.ctx.Log.Info("AVM: ImportKey called for user '%s'", args.Username)
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving data: %w", err)
 	}
 	defer db.Close()
 
@@ -885,6 +1603,304 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *a
 	return db.Close()
 }
 
+// ImportMnemonicArgs are arguments for ImportMnemonic
+type ImportMnemonicArgs struct {
+	api.UserPass
+	// BIP-39 mnemonic the user's keys are derived from
+	Mnemonic string `json:"mnemonic"`
+	// Optional BIP-39 passphrase protecting the mnemonic
+	Passphrase string `json:"passphrase"`
+}
+
+// ImportMnemonic sets the seed of the provided user to the seed of the
+// provided mnemonic and adds the first address derived from it to the user.
+// Addresses are derived on the BIP-44 path m/44'/9000'/0'/0/i.
+func (service *Service) ImportMnemonic(_ *http.Request, args *ImportMnemonicArgs, reply *api.JSONAddress) error {
+	service.vm.ctx.Log.Info("AVM: ImportMnemonic called for user '%s'", args.Username)
+
+	seed, err := hdkey.SeedFromMnemonic(args.Mnemonic, args.Passphrase)
+	if err != nil {
+		return fmt.Errorf("problem parsing mnemonic: %w", err)
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	user := userState{vm: service.vm}
+
+	if _, err := user.Seed(db); err == nil {
+		return errMnemonicExists
+	} else if err != database.ErrNotFound {
+		return fmt.Errorf("problem retrieving seed: %w", err)
+	}
+	if err := user.SetSeed(db, seed); err != nil {
+		return fmt.Errorf("problem saving seed: %w", err)
+	}
+
+	sk, err := user.HDKey(db, 0)
+	if err != nil {
+		return fmt.Errorf("problem deriving key: %w", err)
+	}
+	if err := user.AddKey(db, sk); err != nil {
+		return fmt.Errorf("problem saving key: %w", err)
+	}
+	if err := user.SetNextIndex(db, 1); err != nil {
+		return fmt.Errorf("problem saving next index: %w", err)
+	}
+
+	reply.Address, err = service.vm.FormatLocalAddress(sk.PublicKey().Address())
+	if err != nil {
+		return fmt.Errorf("problem formatting address: %w", err)
+	}
+	return db.Close()
+}
+
+// DeriveAddressesArgs are arguments for DeriveAddresses
+type DeriveAddressesArgs struct {
+	api.UserPass
+	// Number of addresses to derive. Defaults to 1.
+	Count json.Uint32 `json:"count"`
+}
+
+// DeriveAddresses derives the next [args.Count] addresses from the seed of the
+// provided user and adds them to the user.
+func (service *Service) DeriveAddresses(_ *http.Request, args *DeriveAddressesArgs, reply *api.JSONAddresses) error {
+	service.vm.ctx.Log.Info("AVM: DeriveAddresses called for user '%s' with count %d", args.Username, args.Count)
+
+	count := uint32(args.Count)
+	if count == 0 {
+		count = 1
+	}
+	if count > maxKeystoreAddresses {
+		return fmt.Errorf("can't derive more than %d addresses", maxKeystoreAddresses)
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	user := userState{vm: service.vm}
+
+	if _, err := user.Seed(db); err == database.ErrNotFound {
+		return errNoMnemonic
+	} else if err != nil {
+		return fmt.Errorf("problem retrieving seed: %w", err)
+	}
+	nextIndex, err := user.NextIndex(db)
+	if err != nil {
+		return fmt.Errorf("problem retrieving next index: %w", err)
+	}
+
+	reply.Addresses = make([]string, 0, count)
+	for i := uint32(0); i < count; i++ {
+		sk, err := user.HDKey(db, nextIndex+i)
+		if err != nil {
+			return fmt.Errorf("problem deriving key: %w", err)
+		}
+		if err := user.AddKey(db, sk); err != nil {
+			return fmt.Errorf("problem saving key: %w", err)
+		}
+		addr, err := service.vm.FormatLocalAddress(sk.PublicKey().Address())
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+		reply.Addresses = append(reply.Addresses, addr)
+	}
+	if err := user.SetNextIndex(db, nextIndex+count); err != nil {
+		return fmt.Errorf("problem saving next index: %w", err)
+	}
+	return db.Close()
+}
+
+// ScanAddressesArgs are arguments for ScanAddresses
+type ScanAddressesArgs struct {
+	api.UserPass
+	// Number of consecutive unused addresses after which the scan stops.
+	// Defaults to 20.
+	GapLimit json.Uint32 `json:"gapLimit"`
+}
+
+// ScanAddressesReply is the response for ScanAddresses
+type ScanAddressesReply struct {
+	// Derived addresses that were used
+	Addresses []string `json:"addresses"`
+	// Index of the next address that DeriveAddresses will derive
+	NextIndex json.Uint32 `json:"nextIndex"`
+	// False if the node started indexing txs by address after the chain
+	// accepted txs. Addresses only touched by the txs accepted before then
+	// look unused, so the scan may have stopped early.
+	Complete bool `json:"complete"`
+}
+
+// ScanAddresses derives addresses from the seed of the provided user until
+// [args.GapLimit] consecutive addresses are unused. An address is used if an
+// accepted tx touched it, even if it no longer holds UTXOs. Every address up to
+// the last used one is added to the user, which is what a wallet restored from
+// the same mnemonic would do. The reply reports if the txs accepted before the
+// node started indexing txs by address are missing.
+func (service *Service) ScanAddresses(_ *http.Request, args *ScanAddressesArgs, reply *ScanAddressesReply) error {
+	service.vm.ctx.Log.Info("AVM: ScanAddresses called for user '%s' with gap limit %d", args.Username, args.GapLimit)
+
+	gapLimit := uint32(args.GapLimit)
+	if gapLimit == 0 {
+		gapLimit = defaultGapLimit
+	}
+	if gapLimit > maxGapLimit {
+		return fmt.Errorf("gap limit can't be more than %d", maxGapLimit)
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	user := userState{vm: service.vm}
+
+	if _, err := user.Seed(db); err == database.ErrNotFound {
+		return errNoMnemonic
+	} else if err != nil {
+		return fmt.Errorf("problem retrieving seed: %w", err)
+	}
+	nextIndex, err := user.NextIndex(db)
+	if err != nil {
+		return fmt.Errorf("problem retrieving next index: %w", err)
+	}
+	indexStart, err := service.vm.state.AddressTxsStart()
+	if err != nil {
+		return fmt.Errorf("problem retrieving the start of the address index: %w", err)
+	}
+	reply.Complete = indexStart.IsZero()
+
+	reply.Addresses = []string{}
+	var scanned []*crypto.PrivateKeySECP256K1R
+	for index, gap := uint32(0), uint32(0); gap < gapLimit && index < maxKeystoreAddresses; index++ {
+		sk, err := user.HDKey(db, index)
+		if err != nil {
+			return fmt.Errorf("problem deriving key: %w", err)
+		}
+		scanned = append(scanned, sk)
+
+		addr := sk.PublicKey().Address()
+		used, err := service.vm.addressUsed(addr)
+		if err != nil {
+			return err
+		}
+		if !used {
+			gap++
+			continue
+		}
+		gap = 0
+
+		addrStr, err := service.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+		reply.Addresses = append(reply.Addresses, addrStr)
+
+		// Add every address up to and including this one
+		for _, sk := range scanned {
+			if err := user.AddKey(db, sk); err != nil {
+				return fmt.Errorf("problem saving key: %w", err)
+			}
+		}
+		scanned = scanned[:0]
+		if index >= nextIndex {
+			nextIndex = index + 1
+		}
+	}
+
+	if err := user.SetNextIndex(db, nextIndex); err != nil {
+		return fmt.Errorf("problem saving next index: %w", err)
+	}
+	reply.NextIndex = json.Uint32(nextIndex)
+	return db.Close()
+}
+
+// addressUsed returns true if an accepted tx touched [addr]. UTXOs that
+// weren't created by an indexed tx, such as those in the genesis, also mark
+// their addresses as used.
+func (vm *VM) addressUsed(addr ids.ShortID) (bool, error) {
+	txIDs, _, err := vm.state.AddressTxs(addr, 0, 1)
+	if err != nil {
+		return false, fmt.Errorf("problem retrieving txs: %w", err)
+	}
+	if len(txIDs) > 0 {
+		return true, nil
+	}
+	utxoIDs, err := vm.state.UTXOIDs(addr.Bytes(), ids.Empty, 1)
+	if err != nil {
+		return false, fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+	return len(utxoIDs) > 0, nil
+}
+
+// EstimateFeeArgs are the arguments for calling EstimateFee
+type EstimateFeeArgs struct {
+	// API method the tx would be issued with, e.g. "send", "mint" or
+	// "createAsset"
+	TxType string `json:"txType"`
+	// Addresses whose UTXOs the tx may spend. If one of them is rate limited,
+	// the fee accounts for it.
+	From []string `json:"from"`
+}
+
+// EstimateFeeReply defines the EstimateFee replies returned from the API
+type EstimateFeeReply struct {
+	// Asset the fee is burned in
+	AssetID ids.ID `json:"assetID"`
+	// Min amount of [AssetID] the tx must burn
+	Fee json.Uint64 `json:"fee"`
+	// True if [Fee] is raised because one of the [From] addresses is rate
+	// limited
+	RateLimited bool `json:"rateLimited"`
+}
+
+// EstimateFee returns the fee that a tx issued through this node must burn
+func (service *Service) EstimateFee(_ *http.Request, args *EstimateFeeArgs, reply *EstimateFeeReply) error {
+	service.vm.ctx.Log.Info("AVM: EstimateFee called with txType: %s", args.TxType)
+
+	createsAsset, ok := txTypeCreatesAsset[args.TxType]
+	if !ok {
+		return fmt.Errorf("%w %q", errUnknownTxType, args.TxType)
+	}
+	fromAddrs := ids.NewShortSet(len(args.From))
+	for _, addrStr := range args.From {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+		}
+		fromAddrs.Add(addr)
+	}
+
+	fee, rateLimited, err := service.vm.estimateFee(createsAsset, fromAddrs)
+	if err != nil {
+		return err
+	}
+	reply.AssetID = service.vm.feeAssetID
+	reply.Fee = json.Uint64(fee)
+	reply.RateLimited = rateLimited
+	return nil
+}
+
+// sendFee returns the fee burned by a tx built by the send APIs: [fee] if it's
+// non-zero, or the min fee otherwise
+func (vm *VM) sendFee(fee uint64) (uint64, error) {
+	switch {
+	case fee == 0:
+		return vm.txFee, nil
+	case fee < vm.txFee:
+		return 0, fmt.Errorf("%w: fee %d is less than %d", errFeeTooLow, fee, vm.txFee)
+	default:
+		return fee, nil
+	}
+}
+
 // SendOutput specifies that [Amount] of asset [AssetID] be sent to [To]
 type SendOutput struct {
 	// The amount of funds to send
@@ -907,6 +1923,9 @@ type SendArgs struct {
 
 	// Memo field
 	Memo string `json:"memo"`
+
+	// Amount of the fee asset to burn. If 0, the min fee is burned.
+	Fee json.Uint64 `json:"fee"`
 }
 
 // SendMultipleArgs are arguments for passing into SendMultiple requests
@@ -919,6 +1938,9 @@ type SendMultipleArgs struct {
 
 	// Memo field
 	Memo string `json:"memo"`
+
+	// Amount of the fee asset to burn. If 0, the min fee is burned.
+	Fee json.Uint64 `json:"fee"`
 }
 
 // Send returns the ID of the newly created transaction
@@ -927,6 +1949,7 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *api.JSONTxI
 		JSONSpendHeader: args.JSONSpendHeader,
 		Outputs:         []SendOutput{args.SendOutput},
 		Memo:            args.Memo,
+		Fee:             args.Fee,
 	}, reply)
 }
 
@@ -941,6 +1964,10 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	} else if len(args.Outputs) == 0 {
 		return errNoOutputs
 	}
+	fee, err := service.vm.sendFee(uint64(args.Fee))
+	if err != nil {
+		return err
+	}
 
 	// Parse the from addresses
 	fromAddrs := ids.ShortSet{}
@@ -967,6 +1994,36 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		return err
 	}
 
+	tx, signers, err := service.buildSendMultiple(args.Outputs, memoBytes, fee, utxos, kc.Addrs, changeAddr)
+	if err != nil {
+		return err
+	}
+	keys := signingKeys(kc, signers)
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// buildSendMultiple returns the unsigned tx that sends [outputs] with [utxos]
+// owned by [spenders], burns [fee] and sends the change to [changeAddr]. Also
+// returns the addresses that must sign each input of the tx.
+func (service *Service) buildSendMultiple(
+	outputs []SendOutput,
+	memoBytes []byte,
+	fee uint64,
+	utxos []*avax.UTXO,
+	spenders ids.ShortSet,
+	changeAddr ids.ShortID,
+) (*Tx, [][]ids.ShortID, error) {
 	// Calculate required input amounts and create the desired outputs
 	// String repr. of asset ID --> asset ID
 	assetIDs := make(map[string]ids.ID)
@@ -974,29 +2031,30 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	amounts := make(map[ids.ID]uint64)
 	// Outputs of our tx
 	outs := []*avax.TransferableOutput{}
-	for _, output := range args.Outputs {
+	for _, output := range outputs {
 		if output.Amount == 0 {
-			return errZeroAmount
+			return nil, nil, errZeroAmount
 		}
 		assetID, ok := assetIDs[output.AssetID] // Asset ID of next output
 		if !ok {
+			var err error
 			assetID, err = service.vm.lookupAssetID(output.AssetID)
 			if err != nil {
-				return fmt.Errorf("couldn't find asset %s", output.AssetID)
+				return nil, nil, fmt.Errorf("couldn't find asset %s", output.AssetID)
 			}
 			assetIDs[output.AssetID] = assetID
 		}
 		currentAmount := amounts[assetID]
 		newAmount, err := safemath.Add64(currentAmount, uint64(output.Amount))
 		if err != nil {
-			return fmt.Errorf("problem calculating required spend amount: %w", err)
+			return nil, nil, fmt.Errorf("problem calculating required spend amount: %w", err)
 		}
 		amounts[assetID] = newAmount
 
 		// Parse the to address
 		to, err := service.vm.ParseLocalAddress(output.To)
 		if err != nil {
-			return fmt.Errorf("problem parsing to address %q: %w", output.To, err)
+			return nil, nil, fmt.Errorf("problem parsing to address %q: %w", output.To, err)
 		}
 
 		// Create the Output
@@ -1018,19 +2076,19 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		amountsWithFee[assetID] = amount
 	}
 
-	amountWithFee, err := safemath.Add64(amounts[service.vm.feeAssetID], service.vm.txFee)
+	amountWithFee, err := safemath.Add64(amounts[service.vm.feeAssetID], fee)
 	if err != nil {
-		return fmt.Errorf("problem calculating required spend amount: %w", err)
+		return nil, nil, fmt.Errorf("problem calculating required spend amount: %w", err)
 	}
 	amountsWithFee[service.vm.feeAssetID] = amountWithFee
 
-	amountsSpent, ins, keys, err := service.vm.Spend(
+	amountsSpent, ins, signers, err := service.vm.SpendFrom(
 		utxos,
-		kc,
+		spenders,
 		amountsWithFee,
 	)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Add the required change outputs
@@ -1053,25 +2111,14 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	}
 	avax.SortTransferableOutputs(outs, service.vm.codec)
 
-	tx := Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+	tx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
 		NetworkID:    service.vm.ctx.NetworkID,
 		BlockchainID: service.vm.ctx.ChainID,
 		Outs:         outs,
 		Ins:          ins,
 		Memo:         memoBytes,
 	}}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
-		return err
-	}
-
-	txID, err := service.vm.IssueTx(tx.Bytes())
-	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
-	}
-
-	reply.TxID = txID
-	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
-	return err
+	return tx, signers, nil
 }
 
 // MintArgs are arguments for passing into Mint requests
@@ -1125,15 +2172,64 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 		return err
 	}
 
-	amountsSpent, ins, keys, err := service.vm.Spend(
+	// Get all UTXOs/keys for the user
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	tx, signers, err := service.buildMint(
+		assetID,
+		uint64(args.Amount),
+		to,
 		feeUTXOs,
-		feeKc,
+		feeKc.Addrs,
+		utxos,
+		kc.Addrs,
+		changeAddr,
+	)
+	if err != nil {
+		return err
+	}
+	keys := signingKeys(kc, signers)
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// buildMint returns the unsigned tx that mints [amount] of [assetID] to [to]
+// with the mint outputs in [utxos] owned by [minters]. The fee is paid with
+// [feeUTXOs] owned by [feeSpenders], and the change is sent to [changeAddr].
+// Also returns the addresses that must sign each input and operation of the
+// tx.
+func (service *Service) buildMint(
+	assetID ids.ID,
+	amount uint64,
+	to ids.ShortID,
+	feeUTXOs []*avax.UTXO,
+	feeSpenders ids.ShortSet,
+	utxos []*avax.UTXO,
+	minters ids.ShortSet,
+	changeAddr ids.ShortID,
+) (*Tx, [][]ids.ShortID, error) {
+	amountsSpent, ins, signers, err := service.vm.SpendFrom(
+		feeUTXOs,
+		feeSpenders,
 		map[ids.ID]uint64{
 			service.vm.feeAssetID: service.vm.txFee,
 		},
 	)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	outs := []*avax.TransferableOutput{}
@@ -1151,26 +2247,20 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 		})
 	}
 
-	// Get all UTXOs/keys for the user
-	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
-	if err != nil {
-		return err
-	}
-
-	ops, opKeys, err := service.vm.Mint(
+	ops, opSigners, err := service.vm.MintFrom(
 		utxos,
-		kc,
+		minters,
 		map[ids.ID]uint64{
-			assetID: uint64(args.Amount),
+			assetID: amount,
 		},
 		to,
 	)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	keys = append(keys, opKeys...)
+	signers = append(signers, opSigners...)
 
-	tx := Tx{UnsignedTx: &OperationTx{
+	tx := &Tx{UnsignedTx: &OperationTx{
 		BaseTx: BaseTx{BaseTx: avax.BaseTx{
 			NetworkID:    service.vm.ctx.NetworkID,
 			BlockchainID: service.vm.ctx.ChainID,
@@ -1179,20 +2269,427 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 		}},
 		Ops: ops,
 	}}
-	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+	return tx, signers, nil
+}
+
+// BuildSendArgs are arguments for passing into BuildSend requests
+type BuildSendArgs struct {
+	// Addresses whose UTXOs the tx spends, and the address to send change to
+	api.JSONFromAddrs
+	api.JSONChangeAddr
+
+	// The amount, assetID, and destination to send funds to
+	SendOutput
+
+	// Memo field
+	Memo string `json:"memo"`
+
+	// Amount of the fee asset to burn. If 0, the min fee is burned.
+	Fee json.Uint64 `json:"fee"`
+
+	// Encoding of the returned tx
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BuildSendMultipleArgs are arguments for passing into BuildSendMultiple
+// requests
+type BuildSendMultipleArgs struct {
+	// Addresses whose UTXOs the tx spends, and the address to send change to
+	api.JSONFromAddrs
+	api.JSONChangeAddr
+
+	// The outputs of the transaction
+	Outputs []SendOutput `json:"outputs"`
+
+	// Memo field
+	Memo string `json:"memo"`
+
+	// Amount of the fee asset to burn. If 0, the min fee is burned.
+	Fee json.Uint64 `json:"fee"`
+
+	// Encoding of the returned tx
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BuildMintArgs are arguments for passing into BuildMint requests
+type BuildMintArgs struct {
+	// Addresses whose UTXOs the tx spends, and the address to send change to
+	api.JSONFromAddrs
+	api.JSONChangeAddr
+
+	Amount  json.Uint64 `json:"amount"`
+	AssetID string      `json:"assetID"`
+	To      string      `json:"to"`
+
+	// Encoding of the returned tx
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BuildTxReply defines the replies of the APIs that build unsigned txs
+type BuildTxReply struct {
+	// The unsigned tx. Its credentials sign the SHA-256 hash of these bytes.
+	UnsignedTx string `json:"unsignedTx"`
+	// Addresses that must sign each credential of the tx, in order. The tx has
+	// a credential for each of its inputs, followed by one for each of its
+	// operations.
+	Signers    [][]string          `json:"signers"`
+	ChangeAddr string              `json:"changeAddr"`
+	Encoding   formatting.Encoding `json:"encoding"`
+}
+
+// BuildSend returns an unsigned tx that sends funds of the [From] addresses,
+// for the tx to be signed outside of the node and issued with IssueSignedTx
+func (service *Service) BuildSend(r *http.Request, args *BuildSendArgs, reply *BuildTxReply) error {
+	return service.BuildSendMultiple(r, &BuildSendMultipleArgs{
+		JSONFromAddrs:  args.JSONFromAddrs,
+		JSONChangeAddr: args.JSONChangeAddr,
+		Outputs:        []SendOutput{args.SendOutput},
+		Memo:           args.Memo,
+		Fee:            args.Fee,
+		Encoding:       args.Encoding,
+	}, reply)
+}
+
+// BuildSendMultiple returns an unsigned tx with multiple outputs that spends
+// funds of the [From] addresses, for the tx to be signed outside of the node
+// and issued with IssueSignedTx
+func (service *Service) BuildSendMultiple(_ *http.Request, args *BuildSendMultipleArgs, reply *BuildTxReply) error {
+	service.vm.ctx.Log.Info("AVM: BuildSendMultiple called with from: %s", args.From)
+
+	// Validate the memo field
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > avax.MaxMemoSize {
+		return fmt.Errorf("max memo length is %d but provided memo field is length %d", avax.MaxMemoSize, l)
+	} else if len(args.Outputs) == 0 {
+		return errNoOutputs
+	}
+	fee, err := service.vm.sendFee(uint64(args.Fee))
+	if err != nil {
 		return err
 	}
 
-	txID, err := service.vm.IssueTx(tx.Bytes())
+	fromAddrs, changeAddr, err := service.parseSpendAddrs(args.From, args.ChangeAddr)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
+	}
+	utxos, err := service.vm.getAllUTXOs(fromAddrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
 	}
 
-	reply.TxID = txID
+	tx, signers, err := service.buildSendMultiple(args.Outputs, memoBytes, fee, utxos, fromAddrs, changeAddr)
+	if err != nil {
+		return err
+	}
+	return service.formatBuiltTx(tx, signers, changeAddr, args.Encoding, reply)
+}
+
+// BuildMint returns an unsigned tx that mints more of the asset with the mint
+// outputs of the [From] addresses, for the tx to be signed outside of the node
+// and issued with IssueSignedTx
+func (service *Service) BuildMint(_ *http.Request, args *BuildMintArgs, reply *BuildTxReply) error {
+	service.vm.ctx.Log.Info("AVM: BuildMint called with from: %s", args.From)
+
+	if args.Amount == 0 {
+		return errInvalidMintAmount
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	to, err := service.vm.ParseLocalAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+	}
+
+	fromAddrs, changeAddr, err := service.parseSpendAddrs(args.From, args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+	utxos, err := service.vm.getAllUTXOs(fromAddrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	tx, signers, err := service.buildMint(
+		assetID,
+		uint64(args.Amount),
+		to,
+		utxos,
+		fromAddrs,
+		utxos,
+		fromAddrs,
+		changeAddr,
+	)
+	if err != nil {
+		return err
+	}
+	return service.formatBuiltTx(tx, signers, changeAddr, args.Encoding, reply)
+}
+
+// parseSpendAddrs returns the addresses in [from], and the address to send
+// change to. The change goes to [changeAddr] if it's given, and to the first
+// address in [from] otherwise.
+func (service *Service) parseSpendAddrs(from []string, changeAddr string) (ids.ShortSet, ids.ShortID, error) {
+	if len(from) == 0 {
+		return nil, ids.ShortID{}, errNoFromAddrs
+	}
+	fromAddrs := ids.NewShortSet(len(from))
+	firstAddr := ids.ShortID{}
+	for i, addrStr := range from {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return nil, ids.ShortID{}, fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+		}
+		if i == 0 {
+			firstAddr = addr
+		}
+		fromAddrs.Add(addr)
+	}
+	change, err := service.vm.selectChangeAddr(firstAddr, changeAddr)
+	return fromAddrs, change, err
+}
+
+// formatBuiltTx writes the unsigned bytes of [tx] and its [signers] to [reply]
+func (service *Service) formatBuiltTx(
+	tx *Tx,
+	signers [][]ids.ShortID,
+	changeAddr ids.ShortID,
+	encoding formatting.Encoding,
+	reply *BuildTxReply,
+) error {
+	unsignedBytes, err := service.vm.codec.Marshal(codecVersion, &tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.UnsignedTx, err = formatting.Encode(encoding, unsignedBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't encode transaction: %w", err)
+	}
+	reply.Signers = make([][]string, len(signers))
+	for i, addrs := range signers {
+		reply.Signers[i] = make([]string, len(addrs))
+		for j, addr := range addrs {
+			reply.Signers[i][j], err = service.vm.FormatLocalAddress(addr)
+			if err != nil {
+				return err
+			}
+		}
+	}
 	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	reply.Encoding = encoding
 	return err
 }
 
+// IssueSignedTxArgs are arguments for passing into IssueSignedTx requests
+type IssueSignedTxArgs struct {
+	// Unsigned tx returned by one of the build APIs
+	UnsignedTx string `json:"unsignedTx"`
+	// Signatures of each credential of the tx, in the order of the signers
+	// returned by the build API. Each signature is a 65 byte recoverable
+	// secp256k1 signature of the SHA-256 hash of the unsigned tx.
+	Signatures [][]string `json:"signatures"`
+	// Encoding of [UnsignedTx] and [Signatures]
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// IssueSignedTx issues an unsigned tx along with signatures produced outside
+// of the node
+func (service *Service) IssueSignedTx(_ *http.Request, args *IssueSignedTxArgs, reply *api.JSONTxID) error {
+	service.vm.ctx.Log.Info("AVM: IssueSignedTx called")
+
+	unsignedBytes, err := formatting.Decode(args.Encoding, args.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx := &Tx{}
+	if _, err := service.vm.codec.Unmarshal(unsignedBytes, &tx.UnsignedTx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+
+	for i, sigStrs := range args.Signatures {
+		cred := &secp256k1fx.Credential{
+			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(sigStrs)),
+		}
+		for j, sigStr := range sigStrs {
+			sig, err := formatting.Decode(args.Encoding, sigStr)
+			if err != nil {
+				return fmt.Errorf("problem decoding signature %d of credential %d: %w", j, i, err)
+			}
+			if len(sig) != crypto.SECP256K1RSigLen {
+				return fmt.Errorf("%w: signature %d of credential %d is %d bytes, expected %d",
+					errInvalidSignature, j, i, len(sig), crypto.SECP256K1RSigLen)
+			}
+			copy(cred.Sigs[j][:], sig)
+		}
+		tx.Creds = append(tx.Creds, cred)
+	}
+
+	signedBytes, err := service.vm.codec.Marshal(codecVersion, tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	txID, err := service.vm.IssueTx(signedBytes)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// AppendSignaturesArgs are arguments for passing into AppendSignatures
+// requests
+type AppendSignaturesArgs struct {
+	api.UserPass
+	// Partially signed tx, as returned by wallet.send
+	PartialTx string              `json:"partialTx"`
+	Encoding  formatting.Encoding `json:"encoding"`
+}
+
+// AppendSignaturesReply defines the AppendSignatures replies returned from
+// the API
+type AppendSignaturesReply struct {
+	// ID of the tx, if it's now fully signed and was issued
+	TxID ids.ID `json:"txID"`
+	// The tx with the signatures of the user, if it's still missing
+	// signatures
+	PartialTx string `json:"partialTx,omitempty"`
+	// Addresses whose signatures are still missing
+	MissingSigners []string            `json:"missingSigners,omitempty"`
+	Encoding       formatting.Encoding `json:"encoding"`
+}
+
+// AppendSignatures adds the signatures of the keys held by the user to a
+// partially signed tx. Once the tx is fully signed, it's issued.
+func (service *Service) AppendSignatures(_ *http.Request, args *AppendSignaturesArgs, reply *AppendSignaturesReply) error {
+	service.vm.ctx.Log.Info("AVM: AppendSignatures called with username: %s", args.Username)
+
+	txBytes, err := formatting.Decode(args.Encoding, args.PartialTx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx := &Tx{}
+	if _, err := service.vm.codec.Unmarshal(txBytes, tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	unsignedBytes, err := service.vm.codec.Marshal(codecVersion, &tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := userState{vm: service.vm}
+	kc, err := user.Keychain(db, nil)
+	if err != nil {
+		return err
+	}
+
+	// The first credentials of the tx are the credentials of its inputs. The
+	// credentials of the operations and imported inputs aren't completed.
+	missingSigners := []ids.ShortID{}
+	for i, in := range localInputs(tx.UnsignedTx) {
+		if i >= len(tx.Creds) {
+			break
+		}
+		cred, ok := tx.Creds[i].(*secp256k1fx.Credential)
+		if !ok {
+			continue
+		}
+		input, ok := in.In.(*secp256k1fx.TransferInput)
+		if !ok || len(input.SigIndices) != len(cred.Sigs) {
+			continue
+		}
+
+		var owners *secp256k1fx.OutputOwners
+		for j, sigIndex := range input.SigIndices {
+			if cred.Sigs[j] != [crypto.SECP256K1RSigLen]byte{} {
+				continue
+			}
+			if owners == nil {
+				utxo, err := service.vm.getUTXO(&in.UTXOID)
+				if err != nil {
+					return fmt.Errorf("problem retrieving UTXO %s: %w", in.InputID(), err)
+				}
+				out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+				if !ok {
+					return errInvalidUTXO
+				}
+				owners = &out.OutputOwners
+			}
+			if int(sigIndex) >= len(owners.Addrs) {
+				return errInvalidUTXO
+			}
+
+			addr := owners.Addrs[sigIndex]
+			key, ok := kc.Get(addr)
+			if !ok {
+				missingSigners = append(missingSigners, addr)
+				continue
+			}
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return fmt.Errorf("problem signing transaction: %w", err)
+			}
+			copy(cred.Sigs[j][:], sig)
+		}
+	}
+
+	signedBytes, err := service.vm.codec.Marshal(codecVersion, tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Encoding = args.Encoding
+	if len(missingSigners) > 0 {
+		reply.PartialTx, err = formatting.Encode(args.Encoding, signedBytes)
+		if err != nil {
+			return fmt.Errorf("couldn't encode transaction: %w", err)
+		}
+		reply.MissingSigners = make([]string, len(missingSigners))
+		for i, addr := range missingSigners {
+			reply.MissingSigners[i], err = service.vm.FormatLocalAddress(addr)
+			if err != nil {
+				return err
+			}
+		}
+		return db.Close()
+	}
+
+	reply.TxID, err = service.vm.IssueTx(signedBytes)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+	return db.Close()
+}
+
+// localInputs returns the inputs of [tx] that spend UTXOs of this chain
+func localInputs(tx UnsignedTx) []*avax.TransferableInput {
+	switch tx := tx.(type) {
+	case *BaseTx:
+		return tx.Ins
+	case *CreateAssetTx:
+		return tx.Ins
+	case *OperationTx:
+		return tx.Ins
+	case *ImportTx:
+		return tx.Ins
+	case *ExportTx:
+		return tx.Ins
+	default:
+		return nil
+	}
+}
+
 // SendNFTArgs are arguments for passing into SendNFT requests
 type SendNFTArgs struct {
 	api.JSONSpendHeader             // User, password, from addrs, change addr
@@ -1248,6 +2745,7 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 		map[ids.ID]uint64{
 			service.vm.feeAssetID: service.vm.txFee,
 		},
+		false,
 	)
 	if err != nil {
 		return err
@@ -1364,6 +2862,7 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 		map[ids.ID]uint64{
 			service.vm.feeAssetID: service.vm.txFee,
 		},
+		false,
 	)
 	if err != nil {
 		return err
@@ -1470,7 +2969,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 		return fmt.Errorf("problem retrieving user's atomic UTXOs: %w", err)
 	}
 
-	amountsSpent, importInputs, importKeys, err := service.vm.SpendAll(atomicUTXOs, kc)
+	amountsSpent, importInputs, importKeys, err := service.vm.SpendAll(atomicUTXOs, kc, false)
 	if err != nil {
 		return err
 	}
@@ -1486,6 +2985,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 			map[ids.ID]uint64{
 				service.vm.feeAssetID: service.vm.txFee - amountSpent,
 			},
+			false,
 		)
 		if err != nil {
 			return err
@@ -1633,7 +3133,7 @@ func (service *Service) Export(_ *http.Request, args *ExportArgs, reply *api.JSO
 		amounts[assetID] = uint64(args.Amount)
 	}
 
-	amountsSpent, ins, keys, err := service.vm.Spend(utxos, kc, amounts)
+	amountsSpent, ins, keys, err := service.vm.Spend(utxos, kc, amounts, false)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,8 @@ package avm
 import (
 	"bytes"
 	"fmt"
+
+	stdjson "encoding/json"
 	"math/rand"
 	"strings"
 	"testing"
@@ -13,14 +15,19 @@ import (
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/stretchr/testify/assert"
 )
@@ -240,6 +247,40 @@ func TestServiceGetTxStatus(t *testing.T) {
 	}
 }
 
+func TestServiceGetTxStatusRejected(t *testing.T) {
+	genesisBytes, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	tx, err := vm.parseTx(NewTx(t, genesisBytes, vm).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflictID := ids.GenerateTestID()
+	if err := tx.RejectWithReason(snowstorm.ConflictAccepted, conflictID); err != nil {
+		t.Fatal(err)
+	}
+
+	statusArgs := &api.JSONTxID{TxID: tx.ID()}
+	statusReply := &GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, statusArgs, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if expected := choices.Rejected; expected != statusReply.Status {
+		t.Fatalf("Expected status %q, got %q", expected, statusReply.Status)
+	}
+	if expected := snowstorm.ConflictAccepted.String(); expected != statusReply.Reason {
+		t.Fatalf("Expected reason %q, got %q", expected, statusReply.Reason)
+	}
+	if statusReply.CausedBy == nil || *statusReply.CausedBy != conflictID {
+		t.Fatalf("Expected the rejection to be caused by %s", conflictID)
+	}
+}
+
 // Test the GetBalance method when argument Strict is true
 func TestServiceGetBalanceStrict(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
@@ -588,6 +629,194 @@ func TestServiceGetAllBalances(t *testing.T) {
 	assert.Len(t, reply.Balances, 0)
 }
 
+func TestServiceSnapshot(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	snapshotReply := &CreateSnapshotReply{}
+	err := s.CreateSnapshot(nil, &CreateSnapshotArgs{TTL: 60}, snapshotReply)
+	assert.NoError(t, err)
+
+	// Spend all of the address's UTXOs after the snapshot was taken
+	addrSet := ids.ShortSet{}
+	addrSet.Add(addrs[0])
+	utxos, err := vm.getAllUTXOs(addrSet)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, utxos)
+	for _, utxo := range utxos {
+		assert.NoError(t, vm.state.DeleteUTXO(utxo.InputID()))
+	}
+	assert.NoError(t, vm.db.Commit())
+
+	addrStr, err := vm.FormatLocalAddress(addrs[0])
+	assert.NoError(t, err)
+	balanceArgs := &GetBalanceArgs{
+		Address: addrStr,
+		AssetID: genesisTx.ID().String(),
+	}
+	balanceReply := &GetBalanceReply{}
+	err = s.GetBalance(nil, balanceArgs, balanceReply)
+	assert.NoError(t, err)
+	assert.Zero(t, uint64(balanceReply.Balance))
+
+	balanceArgs.Snapshot = snapshotReply.Snapshot
+	balanceReply = &GetBalanceReply{}
+	err = s.GetBalance(nil, balanceArgs, balanceReply)
+	assert.NoError(t, err)
+	assert.Equal(t, startBalance, uint64(balanceReply.Balance))
+
+	txReply := GetTxReply{}
+	err = s.GetTx(nil, &api.GetTxArgs{
+		TxID:     genesisTx.ID(),
+		Snapshot: snapshotReply.Snapshot,
+	}, &txReply)
+	assert.NoError(t, err)
+	txBytes, err := formatting.Decode(txReply.Encoding, txReply.Tx.(string))
+	assert.NoError(t, err)
+	assert.Equal(t, genesisTx.Bytes(), txBytes)
+
+	releaseReply := &api.SuccessResponse{}
+	err = s.ReleaseSnapshot(nil, &ReleaseSnapshotArgs{Snapshot: snapshotReply.Snapshot}, releaseReply)
+	assert.NoError(t, err)
+	assert.True(t, releaseReply.Success)
+
+	err = s.GetBalance(nil, balanceArgs, balanceReply)
+	assert.Error(t, err)
+
+	// A released snapshot can't be released again
+	releaseReply = &api.SuccessResponse{}
+	err = s.ReleaseSnapshot(nil, &ReleaseSnapshotArgs{Snapshot: snapshotReply.Snapshot}, releaseReply)
+	assert.Error(t, err)
+	assert.False(t, releaseReply.Success)
+}
+
+func TestServiceGetNFTs(t *testing.T) {
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	assetIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	nftUTXOs := make([]*avax.UTXO, 3)
+	for i := range nftUTXOs {
+		nftUTXOs[i] = &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID:        ids.GenerateTestID(),
+				OutputIndex: 0,
+			},
+			Asset: avax.Asset{ID: assetIDs[i%2]},
+			Out: &nftfx.TransferOutput{
+				GroupID: uint32(i),
+				Payload: []byte{byte(i)},
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addrs[0]},
+				},
+			},
+		}
+		assert.NoError(t, vm.state.PutUTXO(nftUTXOs[i].InputID(), nftUTXOs[i]))
+	}
+
+	addrStr, err := vm.FormatLocalAddress(addrs[0])
+	assert.NoError(t, err)
+
+	// Page through the NFTs one at a time
+	seen := map[ids.ID]NFT{}
+	args := &GetNFTsArgs{
+		JSONAddress: api.JSONAddress{Address: addrStr},
+		Limit:       1,
+		Encoding:    formatting.Hex,
+	}
+	for {
+		reply := &GetNFTsReply{}
+		assert.NoError(t, s.GetNFTs(nil, args, reply))
+		if len(reply.NFTs) == 0 {
+			break
+		}
+		assert.Len(t, reply.NFTs, 1)
+		seen[reply.NFTs[0].UTXOID] = reply.NFTs[0]
+		args.StartUTXO = reply.EndUTXO.String()
+	}
+	assert.Len(t, seen, len(nftUTXOs))
+	for i, utxo := range nftUTXOs {
+		nft, ok := seen[utxo.InputID()]
+		assert.True(t, ok)
+		assert.Equal(t, utxo.AssetID(), nft.AssetID)
+		assert.EqualValues(t, i, nft.GroupID)
+		payload, err := formatting.Decode(formatting.Hex, nft.Payload)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{byte(i)}, payload)
+	}
+
+	// Filter the NFTs by asset
+	reply := &GetNFTsReply{}
+	err = s.GetNFTs(nil, &GetNFTsArgs{
+		JSONAddress: api.JSONAddress{Address: addrStr},
+		AssetID:     assetIDs[1].String(),
+		Encoding:    formatting.Hex,
+	}, reply)
+	assert.NoError(t, err)
+	assert.Len(t, reply.NFTs, 1)
+	assert.Equal(t, nftUTXOs[1].InputID(), reply.NFTs[0].UTXOID)
+}
+
+func TestServiceSnapshotExpiry(t *testing.T) {
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	err := s.CreateSnapshot(nil, &CreateSnapshotArgs{TTL: 0}, &CreateSnapshotReply{})
+	assert.Error(t, err)
+
+	snapshotReply := &CreateSnapshotReply{}
+	err = s.CreateSnapshot(nil, &CreateSnapshotArgs{TTL: 60}, snapshotReply)
+	assert.NoError(t, err)
+
+	vm.clock.Set(vm.clock.Time().Add(time.Minute))
+	_, err = vm.getState(snapshotReply.Snapshot)
+	assert.Error(t, err)
+	assert.Empty(t, vm.snapshots)
+}
+
+func TestSnapshotExpiresWithoutAPICalls(t *testing.T) {
+	_, vm, _, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	snapshotID, _, err := vm.createSnapshot(time.Millisecond)
+	assert.NoError(t, err)
+	snapshot := vm.snapshots[snapshotID]
+
+	// The snapshot is closed once it expires, even though it's never used
+	// again, so writes stop being preserved in it
+	expired := false
+	for i := 0; i < 100 && !expired; i++ {
+		vm.ctx.Lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		vm.ctx.Lock.Lock()
+		_, open := vm.snapshots[snapshotID]
+		expired = !open
+	}
+	assert.True(t, expired)
+	assert.Equal(t, database.ErrClosed, snapshot.db.Close())
+}
+
 func TestServiceGetTx(t *testing.T) {
 	_, vm, s, _, genesisTx := setup(t, true)
 	defer func() {
@@ -599,7 +828,7 @@ func TestServiceGetTx(t *testing.T) {
 
 	txID := genesisTx.ID()
 
-	reply := api.FormattedTx{}
+	reply := GetTxReply{}
 	err := s.GetTx(nil, &api.GetTxArgs{
 		TxID: txID,
 	}, &reply)
@@ -607,13 +836,43 @@ func TestServiceGetTx(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	txBytes, err := formatting.Decode(reply.Encoding, reply.Tx)
+	txBytes, err := formatting.Decode(reply.Encoding, reply.Tx.(string))
 	if err != nil {
 		t.Fatal(err)
 	}
 	assert.Equal(t, genesisTx.Bytes(), txBytes, "Wrong tx returned from service.GetTx")
 }
 
+func TestServiceGetTxJSON(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	reply := GetTxReply{}
+	err := s.GetTx(nil, &api.GetTxArgs{
+		TxID:     genesisTx.ID(),
+		Encoding: formatting.JSON,
+	}, &reply)
+	assert.NoError(t, err)
+	assert.Equal(t, formatting.JSON, reply.Encoding)
+
+	tx, ok := reply.Tx.(*Tx)
+	assert.True(t, ok)
+	assert.Equal(t, genesisTx.ID(), tx.ID())
+
+	// The addresses of the outputs are formatted for this chain
+	jsonBytes, err := stdjson.Marshal(reply.Tx)
+	assert.NoError(t, err)
+	addrStr, err := vm.FormatLocalAddress(addrs[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(jsonBytes), fmt.Sprintf("%q", addrStr))
+	assert.Contains(t, string(jsonBytes), `"unsignedTx"`)
+}
+
 func TestServiceGetNilTx(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
 	defer func() {
@@ -623,7 +882,7 @@ func TestServiceGetNilTx(t *testing.T) {
 		vm.ctx.Lock.Unlock()
 	}()
 
-	reply := api.FormattedTx{}
+	reply := GetTxReply{}
 	err := s.GetTx(nil, &api.GetTxArgs{}, &reply)
 	assert.Error(t, err, "Nil TxID should have returned an error")
 }
@@ -637,11 +896,134 @@ func TestServiceGetUnknownTx(t *testing.T) {
 		vm.ctx.Lock.Unlock()
 	}()
 
-	reply := api.FormattedTx{}
+	reply := GetTxReply{}
 	err := s.GetTx(nil, &api.GetTxArgs{TxID: ids.Empty}, &reply)
 	assert.Error(t, err, "Unknown TxID should have returned an error")
 }
 
+func TestServiceGetPendingTxs(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(t, vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	issued := time.Unix(1600000000, 0)
+	vm.clock.Set(issued)
+	newTx := NewTx(t, genesisBytes, vm)
+	txID, err := vm.walletService.issue(newTx.Bytes())
+	assert.NoError(t, err)
+	vm.timer.Cancel()
+	vm.clock.Set(issued.Add(2 * time.Second))
+
+	expected := PendingTx{
+		TxID:          txID,
+		Size:          json.Uint64(len(newTx.Bytes())),
+		IssuedAt:      issued,
+		TimeInMempool: "2s",
+		Batched:       true,
+		Status:        choices.Processing,
+	}
+	reply := GetPendingTxsReply{}
+	assert.NoError(t, s.GetPendingTxs(nil, nil, &reply))
+	assert.Equal(t, []PendingTx{expected}, reply.Batched)
+	assert.Equal(t, []PendingTx{expected}, reply.Wallet)
+
+	stats := GetPendingTxsStatsReply{}
+	assert.NoError(t, s.GetPendingTxsStats(nil, nil, &stats))
+	assert.Equal(t, GetPendingTxsStatsReply{
+		NumBatched:   1,
+		BatchedBytes: expected.Size,
+		NumWallet:    1,
+		WalletBytes:  expected.Size,
+	}, stats)
+
+	// Once the tx is passed to consensus, only the wallet service tracks it
+	txs := vm.PendingTxs()
+	assert.Len(t, txs, 1)
+	expected.Batched = false
+	reply = GetPendingTxsReply{}
+	assert.NoError(t, s.GetPendingTxs(nil, nil, &reply))
+	assert.Empty(t, reply.Batched)
+	assert.Equal(t, []PendingTx{expected}, reply.Wallet)
+
+	assert.NoError(t, txs[0].Verify())
+	assert.NoError(t, txs[0].Accept())
+	reply = GetPendingTxsReply{}
+	assert.NoError(t, s.GetPendingTxs(nil, nil, &reply))
+	assert.Empty(t, reply.Batched)
+	assert.Empty(t, reply.Wallet)
+}
+
+func TestServiceGetAddressTxs(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(t, vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(t, err)
+
+	// Accepting a tx that spends a UTXO of the address indexes the tx
+	newTx := NewTx(t, genesisBytes, vm)
+	tx, err := vm.ParseTx(newTx.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Verify())
+	assert.NoError(t, tx.Accept())
+
+	reply := GetAddressTxsReply{}
+	err = s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: addrStr}, &reply)
+	assert.NoError(t, err)
+	assert.Equal(t, []ids.ID{tx.ID()}, reply.TxIDs)
+	assert.Equal(t, json.Uint64(1), reply.Cursor)
+	assert.True(t, reply.Complete)
+	assert.Zero(t, reply.IndexStart)
+
+	otherTxID := ids.GenerateTestID()
+	assert.NoError(t, vm.state.AddAddressTx(keys[0].PublicKey().Address(), otherTxID))
+
+	reply = GetAddressTxsReply{}
+	err = s.GetAddressTxs(nil, &GetAddressTxsArgs{
+		Address:  addrStr,
+		Cursor:   1,
+		PageSize: 1,
+	}, &reply)
+	assert.NoError(t, err)
+	assert.Equal(t, []ids.ID{otherTxID}, reply.TxIDs)
+	assert.Equal(t, json.Uint64(2), reply.Cursor)
+
+	reply = GetAddressTxsReply{}
+	err = s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: addrStr, Cursor: 2}, &reply)
+	assert.NoError(t, err)
+	assert.Empty(t, reply.TxIDs)
+	assert.Equal(t, json.Uint64(2), reply.Cursor)
+
+	err = s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: "bad"}, &reply)
+	assert.Error(t, err)
+
+	// A node that started indexing txs by address after the chain accepted txs
+	// reports that the txs accepted before then are missing
+	state := NewState(memdb.New(), vm.genesisCodec, vm.codec)
+	assert.NoError(t, state.SetInitialized())
+	vm.state = state
+	vm.Aliaser.Initialize()
+	now := time.Unix(1000, 0)
+	vm.clock.Set(now)
+	assert.NoError(t, vm.initGenesis(genesisBytes))
+
+	reply = GetAddressTxsReply{}
+	err = s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: addrStr}, &reply)
+	assert.NoError(t, err)
+	assert.Empty(t, reply.TxIDs)
+	assert.False(t, reply.Complete)
+	assert.Equal(t, json.Uint64(now.Unix()), reply.IndexStart)
+}
+
 func TestServiceGetUTXOs(t *testing.T) {
 	_, vm, s, m, _ := setup(t, true)
 	defer func() {
@@ -1437,6 +1819,85 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func TestSendWithFee(t *testing.T) {
+	_, vm, s, _, genesisTx := setupWithKeys(t, true)
+	defer func() {
+		assert.NoError(t, vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(t, err)
+	args := &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+		},
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      addrStr,
+		},
+		Fee: json.Uint64(testTxFee - 1),
+	}
+	reply := &api.JSONTxIDChangeAddr{}
+	vm.timer.Cancel()
+	err = s.Send(nil, args, reply)
+	assert.ErrorIs(t, err, errFeeTooLow)
+
+	args.Fee = json.Uint64(3 * testTxFee)
+	assert.NoError(t, s.Send(nil, args, reply))
+	assert.Len(t, vm.txs, 1)
+	tx := vm.txs[0].(*UniqueTx)
+	assert.Equal(t, 3*testTxFee, vm.paidFee(tx.UnsignedTx))
+}
+
+func TestServiceEstimateFee(t *testing.T) {
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(t, vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	reply := EstimateFeeReply{}
+	assert.NoError(t, s.EstimateFee(nil, &EstimateFeeArgs{TxType: "send"}, &reply))
+	assert.Equal(t, EstimateFeeReply{
+		AssetID: vm.feeAssetID,
+		Fee:     json.Uint64(vm.txFee),
+	}, reply)
+
+	reply = EstimateFeeReply{}
+	assert.NoError(t, s.EstimateFee(nil, &EstimateFeeArgs{TxType: "createAsset"}, &reply))
+	assert.Equal(t, json.Uint64(vm.creationTxFee), reply.Fee)
+
+	err := s.EstimateFee(nil, &EstimateFeeArgs{TxType: "burn"}, &reply)
+	assert.ErrorIs(t, err, errUnknownTxType)
+
+	// The fee of a rate limited address is raised
+	addr := keys[0].PublicKey().Address()
+	addrStr, err := vm.FormatLocalAddress(addr)
+	assert.NoError(t, err)
+	vm.issuanceLimiter, err = newIssuanceLimiter(IssuanceRateLimitConfig{
+		MaxTxs:        1,
+		Window:        "1m",
+		FeeMultiplier: 10,
+	})
+	assert.NoError(t, err)
+	vm.issuanceLimiter.Issued(ids.ShortSet{addr: struct{}{}}, vm.clock.Time())
+
+	reply = EstimateFeeReply{}
+	assert.NoError(t, s.EstimateFee(nil, &EstimateFeeArgs{
+		TxType: "send",
+		From:   []string{addrStr},
+	}, &reply))
+	assert.Equal(t, json.Uint64(10*vm.txFee), reply.Fee)
+	assert.True(t, reply.RateLimited)
+}
+
 func TestSendMultiple(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1507,6 +1968,62 @@ func TestSendMultiple(t *testing.T) {
 	}
 }
 
+func TestBuildSendAndIssueSignedTx(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(err)
+	buildArgs := &BuildSendArgs{
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      addrStr,
+		},
+		Encoding: formatting.Hex,
+	}
+	buildReply := &BuildTxReply{}
+	err = s.BuildSend(nil, buildArgs, buildReply)
+	assert.ErrorIs(err, errNoFromAddrs)
+
+	// The tx spends a UTXO of [keys[0]] without its key being in the keystore
+	buildArgs.From = []string{addrStr}
+	assert.NoError(s.BuildSend(nil, buildArgs, buildReply))
+	assert.Equal([][]string{{addrStr}}, buildReply.Signers)
+	assert.Equal(addrStr, buildReply.ChangeAddr)
+
+	unsignedBytes, err := formatting.Decode(buildReply.Encoding, buildReply.UnsignedTx)
+	assert.NoError(err)
+	hash := hashing.ComputeHash256(unsignedBytes)
+	sign := func(key *crypto.PrivateKeySECP256K1R) string {
+		sig, err := key.SignHash(hash)
+		assert.NoError(err)
+		sigStr, err := formatting.Encode(formatting.Hex, sig)
+		assert.NoError(err)
+		return sigStr
+	}
+
+	vm.timer.Cancel()
+	issueArgs := &IssueSignedTxArgs{
+		UnsignedTx: buildReply.UnsignedTx,
+		Signatures: [][]string{{sign(keys[1])}},
+		Encoding:   formatting.Hex,
+	}
+	reply := &api.JSONTxID{}
+	assert.Error(s.IssueSignedTx(nil, issueArgs, reply))
+	assert.Empty(vm.txs)
+
+	issueArgs.Signatures = [][]string{{sign(keys[0])}}
+	assert.NoError(s.IssueSignedTx(nil, issueArgs, reply))
+	assert.Len(vm.txs, 1)
+	assert.Equal(vm.txs[0].ID(), reply.TxID)
+}
+
 func TestCreateAndListAddresses(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
 	defer func() {
@@ -1546,6 +2063,102 @@ func TestCreateAndListAddresses(t *testing.T) {
 	t.Fatalf("Failed to find newly created address among %d addresses", len(listReply.Addresses))
 }
 
+func TestMnemonicAddresses(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	user := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+
+	deriveReply := &api.JSONAddresses{}
+	err := s.DeriveAddresses(nil, &DeriveAddressesArgs{UserPass: user}, deriveReply)
+	assert.Equal(t, errNoMnemonic, err)
+
+	importArgs := &ImportMnemonicArgs{
+		UserPass: user,
+		Mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+	}
+	importReply := &api.JSONAddress{}
+	err = s.ImportMnemonic(nil, importArgs, importReply)
+	assert.NoError(t, err)
+	err = s.ImportMnemonic(nil, importArgs, &api.JSONAddress{})
+	assert.Equal(t, errMnemonicExists, err)
+
+	err = s.DeriveAddresses(nil, &DeriveAddressesArgs{UserPass: user, Count: 2}, deriveReply)
+	assert.NoError(t, err)
+	assert.Len(t, deriveReply.Addresses, 2)
+
+	listReply := &api.JSONAddresses{}
+	err = s.ListAddresses(nil, &user, listReply)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]string{importReply.Address}, deriveReply.Addresses...), listReply.Addresses)
+
+	// Fund the address at index 5, which hasn't been derived yet
+	db, err := vm.ctx.Keystore.GetDatabase(username, password)
+	assert.NoError(t, err)
+	sk, err := (&userState{vm: vm}).HDKey(db, 5)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: genesisTx.ID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1337,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{sk.PublicKey().Address()},
+			},
+		},
+	}
+	err = vm.state.PutUTXO(utxo.InputID(), utxo)
+	assert.NoError(t, err)
+
+	fundedAddr, err := vm.FormatLocalAddress(sk.PublicKey().Address())
+	assert.NoError(t, err)
+
+	// A gap limit of 2 stops the scan before reaching the funded address
+	scanReply := &ScanAddressesReply{}
+	err = s.ScanAddresses(nil, &ScanAddressesArgs{UserPass: user, GapLimit: 2}, scanReply)
+	assert.NoError(t, err)
+	assert.Empty(t, scanReply.Addresses)
+	assert.EqualValues(t, 3, scanReply.NextIndex)
+
+	err = s.ScanAddresses(nil, &ScanAddressesArgs{UserPass: user}, scanReply)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fundedAddr}, scanReply.Addresses)
+	assert.EqualValues(t, 6, scanReply.NextIndex)
+	assert.True(t, scanReply.Complete)
+
+	err = s.ListAddresses(nil, &user, listReply)
+	assert.NoError(t, err)
+	assert.Len(t, listReply.Addresses, 6)
+	assert.Equal(t, fundedAddr, listReply.Addresses[5])
+
+	// An address that no longer holds UTXOs is still used if a tx touched it
+	db, err = vm.ctx.Keystore.GetDatabase(username, password)
+	assert.NoError(t, err)
+	spentSK, err := (&userState{vm: vm}).HDKey(db, 8)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+	assert.NoError(t, vm.state.AddAddressTx(spentSK.PublicKey().Address(), ids.GenerateTestID()))
+
+	spentAddr, err := vm.FormatLocalAddress(spentSK.PublicKey().Address())
+	assert.NoError(t, err)
+
+	err = s.ScanAddresses(nil, &ScanAddressesArgs{UserPass: user}, scanReply)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fundedAddr, spentAddr}, scanReply.Addresses)
+	assert.EqualValues(t, 9, scanReply.NextIndex)
+}
+
 func TestImport(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,169 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errNotOperationTx        = errors.New("only operation transactions can be simulated")
+	errCantVerifyUnsignedOps = errors.New("feature extension can't verify unsigned operations")
+)
+
+// simulation is the result of simulating a transaction
+type simulation struct {
+	// UTXOs the transaction would produce
+	utxos []*avax.UTXO
+	// Amount of AVAX the transaction would burn
+	fee uint64
+	// True if the credentials of the transaction were verified
+	credentialsVerified bool
+	// Reason the transaction would be rejected, or nil if it would be accepted
+	// into consensus
+	err error
+}
+
+// simulateOperationTx verifies the operation transaction [txBytes] against the
+// current state without issuing it. If the transaction has no credentials, it
+// is assumed to not be signed yet, so everything but its credentials is
+// verified. Returns an error if [txBytes] isn't an operation transaction.
+func (vm *VM) simulateOperationTx(txBytes []byte) (*simulation, error) {
+	if !vm.bootstrapped {
+		return nil, errBootstrapping
+	}
+	tx, err := vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	opTx, ok := tx.UnsignedTx.(*OperationTx)
+	if !ok {
+		return nil, errNotOperationTx
+	}
+
+	s := &simulation{
+		utxos:               opTx.UTXOs(),
+		fee:                 burnedAsset(opTx.Ins, opTx.Outs, vm.ctx.AVAXAssetID),
+		credentialsVerified: len(tx.Creds) > 0,
+	}
+	if s.credentialsVerified {
+		if err := tx.SyntacticVerify(vm.ctx, vm.codec, vm.ctx.AVAXAssetID, vm.txFee, vm.creationTxFee, len(vm.fxs)); err != nil {
+			s.err = err
+			return s, nil
+		}
+		s.err = tx.SemanticVerify(vm, opTx)
+		return s, nil
+	}
+
+	if err := opTx.SyntacticVerify(vm.ctx, vm.codec, vm.ctx.AVAXAssetID, vm.txFee, vm.creationTxFee, len(vm.fxs)); err != nil {
+		s.err = err
+		return s, nil
+	}
+	s.err = vm.verifyUnsignedOperationTx(opTx)
+	return s, nil
+}
+
+// verifyUnsignedOperationTx performs the semantic verification of [tx] that
+// doesn't depend on its credentials. That is, it verifies that the consumed
+// UTXOs exist, that the assets support the feature extensions used and that
+// the feature extensions allow the operations.
+func (vm *VM) verifyUnsignedOperationTx(tx *OperationTx) error {
+	for _, in := range tx.Ins {
+		utxo, err := vm.getUTXO(&in.UTXOID)
+		if err != nil {
+			return err
+		}
+		if utxo.AssetID() != in.AssetID() {
+			return errAssetIDMismatch
+		}
+		fxIndex, err := vm.getFx(in.In)
+		if err != nil {
+			return err
+		}
+		if !vm.verifyFxUsage(fxIndex, in.AssetID()) {
+			return errIncompatibleFx
+		}
+	}
+	for _, out := range tx.Outs {
+		fxIndex, err := vm.getFx(out.Out)
+		if err != nil {
+			return err
+		}
+		if !vm.verifyFxUsage(fxIndex, out.AssetID()) {
+			return errIncompatibleFx
+		}
+	}
+	for _, op := range tx.Ops {
+		if err := vm.verifyUnsignedOperation(tx, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyUnsignedOperation is verifyOperation without the credential
+func (vm *VM) verifyUnsignedOperation(tx UnsignedTx, op *Operation) error {
+	opAssetID := op.AssetID()
+
+	utxos := make([]interface{}, len(op.UTXOIDs))
+	for i, utxoID := range op.UTXOIDs {
+		utxo, err := vm.getUTXO(utxoID)
+		if err != nil {
+			return err
+		}
+		if utxo.AssetID() != opAssetID {
+			return errAssetIDMismatch
+		}
+		utxos[i] = utxo.Out
+	}
+
+	fxIndex, err := vm.getFx(op.Op)
+	if err != nil {
+		return err
+	}
+	if !vm.verifyFxUsage(fxIndex, opAssetID) {
+		return errIncompatibleFx
+	}
+	fx, ok := vm.fxs[fxIndex].Fx.(UnsignedOperationVerifier)
+	if !ok {
+		return errCantVerifyUnsignedOps
+	}
+	return fx.VerifyUnsignedOperation(tx, op.Op, utxos)
+}
+
+// burnedAsset returns the amount of [assetID] consumed by [ins] but not
+// produced by [outs], or 0 if the amounts overflow
+func burnedAsset(ins []*avax.TransferableInput, outs []*avax.TransferableOutput, assetID ids.ID) uint64 {
+	var consumed, produced uint64
+	for _, in := range ins {
+		if in.AssetID() != assetID {
+			continue
+		}
+		amount, err := safemath.Add64(consumed, in.Input().Amount())
+		if err != nil {
+			return 0
+		}
+		consumed = amount
+	}
+	for _, out := range outs {
+		if out.AssetID() != assetID {
+			continue
+		}
+		amount, err := safemath.Add64(produced, out.Output().Amount())
+		if err != nil {
+			return 0
+		}
+		produced = amount
+	}
+	burned, err := safemath.Sub64(consumed, produced)
+	if err != nil {
+		return 0
+	}
+	return burned
+}
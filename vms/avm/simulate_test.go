@@ -0,0 +1,161 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestSimulateOperationTx(t *testing.T) {
+	vm := &VM{}
+	ctx := NewContext(t)
+	ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(t, vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	genesisBytes := BuildGenesisTest(t)
+	issuer := make(chan common.Message, 1)
+	err := vm.Initialize(
+		ctx,
+		manager.NewMemDB(version.DefaultVersion1_0_0),
+		genesisBytes,
+		nil,
+		nil,
+		issuer,
+		[]*common.Fx{
+			{
+				ID: ids.Empty.Prefix(0),
+				Fx: &secp256k1fx.Fx{},
+			},
+			{
+				ID: ids.Empty.Prefix(1),
+				Fx: &nftfx.Fx{},
+			},
+		},
+	)
+	assert.NoError(t, err)
+	vm.batchTimeout = 0
+
+	createAssetTx := &Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+		}},
+		Name:         "Team Rocket",
+		Symbol:       "TR",
+		Denomination: 0,
+		States: []*InitialState{{
+			FxID: 1,
+			Outs: []verify.State{
+				&nftfx.MintOutput{
+					GroupID: 1,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+					},
+				},
+			},
+		}},
+	}}
+	assert.NoError(t, createAssetTx.SignSECP256K1Fx(vm.codec, nil))
+
+	// Simulating requires the chain to be bootstrapped
+	_, err = vm.simulateOperationTx(createAssetTx.Bytes())
+	assert.Equal(t, errBootstrapping, err)
+
+	assert.NoError(t, vm.Bootstrapping())
+	assert.NoError(t, vm.Bootstrapped())
+
+	// Only operation transactions can be simulated
+	_, err = vm.simulateOperationTx(createAssetTx.Bytes())
+	assert.Equal(t, errNotOperationTx, err)
+
+	_, err = vm.IssueTx(createAssetTx.Bytes())
+	assert.NoError(t, err)
+
+	newMintNFTTx := func() *Tx {
+		return &Tx{UnsignedTx: &OperationTx{
+			BaseTx: BaseTx{BaseTx: avax.BaseTx{
+				NetworkID:    networkID,
+				BlockchainID: chainID,
+			}},
+			Ops: []*Operation{{
+				Asset: avax.Asset{ID: createAssetTx.ID()},
+				UTXOIDs: []*avax.UTXOID{{
+					TxID:        createAssetTx.ID(),
+					OutputIndex: 0,
+				}},
+				Op: &nftfx.MintOperation{
+					MintInput: secp256k1fx.Input{
+						SigIndices: []uint32{0},
+					},
+					GroupID: 1,
+					Payload: []byte{'h', 'e', 'l', 'l', 'o'},
+					Outputs: []*secp256k1fx.OutputOwners{{}},
+				},
+			}},
+		}}
+	}
+
+	// An unsigned transaction is verified without its credentials
+	unsignedTx := newMintNFTTx()
+	assert.NoError(t, unsignedTx.SignNFTFx(vm.codec, nil))
+	s, err := vm.simulateOperationTx(unsignedTx.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, s.err)
+	assert.False(t, s.credentialsVerified)
+	assert.Zero(t, s.fee)
+	assert.Len(t, s.utxos, 1)
+
+	// The feature extension verifies the operations of unsigned transactions
+	wrongGroupTx := newMintNFTTx()
+	wrongGroupTx.UnsignedTx.(*OperationTx).Ops[0].Op.(*nftfx.MintOperation).GroupID = 2
+	assert.NoError(t, wrongGroupTx.SignNFTFx(vm.codec, nil))
+	s, err = vm.simulateOperationTx(wrongGroupTx.Bytes())
+	assert.NoError(t, err)
+	assert.Error(t, s.err)
+	assert.False(t, s.credentialsVerified)
+
+	tooManySignersTx := newMintNFTTx()
+	tooManySignersTx.UnsignedTx.(*OperationTx).Ops[0].Op.(*nftfx.MintOperation).MintInput.SigIndices = []uint32{0, 1}
+	assert.NoError(t, tooManySignersTx.SignNFTFx(vm.codec, nil))
+	s, err = vm.simulateOperationTx(tooManySignersTx.Bytes())
+	assert.NoError(t, err)
+	assert.Error(t, s.err)
+
+	// A transaction signed by the wrong key is rejected
+	badSigTx := newMintNFTTx()
+	assert.NoError(t, badSigTx.SignNFTFx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[1]}}))
+	s, err = vm.simulateOperationTx(badSigTx.Bytes())
+	assert.NoError(t, err)
+	assert.Error(t, s.err)
+	assert.True(t, s.credentialsVerified)
+
+	signedTx := newMintNFTTx()
+	assert.NoError(t, signedTx.SignNFTFx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}))
+	s, err = vm.simulateOperationTx(signedTx.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, s.err)
+	assert.True(t, s.credentialsVerified)
+	assert.Len(t, s.utxos, 1)
+	assert.Equal(t, signedTx.ID(), s.utxos[0].TxID)
+
+	// Simulating doesn't issue the transaction
+	_, err = vm.IssueTx(signedTx.Bytes())
+	assert.NoError(t, err)
+}
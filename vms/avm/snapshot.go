@@ -0,0 +1,136 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/snapshotdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	// Max number of snapshots that can be open at the same time
+	maxSnapshots = 16
+
+	// Max duration a snapshot can be kept open for
+	maxSnapshotTTL = 10 * time.Minute
+)
+
+var (
+	errTooManySnapshots = fmt.Errorf("the maximum of %d snapshots are already open", maxSnapshots)
+	errInvalidSnapshot  = fmt.Errorf("snapshot TTL must be in (0, %s]", maxSnapshotTTL)
+	errUnknownSnapshot  = errors.New("unknown or expired snapshot")
+)
+
+// snapshot pins the state of the chain at the time it was taken
+type snapshot struct {
+	db     *snapshotdb.Snapshot
+	state  State
+	expiry time.Time
+	// Closes the snapshot once it expires, so that an abandoned snapshot
+	// doesn't keep growing as the chain's state is written
+	timer *time.Timer
+}
+
+// createSnapshot takes a snapshot of the last committed state of the chain
+// that is kept open for [ttl], and closed once [ttl] passes even if it's never
+// used again. Returns the ID of the snapshot.
+func (vm *VM) createSnapshot(ttl time.Duration) (ids.ID, time.Time, error) {
+	if ttl <= 0 || ttl > maxSnapshotTTL {
+		return ids.ID{}, time.Time{}, errInvalidSnapshot
+	}
+
+	vm.pruneSnapshots()
+	if len(vm.snapshots) >= maxSnapshots {
+		return ids.ID{}, time.Time{}, errTooManySnapshots
+	}
+
+	snapshotID := ids.ID{}
+	if _, err := rand.Read(snapshotID[:]); err != nil {
+		return ids.ID{}, time.Time{}, fmt.Errorf("couldn't generate snapshot ID: %w", err)
+	}
+
+	db, err := vm.snapshotDB.NewSnapshot()
+	if err != nil {
+		return ids.ID{}, time.Time{}, err
+	}
+	expiry := vm.clock.Time().Add(ttl)
+	s := &snapshot{
+		db:     db,
+		state:  NewState(db, vm.genesisCodec, vm.codec),
+		expiry: expiry,
+	}
+	s.timer = time.AfterFunc(ttl, func() {
+		vm.ctx.Lock.Lock()
+		defer vm.ctx.Lock.Unlock()
+
+		// The snapshot may have been released, or pruned, already
+		if vm.snapshots[snapshotID] != s {
+			return
+		}
+		if err := vm.closeSnapshot(snapshotID); err != nil {
+			vm.ctx.Log.Debug("failed to close snapshot %s: %s", snapshotID, err)
+		}
+	})
+	vm.snapshots[snapshotID] = s
+	return snapshotID, expiry, nil
+}
+
+// getState returns the state pinned by the snapshot [snapshotID]. If
+// [snapshotID] is empty, the current state is returned.
+func (vm *VM) getState(snapshotID ids.ID) (State, error) {
+	if snapshotID == ids.Empty {
+		return vm.state, nil
+	}
+
+	vm.pruneSnapshots()
+	snapshot, ok := vm.snapshots[snapshotID]
+	if !ok {
+		return nil, errUnknownSnapshot
+	}
+	return snapshot.state, nil
+}
+
+// releaseSnapshot closes the snapshot [snapshotID]
+func (vm *VM) releaseSnapshot(snapshotID ids.ID) error {
+	vm.pruneSnapshots()
+	if _, ok := vm.snapshots[snapshotID]; !ok {
+		return errUnknownSnapshot
+	}
+	return vm.closeSnapshot(snapshotID)
+}
+
+// pruneSnapshots closes all the expired snapshots
+func (vm *VM) pruneSnapshots() {
+	now := vm.clock.Time()
+	for snapshotID, snapshot := range vm.snapshots {
+		if now.Before(snapshot.expiry) {
+			continue
+		}
+		if err := vm.closeSnapshot(snapshotID); err != nil {
+			vm.ctx.Log.Debug("failed to close snapshot %s: %s", snapshotID, err)
+		}
+	}
+}
+
+// closeAllSnapshots closes all the open snapshots
+func (vm *VM) closeAllSnapshots() {
+	for snapshotID := range vm.snapshots {
+		if err := vm.closeSnapshot(snapshotID); err != nil {
+			vm.ctx.Log.Debug("failed to close snapshot %s: %s", snapshotID, err)
+		}
+	}
+}
+
+// closeSnapshot closes the open snapshot [snapshotID]
+func (vm *VM) closeSnapshot(snapshotID ids.ID) error {
+	snapshot := vm.snapshots[snapshotID]
+	delete(vm.snapshots, snapshotID)
+	snapshot.timer.Stop()
+	return snapshot.db.Close()
+}
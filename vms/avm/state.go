@@ -18,29 +18,39 @@ const (
 )
 
 var (
-	utxoStatePrefix            = []byte("utxo")
-	statusStatePrefix          = []byte("status")
-	singletonStatePrefix       = []byte("singleton")
-	txStatePrefix              = []byte("tx")
-	_                    State = &state{}
+	utxoStatePrefix             = []byte("utxo")
+	statusStatePrefix           = []byte("status")
+	singletonStatePrefix        = []byte("singleton")
+	txStatePrefix               = []byte("tx")
+	nftIndexStatePrefix         = []byte("nftIndex")
+	acceptedTxStatePrefix       = []byte("acceptedTx")
+	rejectedTxStatePrefix       = []byte("rejectedTx")
+	addressTxStatePrefix        = []byte("addressTx")
+	_                     State = &state{}
 )
 
 // State persistently maintains a set of UTXOs, transaction, statuses, and
 // singletons.
 type State interface {
-	avax.UTXOState
+	UTXOState
 	avax.StatusState
 	avax.SingletonState
 	TxState
+	AcceptedTxState
+	RejectedTxState
+	AddressTxState
 
 	DeduplicateTx(tx *UniqueTx) *UniqueTx
 }
 
 type state struct {
-	avax.UTXOState
+	UTXOState
 	avax.StatusState
 	avax.SingletonState
 	TxState
+	AcceptedTxState
+	RejectedTxState
+	AddressTxState
 
 	uniqueTxs cache.Deduplicator
 }
@@ -50,12 +60,19 @@ func NewState(db database.Database, genesisCodec, codec codec.Manager) State {
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
 	txDB := prefixdb.New(txStatePrefix, db)
+	nftIndexDB := prefixdb.New(nftIndexStatePrefix, db)
+	acceptedTxDB := prefixdb.New(acceptedTxStatePrefix, db)
+	rejectedTxDB := prefixdb.New(rejectedTxStatePrefix, db)
+	addressTxDB := prefixdb.New(addressTxStatePrefix, db)
 
 	return &state{
-		UTXOState:      avax.NewUTXOState(utxoDB, codec),
-		StatusState:    avax.NewStatusState(statusDB),
-		SingletonState: avax.NewSingletonState(singletonDB),
-		TxState:        NewTxState(txDB, genesisCodec),
+		UTXOState:       newUTXOState(avax.NewUTXOState(utxoDB, codec), codec, utxoDB, singletonDB, nftIndexDB),
+		StatusState:     avax.NewStatusState(statusDB),
+		SingletonState:  avax.NewSingletonState(singletonDB),
+		TxState:         NewTxState(txDB, genesisCodec),
+		AcceptedTxState: NewAcceptedTxState(acceptedTxDB),
+		RejectedTxState: NewRejectedTxState(rejectedTxDB),
+		AddressTxState:  NewAddressTxState(addressTxDB),
 
 		uniqueTxs: &cache.EvictableLRU{
 			Size: txDeduplicatorSize,
@@ -68,6 +85,10 @@ func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, na
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
 	txDB := prefixdb.New(txStatePrefix, db)
+	nftIndexDB := prefixdb.New(nftIndexStatePrefix, db)
+	acceptedTxDB := prefixdb.New(acceptedTxStatePrefix, db)
+	rejectedTxDB := prefixdb.New(rejectedTxStatePrefix, db)
+	addressTxDB := prefixdb.New(addressTxStatePrefix, db)
 
 	utxoState, err := avax.NewMeteredUTXOState(utxoDB, codec, namespace, metrics)
 	if err != nil {
@@ -81,10 +102,13 @@ func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, na
 
 	txState, err := NewMeteredTxState(txDB, genesisCodec, namespace, metrics)
 	return &state{
-		UTXOState:      utxoState,
-		StatusState:    statusState,
-		SingletonState: avax.NewSingletonState(singletonDB),
-		TxState:        txState,
+		UTXOState:       newUTXOState(utxoState, codec, utxoDB, singletonDB, nftIndexDB),
+		StatusState:     statusState,
+		SingletonState:  avax.NewSingletonState(singletonDB),
+		TxState:         txState,
+		AcceptedTxState: NewAcceptedTxState(acceptedTxDB),
+		RejectedTxState: NewRejectedTxState(rejectedTxDB),
+		AddressTxState:  NewAddressTxState(addressTxDB),
 
 		uniqueTxs: &cache.EvictableLRU{
 			Size: txDeduplicatorSize,
@@ -5,13 +5,20 @@ package avm
 
 import (
 	"math"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
@@ -178,3 +185,243 @@ func TestFundingAddresses(t *testing.T) {
 		t.Fatalf("Should have returned 0 utxoIDs")
 	}
 }
+
+func TestNFTIndex(t *testing.T) {
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	addr := keys[0].PublicKey().Address()
+	nftUTXO := &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        ids.GenerateTestID(),
+			OutputIndex: 0,
+		},
+		Asset: avax.Asset{ID: ids.GenerateTestID()},
+		Out: &nftfx.TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'e', 'l', 'l', 'o'},
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	avaxUTXO := &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        ids.GenerateTestID(),
+			OutputIndex: 0,
+		},
+		Asset: avax.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+
+	// UTXOs written before the NFT index existed aren't indexed
+	db := memdb.New()
+	oldState := avax.NewUTXOState(prefixdb.New(utxoStatePrefix, db), vm.codec)
+	if err := oldState.PutUTXO(nftUTXO.InputID(), nftUTXO); err != nil {
+		t.Fatal(err)
+	}
+
+	state := NewState(db, vm.genesisCodec, vm.codec)
+	if err := state.PutUTXO(avaxUTXO.InputID(), avaxUTXO); err != nil {
+		t.Fatal(err)
+	}
+	utxoIDs, err := state.NFTUTXOIDs(addr.Bytes(), ids.Empty, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 0 {
+		t.Fatalf("Expected no NFTs to be indexed but got %d", len(utxoIDs))
+	}
+
+	if err := state.IndexNFTs(); err != nil {
+		t.Fatal(err)
+	}
+	utxoIDs, err = state.NFTUTXOIDs(addr.Bytes(), ids.Empty, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 || utxoIDs[0] != nftUTXO.InputID() {
+		t.Fatalf("Expected the NFT to be indexed but got %v", utxoIDs)
+	}
+
+	// New NFTs are indexed when they are stored
+	newNFTUTXO := &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        nftUTXO.TxID,
+			OutputIndex: 1,
+		},
+		Asset: nftUTXO.Asset,
+		Out:   nftUTXO.Out,
+	}
+	if err := state.PutUTXO(newNFTUTXO.InputID(), newNFTUTXO); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.DeleteUTXO(nftUTXO.InputID()); err != nil {
+		t.Fatal(err)
+	}
+	utxoIDs, err = state.NFTUTXOIDs(addr.Bytes(), ids.Empty, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 || utxoIDs[0] != newNFTUTXO.InputID() {
+		t.Fatalf("Expected only the new NFT to be indexed but got %v", utxoIDs)
+	}
+}
+
+func TestAcceptedTxState(t *testing.T) {
+	db := memdb.New()
+	s := NewAcceptedTxState(db)
+
+	if txIDs, err := s.RecentlyAcceptedTxs(10); err != nil {
+		t.Fatal(err)
+	} else if len(txIDs) != 0 {
+		t.Fatalf("Should have returned no txs")
+	}
+
+	expected := make([]ids.ID, maxRecentlyAcceptedTxs+2)
+	for i := range expected {
+		expected[i] = ids.GenerateTestID()
+		if err := s.AddAcceptedTx(expected[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txIDs, err := s.RecentlyAcceptedTxs(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected[len(expected)-3:], txIDs) {
+		t.Fatalf("Returned the wrong txs")
+	}
+
+	// The oldest txs should have been forgotten
+	s = NewAcceptedTxState(db)
+	txIDs, err = s.RecentlyAcceptedTxs(maxRecentlyAcceptedTxs + 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected[2:], txIDs) {
+		t.Fatalf("Returned the wrong txs")
+	}
+}
+
+func TestAddressTxState(t *testing.T) {
+	db := memdb.New()
+	s := NewAddressTxState(db)
+
+	addr := ids.GenerateTestShortID()
+	if txIDs, cursor, err := s.AddressTxs(addr, 0, 10); err != nil {
+		t.Fatal(err)
+	} else if len(txIDs) != 0 || cursor != 0 {
+		t.Fatalf("Should have returned no txs")
+	}
+
+	expected := make([]ids.ID, 5)
+	for i := range expected {
+		expected[i] = ids.GenerateTestID()
+		if err := s.AddAddressTx(addr, expected[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddAddressTx(ids.GenerateTestShortID(), ids.GenerateTestID()); err != nil {
+		t.Fatal(err)
+	}
+
+	s = NewAddressTxState(db)
+	txIDs, cursor, err := s.AddressTxs(addr, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected[:3], txIDs) || cursor != 3 {
+		t.Fatalf("Returned the wrong page")
+	}
+
+	txIDs, cursor, err = s.AddressTxs(addr, cursor, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected[3:], txIDs) || cursor != 5 {
+		t.Fatalf("Returned the wrong page")
+	}
+
+	txIDs, cursor, err = s.AddressTxs(addr, cursor, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txIDs) != 0 || cursor != 5 {
+		t.Fatalf("Should have returned no txs past the last one")
+	}
+}
+
+func TestAddressTxStateStart(t *testing.T) {
+	s := NewAddressTxState(memdb.New())
+	if _, err := s.AddressTxsStart(); err != database.ErrNotFound {
+		t.Fatalf("Should have returned %s but returned %s", database.ErrNotFound, err)
+	}
+
+	// An index started with the chain holds every accepted tx
+	now := time.Unix(1000, 0)
+	if err := s.StartAddressTxs(true, now); err != nil {
+		t.Fatal(err)
+	}
+	if start, err := s.AddressTxsStart(); err != nil {
+		t.Fatal(err)
+	} else if !start.IsZero() {
+		t.Fatalf("Should have held every accepted tx")
+	}
+
+	// An index started after the chain accepted txs misses them, and its start
+	// isn't moved once recorded
+	s = NewAddressTxState(memdb.New())
+	if err := s.StartAddressTxs(false, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.StartAddressTxs(true, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if start, err := s.AddressTxsStart(); err != nil {
+		t.Fatal(err)
+	} else if !start.Equal(now) {
+		t.Fatalf("Should have started at %s but started at %s", now, start)
+	}
+}
+
+func TestRejectedTxState(t *testing.T) {
+	db := memdb.New()
+	s := NewRejectedTxState(db)
+
+	txID := ids.GenerateTestID()
+	if _, _, err := s.GetRejection(txID); err != database.ErrNotFound {
+		t.Fatalf("Should have returned %s but returned %s", database.ErrNotFound, err)
+	}
+
+	causeID := ids.GenerateTestID()
+	if err := s.PutRejection(txID, snowstorm.DependencyRejected, causeID); err != nil {
+		t.Fatal(err)
+	}
+
+	s = NewRejectedTxState(db)
+	reason, storedCauseID, err := s.GetRejection(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != snowstorm.DependencyRejected {
+		t.Fatalf("Returned the wrong reason %s", reason)
+	}
+	if storedCauseID != causeID {
+		t.Fatalf("Returned the wrong cause %s", storedCauseID)
+	}
+}
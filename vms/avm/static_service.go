@@ -97,20 +97,13 @@ func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, r
 						if err := json.Unmarshal(b, &holder); err != nil {
 							return fmt.Errorf("problem unmarshaling holder: %w", err)
 						}
-						_, addrbuff, err := formatting.ParseBech32(holder.Address)
+						owners, err := holder.outputOwners(parseBech32Address)
 						if err != nil {
-							return fmt.Errorf("problem parsing holder address: %w", err)
-						}
-						addr, err := ids.ToShortID(addrbuff)
-						if err != nil {
-							return fmt.Errorf("problem parsing holder address: %w", err)
+							return err
 						}
 						initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-							Amt: uint64(holder.Amount),
-							OutputOwners: secp256k1fx.OutputOwners{
-								Threshold: 1,
-								Addrs:     []ids.ShortID{addr},
-							},
+							Amt:          uint64(holder.Amount),
+							OutputOwners: owners,
 						})
 					}
 				case "variableCap":
@@ -169,6 +162,16 @@ func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, r
 	return nil
 }
 
+// parseBech32Address parses a bech32 address without checking its chain ID or
+// HRP, since they aren't known when the genesis is built
+func parseBech32Address(addrStr string) (ids.ShortID, error) {
+	_, addrBytes, err := formatting.ParseBech32(addrStr)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	return ids.ToShortID(addrBytes)
+}
+
 func staticCodec() (codec.Manager, error) {
 	c := linearcodec.New(reflectcodec.DefaultTagName, 1<<20)
 	manager := codec.NewManager(math.MaxUint32)
@@ -6,9 +6,11 @@ package avm
 import (
 	"testing"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
 var addrStrArray = []string{
@@ -104,3 +106,142 @@ func TestBuildGenesis(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestBuildGenesisMultisigHolders(t *testing.T) {
+	ss := CreateStaticService()
+	addrs := []ids.ShortID{}
+	bech32Addrs := []string{}
+	for _, addrStr := range addrStrArray {
+		b, err := formatting.Decode(formatting.CB58, addrStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := ids.ToShortID(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bech32Addr, err := formatting.FormatBech32(testHRP, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, addr)
+		bech32Addrs = append(bech32Addrs, bech32Addr)
+	}
+
+	args := BuildGenesisArgs{
+		Encoding: formatting.Hex,
+		GenesisData: map[string]AssetDefinition{
+			"asset1": {
+				Name:         "myFixedCapAsset",
+				Symbol:       "MFCA",
+				Denomination: 8,
+				InitialState: map[string][]interface{}{
+					"fixedCap": {
+						Holder{
+							Amount:    100000,
+							Address:   bech32Addrs[0],
+							Addresses: bech32Addrs[1:3],
+							Threshold: 2,
+							Locktime:  12345,
+						},
+						Holder{
+							Amount:    100000,
+							Addresses: bech32Addrs[3:],
+						},
+					},
+				},
+			},
+		},
+	}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	genesisBytes, err := formatting.Decode(reply.Encoding, reply.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := GetCreateTxFromGenesisTest(t, genesisBytes, "myFixedCapAsset")
+	createTx := tx.UnsignedTx.(*CreateAssetTx)
+	if len(createTx.States) != 1 || len(createTx.States[0].Outs) != 2 {
+		t.Fatalf("expected one initial state with two outputs")
+	}
+
+	expectedOwners := []secp256k1fx.OutputOwners{
+		{
+			Locktime:  12345,
+			Threshold: 2,
+			Addrs:     []ids.ShortID{addrs[0], addrs[1], addrs[2]},
+		},
+		{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addrs[3]},
+		},
+	}
+	ids.SortShortIDs(expectedOwners[0].Addrs)
+	for _, state := range createTx.States[0].Outs {
+		out, ok := state.(*secp256k1fx.TransferOutput)
+		if !ok {
+			t.Fatalf("expected a *secp256k1fx.TransferOutput but got %T", state)
+		}
+		found := false
+		for _, owners := range expectedOwners {
+			if owners.Equals(&out.OutputOwners) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("unexpected output owners %+v", out.OutputOwners)
+		}
+	}
+}
+
+func TestBuildGenesisInvalidHolder(t *testing.T) {
+	ss := CreateStaticService()
+	b, err := formatting.Decode(formatting.CB58, addrStrArray[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := formatting.FormatBech32(testHRP, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]Holder{
+		"no addresses": {
+			Amount: 100000,
+		},
+		"threshold above number of addresses": {
+			Amount:    100000,
+			Address:   addr,
+			Threshold: 2,
+		},
+		"duplicate addresses": {
+			Amount:    100000,
+			Address:   addr,
+			Addresses: []string{addr},
+		},
+	}
+	for name, holder := range tests {
+		t.Run(name, func(t *testing.T) {
+			args := BuildGenesisArgs{
+				Encoding: formatting.Hex,
+				GenesisData: map[string]AssetDefinition{
+					"asset1": {
+						Name:         "myFixedCapAsset",
+						Symbol:       "MFCA",
+						Denomination: 8,
+						InitialState: map[string][]interface{}{
+							"fixedCap": {holder},
+						},
+					},
+				},
+			}
+			reply := BuildGenesisReply{}
+			if err := ss.BuildGenesis(nil, &args, &reply); err == nil {
+				t.Fatal("should have errored due to an invalid holder")
+			}
+		})
+	}
+}
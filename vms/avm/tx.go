@@ -32,6 +32,10 @@ type UnsignedTx interface {
 	InputUTXOs() []*avax.UTXOID
 	UTXOs() []*avax.UTXO
 
+	// InitCtx sets the context used to format the addresses of the tx when
+	// it's marshalled to JSON
+	InitCtx(ctx *snow.Context)
+
 	SyntacticVerify(
 		ctx *snow.Context,
 		c codec.Manager,
@@ -101,6 +105,8 @@ func (t *Tx) SemanticVerify(vm *VM, tx UnsignedTx) error {
 }
 
 // SignSECP256K1Fx ...
+//
+// The signatures of nil keys are left empty, to be appended later.
 func (t *Tx) SignSECP256K1Fx(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
 	unsignedBytes, err := c.Marshal(codecVersion, &t.UnsignedTx)
 	if err != nil {
@@ -113,6 +119,9 @@ func (t *Tx) SignSECP256K1Fx(c codec.Manager, signers [][]*crypto.PrivateKeySECP
 			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(keys)),
 		}
 		for i, key := range keys {
+			if key == nil {
+				continue
+			}
 			sig, err := key.SignHash(hash)
 			if err != nil {
 				return fmt.Errorf("problem creating transaction: %w", err)
@@ -23,8 +23,9 @@ var (
 )
 
 var (
-	_ snowstorm.Tx    = &UniqueTx{}
-	_ cache.Evictable = &UniqueTx{}
+	_ snowstorm.Tx                = &UniqueTx{}
+	_ snowstorm.RejectionReceiver = &UniqueTx{}
+	_ cache.Evictable             = &UniqueTx{}
 )
 
 // UniqueTx provides a de-duplication service for txs. This only provides a
@@ -122,13 +123,27 @@ func (tx *UniqueTx) Accept() error {
 
 	defer tx.vm.db.Abort()
 
+	// Addresses whose UTXOs the tx consumed or created
+	touchedAddrs := ids.ShortSet{}
+	for _, utxo := range tx.UTXOs() {
+		addTouchedAddrs(touchedAddrs, utxo)
+	}
+
 	// Remove spent utxos
+	var spentUTXOs []*avax.UTXO
 	for _, utxo := range tx.InputUTXOs() {
 		if utxo.Symbolic() {
 			// If the UTXO is symbolic, it can't be spent
 			continue
 		}
 		utxoID := utxo.InputID()
+		spent, err := tx.vm.state.GetUTXO(utxoID)
+		if err != nil {
+			tx.vm.ctx.Log.Error("Failed to get spent utxo %s due to %s", utxoID, err)
+			return err
+		}
+		addTouchedAddrs(touchedAddrs, spent)
+		spentUTXOs = append(spentUTXOs, spent)
 		if err := tx.vm.state.DeleteUTXO(utxoID); err != nil {
 			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
 			return err
@@ -149,6 +164,30 @@ func (tx *UniqueTx) Accept() error {
 	}
 
 	txID := tx.ID()
+	if tx.vm.utxoHistory != nil {
+		epoch := tx.vm.ctx.EpochSchedule().Epoch(tx.vm.clock.Time())
+		if err := tx.vm.utxoHistory.record(spentUTXOs, tx.UTXOs(), epoch); err != nil {
+			tx.vm.ctx.Log.Error("Failed to record the UTXO history of tx %s due to %s", txID, err)
+			return err
+		}
+	}
+
+	if err := tx.vm.state.AddAcceptedTx(txID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record accepted tx %s due to %s", txID, err)
+		return err
+	}
+
+	for addr := range touchedAddrs {
+		if err := tx.vm.state.AddAddressTx(addr, txID); err != nil {
+			tx.vm.ctx.Log.Error("Failed to index tx %s by address due to %s", txID, err)
+			return err
+		}
+	}
+
+	if err := tx.vm.webhooks.notify(txID, touchedAddrs); err != nil {
+		tx.vm.ctx.Log.Error("Failed to notify webhooks of tx %s due to %s", txID, err)
+		return err
+	}
 
 	commitBatch, err := tx.vm.db.CommitBatch()
 	if err != nil {
@@ -163,7 +202,13 @@ func (tx *UniqueTx) Accept() error {
 
 	tx.vm.ctx.Log.Verbo("Accepted Tx: %s", txID)
 
-	tx.vm.pubsub.Publish(txID, NewPubSubFilterer(tx.Tx))
+	if opTx, ok := tx.UnsignedTx.(*OperationTx); ok {
+		tx.vm.metrics.acceptOps(opTx.Ops)
+	}
+
+	// Published here rather than through the decision dispatcher, which
+	// dispatches the tx before it's committed
+	tx.vm.pubsub.Publish(txID, NewPubSubFilterer(tx.vm, tx.Tx))
 	tx.vm.walletService.decided(txID)
 
 	tx.deps = nil // Needed to prevent a memory leak
@@ -171,6 +216,19 @@ func (tx *UniqueTx) Accept() error {
 	return nil
 }
 
+// addTouchedAddrs adds the addresses that own [utxo] to [addrs]
+func addTouchedAddrs(addrs ids.ShortSet, utxo *avax.UTXO) {
+	addressable, ok := utxo.Out.(avax.Addressable)
+	if !ok {
+		return
+	}
+	for _, addrBytes := range addressable.Addresses() {
+		if addr, err := ids.ToShortID(addrBytes); err == nil {
+			addrs.Add(addr)
+		}
+	}
+}
+
 // Reject is called when the transaction was finalized as rejected by consensus
 func (tx *UniqueTx) Reject() error {
 	defer tx.vm.db.Abort()
@@ -195,6 +253,18 @@ func (tx *UniqueTx) Reject() error {
 	return nil
 }
 
+// RejectWithReason is called when the transaction was finalized as rejected by
+// consensus. The reason is persisted along with the rejection.
+func (tx *UniqueTx) RejectWithReason(reason snowstorm.RejectionReason, causeID ids.ID) error {
+	defer tx.vm.db.Abort()
+
+	if err := tx.vm.state.PutRejection(tx.ID(), reason, causeID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record rejection reason of tx %s due to %s", tx.txID, err)
+		return err
+	}
+	return tx.Reject()
+}
+
 // Status returns the current status of this transaction
 func (tx *UniqueTx) Status() choices.Status {
 	tx.refresh()
@@ -6,13 +6,21 @@ package avm
 import (
 	"fmt"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/encdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hdkey"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
-var addresses = ids.Empty
+var (
+	addresses = ids.Empty
+
+	// Keys are at least 20 bytes long so these can't collide with an address.
+	hdSeedKey      = []byte("hdSeed")
+	hdNextIndexKey = []byte("hdNextIndex")
+)
 
 type userState struct{ vm *VM }
 
@@ -81,3 +89,70 @@ func (s *userState) Key(db *encdb.Database, address ids.ShortID) (*crypto.Privat
 	}
 	return sk.(*crypto.PrivateKeySECP256K1R), nil
 }
+
+// AddKey stores [sk] and adds its address to the user's addresses if it isn't
+// already there. Returns an error if the user would exceed
+// [maxKeystoreAddresses] addresses.
+func (s *userState) AddKey(db *encdb.Database, sk *crypto.PrivateKeySECP256K1R) error {
+	// Explicitly drop the error since it may indicate there are no addresses
+	addrs, _ := s.Addresses(db)
+
+	newAddr := sk.PublicKey().Address()
+	for _, addr := range addrs {
+		if addr == newAddr {
+			return s.SetKey(db, sk)
+		}
+	}
+	if len(addrs) >= maxKeystoreAddresses {
+		return fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
+	}
+	if err := s.SetKey(db, sk); err != nil {
+		return err
+	}
+	return s.SetAddresses(db, append(addrs, newAddr))
+}
+
+// SetSeed stores the user's BIP-39 seed
+func (s *userState) SetSeed(db *encdb.Database, seed []byte) error {
+	return db.Put(hdSeedKey, seed)
+}
+
+// Seed returns the user's BIP-39 seed, or [database.ErrNotFound] if the user
+// wasn't created from a mnemonic
+func (s *userState) Seed(db *encdb.Database) ([]byte, error) {
+	return db.Get(hdSeedKey)
+}
+
+// SetNextIndex stores the index of the next address to derive from the user's
+// seed
+func (s *userState) SetNextIndex(db *encdb.Database, index uint32) error {
+	return database.PutUInt32(db, hdNextIndexKey, index)
+}
+
+// NextIndex returns the index of the next address to derive from the user's
+// seed
+func (s *userState) NextIndex(db *encdb.Database) (uint32, error) {
+	index, err := database.GetUInt32(db, hdNextIndexKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return index, err
+}
+
+// HDKey returns the key derived from the user's seed on the BIP-44 path
+// m/44'/9000'/0'/0/[index]
+func (s *userState) HDKey(db *encdb.Database, index uint32) (*crypto.PrivateKeySECP256K1R, error) {
+	seed, err := s.Seed(db)
+	if err != nil {
+		return nil, err
+	}
+	master, err := hdkey.NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+	key, err := master.Derive(hdkey.AccountPath(index))
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey(), nil
+}
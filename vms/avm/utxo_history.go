@@ -0,0 +1,248 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	utxoHistoryPrefix  = []byte("utxoHistory")
+	historyAddrPrefix  = []byte("addr")
+	historySpentPrefix = []byte("spent")
+	// Epoch the history was started in. Removed while the history is disabled.
+	historyStartKey = []byte("start")
+
+	errHistoryDisabled    = errors.New("the UTXO history isn't enabled on this node")
+	errEpochBeforeHistory = errors.New("epoch precedes the UTXO history of this node")
+	errSnapshotAndEpoch   = errors.New("can't read both a snapshot and an epoch")
+
+	_ State = &historicalState{}
+)
+
+// utxoHistory records the epochs the UTXOs of each address were created and
+// spent in, and keeps the spent UTXOs, so that the UTXOs an address held at the
+// end of a past epoch can be read. The epoch of a tx is the epoch this node
+// accepted it in.
+//
+// For each address, the history stores the epoch each of its UTXOs was created
+// in, followed by the epoch it was spent in once it's spent.
+type utxoHistory struct {
+	codec   codec.Manager
+	addrDB  database.Database
+	spentDB database.Database
+
+	// First epoch whose UTXOs are known
+	startEpoch uint32
+}
+
+// newUTXOHistory returns the UTXO history stored in [db], or nil if it isn't
+// [enabled]. If the history wasn't running, it starts in [epoch] with the
+// UTXOs currently stored in [db].
+func newUTXOHistory(db database.Database, codec codec.Manager, enabled bool, epoch uint32) (*utxoHistory, error) {
+	historyDB := prefixdb.New(utxoHistoryPrefix, db)
+	if !enabled {
+		// The history misses the txs accepted while it's disabled, so it must
+		// be started again the next time it's enabled
+		return nil, historyDB.Delete(historyStartKey)
+	}
+
+	h := &utxoHistory{
+		codec:   codec,
+		addrDB:  prefixdb.New(historyAddrPrefix, historyDB),
+		spentDB: prefixdb.New(historySpentPrefix, historyDB),
+	}
+	startEpoch, err := database.GetUInt32(historyDB, historyStartKey)
+	switch err {
+	case nil:
+		h.startEpoch = startEpoch
+		return h, nil
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	// The UTXOs that exist when the history starts are recorded as if they
+	// were created in epoch 0. Records left over from a previous run of the
+	// history are either overwritten here, or belong to UTXOs that were spent
+	// since, which [historicalState] skips.
+	err = avax.ForEachUTXO(prefixdb.New(utxoStatePrefix, db), codec, func(utxo *avax.UTXO) error {
+		return h.created(utxo, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+	h.startEpoch = epoch
+	return h, database.PutUInt32(historyDB, historyStartKey, epoch)
+}
+
+// created records that [utxo] was created in [epoch]
+func (h *utxoHistory) created(utxo *avax.UTXO, epoch uint32) error {
+	addressable, ok := utxo.Out.(avax.Addressable)
+	if !ok {
+		return nil
+	}
+	utxoID := utxo.InputID()
+	for _, addr := range addressable.Addresses() {
+		if err := h.getAddrDB(addr).Put(utxoID[:], database.PackUInt32(epoch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spent records that [utxo] was spent in [epoch]
+func (h *utxoHistory) spent(utxo *avax.UTXO, epoch uint32) error {
+	addressable, ok := utxo.Out.(avax.Addressable)
+	if !ok {
+		return nil
+	}
+	utxoID := utxo.InputID()
+	utxoBytes, err := h.codec.Marshal(codecVersion, utxo)
+	if err != nil {
+		return err
+	}
+	if err := h.spentDB.Put(utxoID[:], utxoBytes); err != nil {
+		return err
+	}
+	for _, addr := range addressable.Addresses() {
+		addrDB := h.getAddrDB(addr)
+		// A UTXO without a record is recorded as created in epoch 0
+		createdEpoch, err := database.GetUInt32(addrDB, utxoID[:])
+		if err != nil && err != database.ErrNotFound {
+			return err
+		}
+		record := append(database.PackUInt32(createdEpoch), database.PackUInt32(epoch)...)
+		if err := addrDB.Put(utxoID[:], record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *utxoHistory) getAddrDB(addr []byte) database.Database {
+	return prefixdb.NewNested(addr, h.addrDB)
+}
+
+// record accepting a tx that spent [spent] and created [created] in [epoch]
+func (h *utxoHistory) record(spent, created []*avax.UTXO, epoch uint32) error {
+	for _, utxo := range spent {
+		if err := h.spent(utxo, epoch); err != nil {
+			return err
+		}
+	}
+	for _, utxo := range created {
+		if err := h.created(utxo, epoch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getStateAtEpoch returns the state whose UTXOs are the UTXOs at the end of
+// [epoch]
+func (vm *VM) getStateAtEpoch(epoch uint32) (State, error) {
+	if vm.utxoHistory == nil {
+		return nil, errHistoryDisabled
+	}
+	if epoch < vm.utxoHistory.startEpoch {
+		return nil, fmt.Errorf("%w: the history starts at epoch %d", errEpochBeforeHistory, vm.utxoHistory.startEpoch)
+	}
+	return &historicalState{
+		State:   vm.state,
+		history: vm.utxoHistory,
+		epoch:   epoch,
+	}, nil
+}
+
+// epochEnd returns the unix time [epoch] ends at, or [now] if [epoch] hasn't
+// ended by then
+func (vm *VM) epochEnd(epoch uint32, now uint64) uint64 {
+	if epoch == math.MaxUint32 {
+		return now
+	}
+	end, ok := vm.ctx.EpochSchedule().TimeOf(epoch + 1)
+	if !ok || end.Unix() < 0 || uint64(end.Unix()) > now {
+		return now
+	}
+	return uint64(end.Unix())
+}
+
+// historicalState is a State whose UTXOs are read from the UTXO history, as
+// they were at the end of [epoch]. Everything else is read from the current
+// state.
+type historicalState struct {
+	State
+
+	history *utxoHistory
+	epoch   uint32
+}
+
+// UTXOIDs returns the IDs of the UTXOs associated with [addr] at the end of
+// [s.epoch], in order, starting after [start]. Returns at most [limit] IDs.
+func (s *historicalState) UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error) {
+	iter := s.history.getAddrDB(addr).NewIteratorWithStart(start[:])
+	defer iter.Release()
+
+	utxoIDs := []ids.ID(nil)
+	for len(utxoIDs) < limit && iter.Next() {
+		utxoID, err := ids.ToID(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		if utxoID == start {
+			continue
+		}
+
+		record := iter.Value()
+		createdEpoch, err := database.ParseUInt32(record[:4])
+		if err != nil {
+			return nil, err
+		}
+		if createdEpoch > s.epoch {
+			continue
+		}
+		if len(record) > 4 {
+			spentEpoch, err := database.ParseUInt32(record[4:])
+			if err != nil {
+				return nil, err
+			}
+			if spentEpoch <= s.epoch {
+				continue
+			}
+		} else if _, err := s.State.GetUTXO(utxoID); err == database.ErrNotFound {
+			// The UTXO was spent while the history was disabled, so before
+			// the history started again
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		utxoIDs = append(utxoIDs, utxoID)
+	}
+	return utxoIDs, iter.Error()
+}
+
+// GetUTXO returns the UTXO [utxoID], whether it's spent or not
+func (s *historicalState) GetUTXO(utxoID ids.ID) (*avax.UTXO, error) {
+	utxoBytes, err := s.history.spentDB.Get(utxoID[:])
+	if err == database.ErrNotFound {
+		return s.State.GetUTXO(utxoID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	utxo := &avax.UTXO{}
+	if _, err := s.history.codec.Unmarshal(utxoBytes, utxo); err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
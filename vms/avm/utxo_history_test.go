@@ -0,0 +1,131 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+func TestUTXOHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(err)
+	assetID := GetCreateTxFromGenesisTest(t, genesisBytes, "AVAX").ID().String()
+	getBalance := func(epoch *json.Uint32) (uint64, error) {
+		reply := GetBalanceReply{}
+		err := s.GetBalance(nil, &GetBalanceArgs{
+			Address:        addrStr,
+			AssetID:        assetID,
+			IncludePartial: true,
+			Epoch:          epoch,
+		}, &reply)
+		return uint64(reply.Balance), err
+	}
+
+	epoch := func(e uint32) *json.Uint32 {
+		jsonEpoch := json.Uint32(e)
+		return &jsonEpoch
+	}
+	_, err = getBalance(epoch(2))
+	assert.ErrorIs(err, errHistoryDisabled)
+
+	// Epoch 1 starts at [firstTransition], and every epoch lasts an hour
+	firstTransition := time.Unix(1600000000, 0)
+	ctx.EpochFirstTransition = firstTransition
+	ctx.EpochDuration = time.Hour
+
+	// Start the history in epoch 2 with the genesis UTXOs
+	vm.clock.Set(firstTransition.Add(time.Hour))
+	vm.utxoHistory, err = newUTXOHistory(vm.db, vm.codec, true, 2)
+	assert.NoError(err)
+	initialBalance, err := getBalance(nil)
+	assert.NoError(err)
+	assert.NotZero(initialBalance)
+
+	// Spend a UTXO of the address in epoch 3
+	vm.clock.Set(firstTransition.Add(2 * time.Hour))
+	newTx := NewTx(t, genesisBytes, vm)
+	tx, err := vm.ParseTx(newTx.Bytes())
+	assert.NoError(err)
+	assert.NoError(tx.Verify())
+	assert.NoError(tx.Accept())
+
+	balance, err := getBalance(nil)
+	assert.NoError(err)
+	assert.Equal(initialBalance-startBalance, balance)
+
+	balance, err = getBalance(epoch(2))
+	assert.NoError(err)
+	assert.Equal(initialBalance, balance)
+	balance, err = getBalance(epoch(3))
+	assert.NoError(err)
+	assert.Equal(initialBalance-startBalance, balance)
+
+	// The spent UTXO is returned by GetUTXOs at the end of epoch 2
+	utxosReply := api.GetUTXOsReply{}
+	err = s.GetUTXOs(nil, &api.GetUTXOsArgs{
+		Addresses: []string{addrStr},
+		Encoding:  formatting.Hex,
+		Epoch:     epoch(2),
+	}, &utxosReply)
+	assert.NoError(err)
+	spentUTXOID := newTx.UnsignedTx.InputUTXOs()[0].InputID()
+	utxos := make([]ids.ID, len(utxosReply.UTXOs))
+	for i, utxoStr := range utxosReply.UTXOs {
+		utxoBytes, err := formatting.Decode(utxosReply.Encoding, utxoStr)
+		assert.NoError(err)
+		utxo := &avax.UTXO{}
+		_, err = vm.codec.Unmarshal(utxoBytes, utxo)
+		assert.NoError(err)
+		utxos[i] = utxo.InputID()
+	}
+	assert.Contains(utxos, spentUTXOID)
+
+	_, err = getBalance(epoch(1))
+	assert.ErrorIs(err, errEpochBeforeHistory)
+	err = s.GetBalance(nil, &GetBalanceArgs{
+		Address:  addrStr,
+		AssetID:  assetID,
+		Snapshot: ids.GenerateTestID(),
+		Epoch:    epoch(2),
+	}, &GetBalanceReply{})
+	assert.ErrorIs(err, errSnapshotAndEpoch)
+
+	// The history is kept across restarts
+	reloaded, err := newUTXOHistory(vm.db, vm.codec, true, 5)
+	assert.NoError(err)
+	assert.Equal(uint32(2), reloaded.startEpoch)
+
+	// Running without the history restarts it the next time it's enabled
+	disabled, err := newUTXOHistory(vm.db, vm.codec, false, 5)
+	assert.NoError(err)
+	assert.Nil(disabled)
+	vm.utxoHistory, err = newUTXOHistory(vm.db, vm.codec, true, 5)
+	assert.NoError(err)
+	assert.Equal(uint32(5), vm.utxoHistory.startEpoch)
+
+	_, err = getBalance(epoch(3))
+	assert.ErrorIs(err, errEpochBeforeHistory)
+	balance, err = getBalance(epoch(5))
+	assert.NoError(err)
+	assert.Equal(initialBalance-startBalance, balance)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/codec/reflectcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/snapshotdb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/pubsub"
@@ -29,8 +30,10 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/workers"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -102,11 +105,32 @@ type VM struct {
 	timer        *timer.Timer
 	batchTimeout time.Duration
 	txs          []snowstorm.Tx
+	// Time each tx in [txs] was issued at
+	txIssueTimes []time.Time
 	toEngine     chan<- common.Message
 
+	// Set while accepted txs are being replayed
+	replaying bool
+
+	// Limits the rate of local tx issuance per address. Nil if issuance isn't
+	// limited.
+	issuanceLimiter *issuanceLimiter
+
+	// Notifies webhooks of the accepted txs touching the addresses they watch
+	webhooks *webhookNotifier
+
+	// Records the UTXOs of each address in past epochs. Nil if the history
+	// isn't enabled.
+	utxoHistory *utxoHistory
+
 	baseDB database.Database
 	db     *versiondb.Database
 
+	// Tracks the values overwritten in [baseDB] for the open snapshots
+	snapshotDB *snapshotdb.Database
+	// Snapshot ID --> snapshot
+	snapshots map[ids.ID]*snapshot
+
 	typeToFxIndex map[reflect.Type]int
 	fxs           []*parsedFx
 
@@ -140,6 +164,14 @@ func (vm *VM) Initialize(
 	if err := vm.metrics.Initialize(ctx.Namespace, ctx.Metrics); err != nil {
 		return err
 	}
+	config, err := parseConfig(configBytes)
+	if err != nil {
+		return err
+	}
+	vm.issuanceLimiter, err = newIssuanceLimiter(config.IssuanceRateLimit)
+	if err != nil {
+		return err
+	}
 	vm.AddressManager = avax.NewAddressManager(ctx)
 	vm.Aliaser.Initialize()
 
@@ -147,7 +179,9 @@ func (vm *VM) Initialize(
 	vm.ctx = ctx
 	vm.toEngine = toEngine
 	vm.baseDB = db
-	vm.db = versiondb.New(db)
+	vm.snapshotDB = snapshotdb.New(db)
+	vm.snapshots = make(map[ids.ID]*snapshot)
+	vm.db = versiondb.New(vm.snapshotDB)
 	vm.typeToFxIndex = map[reflect.Type]int{}
 	vm.assetToFxCache = &cache.LRU{Size: assetToFxCacheSize}
 
@@ -212,6 +246,23 @@ func (vm *VM) Initialize(
 	if err := vm.initGenesis(genesisBytes); err != nil {
 		return err
 	}
+	if err := vm.state.IndexNFTs(); err != nil {
+		return err
+	}
+	vm.utxoHistory, err = newUTXOHistory(
+		vm.db,
+		vm.codec,
+		config.IndexUTXOHistory,
+		ctx.EpochSchedule().Epoch(vm.clock.Time()),
+	)
+	if err != nil {
+		return err
+	}
+	vm.webhooks, err = newWebhookNotifier(vm, vm.db, config.Webhooks)
+	if err != nil {
+		return err
+	}
+	vm.webhooks.start()
 
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
@@ -225,6 +276,7 @@ func (vm *VM) Initialize(
 	vm.walletService.vm = vm
 	vm.walletService.pendingTxMap = make(map[ids.ID]*list.Element)
 	vm.walletService.pendingTxOrdering = list.New()
+	vm.walletService.reservedUTXOs = make(map[ids.ID]time.Time)
 
 	return vm.db.Commit()
 }
@@ -258,10 +310,13 @@ func (vm *VM) Shutdown() error {
 		return nil
 	}
 
+	vm.closeAllSnapshots()
+
 	// There is a potential deadlock if the timer is about to execute a timeout.
 	// So, the lock must be released before stopping the timer.
 	vm.ctx.Lock.Unlock()
 	vm.timer.Stop()
+	vm.webhooks.close()
 	vm.ctx.Lock.Lock()
 
 	return vm.baseDB.Close()
@@ -321,6 +376,7 @@ func (vm *VM) PendingTxs() []snowstorm.Tx {
 
 	txs := vm.txs
 	vm.txs = nil
+	vm.txIssueTimes = nil
 	return txs
 }
 
@@ -361,7 +417,16 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if err := tx.verifyWithoutCacheWrites(); err != nil {
 		return ids.ID{}, err
 	}
+	if vm.issuanceLimiter == nil {
+		vm.issueTx(tx)
+		return tx.ID(), nil
+	}
+	spenders, err := vm.checkIssuanceRate(tx.UnsignedTx)
+	if err != nil {
+		return ids.ID{}, err
+	}
 	vm.issueTx(tx)
+	vm.issuanceLimiter.Issued(spenders, vm.clock.Time())
 	return tx.ID(), nil
 }
 
@@ -379,6 +444,17 @@ func (vm *VM) getPaginatedUTXOs(
 	startAddr ids.ShortID,
 	startUTXOID ids.ID,
 	limit int,
+) ([]*avax.UTXO, ids.ShortID, ids.ID, error) {
+	return vm.getPaginatedUTXOsAt(vm.state, addrs, startAddr, startUTXOID, limit)
+}
+
+// getPaginatedUTXOsAt is getPaginatedUTXOs, but reads the UTXOs from [state]
+func (vm *VM) getPaginatedUTXOsAt(
+	state State,
+	addrs ids.ShortSet,
+	startAddr ids.ShortID,
+	startUTXOID ids.ID,
+	limit int,
 ) ([]*avax.UTXO, ids.ShortID, ids.ID, error) {
 	if limit <= 0 || limit > maxUTXOsToFetch {
 		limit = maxUTXOsToFetch
@@ -404,7 +480,7 @@ func (vm *VM) getPaginatedUTXOs(
 
 		// Get UTXOs associated with [addr]. [searchSize] is used here to ensure
 		// that no UTXOs are dropped due to duplicated fetching.
-		utxoIDs, err := vm.state.UTXOIDs(addr.Bytes(), start, searchSize)
+		utxoIDs, err := state.UTXOIDs(addr.Bytes(), start, searchSize)
 		if err != nil {
 			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXOs for address %s: %w", addr, err)
 		}
@@ -416,7 +492,7 @@ func (vm *VM) getPaginatedUTXOs(
 				continue
 			}
 
-			utxo, err := vm.state.GetUTXO(utxoID)
+			utxo, err := state.GetUTXO(utxoID)
 			if err != nil {
 				return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
 			}
@@ -433,6 +509,11 @@ func (vm *VM) getPaginatedUTXOs(
 }
 
 func (vm *VM) getAllUTXOs(addrs ids.ShortSet) ([]*avax.UTXO, error) {
+	return vm.getAllUTXOsAt(vm.state, addrs)
+}
+
+// getAllUTXOsAt is getAllUTXOs, but reads the UTXOs from [state]
+func (vm *VM) getAllUTXOsAt(state State, addrs ids.ShortSet) ([]*avax.UTXO, error) {
 	seen := make(ids.Set, maxUTXOsToFetch) // IDs of UTXOs already in the list
 	utxos := make([]*avax.UTXO, 0, maxUTXOsToFetch)
 
@@ -442,19 +523,19 @@ func (vm *VM) getAllUTXOs(addrs ids.ShortSet) ([]*avax.UTXO, error) {
 
 	// iterate over the addresses and get all the utxos
 	for _, addr := range addrsList {
-		if err := vm.getAllUniqueAddressUTXOs(addr, &seen, &utxos); err != nil {
+		if err := vm.getAllUniqueAddressUTXOs(state, addr, &seen, &utxos); err != nil {
 			return nil, fmt.Errorf("couldn't get UTXOs for address %s: %w", addr, err)
 		}
 	}
 	return utxos, nil
 }
 
-func (vm *VM) getAllUniqueAddressUTXOs(addr ids.ShortID, seen *ids.Set, utxos *[]*avax.UTXO) error {
+func (vm *VM) getAllUniqueAddressUTXOs(state State, addr ids.ShortID, seen *ids.Set, utxos *[]*avax.UTXO) error {
 	lastIndex := ids.Empty
 	addrBytes := addr.Bytes()
 
 	for {
-		utxoIDs, err := vm.state.UTXOIDs(addrBytes, lastIndex, maxUTXOsToFetch) // Get UTXOs associated with [addr]
+		utxoIDs, err := state.UTXOIDs(addrBytes, lastIndex, maxUTXOsToFetch) // Get UTXOs associated with [addr]
 		if err != nil {
 			return err
 		}
@@ -471,7 +552,7 @@ func (vm *VM) getAllUniqueAddressUTXOs(addr ids.ShortID, seen *ids.Set, utxos *[
 				continue
 			}
 
-			utxo, err := vm.state.GetUTXO(utxoID)
+			utxo, err := state.GetUTXO(utxoID)
 			if err != nil {
 				return err
 			}
@@ -499,6 +580,9 @@ func (vm *VM) CodecRegistry() codec.Registry { return vm.codecRegistry }
 // Logger returns a reference to the internal logger of this VM
 func (vm *VM) Logger() logging.Logger { return vm.ctx.Log }
 
+// CryptoExecutor returns the executor cryptographic operations are run on
+func (vm *VM) CryptoExecutor() workers.Executor { return vm.ctx.CryptoExecutor }
+
 /*
  ******************************************************************************
  ********************************** Timer API *********************************
@@ -567,6 +651,12 @@ func (vm *VM) initGenesis(genesisBytes []byte) error {
 		}
 	}
 
+	// The address index was introduced after chains started accepting txs. It
+	// only holds every accepted tx if it started with the chain.
+	if err := vm.state.StartAddressTxs(!stateInitialized, vm.clock.Time()); err != nil {
+		return err
+	}
+
 	if !stateInitialized {
 		return vm.state.SetInitialized()
 	}
@@ -635,8 +725,29 @@ func (vm *VM) parsePrivateTx(txBytes []byte) (*Tx, error) {
 	return tx, nil
 }
 
+// parseJSONTx returns a copy of the tx with bytes [txBytes] that formats its
+// addresses for this chain when it's marshalled to JSON. The tx is parsed again
+// so that the txs shared with the rest of the VM are never modified.
+func (vm *VM) parseJSONTx(txBytes []byte) (*Tx, error) {
+	tx, err := vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	tx.InitCtx(vm.ctx)
+	return tx, nil
+}
+
+// formatTx returns the tx with bytes [txBytes] in [encoding]
+func (vm *VM) formatTx(txBytes []byte, encoding formatting.Encoding) (interface{}, error) {
+	if encoding == formatting.JSON {
+		return vm.parseJSONTx(txBytes)
+	}
+	return formatting.Encode(encoding, txBytes)
+}
+
 func (vm *VM) issueTx(tx snowstorm.Tx) {
 	vm.txs = append(vm.txs, tx)
+	vm.txIssueTimes = append(vm.txIssueTimes, vm.clock.Time())
 	switch {
 	case len(vm.txs) == batchSize:
 		vm.FlushTxs()
@@ -653,6 +764,10 @@ func (vm *VM) getUTXO(utxoID *avax.UTXOID) (*avax.UTXO, error) {
 	}
 
 	inputTx, inputIndex := utxoID.InputSource()
+	if vm.replaying {
+		return vm.getReplayedUTXO(inputTx, inputIndex)
+	}
+
 	parent := UniqueTx{
 		vm:   vm,
 		txID: inputTx,
@@ -807,56 +922,116 @@ func (vm *VM) LoadUser(
 }
 
 // Spend ...
+//
+// If [partial], the UTXOs that [kc] holds only some of the required keys of
+// are spent once the UTXOs it can fully sign for run out. The keys that [kc]
+// doesn't hold are nil, so that the tx is partially signed.
 func (vm *VM) Spend(
 	utxos []*avax.UTXO,
 	kc *secp256k1fx.Keychain,
 	amounts map[ids.ID]uint64,
+	partial bool,
 ) (
 	map[ids.ID]uint64,
 	[]*avax.TransferableInput,
 	[][]*crypto.PrivateKeySECP256K1R,
 	error,
+) {
+	amountsSpent, ins, signers, err := vm.spendFrom(utxos, kc.Addrs, amounts, partial)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return amountsSpent, ins, signingKeys(kc, signers), nil
+}
+
+// SpendFrom is Spend for when the keys of the spending addresses aren't
+// available. The inputs spend UTXOs owned by [addrs]. Returns the addresses
+// that must sign each input.
+func (vm *VM) SpendFrom(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	amounts map[ids.ID]uint64,
+) (
+	map[ids.ID]uint64,
+	[]*avax.TransferableInput,
+	[][]ids.ShortID,
+	error,
+) {
+	return vm.spendFrom(utxos, addrs, amounts, false)
+}
+
+func (vm *VM) spendFrom(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	amounts map[ids.ID]uint64,
+	partial bool,
+) (
+	map[ids.ID]uint64,
+	[]*avax.TransferableInput,
+	[][]ids.ShortID,
+	error,
 ) {
 	amountsSpent := make(map[ids.ID]uint64, len(amounts))
 	time := vm.clock.Unix()
 
+	matchers := []func(*secp256k1fx.OutputOwners, ids.ShortSet, uint64) ([]uint32, bool){
+		secp256k1fx.MatchAddrs,
+	}
+	if partial {
+		// UTXOs that [addrs] can fully sign for are spent first
+		matchers = append(matchers, secp256k1fx.MatchAddrsPartially)
+	}
+
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
-	for _, utxo := range utxos {
-		assetID := utxo.AssetID()
-		amount := amounts[assetID]
-		amountSpent := amountsSpent[assetID]
+	// UTXO ID --> addresses that must sign the input spending it
+	inputSigners := make(map[ids.ID][]ids.ShortID)
+	for _, match := range matchers {
+		for _, utxo := range utxos {
+			assetID := utxo.AssetID()
+			amount := amounts[assetID]
+			amountSpent := amountsSpent[assetID]
+
+			if amountSpent >= amount {
+				// we already have enough inputs allocated to this asset
+				continue
+			}
 
-		if amountSpent >= amount {
-			// we already have enough inputs allocated to this asset
-			continue
-		}
+			utxoID := utxo.InputID()
+			if _, spent := inputSigners[utxoID]; spent {
+				// this utxo was already spent by a previous matcher
+				continue
+			}
 
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
-		if err != nil {
-			// this utxo can't be spent with the current keys right now
-			continue
-		}
-		input, ok := inputIntf.(avax.TransferableIn)
-		if !ok {
-			// this input doesn't have an amount, so I don't care about it here
-			continue
-		}
-		newAmountSpent, err := safemath.Add64(amountSpent, input.Amount())
-		if err != nil {
-			// there was an error calculating the consumed amount, just error
-			return nil, nil, nil, errSpendOverflow
+			out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+			if !ok {
+				// this output doesn't have an amount, so I don't care about it here
+				continue
+			}
+			indices, ok := match(&out.OutputOwners, addrs, time)
+			if !ok {
+				// this utxo can't be spent with the current addresses right now
+				continue
+			}
+			newAmountSpent, err := safemath.Add64(amountSpent, out.Amt)
+			if err != nil {
+				// there was an error calculating the consumed amount, just error
+				return nil, nil, nil, errSpendOverflow
+			}
+			amountsSpent[assetID] = newAmountSpent
+
+			// add the new input to the array
+			ins = append(ins, &avax.TransferableInput{
+				UTXOID: utxo.UTXOID,
+				Asset:  avax.Asset{ID: assetID},
+				In: &secp256k1fx.TransferInput{
+					Amt: out.Amt,
+					Input: secp256k1fx.Input{
+						SigIndices: indices,
+					},
+				},
+			})
+			inputSigners[utxoID] = signingAddrs(&out.OutputOwners, indices)
 		}
-		amountsSpent[assetID] = newAmountSpent
-
-		// add the new input to the array
-		ins = append(ins, &avax.TransferableInput{
-			UTXOID: utxo.UTXOID,
-			Asset:  avax.Asset{ID: assetID},
-			In:     input,
-		})
-		// add the required keys to the array
-		keys = append(keys, signers)
 	}
 
 	for asset, amount := range amounts {
@@ -869,8 +1044,48 @@ func (vm *VM) Spend(
 		}
 	}
 
-	avax.SortTransferableInputsWithSigners(ins, keys)
-	return amountsSpent, ins, keys, nil
+	avax.SortTransferableInputs(ins)
+	signers := make([][]ids.ShortID, len(ins))
+	for i, in := range ins {
+		signers[i] = inputSigners[in.InputID()]
+	}
+	return amountsSpent, ins, signers, nil
+}
+
+// signingAddrs returns the addresses of [owners] at [indices]
+func signingAddrs(owners *secp256k1fx.OutputOwners, indices []uint32) []ids.ShortID {
+	addrs := make([]ids.ShortID, len(indices))
+	for i, index := range indices {
+		addrs[i] = owners.Addrs[index]
+	}
+	return addrs
+}
+
+// signingKeys returns the keys in [kc] of [signers]. The keys of the signers
+// that [kc] doesn't hold are nil.
+func signingKeys(kc *secp256k1fx.Keychain, signers [][]ids.ShortID) [][]*crypto.PrivateKeySECP256K1R {
+	keys := make([][]*crypto.PrivateKeySECP256K1R, len(signers))
+	for i, addrs := range signers {
+		keys[i] = make([]*crypto.PrivateKeySECP256K1R, len(addrs))
+		for j, addr := range addrs {
+			if key, ok := kc.Get(addr); ok {
+				keys[i][j] = key
+			}
+		}
+	}
+	return keys
+}
+
+// isPartiallySigned returns true if some of [keys] are missing
+func isPartiallySigned(keys [][]*crypto.PrivateKeySECP256K1R) bool {
+	for _, inputKeys := range keys {
+		for _, key := range inputKeys {
+			if key == nil {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // SpendNFT ...
@@ -949,9 +1164,14 @@ func (vm *VM) SpendNFT(
 }
 
 // SpendAll ...
+//
+// If [partial], the UTXOs that [kc] holds only some of the required keys of
+// are spent as well. The keys that [kc] doesn't hold are nil, so that the tx
+// is partially signed.
 func (vm *VM) SpendAll(
 	utxos []*avax.UTXO,
 	kc *secp256k1fx.Keychain,
+	partial bool,
 ) (
 	map[ids.ID]uint64,
 	[]*avax.TransferableInput,
@@ -961,23 +1181,28 @@ func (vm *VM) SpendAll(
 	amountsSpent := make(map[ids.ID]uint64)
 	time := vm.clock.Unix()
 
+	match := secp256k1fx.MatchAddrs
+	if partial {
+		match = secp256k1fx.MatchAddrsPartially
+	}
+
 	ins := []*avax.TransferableInput{}
 	keys := [][]*crypto.PrivateKeySECP256K1R{}
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
 		amountSpent := amountsSpent[assetID]
 
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
-		if err != nil {
-			// this utxo can't be spent with the current keys right now
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			// this output doesn't have an amount, so I don't care about it here
 			continue
 		}
-		input, ok := inputIntf.(avax.TransferableIn)
+		indices, ok := match(&out.OutputOwners, kc.Addrs, time)
 		if !ok {
-			// this input doesn't have an amount, so I don't care about it here
+			// this utxo can't be spent with the current keys right now
 			continue
 		}
-		newAmountSpent, err := safemath.Add64(amountSpent, input.Amount())
+		newAmountSpent, err := safemath.Add64(amountSpent, out.Amt)
 		if err != nil {
 			// there was an error calculating the consumed amount, just error
 			return nil, nil, nil, errSpendOverflow
@@ -988,10 +1213,16 @@ func (vm *VM) SpendAll(
 		ins = append(ins, &avax.TransferableInput{
 			UTXOID: utxo.UTXOID,
 			Asset:  avax.Asset{ID: assetID},
-			In:     input,
+			In: &secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: indices,
+				},
+			},
 		})
 		// add the required keys to the array
-		keys = append(keys, signers)
+		signers := signingAddrs(&out.OutputOwners, indices)
+		keys = append(keys, signingKeys(kc, [][]ids.ShortID{signers})[0])
 	}
 
 	avax.SortTransferableInputsWithSigners(ins, keys)
@@ -1008,11 +1239,32 @@ func (vm *VM) Mint(
 	[]*Operation,
 	[][]*crypto.PrivateKeySECP256K1R,
 	error,
+) {
+	ops, signers, err := vm.MintFrom(utxos, kc.Addrs, amounts, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ops, signingKeys(kc, signers), nil
+}
+
+// MintFrom is Mint for when the keys of the minting addresses aren't
+// available. The operations spend mint outputs owned by [addrs]. Returns the
+// addresses that must sign each operation.
+func (vm *VM) MintFrom(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	amounts map[ids.ID]uint64,
+	to ids.ShortID,
+) (
+	[]*Operation,
+	[][]ids.ShortID,
+	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	// UTXO ID --> addresses that must sign the operation spending it
+	opSigners := make(map[ids.ID][]ids.ShortID)
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
@@ -1029,12 +1281,7 @@ func (vm *VM) Mint(
 			continue
 		}
 
-		inIntf, signers, err := kc.Spend(out, time)
-		if err != nil {
-			continue
-		}
-
-		in, ok := inIntf.(*secp256k1fx.Input)
+		indices, ok := secp256k1fx.MatchAddrs(&out.OutputOwners, addrs, time)
 		if !ok {
 			continue
 		}
@@ -1044,7 +1291,9 @@ func (vm *VM) Mint(
 			Asset:   utxo.Asset,
 			UTXOIDs: []*avax.UTXOID{&utxo.UTXOID},
 			Op: &secp256k1fx.MintOperation{
-				MintInput:  *in,
+				MintInput: secp256k1fx.Input{
+					SigIndices: indices,
+				},
 				MintOutput: *out,
 				TransferOutput: secp256k1fx.TransferOutput{
 					Amt: amount,
@@ -1055,8 +1304,7 @@ func (vm *VM) Mint(
 				},
 			},
 		})
-		// add the required keys to the array
-		keys = append(keys, signers)
+		opSigners[utxo.InputID()] = signingAddrs(&out.OutputOwners, indices)
 
 		// remove the asset from the required amounts to mint
 		delete(amounts, assetID)
@@ -1068,8 +1316,12 @@ func (vm *VM) Mint(
 		}
 	}
 
-	sortOperationsWithSigners(ops, keys, vm.codec)
-	return ops, keys, nil
+	sortOperations(ops, vm.codec)
+	signers := make([][]ids.ShortID, len(ops))
+	for i, op := range ops {
+		signers[i] = opSigners[op.UTXOIDs[0].InputID()]
+	}
+	return ops, signers, nil
 }
 
 // MintNFT ...
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	cjson "github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
 )
 
 // WalletClient ...
@@ -87,3 +88,28 @@ func (c *WalletClient) SendMultiple(
 	}, res)
 	return res.TxID, err
 }
+
+// ReserveUTXOs reserves [utxoIDs] of [user] for [ttl]
+func (c *WalletClient) ReserveUTXOs(
+	user api.UserPass,
+	utxoIDs []avax.UTXOID,
+	ttl time.Duration,
+) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("reserveUTXOs", &ReserveUTXOsArgs{
+		UserPass: user,
+		UTXOIDs:  utxoIDs,
+		TTL:      cjson.Uint64(ttl / time.Second),
+	}, res)
+	return res.Success, err
+}
+
+// ReleaseUTXOs releases the reservations of [utxoIDs] of [user]
+func (c *WalletClient) ReleaseUTXOs(user api.UserPass, utxoIDs []avax.UTXOID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("releaseUTXOs", &ReleaseUTXOsArgs{
+		UserPass: user,
+		UTXOIDs:  utxoIDs,
+	}, res)
+	return res.Success, err
+}
@@ -5,8 +5,10 @@ package avm
 
 import (
 	"container/list"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
@@ -14,15 +16,43 @@ import (
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	cjson "github.com/ava-labs/avalanchego/utils/json"
 	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
+const (
+	// Max number of UTXOs that can be reserved in a single call
+	maxReserveUTXOs = 1024
+
+	// Max duration a UTXO can be reserved for
+	maxReservationTTL = time.Hour
+)
+
+var (
+	errNoUTXOs             = errors.New("no UTXOs provided")
+	errTooManyUTXOs        = fmt.Errorf("number of UTXOs provided exceeds maximum of %d", maxReserveUTXOs)
+	errInvalidReservation  = fmt.Errorf("reservation TTL must be in (0, %s]", maxReservationTTL)
+	errUTXONotOwnedByUser  = errors.New("UTXO isn't spendable by the user")
+	errUTXOAlreadyReserved = errors.New("UTXO is already reserved")
+)
+
 // WalletService ...
 type WalletService struct {
 	vm *VM
 
 	pendingTxMap      map[ids.ID]*list.Element
-	pendingTxOrdering *list.List
+	pendingTxOrdering *list.List // of *walletTx
+
+	// UTXO ID --> time at which the reservation of the UTXO expires. Reserved
+	// UTXOs are never selected as inputs by this service.
+	reservedUTXOs map[ids.ID]time.Time
+}
+
+// walletTx is a tx issued through the wallet service that isn't decided yet
+type walletTx struct {
+	tx *Tx
+	// Time the tx was issued at
+	issued time.Time
 }
 
 func (w *WalletService) decided(txID ids.ID) {
@@ -49,10 +79,37 @@ func (w *WalletService) issue(txBytes []byte) (ids.ID, error) {
 		return txID, nil
 	}
 
-	w.pendingTxMap[txID] = w.pendingTxOrdering.PushBack(tx)
+	w.pendingTxMap[txID] = w.pendingTxOrdering.PushBack(&walletTx{
+		tx:     tx,
+		issued: w.vm.clock.Time(),
+	})
+
+	// The reservations of the UTXOs consumed by this tx have been fulfilled
+	for _, inputUTXO := range tx.InputUTXOs() {
+		delete(w.reservedUTXOs, inputUTXO.InputID())
+	}
 	return txID, nil
 }
 
+// unreserved returns the UTXOs in [utxos] that aren't currently reserved. Any
+// expired reservations are removed.
+func (w *WalletService) unreserved(utxos []*avax.UTXO) []*avax.UTXO {
+	now := w.vm.clock.Time()
+	for utxoID, expiry := range w.reservedUTXOs {
+		if !now.Before(expiry) {
+			delete(w.reservedUTXOs, utxoID)
+		}
+	}
+
+	unreservedUTXOs := make([]*avax.UTXO, 0, len(utxos))
+	for _, utxo := range utxos {
+		if _, reserved := w.reservedUTXOs[utxo.InputID()]; !reserved {
+			unreservedUTXOs = append(unreservedUTXOs, utxo)
+		}
+	}
+	return unreservedUTXOs
+}
+
 func (w *WalletService) update(utxos []*avax.UTXO) ([]*avax.UTXO, error) {
 	utxoMap := make(map[ids.ID]*avax.UTXO, len(utxos))
 	for _, utxo := range utxos {
@@ -60,7 +117,7 @@ func (w *WalletService) update(utxos []*avax.UTXO) ([]*avax.UTXO, error) {
 	}
 
 	for e := w.pendingTxOrdering.Front(); e != nil; e = e.Next() {
-		tx := e.Value.(*Tx)
+		tx := e.Value.(*walletTx).tx
 		for _, inputUTXO := range tx.InputUTXOs() {
 			if inputUTXO.Symbolic() {
 				continue
@@ -99,17 +156,134 @@ func (w *WalletService) IssueTx(r *http.Request, args *api.FormattedTx, reply *a
 	return err
 }
 
+// ReserveUTXOsArgs are the arguments for calling ReserveUTXOs
+type ReserveUTXOsArgs struct {
+	api.UserPass
+
+	// The UTXOs to reserve
+	UTXOIDs []avax.UTXOID `json:"utxoIDs"`
+
+	// Number of seconds the UTXOs are reserved for
+	TTL cjson.Uint64 `json:"ttl"`
+}
+
+// ReserveUTXOs marks the provided UTXOs of the user as reserved until either
+// the reservation expires, a transaction consuming them is issued through this
+// service, or they are released. Reserved UTXOs are never selected as inputs by
+// this service, which allows clients to build transactions spending them
+// without racing against concurrent calls to Send. Either all of the UTXOs are
+// reserved or none of them are.
+func (w *WalletService) ReserveUTXOs(r *http.Request, args *ReserveUTXOsArgs, reply *api.SuccessResponse) error {
+	w.vm.ctx.Log.Info("AVM Wallet: ReserveUTXOs called with username: %s", args.Username)
+
+	ttl := time.Duration(args.TTL) * time.Second
+	switch {
+	case len(args.UTXOIDs) == 0:
+		return errNoUTXOs
+	case len(args.UTXOIDs) > maxReserveUTXOs:
+		return errTooManyUTXOs
+	case ttl <= 0 || ttl > maxReservationTTL:
+		return errInvalidReservation
+	}
+
+	// Load user's UTXOs
+	utxos, _, err := w.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+	utxos, err = w.update(utxos)
+	if err != nil {
+		return err
+	}
+	utxos = w.unreserved(utxos)
+
+	spendable := ids.NewSet(len(utxos))
+	for _, utxo := range utxos {
+		spendable.Add(utxo.InputID())
+	}
+
+	// Make sure every UTXO can be reserved before reserving any of them
+	for _, utxoID := range args.UTXOIDs {
+		inputID := utxoID.InputID()
+		if _, reserved := w.reservedUTXOs[inputID]; reserved {
+			return fmt.Errorf("%w: %s", errUTXOAlreadyReserved, &utxoID)
+		}
+		if !spendable.Contains(inputID) {
+			return fmt.Errorf("%w: %s", errUTXONotOwnedByUser, &utxoID)
+		}
+	}
+
+	expiry := w.vm.clock.Time().Add(ttl)
+	for _, utxoID := range args.UTXOIDs {
+		w.reservedUTXOs[utxoID.InputID()] = expiry
+	}
+	reply.Success = true
+	return nil
+}
+
+// ReleaseUTXOsArgs are the arguments for calling ReleaseUTXOs
+type ReleaseUTXOsArgs struct {
+	api.UserPass
+
+	// The UTXOs to release
+	UTXOIDs []avax.UTXOID `json:"utxoIDs"`
+}
+
+// ReleaseUTXOs removes the reservations of the provided UTXOs of the user
+func (w *WalletService) ReleaseUTXOs(r *http.Request, args *ReleaseUTXOsArgs, reply *api.SuccessResponse) error {
+	w.vm.ctx.Log.Info("AVM Wallet: ReleaseUTXOs called with username: %s", args.Username)
+
+	if len(args.UTXOIDs) == 0 {
+		return errNoUTXOs
+	}
+
+	// Only the owner of the UTXOs is allowed to release them
+	utxos, _, err := w.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+	utxos, err = w.update(utxos)
+	if err != nil {
+		return err
+	}
+
+	owned := ids.NewSet(len(utxos))
+	for _, utxo := range utxos {
+		owned.Add(utxo.InputID())
+	}
+	for _, utxoID := range args.UTXOIDs {
+		if inputID := utxoID.InputID(); owned.Contains(inputID) {
+			delete(w.reservedUTXOs, inputID)
+		}
+	}
+	reply.Success = true
+	return nil
+}
+
+// WalletSendReply defines the Send and SendMultiple replies returned from the
+// wallet API
+type WalletSendReply struct {
+	api.JSONTxIDChangeAddr
+
+	// If the user holds only some of the keys of a multisig UTXO the tx
+	// spends, the tx isn't issued and [TxID] is empty. [PartialTx] is the
+	// partially signed tx instead, to be completed with avm.appendSignatures.
+	PartialTx string              `json:"partialTx,omitempty"`
+	Encoding  formatting.Encoding `json:"encoding,omitempty"`
+}
+
 // Send returns the ID of the newly created transaction
-func (w *WalletService) Send(r *http.Request, args *SendArgs, reply *api.JSONTxIDChangeAddr) error {
+func (w *WalletService) Send(r *http.Request, args *SendArgs, reply *WalletSendReply) error {
 	return w.SendMultiple(r, &SendMultipleArgs{
 		JSONSpendHeader: args.JSONSpendHeader,
 		Outputs:         []SendOutput{args.SendOutput},
 		Memo:            args.Memo,
+		Fee:             args.Fee,
 	}, reply)
 }
 
 // SendMultiple sends a transaction with multiple outputs.
-func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, reply *api.JSONTxIDChangeAddr) error {
+func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, reply *WalletSendReply) error {
 	w.vm.ctx.Log.Info("AVM Wallet: Send called with username: %s", args.Username)
 
 	// Validate the memo field
@@ -121,6 +295,10 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	} else if len(args.Outputs) == 0 {
 		return errNoOutputs
 	}
+	fee, err := w.vm.sendFee(uint64(args.Fee))
+	if err != nil {
+		return err
+	}
 
 	// Parse the from addresses
 	fromAddrs := ids.NewShortSet(len(args.From))
@@ -142,6 +320,7 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	if err != nil {
 		return err
 	}
+	utxos = w.unreserved(utxos)
 
 	// Parse the change address.
 	if len(kc.Keys) == 0 {
@@ -203,7 +382,7 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		amountsWithFee[assetKey] = amount
 	}
 
-	amountWithFee, err := safemath.Add64(amounts[w.vm.feeAssetID], w.vm.txFee)
+	amountWithFee, err := safemath.Add64(amounts[w.vm.feeAssetID], fee)
 	if err != nil {
 		return fmt.Errorf("problem calculating required spend amount: %w", err)
 	}
@@ -213,6 +392,7 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		utxos,
 		kc,
 		amountsWithFee,
+		true,
 	)
 	if err != nil {
 		return err
@@ -249,12 +429,19 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		return err
 	}
 
-	txID, err := w.issue(tx.Bytes())
+	reply.ChangeAddr, err = w.vm.FormatLocalAddress(changeAddr)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
+	}
+	if isPartiallySigned(keys) {
+		reply.Encoding = formatting.Hex
+		reply.PartialTx, err = formatting.Encode(reply.Encoding, tx.Bytes())
+		return err
 	}
 
-	reply.TxID = txID
-	reply.ChangeAddr, err = w.vm.FormatLocalAddress(changeAddr)
-	return err
+	reply.TxID, err = w.issue(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+	return nil
 }
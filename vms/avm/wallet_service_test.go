@@ -6,10 +6,16 @@ package avm
 import (
 	"container/list"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
 // Returns:
@@ -30,7 +36,12 @@ func setupWS(t *testing.T, isAVAXAsset bool) ([]byte, *VM, *WalletService, *atom
 		genesisTx = GetCreateTxFromGenesisTest(t, genesisBytes, feeAssetName)
 	}
 
-	ws := &WalletService{vm: vm, pendingTxMap: make(map[ids.ID]*list.Element), pendingTxOrdering: list.New()}
+	ws := &WalletService{
+		vm:                vm,
+		pendingTxMap:      make(map[ids.ID]*list.Element),
+		pendingTxOrdering: list.New(),
+		reservedUTXOs:     make(map[ids.ID]time.Time),
+	}
 	return genesisBytes, vm, ws, m, genesisTx
 }
 
@@ -109,7 +120,7 @@ func TestWalletService_SendMultiple(t *testing.T) {
 					},
 				},
 			}
-			reply := &api.JSONTxIDChangeAddr{}
+			reply := &WalletSendReply{}
 			vm.timer.Cancel()
 			if err := ws.SendMultiple(nil, args, reply); err != nil {
 				t.Fatalf("Failed to send transaction: %s", err)
@@ -132,3 +143,217 @@ func TestWalletService_SendMultiple(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletService_ReserveUTXOs(t *testing.T) {
+	_, vm, ws, _, genesisTx := setupWSWithKeys(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.timer.Cancel()
+
+	userPass := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+	utxos, _, err := vm.LoadUser(username, password, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoIDs := make([]avax.UTXOID, len(utxos))
+	for i, utxo := range utxos {
+		utxoIDs[i] = utxo.UTXOID
+	}
+
+	reply := &api.SuccessResponse{}
+	if err := ws.ReserveUTXOs(nil, &ReserveUTXOsArgs{
+		UserPass: userPass,
+		UTXOIDs:  utxoIDs,
+		TTL:      60,
+	}, reply); err != nil {
+		t.Fatalf("Failed to reserve UTXOs: %s", err)
+	}
+
+	// Reserving the same UTXOs again should fail
+	if err := ws.ReserveUTXOs(nil, &ReserveUTXOsArgs{
+		UserPass: userPass,
+		UTXOIDs:  utxoIDs[:1],
+		TTL:      60,
+	}, reply); err == nil {
+		t.Fatal("Should have failed to reserve already reserved UTXOs")
+	}
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendArgs := &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{UserPass: userPass},
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      addrStr,
+		},
+	}
+
+	// All the user's UTXOs are reserved, so nothing can be sent
+	if err := ws.Send(nil, sendArgs, &WalletSendReply{}); err == nil {
+		t.Fatal("Should have failed to send with all UTXOs reserved")
+	}
+
+	// Reservations expire after their TTL
+	vm.clock.Set(vm.clock.Time().Add(time.Minute))
+	if err := ws.Send(nil, sendArgs, &WalletSendReply{}); err != nil {
+		t.Fatalf("Failed to send after reservations expired: %s", err)
+	}
+}
+
+func TestWalletService_ReleaseUTXOs(t *testing.T) {
+	_, vm, ws, _, _ := setupWSWithKeys(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	userPass := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+	utxos, _, err := vm.LoadUser(username, password, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxoIDs := []avax.UTXOID{utxos[0].UTXOID}
+
+	reply := &api.SuccessResponse{}
+	if err := ws.ReserveUTXOs(nil, &ReserveUTXOsArgs{
+		UserPass: userPass,
+		UTXOIDs:  utxoIDs,
+		TTL:      60,
+	}, reply); err != nil {
+		t.Fatalf("Failed to reserve UTXOs: %s", err)
+	}
+	if err := ws.ReleaseUTXOs(nil, &ReleaseUTXOsArgs{
+		UserPass: userPass,
+		UTXOIDs:  utxoIDs,
+	}, reply); err != nil {
+		t.Fatalf("Failed to release UTXOs: %s", err)
+	}
+	if len(ws.reservedUTXOs) != 0 {
+		t.Fatalf("Expected no reserved UTXOs but found %d", len(ws.reservedUTXOs))
+	}
+
+	// Released UTXOs can be reserved again
+	if err := ws.ReserveUTXOs(nil, &ReserveUTXOsArgs{
+		UserPass: userPass,
+		UTXOIDs:  utxoIDs,
+		TTL:      60,
+	}, reply); err != nil {
+		t.Fatalf("Failed to reserve released UTXOs: %s", err)
+	}
+}
+
+func TestWalletService_SendPartiallySigned(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, vm, ws, _, _ := setupWS(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	// Move the AVAX of [keys[0]] to a UTXO that [keys[0]] and [keys[1]] must
+	// both sign for
+	avaxTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
+	multisigAddrs := []ids.ShortID{
+		keys[0].PublicKey().Address(),
+		keys[1].PublicKey().Address(),
+	}
+	ids.SortShortIDs(multisigAddrs)
+	multisigTx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    networkID,
+		BlockchainID: chainID,
+		Ins: []*avax.TransferableInput{{
+			UTXOID: avax.UTXOID{
+				TxID:        avaxTx.ID(),
+				OutputIndex: 2,
+			},
+			Asset: avax.Asset{ID: avaxTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt: startBalance,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		}},
+		Outs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: avaxTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: startBalance - vm.txFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 2,
+					Addrs:     multisigAddrs,
+				},
+			},
+		}},
+	}}}
+	assert.NoError(multisigTx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}))
+	tx, err := vm.ParseTx(multisigTx.Bytes())
+	assert.NoError(err)
+	assert.NoError(tx.Verify())
+	assert.NoError(tx.Accept())
+
+	// The user only holds [keys[0]]
+	user := userState{vm: vm}
+	db, err := vm.ctx.Keystore.GetDatabase(username, password)
+	assert.NoError(err)
+	assert.NoError(user.SetKey(db, keys[0]))
+	assert.NoError(user.SetAddresses(db, []ids.ShortID{keys[0].PublicKey().Address()}))
+
+	toStr, err := vm.FormatLocalAddress(keys[2].PublicKey().Address())
+	assert.NoError(err)
+	userPass := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+	reply := &WalletSendReply{}
+	vm.timer.Cancel()
+	assert.NoError(ws.Send(nil, &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{UserPass: userPass},
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: avaxTx.ID().String(),
+			To:      toStr,
+		},
+	}, reply))
+	assert.Equal(ids.Empty, reply.TxID)
+	assert.NotEmpty(reply.PartialTx)
+	assert.Empty(vm.txs)
+
+	// The user can't complete the tx without [keys[1]]
+	appendArgs := &AppendSignaturesArgs{
+		UserPass:  userPass,
+		PartialTx: reply.PartialTx,
+		Encoding:  reply.Encoding,
+	}
+	appendReply := &AppendSignaturesReply{}
+	assert.NoError(s.AppendSignatures(nil, appendArgs, appendReply))
+	missingStr, err := vm.FormatLocalAddress(keys[1].PublicKey().Address())
+	assert.NoError(err)
+	assert.Equal([]string{missingStr}, appendReply.MissingSigners)
+	assert.Empty(vm.txs)
+
+	// Once the user holds [keys[1]], the tx is completed and issued
+	assert.NoError(user.SetKey(db, keys[1]))
+	assert.NoError(user.SetAddresses(db, multisigAddrs))
+	appendReply = &AppendSignaturesReply{}
+	assert.NoError(s.AppendSignatures(nil, appendArgs, appendReply))
+	assert.Empty(appendReply.MissingSigners)
+	assert.Len(vm.txs, 1)
+	assert.Equal(vm.txs[0].ID(), appendReply.TxID)
+}
@@ -0,0 +1,666 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/timer"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// Max number of webhooks a keystore user may register
+	maxWebhooksPerUser = 16
+	// Max number of addresses a webhook may watch
+	maxWebhookAddresses = 1024
+	// Max number of notifications sent at once
+	webhookBatchSize = 64
+	// Number of random bytes in a webhook's secret
+	webhookSecretLen = 32
+
+	// Delay before the first retry of a failed notification. The delay
+	// doubles with every failed attempt, up to [maxWebhookRetryDelay].
+	initialWebhookRetryDelay = time.Second
+	maxWebhookRetryDelay     = time.Hour
+
+	defaultWebhookMaxAttempts = 10
+	defaultWebhookTimeout     = 10 * time.Second
+
+	// WebhookSignatureHeader is the header of a notification that holds the
+	// hex encoded HMAC-SHA256, keyed with the webhook's secret, of
+	// "<timestamp>.<body>"
+	WebhookSignatureHeader = "X-Avalanche-Signature"
+	// WebhookTimestampHeader is the header of a notification that holds the
+	// Unix time at which it was sent
+	WebhookTimestampHeader = "X-Avalanche-Timestamp"
+)
+
+var (
+	webhookStatePrefix       = []byte("webhook")
+	webhookOutboxStatePrefix = []byte("webhookOutbox")
+
+	errTooManyWebhooks         = fmt.Errorf("keystore user can't register more than %d webhooks", maxWebhooksPerUser)
+	errTooManyWebhookAddresses = fmt.Errorf("webhook can't watch more than %d addresses", maxWebhookAddresses)
+	errNoWebhookAddresses      = errors.New("webhook must watch at least one address")
+	errInvalidWebhookURL       = errors.New("webhook URL must be an absolute https URL")
+	errUnknownWebhook          = errors.New("unknown webhook")
+	errWebhooksDisabled        = errors.New("webhooks are disabled on this node")
+	errWebhookHostNotAllowed   = errors.New("webhook host isn't allowed on this node")
+	errPrivateWebhookIP        = errors.New("webhook host resolves to a private IP")
+)
+
+// webhook notifies [URL] of the accepted txs that touch one of [Addresses]
+type webhook struct {
+	// Keystore user that registered the webhook
+	Username string `serialize:"true"`
+	URL      string `serialize:"true"`
+	// Key that the notifications are signed with
+	Secret    string        `serialize:"true"`
+	Addresses []ids.ShortID `serialize:"true"`
+}
+
+// webhookDelivery is a notification waiting in the outbox
+type webhookDelivery struct {
+	WebhookID ids.ID `serialize:"true"`
+	TxID      ids.ID `serialize:"true"`
+	// Addresses watched by the webhook that the tx touched
+	Addresses []ids.ShortID `serialize:"true"`
+	// Number of failed attempts to send the notification
+	Attempts uint32 `serialize:"true"`
+	// Unix time, in nanoseconds, before which the notification isn't sent
+	NextAttempt int64 `serialize:"true"`
+}
+
+// WebhookNotification is the body of the requests sent to webhooks
+type WebhookNotification struct {
+	WebhookID ids.ID `json:"webhookID"`
+	// Retries of a notification have the same delivery ID, so receivers should
+	// use it to drop duplicates. Notifications may arrive out of order.
+	DeliveryID cjson.Uint64 `json:"deliveryID"`
+	ChainID    ids.ID       `json:"chainID"`
+	TxID       ids.ID       `json:"txID"`
+	// Addresses watched by the webhook that the tx touched
+	Addresses []string `json:"addresses"`
+}
+
+// webhookRequest is a notification being sent
+type webhookRequest struct {
+	index    uint64
+	delivery *webhookDelivery
+	url      string
+	body     []byte
+	// Unix time at which the request was signed
+	timestamp string
+	signature string
+}
+
+// webhookNotifier notifies webhooks of the accepted txs touching the addresses
+// they watch. Notifications are written to an outbox in the same batch as the
+// accepted tx, so they survive restarts, and are sent from the outbox with
+// retries until they succeed or run out of attempts.
+//
+// Unless noted otherwise, methods must be called with the context lock held.
+type webhookNotifier struct {
+	vm          *VM
+	maxAttempts uint32
+	timeout     time.Duration
+	client      *http.Client
+
+	// True if keystore users may register webhooks
+	enabled bool
+	// Hosts that webhooks may be registered at. If empty, any host is allowed.
+	allowedHosts map[string]struct{}
+	// Returns an error if notifications may not be sent to [ip]
+	checkIP func(ip net.IP) error
+
+	hooksDB  database.Database
+	outboxDB database.Database
+
+	// Webhook ID --> webhook
+	hooks map[ids.ID]*webhook
+	// Address --> IDs of the webhooks watching it
+	watchers map[ids.ShortID]ids.Set
+	// Index of the next notification added to the outbox
+	nextIndex uint64
+
+	timer *timer.Timer
+	// Cancelled when the notifier is closed to abort the requests in flight
+	requestCtx     context.Context
+	cancelRequests context.CancelFunc
+}
+
+// newWebhookNotifier returns a notifier that stores its webhooks and outbox in
+// [db]
+func newWebhookNotifier(vm *VM, db database.Database, config WebhookConfig) (*webhookNotifier, error) {
+	maxAttempts := config.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+	timeout := defaultWebhookTimeout
+	if config.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse webhook timeout: %w", err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("webhook timeout must be positive but is %s", timeout)
+		}
+	}
+
+	w := &webhookNotifier{
+		vm:          vm,
+		maxAttempts: maxAttempts,
+		timeout:     timeout,
+		enabled:     config.Enabled,
+		checkIP:     checkWebhookIP,
+		hooksDB:     prefixdb.New(webhookStatePrefix, db),
+		outboxDB:    prefixdb.New(webhookOutboxStatePrefix, db),
+		hooks:       make(map[ids.ID]*webhook),
+		watchers:    make(map[ids.ShortID]ids.Set),
+	}
+	if len(config.AllowedHosts) > 0 {
+		w.allowedHosts = make(map[string]struct{}, len(config.AllowedHosts))
+		for _, host := range config.AllowedHosts {
+			w.allowedHosts[host] = struct{}{}
+		}
+	}
+	// The IP is checked when connecting, rather than only when the webhook is
+	// registered, so that a host can't be made to resolve to a private IP
+	// later on. Redirects aren't followed.
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			return w.checkIP(net.ParseIP(host))
+		},
+	}
+	w.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	w.requestCtx, w.cancelRequests = context.WithCancel(context.Background())
+	w.timer = timer.NewTimer(w.send)
+	return w, nil
+}
+
+// load the webhooks and the index of the last notification in the outbox
+func (w *webhookNotifier) load() error {
+	hookIt := w.hooksDB.NewIterator()
+	defer hookIt.Release()
+
+	for hookIt.Next() {
+		hookID, err := ids.ToID(hookIt.Key())
+		if err != nil {
+			return err
+		}
+		hook := &webhook{}
+		if _, err := w.vm.codec.Unmarshal(hookIt.Value(), hook); err != nil {
+			return fmt.Errorf("couldn't unmarshal webhook %s: %w", hookID, err)
+		}
+		w.add(hookID, hook)
+	}
+	if err := hookIt.Error(); err != nil {
+		return err
+	}
+
+	outboxIt := w.outboxDB.NewIterator()
+	defer outboxIt.Release()
+
+	for outboxIt.Next() {
+		index, err := database.ParseUInt64(outboxIt.Key())
+		if err != nil {
+			return err
+		}
+		if index >= w.nextIndex {
+			w.nextIndex = index + 1
+		}
+	}
+	return outboxIt.Error()
+}
+
+// start sending the notifications in the outbox
+func (w *webhookNotifier) start() {
+	go w.vm.ctx.Log.RecoverAndPanic(w.timer.Dispatch)
+	w.timer.SetTimeoutIn(0)
+}
+
+// close stops sending notifications. Must be called without the context lock
+// held.
+func (w *webhookNotifier) close() {
+	w.cancelRequests()
+	w.timer.Stop()
+}
+
+func (w *webhookNotifier) add(hookID ids.ID, hook *webhook) {
+	w.hooks[hookID] = hook
+	for _, addr := range hook.Addresses {
+		hookIDs, ok := w.watchers[addr]
+		if !ok {
+			hookIDs = ids.Set{}
+			w.watchers[addr] = hookIDs
+		}
+		hookIDs.Add(hookID)
+	}
+}
+
+// watching returns true if any address is watched by a webhook
+func (w *webhookNotifier) watching() bool { return len(w.watchers) > 0 }
+
+// register a webhook of [username] that notifies [hookURL] of the accepted txs
+// touching one of [addrs]. Returns the ID of the webhook and the secret its
+// notifications are signed with.
+func (w *webhookNotifier) register(username, hookURL string, addrs ids.ShortSet) (ids.ID, string, error) {
+	switch {
+	case !w.enabled:
+		return ids.ID{}, "", errWebhooksDisabled
+	case addrs.Len() == 0:
+		return ids.ID{}, "", errNoWebhookAddresses
+	case addrs.Len() > maxWebhookAddresses:
+		return ids.ID{}, "", errTooManyWebhookAddresses
+	case len(w.list(username)) >= maxWebhooksPerUser:
+		return ids.ID{}, "", errTooManyWebhooks
+	}
+	parsedURL, err := url.Parse(hookURL)
+	if err != nil || parsedURL.Scheme != "https" || parsedURL.Host == "" {
+		return ids.ID{}, "", errInvalidWebhookURL
+	}
+	if err := w.checkHost(parsedURL.Hostname()); err != nil {
+		return ids.ID{}, "", err
+	}
+
+	hookID := ids.ID{}
+	if _, err := rand.Read(hookID[:]); err != nil {
+		return ids.ID{}, "", fmt.Errorf("couldn't generate webhook ID: %w", err)
+	}
+	secret := make([]byte, webhookSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return ids.ID{}, "", fmt.Errorf("couldn't generate webhook secret: %w", err)
+	}
+	hookAddrs := addrs.List()
+	ids.SortShortIDs(hookAddrs)
+	hook := &webhook{
+		Username:  username,
+		URL:       hookURL,
+		Secret:    hex.EncodeToString(secret),
+		Addresses: hookAddrs,
+	}
+
+	hookBytes, err := w.vm.codec.Marshal(codecVersion, hook)
+	if err != nil {
+		return ids.ID{}, "", err
+	}
+	if err := w.hooksDB.Put(hookID[:], hookBytes); err != nil {
+		return ids.ID{}, "", err
+	}
+	if err := w.vm.db.Commit(); err != nil {
+		return ids.ID{}, "", err
+	}
+	w.add(hookID, hook)
+	return hookID, hook.Secret, nil
+}
+
+// checkHost returns an error if webhooks may not be registered at [host]
+func (w *webhookNotifier) checkHost(host string) error {
+	if w.allowedHosts != nil {
+		if _, ok := w.allowedHosts[host]; !ok {
+			return fmt.Errorf("%w: %s", errWebhookHostNotAllowed, host)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	ipAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("couldn't resolve webhook host %s: %w", host, err)
+	}
+	for _, ipAddr := range ipAddrs {
+		if err := w.checkIP(ipAddr.IP); err != nil {
+			return fmt.Errorf("%w: %s", err, ipAddr.IP)
+		}
+	}
+	return nil
+}
+
+// checkWebhookIP returns an error if [ip] isn't a public unicast IP
+func checkWebhookIP(ip net.IP) error {
+	if ip == nil || ip.IsUnspecified() || ip.IsMulticast() || (utils.IPDesc{IP: ip}).IsPrivate() {
+		return errPrivateWebhookIP
+	}
+	return nil
+}
+
+// remove the webhook [hookID] of [username]. Its notifications left in the
+// outbox are dropped.
+func (w *webhookNotifier) remove(username string, hookID ids.ID) error {
+	hook, ok := w.hooks[hookID]
+	if !ok || hook.Username != username {
+		return errUnknownWebhook
+	}
+	if err := w.hooksDB.Delete(hookID[:]); err != nil {
+		return err
+	}
+	if err := w.vm.db.Commit(); err != nil {
+		return err
+	}
+
+	delete(w.hooks, hookID)
+	for _, addr := range hook.Addresses {
+		hookIDs := w.watchers[addr]
+		hookIDs.Remove(hookID)
+		if hookIDs.Len() == 0 {
+			delete(w.watchers, addr)
+		}
+	}
+	return nil
+}
+
+// list returns the IDs of the webhooks of [username] in sorted order
+func (w *webhookNotifier) list(username string) []ids.ID {
+	hookIDs := []ids.ID(nil)
+	for hookID, hook := range w.hooks {
+		if hook.Username == username {
+			hookIDs = append(hookIDs, hookID)
+		}
+	}
+	ids.SortIDs(hookIDs)
+	return hookIDs
+}
+
+// notify adds a notification of [txID] to the outbox of every webhook watching
+// one of [addrs]. The notifications are written to the VM's database but not
+// committed, so they are committed along with the accepted tx.
+func (w *webhookNotifier) notify(txID ids.ID, addrs ids.ShortSet) error {
+	// Webhook ID --> Addresses it watches that the tx touched
+	touched := make(map[ids.ID][]ids.ShortID)
+	for addr := range addrs {
+		for hookID := range w.watchers[addr] {
+			touched[hookID] = append(touched[hookID], addr)
+		}
+	}
+	if len(touched) == 0 {
+		return nil
+	}
+
+	// Add the notifications in a deterministic order
+	hookIDs := make([]ids.ID, 0, len(touched))
+	for hookID := range touched {
+		hookIDs = append(hookIDs, hookID)
+	}
+	ids.SortIDs(hookIDs)
+
+	for _, hookID := range hookIDs {
+		hookAddrs := touched[hookID]
+		ids.SortShortIDs(hookAddrs)
+		deliveryBytes, err := w.vm.codec.Marshal(codecVersion, &webhookDelivery{
+			WebhookID: hookID,
+			TxID:      txID,
+			Addresses: hookAddrs,
+		})
+		if err != nil {
+			return err
+		}
+		if err := w.outboxDB.Put(database.PackUInt64(w.nextIndex), deliveryBytes); err != nil {
+			return err
+		}
+		w.nextIndex++
+	}
+
+	// The notifications can't be read before the context lock is released,
+	// which happens after they are committed.
+	w.timer.SetTimeoutIn(0)
+	return nil
+}
+
+// send the notifications in the outbox that are due and schedule the next
+// round. Called by the timer without the context lock held.
+func (w *webhookNotifier) send() {
+	w.vm.ctx.Lock.Lock()
+	now := w.vm.clock.Time()
+	requests, next, err := w.dueRequests(now)
+	w.vm.ctx.Lock.Unlock()
+	if err != nil {
+		w.vm.ctx.Log.Error("Couldn't read the webhook outbox: %s", err)
+		w.timer.SetTimeoutIn(maxWebhookRetryDelay)
+		return
+	}
+
+	results := make([]error, len(requests))
+	wg := sync.WaitGroup{}
+	for i, request := range requests {
+		wg.Add(1)
+		go func(i int, request *webhookRequest) {
+			defer wg.Done()
+			results[i] = w.post(request)
+		}(i, request)
+	}
+	wg.Wait()
+
+	if w.requestCtx.Err() != nil {
+		// The notifier is closing. The notifications remain in the outbox and
+		// are sent after a restart.
+		return
+	}
+
+	w.vm.ctx.Lock.Lock()
+	err = w.record(requests, results)
+	w.vm.ctx.Lock.Unlock()
+	if err != nil {
+		w.vm.ctx.Log.Error("Couldn't update the webhook outbox: %s", err)
+		w.timer.SetTimeoutIn(maxWebhookRetryDelay)
+		return
+	}
+
+	switch {
+	case len(requests) == webhookBatchSize:
+		// There may be more notifications that are due
+		w.timer.SetTimeoutIn(0)
+	case !next.IsZero():
+		w.timer.SetTimeoutIn(next.Sub(now))
+	}
+}
+
+// dueRequests returns up to [webhookBatchSize] requests of the notifications
+// that are due at [now], and the time at which the next of the other notifications is
+// due. Returns the zero time if there are no other notifications. The
+// notifications of removed webhooks are dropped.
+func (w *webhookNotifier) dueRequests(now time.Time) ([]*webhookRequest, time.Time, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	next := time.Time{}
+	requests := []*webhookRequest(nil)
+	dropped := [][]byte(nil)
+
+	it := w.outboxDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() && len(requests) < webhookBatchSize {
+		index, err := database.ParseUInt64(it.Key())
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		delivery := &webhookDelivery{}
+		if _, err := w.vm.codec.Unmarshal(it.Value(), delivery); err != nil {
+			return nil, time.Time{}, err
+		}
+		hook, ok := w.hooks[delivery.WebhookID]
+		if !ok {
+			dropped = append(dropped, it.Key())
+			continue
+		}
+		if nextAttempt := time.Unix(0, delivery.NextAttempt); nextAttempt.After(now) {
+			if next.IsZero() || nextAttempt.Before(next) {
+				next = nextAttempt
+			}
+			continue
+		}
+
+		body, err := w.notification(index, delivery)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		_, _ = mac.Write([]byte(timestamp + "."))
+		_, _ = mac.Write(body)
+		requests = append(requests, &webhookRequest{
+			index:     index,
+			delivery:  delivery,
+			url:       hook.URL,
+			body:      body,
+			timestamp: timestamp,
+			signature: hex.EncodeToString(mac.Sum(nil)),
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if len(dropped) == 0 {
+		return requests, next, nil
+	}
+	for _, key := range dropped {
+		if err := w.outboxDB.Delete(key); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	return requests, next, w.vm.db.Commit()
+}
+
+// notification returns the body of the notification [index]
+func (w *webhookNotifier) notification(index uint64, delivery *webhookDelivery) ([]byte, error) {
+	addrs, err := w.formatAddresses(delivery.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(WebhookNotification{
+		WebhookID:  delivery.WebhookID,
+		DeliveryID: cjson.Uint64(index),
+		ChainID:    w.vm.ctx.ChainID,
+		TxID:       delivery.TxID,
+		Addresses:  addrs,
+	})
+}
+
+// post [request] to its webhook. Called without the context lock held.
+func (w *webhookNotifier) post(request *webhookRequest) error {
+	httpRequest, err := http.NewRequestWithContext(w.requestCtx, http.MethodPost, request.url, bytes.NewReader(request.body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set(WebhookTimestampHeader, request.timestamp)
+	httpRequest.Header.Set(WebhookSignatureHeader, request.signature)
+
+	response, err := w.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	if err := response.Body.Close(); err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// record the [results] of sending [requests]. Sent notifications, and the ones
+// that ran out of attempts, are removed from the outbox. The others are
+// retried after a delay.
+func (w *webhookNotifier) record(requests []*webhookRequest, results []error) error {
+	now := w.vm.clock.Time()
+	for i, request := range requests {
+		key := database.PackUInt64(request.index)
+		delivery := request.delivery
+		if results[i] == nil {
+			if err := w.outboxDB.Delete(key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		delivery.Attempts++
+		if delivery.Attempts >= w.maxAttempts {
+			w.vm.ctx.Log.Warn("Dropping notification %d of tx %s to webhook %s after %d attempts: %s",
+				request.index, delivery.TxID, delivery.WebhookID, delivery.Attempts, results[i])
+			if err := w.outboxDB.Delete(key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		w.vm.ctx.Log.Debug("Failed to send notification %d of tx %s to webhook %s: %s",
+			request.index, delivery.TxID, delivery.WebhookID, results[i])
+		delivery.NextAttempt = now.Add(webhookRetryDelay(delivery.Attempts)).UnixNano()
+		deliveryBytes, err := w.vm.codec.Marshal(codecVersion, delivery)
+		if err != nil {
+			return err
+		}
+		if err := w.outboxDB.Put(key, deliveryBytes); err != nil {
+			return err
+		}
+	}
+	return w.vm.db.Commit()
+}
+
+// webhookRetryDelay returns how long to wait before retrying a notification
+// that failed [attempts] times
+func webhookRetryDelay(attempts uint32) time.Duration {
+	delay := initialWebhookRetryDelay
+	for i := uint32(1); i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookRetryDelay {
+		return maxWebhookRetryDelay
+	}
+	return delay
+}
+
+// formatAddresses returns [addrs] formatted for the API
+func (w *webhookNotifier) formatAddresses(addrs []ids.ShortID) ([]string, error) {
+	addrStrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		addrStr, err := w.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		addrStrs[i] = addrStr
+	}
+	return addrStrs, nil
+}
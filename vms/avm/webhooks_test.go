@@ -0,0 +1,217 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type receivedNotification struct {
+	header http.Header
+	body   []byte
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, webhookRetryDelay(1))
+	assert.Equal(t, 2*time.Second, webhookRetryDelay(2))
+	assert.Equal(t, 8*time.Second, webhookRetryDelay(4))
+	assert.Equal(t, maxWebhookRetryDelay, webhookRetryDelay(100))
+}
+
+func TestWebhookRegister(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	addrs := ids.ShortSet{}
+	addrs.Add(keys[0].PublicKey().Address())
+
+	// Webhooks are disabled by default
+	_, _, err := vm.webhooks.register(username, "https://1.1.1.1", addrs)
+	assert.Equal(errWebhooksDisabled, err)
+	vm.webhooks.enabled = true
+
+	_, _, err = vm.webhooks.register(username, "https://1.1.1.1", ids.ShortSet{})
+	assert.Equal(errNoWebhookAddresses, err)
+	_, _, err = vm.webhooks.register(username, "http://1.1.1.1", addrs)
+	assert.Equal(errInvalidWebhookURL, err)
+	_, _, err = vm.webhooks.register(username, "ftp://1.1.1.1", addrs)
+	assert.Equal(errInvalidWebhookURL, err)
+	_, _, err = vm.webhooks.register(username, "/notify", addrs)
+	assert.Equal(errInvalidWebhookURL, err)
+	for _, hookURL := range []string{
+		"https://127.0.0.1:9650",
+		"https://10.0.0.1",
+		"https://169.254.169.254",
+		"https://[::1]",
+		"https://[fe80::1]",
+		"https://0.0.0.0",
+	} {
+		_, _, err = vm.webhooks.register(username, hookURL, addrs)
+		assert.ErrorIs(err, errPrivateWebhookIP, hookURL)
+	}
+
+	vm.webhooks.allowedHosts = map[string]struct{}{"1.1.1.1": {}}
+	_, _, err = vm.webhooks.register(username, "https://8.8.8.8", addrs)
+	assert.ErrorIs(err, errWebhookHostNotAllowed)
+
+	for i := 0; i < maxWebhooksPerUser; i++ {
+		_, _, err = vm.webhooks.register(username, "https://1.1.1.1", addrs)
+		assert.NoError(err)
+	}
+	_, _, err = vm.webhooks.register(username, "https://1.1.1.1", addrs)
+	assert.Equal(errTooManyWebhooks, err)
+
+	hookIDs := vm.webhooks.list(username)
+	assert.Len(hookIDs, maxWebhooksPerUser)
+	assert.Empty(vm.webhooks.list("other user"))
+	assert.Equal(errUnknownWebhook, vm.webhooks.remove("other user", hookIDs[0]))
+
+	for _, hookID := range hookIDs {
+		assert.NoError(vm.webhooks.remove(username, hookID))
+	}
+	assert.False(vm.webhooks.watching())
+	assert.Equal(errUnknownWebhook, vm.webhooks.remove(username, hookIDs[0]))
+}
+
+func TestWebhookNotifications(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	statuses := make(chan int, 1)
+	received := make(chan receivedNotification, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- receivedNotification{
+			header: r.Header,
+			body:   body,
+		}
+		w.WriteHeader(<-statuses)
+	}))
+	defer server.Close()
+
+	// The test server listens on a loopback IP and uses a self-signed
+	// certificate
+	vm.webhooks.enabled = true
+	vm.webhooks.checkIP = func(net.IP) error { return nil }
+	vm.webhooks.client = server.Client()
+
+	addr := keys[0].PublicKey().Address()
+	hookID, secret, err := vm.webhooks.register(username, server.URL, ids.ShortSet{addr: struct{}{}})
+	assert.NoError(err)
+	assert.True(vm.webhooks.watching())
+
+	// Accepting a tx that spends a UTXO of [addr] adds a notification to the
+	// outbox
+	newTx := NewTx(t, genesisBytes, vm)
+	tx, err := vm.ParseTx(newTx.Bytes())
+	assert.NoError(err)
+	assert.NoError(tx.Verify())
+	assert.NoError(tx.Accept())
+
+	// The webhooks and the outbox are persisted
+	reloaded, err := newWebhookNotifier(vm, vm.db, WebhookConfig{})
+	assert.NoError(err)
+	assert.Equal(vm.webhooks.list(username), reloaded.list(username))
+	assert.Equal(uint64(1), reloaded.nextIndex)
+
+	now := time.Unix(1600000000, 0)
+	vm.clock.Set(now)
+	requests, next, err := vm.webhooks.dueRequests(now)
+	assert.NoError(err)
+	assert.Zero(next)
+	assert.Len(requests, 1)
+
+	// The first attempt fails
+	statuses <- http.StatusInternalServerError
+	results := []error{vm.webhooks.post(requests[0])}
+	assert.Error(results[0])
+	assert.NoError(vm.webhooks.record(requests, results))
+
+	notification := <-received
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(notification.header.Get(WebhookTimestampHeader) + "."))
+	_, _ = mac.Write(notification.body)
+	assert.Equal(hex.EncodeToString(mac.Sum(nil)), notification.header.Get(WebhookSignatureHeader))
+
+	body := WebhookNotification{}
+	assert.NoError(json.Unmarshal(notification.body, &body))
+	addrStr, err := vm.FormatLocalAddress(addr)
+	assert.NoError(err)
+	assert.Equal(hookID, body.WebhookID)
+	assert.Equal(vm.ctx.ChainID, body.ChainID)
+	assert.Equal(tx.ID(), body.TxID)
+	assert.Equal([]string{addrStr}, body.Addresses)
+
+	// The notification is retried after a delay
+	requests, next, err = vm.webhooks.dueRequests(now)
+	assert.NoError(err)
+	assert.Empty(requests)
+	assert.Equal(now.Add(initialWebhookRetryDelay), next)
+
+	vm.clock.Set(next)
+	requests, _, err = vm.webhooks.dueRequests(next)
+	assert.NoError(err)
+	assert.Len(requests, 1)
+
+	statuses <- http.StatusOK
+	results = []error{vm.webhooks.post(requests[0])}
+	assert.NoError(results[0])
+	assert.NoError(vm.webhooks.record(requests, results))
+
+	retried := WebhookNotification{}
+	assert.NoError(json.Unmarshal((<-received).body, &retried))
+	assert.Equal(body, retried)
+
+	// The sent notification is removed from the outbox
+	requests, next, err = vm.webhooks.dueRequests(next)
+	assert.NoError(err)
+	assert.Empty(requests)
+	assert.Zero(next)
+}
+
+func TestWebhookPrivateIPRejectedOnDial(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("shouldn't have sent a notification to a private IP")
+	}))
+	defer server.Close()
+
+	// A webhook whose host resolves to a private IP after it's registered
+	// isn't notified
+	err := vm.webhooks.post(&webhookRequest{url: server.URL})
+	assert.ErrorIs(err, errPrivateWebhookIP)
+}
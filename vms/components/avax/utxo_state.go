@@ -208,3 +208,21 @@ func (s *utxoState) getIndexDB(addr []byte) linkeddb.LinkedDB {
 	s.indexCache.Put(addrStr, indexList)
 	return indexList
 }
+
+// ForEachUTXO calls [f] with every UTXO stored by a UTXOState on [db], in
+// order of UTXO ID. Stops at, and returns, the first error returned by [f].
+func ForEachUTXO(db database.Database, codec codec.Manager, f func(*UTXO) error) error {
+	iter := prefixdb.New(utxoPrefix, db).NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		utxo := &UTXO{}
+		if _, err := codec.Unmarshal(iter.Value(), utxo); err != nil {
+			return err
+		}
+		if err := f(utxo); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
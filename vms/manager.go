@@ -48,6 +48,10 @@ type Manager interface {
 
 	// Give an alias to a VM
 	Alias(ids.ID, string) error
+
+	// Give an alias to a VM on behalf of a component, which is reported if the
+	// alias collides with another alias
+	RegisterAlias(vmID ids.ID, alias, registrant string) error
 }
 
 // Implements Manager
@@ -98,7 +102,7 @@ func (m *manager) RegisterFactory(vmID ids.ID, factory Factory) error {
 	if _, exists := m.factories[vmID]; exists {
 		return fmt.Errorf("%q was already registered as a vm", vmID)
 	}
-	if err := m.Alias(vmID, vmID.String()); err != nil {
+	if err := m.RegisterAlias(vmID, vmID.String(), "VM manager"); err != nil {
 		return err
 	}
 
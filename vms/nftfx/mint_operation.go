@@ -3,6 +3,7 @@ package nftfx
 import (
 	"errors"
 
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
@@ -30,6 +31,13 @@ func (op *MintOperation) Outs() []verify.State {
 	return outs
 }
 
+// InitCtx ...
+func (op *MintOperation) InitCtx(ctx *snow.Context) {
+	for _, out := range op.Outputs {
+		out.InitCtx(ctx)
+	}
+}
+
 // Verify ...
 func (op *MintOperation) Verify() error {
 	switch {
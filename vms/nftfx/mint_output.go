@@ -1,6 +1,8 @@
 package nftfx
 
 import (
+	"encoding/json"
+
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
@@ -9,3 +11,13 @@ type MintOutput struct {
 	GroupID                  uint32 `serialize:"true" json:"groupID"`
 	secp256k1fx.OutputOwners `serialize:"true"`
 }
+
+// MarshalJSON marshals the group ID and the owners of the output
+func (out *MintOutput) MarshalJSON() ([]byte, error) {
+	fields, err := out.OutputOwners.Fields()
+	if err != nil {
+		return nil, err
+	}
+	fields["groupID"] = out.GroupID
+	return json.Marshal(fields)
+}
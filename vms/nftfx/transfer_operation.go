@@ -3,6 +3,7 @@ package nftfx
 import (
 	"errors"
 
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
@@ -20,6 +21,9 @@ func (op *TransferOperation) Outs() []verify.State {
 	return []verify.State{&op.Output}
 }
 
+// InitCtx ...
+func (op *TransferOperation) InitCtx(ctx *snow.Context) { op.Output.InitCtx(ctx) }
+
 // Verify ...
 func (op *TransferOperation) Verify() error {
 	switch {
@@ -1,6 +1,7 @@
 package nftfx
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -25,6 +26,17 @@ type TransferOutput struct {
 	secp256k1fx.OutputOwners `serialize:"true"`
 }
 
+// MarshalJSON marshals the group ID, the payload and the owners of the output
+func (out *TransferOutput) MarshalJSON() ([]byte, error) {
+	fields, err := out.OutputOwners.Fields()
+	if err != nil {
+		return nil, err
+	}
+	fields["groupID"] = out.GroupID
+	fields["payload"] = out.Payload
+	return json.Marshal(fields)
+}
+
 // Verify ...
 func (out *TransferOutput) Verify() error {
 	switch {
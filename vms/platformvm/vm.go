@@ -28,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/workers"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -73,6 +74,7 @@ var (
 
 	_ block.ChainVM        = &VM{}
 	_ validators.Connector = &VM{}
+	_ chains.SubnetTracker = &VM{}
 	_ secp256k1fx.VM       = &VM{}
 	_ Fx                   = &secp256k1fx.Fx{}
 )
@@ -194,6 +196,9 @@ func (vm *VM) Initialize(
 			err,
 		)
 	}
+	if vm.Chains != nil {
+		vm.Chains.SetSubnetTracker(vm)
+	}
 
 	vm.lastAcceptedID = is.GetLastAccepted()
 
@@ -229,6 +234,43 @@ func (vm *VM) initBlockchains() error {
 	return nil
 }
 
+// TrackSubnet implements the chains.SubnetTracker interface
+func (vm *VM) TrackSubnet(subnetID ids.ID) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	vm.WhitelistedSubnets.Add(subnetID)
+
+	currentValidators := vm.internalState.CurrentStakerChainState()
+	subnetValidators, err := currentValidators.ValidatorSet(subnetID)
+	if err != nil {
+		return err
+	}
+	if err := vm.Validators.Set(subnetID, subnetValidators); err != nil {
+		return err
+	}
+
+	chains, err := vm.internalState.GetChains(subnetID)
+	if err != nil {
+		return err
+	}
+	for _, chain := range chains {
+		if err := vm.createChain(chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UntrackSubnet implements the chains.SubnetTracker interface
+func (vm *VM) UntrackSubnet(subnetID ids.ID) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	vm.WhitelistedSubnets.Remove(subnetID)
+	return nil
+}
+
 // Create the blockchain described in [tx], but only if this node is a member of
 // the subnet that validates the chain
 func (vm *VM) createChain(tx *Tx) error {
@@ -529,6 +571,8 @@ func (vm *VM) Clock() *timer.Clock { return &vm.clock }
 
 func (vm *VM) Logger() logging.Logger { return vm.ctx.Log }
 
+func (vm *VM) CryptoExecutor() workers.Executor { return vm.ctx.CryptoExecutor }
+
 // Returns the percentage of the total stake on the Primary Network of nodes
 // connected to this node.
 func (vm *VM) getPercentConnected() (float64, error) {
@@ -100,5 +100,44 @@ func (fx *Fx) VerifyTransferOperation(tx secp256k1fx.Tx, op *BurnOperation, cred
 	return fx.VerifyCredentials(tx, &op.Input, &cred.Credential, &out.OutputOwners)
 }
 
+// VerifyUnsignedOperation performs the checks of VerifyOperation that don't
+// depend on the credential, so that an operation can be verified before it's
+// signed
+func (fx *Fx) VerifyUnsignedOperation(txIntf, opIntf interface{}, utxosIntf []interface{}) error {
+	_, ok := txIntf.(secp256k1fx.Tx)
+	switch {
+	case !ok:
+		return errWrongTxType
+	case len(utxosIntf) != 1:
+		return errWrongNumberOfUTXOs
+	}
+
+	switch op := opIntf.(type) {
+	case *MintOperation:
+		out, ok := utxosIntf[0].(*MintOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		if err := verify.All(op, out); err != nil {
+			return err
+		}
+		if !out.OutputOwners.Equals(&op.MintOutput.OutputOwners) {
+			return errWrongMintOutput
+		}
+		return fx.Fx.VerifyInput(&op.MintInput, &out.OutputOwners)
+	case *BurnOperation:
+		out, ok := utxosIntf[0].(*OwnedOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		if err := verify.All(op, out); err != nil {
+			return err
+		}
+		return fx.Fx.VerifyInput(&op.Input, &out.OutputOwners)
+	default:
+		return errWrongOperationType
+	}
+}
+
 // VerifyTransfer ...
 func (fx *Fx) VerifyTransfer(_, _, _, _ interface{}) error { return errCantTransfer }
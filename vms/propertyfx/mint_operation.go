@@ -3,6 +3,7 @@ package propertyfx
 import (
 	"errors"
 
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
@@ -24,6 +25,12 @@ func (op *MintOperation) Outs() []verify.State {
 	}
 }
 
+// InitCtx ...
+func (op *MintOperation) InitCtx(ctx *snow.Context) {
+	op.MintOutput.InitCtx(ctx)
+	op.OwnedOutput.InitCtx(ctx)
+}
+
 // Verify ...
 func (op *MintOperation) Verify() error {
 	switch {
@@ -0,0 +1,44 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vms
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+var errInvalidCPULimit = errors.New("cpu limit must be a non-negative number")
+
+// ResourceLimits bounds the resources that a VM running in its own process may
+// use. A zero value means that the resource isn't limited.
+type ResourceLimits struct {
+	// Number of CPUs worth of time the VM may use. For example, 1.5 allows
+	// the VM to use one and a half CPUs.
+	CPUs float64 `json:"cpus"`
+	// Max number of bytes of memory the VM may use
+	MemoryBytes uint64 `json:"memoryBytes"`
+}
+
+// IsZero returns true if no resource is limited
+func (l ResourceLimits) IsZero() bool { return l == ResourceLimits{} }
+
+// Verify that the limits are well formed
+func (l ResourceLimits) Verify() error {
+	if l.CPUs < 0 || math.IsNaN(l.CPUs) || math.IsInf(l.CPUs, 0) {
+		return errInvalidCPULimit
+	}
+	return nil
+}
+
+// A LimitedFactory creates new instances of a VM whose resource usage can be
+// limited
+type LimitedFactory interface {
+	Factory
+
+	// NewLimited returns a new instance of the VM that may use at most
+	// [limits] resources
+	NewLimited(*snow.Context, ResourceLimits) (interface{}, error)
+}
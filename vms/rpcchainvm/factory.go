@@ -5,29 +5,61 @@ package rpcchainvm
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os/exec"
 
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 )
 
-var errWrongVM = errors.New("wrong vm type")
+var (
+	errWrongVM = errors.New("wrong vm type")
+
+	_ vms.LimitedFactory = &Factory{}
+)
 
 // Factory ...
 type Factory struct {
 	Path string
+	// If non-empty, the cgroup v2 directory that the cgroups limiting the
+	// resources of plugins are created in
+	CgroupDir string
 }
 
 // New ...
 func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
-	// Ignore warning from launching an executable with a variable command
-	// because the command is a controlled and required input
+	return f.NewLimited(ctx, vms.ResourceLimits{})
+}
 
-	// #nosec G204
-	cmd := exec.Command(f.Path)
+// NewLimited runs the plugin in a sandbox that limits it to [limits]
+// resources from the moment it starts. If [limits] is zero, the plugin isn't
+// sandboxed.
+func (f *Factory) NewLimited(ctx *snow.Context, limits vms.ResourceLimits) (interface{}, error) {
+	var (
+		cmd *exec.Cmd
+		sb  sandbox
+	)
+	if limits.IsZero() {
+		// Ignore warning from launching an executable with a variable command
+		// because the command is a controlled and required input
+
+		// #nosec G204
+		cmd = exec.Command(f.Path)
+	} else {
+		name := "plugin"
+		if ctx != nil {
+			name = ctx.ChainID.String()
+		}
+		var err error
+		cmd, sb, err = newSandbox(f.CgroupDir, name, f.Path, limits)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't limit the resources of the plugin: %w", err)
+		}
+	}
 
 	config := &plugin.ClientConfig{
 		HandshakeConfig: Handshake,
@@ -67,23 +99,32 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 
 	rpcClient, err := client.Client()
 	if err != nil {
-		client.Kill()
+		killSandboxed(client, sb)
 		return nil, err
 	}
 
 	raw, err := rpcClient.Dispense("vm")
 	if err != nil {
-		client.Kill()
+		killSandboxed(client, sb)
 		return nil, err
 	}
 
 	vm, ok := raw.(*VMClient)
 	if !ok {
-		client.Kill()
+		killSandboxed(client, sb)
 		return nil, errWrongVM
 	}
 
 	vm.SetProcess(client)
+	vm.sandbox = sb
 	vm.ctx = ctx
 	return vm, nil
 }
+
+// killSandboxed kills the plugin [client] and releases its sandbox, if any
+func killSandboxed(client *plugin.Client, sb sandbox) {
+	client.Kill()
+	if sb != nil {
+		_ = sb.close()
+	}
+}
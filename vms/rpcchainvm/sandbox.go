@@ -0,0 +1,92 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	errCPULimitNeedsCgroup = errors.New("limiting the CPU of a VM plugin requires a cgroup directory")
+	errSandboxUnsupported  = errors.New("limiting the resources of VM plugins isn't supported on this platform")
+	errPluginOOMKilled     = errors.New("VM plugin ran out of memory")
+	errPluginNotStarted    = errors.New("VM plugin hasn't started")
+)
+
+// sandbox limits the resources of a VM plugin subprocess
+type sandbox interface {
+	// stats returns the resource usage of the plugin and how often it hit its
+	// limits
+	stats() (sandboxStats, error)
+
+	// close releases the sandbox. Should be called after the plugin exits.
+	close() error
+}
+
+// sandboxStats are the resource usage of a sandboxed plugin. Counts are since
+// the plugin started.
+type sandboxStats struct {
+	// Number of times a process in the sandbox was killed for running out of
+	// memory
+	OOMKills uint64 `json:"oomKills"`
+	// Number of scheduling periods the sandbox was throttled for exceeding
+	// its CPU limit
+	CPUThrottledPeriods uint64 `json:"cpuThrottledPeriods"`
+	// Number of bytes of memory in use
+	MemoryBytes uint64 `json:"memoryBytes"`
+}
+
+// registerSandboxMetrics reports the stats of [sb] through [registerer]
+func registerSandboxMetrics(sb sandbox, namespace string, registerer prometheus.Registerer) error {
+	// Report zeros if the stats can't be read
+	readStats := func() sandboxStats {
+		stats, _ := sb.stats()
+		return stats
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "plugin_oom_kills",
+			Help:      "Number of times a process of the VM plugin was killed for running out of memory",
+		}, func() float64 { return float64(readStats().OOMKills) })),
+		registerer.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "plugin_cpu_throttled_periods",
+			Help:      "Number of scheduling periods the VM plugin was throttled for exceeding its CPU limit",
+		}, func() float64 { return float64(readStats().CPUThrottledPeriods) })),
+		registerer.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "plugin_memory_bytes",
+			Help:      "Number of bytes of memory used by the VM plugin",
+		}, func() float64 { return float64(readStats().MemoryBytes) })),
+	)
+	return errs.Err
+}
+
+// parseFlatKeyed returns the value of [key] in [contents], which are formatted
+// as cgroup flat keyed files. That is, a "<key> <value>" pair per line.
+func parseFlatKeyed(contents []byte, key string) (uint64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) != 2 || string(fields[0]) != key {
+			continue
+		}
+		return strconv.ParseUint(string(fields[1]), 10, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("key %q not found", key)
+}
@@ -0,0 +1,163 @@
+// +build linux
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/vms"
+)
+
+const (
+	// Length of the period, in microseconds, that a cgroup's CPU quota is
+	// enforced over
+	cgroupCPUPeriod = 100000
+
+	// Shell that runs the plugin after limiting its resources. The limits are
+	// applied by the shell before it execs the plugin, so the plugin never
+	// runs unlimited and keeps the shell's pid.
+	sandboxShell = "/bin/sh"
+	// Moves the shell to the cgroup whose cgroup.procs file is $1, then runs
+	// the plugin at $0
+	cgroupScript = `echo $$ > "$1" && exec "$0"`
+	// Limits the data segment of the shell to $1 KiB, then runs the plugin at
+	// $0
+	rlimitScript = `ulimit -d "$1" && exec "$0"`
+)
+
+// newSandbox returns the command that runs the plugin at [path] limited to
+// [limits] resources. If [cgroupDir] is non-empty, it must be a cgroup v2
+// directory that this process may manage, and the plugin is run in a new
+// cgroup named [name] in it. Otherwise, the plugin's memory is limited with
+// RLIMIT_DATA and its CPU can't be limited.
+func newSandbox(cgroupDir, name, path string, limits vms.ResourceLimits) (*exec.Cmd, sandbox, error) {
+	if cgroupDir == "" {
+		return newRlimitSandbox(path, limits)
+	}
+	return newCgroupSandbox(filepath.Join(cgroupDir, name), path, limits)
+}
+
+// cgroupSandbox limits the resources of a plugin with a cgroup
+type cgroupSandbox struct {
+	dir string
+}
+
+func newCgroupSandbox(dir, path string, limits vms.ResourceLimits) (*exec.Cmd, *cgroupSandbox, error) {
+	// Allow the cgroups in the parent directory to limit the CPU and memory of
+	// their processes
+	subtreeControl := filepath.Join(filepath.Dir(dir), "cgroup.subtree_control")
+	if err := ioutil.WriteFile(subtreeControl, []byte("+cpu +memory"), perms.ReadWrite); err != nil {
+		return nil, nil, fmt.Errorf("couldn't enable cgroup controllers: %w", err)
+	}
+	if err := os.Mkdir(dir, perms.ReadWriteExecute); err != nil && !os.IsExist(err) {
+		return nil, nil, fmt.Errorf("couldn't create cgroup: %w", err)
+	}
+
+	s := &cgroupSandbox{dir: dir}
+	cpuMax := "max"
+	if limits.CPUs > 0 {
+		cpuMax = strconv.FormatUint(uint64(limits.CPUs*cgroupCPUPeriod), 10)
+	}
+	memoryMax := "max"
+	if limits.MemoryBytes > 0 {
+		memoryMax = strconv.FormatUint(limits.MemoryBytes, 10)
+	}
+	if err := s.write("cpu.max", fmt.Sprintf("%s %d", cpuMax, cgroupCPUPeriod)); err != nil {
+		return nil, nil, err
+	}
+	if err := s.write("memory.max", memoryMax); err != nil {
+		return nil, nil, err
+	}
+
+	// #nosec G204
+	cmd := exec.Command(sandboxShell, "-c", cgroupScript, path, filepath.Join(dir, "cgroup.procs"))
+	return cmd, s, nil
+}
+
+func (s *cgroupSandbox) write(file, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(s.dir, file), []byte(value), perms.ReadWrite); err != nil {
+		return fmt.Errorf("couldn't write cgroup file %s: %w", file, err)
+	}
+	return nil
+}
+
+func (s *cgroupSandbox) read(file, key string) (uint64, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(s.dir, file))
+	if err != nil {
+		return 0, err
+	}
+	if key == "" {
+		return strconv.ParseUint(string(bytes.TrimSpace(contents)), 10, 64)
+	}
+	return parseFlatKeyed(contents, key)
+}
+
+func (s *cgroupSandbox) stats() (sandboxStats, error) {
+	oomKills, err := s.read("memory.events", "oom_kill")
+	if err != nil {
+		return sandboxStats{}, err
+	}
+	throttled, err := s.read("cpu.stat", "nr_throttled")
+	if err != nil {
+		return sandboxStats{}, err
+	}
+	memory, err := s.read("memory.current", "")
+	return sandboxStats{
+		OOMKills:            oomKills,
+		CPUThrottledPeriods: throttled,
+		MemoryBytes:         memory,
+	}, err
+}
+
+// close removes the cgroup, which fails if the plugin is still running
+func (s *cgroupSandbox) close() error { return os.Remove(s.dir) }
+
+// rlimitSandbox limits the memory of a plugin with RLIMIT_DATA. Unlike
+// RLIMIT_AS, it doesn't count the address space that the Go runtime reserves
+// without using, so it doesn't break plugins written in Go.
+type rlimitSandbox struct {
+	cmd *exec.Cmd
+}
+
+func newRlimitSandbox(path string, limits vms.ResourceLimits) (*exec.Cmd, *rlimitSandbox, error) {
+	if limits.CPUs > 0 {
+		return nil, nil, errCPULimitNeedsCgroup
+	}
+	// ulimit takes the limit in KiB. Round up so that a small limit isn't zero.
+	limitKiB := (limits.MemoryBytes + 1023) / 1024
+
+	// #nosec G204
+	cmd := exec.Command(sandboxShell, "-c", rlimitScript, path, strconv.FormatUint(limitKiB, 10))
+	return cmd, &rlimitSandbox{cmd: cmd}, nil
+}
+
+// stats only reports the memory usage of the plugin, since its limits are
+// enforced by failing allocations rather than by counted events
+func (s *rlimitSandbox) stats() (sandboxStats, error) {
+	if s.cmd.Process == nil {
+		return sandboxStats{}, errPluginNotStarted
+	}
+	statm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/statm", s.cmd.Process.Pid))
+	if err != nil {
+		return sandboxStats{}, err
+	}
+	// The second field is the number of resident pages
+	var size, resident uint64
+	if _, err := fmt.Sscan(string(statm), &size, &resident); err != nil {
+		return sandboxStats{}, err
+	}
+	return sandboxStats{MemoryBytes: resident * uint64(os.Getpagesize())}, nil
+}
+
+func (s *rlimitSandbox) close() error { return nil }
+
@@ -0,0 +1,18 @@
+// +build !linux
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"os/exec"
+
+	"github.com/ava-labs/avalanchego/vms"
+)
+
+// newSandbox fails, as the resources of processes can only be limited on
+// linux
+func newSandbox(string, string, string, vms.ResourceLimits) (*exec.Cmd, sandbox, error) {
+	return nil, nil, errSandboxUnsupported
+}
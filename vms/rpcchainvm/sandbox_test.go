@@ -0,0 +1,28 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlatKeyed(t *testing.T) {
+	memoryEvents := []byte("low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\n")
+
+	oomKills, err := parseFlatKeyed(memoryEvents, "oom_kill")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), oomKills)
+
+	oom, err := parseFlatKeyed(memoryEvents, "oom")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), oom)
+
+	_, err = parseFlatKeyed(memoryEvents, "oom_group_kill")
+	assert.Error(t, err)
+
+	_, err = parseFlatKeyed([]byte("oom_kill many\n"), "oom_kill")
+	assert.Error(t, err)
+}
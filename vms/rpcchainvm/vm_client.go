@@ -59,6 +59,8 @@ type VMClient struct {
 	client vmproto.VMClient
 	broker *plugin.GRPCBroker
 	proc   *plugin.Client
+	// Limits the resources of [proc]. May be nil.
+	sandbox sandbox
 
 	db           *rpcdb.DatabaseServer
 	messenger    *messenger.Server
@@ -213,6 +215,9 @@ func (vm *VMClient) Initialize(
 	}
 	vm.State = chainState
 
+	if vm.sandbox != nil {
+		return registerSandboxMetrics(vm.sandbox, fmt.Sprintf("%s_rpcchainvm", ctx.Namespace), ctx.Metrics)
+	}
 	return nil
 }
 
@@ -288,6 +293,9 @@ func (vm *VMClient) Shutdown() error {
 	}
 
 	vm.proc.Kill()
+	if vm.sandbox != nil {
+		errs.Add(vm.sandbox.close())
+	}
 	return errs.Err
 }
 
@@ -419,10 +427,29 @@ func (vm *VMClient) SetPreference(id ids.ID) error {
 }
 
 func (vm *VMClient) HealthCheck() (interface{}, error) {
-	return vm.client.Health(
+	health, err := vm.client.Health(
 		context.Background(),
 		&vmproto.HealthRequest{},
 	)
+	if vm.sandbox == nil {
+		return health, err
+	}
+
+	stats, statsErr := vm.sandbox.stats()
+	details := map[string]interface{}{
+		"vm":      health,
+		"sandbox": stats,
+	}
+	switch {
+	case err != nil:
+		return details, err
+	case statsErr != nil:
+		return details, fmt.Errorf("couldn't read the plugin's resource usage: %w", statsErr)
+	case stats.OOMKills > 0:
+		return details, errPluginOOMKilled
+	default:
+		return details, nil
+	}
 }
 
 func (vm *VMClient) Version() (string, error) {
@@ -25,6 +25,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/workers"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/galiaslookup"
@@ -189,6 +190,8 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		SNLookup:             snLookupClient,
 		EpochFirstTransition: epochFirstTransition,
 		EpochDuration:        time.Duration(req.EpochDuration),
+		// The node's crypto pool can't be shared with the plugin process
+		CryptoExecutor: workers.Inline{},
 	}
 
 	if err := vm.vm.Initialize(vm.ctx, dbManager, req.GenesisBytes, req.UpgradeBytes, req.ConfigBytes, toEngine, nil); err != nil {
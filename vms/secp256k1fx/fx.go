@@ -140,6 +140,33 @@ func (fx *Fx) verifyOperation(tx Tx, op *MintOperation, cred *Credential, utxo *
 	return fx.VerifyCredentials(tx, &op.MintInput, cred, &utxo.OutputOwners)
 }
 
+// VerifyUnsignedOperation performs the checks of VerifyOperation that don't
+// depend on the credential, so that an operation can be verified before it's
+// signed
+func (fx *Fx) VerifyUnsignedOperation(txIntf, opIntf interface{}, utxosIntf []interface{}) error {
+	if _, ok := txIntf.(Tx); !ok {
+		return errWrongTxType
+	}
+	op, ok := opIntf.(*MintOperation)
+	if !ok {
+		return errWrongOpType
+	}
+	if len(utxosIntf) != 1 {
+		return errWrongNumberOfUTXOs
+	}
+	out, ok := utxosIntf[0].(*MintOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	if err := verify.All(op, out); err != nil {
+		return err
+	}
+	if !out.Equals(&op.MintOutput.OutputOwners) {
+		return errWrongMintCreated
+	}
+	return fx.VerifyInput(&op.MintInput, &out.OutputOwners)
+}
+
 // VerifyTransfer ...
 func (fx *Fx) VerifyTransfer(txIntf, inIntf, credIntf, utxoIntf interface{}) error {
 	tx, ok := txIntf.(Tx)
@@ -189,26 +216,66 @@ func (fx *Fx) VerifyCredentials(tx Tx, in *Input, cred *Credential, out *OutputO
 		return nil
 	}
 
-	txHash := hashing.ComputeHash256(tx.UnsignedBytes())
-	for i, index := range in.SigIndices {
+	for _, index := range in.SigIndices {
 		// Make sure the input references an address that exists
 		if index >= uint32(len(out.Addrs)) {
 			return errInputOutputIndexOutOfBounds
 		}
+	}
+
+	// The hashing and the public key recoveries are run on the executor
+	// provided by the VM, which may run the recoveries concurrently
+	executor := fx.VM.CryptoExecutor()
+	var txHash []byte
+	executor.Run(func() {
+		txHash = hashing.ComputeHash256(tx.UnsignedBytes())
+	})
+
+	pks := make([]crypto.PublicKey, numSigs)
+	errs := make([]error, numSigs)
+	recoveries := make([]func(), numSigs)
+	for i := range recoveries {
+		i := i
+		recoveries[i] = func() {
+			sig := cred.Sigs[i]
+			pks[i], errs[i] = fx.SECPFactory.RecoverHashPublicKey(txHash, sig[:])
+		}
+	}
+	executor.Run(recoveries...)
+
+	for i, index := range in.SigIndices {
+		if errs[i] != nil {
+			return errs[i]
+		}
 		// Make sure each signature in the signature list is from an owner of
 		// the output being consumed
-		sig := cred.Sigs[i]
-		pk, err := fx.SECPFactory.RecoverHashPublicKey(txHash, sig[:])
-		if err != nil {
-			return err
-		}
-		if expectedAddress := out.Addrs[index]; expectedAddress != pk.Address() {
+		if expectedAddress := out.Addrs[index]; expectedAddress != pks[i].Address() {
 			return fmt.Errorf("expected signature from %s but got from %s",
 				expectedAddress,
-				pk.Address())
+				pks[i].Address())
 		}
 	}
+	return nil
+}
 
+// VerifyInput performs the checks of VerifyCredentials that don't depend on
+// the credential. That is, it verifies that [in] may spend [out] at the
+// current time and names enough of its owners.
+func (fx *Fx) VerifyInput(in *Input, out *OutputOwners) error {
+	numSigs := len(in.SigIndices)
+	switch {
+	case out.Locktime > fx.VM.Clock().Unix():
+		return errTimelocked
+	case out.Threshold < uint32(numSigs):
+		return errTooManySigners
+	case out.Threshold > uint32(numSigs):
+		return errTooFewSigners
+	}
+	for _, index := range in.SigIndices {
+		if index >= uint32(len(out.Addrs)) {
+			return errInputOutputIndexOutOfBounds
+		}
+	}
 	return nil
 }
 
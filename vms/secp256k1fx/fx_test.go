@@ -638,6 +638,80 @@ func TestFxVerifyOperation(t *testing.T) {
 	}
 }
 
+func TestFxVerifyUnsignedOperation(t *testing.T) {
+	vm := TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.CLK.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &TestTx{Bytes: txBytes}
+	utxo := &MintOutput{
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				addr,
+			},
+		},
+	}
+	newOp := func() *MintOperation {
+		return &MintOperation{
+			MintInput: Input{
+				SigIndices: []uint32{0},
+			},
+			MintOutput: MintOutput{
+				OutputOwners: OutputOwners{
+					Threshold: 1,
+					Addrs: []ids.ShortID{
+						addr,
+					},
+				},
+			},
+			TransferOutput: TransferOutput{
+				Amt: 1,
+				OutputOwners: OutputOwners{
+					Threshold: 1,
+					Addrs: []ids.ShortID{
+						addr,
+					},
+				},
+			},
+		}
+	}
+	utxos := []interface{}{utxo}
+
+	if err := fx.VerifyUnsignedOperation(tx, newOp(), utxos); err != nil {
+		t.Fatal(err)
+	}
+
+	wrongMint := newOp()
+	wrongMint.MintOutput.Threshold = 0
+	wrongMint.MintOutput.Addrs = nil
+	if err := fx.VerifyUnsignedOperation(tx, wrongMint, utxos); err != errWrongMintCreated {
+		t.Fatalf("should have failed with %s, failed with %v", errWrongMintCreated, err)
+	}
+
+	tooFewSigners := newOp()
+	tooFewSigners.MintInput.SigIndices = nil
+	if err := fx.VerifyUnsignedOperation(tx, tooFewSigners, utxos); err != errTooFewSigners {
+		t.Fatalf("should have failed with %s, failed with %v", errTooFewSigners, err)
+	}
+
+	outOfBounds := newOp()
+	outOfBounds.MintInput.SigIndices = []uint32{1}
+	if err := fx.VerifyUnsignedOperation(tx, outOfBounds, utxos); err != errInputOutputIndexOutOfBounds {
+		t.Fatalf("should have failed with %s, failed with %v", errInputOutputIndexOutOfBounds, err)
+	}
+
+	if err := fx.VerifyUnsignedOperation(tx, newOp(), nil); err != errWrongNumberOfUTXOs {
+		t.Fatalf("should have failed with %s, failed with %v", errWrongNumberOfUTXOs, err)
+	}
+}
+
 func TestFxVerifyOperationUnknownTx(t *testing.T) {
 	vm := TestVM{
 		Codec: linearcodec.NewDefault(),
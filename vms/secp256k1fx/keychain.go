@@ -93,18 +93,63 @@ func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable
 
 // Match attempts to match a list of addresses up to the provided threshold
 func (kc *Keychain) Match(owners *OutputOwners, time uint64) ([]uint32, []*crypto.PrivateKeySECP256K1R, bool) {
-	if time < owners.Locktime {
+	sigs, able := MatchAddrs(owners, kc.Addrs, time)
+	if !able {
 		return nil, nil, false
 	}
+	keys := make([]*crypto.PrivateKeySECP256K1R, len(sigs))
+	for i, sig := range sigs {
+		keys[i], _ = kc.Get(owners.Addrs[sig])
+	}
+	return sigs, keys, true
+}
+
+// MatchAddrs attempts to match a list of addresses up to the provided threshold
+// with [addrs], without needing their keys. Returns the indices of the
+// addresses that must sign.
+func MatchAddrs(owners *OutputOwners, addrs ids.ShortSet, time uint64) ([]uint32, bool) {
+	if time < owners.Locktime {
+		return nil, false
+	}
 	sigs := make([]uint32, 0, owners.Threshold)
-	keys := make([]*crypto.PrivateKeySECP256K1R, 0, owners.Threshold)
-	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(keys)) < owners.Threshold; i++ {
-		if key, exists := kc.Get(owners.Addrs[i]); exists {
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
+		if addrs.Contains(owners.Addrs[i]) {
+			sigs = append(sigs, i)
+		}
+	}
+	return sigs, uint32(len(sigs)) == owners.Threshold
+}
+
+// MatchAddrsPartially is MatchAddrs for when [addrs] hold only some of the
+// addresses needed to reach the threshold. Addresses of [addrs] are matched
+// first, and the remaining signers are the first other addresses of [owners].
+// Returns false if none of [addrs] must sign.
+func MatchAddrsPartially(owners *OutputOwners, addrs ids.ShortSet, time uint64) ([]uint32, bool) {
+	sigs, able := MatchAddrs(owners, addrs, time)
+	switch {
+	case able:
+		return sigs, true
+	case len(sigs) == 0 || uint32(len(owners.Addrs)) < owners.Threshold:
+		return nil, false
+	}
+
+	matched := make([]bool, len(owners.Addrs))
+	for _, sig := range sigs {
+		matched[sig] = true
+	}
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
+		if !matched[i] {
+			matched[i] = true
 			sigs = append(sigs, i)
-			keys = append(keys, key)
 		}
 	}
-	return sigs, keys, uint32(len(keys)) == owners.Threshold
+	sigs = sigs[:0]
+	for i, isSigner := range matched {
+		if isSigner {
+			sigs = append(sigs, uint32(i))
+		}
+	}
+	return sigs, true
 }
 
 // PrefixedString returns the key chain as a string representation with [prefix]
@@ -162,6 +162,63 @@ func TestKeychainMatch(t *testing.T) {
 	}
 }
 
+func TestMatchAddrs(t *testing.T) {
+	addr0 := ids.GenerateTestShortID()
+	addr1 := ids.GenerateTestShortID()
+	addr2 := ids.GenerateTestShortID()
+	owners := OutputOwners{
+		Locktime:  1,
+		Threshold: 2,
+		Addrs:     []ids.ShortID{addr0, addr1, addr2},
+	}
+
+	addrs := ids.ShortSet{}
+	addrs.Add(addr0, addr2)
+	if _, ok := MatchAddrs(&owners, addrs, 0); ok {
+		t.Fatalf("Shouldn't have been able to match before the locktime")
+	}
+	if indices, ok := MatchAddrs(&owners, addrs, 1); !ok {
+		t.Fatalf("Should have been able to match with the owners")
+	} else if len(indices) != 2 || indices[0] != 0 || indices[1] != 2 {
+		t.Fatalf("Should have returned indices [0 2] but returned %v", indices)
+	}
+
+	addrs.Remove(addr2)
+	if _, ok := MatchAddrs(&owners, addrs, 1); ok {
+		t.Fatalf("Shouldn't have been able to match with one address")
+	}
+}
+
+func TestMatchAddrsPartially(t *testing.T) {
+	addr0 := ids.GenerateTestShortID()
+	addr1 := ids.GenerateTestShortID()
+	addr2 := ids.GenerateTestShortID()
+	owners := OutputOwners{
+		Threshold: 2,
+		Addrs:     []ids.ShortID{addr0, addr1, addr2},
+	}
+
+	addrs := ids.ShortSet{}
+	if _, ok := MatchAddrsPartially(&owners, addrs, 0); ok {
+		t.Fatalf("Shouldn't have been able to match without any address")
+	}
+
+	// The other signer is the first address that isn't held
+	addrs.Add(addr2)
+	if indices, ok := MatchAddrsPartially(&owners, addrs, 0); !ok {
+		t.Fatalf("Should have been able to partially match with the owners")
+	} else if len(indices) != 2 || indices[0] != 0 || indices[1] != 2 {
+		t.Fatalf("Should have returned indices [0 2] but returned %v", indices)
+	}
+
+	addrs.Add(addr1)
+	if indices, ok := MatchAddrsPartially(&owners, addrs, 0); !ok {
+		t.Fatalf("Should have been able to match with the owners")
+	} else if len(indices) != 2 || indices[0] != 1 || indices[1] != 2 {
+		t.Fatalf("Should have returned indices [1 2] but returned %v", indices)
+	}
+}
+
 func TestKeychainSpendMint(t *testing.T) {
 	kc := NewKeychain()
 
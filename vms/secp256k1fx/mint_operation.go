@@ -6,6 +6,7 @@ package secp256k1fx
 import (
 	"errors"
 
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 )
 
@@ -23,6 +24,12 @@ func (op *MintOperation) Outs() []verify.State {
 	return []verify.State{&op.MintOutput, &op.TransferOutput}
 }
 
+// InitCtx ...
+func (op *MintOperation) InitCtx(ctx *snow.Context) {
+	op.MintOutput.InitCtx(ctx)
+	op.TransferOutput.InitCtx(ctx)
+}
+
 // Verify ...
 func (op *MintOperation) Verify() error {
 	switch {
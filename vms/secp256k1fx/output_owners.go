@@ -4,9 +4,13 @@
 package secp256k1fx
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 )
 
@@ -23,6 +27,51 @@ type OutputOwners struct {
 	Locktime  uint64        `serialize:"true" json:"locktime"`
 	Threshold uint32        `serialize:"true" json:"threshold"`
 	Addrs     []ids.ShortID `serialize:"true" json:"addresses"`
+
+	// If set, the addresses are formatted for the chain of [ctx] when
+	// marshalled to JSON. Isn't serialized.
+	ctx *snow.Context
+}
+
+// InitCtx sets the context used to format the addresses
+func (out *OutputOwners) InitCtx(ctx *snow.Context) { out.ctx = ctx }
+
+// Fields returns the fields of the owners marshalled to JSON. If the owners
+// were initialized with a context, the addresses are formatted for its chain.
+func (out *OutputOwners) Fields() (map[string]interface{}, error) {
+	fields := map[string]interface{}{
+		"locktime":  out.Locktime,
+		"threshold": out.Threshold,
+	}
+	if out.ctx == nil {
+		fields["addresses"] = out.Addrs
+		return fields, nil
+	}
+
+	chainIDAlias, err := out.ctx.BCLookup.PrimaryAlias(out.ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	hrp := constants.GetHRP(out.ctx.NetworkID)
+	addrs := make([]string, len(out.Addrs))
+	for i, addr := range out.Addrs {
+		addrs[i], err = formatting.FormatAddress(chainIDAlias, hrp, addr.Bytes())
+		if err != nil {
+			return nil, err
+		}
+	}
+	fields["addresses"] = addrs
+	return fields, nil
+}
+
+// MarshalJSON marshals the owners, with the addresses formatted if the owners
+// were initialized with a context
+func (out *OutputOwners) MarshalJSON() ([]byte, error) {
+	fields, err := out.Fields()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
 }
 
 // Addresses returns the addresses that manage this output
@@ -4,6 +4,7 @@
 package secp256k1fx
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -24,6 +25,16 @@ type TransferOutput struct {
 // Amount returns the quantity of the asset this output consumes
 func (out *TransferOutput) Amount() uint64 { return out.Amt }
 
+// MarshalJSON marshals the amount and the owners of the output
+func (out *TransferOutput) MarshalJSON() ([]byte, error) {
+	fields, err := out.OutputOwners.Fields()
+	if err != nil {
+		return nil, err
+	}
+	fields["amount"] = out.Amt
+	return json.Marshal(fields)
+}
+
 // Verify ...
 func (out *TransferOutput) Verify() error {
 	switch {
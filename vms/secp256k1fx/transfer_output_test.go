@@ -5,11 +5,15 @@ package secp256k1fx
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 )
 
@@ -246,3 +250,46 @@ func TestTransferOutputState(t *testing.T) {
 		t.Fatalf("should be marked as state")
 	}
 }
+
+func TestOutputMarshalJSON(t *testing.T) {
+	addr := ids.ShortID{1}
+	out := &TransferOutput{
+		Amt: 1,
+		OutputOwners: OutputOwners{
+			Locktime:  2,
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		},
+	}
+
+	// Without a context, addresses are marshalled as IDs
+	jsonBytes, err := json.Marshal(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"addresses":["` + addr.String() + `"],"amount":1,"locktime":2,"threshold":1}`
+	if string(jsonBytes) != expected {
+		t.Fatalf("expected %s but got %s", expected, jsonBytes)
+	}
+
+	// With a context, addresses are formatted for its chain
+	ctx := snow.DefaultContextTest()
+	ctx.NetworkID = constants.MainnetID
+	ctx.ChainID = ids.ID{2}
+	if err := ctx.BCLookup.(*ids.Aliaser).Alias(ctx.ChainID, "X"); err != nil {
+		t.Fatal(err)
+	}
+	out.InitCtx(ctx)
+	jsonBytes, err = json.Marshal(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrStr, err := formatting.FormatAddress("X", constants.MainnetHRP, addr.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"addresses":["` + addrStr + `"],"amount":1,"locktime":2,"threshold":1}`
+	if string(jsonBytes) != expected {
+		t.Fatalf("expected %s but got %s", expected, jsonBytes)
+	}
+}
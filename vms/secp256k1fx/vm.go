@@ -7,6 +7,7 @@ import (
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/workers"
 )
 
 // VM that this Fx must be run by
@@ -14,6 +15,8 @@ type VM interface {
 	CodecRegistry() codec.Registry
 	Clock() *timer.Clock
 	Logger() logging.Logger
+	// CryptoExecutor returns the executor cryptographic operations are run on
+	CryptoExecutor() workers.Executor
 }
 
 var _ VM = &TestVM{}
@@ -25,6 +28,7 @@ type TestVM struct {
 	Log   logging.Logger
 }
 
-func (vm *TestVM) Clock() *timer.Clock           { return &vm.CLK }
-func (vm *TestVM) CodecRegistry() codec.Registry { return vm.Codec }
-func (vm *TestVM) Logger() logging.Logger        { return vm.Log }
+func (vm *TestVM) Clock() *timer.Clock              { return &vm.CLK }
+func (vm *TestVM) CodecRegistry() codec.Registry    { return vm.Codec }
+func (vm *TestVM) Logger() logging.Logger           { return vm.Log }
+func (vm *TestVM) CryptoExecutor() workers.Executor { return workers.Inline{} }